@@ -7,7 +7,10 @@ import (
 	"os"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
 	"github.com/makkenzo/license-service-api/internal/domain/apikey"
+	"github.com/makkenzo/license-service-api/internal/idgen"
 	apikeyRepoImpl "github.com/makkenzo/license-service-api/internal/storage/postgres"
 	"github.com/makkenzo/license-service-api/internal/util"
 	"go.uber.org/zap"
@@ -19,7 +22,17 @@ func main() {
 		log.Fatal("DATABASE_URL environment variable is required")
 	}
 
-	fullKey, prefix, keyHash, err := util.GenerateAPIKey()
+	ids, err := idgen.NewGenerator(os.Getenv("DATABASE_IDSTRATEGY"))
+	if err != nil {
+		log.Fatalf("Invalid ID strategy: %v", err)
+	}
+
+	crypto, err := cryptoprovider.New(&config.CryptoConfig{Provider: os.Getenv("CRYPTO_PROVIDER")})
+	if err != nil {
+		log.Fatalf("Invalid crypto provider: %v", err)
+	}
+
+	fullKey, prefix, keyHash, err := util.GenerateAPIKey(crypto)
 	if err != nil {
 		log.Fatalf("Failed to generate API key: %v", err)
 	}
@@ -35,12 +48,13 @@ func main() {
 	}
 	defer pool.Close()
 
-	repo := apikeyRepoImpl.NewAPIKeyRepository(pool, logger)
+	repo := apikeyRepoImpl.NewAPIKeyRepository(pool, ids, logger)
 
 	newKeyRecord := &apikey.APIKey{
 		KeyHash:     keyHash,
 		Prefix:      prefix,
 		Description: "Default Agent Key for Product AwesomeApp",
+		OrgID:       os.Getenv("APIKEY_ORGID"),
 
 		IsEnabled: true,
 	}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"github.com/makkenzo/license-service-api/internal/signing"
+	"github.com/makkenzo/license-service-api/internal/storage/postgres"
+	"go.uber.org/zap"
+)
+
+func main() {
+	configPath := flag.String("config", "./configs/config.dev.yaml", "Path to configuration file")
+	out := flag.String("out", "", "Write the response to this file instead of stdout")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: offlineactivate [-config path] [-out file] <challenge code>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	code := strings.TrimSpace(flag.Arg(0))
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.Region.Replica() {
+		log.Fatal("Offline activations are answered against the primary region, not a replica")
+	}
+	provider, err := cryptoprovider.New(&cfg.Crypto)
+	if err != nil {
+		log.Fatalf("Invalid crypto provider: %v", err)
+	}
+	ids, err := idgen.NewGenerator(cfg.Database.IDStrategy)
+	if err != nil {
+		log.Fatalf("Invalid ID strategy: %v", err)
+	}
+
+	logger, _ := zap.NewDevelopment()
+	ctx := context.Background()
+
+	pool, err := postgres.NewPgxPool(ctx, &cfg.Database, logger)
+	if err != nil {
+		log.Fatalf("Unable to connect to database: %v\n", err)
+	}
+	defer pool.Close()
+
+	var licenses license.Repository = postgres.NewLicenseRepository(pool, ids, logger)
+	if len(cfg.Database.ShardURLs) > 0 {
+		pools, err := postgres.NewShardPools(ctx, &cfg.Database, logger)
+		if err != nil {
+			log.Fatalf("Unable to connect to shards: %v\n", err)
+		}
+		shards := make([]*postgres.LicenseRepository, len(pools))
+		for i, pool := range pools {
+			defer pool.Close()
+			shards[i] = postgres.NewLicenseRepository(pool, ids, logger)
+		}
+		licenses = postgres.NewShardedLicenseRepository(shards, logger)
+	}
+
+	keyring := signing.NewKeyring(postgres.NewSigningKeyRepository(pool, logger), provider, &cfg.Signing, logger)
+	if err := keyring.Load(ctx); err != nil {
+		log.Fatalf("Failed to load signing keys: %v", err)
+	}
+
+	activations := service.NewActivationService(postgres.NewActivationRepository(pool, ids, logger), licenses, &cfg.Heartbeat, logger)
+	offline := service.NewOfflineActivationService(
+		postgres.NewOfflineActivationRepository(pool, ids, logger),
+		licenses,
+		postgres.NewEntitlementRepository(pool, ids, logger),
+		activations,
+		keyring,
+		provider,
+		&cfg.OfflineActivation,
+		logger,
+	)
+
+	resp, err := offline.Respond(ctx, code)
+	if err != nil {
+		log.Fatalf("Offline activation failed: %v", err)
+	}
+
+	if *out != "" {
+		if err := os.WriteFile(*out, []byte(resp.Response+"\n"), 0o644); err != nil {
+			log.Fatalf("Failed to write response: %v", err)
+		}
+	} else {
+		fmt.Println(resp.Response)
+	}
+	fmt.Fprintf(os.Stderr, "License: %s\n", resp.LicenseID)
+	fmt.Fprintf(os.Stderr, "Device: %s\n", resp.DeviceID)
+	fmt.Fprintf(os.Stderr, "Signing key: %s\n", resp.KeyID)
+	if !resp.Created {
+		fmt.Fprintln(os.Stderr, "The device was already activated; no additional seat was taken.")
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/storage/postgres"
+	"go.uber.org/zap"
+)
+
+func main() {
+	configPath := flag.String("config", "./configs/config.dev.yaml", "Path to configuration file")
+	dryRun := flag.Bool("dry-run", true, "Only report how many licenses would move")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	if len(cfg.Database.ShardURLs) == 0 {
+		log.Fatal("DATABASE_SHARD_URLS must list the shards to rebalance")
+	}
+
+	logger, _ := zap.NewDevelopment()
+	ctx := context.Background()
+
+	pools, err := postgres.NewShardPools(ctx, &cfg.Database, logger)
+	if err != nil {
+		log.Fatalf("Unable to connect to shards: %v\n", err)
+	}
+	ids, err := idgen.NewGenerator(cfg.Database.IDStrategy)
+	if err != nil {
+		log.Fatalf("Invalid ID strategy: %v", err)
+	}
+	shards := make([]*postgres.LicenseRepository, len(pools))
+	for i, pool := range pools {
+		defer pool.Close()
+		shards[i] = postgres.NewLicenseRepository(pool, ids, logger)
+	}
+
+	repo := postgres.NewShardedLicenseRepository(shards, logger)
+	report, err := repo.Rebalance(ctx, *dryRun)
+	if err != nil {
+		log.Fatalf("Rebalance failed: %v", err)
+	}
+
+	fmt.Printf("Shards: %d\n", len(shards))
+	fmt.Printf("Scanned licenses: %d\n", report.Scanned)
+	fmt.Printf("Misplaced licenses: %d\n", report.Misplaced)
+	if *dryRun {
+		fmt.Println("\nDry run only, nothing was moved. Re-run with -dry-run=false to apply.")
+		return
+	}
+	fmt.Printf("Moved licenses: %d\n", report.Moved)
+}
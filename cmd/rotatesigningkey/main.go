@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+	"github.com/makkenzo/license-service-api/internal/signing"
+	"github.com/makkenzo/license-service-api/internal/storage/postgres"
+	"go.uber.org/zap"
+)
+
+func main() {
+	configPath := flag.String("config", "./configs/config.dev.yaml", "Path to configuration file")
+	keyRef := flag.String("key-ref", "", "Reference of the new signing key for the crypto provider, e.g. a PEM file path")
+	generate := flag.Bool("generate", false, "Generate a new Ed25519 key and write it to -key-ref first")
+	flag.Parse()
+
+	if *keyRef == "" {
+		log.Fatal("-key-ref is required")
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	provider, err := cryptoprovider.New(&cfg.Crypto)
+	if err != nil {
+		log.Fatalf("Invalid crypto provider: %v", err)
+	}
+
+	if *generate {
+		if err := writeEd25519Key(provider, *keyRef); err != nil {
+			log.Fatalf("Failed to generate signing key: %v", err)
+		}
+		fmt.Printf("Generated Ed25519 signing key at %s\n", *keyRef)
+	}
+
+	logger, _ := zap.NewDevelopment()
+	ctx := context.Background()
+
+	pool, err := postgres.NewPgxPool(ctx, &cfg.Database, logger)
+	if err != nil {
+		log.Fatalf("Unable to connect to database: %v\n", err)
+	}
+	defer pool.Close()
+
+	keyring := signing.NewKeyring(postgres.NewSigningKeyRepository(pool, logger), provider, &cfg.Signing, logger)
+	key, err := keyring.Rotate(ctx, *keyRef)
+	if err != nil {
+		log.Fatalf("Rotation failed: %v", err)
+	}
+
+	fmt.Printf("Active signing key: %s (%s)\n", key.KID, key.Algorithm)
+	fmt.Printf("Running servers switch to it within %s; previous keys stay published in the JWKS.\n", cfg.Signing.RefreshInterval)
+}
+
+// writeEd25519Key refuses to overwrite an existing file so a typo cannot
+// destroy the key a deployment still signs with.
+func writeEd25519Key(provider cryptoprovider.Provider, path string) error {
+	_, priv, err := ed25519.GenerateKey(provider.Rand())
+	if err != nil {
+		return err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := pem.Encode(f, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -8,22 +8,63 @@ import (
 	"log"
 	"net/http"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/audit"
+	"github.com/makkenzo/license-service-api/internal/authz"
+	"github.com/makkenzo/license-service-api/internal/background"
+	"github.com/makkenzo/license-service-api/internal/buildinfo"
+	"github.com/makkenzo/license-service-api/internal/cache"
+	"github.com/makkenzo/license-service-api/internal/chaos"
 	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+	"github.com/makkenzo/license-service-api/internal/display"
+	"github.com/makkenzo/license-service-api/internal/domain/activation"
+	"github.com/makkenzo/license-service-api/internal/domain/apikey"
+	domainaudit "github.com/makkenzo/license-service-api/internal/domain/audit"
+	"github.com/makkenzo/license-service-api/internal/domain/customer"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/product"
+	"github.com/makkenzo/license-service-api/internal/enrichment"
 	"github.com/makkenzo/license-service-api/internal/handler"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
 	"github.com/makkenzo/license-service-api/internal/handler/middleware"
+	"github.com/makkenzo/license-service-api/internal/idgen"
 	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/lastseen"
+	"github.com/makkenzo/license-service-api/internal/lifecycle"
+	"github.com/makkenzo/license-service-api/internal/metering"
+	"github.com/makkenzo/license-service-api/internal/notify"
+	"github.com/makkenzo/license-service-api/internal/objectstore"
+	"github.com/makkenzo/license-service-api/internal/region"
+	"github.com/makkenzo/license-service-api/internal/rpc"
+	"github.com/makkenzo/license-service-api/internal/search"
+	"github.com/makkenzo/license-service-api/internal/servertiming"
 	"github.com/makkenzo/license-service-api/internal/service"
+	"github.com/makkenzo/license-service-api/internal/siem"
+	"github.com/makkenzo/license-service-api/internal/signing"
+	"github.com/makkenzo/license-service-api/internal/slotracker"
+	"github.com/makkenzo/license-service-api/internal/startup"
+	"github.com/makkenzo/license-service-api/internal/statusguard"
+	"github.com/makkenzo/license-service-api/internal/storage/cached"
+	"github.com/makkenzo/license-service-api/internal/storage/instrumented"
 	"github.com/makkenzo/license-service-api/internal/storage/postgres"
 	apikeyRepoImpl "github.com/makkenzo/license-service-api/internal/storage/postgres"
 	"github.com/makkenzo/license-service-api/internal/storage/redis"
+	"github.com/makkenzo/license-service-api/internal/tabular"
+	"github.com/makkenzo/license-service-api/internal/tasks"
+	"github.com/makkenzo/license-service-api/internal/templates"
 	"github.com/makkenzo/license-service-api/internal/worker"
+	"github.com/makkenzo/license-service-api/pkg/api/license/v1/licensev1connect"
 	"github.com/makkenzo/license-service-api/pkg/logger"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	goredis "github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
@@ -37,75 +78,565 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	appLogger, err := logger.NewZapLogger(cfg.Log.Level)
+	appLogger, err := logger.NewZapLogger(cfg.Log.Level, cfg.Log.Masking)
 	if err != nil {
 		log.Fatalf("Failed to initialize logger: %v", err)
 	}
 	defer appLogger.Sync()
+	appLogger = appLogger.With(
+		zap.String("instance_id", cfg.Deployment.InstanceID),
+		zap.String("version", buildinfo.ServiceVersion()),
+		zap.String("track", cfg.Deployment.Track),
+	)
 
 	sugarLogger := appLogger.Sugar()
 
 	sugarLogger.Info("Starting application...")
 	sugarLogger.Infof("Log level set to: %s", cfg.Log.Level)
 
+	cryptoProvider, err := cryptoprovider.New(&cfg.Crypto)
+	if err != nil {
+		sugarLogger.Fatalf("Failed to initialize crypto provider: %v", err)
+	}
+	sugarLogger.Infof("Using %s crypto provider", cryptoProvider.Name())
+
 	appCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	dbPool, err := postgres.NewPgxPool(appCtx, &cfg.Database, appLogger)
+	// The server listens while the dependencies come up, so that probes can
+	// tell an instance that is starting from one that is down. The API router
+	// takes over once every stage is done.
+	readiness := startup.NewReadiness()
+	rootHandler := startup.NewSwitch(readiness.StartingHandler())
+	httpServer := &http.Server{
+		Addr:         ":" + cfg.Server.Port,
+		Handler:      rootHandler,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+	serverErr := make(chan error, 1)
+	go func() {
+		sugarLogger.Infof("HTTP server listening on port %s", cfg.Server.Port)
+		serverErr <- httpServer.ListenAndServe()
+	}()
+
+	var dbPool *pgxpool.Pool
+	err = startup.Retry(appCtx, readiness, &cfg.Startup, appLogger, "postgres", func(ctx context.Context) error {
+		dbPool, err = postgres.NewPgxPool(ctx, &cfg.Database, appLogger)
+		return err
+	})
 	if err != nil {
 		sugarLogger.Fatalf("Failed to connect to PostgreSQL: %v", err)
 	}
 	defer dbPool.Close()
 
-	redisClient, err := redis.NewRedisClient(appCtx, &cfg.Redis, appLogger)
+	var redisClient *goredis.Client
+	err = startup.Retry(appCtx, readiness, &cfg.Startup, appLogger, "redis", func(ctx context.Context) error {
+		redisClient, err = redis.NewRedisClient(ctx, &cfg.Redis, appLogger)
+		return err
+	})
 	if err != nil {
 		sugarLogger.Fatalf("Failed to connect to Redis: %v", err)
 	}
 	defer redisClient.Close()
 
-	licenseRepo := postgres.NewLicenseRepository(dbPool, appLogger)
-	apiKeyRepo := apikeyRepoImpl.NewAPIKeyRepository(dbPool, appLogger)
+	ids, err := idgen.NewGenerator(cfg.Database.IDStrategy)
+	if err != nil {
+		sugarLogger.Fatalf("Invalid ID strategy: %v", err)
+	}
+	sugarLogger.Infof("New records get %s identifiers", cfg.Database.IDStrategy)
+
+	taskClient := asynq.NewClient(asynq.RedisClientOpt{
+		Addr:     cfg.Redis.Addr,
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+	defer taskClient.Close()
+
+	var regionForwarder *region.Forwarder
+	switch cfg.Region.Role {
+	case config.RegionPrimary:
+	case config.RegionReplica:
+		if cfg.Region.PrimaryURL == "" || cfg.Region.SharedSecret == "" {
+			sugarLogger.Fatal("REGION_PRIMARYURL and REGION_SHAREDSECRET are required when REGION_ROLE is replica")
+		}
+		regionForwarder = region.NewForwarder(taskClient, cfg.Region.Name, appLogger)
+		sugarLogger.Infof("Region %s is a read-only replica, writes are forwarded to %s", cfg.Region.Name, cfg.Region.PrimaryURL)
+	default:
+		sugarLogger.Fatalf("Invalid REGION_ROLE %q, expected %s or %s", cfg.Region.Role, config.RegionPrimary, config.RegionReplica)
+	}
+
+	primaryLicenses := postgres.NewLicenseRepository(dbPool, ids, appLogger)
+	var licenseStore license.Repository = primaryLicenses
+	var licenseExporter license.Exporter = primaryLicenses
+	var licenseArchiver license.Archiver = primaryLicenses
+	primaryProducts := postgres.NewProductRepository(dbPool, ids, appLogger)
+	var productStore product.Repository = primaryProducts
+	primaryCustomers := postgres.NewCustomerRepository(dbPool, ids, appLogger)
+	var customerRepo customer.Repository = primaryCustomers
+	// Each database holding licenses has its own outbox for the lifecycle
+	// hook dispatchers to follow.
+	licensePools, licensePoolRepos := []*pgxpool.Pool{dbPool}, []*postgres.LicenseRepository{primaryLicenses}
+	if len(cfg.Database.ShardURLs) > 0 {
+		var shardPools []*pgxpool.Pool
+		err = startup.Retry(appCtx, readiness, &cfg.Startup, appLogger, "postgres_shards", func(ctx context.Context) error {
+			shardPools, err = postgres.NewShardPools(ctx, &cfg.Database, appLogger)
+			return err
+		})
+		if err != nil {
+			sugarLogger.Fatalf("Failed to connect to PostgreSQL shards: %v", err)
+		}
+		shards := make([]*postgres.LicenseRepository, len(shardPools))
+		shardProducts := make([]*postgres.ProductRepository, len(shardPools))
+		shardCustomers := make([]*postgres.CustomerRepository, len(shardPools))
+		for i, pool := range shardPools {
+			defer pool.Close()
+			shards[i] = postgres.NewLicenseRepository(pool, ids, appLogger)
+			shardProducts[i] = postgres.NewProductRepository(pool, ids, appLogger)
+			shardCustomers[i] = postgres.NewCustomerRepository(pool, ids, appLogger)
+		}
+		licensePools, licensePoolRepos = shardPools, shards
+		shardedLicenses := postgres.NewShardedLicenseRepository(shards, appLogger)
+		licenseStore, licenseExporter, licenseArchiver = shardedLicenses, shardedLicenses, shardedLicenses
+		productStore = postgres.NewShardedProductRepository(primaryProducts, shardProducts, appLogger)
+		customerRepo = postgres.NewShardedCustomerRepository(primaryCustomers, shardCustomers, appLogger)
+		sugarLogger.Infof("License storage sharded across %d databases", len(shards))
+	}
+	licenseStore = chaos.WrapLicenseRepository(licenseStore, appLogger)
+
+	repoTracer := instrumented.NewLogTracer(cfg.Database.SlowQueryThreshold, appLogger)
+	licenseStore = instrumented.NewLicenseRepository(licenseStore, repoTracer)
+
+	statusGuard := statusguard.NewGuard(redisClient, &cfg.StatusGuard, appLogger)
+	if cfg.StatusGuard.Enabled {
+		licenseStore = statusguard.NewLicenseRepository(licenseStore, statusGuard, appLogger)
+	}
+
+	var siemExporter *siem.Exporter
+	if cfg.SIEM.Enabled {
+		siemExporter, err = siem.NewExporter(&cfg.SIEM, appLogger)
+		if err != nil {
+			sugarLogger.Fatalf("Failed to initialize SIEM exporter: %v", err)
+		}
+		sugarLogger.Infof("Exporting %v events to SIEM over %s", cfg.SIEM.Categories, cfg.SIEM.Transport)
+	}
+
+	auditRepo := postgres.NewAuditRepository(dbPool, ids, appLogger)
+	var auditEntries domainaudit.Repository = auditRepo
+	if siemExporter != nil {
+		auditEntries = siem.NewAuditRepository(auditRepo, siemExporter)
+	}
+	licenseStore = audit.NewLicenseRepository(licenseStore, auditEntries, appLogger)
+
+	mailer := notify.NewMailer(&cfg.Notify, cryptoProvider, appLogger)
+	renderer, err := templates.NewRenderer(cfg.Templates.DefaultLocale)
+	if err != nil {
+		sugarLogger.Fatalf("Failed to load templates: %v", err)
+	}
+	localizer := display.NewLocalizer(cfg.Templates.DefaultLocale)
+
+	backgroundPool := background.NewPool(&cfg.Background, appLogger)
+
+	webhookService := service.NewWebhookService(postgres.NewWebhookRepository(dbPool, ids, appLogger), taskClient, backgroundPool, cryptoProvider, mailer, &cfg.Webhooks, appLogger)
+	lifecycleHooks := []lifecycle.LicenseLifecycleHook{webhookService}
+	if cfg.LifecycleHooks.WebhookURL != "" {
+		webhookHook, err := lifecycle.NewWebhookHook(&cfg.LifecycleHooks, cryptoProvider)
+		if err != nil {
+			sugarLogger.Fatalf("Invalid LIFECYCLEHOOKS_WEBHOOKEVENTS: %v", err)
+		}
+		if cfg.LifecycleHooks.WebhookSecret == "" {
+			sugarLogger.Warn("LIFECYCLEHOOKS_WEBHOOKSECRET is not set, lifecycle webhooks are sent unsigned")
+		}
+		lifecycleHooks = append(lifecycleHooks, webhookHook)
+	}
+	emailEvents, err := lifecycle.ParseEvents(cfg.LifecycleHooks.EmailEvents)
+	if err != nil {
+		sugarLogger.Fatalf("Invalid LIFECYCLEHOOKS_EMAILEVENTS: %v", err)
+	}
+	if len(emailEvents) > 0 {
+		lifecycleHooks = append(lifecycleHooks, lifecycle.NewEmailHook(mailer, renderer, localizer, emailEvents))
+	}
+	// Replicas forward their writes, which reach the primary's outbox when
+	// they are applied there.
+	var lifecycleDispatchers []*lifecycle.Dispatcher
+	if !cfg.Region.Replica() {
+		for i, pool := range licensePools {
+			lifecycleDispatchers = append(lifecycleDispatchers, lifecycle.NewDispatcher(postgres.NewOutboxRepository(pool, appLogger), licensePoolRepos[i], lifecycleHooks, &cfg.LifecycleHooks, appLogger))
+		}
+		sugarLogger.Infof("%d license lifecycle hook(s) enabled", len(lifecycleHooks))
+	}
+	// Forwarded writes are audited when they are applied on the primary.
+	if regionForwarder != nil {
+		licenseStore = region.NewLicenseRepository(licenseStore, regionForwarder)
+	}
+
+	appCache, err := cache.NewFromConfig(&cfg.Cache, redisClient)
+	if err != nil {
+		sugarLogger.Fatalf("Failed to initialize cache: %v", err)
+	}
+	sugarLogger.Infof("Cache layers: %v", cfg.Cache.Layers)
+	if cfg.Server.ServerTiming {
+		appCache = cache.WithServerTiming(appCache)
+	}
+
+	licenseRepo := cached.NewLicenseRepository(licenseStore, appCache, cfg.Cache.LicenseTTL, cfg.Cache.AggregateTTL, appLogger)
+	var apiKeyStore apikey.Repository = instrumented.NewAPIKeyRepository(apikeyRepoImpl.NewAPIKeyRepository(dbPool, ids, appLogger), repoTracer)
+	if regionForwarder != nil {
+		apiKeyStore = region.NewAPIKeyRepository(apiKeyStore, regionForwarder)
+	}
+	apiKeyRepo := cached.NewAPIKeyRepository(apiKeyStore, appCache, cfg.Cache.APIKeyTTL, appLogger)
+	bindingFailureRepo := redis.NewBindingFailureRepository(redisClient, appLogger)
+	outboxRepo := postgres.NewOutboxRepository(dbPool, appLogger)
+	renewalRepo := postgres.NewRenewalRepository(dbPool, ids, appLogger)
+	var activationRepo activation.Repository = postgres.NewActivationRepository(dbPool, ids, appLogger)
+	if regionForwarder != nil {
+		activationRepo = region.NewActivationRepository(activationRepo, regionForwarder, ids)
+	}
+	statusHistoryRepo := postgres.NewStatusHistoryRepository(dbPool, ids, appLogger)
+	dashboardRepo := postgres.NewDashboardRepository(dbPool, ids, appLogger)
+	noteRepo := postgres.NewNoteRepository(dbPool, ids, appLogger)
+	entitlementRepo := postgres.NewEntitlementRepository(dbPool, ids, appLogger)
+	usageRepo := postgres.NewUsageRepository(dbPool, appLogger)
+	expiryNoticeRepo := postgres.NewExpiryNoticeRepository(dbPool, appLogger)
+	leaseRepo := redis.NewLeaseRepository(redisClient, appLogger)
+	licenseTemplateRepo := postgres.NewLicenseTemplateRepository(dbPool, ids, appLogger)
+	approvalRepo := postgres.NewApprovalRepository(dbPool, ids, appLogger)
+	protectedKeyRepo := postgres.NewProtectedKeyRepository(dbPool, appLogger)
+	productRepo := cached.NewProductRepository(productStore, appCache, cfg.Cache.ProductTTL, appLogger)
+	keyring := signing.NewKeyring(postgres.NewSigningKeyRepository(dbPool, appLogger), cryptoProvider, &cfg.Signing, appLogger)
+	if err := keyring.Load(appCtx); err != nil {
+		sugarLogger.Errorf("Failed to load signing keys: %v", err)
+	} else if keyring.Algorithm() == "" && cfg.Signing.KeyRef != "" && !cfg.Region.Replica() {
+		// Instances starting together may race to register the same key;
+		// the loser just picks up the winner's row.
+		_, err := keyring.Rotate(appCtx, cfg.Signing.KeyRef)
+		if errors.Is(err, ierr.ErrDuplicateKey) {
+			err = keyring.Load(appCtx)
+		}
+		if err != nil {
+			sugarLogger.Fatalf("Failed to register signing key from SIGNING_KEYREF: %v", err)
+		}
+	}
+	if keyring.Algorithm() == "" {
+		sugarLogger.Warn("No active signing key, offline license files are disabled")
+	}
+
+	customStatusService := service.NewCustomStatusService(postgres.NewCustomStatusRepository(dbPool, appLogger), licenseRepo, &cfg.CustomStatuses, appLogger)
+	if err := customStatusService.Load(appCtx); err != nil {
+		sugarLogger.Errorf("Failed to load custom license statuses: %v", err)
+	}
+	if err := dto.RegisterLicenseStatusValidator(customStatusService.Known); err != nil {
+		sugarLogger.Fatalf("Failed to register license status validator: %v", err)
+	}
 
-	licenseService := service.NewLicenseService(licenseRepo, appLogger)
-	authService, err := service.NewAuthService(appCtx, &cfg.OIDC, appLogger)
+	var lastSeenStore *lastseen.Store
+	if cfg.LastSeen.Enabled {
+		lastSeenStore = lastseen.NewStore(redisClient, &cfg.LastSeen, appLogger)
+	}
+	validationEventService, err := service.NewValidationEventService(postgres.NewValidationEventRepository(dbPool, ids, appLogger), backgroundPool, &cfg.ValidationEvents, appLogger)
+	if err != nil {
+		sugarLogger.Fatalf("Invalid VALIDATIONEVENTS_PRODUCTSAMPLERATES: %v", err)
+	}
+	var validationEvents *service.ValidationEventService
+	if cfg.ValidationEvents.Enabled {
+		if cfg.Region.Replica() {
+			sugarLogger.Warn("Validation events are not recorded on read-only replicas")
+		} else {
+			validationEvents = validationEventService
+		}
+	}
+	// Replicas cannot record deliveries, so validation failures there are
+	// not sent to webhooks.
+	validationWebhooks := webhookService
+	if cfg.Region.Replica() {
+		validationWebhooks = nil
+	}
+	apiUsageRepo := postgres.NewAPIUsageRepository(dbPool, appLogger)
+	var meter *metering.Meter
+	if cfg.Metering.Enabled {
+		if cfg.Region.Replica() {
+			sugarLogger.Warn("API usage is not metered on read-only replicas")
+		} else {
+			meter = metering.NewMeter(apiUsageRepo, &cfg.Metering, appLogger)
+		}
+	}
+	sloRepo := redis.NewSLORepository(redisClient, cfg.SLO.Window, appLogger)
+	var sloTracker *slotracker.Tracker
+	if cfg.SLO.Enabled {
+		if cfg.SLO.AvailabilityObjective <= 0 || cfg.SLO.AvailabilityObjective >= 1 || cfg.SLO.LatencyObjective <= 0 || cfg.SLO.LatencyObjective >= 1 {
+			appLogger.Fatal("SLO objectives must be between 0 and 1",
+				zap.Float64("availability", cfg.SLO.AvailabilityObjective), zap.Float64("latency", cfg.SLO.LatencyObjective))
+		}
+		sloTracker = slotracker.NewTracker(sloRepo, &cfg.SLO, appLogger)
+	}
+	enrichmentProviders := []enrichment.Provider{enrichment.NewHeaderProvider(&cfg.Enrichment)}
+	if cfg.Enrichment.IP2ASNFile != "" {
+		ip2asn, err := enrichment.NewIP2ASNProvider(cfg.Enrichment.IP2ASNFile)
+		if err != nil {
+			sugarLogger.Fatalf("Failed to load ENRICHMENT_IP2ASNFILE: %v", err)
+		}
+		sugarLogger.Infof("Loaded %d IP-to-ASN ranges for validation event enrichment", ip2asn.Ranges())
+		enrichmentProviders = append(enrichmentProviders, ip2asn)
+	}
+	enrichMiddleware := middleware.EnrichmentMiddleware(enrichment.NewEnricher(enrichmentProviders, appLogger))
+	licenseService := service.NewLicenseService(licenseRepo, productRepo, customerRepo, activationRepo, statusHistoryRepo, entitlementRepo, licenseTemplateRepo, usageRepo, leaseRepo, lastSeenStore, validationEvents, validationWebhooks, backgroundPool, keyring, customStatusService, &cfg.Query, appLogger)
+	approvalService := service.NewApprovalService(approvalRepo, protectedKeyRepo, licenseRepo, licenseService, &cfg.Approval, appLogger)
+	var authService *service.AuthService
+	err = startup.Retry(appCtx, readiness, &cfg.Startup, appLogger, "oidc", func(ctx context.Context) error {
+		authService, err = service.NewAuthService(ctx, &cfg.OIDC, appLogger)
+		return err
+	})
 	if err != nil {
 		sugarLogger.Fatalf("Failed to initialize Authentication Service: %v", err)
 	}
 	sugarLogger.Info("Authentication Service initialized successfully.")
-	apiKeyService := service.NewAPIKeyService(apiKeyRepo, appLogger)
+	authzEnforcer, err := authz.NewEnforcer(&cfg.Authz, appLogger)
+	if err != nil {
+		sugarLogger.Fatalf("Failed to load authorization policy: %v", err)
+	}
+	var stepUpService *service.StepUpService
+	if cfg.StepUp.Enabled {
+		totpFactor := service.NewTOTPFactor(postgres.NewTOTPRepository(dbPool, appLogger), cryptoProvider, cfg.StepUp.TOTPIssuer, appLogger)
+		stepUpService = service.NewStepUpService(&cfg.StepUp, redis.NewStepUpGrantRepository(redisClient, appLogger), totpFactor, appLogger)
+		sugarLogger.Infof("Step-up authentication is required for destructive actions, max age %s", cfg.StepUp.MaxAge)
+	}
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, productRepo, cryptoProvider, authzEnforcer, appLogger)
+	reportService := service.NewReportService(bindingFailureRepo, appLogger)
+	expirationService := service.NewExpirationService(licenseRepo, appLogger)
+	changeFeedService := service.NewChangeFeedService(outboxRepo, &cfg.ChangeFeed, appLogger)
+	statusFreezeService := service.NewStatusFreezeService(licenseRepo, statusGuard, appLogger)
+	expiryNoticeService := service.NewExpiryNoticeService(licenseRepo, expiryNoticeRepo, appLogger)
+	auditService := service.NewAuditService(auditRepo, licenseRepo, appLogger)
+	templateService := service.NewTemplateService(licenseRepo, renderer, appLogger)
+	customerService := service.NewCustomerService(customerRepo, appLogger)
+	entitlementService := service.NewEntitlementService(entitlementRepo, licenseRepo, appLogger)
+	licenseTemplateService := service.NewLicenseTemplateService(licenseTemplateRepo, productRepo, appLogger)
+	commentService := service.NewCommentService(postgres.NewCommentRepository(dbPool, ids, appLogger), licenseRepo, appLogger)
+	extensionService := service.NewExtensionService(postgres.NewExtensionRepository(dbPool, ids, appLogger), licenseRepo, &cfg.Approval, appLogger)
+	metaService := service.NewMetaService(licenseService, licenseRepo, customStatusService, authzEnforcer, &cfg.Approval, appLogger)
+	noteService := service.NewNoteService(noteRepo, licenseRepo, customerRepo, cryptoProvider, &cfg.Notify, appLogger)
+	productService := service.NewProductService(productRepo, licenseRepo, taskClient, appLogger)
+	renewalService := service.NewRenewalService(renewalRepo, licenseRepo, mailer, renderer, cryptoProvider, &cfg.Renewal, appLogger)
+	if cfg.Renewal.SigningSecret == "" {
+		sugarLogger.Warn("RENEWAL_SIGNINGSECRET is not set, self-service renewal links are disabled")
+	}
+	campaignService := service.NewCampaignService(postgres.NewCampaignRepository(dbPool, ids, appLogger), licenseRepo, mailer, renderer, cryptoProvider, taskClient, &cfg.Campaigns, appLogger)
+	if cfg.Campaigns.UnsubscribeSecret == "" {
+		sugarLogger.Warn("CAMPAIGNS_UNSUBSCRIBESECRET is not set, e-mail campaigns are disabled")
+	}
+	if cfg.Notify.ReplyAddress != "" && cfg.Notify.ReplySecret == "" {
+		sugarLogger.Warn("NOTIFY_REPLYSECRET is not set, replies to notification e-mails are not attached to licenses")
+	}
+	activationService := service.NewActivationService(activationRepo, licenseRepo, &cfg.Heartbeat, appLogger)
+	offlineActivationService := service.NewOfflineActivationService(postgres.NewOfflineActivationRepository(dbPool, ids, appLogger), licenseRepo, entitlementRepo, activationService, keyring, cryptoProvider, &cfg.OfflineActivation, appLogger)
+	telemetryService := service.NewTelemetryService(licenseRepo, &cfg.Telemetry, appLogger)
+	var analyticsStore objectstore.Store
+	if cfg.AnalyticsExport.Enabled {
+		switch strings.ToLower(cfg.AnalyticsExport.Format) {
+		case tabular.FormatCSV, tabular.FormatParquet:
+		default:
+			sugarLogger.Fatalf("Invalid ANALYTICSEXPORT_FORMAT %q, expected %s or %s", cfg.AnalyticsExport.Format, tabular.FormatCSV, tabular.FormatParquet)
+		}
+		if cfg.AnalyticsExport.S3.Bucket != "" {
+			s3Client, err := objectstore.NewS3Client(&cfg.AnalyticsExport.S3, cryptoProvider)
+			if err != nil {
+				sugarLogger.Fatalf("Failed to initialize analytics export S3 client: %v", err)
+			}
+			analyticsStore = s3Client
+		}
+	}
+	analyticsExportService := service.NewAnalyticsExportService(postgres.NewAnalyticsRepository(dbPool, appLogger), analyticsStore, cryptoProvider, &cfg.AnalyticsExport, appLogger)
+	var lintWebhooks []service.LintWebhook
+	if cfg.LifecycleHooks.WebhookURL != "" {
+		lintWebhooks = append(lintWebhooks, service.LintWebhook{Name: "lifecycle_hooks", URL: cfg.LifecycleHooks.WebhookURL})
+	}
+	if cfg.SIEM.Enabled && strings.EqualFold(cfg.SIEM.Transport, "http") && cfg.SIEM.URL != "" {
+		lintWebhooks = append(lintWebhooks, service.LintWebhook{Name: "siem", URL: cfg.SIEM.URL})
+	}
+	lintService := service.NewLintService(productRepo, licenseTemplateRepo, lintWebhooks, appLogger)
+	apiUsageService := service.NewAPIUsageService(apiUsageRepo, apiKeyRepo, appLogger)
+	integrityService := service.NewIntegrityService(postgres.NewIntegrityRepository(dbPool, ids, appLogger), licenseRepo, productRepo, activationRepo, taskClient, appLogger)
+	dashboardService := service.NewDashboardService(dashboardRepo, appLogger)
+	suspensionService := service.NewSuspensionService(licenseRepo, statusHistoryRepo, appLogger)
+	revocationService := service.NewRevocationService(licenseRepo, statusHistoryRepo, appLogger)
+	usageService := service.NewUsageService(usageRepo, licenseRepo, entitlementRepo, appLogger)
+	floatingService := service.NewFloatingService(leaseRepo, licenseRepo, &cfg.Floating, appLogger)
+	keyRotationService := service.NewKeyRotationService(licenseRepo, productRepo, &cfg.KeyRotation, appLogger)
+	licenseHierarchyService := service.NewLicenseHierarchyService(licenseRepo, productRepo, appLogger)
+	exportService := service.NewExportService(licenseExporter, &cfg.Query, appLogger)
+	archiveService := service.NewArchiveService(licenseArchiver, postgres.NewArchiveRepository(dbPool, appLogger), &cfg.Archive, appLogger)
+	bulkRevokeService := service.NewBulkRevokeService(licenseRepo, redis.NewConfirmationRepository(redisClient, appLogger), cryptoProvider, appLogger)
 
-	healthHandler := handler.NewHealthHandler(dbPool, redisClient, appLogger)
-	licenseHandler := handler.NewLicenseHandler(licenseService, appLogger)
-	dashboardHandler := handler.NewDashboardHandler(licenseService, appLogger)
+	healthHandler := handler.NewHealthHandler(dbPool, redisClient, &cfg.Deployment, appLogger)
+	licenseHandler := handler.NewLicenseHandler(licenseService, approvalService, archiveService, localizer, appLogger)
+	dashboardHandler := handler.NewDashboardHandler(licenseService, dashboardService, appLogger)
+	sloHandler := handler.NewSLOHandler(service.NewSLOService(sloRepo, &cfg.SLO, appLogger), appLogger)
 	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService, appLogger)
+	reportHandler := handler.NewReportHandler(reportService, appLogger)
+	expirationHandler := handler.NewExpirationHandler(expirationService, appLogger)
+	changeFeedHandler := handler.NewChangeFeedHandler(changeFeedService, appLogger)
+	statusFreezeHandler := handler.NewStatusFreezeHandler(statusFreezeService, appLogger)
+	expiryNoticeHandler := handler.NewExpiryNoticeHandler(expiryNoticeService, appLogger)
+	renewalHandler := handler.NewRenewalHandler(renewalService, appLogger)
+	productHandler := handler.NewProductHandler(productService, appLogger)
+	customerHandler := handler.NewCustomerHandler(customerService, licenseService, appLogger)
+	noteHandler := handler.NewNoteHandler(noteService, appLogger)
+	commentHandler := handler.NewCommentHandler(commentService, appLogger)
+	extensionHandler := handler.NewExtensionHandler(extensionService, appLogger)
+	metaHandler := handler.NewMetaHandler(metaService, appLogger)
+	entitlementHandler := handler.NewEntitlementHandler(entitlementService, appLogger)
+	licenseTemplateHandler := handler.NewLicenseTemplateHandler(licenseTemplateService, appLogger)
+	customStatusHandler := handler.NewCustomStatusHandler(customStatusService, appLogger)
+	approvalHandler := handler.NewApprovalHandler(approvalService, appLogger)
+	templateHandler := handler.NewTemplateHandler(templateService, appLogger)
+	auditHandler := handler.NewAuditHandler(auditService, appLogger)
+	telemetryHandler := handler.NewTelemetryHandler(telemetryService, appLogger)
+	analyticsExportHandler := handler.NewAnalyticsExportHandler(analyticsExportService, appLogger)
+	lintHandler := handler.NewLintHandler(lintService, appLogger)
+	integrityHandler := handler.NewIntegrityHandler(integrityService, appLogger)
+	campaignHandler := handler.NewCampaignHandler(campaignService, appLogger)
+	apiUsageHandler := handler.NewAPIUsageHandler(apiUsageService, appLogger)
+	webhookHandler := handler.NewWebhookHandler(webhookService, appLogger)
+	activationHandler := handler.NewActivationHandler(activationService, appLogger)
+	offlineActivationHandler := handler.NewOfflineActivationHandler(offlineActivationService, appLogger)
+	signingKeyHandler := handler.NewSigningKeyHandler(keyring, appLogger)
+	exportHandler := handler.NewExportHandler(exportService, appLogger)
+	bulkRevokeHandler := handler.NewBulkRevokeHandler(bulkRevokeService, appLogger)
+	suspensionHandler := handler.NewSuspensionHandler(suspensionService, appLogger)
+	revocationHandler := handler.NewRevocationHandler(revocationService, appLogger)
+	usageHandler := handler.NewUsageHandler(usageService, appLogger)
+	floatingHandler := handler.NewFloatingHandler(floatingService, appLogger)
+	keyRotationHandler := handler.NewKeyRotationHandler(keyRotationService, appLogger)
+	licenseHierarchyHandler := handler.NewLicenseHierarchyHandler(licenseHierarchyService, licenseService, appLogger)
+	validationEventHandler := handler.NewValidationEventHandler(validationEventService, appLogger)
 
-	authMiddleware := middleware.AuthMiddleware(authService, appLogger)
-	apiKeyAuthMiddleware := middleware.APIKeyAuthMiddleware(apiKeyRepo, appLogger)
+	authMiddleware := middleware.AuthMiddleware(authService, authzEnforcer, appLogger)
+	stepUpMiddleware := middleware.RequireStepUp(stepUpService, appLogger)
+	apiKeyAuthMiddleware := middleware.APIKeyAuthMiddleware(apiKeyRepo, backgroundPool, cryptoProvider, appLogger)
 	errorMiddleware := middleware.ErrorHandlerMiddleware(appLogger)
+	bindingFailureMiddleware := middleware.BindingFailureMiddleware(bindingFailureRepo, backgroundPool, appLogger)
 
-	startupCtx, cancelStartup := context.WithTimeout(context.Background(), 5*time.Minute)
-	updatedCount, startupCheckErr := service.CheckAndExpireLicenses(startupCtx, licenseRepo, appLogger)
-	cancelStartup()
-	if startupCheckErr != nil {
-		sugarLogger.Errorf("Initial license expiration check failed: %v", startupCheckErr)
-	} else {
-		sugarLogger.Infof("Initial license expiration check completed. Updated %d licenses.", updatedCount)
+	if !cfg.Region.Replica() {
+		startupCtx, cancelStartup := context.WithTimeout(context.Background(), 5*time.Minute)
+		updatedCount, startupCheckErr := service.CheckAndExpireLicenses(startupCtx, licenseRepo, appLogger)
+		cancelStartup()
+		if startupCheckErr != nil {
+			sugarLogger.Errorf("Initial license expiration check failed: %v", startupCheckErr)
+		} else {
+			sugarLogger.Infof("Initial license expiration check completed. Updated %d licenses.", updatedCount)
+		}
+	}
+
+	campaignTaskHandler := tasks.NewCampaignHandler(campaignService, appLogger)
+	workerJobs := []worker.Job{
+		{
+			TaskType: tasks.TypeBindingFailureReport,
+			Handler:  tasks.NewBindingFailureReportHandler(bindingFailureRepo, 20, appLogger),
+			Schedule: "0 8 * * 1",
+			NewTask:  func() (*asynq.Task, error) { return tasks.NewBindingFailureReportTask() },
+		},
+		{
+			TaskType: tasks.TypeMetadataMigration,
+			Handler:  tasks.NewMetadataMigrationHandler(productService, appLogger),
+		},
+		{
+			TaskType: tasks.TypeIntegrityCheck,
+			Handler:  tasks.NewIntegrityCheckHandler(integrityService, appLogger),
+		},
+		{
+			TaskType: tasks.TypeWebhookDeliver,
+			Handler:  tasks.NewWebhookDeliverHandler(webhookService, appLogger),
+		},
+		{
+			TaskType: tasks.TypeCampaignPrepare,
+			Handler:  campaignTaskHandler,
+		},
+		{
+			TaskType: tasks.TypeCampaignSend,
+			Handler:  campaignTaskHandler,
+		},
+	}
+	if regionForwarder != nil {
+		workerJobs = append(workerJobs, worker.Job{
+			TaskType: tasks.TypeRegionForward,
+			Handler:  tasks.NewRegionForwardHandler(region.NewClient(&cfg.Region, cryptoProvider), appLogger),
+		})
+	}
+	if cfg.Cache.Prewarm.Enabled {
+		prewarmCtx, cancelPrewarm := context.WithTimeout(appCtx, time.Minute)
+		warmedCount, prewarmErr := licenseRepo.Warm(prewarmCtx, cfg.Cache.Prewarm.TopN)
+		cancelPrewarm()
+		if prewarmErr != nil {
+			sugarLogger.Errorf("Initial license cache pre-warm failed: %v", prewarmErr)
+		} else {
+			sugarLogger.Infof("Initial license cache pre-warm completed. Cached %d licenses.", warmedCount)
+		}
+
+		workerJobs = append(workerJobs, worker.Job{
+			TaskType: tasks.TypeLicenseCachePrewarm,
+			Handler:  tasks.NewCachePrewarmHandler(licenseRepo, cfg.Cache.Prewarm.TopN, appLogger),
+			Schedule: fmt.Sprintf("@every %s", cfg.Cache.Prewarm.Interval),
+			NewTask:  func() (*asynq.Task, error) { return tasks.NewLicenseCachePrewarmTask() },
+		})
+	}
+	if telemetryService.Active() {
+		sugarLogger.Infof("Anonymous telemetry is enabled, reporting every %s", cfg.Telemetry.Interval)
+		workerJobs = append(workerJobs, worker.Job{
+			TaskType: tasks.TypeTelemetryPing,
+			Handler:  tasks.NewTelemetryPingHandler(telemetryService, appLogger),
+			Schedule: fmt.Sprintf("@every %s", cfg.Telemetry.Interval),
+			NewTask:  func() (*asynq.Task, error) { return tasks.NewTelemetryPingTask() },
+		})
+	} else if cfg.Telemetry.Enabled {
+		sugarLogger.Warn("TELEMETRY_ENABLED is set but no telemetry endpoint is configured, usage pings are disabled")
+	}
+	if cfg.ExpiryNotify.Enabled && !cfg.Region.Replica() {
+		expiredWindow := time.Duration(0)
+		if cfg.ExpiryNotify.NotifyExpired {
+			expiredWindow = cfg.ExpiryNotify.ExpiredWindow
+		}
+		sugarLogger.Infof("License expiry e-mails are enabled, reminding %v days ahead on schedule %q", cfg.ExpiryNotify.ReminderDays, cfg.ExpiryNotify.Schedule)
+		workerJobs = append(workerJobs, worker.Job{
+			TaskType: tasks.TypeExpiryNotify,
+			Handler:  tasks.NewExpiryNotifyHandler(licenseRepo, expiryNoticeRepo, mailer, renderer, localizer, cfg.ExpiryNotify.ReminderDays, expiredWindow, appLogger),
+			Schedule: cfg.ExpiryNotify.Schedule,
+			NewTask:  func() (*asynq.Task, error) { return tasks.NewExpiryNotifyTask() },
+		})
+	}
+	if cfg.Archive.Enabled && !cfg.Region.Replica() {
+		if cfg.Archive.MinAgeYears < 1 || cfg.Archive.BatchSize < 1 {
+			appLogger.Fatal("ARCHIVE_MINAGEYEARS and ARCHIVE_BATCHSIZE must be at least 1",
+				zap.Int("min_age_years", cfg.Archive.MinAgeYears), zap.Int("batch_size", cfg.Archive.BatchSize))
+		}
+		sugarLogger.Infof("License archiving is enabled, archiving licenses terminal for %d years on schedule %q", cfg.Archive.MinAgeYears, cfg.Archive.Schedule)
+		workerJobs = append(workerJobs, worker.Job{
+			TaskType: tasks.TypeLicenseArchive,
+			Handler:  tasks.NewLicenseArchiveHandler(archiveService, appLogger),
+			Schedule: cfg.Archive.Schedule,
+			NewTask:  func() (*asynq.Task, error) { return tasks.NewLicenseArchiveTask() },
+		})
+	}
+	if analyticsExportService.Active() {
+		sugarLogger.Infof("Analytics export is enabled, uploading to bucket %s on schedule %q", cfg.AnalyticsExport.S3.Bucket, cfg.AnalyticsExport.Schedule)
+		workerJobs = append(workerJobs, worker.Job{
+			TaskType: tasks.TypeAnalyticsExport,
+			Handler:  tasks.NewAnalyticsExportHandler(analyticsExportService, appLogger),
+			Schedule: cfg.AnalyticsExport.Schedule,
+			NewTask:  func() (*asynq.Task, error) { return tasks.NewAnalyticsExportTask() },
+		})
+	} else if cfg.AnalyticsExport.Enabled {
+		sugarLogger.Warn("ANALYTICSEXPORT_ENABLED is set but ANALYTICSEXPORT_S3_BUCKET or ANALYTICSEXPORT_PSEUDONYMSECRET is missing, analytics exports are disabled")
 	}
 
 	router := gin.New()
-	router.Use(gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		return fmt.Sprintf("%s - [%s] \"%s %s %s %d %s \"%s\" %s\"\n",
-			param.ClientIP,
-			param.TimeStamp.Format(time.RFC1123),
-			param.Method,
-			param.Path,
-			param.Request.Proto,
-			param.StatusCode,
-			param.Latency,
-			param.Request.UserAgent(),
-			param.ErrorMessage,
-		)
-	}))
+	router.Use(middleware.AccessLogMiddleware(appLogger))
+	if cfg.Server.ServerTiming {
+		router.Use(middleware.ServerTimingMiddleware())
+		appLogger.Warn("Server-Timing headers enabled; they expose request internals and are meant for debugging")
+	}
+	// Ahead of recovery, so that requests which panic are counted as 500s.
+	router.Use(middleware.DeploymentMiddleware(&cfg.Deployment))
 	router.Use(gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
 		logMsg := "Panic recovered"
 		if err, ok := recovered.(string); ok {
@@ -129,34 +660,139 @@ func main() {
 			"Authorization",
 			"X-API-Key",
 		},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", middleware.HeaderInstanceID, middleware.HeaderServiceVersion, middleware.HeaderReleaseTrack, servertiming.HeaderName},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}
 	router.Use(cors.New(corsConfig))
+	if siemExporter != nil {
+		router.Use(middleware.SecurityEventsMiddleware(siemExporter))
+	}
+	if meter != nil {
+		router.Use(middleware.MeteringMiddleware(meter))
+	}
+	if sloTracker != nil {
+		router.Use(middleware.SLOMiddleware(sloTracker, http.MethodPost, "/api/v1/licenses/validate"))
+	}
 	router.Use(errorMiddleware)
+	router.Use(bindingFailureMiddleware)
+	if cfg.Region.Replica() {
+		router.Use(middleware.ReadOnlyRegionMiddleware(cfg.Region.PrimaryURL))
+	}
 
 	router.GET("/healthz", healthHandler.Check)
+	router.GET("/readyz", gin.WrapH(readiness))
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	router.GET("/.well-known/jwks.json", signingKeyHandler.JWKS)
+	licenseEventRPC := rpc.NewLicenseEventServer(changeFeedService, appLogger).Handler()
+	router.POST(licensev1connect.LicenseEventServiceSubscribeLicenseEventsProcedure, authMiddleware, gin.WrapH(licenseEventRPC))
+	if !cfg.Region.Replica() && cfg.Region.SharedSecret != "" {
+		regionHandler := handler.NewRegionHandler(region.NewApplier(licenseRepo, activationRepo, apiKeyRepo, appLogger), cryptoProvider, cfg.Region.SharedSecret, appLogger)
+		router.POST(region.WritesPath, regionHandler.ApplyWrite)
+	}
+
+	validateScope := middleware.RequireAPIKeyScope(apikey.ScopeLicensesValidate)
+	activationsScope := middleware.RequireAPIKeyScope(apikey.ScopeActivationsWrite)
+	usageWriteScope := middleware.RequireAPIKeyScope(apikey.ScopeUsageWrite)
+	usageReadScope := middleware.RequireAPIKeyScope(apikey.ScopeUsageRead)
 
 	apiV1 := router.Group("/api/v1")
 	{
 		licenseRoutes := apiV1.Group("/licenses")
 		{
-			licenseRoutes.POST("/validate", apiKeyAuthMiddleware, licenseHandler.Validate)
+			licenseRoutes.POST("/validate", apiKeyAuthMiddleware, validateScope, enrichMiddleware, licenseHandler.Validate)
+			licenseRoutes.POST("/validate/batch", apiKeyAuthMiddleware, validateScope, enrichMiddleware, licenseHandler.ValidateBatch)
+			licenseRoutes.POST("/passport", apiKeyAuthMiddleware, validateScope, enrichMiddleware, licenseHandler.Passport)
+			licenseRoutes.GET("/capabilities", apiKeyAuthMiddleware, licenseHandler.Capabilities)
+			licenseRoutes.GET("/revoked", apiKeyAuthMiddleware, validateScope, revocationHandler.List)
+			licenseRoutes.POST("/activate", apiKeyAuthMiddleware, activationsScope, activationHandler.Activate)
+			licenseRoutes.POST("/deactivate", apiKeyAuthMiddleware, activationsScope, activationHandler.Deactivate)
+			licenseRoutes.POST("/heartbeat", apiKeyAuthMiddleware, activationsScope, activationHandler.Heartbeat)
+			licenseRoutes.POST("/offline/challenge", apiKeyAuthMiddleware, activationsScope, offlineActivationHandler.Challenge)
+			licenseRoutes.POST("/usage", apiKeyAuthMiddleware, usageWriteScope, usageHandler.Report)
+			licenseRoutes.POST("/checkout", apiKeyAuthMiddleware, activationsScope, floatingHandler.Checkout)
+			licenseRoutes.POST("/checkin", apiKeyAuthMiddleware, activationsScope, floatingHandler.Checkin)
+			// Gin needs one wildcard name per segment, so the license key is
+			// matched as :id.
+			licenseRoutes.GET("/:id/usage-summary", apiKeyAuthMiddleware, usageReadScope, activationHandler.UsageSummary)
 
 			licenseRoutes.Use(authMiddleware)
 
 			licenseRoutes.POST("", licenseHandler.Create)
 			licenseRoutes.GET("", licenseHandler.List)
+			licenseRoutes.GET("/changes", changeFeedHandler.List)
+			licenseRoutes.GET("/changes/export", stepUpMiddleware, changeFeedHandler.Export)
+			licenseRoutes.GET("/export", stepUpMiddleware, exportHandler.Licenses)
+			licenseRoutes.GET("/aggregate", licenseHandler.Aggregate)
+			licenseRoutes.GET("/compare", licenseHandler.Compare)
+			licenseRoutes.GET("/search", licenseHandler.Search)
+			licenseRoutes.POST("/bulk-revoke", stepUpMiddleware, bulkRevokeHandler.BulkRevoke)
+			licenseRoutes.POST("/import", licenseHandler.Import)
 			licenseRoutes.GET("/:id", licenseHandler.GetByID)
 			licenseRoutes.PATCH("/:id", licenseHandler.Update)
 			licenseRoutes.PATCH("/:id/status", licenseHandler.UpdateStatus)
+			licenseRoutes.POST("/:id/suspend", suspensionHandler.Suspend)
+			licenseRoutes.POST("/:id/reinstate", suspensionHandler.Reinstate)
+			licenseRoutes.POST("/:id/revoke", revocationHandler.Revoke)
+			licenseRoutes.POST("/:id/rotate-key", stepUpMiddleware, keyRotationHandler.RotateKey)
+			licenseRoutes.POST("/:id/clone", licenseHandler.Clone)
+			licenseRoutes.GET("/:id/children", licenseHierarchyHandler.ListChildren)
+			licenseRoutes.POST("/:id/children", licenseHierarchyHandler.CreateChild)
+			licenseRoutes.GET("/:id/status-freeze", statusFreezeHandler.Get)
+			licenseRoutes.DELETE("/:id/status-freeze", statusFreezeHandler.Unfreeze)
+			licenseRoutes.GET("/:id/expiry-notifications", expiryNoticeHandler.Get)
+			licenseRoutes.PUT("/:id/expiry-notifications", expiryNoticeHandler.Update)
+			licenseRoutes.POST("/:id/renewal-offers", renewalHandler.CreateOffer)
+			licenseRoutes.GET("/:id/certificate", templateHandler.Certificate)
+			licenseRoutes.GET("/:id/license-file", licenseHandler.LicenseFile)
+			licenseRoutes.GET("/:id/activations", activationHandler.List)
+			licenseRoutes.GET("/:id/usage", usageHandler.List)
+			licenseRoutes.GET("/:id/audit", auditHandler.ListForLicense)
+			licenseRoutes.GET("/:id/notes", noteHandler.ListForLicense)
+			licenseRoutes.GET("/:id/comments", commentHandler.List)
+			licenseRoutes.POST("/:id/comments", commentHandler.Create)
+			licenseRoutes.DELETE("/:id/comments/:commentId", commentHandler.Delete)
+			licenseRoutes.GET("/:id/extension-requests", extensionHandler.ListForLicense)
+			licenseRoutes.POST("/:id/extension-requests", extensionHandler.Create)
+			licenseRoutes.GET("/:id/extension-requests/:requestId", extensionHandler.Get)
+			licenseRoutes.POST("/:id/extension-requests/:requestId/approve", extensionHandler.Approve)
+			licenseRoutes.POST("/:id/extension-requests/:requestId/reject", extensionHandler.Reject)
+			licenseRoutes.GET("/:id/entitlements", entitlementHandler.List)
+			licenseRoutes.PUT("/:id/entitlements/:name", entitlementHandler.Put)
+			licenseRoutes.DELETE("/:id/entitlements/:name", entitlementHandler.Delete)
+			licenseRoutes.POST("/:id/activations/reclaim", activationHandler.ReclaimStale)
+			licenseRoutes.DELETE("/:id/activations/:activationId", activationHandler.Revoke)
+		}
+		renewalRoutes := apiV1.Group("/renewals")
+		{
+			renewalRoutes.GET("/offer", renewalHandler.GetOffer)
+			renewalRoutes.POST("/accept", renewalHandler.Accept)
+		}
+		campaignRoutes := apiV1.Group("/campaigns")
+		{
+			// Unsubscribe links are opened by recipients, who have no account.
+			campaignRoutes.GET("/unsubscribe", campaignHandler.Unsubscribe)
+			campaignRoutes.POST("/unsubscribe", campaignHandler.Unsubscribe)
+
+			campaignRoutes.Use(authMiddleware)
+
+			campaignRoutes.POST("", stepUpMiddleware, campaignHandler.Create)
+			campaignRoutes.GET("", campaignHandler.List)
+			campaignRoutes.GET("/:id", campaignHandler.Get)
+			campaignRoutes.GET("/:id/recipients", campaignHandler.Recipients)
+			campaignRoutes.POST("/:id/cancel", campaignHandler.Cancel)
 		}
 		dashboardRoutes := apiV1.Group("/dashboard")
 		dashboardRoutes.Use(authMiddleware)
 		{
 			dashboardRoutes.GET("/summary", dashboardHandler.GetSummary)
+			dashboardRoutes.GET("/timeseries", dashboardHandler.GetTimeseries)
+			dashboardRoutes.GET("/slo", sloHandler.Report)
+			dashboardRoutes.POST("/widgets", dashboardHandler.CreateWidget)
+			dashboardRoutes.GET("/widgets", dashboardHandler.ListWidgets)
+			dashboardRoutes.GET("/widgets/:id", dashboardHandler.GetWidget)
+			dashboardRoutes.PATCH("/widgets/:id", dashboardHandler.UpdateWidget)
+			dashboardRoutes.DELETE("/widgets/:id", dashboardHandler.DeleteWidget)
 		}
 		apiKeyRoutes := apiV1.Group("/apikeys")
 		apiKeyRoutes.Use(authMiddleware)
@@ -165,22 +801,197 @@ func main() {
 			apiKeyRoutes.GET("", apiKeyHandler.List)
 			apiKeyRoutes.DELETE("/:id", apiKeyHandler.Revoke)
 		}
+		webhookRoutes := apiV1.Group("/webhooks")
+		webhookRoutes.Use(authMiddleware)
+		{
+			webhookRoutes.POST("", webhookHandler.Create)
+			webhookRoutes.GET("", webhookHandler.List)
+			webhookRoutes.GET("/:id", webhookHandler.Get)
+			webhookRoutes.PATCH("/:id", webhookHandler.Update)
+			webhookRoutes.DELETE("/:id", webhookHandler.Delete)
+			webhookRoutes.GET("/:id/deliveries", webhookHandler.ListDeliveries)
+		}
+		customerRoutes := apiV1.Group("/customers")
+		customerRoutes.Use(authMiddleware)
+		{
+			customerRoutes.POST("", customerHandler.Create)
+			customerRoutes.GET("", customerHandler.List)
+			customerRoutes.GET("/:id", customerHandler.Get)
+			customerRoutes.PATCH("/:id", customerHandler.Update)
+			customerRoutes.DELETE("/:id", customerHandler.Delete)
+			customerRoutes.GET("/:id/licenses", customerHandler.Licenses)
+			customerRoutes.GET("/:id/notes", noteHandler.ListForCustomer)
+		}
+		inboundRoutes := apiV1.Group("/inbound/email")
+		{
+			inboundRoutes.POST("/mailgun", noteHandler.Mailgun)
+			inboundRoutes.POST("/ses", noteHandler.SES)
+		}
+		protectedKeyRoutes := apiV1.Group("/protected-keys")
+		protectedKeyRoutes.Use(authMiddleware)
+		{
+			protectedKeyRoutes.GET("", approvalHandler.ListProtectedKeys)
+			protectedKeyRoutes.PUT("/:key", approvalHandler.PutProtectedKey)
+			protectedKeyRoutes.DELETE("/:key", approvalHandler.DeleteProtectedKey)
+		}
+		approvalRoutes := apiV1.Group("/approvals")
+		approvalRoutes.Use(authMiddleware)
+		{
+			approvalRoutes.GET("", approvalHandler.List)
+			approvalRoutes.GET("/:id", approvalHandler.Get)
+			approvalRoutes.POST("/:id/approve", approvalHandler.Approve)
+			approvalRoutes.POST("/:id/reject", approvalHandler.Reject)
+		}
+		extensionRoutes := apiV1.Group("/extension-requests")
+		extensionRoutes.Use(authMiddleware)
+		{
+			extensionRoutes.GET("", extensionHandler.List)
+		}
+		metaRoutes := apiV1.Group("/meta")
+		metaRoutes.Use(authMiddleware)
+		{
+			metaRoutes.GET("/enums", metaHandler.Enums)
+		}
+		licenseTemplateRoutes := apiV1.Group("/license-templates")
+		licenseTemplateRoutes.Use(authMiddleware)
+		{
+			licenseTemplateRoutes.POST("", licenseTemplateHandler.Create)
+			licenseTemplateRoutes.GET("", licenseTemplateHandler.List)
+			licenseTemplateRoutes.GET("/:id", licenseTemplateHandler.Get)
+			licenseTemplateRoutes.PATCH("/:id", licenseTemplateHandler.Update)
+			licenseTemplateRoutes.DELETE("/:id", licenseTemplateHandler.Delete)
+		}
+		customStatusRoutes := apiV1.Group("/license-statuses")
+		customStatusRoutes.Use(authMiddleware)
+		{
+			customStatusRoutes.GET("", customStatusHandler.List)
+			customStatusRoutes.POST("", customStatusHandler.Create)
+			customStatusRoutes.PATCH("/:name", customStatusHandler.Update)
+			customStatusRoutes.DELETE("/:name", customStatusHandler.Delete)
+		}
+		productRoutes := apiV1.Group("/products")
+		productRoutes.Use(authMiddleware)
+		{
+			productRoutes.POST("", productHandler.Create)
+			productRoutes.GET("", productHandler.List)
+			productRoutes.GET("/lifecycles", productHandler.ListLifecycles)
+			productRoutes.GET("/agent-policies", productHandler.ListAgentPolicies)
+			productRoutes.GET("/:name", productHandler.Get)
+			productRoutes.PATCH("/:name", productHandler.Update)
+			productRoutes.DELETE("/:name", productHandler.Delete)
+			productRoutes.GET("/:name/lifecycle", productHandler.GetLifecycle)
+			productRoutes.PUT("/:name/lifecycle", productHandler.SetLifecycle)
+			productRoutes.GET("/:name/agent-policy", productHandler.GetAgentPolicy)
+			productRoutes.PUT("/:name/agent-policy", productHandler.SetAgentPolicy)
+			productRoutes.DELETE("/:name/agent-policy", productHandler.DeleteAgentPolicy)
+			productRoutes.GET("/:name/cache-policy", productHandler.GetCachePolicy)
+			productRoutes.PUT("/:name/cache-policy", productHandler.SetCachePolicy)
+			productRoutes.DELETE("/:name/cache-policy", productHandler.DeleteCachePolicy)
+			productRoutes.POST("/:name/migration-campaigns", productHandler.StartMigrationCampaign)
+			productRoutes.POST("/:name/metadata-migrations", productHandler.MigrateMetadata)
+		}
+		signingKeyRoutes := apiV1.Group("/signing-keys")
+		signingKeyRoutes.Use(authMiddleware)
+		{
+			signingKeyRoutes.GET("", signingKeyHandler.List)
+			signingKeyRoutes.POST("/rotate", stepUpMiddleware, signingKeyHandler.Rotate)
+		}
+		templateRoutes := apiV1.Group("/templates")
+		templateRoutes.Use(authMiddleware)
+		{
+			templateRoutes.GET("", templateHandler.Catalog)
+		}
+		auditRoutes := apiV1.Group("/audit")
+		auditRoutes.Use(authMiddleware)
+		{
+			auditRoutes.GET("", auditHandler.List)
+			auditRoutes.GET("/:id/diff", auditHandler.Diff)
+		}
+		telemetryRoutes := apiV1.Group("/telemetry")
+		telemetryRoutes.Use(authMiddleware)
+		{
+			telemetryRoutes.GET("/preview", telemetryHandler.Preview)
+		}
+		reportRoutes := apiV1.Group("/reports")
+		reportRoutes.Use(authMiddleware)
+		{
+			reportRoutes.GET("/binding-failures", reportHandler.BindingFailures)
+		}
+		adminRoutes := apiV1.Group("/admin")
+		adminRoutes.Use(authMiddleware)
+		{
+			adminRoutes.GET("/expiration/preview", expirationHandler.Preview)
+			adminRoutes.POST("/analytics-exports", stepUpMiddleware, analyticsExportHandler.Run)
+			adminRoutes.POST("/lint", lintHandler.Lint)
+			adminRoutes.POST("/integrity-checks", stepUpMiddleware, integrityHandler.Start)
+			adminRoutes.GET("/integrity-checks", integrityHandler.List)
+			adminRoutes.GET("/integrity-checks/:id", integrityHandler.Get)
+			adminRoutes.GET("/usage-statements", apiUsageHandler.Statement)
+			adminRoutes.GET("/usage-statements/export", stepUpMiddleware, apiUsageHandler.Export)
+		}
+		if stepUpService != nil {
+			stepUpHandler := handler.NewStepUpHandler(stepUpService, appLogger)
+			authRoutes := apiV1.Group("/auth")
+			authRoutes.Use(authMiddleware)
+			{
+				authRoutes.POST("/step-up", stepUpHandler.StepUp)
+				authRoutes.POST("/step-up/totp", stepUpHandler.EnrollTOTP)
+			}
+		}
+		validationEventRoutes := apiV1.Group("/validation-events")
+		validationEventRoutes.Use(authMiddleware)
+		{
+			validationEventRoutes.GET("", validationEventHandler.List)
+		}
+		if chaos.Enabled {
+			chaosRoutes := apiV1.Group("")
+			chaosRoutes.Use(authMiddleware)
+			chaos.RegisterRoutes(chaosRoutes)
+		}
 	}
 
 	g, groupCtx := errgroup.WithContext(appCtx)
 
-	httpServer := &http.Server{
-		Addr:         ":" + cfg.Server.Port,
-		Handler:      router,
-		ReadTimeout:  cfg.Server.ReadTimeout,
-		WriteTimeout: cfg.Server.WriteTimeout,
-		IdleTimeout:  cfg.Server.IdleTimeout,
+	// The SIEM exporter, the meter and the SLO tracker outlive groupCtx and
+	// are stopped once the HTTP server has drained, so the last requests are
+	// still sent and counted.
+	siemCtx, stopSIEM := context.WithCancel(context.Background())
+	defer stopSIEM()
+	if siemExporter != nil {
+		g.Go(func() error {
+			return siemExporter.Run(siemCtx)
+		})
+	}
+	if meter != nil {
+		g.Go(func() error {
+			return meter.Run(siemCtx)
+		})
+	}
+	if sloTracker != nil {
+		g.Go(func() error {
+			return sloTracker.Run(siemCtx)
+		})
 	}
 
 	g.Go(func() error {
-		sugarLogger.Infof("HTTP server listening on port %s", cfg.Server.Port)
+		return keyring.Run(groupCtx)
+	})
+
+	g.Go(func() error {
+		return customStatusService.Run(groupCtx)
+	})
 
-		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+	if authzEnforcer != nil {
+		g.Go(func() error {
+			return authzEnforcer.Run(groupCtx)
+		})
+	}
+
+	rootHandler.Serve(router)
+	readiness.MarkReady()
+
+	g.Go(func() error {
+		if err := <-serverErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
 			sugarLogger.Errorf("HTTP server ListenAndServe error: %v", err)
 			return fmt.Errorf("http server failed: %w", err)
 		}
@@ -190,6 +1001,9 @@ func main() {
 
 	g.Go(func() error {
 		<-groupCtx.Done()
+		readiness.MarkDraining()
+		// Requests finishing below may still queue audit events and usage counts.
+		defer stopSIEM()
 		sugarLogger.Info("Shutting down HTTP server...")
 
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownPeriod)
@@ -199,11 +1013,37 @@ func main() {
 			return fmt.Errorf("http server shutdown error: %w", err)
 		}
 		sugarLogger.Info("HTTP server shutdown complete.")
+
+		if err := backgroundPool.Shutdown(shutdownCtx); err != nil {
+			sugarLogger.Warnf("Background pool did not drain before shutdown deadline: %v", err)
+		}
 		return nil
 	})
 
+	if cfg.Search.Enabled && cfg.Region.Replica() {
+		sugarLogger.Warn("Search indexing runs on the primary region only, SEARCH_ENABLED is ignored on replicas")
+	} else if cfg.Search.Enabled {
+		if len(cfg.Database.ShardURLs) > 0 {
+			sugarLogger.Warn("Search indexing only follows the primary database outbox; licenses on other shards are not indexed")
+		}
+		indexer := search.NewIndexer(&cfg.Search, outboxRepo, licenseRepo, search.NewClient(&cfg.Search), appLogger)
+		g.Go(func() error {
+			if err := indexer.Run(groupCtx); err != nil {
+				sugarLogger.Error("Search indexer failed", zap.Error(err))
+				return fmt.Errorf("search indexer error: %w", err)
+			}
+			return nil
+		})
+	}
+
+	for _, dispatcher := range lifecycleDispatchers {
+		g.Go(func() error {
+			return dispatcher.Run(groupCtx)
+		})
+	}
+
 	g.Go(func() error {
-		if err := worker.RunWorkers(groupCtx, cfg, licenseRepo, appLogger); err != nil {
+		if err := worker.RunWorkers(groupCtx, cfg, licenseRepo, appLogger, workerJobs...); err != nil {
 			sugarLogger.Error("Asynq worker failed", zap.Error(err))
 			return fmt.Errorf("asynq worker error: %w", err)
 		}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"github.com/makkenzo/license-service-api/internal/signing"
+)
+
+const dateFormat = "2006-01-02 15:04 MST"
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "an unknown date"
+	}
+	return t.Local().Format(dateFormat)
+}
+
+// diagnoseValidation explains a validation response, first line being the
+// verdict.
+func diagnoseValidation(r *dto.ValidateLicenseResponse, product string) []string {
+	var lines []string
+	switch r.Reason {
+	case service.ReasonValid, "":
+		if r.IsValid {
+			lines = append(lines, "VALID: the license key is accepted.")
+		} else {
+			lines = append(lines, "INVALID: the server rejected the license without giving a reason.")
+		}
+	case service.ReasonInGracePeriod:
+		lines = append(lines, fmt.Sprintf("VALID (grace period): the license expired on %s but keeps working until %s. Renew it before then.",
+			formatTime(r.ExpiresAt), formatTime(r.GraceExpiresAt)))
+	case service.ReasonNotFound:
+		lines = append(lines, "INVALID: no license with this key exists.",
+			"Check the key for typos and missing characters; keys are case-sensitive.")
+	case service.ReasonProductMismatch:
+		lines = append(lines, fmt.Sprintf("INVALID: the key belongs to a different product than %q.", product),
+			"Check that you are using the key issued for this product.")
+	case string(license.StatusPending):
+		lines = append(lines, "INVALID: the license has been issued but not activated by the vendor yet.")
+	case string(license.StatusInactive):
+		lines = append(lines, "INVALID: the license has been deactivated by the vendor.")
+	case string(license.StatusRevoked):
+		lines = append(lines, "INVALID: the license has been revoked and cannot be used anymore.")
+	case string(license.StatusSuspended):
+		lines = append(lines, "INVALID: the license is suspended.")
+		if r.StatusReason != "" {
+			lines = append(lines, "Reason given by the vendor: "+r.StatusReason)
+		}
+	case service.ReasonExpired:
+		lines = append(lines, fmt.Sprintf("INVALID: the license expired on %s.", formatTime(r.ExpiresAt)),
+			"Renew the license to continue using the product.")
+	case service.ReasonNotYetActive:
+		lines = append(lines, fmt.Sprintf("INVALID: the license only starts on %s.", formatTime(r.StartsAt)))
+	case service.ReasonProductEOL:
+		lines = append(lines, "INVALID: this product has reached its end of life and is no longer licensed.")
+	case service.ReasonAgentOutdated:
+		lines = append(lines, "INVALID: this version of the application is too old for the license server.",
+			"Update the application, or pass its version with -agent-version if it is already up to date.")
+	case service.ReasonDeviceIDRequired:
+		lines = append(lines, "INVALID: the license is bound to devices, so the check needs a device ID.",
+			"Run the check again with -device-id.")
+	case service.ReasonDeviceIDMismatch:
+		lines = append(lines, "INVALID: the license is bound to a different device.")
+	case service.ReasonDeviceNotActive:
+		lines = append(lines, "INVALID: this device is not activated for the license.",
+			"Activate the license on this device from the application first.")
+	case service.ReasonSeatLimit:
+		lines = append(lines, "INVALID: all seats of the license are in use.",
+			"Deactivate the license on a device you no longer use, or ask the vendor for more seats.")
+	case service.ReasonUserIDRequired, service.ReasonUserIDMismatch:
+		lines = append(lines, "INVALID: the license is assigned to a specific user; sign in as that user in the application.")
+	case service.ReasonUsageLimit:
+		lines = append(lines, fmt.Sprintf("INVALID: the monthly limit of %q has been reached.", r.UsageMetric),
+			"The limit resets at the start of next month, or the vendor can raise it.")
+	default:
+		lines = append(lines, fmt.Sprintf("INVALID: the server rejected the license with reason %q, which this tool does not know.", r.Reason),
+			"A newer version of this tool may explain it.")
+	}
+
+	if r.Status != nil {
+		lines = append(lines, "Status: "+string(*r.Status))
+	}
+	if r.ExpiresAt != nil && r.Reason != service.ReasonExpired && r.Reason != service.ReasonInGracePeriod {
+		lines = append(lines, "Expires: "+formatTime(r.ExpiresAt))
+	}
+	if r.FloatingSeats != nil {
+		lines = append(lines, fmt.Sprintf("Floating seats in use: %d of %d", r.FloatingSeats.InUse, r.FloatingSeats.Max))
+	}
+	for _, w := range r.Warnings {
+		lines = append(lines, "Warning: "+w)
+	}
+	return lines
+}
+
+func diagnoseHTTPError(status int, apiErr *dto.APIErrorResponse) string {
+	detail := ""
+	if apiErr.Message != "" {
+		detail = fmt.Sprintf(" (%s: %s)", apiErr.Code, apiErr.Message)
+	}
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return "ERROR: the server did not accept the API key. Check -api-key or $LICENSE_API_KEY" + detail + "."
+	case http.StatusBadRequest:
+		return "ERROR: the server rejected the request" + detail + "."
+	case http.StatusTooManyRequests:
+		return "ERROR: too many requests, try again in a minute" + detail + "."
+	case http.StatusNotFound:
+		return "ERROR: no license server API at this address. Check the -server URL" + detail + "."
+	default:
+		return fmt.Sprintf("ERROR: the license server answered with status %d%s. Try again later.", status, detail)
+	}
+}
+
+// diagnoseLicenseFile checks an offline license file the way agents do and
+// explains the result.
+func diagnoseLicenseFile(token string, jwks signing.JWKS, product string, now time.Time) ([]string, bool) {
+	header, payload, err := signing.Verify(token, jwks)
+	switch {
+	case errors.Is(err, signing.ErrMalformedToken):
+		return []string{"INVALID: this is not a license file (" + err.Error() + ").",
+			"Make sure the whole file was copied, without extra characters."}, false
+	case errors.Is(err, signing.ErrUnknownKey):
+		return []string{"INVALID: the file was signed with a key this server does not publish.",
+			"It was issued by a different license server, or its key has been removed."}, false
+	case err != nil:
+		return []string{"INVALID: the signature does not match; the file has been modified or is damaged (" + err.Error() + ")."}, false
+	}
+	if header.Typ != signing.LicenseFileType {
+		return []string{fmt.Sprintf("INVALID: this is a signed %q token, not a license file.", header.Typ)}, false
+	}
+
+	var claims signing.LicenseClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return []string{"INVALID: the license file contents are unreadable (" + err.Error() + ")."}, false
+	}
+
+	details := []string{
+		"Signed by: " + claims.Issuer + " (key " + header.Kid + ")",
+		"Issued: " + time.Unix(claims.IssuedAt, 0).Local().Format(dateFormat),
+		"Product: " + claims.Product,
+		"Type: " + claims.Type,
+	}
+	if claims.CustomerName != "" {
+		details = append(details, "Licensed to: "+claims.CustomerName)
+	}
+	details = append(details, fmt.Sprintf("Seats: %d", claims.MaxActivations))
+
+	verdict, valid := fileVerdict(&claims, product, now)
+	return append([]string{verdict}, details...), valid
+}
+
+func fileVerdict(claims *signing.LicenseClaims, product string, now time.Time) (string, bool) {
+	if product != "" && claims.Product != product {
+		return fmt.Sprintf("INVALID: the file is for product %q, not %q.", claims.Product, product), false
+	}
+	if claims.Status != string(license.StatusActive) {
+		return fmt.Sprintf("INVALID: the license was %s when the file was issued.", claims.Status), false
+	}
+	if claims.NotBefore != 0 && now.Unix() < claims.NotBefore {
+		start := time.Unix(claims.NotBefore, 0)
+		return fmt.Sprintf("INVALID: the license only starts on %s.", formatTime(&start)), false
+	}
+	if claims.ExpiresAt != 0 && now.Unix() >= claims.ExpiresAt {
+		expires := time.Unix(claims.ExpiresAt, 0)
+		graceEnd := expires.AddDate(0, 0, claims.GracePeriodDays)
+		if now.Before(graceEnd) {
+			return fmt.Sprintf("VALID (grace period): the license expired on %s but keeps working until %s. Renew it before then.",
+				formatTime(&expires), formatTime(&graceEnd)), true
+		}
+		return fmt.Sprintf("INVALID: the license expired on %s. Renew it and download a new license file.", formatTime(&expires)), false
+	}
+	if claims.ExpiresAt != 0 {
+		expires := time.Unix(claims.ExpiresAt, 0)
+		return fmt.Sprintf("VALID: the signature is genuine and the license is active until %s.", formatTime(&expires)), true
+	}
+	return "VALID: the signature is genuine and the license does not expire.", true
+}
+
+func diagnoseActivation(token string, jwks signing.JWKS, challenge signing.OfflineChallenge, deviceID string, now time.Time) ([]string, bool) {
+	claims, err := signing.VerifyOfflineActivation(token, jwks, challenge, deviceID)
+	switch {
+	case errors.Is(err, signing.ErrChallengeMismatch):
+		return []string{"INVALID: the response answers a different challenge or device.",
+			"Ask the vendor to answer the challenge issued for this device."}, false
+	case errors.Is(err, signing.ErrMalformedToken):
+		return []string{"INVALID: this is not an offline activation response (" + err.Error() + ").",
+			"Make sure the whole response was copied, without extra characters."}, false
+	case errors.Is(err, signing.ErrUnknownKey):
+		return []string{"INVALID: the response was signed with a key this server does not publish."}, false
+	case err != nil:
+		return []string{"INVALID: the signature does not match; the response has been modified or is damaged (" + err.Error() + ")."}, false
+	}
+
+	details := []string{
+		"Signed by: " + claims.Issuer,
+		"Issued: " + time.Unix(claims.IssuedAt, 0).Local().Format(dateFormat),
+		"Device: " + claims.DeviceID,
+		"Product: " + claims.Product,
+		"Type: " + claims.Type,
+		fmt.Sprintf("Seats: %d", claims.MaxActivations),
+	}
+	// Responses are only signed for active licenses; the dates are checked
+	// as for a license file.
+	verdict, valid := fileVerdict(&signing.LicenseClaims{
+		Status:          string(license.StatusActive),
+		ExpiresAt:       claims.ExpiresAt,
+		NotBefore:       claims.NotBefore,
+		GracePeriodDays: claims.GracePeriodDays,
+	}, "", now)
+	return append([]string{verdict}, details...), valid
+}
@@ -0,0 +1,239 @@
+// Command verify lets customers check a license themselves: "verify key"
+// asks the license server why a key is or is not accepted, and "verify file"
+// checks an offline license file without contacting the server for anything
+// but its public keys, as "verify activation" does for the response to an
+// offline activation challenge.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/signing"
+)
+
+// Exit codes: the license is usable, it is not, or it could not be checked.
+const (
+	exitValid   = 0
+	exitInvalid = 1
+	exitError   = 2
+)
+
+const usage = `Usage:
+  verify key  -server URL -api-key KEY -product NAME [-device-id ID] [-agent-version V] LICENSE_KEY
+  verify file (-server URL | -jwks URL_OR_PATH) [-product NAME] LICENSE_FILE
+  verify activation (-server URL | -jwks URL_OR_PATH) -challenge CODE -device-id ID RESPONSE_FILE
+
+"verify key" asks the license server to validate a key and explains the answer.
+"verify file" checks the signature and dates of an offline license file.
+"verify activation" checks that an offline activation response answers the challenge.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(exitError)
+	}
+
+	var code int
+	switch os.Args[1] {
+	case "key":
+		code = verifyKey(os.Args[2:])
+	case "file":
+		code = verifyFile(os.Args[2:])
+	case "activation":
+		code = verifyActivation(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q.\n\n%s", os.Args[1], usage)
+		code = exitError
+	}
+	os.Exit(code)
+}
+
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+func verifyKey(args []string) int {
+	fs := flag.NewFlagSet("key", flag.ExitOnError)
+	server := fs.String("server", "", "Base URL of the license server, e.g. https://licenses.example.com")
+	apiKey := fs.String("api-key", os.Getenv("LICENSE_API_KEY"), "API key of the product (default $LICENSE_API_KEY)")
+	product := fs.String("product", "", "Product name the key is for")
+	deviceID := fs.String("device-id", "", "Device ID, for licenses bound to devices")
+	agentVersion := fs.String("agent-version", "", "Version of the application, for products with a minimum version")
+	_ = fs.Parse(args)
+
+	if *server == "" || *apiKey == "" || *product == "" || fs.NArg() != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return exitError
+	}
+
+	req := dto.ValidateLicenseRequest{
+		LicenseKey:   strings.TrimSpace(fs.Arg(0)),
+		ProductName:  *product,
+		AgentVersion: *agentVersion,
+	}
+	if *deviceID != "" {
+		req.Metadata, _ = json.Marshal(map[string]string{"device_id": *deviceID})
+	}
+	body, _ := json.Marshal(req)
+
+	httpReq, err := http.NewRequest(http.MethodPost, strings.TrimRight(*server, "/")+"/api/v1/licenses/validate", bytes.NewReader(body))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid server URL: %v\n", err)
+		return exitError
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", *apiKey)
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not reach the license server: %v\n", err)
+		fmt.Fprintln(os.Stderr, "Check the -server URL and your network or proxy settings.")
+		return exitError
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not read the server response: %v\n", err)
+		return exitError
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr dto.APIErrorResponse
+		_ = json.Unmarshal(data, &apiErr)
+		fmt.Println(diagnoseHTTPError(resp.StatusCode, &apiErr))
+		return exitError
+	}
+
+	var result dto.ValidateLicenseResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		fmt.Fprintf(os.Stderr, "Unexpected response from the license server: %v\n", err)
+		return exitError
+	}
+	for _, line := range diagnoseValidation(&result, *product) {
+		fmt.Println(line)
+	}
+	if result.IsValid {
+		return exitValid
+	}
+	return exitInvalid
+}
+
+func verifyFile(args []string) int {
+	fs := flag.NewFlagSet("file", flag.ExitOnError)
+	server := fs.String("server", "", "Base URL of the license server; its JWKS is fetched from /.well-known/jwks.json")
+	jwksSource := fs.String("jwks", "", "URL or path of the JWKS, instead of -server (e.g. for machines without network access)")
+	product := fs.String("product", "", "Product the file must be for")
+	_ = fs.Parse(args)
+
+	if (*server == "") == (*jwksSource == "") || fs.NArg() != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return exitError
+	}
+	if *server != "" {
+		*jwksSource = strings.TrimRight(*server, "/") + "/.well-known/jwks.json"
+	}
+
+	token, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not read the license file: %v\n", err)
+		return exitError
+	}
+	jwks, err := loadJWKS(*jwksSource)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not load the public keys: %v\n", err)
+		return exitError
+	}
+
+	lines, valid := diagnoseLicenseFile(string(token), jwks, *product, time.Now())
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	if valid {
+		return exitValid
+	}
+	return exitInvalid
+}
+
+func verifyActivation(args []string) int {
+	fs := flag.NewFlagSet("activation", flag.ExitOnError)
+	server := fs.String("server", "", "Base URL of the license server; its JWKS is fetched from /.well-known/jwks.json")
+	jwksSource := fs.String("jwks", "", "URL or path of the JWKS, instead of -server (e.g. for machines without network access)")
+	challengeCode := fs.String("challenge", "", "Code of the challenge the response must answer")
+	deviceID := fs.String("device-id", "", "Device ID the challenge was issued for")
+	_ = fs.Parse(args)
+
+	if (*server == "") == (*jwksSource == "") || *challengeCode == "" || *deviceID == "" || fs.NArg() != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return exitError
+	}
+	if *server != "" {
+		*jwksSource = strings.TrimRight(*server, "/") + "/.well-known/jwks.json"
+	}
+
+	challenge, err := signing.ParseOfflineChallenge(strings.TrimSpace(*challengeCode))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -challenge: %v\n", err)
+		return exitError
+	}
+	token, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not read the activation response: %v\n", err)
+		return exitError
+	}
+	jwks, err := loadJWKS(*jwksSource)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not load the public keys: %v\n", err)
+		return exitError
+	}
+
+	lines, valid := diagnoseActivation(strings.TrimSpace(string(token)), jwks, challenge, *deviceID, time.Now())
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	if valid {
+		return exitValid
+	}
+	return exitInvalid
+}
+
+func loadJWKS(source string) (signing.JWKS, error) {
+	var data []byte
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		resp, err := httpClient.Get(source)
+		if err != nil {
+			return signing.JWKS{}, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return signing.JWKS{}, fmt.Errorf("%s answered %s", source, resp.Status)
+		}
+		if data, err = io.ReadAll(io.LimitReader(resp.Body, 1<<20)); err != nil {
+			return signing.JWKS{}, err
+		}
+	} else {
+		var err error
+		if data, err = os.ReadFile(source); err != nil {
+			return signing.JWKS{}, err
+		}
+	}
+
+	var jwks signing.JWKS
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return signing.JWKS{}, fmt.Errorf("not a JWKS: %w", err)
+	}
+	if len(jwks.Keys) == 0 {
+		return signing.JWKS{}, errors.New("the JWKS holds no keys; the server may not sign license files")
+	}
+	return jwks, nil
+}
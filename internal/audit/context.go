@@ -0,0 +1,24 @@
+// Package audit records who changed what on licenses and turns the recorded
+// snapshots into per-field diffs.
+package audit
+
+import (
+	"context"
+
+	"github.com/makkenzo/license-service-api/internal/domain/audit"
+)
+
+type actorKey struct{}
+
+// WithActor attaches the actor performing the request to ctx. Mutations made
+// with a context that carries no actor are attributed to the system.
+func WithActor(ctx context.Context, actor audit.Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFrom(ctx context.Context) audit.Actor {
+	if actor, ok := ctx.Value(actorKey{}).(audit.Actor); ok {
+		return actor
+	}
+	return audit.Actor{Type: audit.ActorSystem}
+}
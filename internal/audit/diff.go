@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+const (
+	ChangeAdded     = "added"
+	ChangeRemoved   = "removed"
+	ChangeModified  = "modified"
+	ChangeUnchanged = "unchanged"
+)
+
+type FieldDiff struct {
+	Field  string      `json:"field"`
+	Old    interface{} `json:"old"`
+	New    interface{} `json:"new"`
+	Change string      `json:"change"`
+}
+
+// Diff compares two snapshots field by field. Nested objects are flattened
+// into dotted paths ("metadata.limits.seats"); arrays are compared as a
+// whole. A field that is absent or null on one side counts as added or
+// removed. The result is sorted by field path.
+func Diff(before, after json.RawMessage) ([]FieldDiff, error) {
+	oldFields, err := flatten(before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode before snapshot: %w", err)
+	}
+	newFields, err := flatten(after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode after snapshot: %w", err)
+	}
+
+	paths := make(map[string]struct{}, len(oldFields)+len(newFields))
+	for p := range oldFields {
+		paths[p] = struct{}{}
+	}
+	for p := range newFields {
+		paths[p] = struct{}{}
+	}
+
+	diffs := make([]FieldDiff, 0, len(paths))
+	for p := range paths {
+		oldValue, newValue := oldFields[p], newFields[p]
+
+		change := ChangeModified
+		switch {
+		case oldValue == nil && newValue == nil, reflect.DeepEqual(oldValue, newValue):
+			change = ChangeUnchanged
+		case oldValue == nil:
+			change = ChangeAdded
+		case newValue == nil:
+			change = ChangeRemoved
+		}
+
+		diffs = append(diffs, FieldDiff{Field: p, Old: oldValue, New: newValue, Change: change})
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Field < diffs[j].Field })
+	return diffs, nil
+}
+
+func flatten(data json.RawMessage) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return fields, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var root map[string]interface{}
+	if err := dec.Decode(&root); err != nil {
+		return nil, err
+	}
+
+	var walk func(prefix string, obj map[string]interface{})
+	walk = func(prefix string, obj map[string]interface{}) {
+		for key, value := range obj {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+				walk(path, nested)
+				continue
+			}
+			fields[path] = value
+		}
+	}
+	walk("", root)
+	return fields, nil
+}
+
+type FieldComparison struct {
+	Field  string        `json:"field"`
+	Values []interface{} `json:"values"`
+	Same   bool          `json:"same"`
+}
+
+// Compare lines up several snapshots field by field, flattened like Diff.
+// Values holds one entry per snapshot, nil where the field is absent.
+func Compare(snapshots ...json.RawMessage) ([]FieldComparison, error) {
+	flat := make([]map[string]interface{}, len(snapshots))
+	paths := map[string]struct{}{}
+	for i, snap := range snapshots {
+		fields, err := flatten(snap)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode snapshot %d: %w", i, err)
+		}
+		for p := range fields {
+			paths[p] = struct{}{}
+		}
+		flat[i] = fields
+	}
+
+	comparisons := make([]FieldComparison, 0, len(paths))
+	for p := range paths {
+		c := FieldComparison{Field: p, Values: make([]interface{}, len(flat)), Same: true}
+		for i, fields := range flat {
+			c.Values[i] = fields[p]
+			if i > 0 && !reflect.DeepEqual(c.Values[0], c.Values[i]) {
+				c.Same = false
+			}
+		}
+		comparisons = append(comparisons, c)
+	}
+
+	sort.Slice(comparisons, func(i, j int) bool { return comparisons[i].Field < comparisons[j].Field })
+	return comparisons, nil
+}
@@ -0,0 +1,182 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/audit"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"go.uber.org/zap"
+)
+
+// LicenseRepository records an audit entry for every license creation, update,
+// status change and key rotation. Audit failures are logged and never fail the mutation.
+// Metadata-only updates from validation traffic are not audited.
+type LicenseRepository struct {
+	license.Repository
+	entries audit.Repository
+	logger  *zap.Logger
+}
+
+func NewLicenseRepository(repo license.Repository, entries audit.Repository, logger *zap.Logger) *LicenseRepository {
+	return &LicenseRepository{
+		Repository: repo,
+		entries:    entries,
+		logger:     logger.Named("AuditedLicenseRepository"),
+	}
+}
+
+var _ license.Repository = (*LicenseRepository)(nil)
+
+func (r *LicenseRepository) Create(ctx context.Context, lic *license.License) (uuid.UUID, error) {
+	id, err := r.Repository.Create(ctx, lic)
+	if err != nil {
+		return id, err
+	}
+
+	created := *lic
+	created.ID = id
+	r.record(ctx, id, audit.ActionCreate, nil, &created)
+	return id, nil
+}
+
+func (r *LicenseRepository) CreateBatch(ctx context.Context, licenses []*license.License) error {
+	err := r.Repository.CreateBatch(ctx, licenses)
+	for _, lic := range licenses {
+		if lic.ID == uuid.Nil {
+			continue
+		}
+		created := *lic
+		r.record(ctx, lic.ID, audit.ActionCreate, nil, &created)
+	}
+	return err
+}
+
+func (r *LicenseRepository) Update(ctx context.Context, lic *license.License) error {
+	before := r.before(ctx, lic.ID)
+	if err := r.Repository.Update(ctx, lic); err != nil {
+		return err
+	}
+	r.record(ctx, lic.ID, audit.ActionUpdate, before, lic)
+	return nil
+}
+
+func (r *LicenseRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status license.LicenseStatus) error {
+	before := r.before(ctx, id)
+	if err := r.Repository.UpdateStatus(ctx, id, status); err != nil {
+		return err
+	}
+
+	var after *license.License
+	if before != nil {
+		updated := *before
+		updated.Status = status
+		after = &updated
+	}
+	r.record(ctx, id, audit.ActionUpdateStatus, before, after)
+	return nil
+}
+
+func (r *LicenseRepository) RotateKey(ctx context.Context, id uuid.UUID, newKey func() (string, error), oldKeyValidUntil time.Time) (*license.License, error) {
+	before := r.before(ctx, id)
+	lic, err := r.Repository.RotateKey(ctx, id, newKey, oldKeyValidUntil)
+	if err != nil {
+		return nil, err
+	}
+	r.record(ctx, id, audit.ActionRotateKey, before, lic)
+	return lic, nil
+}
+
+func (r *LicenseRepository) before(ctx context.Context, id uuid.UUID) *license.License {
+	lic, err := r.Repository.FindByID(ctx, id)
+	if err != nil {
+		r.logger.Debug("Could not load license state before mutation", zap.String("license_id", id.String()), zap.Error(err))
+		return nil
+	}
+	return lic
+}
+
+func (r *LicenseRepository) record(ctx context.Context, id uuid.UUID, action string, before, after *license.License) {
+	entry := &audit.Entry{
+		EntityType: audit.EntityLicense,
+		EntityID:   id,
+		Action:     action,
+		Actor:      ActorFrom(ctx),
+		Before:     Snapshot(before),
+		After:      Snapshot(after),
+	}
+
+	// The mutation already happened; don't let a cancelled request drop its
+	// audit entry.
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := r.entries.Record(recordCtx, entry); err != nil {
+		r.logger.Error("Failed to record license audit entry",
+			zap.String("license_id", id.String()),
+			zap.String("action", action),
+			zap.Error(err),
+		)
+	}
+}
+
+// Snapshot flattens lic into the JSON object stored with audit entries.
+// Timestamps maintained by the database are left out so they don't show up as
+// changes in every diff.
+func Snapshot(lic *license.License) json.RawMessage {
+	if lic == nil {
+		return nil
+	}
+
+	snap := map[string]interface{}{
+		"license_key":    lic.LicenseKey,
+		"status":         lic.Status,
+		"type":           lic.Type,
+		"product_name":   lic.ProductName,
+		"customer_name":  nil,
+		"customer_email": nil,
+		"customer_id":    nil,
+		"issued_at":      nil,
+		"expires_at":     nil,
+		"starts_at":      nil,
+		"metadata":       nil,
+		"tags":           []string{},
+		"operator_notes": lic.OperatorNotes,
+		"floating":       lic.Floating,
+		"parent_id":      nil,
+	}
+	if lic.Tags != nil {
+		snap["tags"] = lic.Tags
+	}
+	if lic.CustomerName.Valid {
+		snap["customer_name"] = lic.CustomerName.String
+	}
+	if lic.CustomerEmail.Valid {
+		snap["customer_email"] = lic.CustomerEmail.String
+	}
+	if lic.CustomerID.Valid {
+		snap["customer_id"] = lic.CustomerID.UUID
+	}
+	if lic.ParentID.Valid {
+		snap["parent_id"] = lic.ParentID.UUID
+	}
+	if lic.IssuedAt.Valid {
+		snap["issued_at"] = lic.IssuedAt.Time.UTC()
+	}
+	if lic.ExpiresAt.Valid {
+		snap["expires_at"] = lic.ExpiresAt.Time.UTC()
+	}
+	if lic.StartsAt.Valid {
+		snap["starts_at"] = lic.StartsAt.Time.UTC()
+	}
+	if len(lic.Metadata) > 0 && json.Valid(lic.Metadata) {
+		snap["metadata"] = lic.Metadata
+	}
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return nil
+	}
+	return data
+}
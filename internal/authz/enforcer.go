@@ -0,0 +1,125 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"go.uber.org/zap"
+)
+
+// Enforcer holds the policy loaded from the configured file. Run reloads it
+// when the file changes; a file that no longer parses is logged and the
+// previous policy stays in force.
+type Enforcer struct {
+	cfg    *config.AuthzConfig
+	logger *zap.Logger
+	policy atomic.Pointer[Policy]
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+}
+
+// NewEnforcer loads cfg.PolicyFile. It returns nil without a policy file,
+// and a nil Enforcer allows every route.
+func NewEnforcer(cfg *config.AuthzConfig, logger *zap.Logger) (*Enforcer, error) {
+	if cfg.PolicyFile == "" {
+		return nil, nil
+	}
+	e := &Enforcer{cfg: cfg, logger: logger.Named("AuthzEnforcer")}
+	if err := e.Load(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Load reads and installs the policy file. A version of the file that fails
+// to load is not retried until the file changes again.
+func (e *Enforcer) Load() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	info, err := os.Stat(e.cfg.PolicyFile)
+	if err != nil {
+		return fmt.Errorf("reading authorization policy: %w", err)
+	}
+	e.modTime, e.size = info.ModTime(), info.Size()
+	data, err := os.ReadFile(e.cfg.PolicyFile)
+	if err != nil {
+		return fmt.Errorf("reading authorization policy: %w", err)
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	e.policy.Store(p)
+	e.logger.Info("Authorization policy loaded",
+		zap.String("file", e.cfg.PolicyFile),
+		zap.Int("rules", len(p.Rules)),
+		zap.String("default", p.Default),
+	)
+	return nil
+}
+
+func (e *Enforcer) changed() bool {
+	info, err := os.Stat(e.cfg.PolicyFile)
+	if err != nil {
+		e.logger.Error("Failed to stat authorization policy, keeping the loaded one", zap.String("file", e.cfg.PolicyFile), zap.Error(err))
+		return false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return !info.ModTime().Equal(e.modTime) || info.Size() != e.size
+}
+
+// Run checks the policy file every cfg.RefreshInterval and reloads it when
+// it has changed, until ctx is done.
+func (e *Enforcer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.cfg.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if !e.changed() {
+				continue
+			}
+			if err := e.Load(); err != nil {
+				e.logger.Error("Failed to reload authorization policy, keeping the previous one", zap.String("file", e.cfg.PolicyFile), zap.Error(err))
+			}
+		}
+	}
+}
+
+// Allows applies the current policy, see Policy.Allows. It is safe to call
+// on a nil Enforcer, which allows everything.
+func (e *Enforcer) Allows(method, route string, hasRole func(string) bool) bool {
+	if e == nil {
+		return true
+	}
+	return e.policy.Load().Allows(method, route, hasRole)
+}
+
+// AllowsScope applies the current policy, see Policy.AllowsScope. A nil
+// Enforcer lets every user create keys with any scope.
+func (e *Enforcer) AllowsScope(scope string, hasRole func(string) bool) bool {
+	if e == nil {
+		return true
+	}
+	return e.policy.Load().AllowsScope(scope, hasRole)
+}
+
+// Roles returns the roles the current policy names, see Policy.Roles. A nil
+// Enforcer names none.
+func (e *Enforcer) Roles() []string {
+	if e == nil {
+		return nil
+	}
+	return e.policy.Load().Roles()
+}
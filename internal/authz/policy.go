@@ -0,0 +1,208 @@
+// Package authz decides which routes a user may call from a declarative
+// policy file, so deployments can lock down or open endpoints per role
+// without changing the route groups. It applies to users authenticated with
+// an OIDC token; agent routes authenticated with API keys are not covered.
+//
+// The file is YAML:
+//
+//	default: allow            # or deny, for routes no rule matches
+//	rules:
+//	  - path: /api/v1/admin/*
+//	    roles: [license_admin]
+//	  - path: /api/v1/licenses/:id/revoke
+//	    methods: [POST]
+//	    roles: [license_admin, support]
+//	  - path: /api/v1/products/*
+//	    methods: [GET]
+//	    roles: ["*"]
+//	api_key_scopes:
+//	  activations:write: [license_admin]
+//
+// The first rule whose path and methods match decides. Paths are compared
+// with the route template segment by segment: a ":name" segment matches any
+// segment and a final "*" matches the rest of the path, including nothing.
+// A rule without methods matches all of them. Role "*" admits every
+// authenticated user and a rule without roles admits nobody.
+//
+// api_key_scopes names the roles that may create API keys with each scope.
+// Scopes it does not list follow the default.
+package authz
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/makkenzo/license-service-api/internal/domain/apikey"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrRouteForbidden = ierr.ErrForbidden.Derive("ROLE_REQUIRED", "the user's roles do not allow this route")
+
+// AnyRole in a rule's roles admits every authenticated user.
+const AnyRole = "*"
+
+const (
+	DefaultAllow = "allow"
+	DefaultDeny  = "deny"
+)
+
+type Rule struct {
+	Path    string   `yaml:"path"`
+	Methods []string `yaml:"methods"`
+	Roles   []string `yaml:"roles"`
+
+	segments []string
+}
+
+type Policy struct {
+	Default      string              `yaml:"default"`
+	Rules        []Rule              `yaml:"rules"`
+	APIKeyScopes map[string][]string `yaml:"api_key_scopes"`
+}
+
+var knownMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true,
+	http.MethodPatch: true, http.MethodDelete: true, http.MethodOptions: true,
+}
+
+// Parse reads a policy and checks it. Unknown fields are rejected, so a
+// misspelt key does not silently open a route.
+func Parse(data []byte) (*Policy, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var p Policy
+	if err := dec.Decode(&p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing authorization policy: %w", err)
+	}
+
+	switch p.Default {
+	case "":
+		p.Default = DefaultAllow
+	case DefaultAllow, DefaultDeny:
+	default:
+		return nil, fmt.Errorf("authorization policy default must be %s or %s, got %q", DefaultAllow, DefaultDeny, p.Default)
+	}
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if !strings.HasPrefix(r.Path, "/") {
+			return nil, fmt.Errorf("authorization rule %d: path %q must start with /", i+1, r.Path)
+		}
+		r.segments = strings.Split(strings.Trim(r.Path, "/"), "/")
+		for j, seg := range r.segments {
+			if seg == "*" && j != len(r.segments)-1 {
+				return nil, fmt.Errorf("authorization rule %d: * may only end the path %q", i+1, r.Path)
+			}
+		}
+		for j, m := range r.Methods {
+			r.Methods[j] = strings.ToUpper(m)
+			if !knownMethods[r.Methods[j]] {
+				return nil, fmt.Errorf("authorization rule %d: unknown method %q", i+1, m)
+			}
+		}
+		for _, role := range r.Roles {
+			if role == "" {
+				return nil, fmt.Errorf("authorization rule %d: roles must not be empty strings", i+1)
+			}
+		}
+	}
+	for scope, roles := range p.APIKeyScopes {
+		if !apikey.ValidScope(scope) {
+			return nil, fmt.Errorf("authorization policy: unknown api key scope %q", scope)
+		}
+		for _, role := range roles {
+			if role == "" {
+				return nil, fmt.Errorf("authorization policy: roles of api key scope %s must not be empty strings", scope)
+			}
+		}
+	}
+	return &p, nil
+}
+
+// Allows reports whether a user with the roles hasRole confirms may call
+// the route template route, e.g. "/api/v1/licenses/:id", with method.
+func (p *Policy) Allows(method, route string, hasRole func(string) bool) bool {
+	segments := strings.Split(strings.Trim(route, "/"), "/")
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if !r.matches(method, segments) {
+			continue
+		}
+		for _, role := range r.Roles {
+			if role == AnyRole || hasRole(role) {
+				return true
+			}
+		}
+		return false
+	}
+	return p.Default == DefaultAllow
+}
+
+// AllowsScope reports whether a user with the roles hasRole confirms may
+// create API keys with scope.
+func (p *Policy) AllowsScope(scope string, hasRole func(string) bool) bool {
+	roles, ok := p.APIKeyScopes[scope]
+	if !ok {
+		return p.Default == DefaultAllow
+	}
+	for _, role := range roles {
+		if role == AnyRole || hasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// Roles returns the roles the rules and then the api key scopes name,
+// without AnyRole, in the order they first appear.
+func (p *Policy) Roles() []string {
+	seen := map[string]bool{AnyRole: true}
+	var roles []string
+	add := func(names []string) {
+		for _, role := range names {
+			if !seen[role] {
+				seen[role] = true
+				roles = append(roles, role)
+			}
+		}
+	}
+	for _, r := range p.Rules {
+		add(r.Roles)
+	}
+	for _, scope := range apikey.AllScopes {
+		add(p.APIKeyScopes[scope])
+	}
+	return roles
+}
+
+func (r *Rule) matches(method string, segments []string) bool {
+	if len(r.Methods) > 0 {
+		found := false
+		for _, m := range r.Methods {
+			if m == method {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	for i, seg := range r.segments {
+		if seg == "*" && i == len(r.segments)-1 {
+			return true
+		}
+		if i >= len(segments) {
+			return false
+		}
+		if !strings.HasPrefix(seg, ":") && seg != segments[i] {
+			return false
+		}
+	}
+	return len(segments) == len(r.segments)
+}
@@ -0,0 +1,128 @@
+// Package background runs fire-and-forget work (metadata writes, last-used
+// timestamps, counters) on a fixed number of goroutines with a bounded
+// queue, so load spikes drop background work instead of piling up database
+// connections.
+package background
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/safego"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var (
+	droppedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "background_pool_dropped_total",
+		Help: "Background tasks dropped because the queue was full or the pool was stopped.",
+	}, []string{"task"})
+	processedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "background_pool_processed_total",
+		Help: "Background tasks run to completion.",
+	}, []string{"task"})
+	queueLength = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "background_pool_queue_length",
+		Help: "Background tasks waiting for a worker.",
+	})
+)
+
+type Task func(ctx context.Context)
+
+type job struct {
+	name string
+	fn   Task
+}
+
+type Pool struct {
+	queue   chan job
+	timeout time.Duration
+	logger  *zap.Logger
+
+	mu      sync.RWMutex
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+func NewPool(cfg *config.BackgroundConfig, logger *zap.Logger) *Pool {
+	p := &Pool{
+		queue:   make(chan job, cfg.QueueSize),
+		timeout: cfg.TaskTimeout,
+		logger:  logger.Named("BackgroundPool"),
+	}
+	for i := 0; i < cfg.Workers; i++ {
+		p.wg.Add(1)
+		go p.run()
+	}
+	p.logger.Info("Background pool started", zap.Int("workers", cfg.Workers), zap.Int("queue_size", cfg.QueueSize))
+	return p
+}
+
+// Submit queues fn without blocking. It reports false and counts a drop when
+// the queue is full or the pool has been shut down.
+func (p *Pool) Submit(name string, fn Task) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.stopped {
+		droppedTotal.WithLabelValues(name).Inc()
+		return false
+	}
+
+	select {
+	case p.queue <- job{name: name, fn: fn}:
+		queueLength.Inc()
+		return true
+	default:
+		droppedTotal.WithLabelValues(name).Inc()
+		p.logger.Warn("Background queue full, dropping task", zap.String("task", name))
+		return false
+	}
+}
+
+func (p *Pool) run() {
+	defer p.wg.Done()
+	for j := range p.queue {
+		queueLength.Dec()
+		p.execute(j)
+	}
+}
+
+func (p *Pool) execute(j job) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	if safego.Run(p.logger, j.name, func() { j.fn(ctx) }) {
+		processedTotal.WithLabelValues(j.name).Inc()
+	}
+}
+
+// Shutdown stops accepting tasks and waits for queued ones to finish or for
+// ctx to expire, whichever comes first.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.stopped {
+		p.stopped = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	safego.Go(p.logger, "background_pool_drain", func() {
+		p.wg.Wait()
+		close(done)
+	})
+
+	select {
+	case <-done:
+		p.logger.Info("Background pool drained")
+		return nil
+	case <-ctx.Done():
+		p.logger.Warn("Background pool shutdown timed out", zap.Int("pending", len(p.queue)))
+		return ctx.Err()
+	}
+}
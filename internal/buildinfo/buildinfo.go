@@ -0,0 +1,26 @@
+// Package buildinfo holds values stamped into the binary at build time:
+//
+//	go build -ldflags "-X github.com/makkenzo/license-service-api/internal/buildinfo.Version=1.4.0"
+package buildinfo
+
+import "runtime/debug"
+
+var (
+	// Version is the release version. When not stamped it falls back to the
+	// module version recorded by the Go toolchain, or "dev".
+	Version = ""
+
+	// TelemetryEndpoint is the vendor's default usage ping endpoint. The
+	// telemetry.endpoint setting overrides it.
+	TelemetryEndpoint = ""
+)
+
+func ServiceVersion() string {
+	if Version != "" {
+		return Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
@@ -0,0 +1,17 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrMiss = errors.New("cache miss")
+
+// Cache is a byte-oriented key/value cache with per-entry TTL. Get returns
+// ErrMiss when the key is absent or expired.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
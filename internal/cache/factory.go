@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// NewFromConfig builds the cache stack listed in cfg.Layers, fastest first.
+func NewFromConfig(cfg *config.CacheConfig, client *redis.Client) (Cache, error) {
+	layers := make([]Cache, 0, len(cfg.Layers))
+	for _, name := range cfg.Layers {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "memory":
+			memCache, err := NewMemoryCache(cfg.Memory.MaxCostBytes, cfg.Memory.MaxTTL)
+			if err != nil {
+				return nil, err
+			}
+			layers = append(layers, memCache)
+		case "redis":
+			layers = append(layers, NewRedisCache(client))
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown cache layer %q", name)
+		}
+	}
+
+	if len(layers) == 0 {
+		return nil, fmt.Errorf("at least one cache layer must be configured")
+	}
+	if len(layers) == 1 {
+		return layers[0], nil
+	}
+	return NewLayered(cfg.Memory.MaxTTL, layers...), nil
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Layered stacks caches from fastest to slowest (e.g. memory then Redis).
+// Reads fall through the layers and backfill the faster ones on a hit; writes
+// and deletes go to every layer.
+type Layered struct {
+	layers []Cache
+	ttl    time.Duration
+}
+
+func NewLayered(backfillTTL time.Duration, layers ...Cache) *Layered {
+	return &Layered{layers: layers, ttl: backfillTTL}
+}
+
+var _ Cache = (*Layered)(nil)
+
+func (c *Layered) Get(ctx context.Context, key string) ([]byte, error) {
+	var firstErr error
+	for i, layer := range c.layers {
+		value, err := layer.Get(ctx, key)
+		if err != nil {
+			if !errors.Is(err, ErrMiss) && firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		for _, upper := range c.layers[:i] {
+			_ = upper.Set(ctx, key, value, c.ttl)
+		}
+		return value, nil
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return nil, ErrMiss
+}
+
+func (c *Layered) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	var errs []error
+	for _, layer := range c.layers {
+		if err := layer.Set(ctx, key, value, ttl); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Layered) Delete(ctx context.Context, keys ...string) error {
+	var errs []error
+	for _, layer := range c.layers {
+		if err := layer.Delete(ctx, keys...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
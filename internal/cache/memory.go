@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dgraph-io/ristretto"
+)
+
+// MemoryCache is a process-local cache backed by ristretto. Entries are not
+// shared between instances, so maxTTL bounds how long an entry invalidated
+// elsewhere can stay visible here.
+type MemoryCache struct {
+	store  *ristretto.Cache
+	maxTTL time.Duration
+}
+
+func NewMemoryCache(maxCostBytes int64, maxTTL time.Duration) (*MemoryCache, error) {
+	store, err := ristretto.NewCache(&ristretto.Config{
+		NumCounters: maxCostBytes / 100,
+		MaxCost:     maxCostBytes,
+		BufferItems: 64,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create in-memory cache: %w", err)
+	}
+	return &MemoryCache{store: store, maxTTL: maxTTL}, nil
+}
+
+var _ Cache = (*MemoryCache)(nil)
+
+func (c *MemoryCache) Get(_ context.Context, key string) ([]byte, error) {
+	value, ok := c.store.Get(key)
+	if !ok {
+		return nil, ErrMiss
+	}
+	return value.([]byte), nil
+}
+
+func (c *MemoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	if c.maxTTL > 0 && (ttl <= 0 || ttl > c.maxTTL) {
+		ttl = c.maxTTL
+	}
+	c.store.SetWithTTL(key, value, int64(len(value)), ttl)
+	c.store.Wait()
+	return nil
+}
+
+func (c *MemoryCache) Delete(_ context.Context, keys ...string) error {
+	for _, key := range keys {
+		c.store.Del(key)
+	}
+	return nil
+}
+
+func (c *MemoryCache) Close() {
+	c.store.Close()
+}
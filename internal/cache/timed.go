@@ -0,0 +1,33 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/servertiming"
+)
+
+type timedCache struct {
+	cache Cache
+}
+
+// WithServerTiming reports the time spent in c as the cache metric of the
+// Server-Timing header.
+func WithServerTiming(c Cache) Cache {
+	return &timedCache{cache: c}
+}
+
+func (t *timedCache) Get(ctx context.Context, key string) ([]byte, error) {
+	defer servertiming.Track(ctx, servertiming.MetricCache)()
+	return t.cache.Get(ctx, key)
+}
+
+func (t *timedCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	defer servertiming.Track(ctx, servertiming.MetricCache)()
+	return t.cache.Set(ctx, key, value, ttl)
+}
+
+func (t *timedCache) Delete(ctx context.Context, keys ...string) error {
+	defer servertiming.Track(ctx, servertiming.MetricCache)()
+	return t.cache.Delete(ctx, keys...)
+}
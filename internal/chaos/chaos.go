@@ -0,0 +1,212 @@
+//go:build chaos
+
+// Package chaos injects latency and errors into the repository layer so
+// client SDK retry logic can be exercised against a real server. It is only
+// compiled with -tags chaos; release builds get the no-op in chaos_off.go.
+package chaos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+const Enabled = true
+
+var ErrInjected = errors.New("chaos: injected repository failure")
+
+// Settings describe the faults applied to repository calls. An empty
+// Methods list applies them to every method.
+type Settings struct {
+	LatencyMS int      `json:"latency_ms" binding:"gte=0"`
+	JitterMS  int      `json:"jitter_ms" binding:"gte=0"`
+	ErrorRate float64  `json:"error_rate" binding:"gte=0,lte=1"`
+	ErrorKind string   `json:"error_kind" binding:"omitempty,oneof=internal conflict timeout"`
+	Methods   []string `json:"methods"`
+}
+
+type injector struct {
+	mu       sync.RWMutex
+	settings Settings
+}
+
+var global = &injector{}
+
+func (i *injector) get() Settings {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.settings
+}
+
+func (i *injector) set(s Settings) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.settings = s
+}
+
+func (i *injector) apply(ctx context.Context, method string) error {
+	s := i.get()
+	if len(s.Methods) > 0 {
+		matched := false
+		for _, m := range s.Methods {
+			if m == method {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil
+		}
+	}
+
+	delay := time.Duration(s.LatencyMS) * time.Millisecond
+	if s.JitterMS > 0 {
+		delay += time.Duration(rand.Int64N(int64(s.JitterMS))) * time.Millisecond
+	}
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if s.ErrorRate <= 0 || rand.Float64() >= s.ErrorRate {
+		return nil
+	}
+	switch s.ErrorKind {
+	case "conflict":
+		return fmt.Errorf("%w: %w", ierr.ErrConflict, ErrInjected)
+	case "timeout":
+		return fmt.Errorf("%w: %w", context.DeadlineExceeded, ErrInjected)
+	default:
+		return ErrInjected
+	}
+}
+
+type licenseRepository struct {
+	license.Repository
+	inj *injector
+}
+
+// WrapLicenseRepository returns repo with fault injection in front of every
+// call. Faults are off until configured through the chaos endpoints.
+func WrapLicenseRepository(repo license.Repository, logger *zap.Logger) license.Repository {
+	logger.Named("Chaos").Warn("Chaos fault injection is compiled in; never run this build in production")
+	return &licenseRepository{Repository: repo, inj: global}
+}
+
+func (r *licenseRepository) Create(ctx context.Context, lic *license.License) (uuid.UUID, error) {
+	if err := r.inj.apply(ctx, "Create"); err != nil {
+		return uuid.Nil, err
+	}
+	return r.Repository.Create(ctx, lic)
+}
+
+func (r *licenseRepository) CreateBatch(ctx context.Context, licenses []*license.License) error {
+	if err := r.inj.apply(ctx, "CreateBatch"); err != nil {
+		return err
+	}
+	return r.Repository.CreateBatch(ctx, licenses)
+}
+
+func (r *licenseRepository) FindByID(ctx context.Context, id uuid.UUID) (*license.License, error) {
+	if err := r.inj.apply(ctx, "FindByID"); err != nil {
+		return nil, err
+	}
+	return r.Repository.FindByID(ctx, id)
+}
+
+func (r *licenseRepository) FindByKey(ctx context.Context, key string) (*license.License, error) {
+	if err := r.inj.apply(ctx, "FindByKey"); err != nil {
+		return nil, err
+	}
+	return r.Repository.FindByKey(ctx, key)
+}
+
+func (r *licenseRepository) List(ctx context.Context, params license.ListParams) ([]*license.License, int64, error) {
+	if err := r.inj.apply(ctx, "List"); err != nil {
+		return nil, 0, err
+	}
+	return r.Repository.List(ctx, params)
+}
+
+func (r *licenseRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status license.LicenseStatus) error {
+	if err := r.inj.apply(ctx, "UpdateStatus"); err != nil {
+		return err
+	}
+	return r.Repository.UpdateStatus(ctx, id, status)
+}
+
+func (r *licenseRepository) Update(ctx context.Context, lic *license.License) error {
+	if err := r.inj.apply(ctx, "Update"); err != nil {
+		return err
+	}
+	return r.Repository.Update(ctx, lic)
+}
+
+func (r *licenseRepository) GetDashboardSummary(ctx context.Context, expiringPeriodDays int) (*license.DashboardSummaryData, error) {
+	if err := r.inj.apply(ctx, "GetDashboardSummary"); err != nil {
+		return nil, err
+	}
+	return r.Repository.GetDashboardSummary(ctx, expiringPeriodDays)
+}
+
+func (r *licenseRepository) UpdateMetadata(ctx context.Context, id uuid.UUID, metadata json.RawMessage) error {
+	if err := r.inj.apply(ctx, "UpdateMetadata"); err != nil {
+		return err
+	}
+	return r.Repository.UpdateMetadata(ctx, id, metadata)
+}
+
+func (r *licenseRepository) ListRecentlyValidated(ctx context.Context, limit int) ([]*license.License, error) {
+	if err := r.inj.apply(ctx, "ListRecentlyValidated"); err != nil {
+		return nil, err
+	}
+	return r.Repository.ListRecentlyValidated(ctx, limit)
+}
+
+func (r *licenseRepository) Aggregate(ctx context.Context, params license.AggregateParams) ([]license.AggregateRow, error) {
+	if err := r.inj.apply(ctx, "Aggregate"); err != nil {
+		return nil, err
+	}
+	return r.Repository.Aggregate(ctx, params)
+}
+
+func (r *licenseRepository) RotateKey(ctx context.Context, id uuid.UUID, newKey func() (string, error), oldKeyValidUntil time.Time) (*license.License, error) {
+	if err := r.inj.apply(ctx, "RotateKey"); err != nil {
+		return nil, err
+	}
+	return r.Repository.RotateKey(ctx, id, newKey, oldKeyValidUntil)
+}
+
+// RegisterRoutes exposes GET/PUT/DELETE /chaos/repository on group.
+func RegisterRoutes(group *gin.RouterGroup) {
+	group.GET("/chaos/repository", func(c *gin.Context) {
+		c.JSON(http.StatusOK, global.get())
+	})
+	group.PUT("/chaos/repository", func(c *gin.Context) {
+		var s Settings
+		if err := c.ShouldBindJSON(&s); err != nil {
+			_ = c.Error(err)
+			return
+		}
+		global.set(s)
+		c.JSON(http.StatusOK, s)
+	})
+	group.DELETE("/chaos/repository", func(c *gin.Context) {
+		global.set(Settings{})
+		c.Status(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,17 @@
+//go:build !chaos
+
+package chaos
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"go.uber.org/zap"
+)
+
+const Enabled = false
+
+func WrapLicenseRepository(repo license.Repository, _ *zap.Logger) license.Repository {
+	return repo
+}
+
+func RegisterRoutes(_ *gin.RouterGroup) {}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log"
+	"os"
 	"strings"
 	"time"
 
@@ -11,11 +12,46 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Log      LogConfig
-	OIDC     OIDCConfig
+	Server            ServerConfig
+	Startup           StartupConfig
+	Database          DatabaseConfig
+	Redis             RedisConfig
+	Log               LogConfig
+	OIDC              OIDCConfig
+	Cache             CacheConfig
+	Background        BackgroundConfig
+	Search            SearchConfig
+	StatusGuard       StatusGuardConfig
+	Notify            NotifyConfig
+	Renewal           RenewalConfig
+	Templates         TemplatesConfig
+	Telemetry         TelemetryConfig
+	LastSeen          LastSeenConfig
+	ValidationEvents  ValidationEventsConfig
+	ChangeFeed        ChangeFeedConfig
+	Floating          FloatingConfig
+	Heartbeat         HeartbeatConfig
+	KeyRotation       KeyRotationConfig
+	CustomStatuses    CustomStatusesConfig
+	LifecycleHooks    LifecycleHooksConfig
+	Enrichment        EnrichmentConfig
+	AnalyticsExport   AnalyticsExportConfig
+	SIEM              SIEMConfig
+	Crypto            CryptoConfig
+	Signing           SigningConfig
+	Region            RegionConfig
+	Query             QueryConfig
+	Approval          ApprovalConfig
+	Authz             AuthzConfig
+	StepUp            StepUpConfig
+	ExpiryNotify      ExpiryNotifyConfig
+	Metering          MeteringConfig
+	SLO               SLOConfig
+	Archive           ArchiveConfig
+	OfflineActivation OfflineActivationConfig
+	Webhooks          WebhooksConfig
+	Campaigns         CampaignsConfig
+	Deployment        DeploymentConfig
 }
 
 type ServerConfig struct {
@@ -24,13 +60,33 @@ type ServerConfig struct {
 	WriteTimeout   time.Duration `mapstructure:"writeTimeout"`
 	IdleTimeout    time.Duration `mapstructure:"idleTimeout"`
 	ShutdownPeriod time.Duration `mapstructure:"shutdownPeriod"`
+	// ServerTiming adds a Server-Timing header with database, cache and
+	// policy check timings to every response. Meant for debugging.
+	ServerTiming bool `mapstructure:"serverTiming"`
+}
+
+// StartupConfig controls how long startup keeps retrying each dependency
+// (PostgreSQL, Redis, the OIDC issuer) before giving up. Attempts are spaced
+// with exponential backoff from InitialBackoff to MaxBackoff; a zero Timeout
+// tries each dependency once.
+type StartupConfig struct {
+	Timeout        time.Duration `mapstructure:"timeout"`
+	InitialBackoff time.Duration `mapstructure:"initialBackoff"`
+	MaxBackoff     time.Duration `mapstructure:"maxBackoff"`
 }
 
 type DatabaseConfig struct {
 	URL             string        `mapstructure:"url"`
+	ShardURLs       []string      `mapstructure:"shardUrls"`
 	MaxOpenConns    int           `mapstructure:"maxOpenConns"`
 	MaxIdleConns    int           `mapstructure:"maxIdleConns"`
 	ConnMaxLifetime time.Duration `mapstructure:"connMaxLifetime"`
+	// IDStrategy picks how primary keys of new records are generated:
+	// "uuid" (random) or "ulid" (time-sortable). See package idgen.
+	IDStrategy string `mapstructure:"idStrategy"`
+	// SlowQueryThreshold is how long a repository call may take before it
+	// is logged as slow.
+	SlowQueryThreshold time.Duration `mapstructure:"slowQueryThreshold"`
 }
 
 type RedisConfig struct {
@@ -41,6 +97,8 @@ type RedisConfig struct {
 
 type LogConfig struct {
 	Level string `mapstructure:"level"`
+	// Masking is off, partial or strict; see logger.NewZapLogger.
+	Masking string `mapstructure:"masking"`
 }
 
 type JWTConfig struct {
@@ -48,6 +106,394 @@ type JWTConfig struct {
 	TokenTTL  time.Duration `mapstructure:"tokenTTL"`
 }
 
+type CacheConfig struct {
+	Layers       []string          `mapstructure:"layers"`
+	LicenseTTL   time.Duration     `mapstructure:"licenseTTL"`
+	APIKeyTTL    time.Duration     `mapstructure:"apiKeyTTL"`
+	ProductTTL   time.Duration     `mapstructure:"productTTL"`
+	AggregateTTL time.Duration     `mapstructure:"aggregateTTL"`
+	Memory       MemoryCacheConfig `mapstructure:"memory"`
+	Prewarm      PrewarmConfig     `mapstructure:"prewarm"`
+}
+
+type MemoryCacheConfig struct {
+	MaxCostBytes int64         `mapstructure:"maxCostBytes"`
+	MaxTTL       time.Duration `mapstructure:"maxTTL"`
+}
+
+type PrewarmConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	TopN     int           `mapstructure:"topN"`
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+type BackgroundConfig struct {
+	Workers     int           `mapstructure:"workers"`
+	QueueSize   int           `mapstructure:"queueSize"`
+	TaskTimeout time.Duration `mapstructure:"taskTimeout"`
+}
+
+type SearchConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	URL          string        `mapstructure:"url"`
+	Username     string        `mapstructure:"username"`
+	Password     string        `mapstructure:"password"`
+	IndexPrefix  string        `mapstructure:"indexPrefix"`
+	BatchSize    int           `mapstructure:"batchSize"`
+	PollInterval time.Duration `mapstructure:"pollInterval"`
+}
+
+// LastSeenConfig controls the per-agent record used to flag entitlement
+// changes in validation responses.
+type LastSeenConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Debounce time.Duration `mapstructure:"debounce"`
+	TTL      time.Duration `mapstructure:"ttl"`
+}
+
+// ValidationEventsConfig controls the validation_events table. Failed
+// validations are always stored; SuccessSampleRate N keeps one in N
+// successful ones. ProductSampleRates overrides the rate per product with
+// "name=N" entries.
+type ValidationEventsConfig struct {
+	Enabled            bool     `mapstructure:"enabled"`
+	SuccessSampleRate  int      `mapstructure:"successSampleRate"`
+	ProductSampleRates []string `mapstructure:"productSampleRates"`
+}
+
+// ChangeFeedConfig controls the SubscribeLicenseEvents stream. Each
+// subscription polls the outbox every PollInterval; at most
+// MaxSubscriptions streams are served at once.
+type ChangeFeedConfig struct {
+	PollInterval     time.Duration `mapstructure:"pollInterval"`
+	MaxSubscriptions int           `mapstructure:"maxSubscriptions"`
+}
+
+// FloatingConfig bounds the seat leases of floating licenses. Clients may
+// ask for any lease up to MaxLeaseTTL and get LeaseTTL otherwise.
+type FloatingConfig struct {
+	LeaseTTL    time.Duration `mapstructure:"leaseTTL"`
+	MaxLeaseTTL time.Duration `mapstructure:"maxLeaseTTL"`
+}
+
+// HeartbeatConfig controls when activations whose agent stopped sending
+// heartbeats are stale. With AutoReclaim, an activation that finds every
+// seat taken first frees the stale ones of the license.
+type HeartbeatConfig struct {
+	StaleAfter  time.Duration `mapstructure:"staleAfter"`
+	AutoReclaim bool          `mapstructure:"autoReclaim"`
+}
+
+// KeyRotationConfig sets how long the previous key of a rotated license
+// keeps working. Operators may ask for any overlap up to MaxOverlap and get
+// Overlap otherwise.
+type KeyRotationConfig struct {
+	Overlap    time.Duration `mapstructure:"overlap"`
+	MaxOverlap time.Duration `mapstructure:"maxOverlap"`
+}
+
+// CustomStatusesConfig sets how often each instance reloads the custom
+// license statuses, so that ones created on another instance are picked up.
+type CustomStatusesConfig struct {
+	RefreshInterval time.Duration `mapstructure:"refreshInterval"`
+}
+
+// LifecycleHooksConfig selects the hooks run when a license is created,
+// activated, expires or is revoked. WebhookURL, when set, receives the
+// events listed in WebhookEvents as JSON POSTs signed with WebhookSecret;
+// customers are e-mailed about the events listed in EmailEvents.
+type LifecycleHooksConfig struct {
+	WebhookURL     string        `mapstructure:"webhookUrl"`
+	WebhookSecret  string        `mapstructure:"webhookSecret"`
+	WebhookEvents  []string      `mapstructure:"webhookEvents"`
+	WebhookTimeout time.Duration `mapstructure:"webhookTimeout"`
+	EmailEvents    []string      `mapstructure:"emailEvents"`
+	// The hooks are run from the license outbox, polled every PollInterval
+	// for up to BatchSize changes.
+	PollInterval time.Duration `mapstructure:"pollInterval"`
+	BatchSize    int           `mapstructure:"batchSize"`
+}
+
+// EnrichmentConfig selects where validation events get the network context
+// of the request from. The *Header settings name headers an edge proxy sets,
+// e.g. CF-IPCountry; they are trusted as they are. IP2ASNFile is an
+// iptoasn.com TSV table used to look up the ASN and country of the client
+// IP. Headers take precedence over the table.
+type EnrichmentConfig struct {
+	CountryHeader        string `mapstructure:"countryHeader"`
+	ASNHeader            string `mapstructure:"asnHeader"`
+	TLSFingerprintHeader string `mapstructure:"tlsFingerprintHeader"`
+	IP2ASNFile           string `mapstructure:"ip2asnFile"`
+}
+
+// AnalyticsExportConfig controls the daily export of pseudonymized license
+// activity to S3 for data teams. PseudonymSecret keys the HMAC that turns
+// license IDs into pseudonyms; changing it gives every license a new one.
+// Format is "parquet" or "csv", Schedule a cron spec for the worker.
+type AnalyticsExportConfig struct {
+	Enabled         bool     `mapstructure:"enabled"`
+	Schedule        string   `mapstructure:"schedule"`
+	Format          string   `mapstructure:"format"`
+	PseudonymSecret string   `mapstructure:"pseudonymSecret"`
+	Prefix          string   `mapstructure:"prefix"`
+	S3              S3Config `mapstructure:"s3"`
+}
+
+// S3Config addresses a bucket on AWS S3 or, with Endpoint, on an
+// S3-compatible store such as MinIO.
+type S3Config struct {
+	Endpoint        string        `mapstructure:"endpoint"`
+	Region          string        `mapstructure:"region"`
+	Bucket          string        `mapstructure:"bucket"`
+	AccessKeyID     string        `mapstructure:"accessKeyId"`
+	SecretAccessKey string        `mapstructure:"secretAccessKey"`
+	PathStyle       bool          `mapstructure:"pathStyle"`
+	Timeout         time.Duration `mapstructure:"timeout"`
+}
+
+// SIEMConfig controls the export of audit and security events. Transport is
+// "syslog" (Address, Network udp, tcp or tls) or "http" (URL, Token, Format
+// splunk or json). Events of categories not listed are not exported.
+type SIEMConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Transport     string        `mapstructure:"transport"`
+	Address       string        `mapstructure:"address"`
+	Network       string        `mapstructure:"network"`
+	URL           string        `mapstructure:"url"`
+	Token         string        `mapstructure:"token"`
+	Format        string        `mapstructure:"format"`
+	Categories    []string      `mapstructure:"categories"`
+	BufferSize    int           `mapstructure:"bufferSize"`
+	BatchSize     int           `mapstructure:"batchSize"`
+	FlushInterval time.Duration `mapstructure:"flushInterval"`
+	// BlockTimeout is how long a request may wait for buffer space before
+	// its event is dropped; zero drops immediately when the buffer is full.
+	BlockTimeout time.Duration `mapstructure:"blockTimeout"`
+	MaxRetries   int           `mapstructure:"maxRetries"`
+	Timeout      time.Duration `mapstructure:"timeout"`
+}
+
+// CryptoConfig selects the implementation behind API key hashing, renewal
+// link MACs and signing: "stdlib" or "fips", or a provider registered by a
+// custom build, e.g. for a PKCS#11 HSM.
+type CryptoConfig struct {
+	Provider string `mapstructure:"provider"`
+}
+
+// SigningConfig controls the keys license files are signed with. KeyRef is
+// only used to bootstrap the first key; later keys are added by rotation.
+// Retired keys stay in the JWKS for PublishRetiredFor, zero keeps them
+// forever. PassportTTL bounds validation passports, which edge proxies
+// accept without calling back, so it is how long a revocation can go
+// unnoticed there. EntitlementTokenTTL does the same for the entitlement
+// tokens agents cache between validations.
+type SigningConfig struct {
+	KeyRef              string        `mapstructure:"keyRef"`
+	Issuer              string        `mapstructure:"issuer"`
+	RefreshInterval     time.Duration `mapstructure:"refreshInterval"`
+	PublishRetiredFor   time.Duration `mapstructure:"publishRetiredFor"`
+	PassportTTL         time.Duration `mapstructure:"passportTTL"`
+	PassportAudience    string        `mapstructure:"passportAudience"`
+	EntitlementTokenTTL time.Duration `mapstructure:"entitlementTokenTTL"`
+}
+
+const (
+	RegionPrimary = "primary"
+	RegionReplica = "replica"
+)
+
+// RegionConfig sets up multi-region deployments. A replica region reads
+// from a logical replica of the primary database and only serves agents;
+// the writes agents cause are forwarded to PrimaryURL. SharedSecret
+// authenticates forwarded writes and must match on both sides.
+type RegionConfig struct {
+	Name           string        `mapstructure:"name"`
+	Role           string        `mapstructure:"role"`
+	PrimaryURL     string        `mapstructure:"primaryUrl"`
+	SharedSecret   string        `mapstructure:"sharedSecret"`
+	ForwardTimeout time.Duration `mapstructure:"forwardTimeout"`
+}
+
+func (c *RegionConfig) Replica() bool {
+	return c.Role == RegionReplica
+}
+
+// DeploymentConfig identifies this instance in response headers, metrics
+// and logs, so canary and stable instances can be told apart during a
+// rollout.
+type DeploymentConfig struct {
+	// InstanceID defaults to the host name.
+	InstanceID string `mapstructure:"instanceId"`
+	// Track is the release track of the instance, e.g. "stable" or
+	// "canary".
+	Track string `mapstructure:"track"`
+}
+
+// QueryConfig bounds how much database work a single list or export
+// request may cause. Zero disables a bound.
+type QueryConfig struct {
+	// MaxOffset is the largest offset the license list accepts. Deep pages
+	// make the database scan and discard every row before them.
+	MaxOffset int `mapstructure:"maxOffset"`
+	// MaxExportRows is the most licenses a single export may stream.
+	MaxExportRows int64 `mapstructure:"maxExportRows"`
+}
+
+// ApprovalConfig controls changes to protected license keys, which need the
+// elevated role or a second user's approval.
+type ApprovalConfig struct {
+	// ElevatedRole is the OIDC project role allowed to change protected keys
+	// directly and to manage the list of protected keys.
+	ElevatedRole string `mapstructure:"elevatedRole"`
+	// TTL is how long a held change waits for approval.
+	TTL time.Duration `mapstructure:"ttl"`
+	// ExtensionApproverRole is the OIDC project role allowed to approve or
+	// reject requests to extend the expiry of a license.
+	ExtensionApproverRole string `mapstructure:"extensionApproverRole"`
+}
+
+// AuthzConfig points at the YAML file that maps OIDC roles to the routes
+// users may call, see package authz. Without PolicyFile every authenticated
+// user may call every route. The file is re-read every RefreshInterval when
+// it has changed.
+type AuthzConfig struct {
+	PolicyFile      string        `mapstructure:"policyFile"`
+	RefreshInterval time.Duration `mapstructure:"refreshInterval"`
+}
+
+// StepUpConfig guards destructive admin actions, such as bulk revoke, key
+// rotation and exports, with step-up authentication: the user must have
+// signed in or passed a second factor challenge within MaxAge.
+type StepUpConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	MaxAge  time.Duration `mapstructure:"maxAge"`
+	// TOTPIssuer names the service in authenticator apps.
+	TOTPIssuer string `mapstructure:"totpIssuer"`
+}
+
+// ExpiryNotifyConfig controls the e-mails customers get before and when
+// their licenses expire. A reminder goes out ReminderDays days ahead of
+// expiry; a license that enters the window late only gets the closest one.
+// Licenses that expired within ExpiredWindow get an expiry notice when
+// NotifyExpired is set. Licenses are checked on Schedule, a cron spec.
+type ExpiryNotifyConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	ReminderDays  []int         `mapstructure:"reminderDays"`
+	NotifyExpired bool          `mapstructure:"notifyExpired"`
+	ExpiredWindow time.Duration `mapstructure:"expiredWindow"`
+	Schedule      string        `mapstructure:"schedule"`
+}
+
+// MeteringConfig controls the metering of admin and agent API calls per
+// organization and API key for usage billing. Counts are written to the
+// database every FlushInterval.
+type MeteringConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	FlushInterval time.Duration `mapstructure:"flushInterval"`
+}
+
+// SLOConfig sets the objectives of the license validation endpoint over
+// Window. A request counts against availability when answered with a 5xx
+// status and against latency when it took longer than LatencyThreshold.
+// Counts are added to Redis every FlushInterval.
+type SLOConfig struct {
+	Enabled               bool          `mapstructure:"enabled"`
+	FlushInterval         time.Duration `mapstructure:"flushInterval"`
+	Window                time.Duration `mapstructure:"window"`
+	AvailabilityObjective float64       `mapstructure:"availabilityObjective"`
+	LatencyObjective      float64       `mapstructure:"latencyObjective"`
+	LatencyThreshold      time.Duration `mapstructure:"latencyThreshold"`
+}
+
+// ArchiveConfig controls the archive job. On Schedule, a cron spec, it moves
+// licenses expired or revoked, and unchanged, for MinAgeYears into the
+// archive tables with their events, BatchSize licenses at a time.
+type ArchiveConfig struct {
+	Enabled     bool   `mapstructure:"enabled"`
+	MinAgeYears int    `mapstructure:"minAgeYears"`
+	BatchSize   int    `mapstructure:"batchSize"`
+	Schedule    string `mapstructure:"schedule"`
+}
+
+// OfflineActivationConfig applies to offline activation challenges, which
+// can be answered for ChallengeTTL after they are issued.
+type OfflineActivationConfig struct {
+	ChallengeTTL time.Duration `mapstructure:"challengeTTL"`
+}
+
+// WebhooksConfig applies to the webhook subscriptions managed through the
+// API. A failed delivery is retried MaxRetries times, waiting InitialBackoff
+// doubled after every attempt up to MaxBackoff; subscriptions can override
+// each of these. An endpoint failing every attempt for DisableAfter is
+// disabled and, when AlertEmail is set, an alert is mailed there.
+type WebhooksConfig struct {
+	Timeout        time.Duration `mapstructure:"timeout"`
+	MaxRetries     int           `mapstructure:"maxRetries"`
+	InitialBackoff time.Duration `mapstructure:"initialBackoff"`
+	MaxBackoff     time.Duration `mapstructure:"maxBackoff"`
+	DisableAfter   time.Duration `mapstructure:"disableAfter"`
+	AlertEmail     string        `mapstructure:"alertEmail"`
+}
+
+// CampaignsConfig applies to bulk e-mail campaigns. Recipients are mailed
+// BatchSize at a time, one batch every BatchInterval, and a batch with
+// failed sends is retried MaxRetries times. Every e-mail links to
+// UnsubscribeURL with a token signed with UnsubscribeSecret; campaigns
+// cannot be started without the secret.
+type CampaignsConfig struct {
+	BatchSize         int           `mapstructure:"batchSize"`
+	BatchInterval     time.Duration `mapstructure:"batchInterval"`
+	MaxRetries        int           `mapstructure:"maxRetries"`
+	UnsubscribeSecret string        `mapstructure:"unsubscribeSecret"`
+	UnsubscribeURL    string        `mapstructure:"unsubscribeUrl"`
+}
+
+type StatusGuardConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	MaxFlipsPerHour int           `mapstructure:"maxFlipsPerHour"`
+	FreezeDuration  time.Duration `mapstructure:"freezeDuration"`
+}
+
+type NotifyConfig struct {
+	SMTPHost     string `mapstructure:"smtpHost"`
+	SMTPPort     int    `mapstructure:"smtpPort"`
+	SMTPUsername string `mapstructure:"smtpUsername"`
+	SMTPPassword string `mapstructure:"smtpPassword"`
+	From         string `mapstructure:"from"`
+	// ReplyAddress and ReplySecret give notification e-mails a signed
+	// Reply-To address, so customer replies arriving through the inbound
+	// e-mail webhooks are attached to the license as notes. Rotating the
+	// secret orphans replies to e-mails sent before.
+	ReplyAddress      string `mapstructure:"replyAddress"`
+	ReplySecret       string `mapstructure:"replySecret"`
+	MailgunSigningKey string `mapstructure:"mailgunSigningKey"`
+	// SESTopicARN is the SNS topic SES publishes received e-mail to; other
+	// topics are refused.
+	SESTopicARN string `mapstructure:"sesTopicArn"`
+}
+
+type RenewalConfig struct {
+	// SigningSecret signs self-service renewal links. Rotating it invalidates
+	// every link that has been sent out.
+	SigningSecret string        `mapstructure:"signingSecret"`
+	BaseURL       string        `mapstructure:"baseUrl"`
+	LinkTTL       time.Duration `mapstructure:"linkTTL"`
+}
+
+type TemplatesConfig struct {
+	// DefaultLocale is tried after the customer locale and before "en".
+	DefaultLocale string `mapstructure:"defaultLocale"`
+}
+
+// TelemetryConfig controls the anonymous usage ping. It is off unless the
+// operator turns it on; Endpoint falls back to the one stamped into the
+// build by the vendor.
+type TelemetryConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Endpoint string        `mapstructure:"endpoint"`
+	Interval time.Duration `mapstructure:"interval"`
+}
+
 type OIDCConfig struct {
 	IssuerURL string `mapstructure:"issuerUrl"`
 	ClientID  string `mapstructure:"clientId"`
@@ -66,14 +512,192 @@ func LoadConfig(configPath string) (*Config, error) {
 	viper.SetDefault("server.writeTimeout", 10*time.Second)
 	viper.SetDefault("server.idleTimeout", 120*time.Second)
 	viper.SetDefault("server.shutdownPeriod", 15*time.Second)
+	viper.SetDefault("server.serverTiming", false)
+	viper.SetDefault("startup.timeout", 2*time.Minute)
+	viper.SetDefault("startup.initialBackoff", time.Second)
+	viper.SetDefault("startup.maxBackoff", 15*time.Second)
 
 	viper.SetDefault("database.maxOpenConns", 25)
 	viper.SetDefault("database.maxIdleConns", 25)
 	viper.SetDefault("database.connMaxLifetime", 5*time.Minute)
+	viper.SetDefault("database.idStrategy", "uuid")
+	viper.SetDefault("database.slowQueryThreshold", 200*time.Millisecond)
 
 	viper.SetDefault("redis.db", "0")
 
 	viper.SetDefault("log.level", "info")
+	viper.SetDefault("log.masking", "partial")
+
+	viper.SetDefault("cache.layers", []string{"redis"})
+	viper.SetDefault("cache.licenseTTL", 5*time.Minute)
+	viper.SetDefault("cache.apiKeyTTL", time.Minute)
+	viper.SetDefault("cache.productTTL", time.Minute)
+	viper.SetDefault("cache.aggregateTTL", time.Minute)
+	viper.SetDefault("cache.memory.maxCostBytes", 64<<20)
+	viper.SetDefault("cache.memory.maxTTL", 30*time.Second)
+	viper.SetDefault("cache.prewarm.enabled", false)
+	viper.SetDefault("cache.prewarm.topN", 1000)
+	viper.SetDefault("cache.prewarm.interval", 15*time.Minute)
+
+	viper.SetDefault("background.workers", 8)
+	viper.SetDefault("background.queueSize", 1000)
+	viper.SetDefault("background.taskTimeout", 15*time.Second)
+
+	viper.SetDefault("search.enabled", false)
+	viper.SetDefault("search.url", "http://localhost:9200")
+	viper.SetDefault("search.username", "")
+	viper.SetDefault("search.password", "")
+	viper.SetDefault("search.indexPrefix", "license-service")
+	viper.SetDefault("search.batchSize", 500)
+	viper.SetDefault("search.pollInterval", 5*time.Second)
+
+	viper.SetDefault("statusGuard.enabled", true)
+	viper.SetDefault("statusGuard.maxFlipsPerHour", 10)
+	viper.SetDefault("statusGuard.freezeDuration", time.Hour)
+
+	viper.SetDefault("notify.smtpHost", "")
+	viper.SetDefault("notify.smtpPort", 587)
+	viper.SetDefault("notify.smtpUsername", "")
+	viper.SetDefault("notify.smtpPassword", "")
+	viper.SetDefault("notify.from", "licenses@localhost")
+	viper.SetDefault("notify.replyAddress", "")
+	viper.SetDefault("notify.replySecret", "")
+	viper.SetDefault("notify.mailgunSigningKey", "")
+	viper.SetDefault("notify.sesTopicArn", "")
+
+	viper.SetDefault("renewal.signingSecret", "")
+	viper.SetDefault("renewal.baseUrl", "http://localhost:8080/api/v1/renewals/offer")
+	viper.SetDefault("renewal.linkTTL", 14*24*time.Hour)
+
+	viper.SetDefault("templates.defaultLocale", "en")
+
+	viper.SetDefault("telemetry.enabled", false)
+	viper.SetDefault("telemetry.endpoint", "")
+	viper.SetDefault("telemetry.interval", 24*time.Hour)
+
+	viper.SetDefault("lastSeen.enabled", true)
+	viper.SetDefault("lastSeen.debounce", 5*time.Minute)
+	viper.SetDefault("lastSeen.ttl", 30*24*time.Hour)
+
+	viper.SetDefault("validationEvents.enabled", true)
+	viper.SetDefault("validationEvents.successSampleRate", 1)
+	viper.SetDefault("validationEvents.productSampleRates", []string{})
+
+	viper.SetDefault("changeFeed.pollInterval", 2*time.Second)
+	viper.SetDefault("changeFeed.maxSubscriptions", 100)
+
+	viper.SetDefault("floating.leaseTTL", 15*time.Minute)
+	viper.SetDefault("floating.maxLeaseTTL", 24*time.Hour)
+	viper.SetDefault("heartbeat.staleAfter", 7*24*time.Hour)
+	viper.SetDefault("heartbeat.autoReclaim", false)
+	viper.SetDefault("keyRotation.overlap", 7*24*time.Hour)
+	viper.SetDefault("keyRotation.maxOverlap", 90*24*time.Hour)
+	viper.SetDefault("customStatuses.refreshInterval", 30*time.Second)
+	viper.SetDefault("lifecycleHooks.webhookUrl", "")
+	viper.SetDefault("lifecycleHooks.webhookSecret", "")
+	viper.SetDefault("lifecycleHooks.webhookEvents", []string{"created", "activated", "expired", "revoked"})
+	viper.SetDefault("lifecycleHooks.webhookTimeout", 5*time.Second)
+	viper.SetDefault("lifecycleHooks.emailEvents", []string{})
+	viper.SetDefault("lifecycleHooks.pollInterval", 2*time.Second)
+	viper.SetDefault("lifecycleHooks.batchSize", 100)
+	viper.SetDefault("enrichment.countryHeader", "")
+	viper.SetDefault("enrichment.asnHeader", "")
+	viper.SetDefault("enrichment.tlsFingerprintHeader", "")
+	viper.SetDefault("enrichment.ip2asnFile", "")
+	viper.SetDefault("analyticsExport.enabled", false)
+	viper.SetDefault("analyticsExport.schedule", "30 2 * * *")
+	viper.SetDefault("analyticsExport.format", "parquet")
+	viper.SetDefault("analyticsExport.pseudonymSecret", "")
+	viper.SetDefault("analyticsExport.prefix", "license-analytics/")
+	viper.SetDefault("analyticsExport.s3.endpoint", "")
+	viper.SetDefault("analyticsExport.s3.region", "us-east-1")
+	viper.SetDefault("analyticsExport.s3.bucket", "")
+	viper.SetDefault("analyticsExport.s3.accessKeyId", "")
+	viper.SetDefault("analyticsExport.s3.secretAccessKey", "")
+	viper.SetDefault("analyticsExport.s3.pathStyle", false)
+	viper.SetDefault("analyticsExport.s3.timeout", 5*time.Minute)
+
+	viper.SetDefault("siem.enabled", false)
+	viper.SetDefault("siem.transport", "syslog")
+	viper.SetDefault("siem.address", "localhost:514")
+	viper.SetDefault("siem.network", "udp")
+	viper.SetDefault("siem.url", "")
+	viper.SetDefault("siem.token", "")
+	viper.SetDefault("siem.format", "json")
+	viper.SetDefault("siem.categories", []string{"audit", "auth", "access"})
+	viper.SetDefault("siem.bufferSize", 10000)
+	viper.SetDefault("siem.batchSize", 100)
+	viper.SetDefault("siem.flushInterval", 2*time.Second)
+	viper.SetDefault("siem.blockTimeout", 0)
+	viper.SetDefault("siem.maxRetries", 3)
+	viper.SetDefault("siem.timeout", 5*time.Second)
+
+	viper.SetDefault("crypto.provider", "stdlib")
+
+	viper.SetDefault("signing.keyRef", "")
+	viper.SetDefault("signing.issuer", "license-service")
+	viper.SetDefault("signing.refreshInterval", time.Minute)
+	viper.SetDefault("signing.publishRetiredFor", 0)
+	viper.SetDefault("signing.passportTTL", 15*time.Minute)
+	viper.SetDefault("signing.passportAudience", "")
+	viper.SetDefault("signing.entitlementTokenTTL", time.Hour)
+
+	viper.SetDefault("region.name", "default")
+	viper.SetDefault("region.role", RegionPrimary)
+	viper.SetDefault("region.primaryUrl", "")
+	viper.SetDefault("region.sharedSecret", "")
+	viper.SetDefault("region.forwardTimeout", 10*time.Second)
+
+	viper.SetDefault("query.maxOffset", 10000)
+	viper.SetDefault("query.maxExportRows", 100000)
+
+	viper.SetDefault("approval.elevatedRole", "license_admin")
+	viper.SetDefault("approval.ttl", 72*time.Hour)
+	viper.SetDefault("approval.extensionApproverRole", "license_approver")
+
+	viper.SetDefault("authz.policyFile", "")
+	viper.SetDefault("authz.refreshInterval", 30*time.Second)
+
+	viper.SetDefault("stepUp.enabled", false)
+	viper.SetDefault("stepUp.maxAge", 5*time.Minute)
+	viper.SetDefault("stepUp.totpIssuer", "License Service")
+
+	viper.SetDefault("expiryNotify.enabled", false)
+	viper.SetDefault("expiryNotify.reminderDays", []int{30, 7, 1})
+	viper.SetDefault("expiryNotify.notifyExpired", true)
+	viper.SetDefault("expiryNotify.expiredWindow", 72*time.Hour)
+	viper.SetDefault("expiryNotify.schedule", "0 * * * *")
+
+	viper.SetDefault("metering.enabled", false)
+	viper.SetDefault("metering.flushInterval", 30*time.Second)
+
+	viper.SetDefault("slo.enabled", true)
+	viper.SetDefault("slo.flushInterval", 10*time.Second)
+	viper.SetDefault("slo.window", 30*24*time.Hour)
+	viper.SetDefault("slo.availabilityObjective", 0.999)
+	viper.SetDefault("slo.latencyObjective", 0.99)
+	viper.SetDefault("slo.latencyThreshold", 300*time.Millisecond)
+	viper.SetDefault("archive.enabled", false)
+	viper.SetDefault("archive.minAgeYears", 3)
+	viper.SetDefault("archive.batchSize", 500)
+	viper.SetDefault("archive.schedule", "0 3 * * *")
+	viper.SetDefault("offlineActivation.challengeTTL", "72h")
+
+	viper.SetDefault("webhooks.timeout", 10*time.Second)
+	viper.SetDefault("webhooks.maxRetries", 10)
+	viper.SetDefault("webhooks.initialBackoff", 30*time.Second)
+	viper.SetDefault("webhooks.maxBackoff", time.Hour)
+	viper.SetDefault("webhooks.disableAfter", 24*time.Hour)
+	viper.SetDefault("webhooks.alertEmail", "")
+
+	viper.SetDefault("campaigns.batchSize", 100)
+	viper.SetDefault("campaigns.batchInterval", time.Minute)
+	viper.SetDefault("campaigns.maxRetries", 3)
+	viper.SetDefault("campaigns.unsubscribeSecret", "")
+	viper.SetDefault("campaigns.unsubscribeUrl", "http://localhost:8080/api/v1/campaigns/unsubscribe")
+
+	viper.SetDefault("deployment.instanceId", "")
+	viper.SetDefault("deployment.track", "stable")
 
 	viper.AutomaticEnv()
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
@@ -91,6 +715,9 @@ func LoadConfig(configPath string) (*Config, error) {
 	if err := viper.BindEnv("database.url", "DATABASE_URL"); err != nil {
 		log.Printf("Warning: could not bind DATABASE_URL: %v\n", err)
 	}
+	if err := viper.BindEnv("database.shardUrls", "DATABASE_SHARD_URLS"); err != nil {
+		log.Printf("Warning: could not bind DATABASE_SHARD_URLS: %v\n", err)
+	}
 	if err := viper.BindEnv("redis.addr", "REDIS_ADDR"); err != nil {
 		log.Printf("Warning: could not bind REDIS_ADDR: %v\n", err)
 	}
@@ -120,6 +747,9 @@ func LoadConfig(configPath string) (*Config, error) {
 	if err := viper.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal configuration: %w", err)
 	}
+	if cfg.Deployment.InstanceID == "" {
+		cfg.Deployment.InstanceID, _ = os.Hostname()
+	}
 
 	return &cfg, nil
 }
@@ -0,0 +1,30 @@
+package cryptoprovider
+
+import (
+	"crypto/fips140"
+	"fmt"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+)
+
+const FIPSName = "fips"
+
+func init() {
+	Register(FIPSName, func(*config.CryptoConfig) (Provider, error) {
+		if !fips140.Enabled() {
+			return nil, fmt.Errorf("crypto provider %q requires the Go FIPS 140-3 module, run with GODEBUG=fips140=on or build with GOFIPS140", FIPSName)
+		}
+		return FIPS{}, nil
+	})
+}
+
+// FIPS is the stdlib provider running on Go's validated FIPS 140-3 module.
+// It refuses to start unless that module is enabled, so a misconfigured
+// deployment fails loudly instead of silently using unvalidated code.
+type FIPS struct {
+	Stdlib
+}
+
+var _ Provider = FIPS{}
+
+func (FIPS) Name() string { return FIPSName }
@@ -0,0 +1,89 @@
+// Package cryptoprovider puts the service's hashing, MAC, randomness and
+// signing behind one interface, so deployments that need FIPS-validated
+// modules or keys held in an HSM can swap the implementation by
+// configuration. The stdlib and fips providers are built in; others, such
+// as a PKCS#11 provider, register themselves from their own package with
+// Register and are selected by name.
+package cryptoprovider
+
+import (
+	"crypto"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+)
+
+// Provider implementations must keep the algorithms fixed: stored API key
+// hashes and issued renewal links are only valid as long as Hash stays
+// SHA-256 and MAC stays HMAC-SHA256.
+type Provider interface {
+	Name() string
+	// Rand is the source of randomness for secrets such as API keys.
+	Rand() io.Reader
+	// Hash returns the SHA-256 digest of data.
+	Hash(data []byte) []byte
+	// MAC returns the HMAC-SHA256 of data under key.
+	MAC(key, data []byte) []byte
+	// Signer opens the signing key identified by ref: a PEM file path for
+	// the built-in providers, a key URI for HSM-backed ones.
+	Signer(ref string) (Signer, error)
+}
+
+// Signer signs with a private key the service may never see, which is why
+// signing can fail at any time.
+type Signer interface {
+	// Algorithm is the JOSE name of the signature algorithm, e.g. "EdDSA".
+	Algorithm() string
+	Public() crypto.PublicKey
+	Sign(data []byte) ([]byte, error)
+}
+
+type Factory func(cfg *config.CryptoConfig) (Provider, error)
+
+var (
+	mu        sync.RWMutex
+	factories = map[string]Factory{}
+)
+
+// Register makes a provider available under name. It is meant to be called
+// from init functions and panics on duplicate names.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	name = strings.ToLower(name)
+	if _, dup := factories[name]; dup {
+		panic("cryptoprovider: provider " + name + " registered twice")
+	}
+	factories[name] = factory
+}
+
+// New returns the provider named in cfg; an empty name selects stdlib.
+func New(cfg *config.CryptoConfig) (Provider, error) {
+	name := strings.ToLower(cfg.Provider)
+	if name == "" {
+		name = StdlibName
+	}
+
+	mu.RLock()
+	factory, ok := factories[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown crypto provider %q, available: %s", cfg.Provider, strings.Join(names(), ", "))
+	}
+	return factory(cfg)
+}
+
+func names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	list := make([]string, 0, len(factories))
+	for name := range factories {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
@@ -0,0 +1,124 @@
+package cryptoprovider
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+)
+
+const StdlibName = "stdlib"
+
+func init() {
+	Register(StdlibName, func(*config.CryptoConfig) (Provider, error) {
+		return Stdlib{}, nil
+	})
+}
+
+// Stdlib uses Go's crypto packages and keeps signing keys in PKCS#8 PEM
+// files. Ed25519 and ECDSA P-256 keys are supported.
+type Stdlib struct{}
+
+var _ Provider = Stdlib{}
+
+func (Stdlib) Name() string { return StdlibName }
+
+func (Stdlib) Rand() io.Reader { return rand.Reader }
+
+func (Stdlib) Hash(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+func (Stdlib) MAC(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func (Stdlib) Signer(ref string) (Signer, error) {
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return nil, fmt.Errorf("reading signing key: %w", err)
+	}
+	return ParsePrivateKeyPEM(data)
+}
+
+// ParsePrivateKeyPEM parses a PKCS#8 "PRIVATE KEY" block.
+func ParsePrivateKeyPEM(data []byte) (Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("signing key is not a PEM encoded PKCS#8 private key")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing signing key: %w", err)
+	}
+
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return ed25519Signer{key: k}, nil
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("unsupported ECDSA curve %s, only P-256 is supported", k.Curve.Params().Name)
+		}
+		return ecdsaSigner{key: k}, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing key type %T, expected Ed25519 or ECDSA P-256", key)
+	}
+}
+
+type ed25519Signer struct {
+	key ed25519.PrivateKey
+}
+
+func (ed25519Signer) Algorithm() string { return "EdDSA" }
+
+func (s ed25519Signer) Public() crypto.PublicKey { return s.key.Public() }
+
+func (s ed25519Signer) Sign(data []byte) ([]byte, error) {
+	return ed25519.Sign(s.key, data), nil
+}
+
+type ecdsaSigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func (ecdsaSigner) Algorithm() string { return "ES256" }
+
+func (s ecdsaSigner) Public() crypto.PublicKey { return s.key.Public() }
+
+// Sign returns the fixed-size r||s encoding JOSE uses rather than ASN.1.
+func (s ecdsaSigner) Sign(data []byte) ([]byte, error) {
+	digest := sha256.Sum256(data)
+	r, sv, err := ecdsa.Sign(rand.Reader, s.key, digest[:])
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa signing: %w", err)
+	}
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	sv.FillBytes(sig[32:])
+	return sig, nil
+}
+
+// VerifyES256 checks a signature produced by an ES256 Signer.
+func VerifyES256(pub *ecdsa.PublicKey, data, sig []byte) bool {
+	if len(sig) != 64 {
+		return false
+	}
+	digest := sha256.Sum256(data)
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+	return ecdsa.Verify(pub, digest[:], r, s)
+}
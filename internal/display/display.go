@@ -0,0 +1,191 @@
+// Package display formats dates, relative times and numbers the way a
+// customer reads them: in their locale and time zone. API responses that
+// opt into display values and the customer e-mails use the same formatter,
+// so a client app showing a license and the e-mail about it agree.
+//
+// The locales are the ones the templates ship in, en and ru, resolved with
+// the same fallback chain as templates: "pt-BR" tries "pt", then the
+// default locale and finally "en".
+package display
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	// The runtime image has no zoneinfo of its own.
+	_ "time/tzdata"
+
+	"github.com/makkenzo/license-service-api/internal/templates"
+)
+
+const fallbackLocale = "en"
+
+type language struct {
+	months        [12]string
+	thousandsSep  string
+	expiresIn     func(n int, u unit) string
+	expiredAgo    func(n int, u unit) string
+	expiresSoon   string
+	expiredRecent string
+}
+
+type unit int
+
+const (
+	days unit = iota
+	hours
+)
+
+var languages = map[string]*language{
+	"en": {
+		months: [12]string{"January", "February", "March", "April", "May", "June",
+			"July", "August", "September", "October", "November", "December"},
+		thousandsSep:  ",",
+		expiresIn:     func(n int, u unit) string { return "expires in " + englishCount(n, u) },
+		expiredAgo:    func(n int, u unit) string { return "expired " + englishCount(n, u) + " ago" },
+		expiresSoon:   "expires in less than an hour",
+		expiredRecent: "expired less than an hour ago",
+	},
+	"ru": {
+		months: [12]string{"января", "февраля", "марта", "апреля", "мая", "июня",
+			"июля", "августа", "сентября", "октября", "ноября", "декабря"},
+		thousandsSep:  "\u00a0",
+		expiresIn:     func(n int, u unit) string { return "истекает через " + russianCount(n, u) },
+		expiredAgo:    func(n int, u unit) string { return "истекла " + russianCount(n, u) + " назад" },
+		expiresSoon:   "истекает менее чем через час",
+		expiredRecent: "истекла менее часа назад",
+	},
+}
+
+func englishCount(n int, u unit) string {
+	word := "day"
+	if u == hours {
+		word = "hour"
+	}
+	if n != 1 {
+		word += "s"
+	}
+	return strconv.Itoa(n) + " " + word
+}
+
+func russianCount(n int, u unit) string {
+	forms := [3]string{"день", "дня", "дней"}
+	if u == hours {
+		forms = [3]string{"час", "часа", "часов"}
+	}
+	form := forms[2]
+	switch mod10, mod100 := n%10, n%100; {
+	case mod10 == 1 && mod100 != 11:
+		form = forms[0]
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		form = forms[1]
+	}
+	return strconv.Itoa(n) + " " + form
+}
+
+// Localizer hands out formatters for customers.
+type Localizer struct {
+	defaultLocale string
+}
+
+func NewLocalizer(defaultLocale string) *Localizer {
+	return &Localizer{defaultLocale: templates.NormalizeLocale(defaultLocale)}
+}
+
+// For returns the formatter for locale and the IANA time zone timezone. An
+// empty or unknown time zone is UTC.
+func (l *Localizer) For(locale, timezone string) *Formatter {
+	f := &Formatter{locale: fallbackLocale, loc: time.UTC}
+	for _, candidate := range []string{templates.NormalizeLocale(locale), l.defaultLocale} {
+		base, _, _ := strings.Cut(candidate, "-")
+		if _, ok := languages[base]; ok {
+			f.locale = base
+			break
+		}
+	}
+	f.lang = languages[f.locale]
+	if loc, err := LoadTimezone(timezone); err == nil {
+		f.loc = loc
+	}
+	return f
+}
+
+// LoadTimezone returns the IANA time zone name, UTC for an empty name.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	// Local would be the server's zone, which means nothing to a customer.
+	if strings.EqualFold(name, "local") {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return time.LoadLocation(name)
+}
+
+type Formatter struct {
+	locale string
+	loc    *time.Location
+	lang   *language
+}
+
+// Locale is the locale the formatter ended up with after fallback.
+func (f *Formatter) Locale() string { return f.locale }
+
+func (f *Formatter) Timezone() string { return f.loc.String() }
+
+// Date formats the calendar date of t in the formatter's time zone, e.g.
+// "12 October 2026".
+func (f *Formatter) Date(t time.Time) string {
+	t = t.In(f.loc)
+	return fmt.Sprintf("%d %s %d", t.Day(), f.lang.months[t.Month()-1], t.Year())
+}
+
+// DateTime is Date followed by the 24-hour wall clock time.
+func (f *Formatter) DateTime(t time.Time) string {
+	return f.Date(t) + ", " + t.In(f.loc).Format("15:04")
+}
+
+// Expiry says when expiresAt is relative to now, e.g. "expires in 12 days"
+// or "expired 3 hours ago". Durations of a day or more are rounded to
+// whole days.
+func (f *Formatter) Expiry(expiresAt, now time.Time) string {
+	d := expiresAt.Sub(now)
+	future := d > 0
+	if !future {
+		d = -d
+	}
+
+	n, u := int((d+12*time.Hour)/(24*time.Hour)), days
+	if d < 24*time.Hour {
+		n, u = int(d/time.Hour), hours
+	}
+	switch {
+	case n == 0 && future:
+		return f.lang.expiresSoon
+	case n == 0:
+		return f.lang.expiredRecent
+	case future:
+		return f.lang.expiresIn(n, u)
+	default:
+		return f.lang.expiredAgo(n, u)
+	}
+}
+
+// Number formats n with the locale's thousands separator.
+func (f *Formatter) Number(n int64) string {
+	digits := strconv.FormatInt(n, 10)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, ch := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(f.lang.thousandsSep)
+		}
+		b.WriteRune(ch)
+	}
+	return b.String()
+}
@@ -0,0 +1,56 @@
+package activation
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+)
+
+var ErrSeatLimitExceeded = ierr.ErrConflict.Derive("SEAT_LIMIT_EXCEEDED", "all seats of this license are in use, revoke an activation first")
+
+// Activation binds a license to one device. Deactivated rows are kept as
+// history; only rows without DeactivatedAt count as bound devices.
+type Activation struct {
+	ID            uuid.UUID  `db:"id"`
+	LicenseID     uuid.UUID  `db:"license_id"`
+	DeviceID      string     `db:"device_id"`
+	IP            string     `db:"ip"`
+	UserAgent     string     `db:"user_agent"`
+	ActivatedAt   time.Time  `db:"activated_at"`
+	DeactivatedAt *time.Time `db:"deactivated_at"`
+	// LastSeenAt, AppVersion and Host come from the latest heartbeat.
+	LastSeenAt *time.Time `db:"last_seen_at"`
+	AppVersion string     `db:"app_version"`
+	Host       HostInfo   `db:"host_info"`
+}
+
+func (a *Activation) Active() bool {
+	return a.DeactivatedAt == nil
+}
+
+// LastActivity is the time of the last heartbeat, or of the activation if
+// the agent never sent one.
+func (a *Activation) LastActivity() time.Time {
+	if a.LastSeenAt != nil {
+		return *a.LastSeenAt
+	}
+	return a.ActivatedAt
+}
+
+// HostInfo describes the machine an agent runs on.
+type HostInfo struct {
+	Hostname  string `json:"hostname,omitempty"`
+	OS        string `json:"os,omitempty"`
+	OSVersion string `json:"os_version,omitempty"`
+	Arch      string `json:"arch,omitempty"`
+}
+
+// Heartbeat is an agent's periodic report that it still runs on a device.
+type Heartbeat struct {
+	LicenseID  uuid.UUID
+	DeviceID   string
+	AppVersion string
+	Host       HostInfo
+	At         time.Time
+}
@@ -0,0 +1,43 @@
+package activation
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// Activate binds a.DeviceID to a.LicenseID. If the device is already
+	// bound, a is filled from the existing activation and created is false.
+	// It fails with ErrSeatLimitExceeded when maxActive other devices are
+	// bound. The license is not checked: activations are stored apart from
+	// sharded licenses, so callers look the license up first.
+	Activate(ctx context.Context, a *Activation, maxActive int) (created bool, err error)
+	// Deactivate unbinds a device and returns ierr.ErrNotFound if it was not
+	// bound.
+	Deactivate(ctx context.Context, licenseID uuid.UUID, deviceID string) (*Activation, error)
+	// Revoke unbinds the activation with the given ID, freeing its seat. It
+	// returns ierr.ErrNotFound if the activation does not belong to the
+	// license or is no longer active.
+	Revoke(ctx context.Context, licenseID, activationID uuid.UUID) (*Activation, error)
+	// Heartbeat records that the device of an active activation is still
+	// running. It returns ierr.ErrNotFound if the device is not bound.
+	Heartbeat(ctx context.Context, hb *Heartbeat) (*Activation, error)
+	// ReclaimStale unbinds the active activations of a license whose last
+	// activity is before the given time and returns them.
+	ReclaimStale(ctx context.Context, licenseID uuid.UUID, before time.Time) ([]*Activation, error)
+	// CountSeenSince counts the active activations, across all licenses,
+	// that sent a heartbeat at or after since.
+	CountSeenSince(ctx context.Context, since time.Time) (int64, error)
+	ListActive(ctx context.Context, licenseID uuid.UUID) ([]*Activation, error)
+	// ListLicenseIDs returns, in order, up to limit IDs of licenses with
+	// activations, active or not, that sort after the given one.
+	ListLicenseIDs(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, error)
+	// DeleteByLicense removes every activation of a license, for licenses
+	// that no longer exist, and returns how many there were.
+	DeleteByLicense(ctx context.Context, licenseID uuid.UUID) (int64, error)
+	// List returns the activations of a license, newest first, including
+	// deactivated ones when includeInactive is set.
+	List(ctx context.Context, licenseID uuid.UUID, includeInactive bool) ([]*Activation, error)
+}
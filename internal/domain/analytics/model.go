@@ -0,0 +1,23 @@
+package analytics
+
+import "github.com/google/uuid"
+
+// LicenseActivity is what one license did during a period: how often it
+// was validated and how its devices changed. It holds nothing about the
+// customer; exports replace LicenseID with a pseudonym.
+type LicenseActivity struct {
+	LicenseID uuid.UUID
+	// ProductName is empty when the license had device changes but no
+	// validations in the period.
+	ProductName string
+	// Validations is estimated from sampled validation events.
+	Validations        int64
+	FailedValidations  int64
+	DistinctDevices    int64
+	Countries          int64
+	DevicesActivated   int64
+	DevicesDeactivated int64
+	// ActiveDevices is the number of devices activated at the end of the
+	// period.
+	ActiveDevices int64
+}
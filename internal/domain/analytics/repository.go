@@ -0,0 +1,13 @@
+package analytics
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// LicenseActivity calls fn for every license that was validated or had
+	// devices activated in [from, to), ordered by license ID. An error from
+	// fn stops the iteration and is returned as is.
+	LicenseActivity(ctx context.Context, from, to time.Time, fn func(*LicenseActivity) error) error
+}
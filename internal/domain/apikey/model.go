@@ -1,20 +1,29 @@
 package apikey
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type APIKey struct {
-	ID          uuid.UUID  `db:"id"`
-	KeyHash     string     `db:"key_hash"`
-	Prefix      string     `db:"prefix"`
-	Description string     `db:"description"`
-	ProductID   uuid.UUID  `db:"product_id"`
-	IsEnabled   bool       `db:"is_enabled"`
-	CreatedAt   time.Time  `db:"created_at"`
-	LastUsedAt  *time.Time `db:"last_used_at"`
+	ID          uuid.UUID `db:"id"`
+	KeyHash     string    `db:"key_hash"`
+	Prefix      string    `db:"prefix"`
+	Description string    `db:"description"`
+	ProductID   uuid.UUID `db:"product_id"`
+	// OrgID is the organization the key's calls are billed to, empty for
+	// keys that are not billed.
+	OrgID string `db:"org_id"`
+	// Scopes are the agent operations the key may call, see AllScopes.
+	Scopes []string `db:"scopes"`
+	// CreatedBy is the subject of the user who created the key, empty for
+	// keys created outside the API.
+	CreatedBy  string     `db:"created_by"`
+	IsEnabled  bool       `db:"is_enabled"`
+	CreatedAt  time.Time  `db:"created_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
 }
 
 const (
@@ -22,3 +31,30 @@ const (
 	APIKeySecretLength = 32
 	APIKeyFormat       = "lm_%s_%s"
 )
+
+const (
+	// ScopeLicensesValidate allows validating licenses and reading passports
+	// and revocation lists.
+	ScopeLicensesValidate = "licenses:validate"
+	// ScopeActivationsWrite allows activating, deactivating, heartbeats and
+	// floating seat checkouts.
+	ScopeActivationsWrite = "activations:write"
+	// ScopeUsageWrite allows reporting metered usage.
+	ScopeUsageWrite = "usage:write"
+	// ScopeUsageRead allows reading usage summaries.
+	ScopeUsageRead = "usage:read"
+)
+
+// AllScopes lists every scope, in the order they are documented. Keys
+// created without scopes get all of them.
+var AllScopes = []string{ScopeLicensesValidate, ScopeActivationsWrite, ScopeUsageWrite, ScopeUsageRead}
+
+func ValidScope(scope string) bool {
+	return slices.Contains(AllScopes, scope)
+}
+
+// HasScope reports whether the key may call operations of scope. Keys
+// without scopes predate them and may call everything.
+func (k *APIKey) HasScope(scope string) bool {
+	return len(k.Scopes) == 0 || slices.Contains(k.Scopes, scope)
+}
@@ -0,0 +1,32 @@
+package apiusage
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The APIs calls are metered for: the admin API, called by users with OIDC
+// tokens, and the API agents call with API keys, mostly to validate.
+const (
+	APIAdmin    = "admin"
+	APIValidate = "validate"
+)
+
+// Counter counts the calls one organization made to one API in a month.
+// Validate calls are counted per API key; admin calls have a nil APIKeyID.
+// OrgID is empty for calls that cannot be attributed to an organization.
+type Counter struct {
+	Month       time.Time `db:"month"`
+	OrgID       string    `db:"org_id"`
+	API         string    `db:"api"`
+	APIKeyID    uuid.UUID `db:"api_key_id"`
+	Calls       int64     `db:"calls"`
+	FailedCalls int64     `db:"failed_calls"`
+}
+
+// MonthOf returns the first day of the UTC month of t.
+func MonthOf(t time.Time) time.Time {
+	y, m, _ := t.UTC().Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+}
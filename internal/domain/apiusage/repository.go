@@ -0,0 +1,14 @@
+package apiusage
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// Add adds the counts to the stored counters in one transaction.
+	Add(ctx context.Context, counters []*Counter) error
+	// ListMonth returns the counters of a month ordered by organization, API
+	// and API key, only those of orgID when it is set.
+	ListMonth(ctx context.Context, month time.Time, orgID *string) ([]*Counter, error)
+}
@@ -0,0 +1,52 @@
+// Package approval holds changes to protected license keys until a second
+// user approves them.
+package approval
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+)
+
+// ProtectedKey names a license field or metadata path, written as in audit
+// diffs: "max_activations" or "metadata.limits.seats". A metadata path also
+// protects everything nested below it.
+type ProtectedKey struct {
+	Key         string    `db:"key" json:"key"`
+	Description string    `db:"description" json:"description"`
+	CreatedBy   string    `db:"created_by" json:"created_by"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+// Request is a license update that touches protected keys, held until it is
+// approved by someone other than RequestedBy. Changes is the update request
+// body as sent.
+type Request struct {
+	ID            uuid.UUID       `db:"id"`
+	LicenseID     uuid.UUID       `db:"license_id"`
+	Changes       json.RawMessage `db:"changes"`
+	ProtectedKeys []string        `db:"protected_keys"`
+	RequestedBy   string          `db:"requested_by"`
+	// BaseUpdatedAt is the license's updated_at when the change was
+	// requested, so approval can tell the license changed in between.
+	BaseUpdatedAt time.Time      `db:"base_updated_at"`
+	Status        Status         `db:"status"`
+	DecidedBy     sql.NullString `db:"decided_by"`
+	DecidedAt     sql.NullTime   `db:"decided_at"`
+	CreatedAt     time.Time      `db:"created_at"`
+	ExpiresAt     time.Time      `db:"expires_at"`
+}
+
+func (r *Request) Expired(now time.Time) bool {
+	return r.Status == StatusPending && !now.Before(r.ExpiresAt)
+}
@@ -0,0 +1,34 @@
+package approval
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type KeyRepository interface {
+	List(ctx context.Context) ([]*ProtectedKey, error)
+	// Put creates the key or updates its description.
+	Put(ctx context.Context, key *ProtectedKey) error
+	Delete(ctx context.Context, key string) error
+}
+
+type ListParams struct {
+	Status    *Status
+	LicenseID *uuid.UUID
+	Limit     int
+	Offset    int
+}
+
+type Repository interface {
+	Create(ctx context.Context, r *Request) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Request, error)
+	List(ctx context.Context, params ListParams) ([]*Request, int64, error)
+	// Decide moves a pending request to status. It fails with
+	// ierr.ErrConflict when the request was already decided, so only one
+	// approver wins.
+	Decide(ctx context.Context, id uuid.UUID, status Status, decidedBy string) (*Request, error)
+	// Reopen puts an approved request back to pending when its change could
+	// not be applied.
+	Reopen(ctx context.Context, id uuid.UUID) error
+}
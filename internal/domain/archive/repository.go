@@ -0,0 +1,24 @@
+package archive
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// EventCounts are the rows of each event table moved for a set of licenses.
+type EventCounts struct {
+	Activations      int64
+	StatusChanges    int64
+	ValidationEvents int64
+}
+
+// EventRepository moves the activations, status history and validation
+// events of licenses between their tables and the archive tables.
+type EventRepository interface {
+	// Archive moves the events of licenseIDs into the archive tables in one
+	// transaction.
+	Archive(ctx context.Context, licenseIDs []uuid.UUID) (EventCounts, error)
+	// Restore moves them back.
+	Restore(ctx context.Context, licenseIDs []uuid.UUID) error
+}
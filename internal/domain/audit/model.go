@@ -0,0 +1,46 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ActorType string
+
+const (
+	ActorUser     ActorType = "user"
+	ActorAPIKey   ActorType = "api_key"
+	ActorCustomer ActorType = "customer"
+	ActorSystem   ActorType = "system"
+)
+
+const EntityLicense = "license"
+
+const (
+	ActionCreate       = "create"
+	ActionUpdate       = "update"
+	ActionUpdateStatus = "update_status"
+	ActionRotateKey    = "rotate_key"
+)
+
+type Actor struct {
+	Type      ActorType `json:"type"`
+	ID        string    `json:"id,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+}
+
+// Entry is one recorded mutation. Before and After are flat JSON snapshots of
+// the entity; Before is empty for creations.
+type Entry struct {
+	ID         uuid.UUID       `db:"id"`
+	EntityType string          `db:"entity_type"`
+	EntityID   uuid.UUID       `db:"entity_id"`
+	Action     string          `db:"action"`
+	Actor      Actor           `db:"-"`
+	Before     json.RawMessage `db:"before"`
+	After      json.RawMessage `db:"after"`
+	CreatedAt  time.Time       `db:"created_at"`
+}
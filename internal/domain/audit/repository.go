@@ -0,0 +1,29 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ListParams filters audit entries; nil fields match everything. Entries
+// are returned newest first.
+type ListParams struct {
+	EntityType *string
+	EntityID   *uuid.UUID
+	Action     *string
+	ActorType  *ActorType
+	ActorID    *string
+	// CreatedAfter is inclusive, CreatedBefore exclusive.
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Limit         int
+	Offset        int
+}
+
+type Repository interface {
+	Record(ctx context.Context, entry *Entry) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Entry, error)
+	List(ctx context.Context, params ListParams) ([]*Entry, int64, error)
+}
@@ -0,0 +1,33 @@
+package bindingfailure
+
+import (
+	"fmt"
+	"time"
+)
+
+// Failure is one rejected request payload: which endpoint, which field, and
+// which client integration sent it.
+type Failure struct {
+	Endpoint string
+	Field    string
+	Client   string
+}
+
+type Offender struct {
+	Failure
+	Count int64
+}
+
+// WeekKey returns the ISO week t falls into, e.g. "2025-W07".
+func WeekKey(t time.Time) string {
+	year, week := t.UTC().ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+func ParseWeekKey(s string) (string, error) {
+	var year, week int
+	if _, err := fmt.Sscanf(s, "%4d-W%02d", &year, &week); err != nil || week < 1 || week > 53 {
+		return "", fmt.Errorf("invalid ISO week %q, expected YYYY-Www", s)
+	}
+	return fmt.Sprintf("%d-W%02d", year, week), nil
+}
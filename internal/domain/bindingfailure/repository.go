@@ -0,0 +1,10 @@
+package bindingfailure
+
+import (
+	"context"
+)
+
+type Repository interface {
+	Record(ctx context.Context, failure Failure) error
+	Top(ctx context.Context, week string, limit int) ([]Offender, error)
+}
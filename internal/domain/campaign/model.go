@@ -0,0 +1,77 @@
+package campaign
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusSending   Status = "sending"
+	StatusCompleted Status = "completed"
+	StatusCancelled Status = "cancelled"
+	StatusFailed    Status = "failed"
+)
+
+// Filter selects the licenses whose holders a campaign is sent to. Empty
+// fields match every license; without Statuses revoked licenses are left
+// out. ExpiresWithinDays selects licenses expiring between the time the
+// campaign was created and that many days later.
+type Filter struct {
+	ProductNames      []string `json:"product_names,omitempty"`
+	Statuses          []string `json:"statuses,omitempty"`
+	ExpiresWithinDays int      `json:"expires_within_days,omitempty"`
+}
+
+// Campaign is one e-mail sent to the holders of the licenses matching
+// Filter, each address once.
+type Campaign struct {
+	ID          uuid.UUID  `db:"id"`
+	Name        string     `db:"name"`
+	Subject     string     `db:"subject"`
+	Message     string     `db:"message"`
+	Filter      Filter     `db:"filter"`
+	Status      Status     `db:"status"`
+	RequestedBy string     `db:"requested_by"`
+	Error       string     `db:"error"`
+	CreatedAt   time.Time  `db:"created_at"`
+	StartedAt   *time.Time `db:"started_at"`
+	FinishedAt  *time.Time `db:"finished_at"`
+}
+
+type RecipientStatus string
+
+const (
+	RecipientPending RecipientStatus = "pending"
+	RecipientSent    RecipientStatus = "sent"
+	RecipientFailed  RecipientStatus = "failed"
+	// RecipientUnsubscribed addresses had unsubscribed when their batch was
+	// sent and were skipped.
+	RecipientUnsubscribed RecipientStatus = "unsubscribed"
+)
+
+// Recipient is one address of a campaign. LicenseID is the license the
+// address was picked for, used for the customer's name and locale. A
+// recipient stays pending while a failed send is being retried.
+type Recipient struct {
+	ID         uuid.UUID       `db:"id"`
+	CampaignID uuid.UUID       `db:"campaign_id"`
+	Email      string          `db:"email"`
+	LicenseID  uuid.UUID       `db:"license_id"`
+	Batch      int             `db:"batch"`
+	Status     RecipientStatus `db:"status"`
+	Attempts   int             `db:"attempts"`
+	LastError  string          `db:"last_error"`
+	SentAt     *time.Time      `db:"sent_at"`
+	CreatedAt  time.Time       `db:"created_at"`
+	UpdatedAt  time.Time       `db:"updated_at"`
+}
+
+type RecipientQuery struct {
+	Status *RecipientStatus
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,34 @@
+package campaign
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, c *Campaign) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Campaign, error)
+	// List returns the most recent campaigns first.
+	List(ctx context.Context, limit int) ([]*Campaign, error)
+	// SetStatus moves a campaign to status, recording when it started or
+	// finished. It returns ierr.ErrConflict when the campaign is not in one
+	// of the from statuses.
+	SetStatus(ctx context.Context, id uuid.UUID, status Status, reason string, from ...Status) error
+
+	// AddRecipients skips addresses the campaign already has, so adding the
+	// same recipients again is harmless.
+	AddRecipients(ctx context.Context, recipients []*Recipient) error
+	FindRecipient(ctx context.Context, id uuid.UUID) (*Recipient, error)
+	// BatchRecipients returns the pending recipients of a batch.
+	BatchRecipients(ctx context.Context, campaignID uuid.UUID, batch int) ([]*Recipient, error)
+	UpdateRecipient(ctx context.Context, r *Recipient) error
+	ListRecipients(ctx context.Context, campaignID uuid.UUID, q RecipientQuery) ([]*Recipient, int64, error)
+	// CountRecipients counts the recipients of a campaign by status.
+	CountRecipients(ctx context.Context, campaignID uuid.UUID) (map[RecipientStatus]int64, error)
+
+	// Unsubscribe stops every future campaign from mailing email.
+	Unsubscribe(ctx context.Context, email string, campaignID uuid.UUID) error
+	// Unsubscribed returns which of emails have unsubscribed.
+	Unsubscribed(ctx context.Context, emails []string) (map[string]bool, error)
+}
@@ -0,0 +1,19 @@
+package comment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Comment is a remark an operator left on a license. Author is the OIDC
+// subject of the operator, AuthorName how they were called when they wrote
+// it.
+type Comment struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	LicenseID  uuid.UUID `db:"license_id" json:"license_id"`
+	Author     string    `db:"author" json:"author"`
+	AuthorName string    `db:"author_name" json:"author_name,omitempty"`
+	Body       string    `db:"body" json:"body"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
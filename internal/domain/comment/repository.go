@@ -0,0 +1,17 @@
+package comment
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// Create fills in ID and CreatedAt.
+	Create(ctx context.Context, c *Comment) error
+	// FindByID returns ierr.ErrNotFound unless the comment is on the license.
+	FindByID(ctx context.Context, licenseID, id uuid.UUID) (*Comment, error)
+	// List returns the comments on a license, oldest first.
+	List(ctx context.Context, licenseID uuid.UUID, limit, offset int) ([]*Comment, int64, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
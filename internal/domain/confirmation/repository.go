@@ -0,0 +1,15 @@
+// Package confirmation holds short-lived tokens that tie the execution of a
+// destructive operation to the preview a caller has seen.
+package confirmation
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Save(ctx context.Context, token string, data []byte, ttl time.Duration) error
+	// Take returns the data saved under token and deletes it, so a token
+	// can be used once. Unknown or expired tokens are ierr.ErrNotFound.
+	Take(ctx context.Context, token string) ([]byte, error)
+}
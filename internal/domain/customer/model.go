@@ -0,0 +1,18 @@
+package customer
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Customer is who licenses are issued to. Licenses keep a copy of Name and
+// Email in their customer_name and customer_email columns, which the
+// repository updates whenever the customer changes.
+type Customer struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Email     string    `db:"email" json:"email"`
+	Name      string    `db:"name" json:"name"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,32 @@
+package customer
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ListParams filters customers. Email and Name match case-insensitive
+// substrings.
+type ListParams struct {
+	Email  *string
+	Name   *string
+	Limit  int
+	Offset int
+}
+
+type Repository interface {
+	// Create fills in ID and timestamps and returns ierr.ErrDuplicateKey
+	// when another customer has the e-mail, in any case.
+	Create(ctx context.Context, c *Customer) error
+	// Ensure returns the customer with the e-mail, creating it with name if
+	// there is none yet. An existing customer keeps its name.
+	Ensure(ctx context.Context, email, name string) (*Customer, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*Customer, error)
+	List(ctx context.Context, params ListParams) ([]*Customer, int64, error)
+	// Update also rewrites the customer copy held by its licenses.
+	Update(ctx context.Context, c *Customer) error
+	// Delete returns ierr.ErrConflict while licenses still reference the
+	// customer.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
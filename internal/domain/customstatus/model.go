@@ -0,0 +1,51 @@
+// Package customstatus holds the license statuses operators define on top of
+// the built-in ones, e.g. on_hold or fraud_review, and how validation treats
+// licenses in them.
+package customstatus
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+)
+
+// Behavior is what validation answers for a license in the status.
+type Behavior string
+
+const (
+	// BehaviorValid validates the license like an active one.
+	BehaviorValid Behavior = "valid"
+	// BehaviorWarn validates it like an active one and adds a warning.
+	BehaviorWarn Behavior = "warn"
+	// BehaviorInvalid denies it with the status name as the reason.
+	BehaviorInvalid Behavior = "invalid"
+)
+
+func (b Behavior) Valid() bool {
+	return b == BehaviorValid || b == BehaviorWarn || b == BehaviorInvalid
+}
+
+type Status struct {
+	Name        license.LicenseStatus `db:"name" json:"name"`
+	Behavior    Behavior              `db:"behavior" json:"behavior"`
+	Description string                `db:"description" json:"description"`
+	CreatedAt   time.Time             `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time             `db:"updated_at" json:"updated_at"`
+}
+
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,47}$`)
+
+// ValidateName checks that name can be used for a new custom status: lower
+// snake case and not one of the built-in statuses.
+func ValidateName(name license.LicenseStatus) error {
+	if !namePattern.MatchString(string(name)) {
+		return fmt.Errorf("%w: status name must be 2-48 lower case letters, digits or underscores, starting with a letter", ierr.ErrValidation)
+	}
+	if name.IsBuiltin() {
+		return fmt.Errorf("%w: %s is a built-in status", ierr.ErrValidation, name)
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package customstatus
+
+import (
+	"context"
+
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+)
+
+type Repository interface {
+	// Create fills in the timestamps and returns ierr.ErrDuplicateKey when
+	// the name is taken.
+	Create(ctx context.Context, s *Status) error
+	// List returns all custom statuses ordered by name.
+	List(ctx context.Context) ([]*Status, error)
+	// Update changes the behavior and description.
+	Update(ctx context.Context, s *Status) error
+	Delete(ctx context.Context, name license.LicenseStatus) error
+}
@@ -0,0 +1,110 @@
+package dashboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+)
+
+type WidgetKind string
+
+const (
+	KindSummary    WidgetKind = "summary"
+	KindTimeseries WidgetKind = "timeseries"
+)
+
+// DefaultOrgID scopes widgets of users whose token carries no organization.
+const DefaultOrgID = "default"
+
+const MaxWidgetsPerOrg = 50
+
+// Widget is one tile or chart on an organization's dashboard. Params holds
+// the kind-specific query parameters that the summary and timeseries
+// endpoints resolve when called with the widget's ID.
+type Widget struct {
+	ID        uuid.UUID       `db:"id" json:"id"`
+	OrgID     string          `db:"org_id" json:"org_id"`
+	Kind      WidgetKind      `db:"kind" json:"kind"`
+	Title     string          `db:"title" json:"title"`
+	Position  int             `db:"position" json:"position"`
+	Params    json.RawMessage `db:"params" json:"params"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+type SummaryParams struct {
+	ExpiringPeriodDays int `json:"expiring_period_days,omitempty"`
+}
+
+// TimeseriesParams describe a license count over time: Field is bucketed by
+// Bucket, optionally filtered like the aggregate endpoint.
+type TimeseriesParams struct {
+	Field       string  `json:"field"`
+	Bucket      string  `json:"bucket"`
+	Metric      string  `json:"metric,omitempty"`
+	Status      *string `json:"status,omitempty"`
+	ProductName *string `json:"product_name,omitempty"`
+	Type        *string `json:"type,omitempty"`
+}
+
+const MaxExpiringPeriodDays = 365
+
+var (
+	timeseriesFields  = map[string]bool{"created_at": true, "issued_at": true, "expires_at": true}
+	timeseriesBuckets = map[string]bool{"day": true, "week": true, "month": true, "year": true}
+	timeseriesMetrics = map[string]bool{"": true, "count": true, "customers": true}
+	licenseStatuses   = map[string]bool{"pending": true, "active": true, "inactive": true, "expired": true, "revoked": true, "suspended": true}
+)
+
+func (p SummaryParams) Validate() error {
+	if p.ExpiringPeriodDays < 0 || p.ExpiringPeriodDays > MaxExpiringPeriodDays {
+		return fmt.Errorf("%w: expiring_period_days must be between 1 and %d", ierr.ErrValidation, MaxExpiringPeriodDays)
+	}
+	return nil
+}
+
+func (p TimeseriesParams) Validate() error {
+	switch {
+	case !timeseriesFields[p.Field]:
+		return fmt.Errorf("%w: timeseries field must be one of created_at, issued_at, expires_at", ierr.ErrValidation)
+	case !timeseriesBuckets[p.Bucket]:
+		return fmt.Errorf("%w: timeseries bucket must be one of day, week, month, year", ierr.ErrValidation)
+	case !timeseriesMetrics[p.Metric]:
+		return fmt.Errorf("%w: timeseries metric must be count or customers", ierr.ErrValidation)
+	case p.Status != nil && !licenseStatuses[*p.Status]:
+		return fmt.Errorf("%w: invalid license status %s", ierr.ErrValidation, *p.Status)
+	}
+	return nil
+}
+
+// DecodeParams checks raw against the parameter schema of kind and returns
+// it re-encoded, so that unknown keys are rejected and never stored.
+func DecodeParams(kind WidgetKind, raw json.RawMessage) (json.RawMessage, error) {
+	if len(bytes.TrimSpace(raw)) == 0 || bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		raw = json.RawMessage("{}")
+	}
+
+	var params interface{ Validate() error }
+	switch kind {
+	case KindSummary:
+		params = &SummaryParams{}
+	case KindTimeseries:
+		params = &TimeseriesParams{}
+	default:
+		return nil, fmt.Errorf("%w: unknown widget kind %q", ierr.ErrValidation, kind)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(params); err != nil {
+		return nil, fmt.Errorf("%w: invalid %s widget params: %v", ierr.ErrValidation, kind, err)
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(params)
+}
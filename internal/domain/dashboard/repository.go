@@ -0,0 +1,18 @@
+package dashboard
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository methods take the organization explicitly; a widget of another
+// organization is reported as ierr.ErrNotFound.
+type Repository interface {
+	Create(ctx context.Context, w *Widget) error
+	FindByID(ctx context.Context, orgID string, id uuid.UUID) (*Widget, error)
+	List(ctx context.Context, orgID string) ([]*Widget, error)
+	Count(ctx context.Context, orgID string) (int, error)
+	Update(ctx context.Context, w *Widget) error
+	Delete(ctx context.Context, orgID string, id uuid.UUID) error
+}
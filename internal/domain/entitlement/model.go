@@ -0,0 +1,102 @@
+// Package entitlement holds the typed features and limits of a license that
+// agents receive as allowed_data on validation.
+package entitlement
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+)
+
+type Type string
+
+const (
+	TypeBoolean Type = "boolean"
+	TypeInteger Type = "integer"
+	TypeString  Type = "string"
+)
+
+func (t Type) Valid() bool {
+	switch t {
+	case TypeBoolean, TypeInteger, TypeString:
+		return true
+	}
+	return false
+}
+
+// Entitlement is a named feature flag or limit of a license. Value is the
+// JSON encoding of a value of Type.
+type Entitlement struct {
+	ID        uuid.UUID       `db:"id" json:"id"`
+	LicenseID uuid.UUID       `db:"license_id" json:"license_id"`
+	Name      string          `db:"name" json:"name"`
+	Type      Type            `db:"type" json:"type"`
+	Value     json.RawMessage `db:"value" json:"value"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+const (
+	MaxNameLength        = 100
+	MaxStringValueLength = 1000
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:-]*$`)
+
+// ValidateName checks names of new entitlements. Entitlements backfilled
+// from license metadata may not follow it.
+func ValidateName(name string) error {
+	if name == "" || len(name) > MaxNameLength {
+		return fmt.Errorf("%w: entitlement name must be 1 to %d characters long", ierr.ErrValidation, MaxNameLength)
+	}
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("%w: entitlement name may only contain letters, digits, '.', ':', '-' and '_' and must start with a letter or digit", ierr.ErrValidation)
+	}
+	return nil
+}
+
+// NormalizeValue checks that value is a JSON value of type t and returns it
+// in compact form.
+func NormalizeValue(t Type, value json.RawMessage) (json.RawMessage, error) {
+	if !t.Valid() {
+		return nil, fmt.Errorf("%w: entitlement type must be one of boolean, integer, string", ierr.ErrValidation)
+	}
+	dec := json.NewDecoder(bytes.NewReader(value))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("%w: entitlement value is not valid JSON", ierr.ErrValidation)
+	}
+
+	switch t {
+	case TypeBoolean:
+		if _, ok := v.(bool); !ok {
+			return nil, fmt.Errorf("%w: value of a boolean entitlement must be true or false", ierr.ErrValidation)
+		}
+	case TypeInteger:
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("%w: value of an integer entitlement must be a number", ierr.ErrValidation)
+		}
+		i, err := strconv.ParseInt(n.String(), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: value of an integer entitlement must be a whole number", ierr.ErrValidation)
+		}
+		v = i
+	case TypeString:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: value of a string entitlement must be a string", ierr.ErrValidation)
+		}
+		if len(s) > MaxStringValueLength {
+			return nil, fmt.Errorf("%w: value of a string entitlement must be at most %d characters long", ierr.ErrValidation, MaxStringValueLength)
+		}
+	}
+	return json.Marshal(v)
+}
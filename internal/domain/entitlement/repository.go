@@ -0,0 +1,16 @@
+package entitlement
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// ListByLicense returns the entitlements of a license ordered by name.
+	ListByLicense(ctx context.Context, licenseID uuid.UUID) ([]*Entitlement, error)
+	// Put creates the entitlement or replaces the type and value of the one
+	// with the same name, filling in ID and timestamps.
+	Put(ctx context.Context, e *Entitlement) error
+	Delete(ctx context.Context, licenseID uuid.UUID, name string) error
+}
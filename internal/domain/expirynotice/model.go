@@ -0,0 +1,27 @@
+package expirynotice
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Kind string
+
+const (
+	// KindReminder notices go out DaysBefore days ahead of expiry.
+	KindReminder Kind = "reminder"
+	// KindExpired notices go out once the license has expired.
+	KindExpired Kind = "expired"
+)
+
+// Notice is an expiry e-mail sent to the customer of a license. Notices are
+// keyed by the expiry date they were sent for, so a renewed license gets
+// its reminders again.
+type Notice struct {
+	LicenseID  uuid.UUID `db:"license_id"`
+	Kind       Kind      `db:"kind"`
+	DaysBefore int       `db:"days_before"`
+	ExpiresAt  time.Time `db:"expires_at"`
+	SentAt     time.Time `db:"sent_at"`
+}
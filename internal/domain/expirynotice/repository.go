@@ -0,0 +1,23 @@
+package expirynotice
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// Claim records n before its e-mail is sent. It returns false when the
+	// notice was recorded before, so each notice is sent at most once even
+	// when several workers run.
+	Claim(ctx context.Context, n *Notice) (bool, error)
+	// Release removes the claim of a notice whose e-mail could not be sent,
+	// so that the next run tries again.
+	Release(ctx context.Context, n *Notice) error
+	ListByLicense(ctx context.Context, licenseID uuid.UUID) ([]*Notice, error)
+
+	OptedOut(ctx context.Context, licenseID uuid.UUID) (bool, error)
+	// OptedOutAmong returns which of the licenses opted out.
+	OptedOutAmong(ctx context.Context, licenseIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+	SetOptOut(ctx context.Context, licenseID uuid.UUID, optOut bool) error
+}
@@ -0,0 +1,34 @@
+// Package extension holds requests to extend the expiry of a license until
+// a user with the approver role grants them.
+package extension
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+)
+
+// Request asks to move the expiry of a license to RequestedExpiresAt.
+// PreviousExpiresAt is the expiry the license had when it was requested.
+type Request struct {
+	ID                 uuid.UUID      `db:"id"`
+	LicenseID          uuid.UUID      `db:"license_id"`
+	RequestedExpiresAt time.Time      `db:"requested_expires_at"`
+	PreviousExpiresAt  time.Time      `db:"previous_expires_at"`
+	Reason             string         `db:"reason"`
+	RequestedBy        string         `db:"requested_by"`
+	Status             Status         `db:"status"`
+	DecidedBy          sql.NullString `db:"decided_by"`
+	DecidedAt          sql.NullTime   `db:"decided_at"`
+	DecisionNote       string         `db:"decision_note"`
+	CreatedAt          time.Time      `db:"created_at"`
+}
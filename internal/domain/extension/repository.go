@@ -0,0 +1,30 @@
+package extension
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type ListParams struct {
+	Status    *Status
+	LicenseID *uuid.UUID
+	Limit     int
+	Offset    int
+}
+
+type Repository interface {
+	// Create fails with ierr.ErrDuplicateKey when the license already has a
+	// pending request.
+	Create(ctx context.Context, r *Request) error
+	FindByID(ctx context.Context, licenseID, id uuid.UUID) (*Request, error)
+	// List returns requests newest first.
+	List(ctx context.Context, params ListParams) ([]*Request, int64, error)
+	// Decide moves a pending request to status. It fails with
+	// ierr.ErrConflict when the request was already decided, so only one
+	// approver wins.
+	Decide(ctx context.Context, id uuid.UUID, status Status, decidedBy, note string) (*Request, error)
+	// Reopen puts an approved request back to pending when the extension
+	// could not be applied.
+	Reopen(ctx context.Context, id uuid.UUID) error
+}
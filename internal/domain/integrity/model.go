@@ -0,0 +1,73 @@
+package integrity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// The invariants an integrity check scans for.
+const (
+	CheckOverdueActive    = "overdue_active"
+	CheckMissingProduct   = "missing_product"
+	CheckOrphanActivation = "orphan_activation"
+	CheckMetadataSchema   = "metadata_schema_violation"
+)
+
+// Repairs a check can be asked to apply to the violations it finds.
+const (
+	// RepairExpireOverdue marks active licenses past their expiry and
+	// grace period expired.
+	RepairExpireOverdue = "expire_overdue"
+	// RepairDeleteOrphanActivations deletes the activations of licenses
+	// that do not exist.
+	RepairDeleteOrphanActivations = "delete_orphan_activations"
+)
+
+var Repairs = []string{RepairExpireOverdue, RepairDeleteOrphanActivations}
+
+// Run is one integrity check. Repairs lists the repairs it applies; without
+// any it only reports.
+type Run struct {
+	ID          uuid.UUID  `db:"id"`
+	Status      Status     `db:"status"`
+	Repairs     []string   `db:"repairs"`
+	RequestedBy string     `db:"requested_by"`
+	Report      *Report    `db:"report"`
+	Error       string     `db:"error"`
+	CreatedAt   time.Time  `db:"created_at"`
+	StartedAt   *time.Time `db:"started_at"`
+	FinishedAt  *time.Time `db:"finished_at"`
+}
+
+// Issue is one invariant violation. Repair names the repair that fixes it,
+// if one exists; Repaired tells whether the run applied it.
+type Issue struct {
+	Check       string `json:"check"`
+	Subject     string `json:"subject"`
+	Message     string `json:"message"`
+	Repair      string `json:"repair,omitempty"`
+	Repaired    bool   `json:"repaired"`
+	RepairError string `json:"repair_error,omitempty"`
+}
+
+// Report counts what was scanned and the violations per check. Only the
+// first MaxIssues violations are listed.
+type Report struct {
+	Scanned   map[string]int64 `json:"scanned"`
+	Counts    map[string]int64 `json:"counts"`
+	Repaired  int64            `json:"repaired"`
+	Issues    []Issue          `json:"issues"`
+	Truncated bool             `json:"truncated"`
+}
+
+const MaxIssues = 1000
@@ -0,0 +1,17 @@
+package integrity
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, run *Run) (uuid.UUID, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*Run, error)
+	// List returns the most recent runs first.
+	List(ctx context.Context, limit int) ([]*Run, error)
+	Start(ctx context.Context, id uuid.UUID) error
+	Finish(ctx context.Context, id uuid.UUID, report *Report) error
+	Fail(ctx context.Context, id uuid.UUID, reason string) error
+}
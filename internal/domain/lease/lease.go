@@ -0,0 +1,33 @@
+// Package lease holds the seat leases of floating licenses: a client checks
+// out a seat for a limited time and checks it in when done. Leases that are
+// neither renewed nor checked in expire and free their seat.
+package lease
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+)
+
+var ErrNoFreeSeat = ierr.ErrConflict.Derive("FLOATING_SEATS_EXHAUSTED", "all floating seats of this license are checked out, try again later")
+
+type Lease struct {
+	LicenseID uuid.UUID
+	ClientID  string
+	ExpiresAt time.Time
+}
+
+type Repository interface {
+	// Checkout leases a seat of the license to clientID for ttl, or extends
+	// the lease the client already holds. It fails with ErrNoFreeSeat when
+	// maxSeats other clients hold unexpired leases, and returns the number
+	// of seats in use afterwards.
+	Checkout(ctx context.Context, licenseID uuid.UUID, clientID string, ttl time.Duration, maxSeats int) (*Lease, int, error)
+	// Checkin releases the client's lease, or returns ierr.ErrNotFound if
+	// it holds none.
+	Checkin(ctx context.Context, licenseID uuid.UUID, clientID string) error
+	// InUse counts the unexpired leases of the license.
+	InUse(ctx context.Context, licenseID uuid.UUID) (int, error)
+}
@@ -0,0 +1,28 @@
+package license
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Archived is a license moved to the archive.
+type Archived struct {
+	License
+	ArchivedAt time.Time
+}
+
+// Archiver moves cold licenses out of the licenses table into the archive.
+// Like Exporter it is served straight from the database, bypassing caches
+// and decorators.
+type Archiver interface {
+	// ArchiveCandidates returns up to limit licenses that are expired or
+	// revoked and unchanged since before cutoff, least recently changed first.
+	// Licenses with children that are not archived yet are left out.
+	ArchiveCandidates(ctx context.Context, cutoff time.Time, limit int) ([]uuid.UUID, error)
+	// Archive moves the licenses of ids that are still candidates for
+	// cutoff and returns the IDs of those moved.
+	Archive(ctx context.Context, ids []uuid.UUID, cutoff time.Time) ([]uuid.UUID, error)
+	FindArchivedByID(ctx context.Context, id uuid.UUID) (*Archived, error)
+}
@@ -0,0 +1,62 @@
+package license
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// childPageSize is how many child licenses are loaded per query when all
+// children of a parent are needed.
+const childPageSize = 1000
+
+// ListChildren returns every child license of parentID.
+func ListChildren(ctx context.Context, repo Repository, parentID uuid.UUID) ([]*License, error) {
+	params := ListParams{
+		ParentID:  &parentID,
+		Limit:     childPageSize,
+		SortBy:    "id",
+		SortOrder: "ASC",
+	}
+	var children []*License
+	for ; ; params.Offset += childPageSize {
+		page, _, err := repo.List(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("repository error listing child licenses of %s: %w", parentID, err)
+		}
+		children = append(children, page...)
+		if len(page) < childPageSize {
+			return children, nil
+		}
+	}
+}
+
+// Seats is MaxActivations, guarding against licenses loaded before
+// max_activations existed.
+func (l *License) Seats() int {
+	if l.MaxActivations < 1 {
+		return DefaultMaxActivations
+	}
+	return l.MaxActivations
+}
+
+// OwnSeats is how many seats of lic its own devices may take. A parent
+// license keeps only the seats its child licenses that are not revoked have
+// not reserved.
+func OwnSeats(ctx context.Context, repo Repository, lic *License) (int, error) {
+	if lic.IsChild() {
+		return lic.Seats(), nil
+	}
+	children, err := ListChildren(ctx, repo, lic.ID)
+	if err != nil {
+		return 0, err
+	}
+	own := lic.Seats()
+	for _, child := range children {
+		if child.Status != StatusRevoked {
+			own -= child.MaxActivations
+		}
+	}
+	return max(own, 0), nil
+}
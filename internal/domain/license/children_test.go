@@ -0,0 +1,59 @@
+package license
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// childRepository serves List from a fixed set of child licenses.
+type childRepository struct {
+	Repository
+	children []*License
+}
+
+func (r *childRepository) List(_ context.Context, params ListParams) ([]*License, int64, error) {
+	var page []*License
+	for _, child := range r.children {
+		if child.ParentID.UUID == *params.ParentID {
+			page = append(page, child)
+		}
+	}
+	return page, int64(len(page)), nil
+}
+
+func TestOwnSeats(t *testing.T) {
+	parent := &License{ID: uuid.New(), MaxActivations: 10}
+	childOf := func(seats int, status LicenseStatus) *License {
+		return &License{ID: uuid.New(), MaxActivations: seats, Status: status, ParentID: uuid.NullUUID{UUID: parent.ID, Valid: true}}
+	}
+	repo := &childRepository{children: []*License{
+		childOf(3, StatusActive),
+		childOf(2, StatusSuspended),
+		childOf(4, StatusRevoked),
+	}}
+
+	cases := []struct {
+		name string
+		lic  *License
+		want int
+	}{
+		{"parent keeps unreserved seats", parent, 5},
+		{"child has its own seats", repo.children[0], 3},
+		{"license without children", &License{ID: uuid.New(), MaxActivations: 2}, 2},
+		{"license loaded without seats", &License{ID: uuid.New()}, DefaultMaxActivations},
+		{"children reserve every seat", &License{ID: parent.ID, MaxActivations: 4}, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := OwnSeats(context.Background(), repo, tc.lic)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("OwnSeats = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
@@ -16,23 +16,96 @@ const (
 	StatusInactive LicenseStatus = "inactive"
 	StatusExpired  LicenseStatus = "expired"
 	StatusRevoked  LicenseStatus = "revoked"
+	// StatusSuspended is set by an operator through suspend and lifted by
+	// reinstate; the reason is kept in the status history.
+	StatusSuspended LicenseStatus = "suspended"
 )
 
 type License struct {
-	ID            uuid.UUID       `db:"id" json:"id"`
-	LicenseKey    string          `db:"license_key" json:"license_key"`
-	Status        LicenseStatus   `db:"status" json:"status"`
-	Type          string          `db:"type" json:"type"`
-	CustomerName  sql.NullString  `db:"customer_name" json:"customer_name,omitempty"`
-	CustomerEmail sql.NullString  `db:"customer_email" json:"customer_email,omitempty"`
-	ProductName   string          `db:"product_name" json:"product_name"`
-	Metadata      json.RawMessage `db:"metadata" json:"metadata,omitempty"`
-	IssuedAt      sql.NullTime    `db:"issued_at" json:"issued_at,omitempty"`
-	ExpiresAt     sql.NullTime    `db:"expires_at" json:"expires_at,omitempty"`
-	CreatedAt     time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt     time.Time       `db:"updated_at" json:"updated_at"`
+	ID         uuid.UUID     `db:"id" json:"id"`
+	LicenseKey string        `db:"license_key" json:"license_key"`
+	Status     LicenseStatus `db:"status" json:"status"`
+	Type       string        `db:"type" json:"type"`
+	// CustomerName and CustomerEmail mirror the customer of CustomerID;
+	// licenses without a customer may still carry a name.
+	CustomerName  sql.NullString `db:"customer_name" json:"customer_name,omitempty"`
+	CustomerEmail sql.NullString `db:"customer_email" json:"customer_email,omitempty"`
+	CustomerID    uuid.NullUUID  `db:"customer_id" json:"customer_id,omitempty"`
+	ProductID     uuid.UUID      `db:"product_id" json:"product_id"`
+	// ProductName mirrors products.name of ProductID; the database keeps the
+	// two in step, so it can be filtered and displayed without a join.
+	ProductName string          `db:"product_name" json:"product_name"`
+	Metadata    json.RawMessage `db:"metadata" json:"metadata,omitempty"`
+	IssuedAt    sql.NullTime    `db:"issued_at" json:"issued_at,omitempty"`
+	ExpiresAt   sql.NullTime    `db:"expires_at" json:"expires_at,omitempty"`
+	// StartsAt schedules the license: until then it is pending and does not
+	// validate; see NotYetActive and Due.
+	StartsAt sql.NullTime `db:"starts_at" json:"starts_at,omitempty"`
+	// MaxActivations is the number of seats: how many devices the license
+	// can be activated on at the same time.
+	MaxActivations int `db:"max_activations" json:"max_activations"`
+	// GracePeriodDays keeps an expired license valid for that many days
+	// after ExpiresAt, so a lapse does not cut customers off at once.
+	GracePeriodDays int `db:"grace_period_days" json:"grace_period_days"`
+	// Tags segment licenses, e.g. by campaign or reseller; see NormalizeTags.
+	Tags []string `db:"tags" json:"tags"`
+	// OperatorNotes is free text for operators and never reaches agents.
+	OperatorNotes string `db:"operator_notes" json:"operator_notes,omitempty"`
+	// RevokedAt is maintained by the database whenever the status becomes
+	// revoked; writes through Create and Update leave it alone.
+	RevokedAt sql.NullTime `db:"revoked_at" json:"revoked_at,omitempty"`
+	// Floating licenses are not bound to devices: MaxActivations limits how
+	// many clients hold a seat lease at once, see package lease.
+	Floating bool `db:"floating" json:"floating"`
+	// ParentID links a child license to the license it was issued from; see
+	// IsChild.
+	ParentID  uuid.NullUUID `db:"parent_id" json:"parent_id,omitempty"`
+	CreatedAt time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at" json:"updated_at"`
 }
 
+// GraceExpiresAt is when the grace period after ExpiresAt ends; ok is false
+// for licenses that never expire.
+func (l *License) GraceExpiresAt() (t time.Time, ok bool) {
+	if !l.ExpiresAt.Valid {
+		return time.Time{}, false
+	}
+	return l.ExpiresAt.Time.UTC().AddDate(0, 0, l.GracePeriodDays), true
+}
+
+// Lapsed reports whether the license is past both ExpiresAt and its grace
+// period at now, i.e. whether it should be marked expired.
+func (l *License) Lapsed(now time.Time) bool {
+	graceEnd, ok := l.GraceExpiresAt()
+	return ok && now.After(graceEnd)
+}
+
+// InGracePeriod reports whether the license has expired at now but is still
+// within its grace period.
+func (l *License) InGracePeriod(now time.Time) bool {
+	return l.ExpiresAt.Valid && now.After(l.ExpiresAt.Time.UTC()) && !l.Lapsed(now)
+}
+
+// NotYetActive reports whether the license starts after now.
+func (l *License) NotYetActive(now time.Time) bool {
+	return l.StartsAt.Valid && now.Before(l.StartsAt.Time.UTC())
+}
+
+// Due reports whether a pending license is scheduled to start at or before
+// now, i.e. whether it should be marked active.
+func (l *License) Due(now time.Time) bool {
+	return l.Status == StatusPending && l.StartsAt.Valid && !l.NotYetActive(now)
+}
+
+// IsChild reports whether the license was issued from a parent license,
+// whose expiry it follows and whose entitlements it inherits.
+func (l *License) IsChild() bool {
+	return l.ParentID.Valid
+}
+
+// DefaultMaxActivations is the seat count of licenses created without one.
+const DefaultMaxActivations = 1
+
 func (l *License) SetMetadata(data interface{}) error {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
@@ -48,3 +121,27 @@ func (l *License) GetMetadata(target interface{}) error {
 	}
 	return json.Unmarshal(l.Metadata, target)
 }
+
+// Locale returns the customer locale kept under the "locale" metadata key,
+// or an empty string when none is set.
+func (l *License) Locale() string {
+	var meta struct {
+		Locale string `json:"locale"`
+	}
+	if len(l.Metadata) == 0 || json.Unmarshal(l.Metadata, &meta) != nil {
+		return ""
+	}
+	return meta.Locale
+}
+
+// Timezone returns the customer's IANA time zone kept under the "timezone"
+// metadata key, or an empty string when none is set.
+func (l *License) Timezone() string {
+	var meta struct {
+		Timezone string `json:"timezone"`
+	}
+	if len(l.Metadata) == 0 || json.Unmarshal(l.Metadata, &meta) != nil {
+		return ""
+	}
+	return meta.Timezone
+}
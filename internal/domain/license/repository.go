@@ -3,20 +3,108 @@ package license
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type ListParams struct {
-	Status        *LicenseStatus
+	Status *LicenseStatus
+	// Statuses selects licenses in any of the statuses.
+	Statuses      []LicenseStatus
 	CustomerEmail *string
+	CustomerID    *uuid.UUID
 	ProductName   *string
-	Type          *string
+	// ProductNames selects licenses of any of the products.
+	ProductNames []string
+	Type         *string
+	// Tag selects licenses carrying the tag; see NormalizeTags.
+	Tag *string
+	// CreatedAfter is inclusive, CreatedBefore exclusive.
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// StartsBefore selects licenses whose starts_at is at or before it.
+	StartsBefore *time.Time
+	// RevokedSince selects licenses revoked at or after it.
+	RevokedSince *time.Time
+	// ExpiresAfter and ExpiresBefore select licenses whose expires_at is at
+	// or after, respectively at or before, them. Licenses that never expire
+	// match neither.
+	ExpiresAfter  *time.Time
+	ExpiresBefore *time.Time
 	Limit         int
 	Offset        int
 	SortBy        string
 	SortOrder     string
+	// Sort, when set, takes precedence over SortBy/SortOrder. Columns are
+	// checked against the repository's sort allowlist.
+	Sort []SortField
+	// ParentID selects the child licenses of a license.
+	ParentID *uuid.UUID
+	// Query selects licenses whose key, customer name, customer e-mail or
+	// a metadata value contains it, ignoring case, or that match it as a
+	// full-text search. Results are ordered by SearchRank first.
+	Query *string
+}
+
+// SearchRank is how well lic matches a search query, lower is better: 0
+// when its key is the query, 1 when its key, customer name or customer
+// e-mail starts with it and 2 for any other match. Repositories order
+// search results the same way.
+func SearchRank(lic *License, query string) int {
+	query = strings.ToLower(query)
+	key := strings.ToLower(lic.LicenseKey)
+	switch {
+	case key == query:
+		return 0
+	case strings.HasPrefix(key, query),
+		lic.CustomerName.Valid && strings.HasPrefix(strings.ToLower(lic.CustomerName.String), query),
+		lic.CustomerEmail.Valid && strings.HasPrefix(strings.ToLower(lic.CustomerEmail.String), query):
+		return 1
+	default:
+		return 2
+	}
+}
+
+type SortField struct {
+	Column string
+	Order  string
+}
+
+const MaxSortFields = 5
+
+// ParseSort parses a spreadsheet-style sort spec such as
+// "status:asc,expires_at:desc". The order defaults to ASC.
+func ParseSort(spec string) ([]SortField, error) {
+	parts := strings.Split(spec, ",")
+	if len(parts) > MaxSortFields {
+		return nil, fmt.Errorf("at most %d sort fields are allowed", MaxSortFields)
+	}
+
+	fields := make([]SortField, 0, len(parts))
+	seen := make(map[string]struct{}, len(parts))
+	for _, part := range parts {
+		column, order, _ := strings.Cut(strings.TrimSpace(part), ":")
+		column = strings.ToLower(strings.TrimSpace(column))
+		order = strings.ToUpper(strings.TrimSpace(order))
+		if column == "" {
+			return nil, fmt.Errorf("empty sort field in %q", spec)
+		}
+		if order == "" {
+			order = "ASC"
+		}
+		if order != "ASC" && order != "DESC" {
+			return nil, fmt.Errorf("invalid sort order %q for %s", order, column)
+		}
+		if _, dup := seen[column]; dup {
+			return nil, fmt.Errorf("duplicate sort field %s", column)
+		}
+		seen[column] = struct{}{}
+		fields = append(fields, SortField{Column: column, Order: order})
+	}
+	return fields, nil
 }
 
 type DashboardSummaryData struct {
@@ -28,10 +116,80 @@ type DashboardSummaryData struct {
 	NextToExpireDate  *time.Time
 	NextToExpireProd  *string
 	ProductCounts     map[string]int64
+	TagCounts         map[string]int64
+}
+
+const (
+	MetricCount     = "count"
+	MetricCustomers = "customers"
+)
+
+// AggregateDimension is one group-by entry: a dimension name such as
+// "product", optionally bucketed ("created_at" by "month").
+type AggregateDimension struct {
+	Name   string
+	Bucket string
+}
+
+func (d AggregateDimension) String() string {
+	if d.Bucket == "" {
+		return d.Name
+	}
+	return d.Name + ":" + d.Bucket
+}
+
+type AggregateParams struct {
+	GroupBy     []AggregateDimension
+	Metric      string
+	Status      *LicenseStatus
+	ProductName *string
+	Type        *string
+}
+
+// AggregateRow holds one group. Group values are in GroupBy order; nil means
+// the column was NULL.
+type AggregateRow struct {
+	Group []*string
+	Value int64
+}
+
+const MaxAggregateDimensions = 4
+
+// ParseGroupBy parses "product,type,created_at:month". Dimension names and
+// buckets are checked by the repository.
+func ParseGroupBy(spec string) ([]AggregateDimension, error) {
+	parts := strings.Split(spec, ",")
+	if len(parts) > MaxAggregateDimensions {
+		return nil, fmt.Errorf("at most %d group_by dimensions are allowed", MaxAggregateDimensions)
+	}
+
+	dims := make([]AggregateDimension, 0, len(parts))
+	seen := make(map[string]struct{}, len(parts))
+	for _, part := range parts {
+		name, bucket, _ := strings.Cut(strings.TrimSpace(part), ":")
+		dim := AggregateDimension{
+			Name:   strings.ToLower(strings.TrimSpace(name)),
+			Bucket: strings.ToLower(strings.TrimSpace(bucket)),
+		}
+		if dim.Name == "" {
+			return nil, fmt.Errorf("empty group_by dimension in %q", spec)
+		}
+		if _, dup := seen[dim.Name]; dup {
+			return nil, fmt.Errorf("duplicate group_by dimension %s", dim.Name)
+		}
+		seen[dim.Name] = struct{}{}
+		dims = append(dims, dim)
+	}
+	return dims, nil
 }
 
 type Repository interface {
 	Create(ctx context.Context, license *License) (uuid.UUID, error)
+	// CreateBatch inserts licenses all or none and fills in their IDs.
+	// Repositories that place licenses by key use one transaction per
+	// placement and delete the stored licenses again on error; any they
+	// fail to delete stay stored and keep their ID.
+	CreateBatch(ctx context.Context, licenses []*License) error
 	FindByID(ctx context.Context, id uuid.UUID) (*License, error)
 	FindByKey(ctx context.Context, key string) (*License, error)
 	List(ctx context.Context, params ListParams) ([]*License, int64, error)
@@ -39,4 +197,24 @@ type Repository interface {
 	Update(ctx context.Context, license *License) error
 	GetDashboardSummary(ctx context.Context, expiringPeriodDays int) (*DashboardSummaryData, error)
 	UpdateMetadata(ctx context.Context, id uuid.UUID, metadata json.RawMessage) error
+	ListRecentlyValidated(ctx context.Context, limit int) ([]*License, error)
+	Aggregate(ctx context.Context, params AggregateParams) ([]AggregateRow, error)
+	// RotateKey gives license id a key from newKey and returns the updated
+	// license. The replaced key keeps resolving through FindByKey until
+	// oldKeyValidUntil. newKey may be called more than once when a
+	// repository places licenses by key.
+	RotateKey(ctx context.Context, id uuid.UUID, newKey func() (string, error), oldKeyValidUntil time.Time) (*License, error)
+	// WithLock runs fn while holding a lock on license id that is shared by
+	// all instances of the service, so changes that read and then write
+	// the children of a license do not interleave. fn must not take the
+	// lock of id again.
+	WithLock(ctx context.Context, id uuid.UUID, fn func(ctx context.Context) error) error
+}
+
+// Exporter streams licenses without loading the whole result set. It is
+// served straight from the database, bypassing caches and decorators, and
+// calls fn for each license; an error from fn stops the export and is
+// returned as is.
+type Exporter interface {
+	Export(ctx context.Context, params ListParams, fn func(*License) error) error
 }
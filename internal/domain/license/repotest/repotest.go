@@ -0,0 +1,719 @@
+// Package repotest is a conformance suite for license.Repository
+// implementations. Backends call Run from their own tests:
+//
+//	func TestPostgresLicenseRepository(t *testing.T) {
+//		repotest.Run(t, func(t *testing.T) license.Repository {
+//			return postgres.NewLicenseRepository(testPool(t), idgen.UUIDGenerator{}, zap.NewNop())
+//		}, func(t *testing.T, name string) uuid.UUID {
+//			return createProduct(t, testPool(t), name)
+//		})
+//	}
+//
+// Every case works inside its own product name, so backends may share one
+// database between cases as long as it starts empty for each Run.
+package repotest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+)
+
+type Factory func(t *testing.T) license.Repository
+
+// ProductFactory registers a product with the given name in the backend and
+// returns its ID. Licenses must reference an existing product.
+type ProductFactory func(t *testing.T, name string) uuid.UUID
+
+func Run(t *testing.T, newRepo Factory, newProduct ProductFactory) {
+	t.Run("CreateAndFind", func(t *testing.T) { testCreateAndFind(t, newRepo(t), newProduct) })
+	t.Run("DuplicateKey", func(t *testing.T) { testDuplicateKey(t, newRepo(t), newProduct) })
+	t.Run("CreateBatch", func(t *testing.T) { testCreateBatch(t, newRepo(t), newProduct) })
+	t.Run("NotFound", func(t *testing.T) { testNotFound(t, newRepo(t), newProduct) })
+	t.Run("Pagination", func(t *testing.T) { testPagination(t, newRepo(t), newProduct) })
+	t.Run("Filters", func(t *testing.T) { testFilters(t, newRepo(t), newProduct) })
+	t.Run("ConcurrentStatusUpdates", func(t *testing.T) { testConcurrentStatusUpdates(t, newRepo(t), newProduct) })
+	t.Run("MetadataUpdates", func(t *testing.T) { testMetadataUpdates(t, newRepo(t), newProduct) })
+	t.Run("ConcurrentMetadataUpdates", func(t *testing.T) { testConcurrentMetadataUpdates(t, newRepo(t), newProduct) })
+	t.Run("RecentlyValidated", func(t *testing.T) { testRecentlyValidated(t, newRepo(t), newProduct) })
+	t.Run("Export", func(t *testing.T) { testExport(t, newRepo(t), newProduct) })
+	t.Run("ExportCancelledMidStream", func(t *testing.T) { testExportCancelled(t, newRepo(t), newProduct) })
+	t.Run("RotateKey", func(t *testing.T) { testRotateKey(t, newRepo(t), newProduct) })
+	t.Run("Children", func(t *testing.T) { testChildren(t, newRepo(t), newProduct) })
+	t.Run("Search", func(t *testing.T) { testSearch(t, newRepo(t), newProduct) })
+}
+
+func testCreateAndFind(t *testing.T, repo license.Repository, newProduct ProductFactory) {
+	ctx := context.Background()
+	product := uniqueProduct(t, newProduct)
+
+	want := &license.License{
+		LicenseKey:    uuid.NewString(),
+		Status:        license.StatusActive,
+		Type:          "pro",
+		CustomerName:  sql.NullString{String: "Acme", Valid: true},
+		CustomerEmail: sql.NullString{String: "ops@acme.test", Valid: true},
+		ProductID:     product.ID,
+		ProductName:   product.Name,
+		Metadata:      json.RawMessage(`{"features":["a","b"]}`),
+		IssuedAt:      sql.NullTime{Time: time.Now().UTC().Truncate(time.Second), Valid: true},
+		ExpiresAt:     sql.NullTime{Time: time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second), Valid: true},
+	}
+
+	id, err := repo.Create(ctx, want)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if id == uuid.Nil {
+		t.Fatal("Create returned a nil ID")
+	}
+
+	byID, err := repo.FindByID(ctx, id)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	byKey, err := repo.FindByKey(ctx, want.LicenseKey)
+	if err != nil {
+		t.Fatalf("FindByKey: %v", err)
+	}
+
+	for name, got := range map[string]*license.License{"FindByID": byID, "FindByKey": byKey} {
+		if got.ID != id || got.LicenseKey != want.LicenseKey || got.Status != want.Status ||
+			got.Type != want.Type || got.ProductID != want.ProductID || got.ProductName != want.ProductName ||
+			got.CustomerName != want.CustomerName || got.CustomerEmail != want.CustomerEmail {
+			t.Errorf("%s returned %+v, want fields of %+v", name, got, want)
+		}
+		if !got.ExpiresAt.Valid || !got.ExpiresAt.Time.Equal(want.ExpiresAt.Time) {
+			t.Errorf("%s expires_at = %v, want %v", name, got.ExpiresAt, want.ExpiresAt)
+		}
+		assertJSONEqual(t, name+" metadata", got.Metadata, want.Metadata)
+		if got.CreatedAt.IsZero() || got.UpdatedAt.IsZero() {
+			t.Errorf("%s did not populate created_at/updated_at", name)
+		}
+	}
+}
+
+func testDuplicateKey(t *testing.T, repo license.Repository, newProduct ProductFactory) {
+	ctx := context.Background()
+	product := uniqueProduct(t, newProduct)
+	lic := newLicense(product)
+
+	if _, err := repo.Create(ctx, lic); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	dup := newLicense(product)
+	dup.LicenseKey = lic.LicenseKey
+	if _, err := repo.Create(ctx, dup); !errors.Is(err, ierr.ErrDuplicateKey) {
+		t.Fatalf("Create with duplicate key: got %v, want ErrDuplicateKey", err)
+	}
+}
+
+func testCreateBatch(t *testing.T, repo license.Repository, newProduct ProductFactory) {
+	ctx := context.Background()
+	product := uniqueProduct(t, newProduct)
+
+	batch := []*license.License{newLicense(product), newLicense(product), newLicense(product)}
+	if err := repo.CreateBatch(ctx, batch); err != nil {
+		t.Fatalf("CreateBatch: %v", err)
+	}
+	for _, lic := range batch {
+		if lic.ID == uuid.Nil {
+			t.Fatalf("CreateBatch left license %s without an ID", lic.LicenseKey)
+		}
+		got, err := repo.FindByKey(ctx, lic.LicenseKey)
+		if err != nil {
+			t.Fatalf("FindByKey(%s): %v", lic.LicenseKey, err)
+		}
+		if got.ID != lic.ID {
+			t.Errorf("FindByKey(%s) ID = %s, want %s", lic.LicenseKey, got.ID, lic.ID)
+		}
+	}
+
+	// A conflict stores none of the batch, even when its licenses go to
+	// different shards; a key given twice in one batch stores neither.
+	fresh := []*license.License{newLicense(product), newLicense(product), newLicense(product), newLicense(product)}
+	taken := newLicense(product)
+	taken.LicenseKey = batch[0].LicenseKey
+	if err := repo.CreateBatch(ctx, append(fresh, taken)); !errors.Is(err, ierr.ErrDuplicateKey) {
+		t.Fatalf("CreateBatch with a stored key: got %v, want ErrDuplicateKey", err)
+	}
+	for _, lic := range fresh {
+		if lic.ID != uuid.Nil {
+			t.Errorf("CreateBatch with a stored key left license %s with ID %s", lic.LicenseKey, lic.ID)
+		}
+		if _, err := repo.FindByKey(ctx, lic.LicenseKey); !errors.Is(err, ierr.ErrNotFound) {
+			t.Errorf("FindByKey(%s) after CreateBatch with a stored key: got %v, want ErrNotFound", lic.LicenseKey, err)
+		}
+	}
+
+	twice := newLicense(product)
+	again := newLicense(product)
+	again.LicenseKey = twice.LicenseKey
+	if err := repo.CreateBatch(ctx, []*license.License{twice, again}); !errors.Is(err, ierr.ErrDuplicateKey) {
+		t.Fatalf("CreateBatch with a key twice: got %v, want ErrDuplicateKey", err)
+	}
+	if twice.ID != uuid.Nil || again.ID != uuid.Nil {
+		t.Errorf("CreateBatch with a key twice gave an ID to %s", twice.LicenseKey)
+	}
+	if _, err := repo.FindByKey(ctx, twice.LicenseKey); !errors.Is(err, ierr.ErrNotFound) {
+		t.Errorf("FindByKey(%s) after CreateBatch with the key twice: got %v, want ErrNotFound", twice.LicenseKey, err)
+	}
+}
+
+func testNotFound(t *testing.T, repo license.Repository, newProduct ProductFactory) {
+	ctx := context.Background()
+	missing := uuid.New()
+
+	if _, err := repo.FindByID(ctx, missing); !errors.Is(err, ierr.ErrNotFound) {
+		t.Errorf("FindByID: got %v, want ErrNotFound", err)
+	}
+	if _, err := repo.FindByKey(ctx, uuid.NewString()); !errors.Is(err, ierr.ErrNotFound) {
+		t.Errorf("FindByKey: got %v, want ErrNotFound", err)
+	}
+	if err := repo.UpdateStatus(ctx, missing, license.StatusRevoked); !errors.Is(err, ierr.ErrNotFound) {
+		t.Errorf("UpdateStatus: got %v, want ErrNotFound", err)
+	}
+	if err := repo.UpdateMetadata(ctx, missing, json.RawMessage(`{}`)); !errors.Is(err, ierr.ErrNotFound) {
+		t.Errorf("UpdateMetadata: got %v, want ErrNotFound", err)
+	}
+
+	lic := newLicense(uniqueProduct(t, newProduct))
+	lic.ID = missing
+	if err := repo.Update(ctx, lic); !errors.Is(err, ierr.ErrNotFound) {
+		t.Errorf("Update: got %v, want ErrNotFound", err)
+	}
+}
+
+func testPagination(t *testing.T, repo license.Repository, newProduct ProductFactory) {
+	ctx := context.Background()
+	product := uniqueProduct(t, newProduct)
+	const total = 7
+
+	created := make(map[uuid.UUID]bool, total)
+	for i := 0; i < total; i++ {
+		id, err := repo.Create(ctx, newLicense(product))
+		if err != nil {
+			t.Fatalf("Create #%d: %v", i, err)
+		}
+		created[id] = true
+	}
+
+	params := license.ListParams{ProductName: &product.Name, SortBy: "id", SortOrder: "ASC"}
+
+	seen := make(map[uuid.UUID]bool, total)
+	var previous *license.License
+	for offset := 0; offset < total; offset += 3 {
+		params.Limit, params.Offset = 3, offset
+		page, count, err := repo.List(ctx, params)
+		if err != nil {
+			t.Fatalf("List offset=%d: %v", offset, err)
+		}
+		if count != total {
+			t.Errorf("List offset=%d total = %d, want %d", offset, count, total)
+		}
+		wantLen := min(3, total-offset)
+		if len(page) != wantLen {
+			t.Errorf("List offset=%d returned %d rows, want %d", offset, len(page), wantLen)
+		}
+		for _, lic := range page {
+			if seen[lic.ID] {
+				t.Errorf("license %s returned on more than one page", lic.ID)
+			}
+			seen[lic.ID] = true
+			if previous != nil && previous.ID.String() > lic.ID.String() {
+				t.Errorf("rows not sorted by id ascending: %s before %s", previous.ID, lic.ID)
+			}
+			previous = lic
+		}
+	}
+	if len(seen) != total {
+		t.Errorf("paging returned %d distinct licenses, want %d", len(seen), total)
+	}
+	for id := range created {
+		if !seen[id] {
+			t.Errorf("license %s never returned while paging", id)
+		}
+	}
+
+	for _, offset := range []int{total, total + 10} {
+		params.Limit, params.Offset = 3, offset
+		page, count, err := repo.List(ctx, params)
+		if err != nil {
+			t.Fatalf("List offset=%d: %v", offset, err)
+		}
+		if len(page) != 0 {
+			t.Errorf("List offset=%d returned %d rows, want none", offset, len(page))
+		}
+		if count != total {
+			t.Errorf("List offset=%d total = %d, want %d", offset, count, total)
+		}
+	}
+
+	empty := uniqueProduct(t, newProduct)
+	page, count, err := repo.List(ctx, license.ListParams{ProductName: &empty.Name, Limit: 10})
+	if err != nil {
+		t.Fatalf("List on empty filter: %v", err)
+	}
+	if page == nil || len(page) != 0 || count != 0 {
+		t.Errorf("List on empty filter = (%v, %d), want empty non-nil slice and 0", page, count)
+	}
+}
+
+func testFilters(t *testing.T, repo license.Repository, newProduct ProductFactory) {
+	ctx := context.Background()
+	product := uniqueProduct(t, newProduct)
+
+	active := newLicense(product)
+	revoked := newLicense(product)
+	revoked.Status = license.StatusRevoked
+	revoked.Type = "trial"
+	for _, lic := range []*license.License{active, revoked} {
+		if _, err := repo.Create(ctx, lic); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	status := license.StatusRevoked
+	page, count, err := repo.List(ctx, license.ListParams{ProductName: &product.Name, Status: &status, Limit: 10})
+	if err != nil {
+		t.Fatalf("List by status: %v", err)
+	}
+	if count != 1 || len(page) != 1 || page[0].LicenseKey != revoked.LicenseKey {
+		t.Errorf("List by status returned %d/%d rows, want only the revoked license", len(page), count)
+	}
+
+	licType := "trial"
+	page, _, err = repo.List(ctx, license.ListParams{ProductName: &product.Name, Type: &licType, Limit: 10})
+	if err != nil {
+		t.Fatalf("List by type: %v", err)
+	}
+	if len(page) != 1 || page[0].Type != "trial" {
+		t.Errorf("List by type returned %d rows, want only the trial license", len(page))
+	}
+
+	_, count, err = repo.List(ctx, license.ListParams{
+		ProductNames: []string{product.Name, "repotest-missing"},
+		Statuses:     []license.LicenseStatus{license.StatusActive, license.StatusRevoked},
+		Limit:        10,
+	})
+	if err != nil {
+		t.Fatalf("List by statuses and products: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("List by statuses and products matched %d licenses, want 2", count)
+	}
+
+	createdAfter := time.Now().Add(-time.Hour)
+	page, _, err = repo.List(ctx, license.ListParams{ProductName: &product.Name, CreatedAfter: &createdAfter, ExpiresAfter: &createdAfter, Limit: 10})
+	if err != nil {
+		t.Fatalf("List by expiry: %v", err)
+	}
+	if len(page) != 0 {
+		t.Errorf("List by expiry returned %d licenses without expires_at, want none", len(page))
+	}
+}
+
+func testConcurrentStatusUpdates(t *testing.T, repo license.Repository, newProduct ProductFactory) {
+	ctx := context.Background()
+	id, err := repo.Create(ctx, newLicense(uniqueProduct(t, newProduct)))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	statuses := []license.LicenseStatus{license.StatusInactive, license.StatusActive, license.StatusExpired, license.StatusRevoked}
+	var wg sync.WaitGroup
+	errs := make(chan error, 4*len(statuses))
+	for i := 0; i < 4; i++ {
+		for _, status := range statuses {
+			wg.Add(1)
+			go func(status license.LicenseStatus) {
+				defer wg.Done()
+				if err := repo.UpdateStatus(ctx, id, status); err != nil {
+					errs <- err
+				}
+			}(status)
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if !errors.Is(err, ierr.ErrConflict) {
+			t.Errorf("concurrent UpdateStatus: %v", err)
+		}
+	}
+
+	// Revoked is terminal, so whichever write came after the first revoke
+	// must not have replaced it.
+	got, err := repo.FindByID(ctx, id)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if got.Status != license.StatusRevoked {
+		t.Errorf("final status = %q, want %q", got.Status, license.StatusRevoked)
+	}
+	if err := repo.UpdateStatus(ctx, id, license.StatusActive); !errors.Is(err, license.ErrInvalidStatusTransition) {
+		t.Errorf("UpdateStatus of revoked license: got %v, want ErrInvalidStatusTransition", err)
+	}
+	got.Status = license.StatusActive
+	if err := repo.Update(ctx, got); !errors.Is(err, license.ErrInvalidStatusTransition) {
+		t.Errorf("Update of revoked license to active: got %v, want ErrInvalidStatusTransition", err)
+	}
+}
+
+func testMetadataUpdates(t *testing.T, repo license.Repository, newProduct ProductFactory) {
+	ctx := context.Background()
+	lic := newLicense(uniqueProduct(t, newProduct))
+	lic.Metadata = json.RawMessage(`{"features":["a"],"limits":{"seats":5}}`)
+	id, err := repo.Create(ctx, lic)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	merged := json.RawMessage(`{"features":["a"],"limits":{"seats":5},"last_validated_at":"2030-01-01T00:00:00Z"}`)
+	if err := repo.UpdateMetadata(ctx, id, merged); err != nil {
+		t.Fatalf("UpdateMetadata: %v", err)
+	}
+	got, err := repo.FindByID(ctx, id)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	assertJSONEqual(t, "merged metadata", got.Metadata, merged)
+
+	byKey, err := repo.FindByKey(ctx, lic.LicenseKey)
+	if err != nil {
+		t.Fatalf("FindByKey: %v", err)
+	}
+	assertJSONEqual(t, "merged metadata by key", byKey.Metadata, merged)
+
+	replaced := json.RawMessage(`{"limits":{"seats":10}}`)
+	if err := repo.UpdateMetadata(ctx, id, replaced); err != nil {
+		t.Fatalf("UpdateMetadata replace: %v", err)
+	}
+	got, err = repo.FindByID(ctx, id)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	assertJSONEqual(t, "replaced metadata", got.Metadata, replaced)
+}
+
+func testConcurrentMetadataUpdates(t *testing.T, repo license.Repository, newProduct ProductFactory) {
+	ctx := context.Background()
+	id, err := repo.Create(ctx, newLicense(uniqueProduct(t, newProduct)))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	const writers = 16
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			payload := json.RawMessage(fmt.Sprintf(`{"writer":%d}`, i))
+			if err := repo.UpdateMetadata(ctx, id, payload); err != nil && !errors.Is(err, ierr.ErrConflict) {
+				t.Errorf("concurrent UpdateMetadata: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	got, err := repo.FindByID(ctx, id)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	var meta struct {
+		Writer *int `json:"writer"`
+	}
+	if err := json.Unmarshal(got.Metadata, &meta); err != nil || meta.Writer == nil || *meta.Writer < 0 || *meta.Writer >= writers {
+		t.Errorf("metadata after concurrent writes = %s, want one complete writer payload", got.Metadata)
+	}
+}
+
+func testRecentlyValidated(t *testing.T, repo license.Repository, newProduct ProductFactory) {
+	ctx := context.Background()
+	product := uniqueProduct(t, newProduct)
+	base := time.Now().UTC().Add(100 * 365 * 24 * time.Hour)
+
+	ids := make([]uuid.UUID, 3)
+	for i := range ids {
+		lic := newLicense(product)
+		lic.Metadata = json.RawMessage(fmt.Sprintf(`{"last_validated_at":%q}`, base.Add(time.Duration(i)*time.Minute).Format(time.RFC3339Nano)))
+		id, err := repo.Create(ctx, lic)
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		ids[i] = id
+	}
+
+	got, err := repo.ListRecentlyValidated(ctx, 2)
+	if err != nil {
+		t.Fatalf("ListRecentlyValidated: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != ids[2] || got[1].ID != ids[1] {
+		t.Errorf("ListRecentlyValidated returned %d licenses, want the two most recent in descending order", len(got))
+	}
+}
+
+func testExport(t *testing.T, repo license.Repository, newProduct ProductFactory) {
+	exporter, ok := repo.(license.Exporter)
+	if !ok {
+		t.Skip("repository does not implement license.Exporter")
+	}
+	ctx := context.Background()
+	product := uniqueProduct(t, newProduct)
+	const total = 5
+
+	created := make(map[uuid.UUID]bool, total)
+	for i := 0; i < total; i++ {
+		id, err := repo.Create(ctx, newLicense(product))
+		if err != nil {
+			t.Fatalf("Create #%d: %v", i, err)
+		}
+		created[id] = true
+	}
+
+	var previous *license.License
+	seen := 0
+	err := exporter.Export(ctx, license.ListParams{ProductName: &product.Name}, func(lic *license.License) error {
+		if !created[lic.ID] {
+			t.Errorf("Export returned license %s outside the filter", lic.ID)
+		}
+		if previous != nil && previous.ID.String() > lic.ID.String() {
+			t.Errorf("export not in id order: %s before %s", previous.ID, lic.ID)
+		}
+		previous = lic
+		seen++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if seen != total {
+		t.Errorf("Export streamed %d licenses, want %d", seen, total)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = exporter.Export(ctx, license.ListParams{ProductName: &product.Name}, func(*license.License) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Export after callback error = %v with %d calls, want the callback error after 1 call", err, calls)
+	}
+}
+
+// testExportCancelled cancels the context while licenses are being
+// streamed, as a disconnecting client does, and expects the export to stop
+// right away instead of draining the result set.
+func testExportCancelled(t *testing.T, repo license.Repository, newProduct ProductFactory) {
+	exporter, ok := repo.(license.Exporter)
+	if !ok {
+		t.Skip("repository does not implement license.Exporter")
+	}
+	product := uniqueProduct(t, newProduct)
+	const total = 5
+	for i := 0; i < total; i++ {
+		if _, err := repo.Create(context.Background(), newLicense(product)); err != nil {
+			t.Fatalf("Create #%d: %v", i, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := 0
+	err := exporter.Export(ctx, license.ListParams{ProductName: &product.Name}, func(*license.License) error {
+		calls++
+		if calls == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Export after cancellation = %v, want context.Canceled", err)
+	}
+	if calls != 2 {
+		t.Errorf("Export called back %d times, want it to stop after the cancelling call (2)", calls)
+	}
+}
+
+func testRotateKey(t *testing.T, repo license.Repository, newProduct ProductFactory) {
+	ctx := context.Background()
+	lic := newLicense(uniqueProduct(t, newProduct))
+	id, err := repo.Create(ctx, lic)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	oldKey := lic.LicenseKey
+
+	newKey := func() (string, error) { return uuid.NewString(), nil }
+	rotated, err := repo.RotateKey(ctx, id, newKey, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("RotateKey: %v", err)
+	}
+	if rotated.ID != id || rotated.LicenseKey == oldKey {
+		t.Fatalf("RotateKey returned license %s with key %q, want %s with a new key", rotated.ID, rotated.LicenseKey, id)
+	}
+
+	for what, key := range map[string]string{"new key": rotated.LicenseKey, "old key in overlap": oldKey} {
+		got, err := repo.FindByKey(ctx, key)
+		if err != nil {
+			t.Fatalf("FindByKey %s: %v", what, err)
+		}
+		if got.ID != id || got.LicenseKey != rotated.LicenseKey {
+			t.Errorf("FindByKey %s: got %s with key %q, want %s with %q", what, got.ID, got.LicenseKey, id, rotated.LicenseKey)
+		}
+	}
+
+	// An overlap that has already ended retires the replaced key at once.
+	replacedKey := rotated.LicenseKey
+	if _, err := repo.RotateKey(ctx, id, newKey, time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("RotateKey without overlap: %v", err)
+	}
+	if _, err := repo.FindByKey(ctx, replacedKey); !errors.Is(err, ierr.ErrNotFound) {
+		t.Errorf("FindByKey after overlap: got %v, want ErrNotFound", err)
+	}
+
+	if _, err := repo.RotateKey(ctx, uuid.New(), newKey, time.Now()); !errors.Is(err, ierr.ErrNotFound) {
+		t.Errorf("RotateKey unknown license: got %v, want ErrNotFound", err)
+	}
+}
+
+func testChildren(t *testing.T, repo license.Repository, newProduct ProductFactory) {
+	ctx := context.Background()
+	product := uniqueProduct(t, newProduct)
+	parentID, err := repo.Create(ctx, newLicense(product))
+	if err != nil {
+		t.Fatalf("Create parent: %v", err)
+	}
+
+	want := map[uuid.UUID]bool{}
+	for i := 0; i < 3; i++ {
+		child := newLicense(product)
+		child.ParentID = uuid.NullUUID{UUID: parentID, Valid: true}
+		id, err := repo.Create(ctx, child)
+		if err != nil {
+			t.Fatalf("Create child: %v", err)
+		}
+		want[id] = true
+	}
+	if _, err := repo.Create(ctx, newLicense(product)); err != nil {
+		t.Fatalf("Create unrelated license: %v", err)
+	}
+
+	children, total, err := repo.List(ctx, license.ListParams{ParentID: &parentID, Limit: 10})
+	if err != nil {
+		t.Fatalf("List children: %v", err)
+	}
+	if total != int64(len(want)) || len(children) != len(want) {
+		t.Fatalf("List children: got %d of %d, want %d", len(children), total, len(want))
+	}
+	for _, child := range children {
+		if !want[child.ID] || !child.IsChild() || child.ParentID.UUID != parentID {
+			t.Errorf("List children: got license %s with parent %v", child.ID, child.ParentID)
+		}
+	}
+
+	parent, err := repo.FindByID(ctx, parentID)
+	if err != nil {
+		t.Fatalf("FindByID parent: %v", err)
+	}
+	if parent.IsChild() {
+		t.Errorf("parent license has parent %s", parent.ParentID.UUID)
+	}
+}
+
+func testSearch(t *testing.T, repo license.Repository, newProduct ProductFactory) {
+	ctx := context.Background()
+	product := uniqueProduct(t, newProduct)
+	marker := "zq" + uuid.NewString()[:8]
+
+	byName := newLicense(product)
+	byName.CustomerName = sql.NullString{String: "Acme " + marker + " Ltd", Valid: true}
+	byEmail := newLicense(product)
+	byEmail.CustomerEmail = sql.NullString{String: marker + "@example.com", Valid: true}
+	byMetadata := newLicense(product)
+	byMetadata.Metadata = json.RawMessage(`{"site": {"host": "build-` + marker + `.example.com"}}`)
+	byKey := newLicense(product)
+	byKey.LicenseKey = marker
+	for _, lic := range []*license.License{byName, byEmail, byMetadata, byKey, newLicense(product)} {
+		if _, err := repo.Create(ctx, lic); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	query := strings.ToUpper(marker)
+	page, total, err := repo.List(ctx, license.ListParams{ProductName: &product.Name, Query: &query, Limit: 10})
+	if err != nil {
+		t.Fatalf("List by query: %v", err)
+	}
+	if total != 4 || len(page) != 4 {
+		t.Fatalf("List by query: got %d of %d, want 4", len(page), total)
+	}
+	if page[0].LicenseKey != marker || page[1].LicenseKey != byEmail.LicenseKey {
+		t.Errorf("List by query: got %s, %s first, want the exact key, then the e-mail prefix match", page[0].LicenseKey, page[1].LicenseKey)
+	}
+
+	words := "ltd acme"
+	page, _, err = repo.List(ctx, license.ListParams{ProductName: &product.Name, Query: &words, Limit: 10})
+	if err != nil {
+		t.Fatalf("List by full-text query: %v", err)
+	}
+	if len(page) != 1 || page[0].LicenseKey != byName.LicenseKey {
+		t.Errorf("List by full-text query returned %d rows, want only the license of Acme Ltd", len(page))
+	}
+
+	wildcard := "%"
+	if _, total, err = repo.List(ctx, license.ListParams{ProductName: &product.Name, Query: &wildcard, Limit: 10}); err != nil || total != 0 {
+		t.Errorf("List by query %q: got %d rows (err %v), want none", wildcard, total, err)
+	}
+}
+
+func newLicense(product testProduct) *license.License {
+	return &license.License{
+		LicenseKey:  uuid.NewString(),
+		Status:      license.StatusActive,
+		Type:        "pro",
+		ProductID:   product.ID,
+		ProductName: product.Name,
+	}
+}
+
+func uniqueProduct(t *testing.T, newProduct ProductFactory) testProduct {
+	t.Helper()
+	name := "repotest-" + uuid.NewString()[:8]
+	return testProduct{ID: newProduct(t, name), Name: name}
+}
+
+type testProduct struct {
+	ID   uuid.UUID
+	Name string
+}
+
+func assertJSONEqual(t *testing.T, what string, got, want json.RawMessage) {
+	t.Helper()
+	var gotValue, wantValue interface{}
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Errorf("%s: invalid JSON %s: %v", what, got, err)
+		return
+	}
+	if err := json.Unmarshal(want, &wantValue); err != nil {
+		t.Errorf("%s: invalid expected JSON %s: %v", what, want, err)
+		return
+	}
+	gotBytes, _ := json.Marshal(gotValue)
+	wantBytes, _ := json.Marshal(wantValue)
+	if string(gotBytes) != string(wantBytes) {
+		t.Errorf("%s = %s, want %s", what, gotBytes, wantBytes)
+	}
+}
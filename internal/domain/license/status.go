@@ -0,0 +1,76 @@
+package license
+
+import "github.com/makkenzo/license-service-api/internal/ierr"
+
+// ErrInvalidStatusTransition is returned when a status change is not in
+// statusTransitions.
+var ErrInvalidStatusTransition = ierr.ErrConflict.Derive("INVALID_STATUS_TRANSITION", "license status transition is not allowed")
+
+// statusTransitions lists the statuses a license can be moved to directly
+// from each status. Revoked is terminal. Suspension, reinstatement and
+// renewal (expired back to active) have their own endpoints and are not
+// listed here.
+var statusTransitions = map[LicenseStatus][]LicenseStatus{
+	StatusPending:   {StatusActive, StatusInactive, StatusRevoked},
+	StatusActive:    {StatusInactive, StatusExpired, StatusRevoked},
+	StatusInactive:  {StatusActive, StatusRevoked},
+	StatusExpired:   {StatusRevoked},
+	StatusSuspended: {StatusRevoked},
+	StatusRevoked:   {},
+}
+
+// Any status not in statusTransitions is a custom status (see package
+// customstatus). A license can be moved to a custom status from
+// customStatusSources, and from a custom status to customStatusTargets or
+// another custom status. Callers check that custom statuses exist.
+var (
+	customStatusSources = []LicenseStatus{StatusPending, StatusActive, StatusInactive}
+	customStatusTargets = []LicenseStatus{StatusActive, StatusInactive, StatusRevoked}
+)
+
+// BuiltinStatuses returns the statuses the service defines, in lifecycle
+// order.
+func BuiltinStatuses() []LicenseStatus {
+	return []LicenseStatus{StatusPending, StatusActive, StatusInactive, StatusSuspended, StatusExpired, StatusRevoked}
+}
+
+// IsBuiltin reports whether s is one of the statuses the service defines.
+func (s LicenseStatus) IsBuiltin() bool {
+	_, ok := statusTransitions[s]
+	return ok
+}
+
+// NextStatuses returns the built-in statuses a license in status s can be
+// moved to. AcceptsCustom tells whether custom statuses are allowed too.
+func (s LicenseStatus) NextStatuses() []LicenseStatus {
+	if !s.IsBuiltin() {
+		return customStatusTargets
+	}
+	return statusTransitions[s]
+}
+
+// AcceptsCustom reports whether a license in status s can be moved to a
+// custom status.
+func (s LicenseStatus) AcceptsCustom() bool {
+	if !s.IsBuiltin() {
+		return true
+	}
+	for _, source := range customStatusSources {
+		if source == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (s LicenseStatus) CanTransitionTo(to LicenseStatus) bool {
+	if !to.IsBuiltin() {
+		return s != to && s.AcceptsCustom()
+	}
+	for _, next := range s.NextStatuses() {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package license
+
+import "testing"
+
+// Custom statuses, see package customstatus.
+const (
+	statusOnHold    LicenseStatus = "on_hold"
+	statusEscalated LicenseStatus = "escalated"
+)
+
+func TestCanTransitionTo(t *testing.T) {
+	allowed := map[LicenseStatus][]LicenseStatus{
+		StatusPending:   {StatusActive, StatusInactive, StatusRevoked, statusOnHold, statusEscalated},
+		StatusActive:    {StatusInactive, StatusExpired, StatusRevoked, statusOnHold, statusEscalated},
+		StatusInactive:  {StatusActive, StatusRevoked, statusOnHold, statusEscalated},
+		StatusExpired:   {StatusRevoked},
+		StatusSuspended: {StatusRevoked},
+		StatusRevoked:   {},
+		statusOnHold:    {StatusActive, StatusInactive, StatusRevoked, statusEscalated},
+	}
+	targets := append(BuiltinStatuses(), statusOnHold, statusEscalated)
+
+	for from, next := range allowed {
+		for _, to := range targets {
+			want := false
+			for _, n := range next {
+				want = want || n == to
+			}
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestRevokedIsTerminal(t *testing.T) {
+	if next := StatusRevoked.NextStatuses(); len(next) != 0 {
+		t.Errorf("revoked has next statuses %v", next)
+	}
+	if StatusRevoked.AcceptsCustom() {
+		t.Error("revoked accepts custom statuses")
+	}
+}
+
+func TestBuiltinStatusesHaveTransitions(t *testing.T) {
+	for _, status := range BuiltinStatuses() {
+		if !status.IsBuiltin() {
+			t.Errorf("%s is listed as built-in but has no transitions", status)
+		}
+	}
+	if statusOnHold.IsBuiltin() {
+		t.Errorf("%s is reported as built-in", statusOnHold)
+	}
+}
@@ -0,0 +1,48 @@
+package license
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/makkenzo/license-service-api/internal/ierr"
+)
+
+const (
+	MaxTags                = 20
+	MaxTagLength           = 64
+	MaxOperatorNotesLength = 4096
+)
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._:-]*$`)
+
+// NormalizeTag is the stored form of tag.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// NormalizeTags lowercases tags, drops duplicates and sorts them, so that
+// filtering by tag does not depend on how an operator spelled it.
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || len(tag) > MaxTagLength {
+			return nil, fmt.Errorf("%w: tags must be 1 to %d characters long", ierr.ErrValidation, MaxTagLength)
+		}
+		if !tagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("%w: tag %q may only contain letters, digits, '.', ':', '-' and '_' and must start with a letter or digit", ierr.ErrValidation, tag)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	if len(normalized) > MaxTags {
+		return nil, fmt.Errorf("%w: a license can have at most %d tags", ierr.ErrValidation, MaxTags)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
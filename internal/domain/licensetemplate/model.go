@@ -0,0 +1,37 @@
+// Package licensetemplate holds reusable presets for creating licenses, so
+// operators pick a template instead of re-entering the same fields for
+// every sale.
+package licensetemplate
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/entitlement"
+)
+
+// Entitlement is copied to every license created from the template.
+type Entitlement struct {
+	Name  string           `json:"name"`
+	Type  entitlement.Type `json:"type"`
+	Value json.RawMessage  `json:"value"`
+}
+
+// Template presets the fields of new licenses; nil fields fall back to the
+// usual defaults. DurationDays sets expires_at relative to creation.
+type Template struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	ProductID uuid.UUID `db:"product_id" json:"product_id"`
+	// ProductName is read from products and ignored on writes.
+	ProductName     string          `db:"product_name" json:"product_name"`
+	Type            string          `db:"type" json:"type"`
+	DurationDays    *int            `db:"duration_days" json:"duration_days,omitempty"`
+	MaxActivations  *int            `db:"max_activations" json:"max_activations,omitempty"`
+	GracePeriodDays *int            `db:"grace_period_days" json:"grace_period_days,omitempty"`
+	Entitlements    []Entitlement   `db:"entitlements" json:"entitlements"`
+	Metadata        json.RawMessage `db:"metadata" json:"metadata,omitempty"`
+	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,19 @@
+package licensetemplate
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// Create fills in ID and timestamps and returns ierr.ErrDuplicateKey
+	// when the name is taken.
+	Create(ctx context.Context, t *Template) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Template, error)
+	// List returns all templates ordered by name, optionally only those of
+	// one product.
+	List(ctx context.Context, productID *uuid.UUID) ([]*Template, error)
+	Update(ctx context.Context, t *Template) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,29 @@
+package note
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Source string
+
+const (
+	// SourceEmail notes are customer replies to notification e-mails.
+	SourceEmail Source = "email"
+)
+
+// Note is a piece of conversation attached to a license, its customer or
+// both. ExternalID identifies the note at its source, e.g. the Message-ID of
+// an ingested e-mail.
+type Note struct {
+	ID         uuid.UUID     `db:"id" json:"id"`
+	LicenseID  uuid.NullUUID `db:"license_id" json:"license_id,omitempty"`
+	CustomerID uuid.NullUUID `db:"customer_id" json:"customer_id,omitempty"`
+	Source     Source        `db:"source" json:"source"`
+	ExternalID *string       `db:"external_id" json:"external_id,omitempty"`
+	Author     string        `db:"author" json:"author"`
+	Subject    string        `db:"subject" json:"subject"`
+	Body       string        `db:"body" json:"body"`
+	CreatedAt  time.Time     `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,22 @@
+package note
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ListParams selects notes of a license or of a customer, newest first.
+type ListParams struct {
+	LicenseID  *uuid.UUID
+	CustomerID *uuid.UUID
+	Limit      int
+	Offset     int
+}
+
+type Repository interface {
+	// Create fills in ID and CreatedAt and returns ierr.ErrDuplicateKey when
+	// a note with the same source and external ID already exists.
+	Create(ctx context.Context, n *Note) error
+	List(ctx context.Context, params ListParams) ([]*Note, int64, error)
+}
@@ -0,0 +1,26 @@
+// Package offlineactivation holds the challenges of the activation flow for
+// air-gapped machines: a challenge is issued for a device, carried to the
+// vendor, and consumed when the vendor signs the activation response.
+package offlineactivation
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Challenge struct {
+	ID         uuid.UUID    `db:"id"`
+	LicenseID  uuid.UUID    `db:"license_id"`
+	DeviceID   string       `db:"device_id"`
+	Nonce      string       `db:"nonce"`
+	CreatedAt  time.Time    `db:"created_at"`
+	ExpiresAt  time.Time    `db:"expires_at"`
+	ConsumedAt sql.NullTime `db:"consumed_at"`
+}
+
+// Usable reports whether the challenge can still be answered at now.
+func (c *Challenge) Usable(now time.Time) bool {
+	return !c.ConsumedAt.Valid && now.Before(c.ExpiresAt)
+}
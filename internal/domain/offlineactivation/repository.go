@@ -0,0 +1,17 @@
+package offlineactivation
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// Create fills in ID and CreatedAt.
+	Create(ctx context.Context, c *Challenge) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Challenge, error)
+	// Consume marks a challenge that is neither consumed nor expired as
+	// consumed. It fails with ierr.ErrConflict otherwise, so a challenge is
+	// answered at most once.
+	Consume(ctx context.Context, id uuid.UUID) (*Challenge, error)
+}
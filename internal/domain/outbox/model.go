@@ -0,0 +1,30 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Operation string
+
+const (
+	OperationInsert Operation = "insert"
+	OperationUpdate Operation = "update"
+	OperationDelete Operation = "delete"
+)
+
+// Event is one committed change to a license row. IDs are strictly
+// increasing, so a consumer only needs to remember the last one it handled.
+type Event struct {
+	ID            int64     `db:"id"`
+	LicenseID     uuid.UUID `db:"license_id"`
+	Operation     Operation `db:"operation"`
+	ChangedFields []string  `db:"changed_fields"`
+	// Status is the license status after the change and PreviousStatus the
+	// one before it; each is empty where the row did not exist. Events
+	// written before migration 000041 have neither.
+	Status         string    `db:"status"`
+	PreviousStatus string    `db:"previous_status"`
+	CreatedAt      time.Time `db:"created_at"`
+}
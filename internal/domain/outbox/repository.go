@@ -0,0 +1,28 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Query selects events after a cursor. When Fields is set, only events that
+// touched at least one of those columns are returned. ProductName and
+// CustomerID match the license as it is now, so events of deleted licenses
+// never match them.
+type Query struct {
+	AfterID     int64
+	Limit       int
+	Fields      []string
+	ProductName *string
+	CustomerID  *uuid.UUID
+}
+
+type Repository interface {
+	ListAfter(ctx context.Context, q Query) ([]*Event, error)
+	// StreamAfter calls fn for every event after q.AfterID, ignoring
+	// q.Limit, without loading them all; an error from fn stops the stream.
+	StreamAfter(ctx context.Context, q Query, fn func(*Event) error) error
+	GetCursor(ctx context.Context, consumer string) (int64, error)
+	SaveCursor(ctx context.Context, consumer string, lastID int64) error
+}
@@ -0,0 +1,111 @@
+package product
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type AgentEnforcement string
+
+const (
+	AgentWarn AgentEnforcement = "warn"
+	AgentDeny AgentEnforcement = "deny"
+)
+
+// AgentPolicy is the minimum agent/SDK version accepted for a product.
+type AgentPolicy struct {
+	ProductName string           `db:"product_name" json:"product_name"`
+	MinVersion  string           `db:"min_version" json:"min_version"`
+	Enforcement AgentEnforcement `db:"enforcement" json:"enforcement"`
+	CreatedAt   time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time        `db:"updated_at" json:"updated_at"`
+}
+
+// Evaluate checks the version an agent reported. Agents that report no
+// version, or one that cannot be parsed, only get a warning even under deny:
+// older agents predate version reporting and must not be locked out blindly.
+func (p *AgentPolicy) Evaluate(agentVersion string) (warning string, deny bool) {
+	if agentVersion == "" {
+		return fmt.Sprintf("agent did not report its version, %s requires %s or newer", p.ProductName, p.MinVersion), false
+	}
+
+	cmp, err := CompareVersions(agentVersion, p.MinVersion)
+	if err != nil {
+		return fmt.Sprintf("agent version %q is not recognised, %s requires %s or newer", agentVersion, p.ProductName, p.MinVersion), false
+	}
+	if cmp >= 0 {
+		return "", false
+	}
+	if p.Enforcement == AgentDeny {
+		return "", true
+	}
+	return fmt.Sprintf("agent version %s is outdated, %s requires %s or newer", agentVersion, p.ProductName, p.MinVersion), false
+}
+
+var ErrInvalidVersion = errors.New("invalid version")
+
+// CompareVersions compares dotted numeric versions such as "1.4", "v2.0.3" or
+// "2.1.0-beta.1". Missing components count as zero and a pre-release sorts
+// before the release it precedes; pre-release labels are compared as strings.
+func CompareVersions(a, b string) (int, error) {
+	aNums, aPre, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	bNums, bPre, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := 0; i < len(aNums) || i < len(bNums); i++ {
+		var x, y uint64
+		if i < len(aNums) {
+			x = aNums[i]
+		}
+		if i < len(bNums) {
+			y = bNums[i]
+		}
+		if x != y {
+			if x < y {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0, nil
+	case aPre == "":
+		return 1, nil
+	case bPre == "":
+		return -1, nil
+	case aPre < bPre:
+		return -1, nil
+	default:
+		return 1, nil
+	}
+}
+
+func parseVersion(v string) ([]uint64, string, error) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	v, _, _ = strings.Cut(v, "+")
+	core, pre, _ := strings.Cut(v, "-")
+	if core == "" {
+		return nil, "", fmt.Errorf("%w: %q", ErrInvalidVersion, v)
+	}
+
+	parts := strings.Split(core, ".")
+	nums := make([]uint64, len(parts))
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: %q", ErrInvalidVersion, v)
+		}
+		nums[i] = n
+	}
+	return nums, pre, nil
+}
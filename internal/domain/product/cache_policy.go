@@ -0,0 +1,15 @@
+package product
+
+import "time"
+
+// CachePolicy tells agents how long they may rely on a validation result of
+// the product, in seconds: RevalidateAfter is when they should validate
+// again, CacheTTL how long the result may stand in for the server when it
+// cannot be reached.
+type CachePolicy struct {
+	ProductName     string    `db:"product_name" json:"product_name"`
+	CacheTTL        int       `db:"cache_ttl" json:"cache_ttl"`
+	RevalidateAfter int       `db:"revalidate_after" json:"revalidate_after"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+}
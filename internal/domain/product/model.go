@@ -0,0 +1,59 @@
+package product
+
+import (
+	"fmt"
+	"time"
+)
+
+type LifecycleState string
+
+const (
+	StateActive     LifecycleState = "active"
+	StateDeprecated LifecycleState = "deprecated"
+	StateEOL        LifecycleState = "eol"
+)
+
+// EOLBehavior decides what license validation does for an EOL product once
+// its EOL date has passed. Before that date validation only warns.
+type EOLBehavior string
+
+const (
+	EOLWarn EOLBehavior = "warn"
+	EOLDeny EOLBehavior = "deny"
+)
+
+type Lifecycle struct {
+	ProductName      string         `db:"product_name" json:"product_name"`
+	State            LifecycleState `db:"state" json:"state"`
+	EOLDate          *time.Time     `db:"eol_date" json:"eol_date,omitempty"`
+	EOLBehavior      EOLBehavior    `db:"eol_behavior" json:"eol_behavior"`
+	MigrationProduct *string        `db:"migration_product" json:"migration_product,omitempty"`
+	MigrationOffer   string         `db:"migration_offer" json:"migration_offer"`
+	CreatedAt        time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+// Evaluate returns the warning to attach to a successful validation, and
+// whether validation must be denied altogether at now.
+func (l *Lifecycle) Evaluate(now time.Time) (warning string, deny bool) {
+	switch l.State {
+	case StateDeprecated:
+		warning = fmt.Sprintf("product %s is deprecated", l.ProductName)
+	case StateEOL:
+		if l.EOLDate == nil || !now.Before(*l.EOLDate) {
+			if l.EOLBehavior == EOLDeny {
+				return "", true
+			}
+			warning = fmt.Sprintf("product %s has reached end of life", l.ProductName)
+		} else {
+			warning = fmt.Sprintf("product %s reaches end of life on %s", l.ProductName, l.EOLDate.UTC().Format("2006-01-02"))
+		}
+	default:
+		return "", false
+	}
+
+	if l.MigrationProduct != nil && *l.MigrationProduct != "" {
+		warning += fmt.Sprintf(", migrate to %s", *l.MigrationProduct)
+	}
+	return warning, false
+}
@@ -0,0 +1,69 @@
+package product
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+)
+
+// Product is what licenses and API keys are issued for. Name is the stable
+// identifier agents send when validating; DisplayName is for people.
+// KeyFormat and KeyPrefix shape the keys of new licenses, see
+// package licensekey; an empty KeyFormat means UUID keys.
+type Product struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	DisplayName string    `db:"display_name" json:"display_name"`
+	Description string    `db:"description" json:"description"`
+	KeyFormat   string    `db:"key_format" json:"key_format"`
+	KeyPrefix   string    `db:"key_prefix" json:"key_prefix"`
+	// CustomerLicenseCaps limits how many licenses one customer may hold,
+	// by license type, e.g. {"trial": 1, "personal": 5}. The
+	// AllLicenseTypes entry caps all types together.
+	CustomerLicenseCaps map[string]int `db:"customer_license_caps" json:"customer_license_caps"`
+	// MetadataSchema is the JSON Schema license metadata must match; nil
+	// accepts any metadata.
+	MetadataSchema json.RawMessage `db:"metadata_schema" json:"metadata_schema,omitempty"`
+	CreatedAt      time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+// AllLicenseTypes is the CustomerLicenseCaps key for a cap across types.
+const AllLicenseTypes = "*"
+
+// ErrCustomerLicenseCap is returned when creating a license would give a
+// customer more licenses of the product than its caps allow.
+var ErrCustomerLicenseCap = ierr.ErrConflict.Derive("CUSTOMER_LICENSE_CAP_EXCEEDED", "customer already holds the maximum number of licenses allowed for this product")
+
+func ValidateCustomerLicenseCaps(caps map[string]int) error {
+	for licenseType, max := range caps {
+		if licenseType == "" || len(licenseType) > 100 {
+			return fmt.Errorf("%w: customer_license_caps keys must be license types of 1 to 100 characters", ierr.ErrValidation)
+		}
+		if max < 1 {
+			return fmt.Errorf("%w: customer license cap for %q must be at least 1", ierr.ErrValidation, licenseType)
+		}
+	}
+	return nil
+}
+
+const MaxNameLength = 100
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidateName checks names of new products. Names appear in URLs and agent
+// configuration, so they are limited to letters, digits, dots, dashes and
+// underscores. Products backfilled from older licenses may not follow it.
+func ValidateName(name string) error {
+	if name == "" || len(name) > MaxNameLength {
+		return fmt.Errorf("%w: product name must be 1 to %d characters long", ierr.ErrValidation, MaxNameLength)
+	}
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("%w: product name may only contain letters, digits, '.', '-' and '_' and must start with a letter or digit", ierr.ErrValidation)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package product
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// CreateProduct fills in ID and timestamps and returns
+	// ierr.ErrDuplicateKey when the name is taken.
+	CreateProduct(ctx context.Context, p *Product) error
+	FindProductByID(ctx context.Context, id uuid.UUID) (*Product, error)
+	FindProductByName(ctx context.Context, name string) (*Product, error)
+	ListProducts(ctx context.Context) ([]*Product, error)
+	UpdateProduct(ctx context.Context, p *Product) error
+	// DeleteProduct returns ierr.ErrConflict while licenses, API keys or
+	// license templates still reference the product.
+	DeleteProduct(ctx context.Context, id uuid.UUID) error
+
+	// FindLifecycle returns ierr.ErrNotFound for products without a lifecycle
+	// record; such products are treated as active.
+	FindLifecycle(ctx context.Context, productName string) (*Lifecycle, error)
+	ListLifecycles(ctx context.Context) ([]*Lifecycle, error)
+	UpsertLifecycle(ctx context.Context, lifecycle *Lifecycle) error
+
+	// FindAgentPolicy returns ierr.ErrNotFound for products without a minimum
+	// agent version.
+	FindAgentPolicy(ctx context.Context, productName string) (*AgentPolicy, error)
+	ListAgentPolicies(ctx context.Context) ([]*AgentPolicy, error)
+	UpsertAgentPolicy(ctx context.Context, policy *AgentPolicy) error
+	DeleteAgentPolicy(ctx context.Context, productName string) error
+
+	// FindCachePolicy returns ierr.ErrNotFound for products without cache
+	// hints.
+	FindCachePolicy(ctx context.Context, productName string) (*CachePolicy, error)
+	UpsertCachePolicy(ctx context.Context, policy *CachePolicy) error
+	DeleteCachePolicy(ctx context.Context, productName string) error
+}
@@ -0,0 +1,32 @@
+package renewal
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConsentText is stored with every accepted offer so that the exact wording
+// the customer agreed to can be produced later.
+const ConsentText = "I agree to renew this license on the terms of this offer."
+
+type Offer struct {
+	ID            uuid.UUID `db:"id"`
+	LicenseID     uuid.UUID `db:"license_id"`
+	ExtendDays    int       `db:"extend_days"`
+	LinkExpiresAt time.Time `db:"link_expires_at"`
+	CreatedBy     string    `db:"created_by"`
+	CreatedAt     time.Time `db:"created_at"`
+	Consent       *Consent
+}
+
+type Consent struct {
+	AcceptedAt time.Time `db:"accepted_at"`
+	IP         string    `db:"accepted_ip"`
+	UserAgent  string    `db:"accepted_user_agent"`
+	Text       string    `db:"consent_text"`
+}
+
+func (o *Offer) Accepted() bool {
+	return o.Consent != nil
+}
@@ -0,0 +1,18 @@
+package renewal
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, offer *Offer) (uuid.UUID, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*Offer, error)
+	// Accept records consent on a pending offer. It fails with ierr.ErrConflict
+	// when the offer was already accepted, so a link can only be used once.
+	Accept(ctx context.Context, id uuid.UUID, consent *Consent) error
+	// RevokeAcceptance clears the consent again when the renewal itself could
+	// not be applied.
+	RevokeAcceptance(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,28 @@
+package signingkey
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Status string
+
+const (
+	// StatusActive marks the one key new license files are signed with.
+	StatusActive Status = "active"
+	// StatusRetired keys no longer sign but stay published so that files
+	// signed before a rotation keep verifying.
+	StatusRetired Status = "retired"
+)
+
+// Key describes a signing key without its private half: KeyRef tells the
+// crypto provider where to find it and PublicKey is the PKIX DER encoding.
+type Key struct {
+	KID       string       `db:"kid" json:"kid"`
+	Algorithm string       `db:"algorithm" json:"algorithm"`
+	KeyRef    string       `db:"key_ref" json:"key_ref"`
+	PublicKey []byte       `db:"public_key" json:"-"`
+	Status    Status       `db:"status" json:"status"`
+	CreatedAt time.Time    `db:"created_at" json:"created_at"`
+	RetiredAt sql.NullTime `db:"retired_at" json:"retired_at,omitempty"`
+}
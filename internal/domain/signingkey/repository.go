@@ -0,0 +1,11 @@
+package signingkey
+
+import "context"
+
+type Repository interface {
+	// Rotate stores k as the active key and retires the previous one in the
+	// same transaction. A kid that is already known is ierr.ErrDuplicateKey.
+	Rotate(ctx context.Context, k *Key) error
+	// List returns all keys, newest first.
+	List(ctx context.Context) ([]*Key, error)
+}
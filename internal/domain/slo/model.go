@@ -0,0 +1,25 @@
+package slo
+
+import "time"
+
+// Bucket resolutions. Counts are kept per minute for short windows and per
+// hour for long ones.
+const (
+	Minute = time.Minute
+	Hour   = time.Hour
+)
+
+// Counts are the requests to the tracked endpoint seen in a bucket. Errors
+// are requests answered with a 5xx status; Slow are the other requests that
+// took longer than the latency threshold.
+type Counts struct {
+	Requests int64
+	Errors   int64
+	Slow     int64
+}
+
+func (c *Counts) Add(o Counts) {
+	c.Requests += o.Requests
+	c.Errors += o.Errors
+	c.Slow += o.Slow
+}
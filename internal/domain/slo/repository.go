@@ -0,0 +1,15 @@
+package slo
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// Add adds counts keyed by the minute they were seen in to both the
+	// minute and the hour buckets.
+	Add(ctx context.Context, counts map[time.Time]Counts) error
+	// Sum adds up the buckets of resolution that start in [from, to), with
+	// from truncated to the resolution.
+	Sum(ctx context.Context, resolution time.Duration, from, to time.Time) (Counts, error)
+}
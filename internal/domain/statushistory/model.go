@@ -0,0 +1,20 @@
+package statushistory
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+)
+
+// Entry records one status change of a license made by an operator, with
+// the reason they gave.
+type Entry struct {
+	ID         uuid.UUID             `db:"id" json:"id"`
+	LicenseID  uuid.UUID             `db:"license_id" json:"license_id"`
+	FromStatus license.LicenseStatus `db:"from_status" json:"from_status"`
+	ToStatus   license.LicenseStatus `db:"to_status" json:"to_status"`
+	Reason     string                `db:"reason" json:"reason"`
+	ChangedBy  string                `db:"changed_by" json:"changed_by"`
+	CreatedAt  time.Time             `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package statushistory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+)
+
+type Repository interface {
+	Record(ctx context.Context, entry *Entry) error
+	// Latest returns the most recent change of the license into status, or
+	// ierr.ErrNotFound if there is none.
+	Latest(ctx context.Context, licenseID uuid.UUID, status license.LicenseStatus) (*Entry, error)
+	// LatestFor is Latest for several licenses at once. Licenses without
+	// such a change are missing from the result.
+	LatestFor(ctx context.Context, licenseIDs []uuid.UUID, status license.LicenseStatus) (map[uuid.UUID]*Entry, error)
+}
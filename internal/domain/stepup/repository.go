@@ -0,0 +1,32 @@
+package stepup
+
+import (
+	"context"
+	"time"
+)
+
+type TOTPRepository interface {
+	// Find returns ierr.ErrNotFound for users without a secret.
+	Find(ctx context.Context, subject string) (*TOTPSecret, error)
+	// Put stores the secret of a user, replacing an earlier one.
+	Put(ctx context.Context, s *TOTPSecret) error
+	// Use records that the code of counter was accepted. It returns
+	// ierr.ErrConflict when a code of that step or a later one was accepted
+	// before, so every code works once.
+	Use(ctx context.Context, subject string, counter int64) error
+}
+
+type GrantRepository interface {
+	// Save records that subject passed a challenge at the given time; the
+	// record is kept for ttl.
+	Save(ctx context.Context, subject string, at time.Time, ttl time.Duration) error
+	// Find returns when subject last passed a challenge, or
+	// ierr.ErrNotFound when that record has expired.
+	Find(ctx context.Context, subject string) (time.Time, error)
+	// Failures returns how many challenges subject failed in the current
+	// window.
+	Failures(ctx context.Context, subject string) (int64, error)
+	// AddFailure counts a failed challenge. The count is dropped window
+	// after the last failure.
+	AddFailure(ctx context.Context, subject string, window time.Duration) error
+}
@@ -0,0 +1,15 @@
+// Package stepup holds what step-up authentication remembers: the TOTP
+// secrets users enrolled and who recently passed a challenge.
+package stepup
+
+import "time"
+
+// TOTPSecret is the TOTP secret of a user, keyed by OIDC subject.
+type TOTPSecret struct {
+	Subject string
+	Secret  []byte
+	// LastCounter is the time step of the last accepted code. Codes of that
+	// step or an earlier one are not accepted again.
+	LastCounter int64
+	CreatedAt   time.Time
+}
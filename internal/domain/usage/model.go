@@ -0,0 +1,62 @@
+// Package usage holds the metered usage agents report for a license, such
+// as API calls or exported documents.
+package usage
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+)
+
+// LimitPrefix names the integer entitlements that cap usage: an
+// entitlement "usage.api_calls" of 1000 allows 1000 api_calls per calendar
+// month (UTC).
+const LimitPrefix = "usage."
+
+const MaxMetricLength = 100
+
+var metricPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:-]*$`)
+
+func ValidateMetric(metric string) error {
+	if metric == "" || len(metric) > MaxMetricLength {
+		return fmt.Errorf("%w: usage metric must be 1 to %d characters long", ierr.ErrValidation, MaxMetricLength)
+	}
+	if !metricPattern.MatchString(metric) {
+		return fmt.Errorf("%w: usage metric may only contain letters, digits, '.', ':', '-' and '_' and must start with a letter or digit", ierr.ErrValidation)
+	}
+	return nil
+}
+
+type Period string
+
+const (
+	PeriodDay   Period = "day"
+	PeriodMonth Period = "month"
+)
+
+// Start returns the start of the period containing t, in UTC.
+func (p Period) Start(t time.Time) time.Time {
+	t = t.UTC()
+	if p == PeriodMonth {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Event is one usage report: Quantity units of Metric used at OccurredAt.
+type Event struct {
+	Metric     string
+	Quantity   int64
+	OccurredAt time.Time
+}
+
+// Counter is the usage of one metric of a license in one period.
+type Counter struct {
+	LicenseID   uuid.UUID `db:"license_id" json:"license_id"`
+	Metric      string    `db:"metric" json:"metric"`
+	PeriodStart time.Time `db:"period_start" json:"period_start"`
+	Quantity    int64     `db:"quantity" json:"quantity"`
+}
@@ -0,0 +1,27 @@
+package usage
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ListParams selects counters of one license summed per Period, oldest
+// first. From is inclusive, To exclusive.
+type ListParams struct {
+	LicenseID uuid.UUID
+	Metric    *string
+	Period    Period
+	From      *time.Time
+	To        *time.Time
+}
+
+type Repository interface {
+	// Record adds the events to the daily counters of the license.
+	Record(ctx context.Context, licenseID uuid.UUID, events []Event) error
+	List(ctx context.Context, params ListParams) ([]*Counter, error)
+	// Totals returns the usage of the license per metric since the given
+	// time, which should be the start of a day.
+	Totals(ctx context.Context, licenseID uuid.UUID, since time.Time) (map[string]int64, error)
+}
@@ -0,0 +1,30 @@
+package validationevent
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is the outcome of one license validation. Successful validations
+// may be sampled: SampleRate is how many validations the event stands for,
+// so counts are estimated by summing it rather than counting rows.
+type Event struct {
+	ID           uuid.UUID     `db:"id" json:"id"`
+	LicenseID    uuid.NullUUID `db:"license_id" json:"license_id,omitempty"`
+	ProductName  string        `db:"product_name" json:"product_name"`
+	Valid        bool          `db:"valid" json:"valid"`
+	Reason       string        `db:"reason" json:"reason"`
+	AgentVersion string        `db:"agent_version" json:"agent_version,omitempty"`
+	DeviceID     string        `db:"device_id" json:"device_id,omitempty"`
+	IPAddress    string        `db:"ip_address" json:"ip_address,omitempty"`
+	SampleRate   int           `db:"sample_rate" json:"sample_rate"`
+	// ASN, ASOrg, Country, UserAgent and TLSFingerprint describe the
+	// request the validation came in, see package enrichment.
+	ASN            int64     `db:"asn" json:"asn,omitempty"`
+	ASOrg          string    `db:"as_org" json:"as_org,omitempty"`
+	Country        string    `db:"country" json:"country,omitempty"`
+	UserAgent      string    `db:"user_agent" json:"user_agent,omitempty"`
+	TLSFingerprint string    `db:"tls_fingerprint" json:"tls_fingerprint,omitempty"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
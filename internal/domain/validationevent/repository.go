@@ -0,0 +1,23 @@
+package validationevent
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ListParams selects events, newest first.
+type ListParams struct {
+	LicenseID   *uuid.UUID
+	ProductName *string
+	Valid       *bool
+	Country     *string
+	Limit       int
+	Offset      int
+}
+
+type Repository interface {
+	// Record fills in ID and CreatedAt.
+	Record(ctx context.Context, e *Event) error
+	List(ctx context.Context, params ListParams) ([]*Event, int64, error)
+}
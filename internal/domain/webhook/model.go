@@ -0,0 +1,134 @@
+package webhook
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	EventLicenseCreated   EventType = "license.created"
+	EventLicenseExpired   EventType = "license.expired"
+	EventLicenseRevoked   EventType = "license.revoked"
+	EventValidationFailed EventType = "validation.failed"
+)
+
+// Events lists every event type subscriptions can ask for.
+var Events = []EventType{EventLicenseCreated, EventLicenseExpired, EventLicenseRevoked, EventValidationFailed}
+
+// Subscription is an endpoint that receives the events it subscribed to,
+// signed with its Secret.
+type Subscription struct {
+	ID          uuid.UUID   `db:"id"`
+	URL         string      `db:"url"`
+	Secret      string      `db:"secret"`
+	Events      []string    `db:"events"`
+	Description string      `db:"description"`
+	IsEnabled   bool        `db:"is_enabled"`
+	RetryPolicy RetryPolicy `db:"-"`
+	Health      Health      `db:"-"`
+	CreatedBy   string      `db:"created_by"`
+	CreatedAt   time.Time   `db:"created_at"`
+	UpdatedAt   time.Time   `db:"updated_at"`
+}
+
+// RetryPolicy overrides the server's delivery defaults for one
+// subscription. Nil fields use the defaults.
+type RetryPolicy struct {
+	MaxAttempts           *int `db:"max_attempts"`
+	InitialBackoffSeconds *int `db:"initial_backoff_seconds"`
+	MaxBackoffSeconds     *int `db:"max_backoff_seconds"`
+	TimeoutSeconds        *int `db:"timeout_seconds"`
+}
+
+// Policy is the delivery policy in effect for a subscription.
+type Policy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Timeout        time.Duration
+}
+
+// Backoff returns how long to wait after the given failed attempt, counted
+// from 1: InitialBackoff doubled for every earlier attempt, up to
+// MaxBackoff.
+func (p Policy) Backoff(attempt int) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < attempt && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxBackoff)
+}
+
+type HealthStatus string
+
+const (
+	HealthHealthy  HealthStatus = "healthy"
+	HealthFailing  HealthStatus = "failing"
+	HealthDisabled HealthStatus = "disabled"
+)
+
+// Health tracks the outcome of a subscription's delivery attempts.
+// FailingSince is the first failure since the last success; a subscription
+// failing for too long is disabled with DisabledReason.
+type Health struct {
+	ConsecutiveFailures int        `db:"consecutive_failures"`
+	FailingSince        *time.Time `db:"failing_since"`
+	LastSuccessAt       *time.Time `db:"last_success_at"`
+	LastFailureAt       *time.Time `db:"last_failure_at"`
+	DisabledReason      string     `db:"disabled_reason"`
+	DisabledAt          *time.Time `db:"disabled_at"`
+}
+
+func (s *Subscription) HealthStatus() HealthStatus {
+	switch {
+	case !s.IsEnabled:
+		return HealthDisabled
+	case s.Health.FailingSince != nil:
+		return HealthFailing
+	default:
+		return HealthHealthy
+	}
+}
+
+func (s *Subscription) Wants(event EventType) bool {
+	return s.IsEnabled && slices.Contains(s.Events, string(event))
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Delivery is one event sent to one subscription. It stays pending while
+// attempts are being retried.
+type Delivery struct {
+	ID             uuid.UUID       `db:"id"`
+	SubscriptionID uuid.UUID       `db:"subscription_id"`
+	EventID        uuid.UUID       `db:"event_id"`
+	Event          EventType       `db:"event"`
+	Payload        json.RawMessage `db:"payload"`
+	Status         DeliveryStatus  `db:"status"`
+	Attempts       int             `db:"attempts"`
+	// ResponseStatus is the HTTP status of the last attempt, 0 when it got
+	// no answer.
+	ResponseStatus int        `db:"response_status"`
+	LastError      string     `db:"last_error"`
+	CreatedAt      time.Time  `db:"created_at"`
+	LastAttemptAt  *time.Time `db:"last_attempt_at"`
+}
+
+// Attempt is the outcome of one delivery attempt.
+type Attempt struct {
+	Status         DeliveryStatus
+	ResponseStatus int
+	Error          string
+	At             time.Time
+}
@@ -0,0 +1,33 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, s *Subscription) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Subscription, error)
+	List(ctx context.Context) ([]*Subscription, error)
+	// Update enabling a disabled subscription also resets its health, so
+	// the endpoint gets a fresh start.
+	Update(ctx context.Context, s *Subscription) error
+	// Delete deletes the subscription and its deliveries.
+	Delete(ctx context.Context, id uuid.UUID) error
+	// RecordHealth records the outcome of a delivery attempt at the given
+	// time and returns the subscription's health after it.
+	RecordHealth(ctx context.Context, id uuid.UUID, succeeded bool, at time.Time) (*Health, error)
+	// Disable disables an enabled subscription for reason. It reports
+	// whether the subscription was enabled, so only one caller alerts.
+	Disable(ctx context.Context, id uuid.UUID, reason string, at time.Time) (bool, error)
+
+	CreateDelivery(ctx context.Context, d *Delivery) error
+	FindDelivery(ctx context.Context, id uuid.UUID) (*Delivery, error)
+	// RecordAttempt counts an attempt and sets the delivery's status to its
+	// outcome.
+	RecordAttempt(ctx context.Context, id uuid.UUID, a Attempt) error
+	// ListDeliveries returns a subscription's most recent deliveries first.
+	ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]*Delivery, error)
+}
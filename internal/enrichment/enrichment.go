@@ -0,0 +1,74 @@
+// Package enrichment adds network context to agent requests: the autonomous
+// system and country the client connects from, its user agent and TLS
+// fingerprint. Providers each fill in what they know; the result travels
+// with the request context and is stored with validation events, where
+// anomaly detection and geo restrictions can use it.
+package enrichment
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+
+	"go.uber.org/zap"
+)
+
+// Info is the context of one request. Zero values mean unknown.
+type Info struct {
+	ASN   int64
+	ASOrg string
+	// Country is an ISO 3166-1 alpha-2 code in upper case.
+	Country        string
+	UserAgent      string
+	TLSFingerprint string
+}
+
+type Request struct {
+	IP        netip.Addr
+	UserAgent string
+	Header    http.Header
+}
+
+// Provider looks up part of the context of a request. Enrich only fills in
+// fields of info that are still empty, so earlier providers take
+// precedence.
+type Provider interface {
+	Name() string
+	Enrich(ctx context.Context, req *Request, info *Info) error
+}
+
+// Enricher runs the configured providers in order. A failing provider is
+// logged and skipped; enrichment never fails a request.
+type Enricher struct {
+	providers []Provider
+	logger    *zap.Logger
+}
+
+func NewEnricher(providers []Provider, logger *zap.Logger) *Enricher {
+	return &Enricher{
+		providers: providers,
+		logger:    logger.Named("Enricher"),
+	}
+}
+
+func (e *Enricher) Enrich(ctx context.Context, req *Request) *Info {
+	info := &Info{UserAgent: req.UserAgent}
+	for _, p := range e.providers {
+		if err := p.Enrich(ctx, req, info); err != nil {
+			e.logger.Debug("Enrichment provider failed", zap.String("provider", p.Name()), zap.String("ip", req.IP.String()), zap.Error(err))
+		}
+	}
+	return info
+}
+
+type infoKey struct{}
+
+func WithInfo(ctx context.Context, info *Info) context.Context {
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+// InfoFrom returns the context attached by WithInfo, or nil.
+func InfoFrom(ctx context.Context) *Info {
+	info, _ := ctx.Value(infoKey{}).(*Info)
+	return info
+}
@@ -0,0 +1,51 @@
+package enrichment
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+)
+
+// HeaderProvider reads the context an edge proxy or CDN in front of the
+// service attaches to requests, e.g. Cloudflare's CF-IPCountry. The headers
+// are trusted as they are, so it must only be configured when clients
+// cannot reach the service around the proxy. Headers left empty in the
+// configuration are not read.
+type HeaderProvider struct {
+	country        string
+	asn            string
+	tlsFingerprint string
+}
+
+func NewHeaderProvider(cfg *config.EnrichmentConfig) *HeaderProvider {
+	return &HeaderProvider{
+		country:        cfg.CountryHeader,
+		asn:            cfg.ASNHeader,
+		tlsFingerprint: cfg.TLSFingerprintHeader,
+	}
+}
+
+var _ Provider = (*HeaderProvider)(nil)
+
+func (p *HeaderProvider) Name() string { return "header" }
+
+func (p *HeaderProvider) Enrich(_ context.Context, req *Request, info *Info) error {
+	if info.Country == "" && p.country != "" {
+		// Cloudflare sends XX when it does not know the country.
+		if country := strings.ToUpper(strings.TrimSpace(req.Header.Get(p.country))); len(country) == 2 && country != "XX" {
+			info.Country = country
+		}
+	}
+	if info.ASN == 0 && p.asn != "" {
+		value := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(req.Header.Get(p.asn))), "AS")
+		if asn, err := strconv.ParseInt(value, 10, 64); err == nil && asn > 0 {
+			info.ASN = asn
+		}
+	}
+	if info.TLSFingerprint == "" && p.tlsFingerprint != "" {
+		info.TLSFingerprint = strings.TrimSpace(req.Header.Get(p.tlsFingerprint))
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package enrichment
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type asnRange struct {
+	start, end netip.Addr
+	asn        int64
+	country    string
+	org        string
+}
+
+// IP2ASNProvider looks up the autonomous system and country of the client
+// IP in an IP-to-ASN table in the TSV format published by iptoasn.com
+// (range_start, range_end, AS_number, country_code, AS_description), plain
+// or gzipped. The table is loaded once at startup.
+type IP2ASNProvider struct {
+	ranges []asnRange
+}
+
+func NewIP2ASNProvider(path string) (*IP2ASNProvider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var ranges []asnRange
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 5 {
+			continue
+		}
+		start, errStart := netip.ParseAddr(fields[0])
+		end, errEnd := netip.ParseAddr(fields[1])
+		asn, errASN := strconv.ParseInt(fields[2], 10, 64)
+		if errStart != nil || errEnd != nil || errASN != nil {
+			return nil, fmt.Errorf("%s:%d: malformed range", path, line)
+		}
+		// AS 0 marks address space that is not routed.
+		if asn == 0 {
+			continue
+		}
+		country := fields[3]
+		if len(country) != 2 {
+			country = ""
+		}
+		ranges = append(ranges, asnRange{start: start.Unmap(), end: end.Unmap(), asn: asn, country: country, org: fields[4]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("%s holds no routed ranges", path)
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].start.Less(ranges[j].start) })
+	return &IP2ASNProvider{ranges: ranges}, nil
+}
+
+var _ Provider = (*IP2ASNProvider)(nil)
+
+func (p *IP2ASNProvider) Name() string { return "ip2asn" }
+
+// Ranges returns how many routed ranges the table holds.
+func (p *IP2ASNProvider) Ranges() int { return len(p.ranges) }
+
+func (p *IP2ASNProvider) Enrich(_ context.Context, req *Request, info *Info) error {
+	if !req.IP.IsValid() {
+		return nil
+	}
+	ip := req.IP.Unmap()
+	// The last range starting at or before ip is the only one that can
+	// contain it.
+	i := sort.Search(len(p.ranges), func(i int) bool { return ip.Less(p.ranges[i].start) }) - 1
+	if i < 0 || p.ranges[i].end.Less(ip) {
+		return nil
+	}
+
+	r := p.ranges[i]
+	if info.ASN == 0 {
+		info.ASN = r.asn
+	}
+	if info.ASOrg == "" {
+		info.ASOrg = r.org
+	}
+	if info.Country == "" {
+		info.Country = r.country
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type ActivationHandler struct {
+	service *service.ActivationService
+	logger  *zap.Logger
+}
+
+func NewActivationHandler(service *service.ActivationService, logger *zap.Logger) *ActivationHandler {
+	return &ActivationHandler{
+		service: service,
+		logger:  logger.Named("ActivationHandler"),
+	}
+}
+
+func (h *ActivationHandler) Activate(c *gin.Context) {
+	var req dto.ActivationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate activation request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	a, created, err := h.service.Activate(c.Request.Context(), &req, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, dto.NewActivationResponse(a))
+}
+
+func (h *ActivationHandler) Deactivate(c *gin.Context) {
+	var req dto.ActivationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate deactivation request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	a, err := h.service.Deactivate(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewActivationResponse(a))
+}
+
+func (h *ActivationHandler) List(c *gin.Context) {
+	idStr := c.Param("id")
+	licenseID, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid license ID for activation list", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.ListActivationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind activation list query", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	resp, err := h.service.ListActivations(c.Request.Context(), licenseID, req.IncludeInactive)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ActivationHandler) Revoke(c *gin.Context) {
+	idStr, activationIDStr := c.Param("id"), c.Param("activationId")
+	licenseID, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid license ID for activation revoke", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+	activationID, err := idgen.Parse(activationIDStr)
+	if err != nil {
+		h.logger.Warn("Invalid activation ID for revoke", zap.String("activation_id_param", activationIDStr), zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	a, err := h.service.RevokeActivation(c.Request.Context(), licenseID, activationID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewActivationResponse(a))
+}
+
+func (h *ActivationHandler) Heartbeat(c *gin.Context) {
+	var req dto.HeartbeatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate heartbeat request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	a, err := h.service.Heartbeat(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewActivationResponse(a))
+}
+
+func (h *ActivationHandler) ReclaimStale(c *gin.Context) {
+	idStr := c.Param("id")
+	licenseID, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid license ID for activation reclaim", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.ReclaimActivationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind activation reclaim query", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+	var staleAfter time.Duration
+	if req.StaleAfterHours != nil {
+		staleAfter = time.Duration(*req.StaleAfterHours) * time.Hour
+	}
+
+	reclaimed, before, err := h.service.ReclaimStale(c.Request.Context(), licenseID, staleAfter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewReclaimActivationsResponse(licenseID, before, reclaimed))
+}
+
+// UsageSummary is called by server products with their own license key in
+// the path, so the :id route parameter holds a key here.
+func (h *ActivationHandler) UsageSummary(c *gin.Context) {
+	var req dto.UsageSummaryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind usage summary query", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	summary, err := h.service.UsageSummary(c.Request.Context(), c.Param("id"), req.ProductName)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,261 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+	"github.com/makkenzo/license-service-api/internal/domain/activation"
+	"github.com/makkenzo/license-service-api/internal/domain/entitlement"
+	"github.com/makkenzo/license-service-api/internal/domain/lease"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/offlineactivation"
+	"github.com/makkenzo/license-service-api/internal/domain/usage"
+	"github.com/makkenzo/license-service-api/internal/handler"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+// deviceActivations keeps activations in memory and, like the database,
+// refuses an activation over the seat limit.
+type deviceActivations struct {
+	activation.Repository
+	all []*activation.Activation
+}
+
+func (r *deviceActivations) Activate(_ context.Context, a *activation.Activation, maxActive int) (bool, error) {
+	active := 0
+	for _, existing := range r.all {
+		if existing.LicenseID != a.LicenseID || existing.DeactivatedAt != nil {
+			continue
+		}
+		if existing.DeviceID == a.DeviceID {
+			*a = *existing
+			return false, nil
+		}
+		active++
+	}
+	if active >= maxActive {
+		return false, activation.ErrSeatLimitExceeded
+	}
+	a.ID, a.ActivatedAt = uuid.New(), time.Now().UTC()
+	stored := *a
+	r.all = append(r.all, &stored)
+	return true, nil
+}
+
+func (r *deviceActivations) find(licenseID uuid.UUID, match func(*activation.Activation) bool) (*activation.Activation, error) {
+	for _, a := range r.all {
+		if a.LicenseID == licenseID && a.DeactivatedAt == nil && match(a) {
+			return a, nil
+		}
+	}
+	return nil, errFakeNotFound
+}
+
+func (r *deviceActivations) deactivate(licenseID uuid.UUID, match func(*activation.Activation) bool) (*activation.Activation, error) {
+	a, err := r.find(licenseID, match)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	a.DeactivatedAt = &now
+	found := *a
+	return &found, nil
+}
+
+func (r *deviceActivations) Deactivate(_ context.Context, licenseID uuid.UUID, deviceID string) (*activation.Activation, error) {
+	return r.deactivate(licenseID, func(a *activation.Activation) bool { return a.DeviceID == deviceID })
+}
+
+func (r *deviceActivations) Revoke(_ context.Context, licenseID, id uuid.UUID) (*activation.Activation, error) {
+	return r.deactivate(licenseID, func(a *activation.Activation) bool { return a.ID == id })
+}
+
+func (r *deviceActivations) Heartbeat(_ context.Context, hb *activation.Heartbeat) (*activation.Activation, error) {
+	a, err := r.find(hb.LicenseID, func(a *activation.Activation) bool { return a.DeviceID == hb.DeviceID })
+	if err != nil {
+		return nil, err
+	}
+	at := hb.At
+	a.LastSeenAt, a.AppVersion, a.Host = &at, hb.AppVersion, hb.Host
+	found := *a
+	return &found, nil
+}
+
+func (r *deviceActivations) ReclaimStale(context.Context, uuid.UUID, time.Time) ([]*activation.Activation, error) {
+	return []*activation.Activation{}, nil
+}
+
+func (r *deviceActivations) List(_ context.Context, licenseID uuid.UUID, includeInactive bool) ([]*activation.Activation, error) {
+	list := []*activation.Activation{}
+	for _, a := range r.all {
+		if a.LicenseID == licenseID && (includeInactive || a.DeactivatedAt == nil) {
+			found := *a
+			list = append(list, &found)
+		}
+	}
+	return list, nil
+}
+
+func (r *deviceActivations) ListActive(ctx context.Context, licenseID uuid.UUID) ([]*activation.Activation, error) {
+	return r.List(ctx, licenseID, false)
+}
+
+// noEntitlements is the entitlement repository of licenses without any.
+type noEntitlements struct{ entitlement.Repository }
+
+func (noEntitlements) ListByLicense(context.Context, uuid.UUID) ([]*entitlement.Entitlement, error) {
+	return []*entitlement.Entitlement{}, nil
+}
+
+func TestActivationHandler(t *testing.T) {
+	lic := newTestLicense(2)
+	activations := &deviceActivations{}
+	svc := service.NewActivationService(activations, &licenseLookup{licenses: []*license.License{lic}}, &config.HeartbeatConfig{StaleAfter: time.Hour}, zap.NewNop())
+	h := handler.NewActivationHandler(svc, zap.NewNop())
+	seated := &activation.Activation{LicenseID: lic.ID, DeviceID: "device-seated"}
+	if _, err := activations.Activate(context.Background(), seated, 2); err != nil {
+		t.Fatal(err)
+	}
+	id, key := lic.ID, lic.LicenseKey
+	device := func(deviceID string) string {
+		return fmt.Sprintf(`{"license_key":%q,"product_name":"acme","device_id":%q}`, key, deviceID)
+	}
+
+	runCases(t, []apiCase{
+		{name: "activate", method: http.MethodPost, pattern: "/licenses/activate", handler: h.Activate, target: "/licenses/activate",
+			body: device("device-1"), status: http.StatusCreated},
+		{name: "activate again", method: http.MethodPost, pattern: "/licenses/activate", handler: h.Activate, target: "/licenses/activate",
+			body: device("device-1"), status: http.StatusOK},
+		{name: "activate over seats", method: http.MethodPost, pattern: "/licenses/activate", handler: h.Activate, target: "/licenses/activate",
+			body: device("device-2"), status: http.StatusConflict},
+		{name: "heartbeat", method: http.MethodPost, pattern: "/licenses/heartbeat", handler: h.Heartbeat, target: "/licenses/heartbeat",
+			body:   fmt.Sprintf(`{"license_key":%q,"product_name":"acme","device_id":"device-1","app_version":"2.0.1","host":{"os":"linux"}}`, key),
+			status: http.StatusOK},
+		{name: "list", method: http.MethodGet, pattern: "/licenses/:id/activations", handler: h.List,
+			target: "/licenses/" + id.String() + "/activations?include_inactive=true", status: http.StatusOK},
+		{name: "usage summary", method: http.MethodGet, pattern: "/licenses/:id/usage-summary", handler: h.UsageSummary,
+			target: "/licenses/" + key + "/usage-summary?product_name=acme", status: http.StatusOK},
+		{name: "deactivate", method: http.MethodPost, pattern: "/licenses/deactivate", handler: h.Deactivate, target: "/licenses/deactivate",
+			body: device("device-1"), status: http.StatusOK},
+		{name: "revoke", method: http.MethodDelete, pattern: "/licenses/:id/activations/:activationId", handler: h.Revoke,
+			target: "/licenses/" + id.String() + "/activations/" + seated.ID.String(), status: http.StatusOK},
+		{name: "reclaim", method: http.MethodPost, pattern: "/licenses/:id/activations/reclaim", handler: h.ReclaimStale,
+			target: "/licenses/" + id.String() + "/activations/reclaim?stale_after_hours=1", status: http.StatusOK},
+	})
+}
+
+// challenges accepts new offline activation challenges.
+type challenges struct{ offlineactivation.Repository }
+
+func (challenges) Create(_ context.Context, c *offlineactivation.Challenge) error {
+	c.ID, c.CreatedAt = uuid.New(), time.Now().UTC()
+	return nil
+}
+
+func TestOfflineActivationHandler(t *testing.T) {
+	lic := newTestLicense(2)
+	licenses := &licenseLookup{licenses: []*license.License{lic}}
+	activations := service.NewActivationService(&deviceActivations{}, licenses, &config.HeartbeatConfig{StaleAfter: time.Hour}, zap.NewNop())
+	offline := service.NewOfflineActivationService(challenges{}, licenses, noEntitlements{}, activations, newKeyring(t),
+		cryptoprovider.Stdlib{}, &config.OfflineActivationConfig{ChallengeTTL: time.Hour}, zap.NewNop())
+	h := handler.NewOfflineActivationHandler(offline, zap.NewNop())
+
+	runCases(t, []apiCase{
+		{name: "challenge", method: http.MethodPost, pattern: "/licenses/offline/challenge", handler: h.Challenge, target: "/licenses/offline/challenge",
+			body:   fmt.Sprintf(`{"license_key":%q,"product_name":"acme","device_id":"air-gapped-1"}`, lic.LicenseKey),
+			status: http.StatusCreated},
+	})
+}
+
+// seatLeases hands out up to maxSeats leases of one license.
+type seatLeases struct {
+	lease.Repository
+	clients map[string]bool
+}
+
+func (r *seatLeases) Checkout(_ context.Context, licenseID uuid.UUID, clientID string, ttl time.Duration, maxSeats int) (*lease.Lease, int, error) {
+	if !r.clients[clientID] && len(r.clients) >= maxSeats {
+		return nil, len(r.clients), lease.ErrNoFreeSeat
+	}
+	r.clients[clientID] = true
+	return &lease.Lease{LicenseID: licenseID, ClientID: clientID, ExpiresAt: time.Now().UTC().Add(ttl)}, len(r.clients), nil
+}
+
+func (r *seatLeases) Checkin(_ context.Context, _ uuid.UUID, clientID string) error {
+	if !r.clients[clientID] {
+		return errFakeNotFound
+	}
+	delete(r.clients, clientID)
+	return nil
+}
+
+func TestFloatingHandler(t *testing.T) {
+	lic := newTestLicense(1)
+	lic.Floating = true
+	floating := service.NewFloatingService(&seatLeases{clients: map[string]bool{}}, &licenseLookup{licenses: []*license.License{lic}},
+		&config.FloatingConfig{LeaseTTL: time.Minute, MaxLeaseTTL: time.Hour}, zap.NewNop())
+	h := handler.NewFloatingHandler(floating, zap.NewNop())
+	client := func(clientID string) string {
+		return fmt.Sprintf(`{"license_key":%q,"product_name":"acme","client_id":%q}`, lic.LicenseKey, clientID)
+	}
+
+	runCases(t, []apiCase{
+		{name: "checkout", method: http.MethodPost, pattern: "/licenses/checkout", handler: h.Checkout, target: "/licenses/checkout",
+			body: client("client-1"), status: http.StatusOK},
+		{name: "checkout exhausted", method: http.MethodPost, pattern: "/licenses/checkout", handler: h.Checkout, target: "/licenses/checkout",
+			body: client("client-2"), status: http.StatusConflict},
+		{name: "checkin", method: http.MethodPost, pattern: "/licenses/checkin", handler: h.Checkin, target: "/licenses/checkin",
+			body: client("client-1"), status: http.StatusNoContent},
+	})
+}
+
+// usageCounters records daily counters of one license.
+type usageCounters struct {
+	usage.Repository
+	counters []*usage.Counter
+}
+
+func (r *usageCounters) Record(_ context.Context, licenseID uuid.UUID, events []usage.Event) error {
+	for _, e := range events {
+		r.counters = append(r.counters, &usage.Counter{
+			LicenseID:   licenseID,
+			Metric:      e.Metric,
+			PeriodStart: usage.PeriodDay.Start(e.OccurredAt),
+			Quantity:    e.Quantity,
+		})
+	}
+	return nil
+}
+
+func (r *usageCounters) Totals(context.Context, uuid.UUID, time.Time) (map[string]int64, error) {
+	totals := map[string]int64{}
+	for _, c := range r.counters {
+		totals[c.Metric] += c.Quantity
+	}
+	return totals, nil
+}
+
+func (r *usageCounters) List(context.Context, usage.ListParams) ([]*usage.Counter, error) {
+	return r.counters, nil
+}
+
+func TestUsageHandler(t *testing.T) {
+	lic := newTestLicense(2)
+	svc := service.NewUsageService(&usageCounters{}, &licenseLookup{licenses: []*license.License{lic}}, noEntitlements{}, zap.NewNop())
+	h := handler.NewUsageHandler(svc, zap.NewNop())
+
+	runCases(t, []apiCase{
+		{name: "report", method: http.MethodPost, pattern: "/licenses/usage", handler: h.Report, target: "/licenses/usage",
+			body:   fmt.Sprintf(`{"license_key":%q,"product_name":"acme","events":[{"metric":"api_calls","quantity":3}]}`, lic.LicenseKey),
+			status: http.StatusOK},
+		{name: "list", method: http.MethodGet, pattern: "/licenses/:id/usage", handler: h.List,
+			target: "/licenses/" + lic.ID.String() + "/usage?period=day", status: http.StatusOK},
+	})
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type AnalyticsExportHandler struct {
+	service *service.AnalyticsExportService
+	logger  *zap.Logger
+}
+
+func NewAnalyticsExportHandler(service *service.AnalyticsExportService, logger *zap.Logger) *AnalyticsExportHandler {
+	return &AnalyticsExportHandler{
+		service: service,
+		logger:  logger.Named("AnalyticsExportHandler"),
+	}
+}
+
+// Run exports one day right away, for backfills and for days the scheduled
+// export missed.
+func (h *AnalyticsExportHandler) Run(c *gin.Context) {
+	// The body is optional; without it yesterday is exported.
+	var req dto.RunAnalyticsExportRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.logger.Warn("Failed to bind or validate analytics export request", zap.Error(err))
+			_ = c.Error(err)
+			return
+		}
+	}
+
+	resp, err := h.service.Run(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
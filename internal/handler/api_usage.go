@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type APIUsageHandler struct {
+	service *service.APIUsageService
+	logger  *zap.Logger
+}
+
+func NewAPIUsageHandler(service *service.APIUsageService, logger *zap.Logger) *APIUsageHandler {
+	return &APIUsageHandler{
+		service: service,
+		logger:  logger.Named("APIUsageHandler"),
+	}
+}
+
+func (h *APIUsageHandler) Statement(c *gin.Context) {
+	var req dto.UsageStatementRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind usage statement query", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	resp, err := h.service.Statement(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Export sends the statement as a CSV file. Errors after the first row has
+// been written can only be logged.
+func (h *APIUsageHandler) Export(c *gin.Context) {
+	var req dto.UsageStatementRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind usage statement export query", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", `attachment; filename="usage-statement.csv"`)
+	if err := h.service.ExportStatement(c.Request.Context(), &req, c.Writer); err != nil {
+		if c.Writer.Written() {
+			h.logger.Error("Usage statement export failed mid-stream", zap.Error(err))
+			return
+		}
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		_ = c.Error(err)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/handler/middleware"
+	"github.com/makkenzo/license-service-api/internal/idgen"
 	"github.com/makkenzo/license-service-api/internal/ierr"
 	"github.com/makkenzo/license-service-api/internal/service"
 	"go.uber.org/zap"
@@ -28,16 +30,16 @@ func (h *APIKeyHandler) Create(c *gin.Context) {
 	var req dto.CreateAPIKeyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Warn("Failed to bind create api key request", zap.Error(err))
-		_ = c.Error(fmt.Errorf("%w: %v", ierr.ErrValidation, err))
+		_ = c.Error(fmt.Errorf("%w: %w", ierr.ErrValidation, err))
 		return
 	}
 
 	var productIDPtr *uuid.UUID
-	if req.ProductID != uuid.Nil {
-		productIDPtr = &req.ProductID
+	if productID := req.ProductID.UUID(); productID != uuid.Nil {
+		productIDPtr = &productID
 	}
 
-	respDTO, _, err := h.service.CreateAPIKey(c.Request.Context(), req.Description, productIDPtr)
+	respDTO, _, err := h.service.CreateAPIKey(c.Request.Context(), req.Description, productIDPtr, req.Scopes, middleware.GetUserClaims(c))
 	if err != nil {
 		h.logger.Error("Service failed to create api key", zap.Error(err))
 		_ = c.Error(err)
@@ -62,9 +64,9 @@ func (h *APIKeyHandler) List(c *gin.Context) {
 
 func (h *APIKeyHandler) Revoke(c *gin.Context) {
 	idStr := c.Param("id")
-	id, err := uuid.Parse(idStr)
+	id, err := idgen.Parse(idStr)
 	if err != nil {
-		h.logger.Warn("Invalid UUID format for revoke api key", zap.String("id_param", idStr), zap.Error(err))
+		h.logger.Warn("Invalid ID format for revoke api key", zap.String("id_param", idStr), zap.Error(err))
 		_ = c.Error(fmt.Errorf("%w: invalid api key id format", ierr.ErrValidation))
 		return
 	}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/handler/middleware"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type ApprovalHandler struct {
+	service *service.ApprovalService
+	logger  *zap.Logger
+}
+
+func NewApprovalHandler(service *service.ApprovalService, logger *zap.Logger) *ApprovalHandler {
+	return &ApprovalHandler{
+		service: service,
+		logger:  logger.Named("ApprovalHandler"),
+	}
+}
+
+func (h *ApprovalHandler) ListProtectedKeys(c *gin.Context) {
+	keys, err := h.service.ListProtectedKeys(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+func (h *ApprovalHandler) PutProtectedKey(c *gin.Context) {
+	var req dto.PutProtectedKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate protected key request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	key, err := h.service.PutProtectedKey(c.Request.Context(), c.Param("key"), &req, middleware.GetUserClaims(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
+
+func (h *ApprovalHandler) DeleteProtectedKey(c *gin.Context) {
+	if err := h.service.DeleteProtectedKey(c.Request.Context(), c.Param("key"), middleware.GetUserClaims(c)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ApprovalHandler) List(c *gin.Context) {
+	var req dto.ListApprovalsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	requests, total, err := h.service.ListRequests(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	now := time.Now()
+	approvals := make([]*dto.ApprovalResponse, len(requests))
+	for i, r := range requests {
+		approvals[i] = dto.NewApprovalResponse(r, now)
+	}
+	c.JSON(http.StatusOK, dto.PaginatedApprovalResponse{
+		Approvals:  approvals,
+		TotalCount: total,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	})
+}
+
+func (h *ApprovalHandler) Get(c *gin.Context) {
+	id, err := idgen.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	r, err := h.service.GetRequest(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewApprovalResponse(r, time.Now()))
+}
+
+func (h *ApprovalHandler) Approve(c *gin.Context) {
+	id, err := idgen.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	r, lic, err := h.service.Approve(c.Request.Context(), id, middleware.GetUserClaims(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ApproveResponse{
+		Approval: dto.NewApprovalResponse(r, time.Now()),
+		License:  dto.NewLicenseResponse(lic),
+	})
+}
+
+func (h *ApprovalHandler) Reject(c *gin.Context) {
+	id, err := idgen.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	r, err := h.service.Reject(c.Request.Context(), id, middleware.GetUserClaims(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewApprovalResponse(r, time.Now()))
+}
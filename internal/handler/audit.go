@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type AuditHandler struct {
+	service *service.AuditService
+	logger  *zap.Logger
+}
+
+func NewAuditHandler(service *service.AuditService, logger *zap.Logger) *AuditHandler {
+	return &AuditHandler{
+		service: service,
+		logger:  logger.Named("AuditHandler"),
+	}
+}
+
+func (h *AuditHandler) Diff(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid ID format for audit diff", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(fmt.Errorf("%w: invalid audit entry id format", ierr.ErrValidation))
+		return
+	}
+
+	var req dto.AuditDiffRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	diff, err := h.service.Diff(c.Request.Context(), id, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+func (h *AuditHandler) List(c *gin.Context) {
+	var req dto.ListAuditRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	entries, total, err := h.service.List(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.PaginatedAuditResponse{
+		Entries:    entries,
+		TotalCount: total,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	})
+}
+
+func (h *AuditHandler) ListForLicense(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid ID format for license audit", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.ListAuditRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	entries, total, err := h.service.ListForLicense(c.Request.Context(), id, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.PaginatedAuditResponse{
+		Entries:    entries,
+		TotalCount: total,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	})
+}
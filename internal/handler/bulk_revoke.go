@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/handler/middleware"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"github.com/makkenzo/license-service-api/internal/statusguard"
+	"go.uber.org/zap"
+)
+
+type BulkRevokeHandler struct {
+	service *service.BulkRevokeService
+	logger  *zap.Logger
+}
+
+func NewBulkRevokeHandler(service *service.BulkRevokeService, logger *zap.Logger) *BulkRevokeHandler {
+	return &BulkRevokeHandler{
+		service: service,
+		logger:  logger.Named("BulkRevokeHandler"),
+	}
+}
+
+func (h *BulkRevokeHandler) BulkRevoke(c *gin.Context) {
+	var req dto.BulkRevokeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate bulk revoke request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	requestedBy := ""
+	if claims := middleware.GetUserClaims(c); claims != nil {
+		requestedBy = claims.Subject
+	}
+
+	// Revoking is an explicit status change, so it overrides status freezes.
+	ctx := statusguard.WithOperatorOverride(c.Request.Context())
+	resp, err := h.service.BulkRevoke(ctx, &req, requestedBy)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
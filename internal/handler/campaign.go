@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/handler/middleware"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type CampaignHandler struct {
+	service *service.CampaignService
+	logger  *zap.Logger
+}
+
+func NewCampaignHandler(service *service.CampaignService, logger *zap.Logger) *CampaignHandler {
+	return &CampaignHandler{
+		service: service,
+		logger:  logger.Named("CampaignHandler"),
+	}
+}
+
+func (h *CampaignHandler) Create(c *gin.Context) {
+	var req dto.CreateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate campaign request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	requestedBy := ""
+	if claims := middleware.GetUserClaims(c); claims != nil {
+		requestedBy = claims.Subject
+	}
+
+	resp, err := h.service.CreateCampaign(c.Request.Context(), &req, requestedBy)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, resp)
+}
+
+func (h *CampaignHandler) List(c *gin.Context) {
+	var req dto.ListCampaignsQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	resp, err := h.service.ListCampaigns(c.Request.Context(), req.Limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *CampaignHandler) Get(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid ID format for campaign", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(fmt.Errorf("%w: invalid campaign id format", ierr.ErrValidation))
+		return
+	}
+
+	resp, err := h.service.GetCampaign(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *CampaignHandler) Recipients(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid ID format for campaign", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(fmt.Errorf("%w: invalid campaign id format", ierr.ErrValidation))
+		return
+	}
+
+	var req dto.ListCampaignRecipientsQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	resp, err := h.service.ListRecipients(c.Request.Context(), id, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *CampaignHandler) Cancel(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid ID format for campaign", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(fmt.Errorf("%w: invalid campaign id format", ierr.ErrValidation))
+		return
+	}
+
+	cancelledBy := ""
+	if claims := middleware.GetUserClaims(c); claims != nil {
+		cancelledBy = claims.Subject
+	}
+
+	resp, err := h.service.CancelCampaign(c.Request.Context(), id, cancelledBy)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Unsubscribe serves the link in campaign e-mails. Opening it sends the
+// token in the query string; mail clients using List-Unsubscribe may post
+// it instead.
+func (h *CampaignHandler) Unsubscribe(c *gin.Context) {
+	var req dto.UnsubscribeRequest
+	var err error
+	if c.Request.Method == http.MethodPost && c.Query("token") == "" {
+		err = c.ShouldBindJSON(&req)
+	} else {
+		err = c.ShouldBindQuery(&req)
+	}
+	if err != nil {
+		h.logger.Warn("Failed to bind or validate unsubscribe request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	resp, err := h.service.Unsubscribe(c.Request.Context(), req.Token)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
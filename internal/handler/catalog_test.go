@@ -0,0 +1,555 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+	"github.com/makkenzo/license-service-api/internal/domain/activation"
+	"github.com/makkenzo/license-service-api/internal/domain/apikey"
+	"github.com/makkenzo/license-service-api/internal/domain/customer"
+	"github.com/makkenzo/license-service-api/internal/domain/customstatus"
+	"github.com/makkenzo/license-service-api/internal/domain/dashboard"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/licensetemplate"
+	"github.com/makkenzo/license-service-api/internal/domain/product"
+	"github.com/makkenzo/license-service-api/internal/handler"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+// productCatalog keeps products by name; the tests set the policies of
+// one product only.
+type productCatalog struct {
+	product.Repository
+	products    map[string]*product.Product
+	lifecycle   *product.Lifecycle
+	agentPolicy *product.AgentPolicy
+	cachePolicy *product.CachePolicy
+}
+
+func (r *productCatalog) CreateProduct(_ context.Context, p *product.Product) error {
+	if _, ok := r.products[p.Name]; ok {
+		return ierr.ErrDuplicateKey
+	}
+	p.ID, p.CreatedAt, p.UpdatedAt = uuid.New(), time.Now().UTC(), time.Now().UTC()
+	r.products[p.Name] = p
+	return nil
+}
+
+func (r *productCatalog) FindProductByName(_ context.Context, name string) (*product.Product, error) {
+	p, ok := r.products[name]
+	if !ok {
+		return nil, errFakeNotFound
+	}
+	found := *p
+	return &found, nil
+}
+
+func (r *productCatalog) ListProducts(context.Context) ([]*product.Product, error) {
+	list := []*product.Product{}
+	for _, p := range r.products {
+		list = append(list, p)
+	}
+	return list, nil
+}
+
+func (r *productCatalog) UpdateProduct(_ context.Context, p *product.Product) error {
+	p.UpdatedAt = time.Now().UTC()
+	r.products[p.Name] = p
+	return nil
+}
+
+func (r *productCatalog) DeleteProduct(_ context.Context, id uuid.UUID) error {
+	for name, p := range r.products {
+		if p.ID == id {
+			delete(r.products, name)
+			return nil
+		}
+	}
+	return errFakeNotFound
+}
+
+func (r *productCatalog) FindLifecycle(context.Context, string) (*product.Lifecycle, error) {
+	if r.lifecycle == nil {
+		return nil, errFakeNotFound
+	}
+	return r.lifecycle, nil
+}
+
+func (r *productCatalog) ListLifecycles(ctx context.Context) ([]*product.Lifecycle, error) {
+	if lc, err := r.FindLifecycle(ctx, ""); err == nil {
+		return []*product.Lifecycle{lc}, nil
+	}
+	return []*product.Lifecycle{}, nil
+}
+
+func (r *productCatalog) UpsertLifecycle(_ context.Context, lc *product.Lifecycle) error {
+	lc.CreatedAt, lc.UpdatedAt = time.Now().UTC(), time.Now().UTC()
+	r.lifecycle = lc
+	return nil
+}
+
+func (r *productCatalog) FindAgentPolicy(context.Context, string) (*product.AgentPolicy, error) {
+	if r.agentPolicy == nil {
+		return nil, errFakeNotFound
+	}
+	return r.agentPolicy, nil
+}
+
+func (r *productCatalog) ListAgentPolicies(ctx context.Context) ([]*product.AgentPolicy, error) {
+	if p, err := r.FindAgentPolicy(ctx, ""); err == nil {
+		return []*product.AgentPolicy{p}, nil
+	}
+	return []*product.AgentPolicy{}, nil
+}
+
+func (r *productCatalog) UpsertAgentPolicy(_ context.Context, p *product.AgentPolicy) error {
+	p.CreatedAt, p.UpdatedAt = time.Now().UTC(), time.Now().UTC()
+	r.agentPolicy = p
+	return nil
+}
+
+func (r *productCatalog) DeleteAgentPolicy(context.Context, string) error {
+	r.agentPolicy = nil
+	return nil
+}
+
+func (r *productCatalog) FindCachePolicy(context.Context, string) (*product.CachePolicy, error) {
+	if r.cachePolicy == nil {
+		return nil, errFakeNotFound
+	}
+	return r.cachePolicy, nil
+}
+
+func (r *productCatalog) UpsertCachePolicy(_ context.Context, p *product.CachePolicy) error {
+	p.CreatedAt, p.UpdatedAt = time.Now().UTC(), time.Now().UTC()
+	r.cachePolicy = p
+	return nil
+}
+
+func (r *productCatalog) DeleteCachePolicy(context.Context, string) error {
+	r.cachePolicy = nil
+	return nil
+}
+
+func TestProductHandler(t *testing.T) {
+	products := &productCatalog{products: map[string]*product.Product{
+		"acme":   newAcmeProduct(),
+		"legacy": {ID: uuid.New(), Name: "legacy", CustomerLicenseCaps: map[string]int{}},
+	}}
+	h := handler.NewProductHandler(service.NewProductService(products, &licenseLookup{}, nil, zap.NewNop()), zap.NewNop())
+
+	runCases(t, []apiCase{
+		{name: "create", method: http.MethodPost, pattern: "/products", handler: h.Create, target: "/products",
+			body: `{"name":"widget","display_name":"Widget","customer_license_caps":{"*":3},"metadata_schema":{"type":"object"}}`, status: http.StatusCreated},
+		{name: "create duplicate", method: http.MethodPost, pattern: "/products", handler: h.Create, target: "/products",
+			body: `{"name":"acme"}`, status: http.StatusConflict},
+		{name: "list", method: http.MethodGet, pattern: "/products", handler: h.List, target: "/products", status: http.StatusOK},
+		{name: "get", method: http.MethodGet, pattern: "/products/:name", handler: h.Get, target: "/products/acme", status: http.StatusOK},
+		{name: "get missing", method: http.MethodGet, pattern: "/products/:name", handler: h.Get, target: "/products/missing", status: http.StatusNotFound},
+		{name: "update", method: http.MethodPatch, pattern: "/products/:name", handler: h.Update, target: "/products/acme",
+			body: `{"description":"Flagship product"}`, status: http.StatusOK},
+		{name: "set lifecycle", method: http.MethodPut, pattern: "/products/:name/lifecycle", handler: h.SetLifecycle, target: "/products/acme/lifecycle",
+			body: `{"state":"deprecated","migration_offer":"Move to widget"}`, status: http.StatusOK},
+		{name: "get lifecycle", method: http.MethodGet, pattern: "/products/:name/lifecycle", handler: h.GetLifecycle, target: "/products/acme/lifecycle", status: http.StatusOK},
+		{name: "list lifecycles", method: http.MethodGet, pattern: "/products/lifecycles", handler: h.ListLifecycles, target: "/products/lifecycles", status: http.StatusOK},
+		{name: "set agent policy", method: http.MethodPut, pattern: "/products/:name/agent-policy", handler: h.SetAgentPolicy, target: "/products/acme/agent-policy",
+			body: `{"min_version":"1.0.0","enforcement":"warn"}`, status: http.StatusOK},
+		{name: "get agent policy", method: http.MethodGet, pattern: "/products/:name/agent-policy", handler: h.GetAgentPolicy, target: "/products/acme/agent-policy", status: http.StatusOK},
+		{name: "list agent policies", method: http.MethodGet, pattern: "/products/agent-policies", handler: h.ListAgentPolicies, target: "/products/agent-policies", status: http.StatusOK},
+		{name: "delete agent policy", method: http.MethodDelete, pattern: "/products/:name/agent-policy", handler: h.DeleteAgentPolicy, target: "/products/acme/agent-policy", status: http.StatusNoContent},
+		{name: "set cache policy", method: http.MethodPut, pattern: "/products/:name/cache-policy", handler: h.SetCachePolicy, target: "/products/acme/cache-policy",
+			body: `{"cache_ttl":3600,"revalidate_after":600}`, status: http.StatusOK},
+		{name: "get cache policy", method: http.MethodGet, pattern: "/products/:name/cache-policy", handler: h.GetCachePolicy, target: "/products/acme/cache-policy", status: http.StatusOK},
+		{name: "delete cache policy", method: http.MethodDelete, pattern: "/products/:name/cache-policy", handler: h.DeleteCachePolicy, target: "/products/acme/cache-policy", status: http.StatusNoContent},
+		{name: "delete", method: http.MethodDelete, pattern: "/products/:name", handler: h.Delete, target: "/products/legacy", status: http.StatusNoContent},
+	})
+}
+
+// customerDirectory keeps customers by ID.
+type customerDirectory struct {
+	customer.Repository
+	byID map[uuid.UUID]*customer.Customer
+}
+
+func (r *customerDirectory) Create(_ context.Context, c *customer.Customer) error {
+	for _, other := range r.byID {
+		if other.Email == c.Email {
+			return ierr.ErrDuplicateKey
+		}
+	}
+	c.ID, c.CreatedAt, c.UpdatedAt = uuid.New(), time.Now().UTC(), time.Now().UTC()
+	stored := *c
+	r.byID[c.ID] = &stored
+	return nil
+}
+
+func (r *customerDirectory) FindByID(_ context.Context, id uuid.UUID) (*customer.Customer, error) {
+	c, ok := r.byID[id]
+	if !ok {
+		return nil, errFakeNotFound
+	}
+	found := *c
+	return &found, nil
+}
+
+func (r *customerDirectory) List(context.Context, customer.ListParams) ([]*customer.Customer, int64, error) {
+	list := []*customer.Customer{}
+	for _, c := range r.byID {
+		list = append(list, c)
+	}
+	return list, int64(len(list)), nil
+}
+
+func (r *customerDirectory) Update(_ context.Context, c *customer.Customer) error {
+	c.UpdatedAt = time.Now().UTC()
+	stored := *c
+	r.byID[c.ID] = &stored
+	return nil
+}
+
+func (r *customerDirectory) Delete(_ context.Context, id uuid.UUID) error {
+	delete(r.byID, id)
+	return nil
+}
+
+func TestCustomerHandler(t *testing.T) {
+	customers := &customerDirectory{byID: map[uuid.UUID]*customer.Customer{}}
+	existing := &customer.Customer{Email: "it@example.com", Name: "Example IT"}
+	if err := customers.Create(context.Background(), existing); err != nil {
+		t.Fatal(err)
+	}
+	owned := newTestLicense(2)
+	owned.CustomerID = uuid.NullUUID{UUID: existing.ID, Valid: true}
+	licenseSvc := service.NewLicenseService(&licenseLookup{licenses: []*license.License{owned}}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		&config.QueryConfig{MaxOffset: 10000}, zap.NewNop())
+	h := handler.NewCustomerHandler(service.NewCustomerService(customers, zap.NewNop()), licenseSvc, zap.NewNop())
+	id := existing.ID.String()
+
+	runCases(t, []apiCase{
+		{name: "create", method: http.MethodPost, pattern: "/customers", handler: h.Create, target: "/customers",
+			body: `{"email":"ops@example.com","name":"Example Ops"}`, status: http.StatusCreated},
+		{name: "create duplicate", method: http.MethodPost, pattern: "/customers", handler: h.Create, target: "/customers",
+			body: `{"email":"it@example.com"}`, status: http.StatusConflict},
+		{name: "list", method: http.MethodGet, pattern: "/customers", handler: h.List, target: "/customers?limit=10", status: http.StatusOK},
+		{name: "get", method: http.MethodGet, pattern: "/customers/:id", handler: h.Get, target: "/customers/" + id, status: http.StatusOK},
+		{name: "update", method: http.MethodPatch, pattern: "/customers/:id", handler: h.Update, target: "/customers/" + id,
+			body: `{"name":"Example IT Department"}`, status: http.StatusOK},
+		{name: "licenses", method: http.MethodGet, pattern: "/customers/:id/licenses", handler: h.Licenses, target: "/customers/" + id + "/licenses", status: http.StatusOK},
+		{name: "delete", method: http.MethodDelete, pattern: "/customers/:id", handler: h.Delete, target: "/customers/" + id, status: http.StatusNoContent},
+	})
+}
+
+// templateStore keeps license templates by ID.
+type templateStore struct {
+	byID map[uuid.UUID]*licensetemplate.Template
+}
+
+var _ licensetemplate.Repository = (*templateStore)(nil)
+
+func (r *templateStore) Create(_ context.Context, t *licensetemplate.Template) error {
+	t.ID, t.CreatedAt, t.UpdatedAt = uuid.New(), time.Now().UTC(), time.Now().UTC()
+	if t.Entitlements == nil {
+		t.Entitlements = []licensetemplate.Entitlement{}
+	}
+	stored := *t
+	r.byID[t.ID] = &stored
+	return nil
+}
+
+func (r *templateStore) FindByID(_ context.Context, id uuid.UUID) (*licensetemplate.Template, error) {
+	t, ok := r.byID[id]
+	if !ok {
+		return nil, errFakeNotFound
+	}
+	found := *t
+	return &found, nil
+}
+
+func (r *templateStore) List(context.Context, *uuid.UUID) ([]*licensetemplate.Template, error) {
+	list := []*licensetemplate.Template{}
+	for _, t := range r.byID {
+		list = append(list, t)
+	}
+	return list, nil
+}
+
+func (r *templateStore) Update(_ context.Context, t *licensetemplate.Template) error {
+	t.UpdatedAt = time.Now().UTC()
+	stored := *t
+	r.byID[t.ID] = &stored
+	return nil
+}
+
+func (r *templateStore) Delete(_ context.Context, id uuid.UUID) error {
+	delete(r.byID, id)
+	return nil
+}
+
+func TestLicenseTemplateHandler(t *testing.T) {
+	templates := &templateStore{byID: map[uuid.UUID]*licensetemplate.Template{}}
+	h := handler.NewLicenseTemplateHandler(service.NewLicenseTemplateService(templates, acmeProducts{}, zap.NewNop()), zap.NewNop())
+	existing := &licensetemplate.Template{Name: "Annual", ProductID: acmeID, Type: "subscription"}
+	if err := templates.Create(context.Background(), existing); err != nil {
+		t.Fatal(err)
+	}
+	id := existing.ID.String()
+
+	runCases(t, []apiCase{
+		{name: "create", method: http.MethodPost, pattern: "/license-templates", handler: h.Create, target: "/license-templates",
+			body: `{"name":"Trial","product_name":"acme","type":"trial","duration_days":14,"max_activations":1}`, status: http.StatusCreated},
+		{name: "list", method: http.MethodGet, pattern: "/license-templates", handler: h.List, target: "/license-templates", status: http.StatusOK},
+		{name: "get", method: http.MethodGet, pattern: "/license-templates/:id", handler: h.Get, target: "/license-templates/" + id, status: http.StatusOK},
+		{name: "update", method: http.MethodPatch, pattern: "/license-templates/:id", handler: h.Update, target: "/license-templates/" + id,
+			body: `{"duration_days":365}`, status: http.StatusOK},
+		{name: "delete", method: http.MethodDelete, pattern: "/license-templates/:id", handler: h.Delete, target: "/license-templates/" + id, status: http.StatusNoContent},
+	})
+}
+
+// statusStore keeps custom statuses by name.
+type statusStore struct {
+	statuses []*customstatus.Status
+}
+
+var _ customstatus.Repository = (*statusStore)(nil)
+
+func (r *statusStore) Create(_ context.Context, s *customstatus.Status) error {
+	s.CreatedAt, s.UpdatedAt = time.Now().UTC(), time.Now().UTC()
+	stored := *s
+	r.statuses = append(r.statuses, &stored)
+	return nil
+}
+
+func (r *statusStore) List(context.Context) ([]*customstatus.Status, error) {
+	list := make([]*customstatus.Status, len(r.statuses))
+	for i, s := range r.statuses {
+		found := *s
+		list[i] = &found
+	}
+	return list, nil
+}
+
+func (r *statusStore) Update(_ context.Context, s *customstatus.Status) error {
+	for _, existing := range r.statuses {
+		if existing.Name == s.Name {
+			s.CreatedAt, s.UpdatedAt = existing.CreatedAt, time.Now().UTC()
+			*existing = *s
+			return nil
+		}
+	}
+	return errFakeNotFound
+}
+
+func (r *statusStore) Delete(_ context.Context, name license.LicenseStatus) error {
+	for i, existing := range r.statuses {
+		if existing.Name == name {
+			r.statuses = append(r.statuses[:i], r.statuses[i+1:]...)
+			return nil
+		}
+	}
+	return errFakeNotFound
+}
+
+func TestCustomStatusHandler(t *testing.T) {
+	statuses := &statusStore{}
+	svc := service.NewCustomStatusService(statuses, &licenseLookup{}, &config.CustomStatusesConfig{RefreshInterval: time.Minute}, zap.NewNop())
+	h := handler.NewCustomStatusHandler(svc, zap.NewNop())
+	if err := statuses.Create(context.Background(), &customstatus.Status{Name: "on_hold", Behavior: customstatus.BehaviorWarn}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	runCases(t, []apiCase{
+		{name: "create", method: http.MethodPost, pattern: "/license-statuses", handler: h.Create, target: "/license-statuses",
+			body: `{"name":"in_review","behavior":"invalid","description":"Pending compliance review"}`, status: http.StatusCreated},
+		{name: "create built-in", method: http.MethodPost, pattern: "/license-statuses", handler: h.Create, target: "/license-statuses",
+			body: `{"name":"active","behavior":"valid"}`, status: http.StatusBadRequest},
+		{name: "list", method: http.MethodGet, pattern: "/license-statuses", handler: h.List, target: "/license-statuses", status: http.StatusOK},
+		{name: "update", method: http.MethodPatch, pattern: "/license-statuses/:name", handler: h.Update, target: "/license-statuses/on_hold",
+			body: `{"behavior":"valid"}`, status: http.StatusOK},
+		{name: "delete", method: http.MethodDelete, pattern: "/license-statuses/:name", handler: h.Delete, target: "/license-statuses/on_hold", status: http.StatusNoContent},
+	})
+}
+
+// dashboardLicenses serves the dashboard summary of one active license.
+type dashboardLicenses struct{ license.Repository }
+
+func (dashboardLicenses) GetDashboardSummary(context.Context, int) (*license.DashboardSummaryData, error) {
+	return &license.DashboardSummaryData{
+		TotalCount:    1,
+		StatusCounts:  map[license.LicenseStatus]int64{license.StatusActive: 1},
+		TypeCounts:    map[string]int64{"subscription": 1},
+		ProductCounts: map[string]int64{"acme": 1},
+		TagCounts:     map[string]int64{},
+	}, nil
+}
+
+// seenActivations counts the installations seen recently.
+type seenActivations struct{ activation.Repository }
+
+func (seenActivations) CountSeenSince(context.Context, time.Time) (int64, error) {
+	return 1, nil
+}
+
+// widgetStore keeps dashboard widgets by ID.
+type widgetStore struct {
+	byID map[uuid.UUID]*dashboard.Widget
+}
+
+var _ dashboard.Repository = (*widgetStore)(nil)
+
+func (r *widgetStore) Create(_ context.Context, w *dashboard.Widget) error {
+	w.ID, w.CreatedAt, w.UpdatedAt = uuid.New(), time.Now().UTC(), time.Now().UTC()
+	if w.Params == nil {
+		w.Params = json.RawMessage(`{}`)
+	}
+	stored := *w
+	r.byID[w.ID] = &stored
+	return nil
+}
+
+func (r *widgetStore) FindByID(_ context.Context, _ string, id uuid.UUID) (*dashboard.Widget, error) {
+	w, ok := r.byID[id]
+	if !ok {
+		return nil, errFakeNotFound
+	}
+	found := *w
+	return &found, nil
+}
+
+func (r *widgetStore) List(context.Context, string) ([]*dashboard.Widget, error) {
+	list := []*dashboard.Widget{}
+	for _, w := range r.byID {
+		list = append(list, w)
+	}
+	return list, nil
+}
+
+func (r *widgetStore) Count(context.Context, string) (int, error) {
+	return len(r.byID), nil
+}
+
+func (r *widgetStore) Update(_ context.Context, w *dashboard.Widget) error {
+	w.UpdatedAt = time.Now().UTC()
+	stored := *w
+	r.byID[w.ID] = &stored
+	return nil
+}
+
+func (r *widgetStore) Delete(_ context.Context, _ string, id uuid.UUID) error {
+	delete(r.byID, id)
+	return nil
+}
+
+func TestDashboardHandler(t *testing.T) {
+	widgets := &widgetStore{byID: map[uuid.UUID]*dashboard.Widget{}}
+	licenseSvc := service.NewLicenseService(dashboardLicenses{}, nil, nil, seenActivations{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		&config.QueryConfig{MaxOffset: 10000}, zap.NewNop())
+	h := handler.NewDashboardHandler(licenseSvc, service.NewDashboardService(widgets, zap.NewNop()), zap.NewNop())
+	existing := &dashboard.Widget{OrgID: dashboard.DefaultOrgID, Kind: dashboard.KindSummary, Title: "Overview"}
+	if err := widgets.Create(context.Background(), existing); err != nil {
+		t.Fatal(err)
+	}
+	id := existing.ID.String()
+
+	runCases(t, []apiCase{
+		{name: "summary", method: http.MethodGet, pattern: "/dashboard/summary", handler: h.GetSummary, target: "/dashboard/summary", status: http.StatusOK},
+		{name: "create widget", method: http.MethodPost, pattern: "/dashboard/widgets", handler: h.CreateWidget, target: "/dashboard/widgets",
+			body: `{"kind":"timeseries","title":"Activations","position":1,"params":{"field":"created_at","bucket":"day"}}`, status: http.StatusCreated},
+		{name: "list widgets", method: http.MethodGet, pattern: "/dashboard/widgets", handler: h.ListWidgets, target: "/dashboard/widgets", status: http.StatusOK},
+		{name: "get widget", method: http.MethodGet, pattern: "/dashboard/widgets/:id", handler: h.GetWidget, target: "/dashboard/widgets/" + id, status: http.StatusOK},
+		{name: "update widget", method: http.MethodPatch, pattern: "/dashboard/widgets/:id", handler: h.UpdateWidget, target: "/dashboard/widgets/" + id,
+			body: `{"title":"Fleet overview"}`, status: http.StatusOK},
+		{name: "delete widget", method: http.MethodDelete, pattern: "/dashboard/widgets/:id", handler: h.DeleteWidget, target: "/dashboard/widgets/" + id, status: http.StatusNoContent},
+	})
+}
+
+// apiKeyStore keeps API keys in creation order.
+type apiKeyStore struct {
+	apikey.Repository
+	keys []*apikey.APIKey
+}
+
+func (r *apiKeyStore) Create(_ context.Context, k *apikey.APIKey) (uuid.UUID, error) {
+	k.ID, k.CreatedAt = uuid.New(), time.Now().UTC()
+	stored := *k
+	r.keys = append(r.keys, &stored)
+	return k.ID, nil
+}
+
+func (r *apiKeyStore) List(context.Context) ([]*apikey.APIKey, error) {
+	return r.keys, nil
+}
+
+func (r *apiKeyStore) Disable(_ context.Context, id uuid.UUID) error {
+	for _, k := range r.keys {
+		if k.ID == id {
+			k.IsEnabled = false
+			return nil
+		}
+	}
+	return errFakeNotFound
+}
+
+func TestAPIKeyHandler(t *testing.T) {
+	keys := &apiKeyStore{}
+	h := handler.NewAPIKeyHandler(service.NewAPIKeyService(keys, nil, cryptoprovider.Stdlib{}, nil, zap.NewNop()), zap.NewNop())
+
+	rec := serve(t, apiCase{method: http.MethodPost, pattern: "/apikeys", handler: h.Create, target: "/apikeys",
+		body: `{"description":"CI agent","scopes":["licenses:validate"]}`, status: http.StatusCreated})
+	if rec.Body.Len() == 0 {
+		t.Fatal("create returned no key")
+	}
+	if len(keys.keys) != 1 {
+		t.Fatalf("%d keys after create, want 1", len(keys.keys))
+	}
+
+	runCases(t, []apiCase{
+		{name: "list", method: http.MethodGet, pattern: "/apikeys", handler: h.List, target: "/apikeys", status: http.StatusOK},
+		{name: "revoke", method: http.MethodDelete, pattern: "/apikeys/:id", handler: h.Revoke, target: "/apikeys/" + keys.keys[0].ID.String(), status: http.StatusNoContent},
+	})
+}
+
+func TestSigningKeyHandler(t *testing.T) {
+	h := handler.NewSigningKeyHandler(newKeyring(t), zap.NewNop())
+
+	runCases(t, []apiCase{
+		{name: "list", method: http.MethodGet, pattern: "/signing-keys", handler: h.List, target: "/signing-keys", status: http.StatusOK},
+		{name: "rotate", method: http.MethodPost, pattern: "/signing-keys/rotate", handler: h.Rotate, target: "/signing-keys/rotate",
+			body: `{"key_ref":"` + writeSigningKey(t) + `"}`, status: http.StatusCreated},
+	})
+}
+
+// productCounts aggregates the license count of the acme product.
+type productCounts struct{ license.Repository }
+
+func (productCounts) Aggregate(_ context.Context, params license.AggregateParams) ([]license.AggregateRow, error) {
+	name := "acme"
+	group := make([]*string, len(params.GroupBy))
+	for i := range group {
+		group[i] = &name
+	}
+	return []license.AggregateRow{{Group: group, Value: 1}}, nil
+}
+
+func TestMetaHandler(t *testing.T) {
+	licenses := productCounts{}
+	statuses := service.NewCustomStatusService(&statusStore{}, licenses, &config.CustomStatusesConfig{RefreshInterval: time.Minute}, zap.NewNop())
+	licenseSvc := service.NewLicenseService(licenses, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, newKeyring(t), statuses,
+		&config.QueryConfig{MaxOffset: 10000}, zap.NewNop())
+	meta := service.NewMetaService(licenseSvc, licenses, statuses, nil, &config.ApprovalConfig{}, zap.NewNop())
+	h := handler.NewMetaHandler(meta, zap.NewNop())
+
+	runCases(t, []apiCase{
+		{name: "enums", method: http.MethodGet, pattern: "/meta/enums", handler: h.Enums, target: "/meta/enums", status: http.StatusOK},
+	})
+}
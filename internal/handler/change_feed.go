@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type ChangeFeedHandler struct {
+	service *service.ChangeFeedService
+	logger  *zap.Logger
+}
+
+func NewChangeFeedHandler(service *service.ChangeFeedService, logger *zap.Logger) *ChangeFeedHandler {
+	return &ChangeFeedHandler{
+		service: service,
+		logger:  logger.Named("ChangeFeedHandler"),
+	}
+}
+
+func (h *ChangeFeedHandler) List(c *gin.Context) {
+	var req dto.ListChangesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	changes, err := h.service.ListChanges(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Service failed to list license changes", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
+
+// Export streams all changes after since as NDJSON instead of one page.
+func (h *ChangeFeedHandler) Export(c *gin.Context) {
+	var req dto.ExportChangesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	stream := newNDJSONStream(c)
+	err := h.service.StreamChanges(c.Request.Context(), &req, func(change dto.LicenseChange) error {
+		return stream.Write(change)
+	})
+	stream.Finish(err)
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/handler/middleware"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type CommentHandler struct {
+	service *service.CommentService
+	logger  *zap.Logger
+}
+
+func NewCommentHandler(service *service.CommentService, logger *zap.Logger) *CommentHandler {
+	return &CommentHandler{
+		service: service,
+		logger:  logger.Named("CommentHandler"),
+	}
+}
+
+func (h *CommentHandler) Create(c *gin.Context) {
+	id, err := idgen.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate comment request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	created, err := h.service.Create(c.Request.Context(), id, &req, middleware.GetUserClaims(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *CommentHandler) List(c *gin.Context) {
+	id, err := idgen.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.ListCommentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	comments, total, err := h.service.List(c.Request.Context(), id, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.PaginatedCommentResponse{
+		Comments:   comments,
+		TotalCount: total,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	})
+}
+
+func (h *CommentHandler) Delete(c *gin.Context) {
+	id, err := idgen.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	commentIDStr := c.Param("commentId")
+	commentID, err := idgen.Parse(commentIDStr)
+	if err != nil {
+		h.logger.Warn("Invalid ID format for comment", zap.String("id_param", commentIDStr), zap.Error(err))
+		_ = c.Error(fmt.Errorf("%w: invalid comment id format", ierr.ErrValidation))
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id, commentID, middleware.GetUserClaims(c)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
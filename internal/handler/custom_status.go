@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type CustomStatusHandler struct {
+	service *service.CustomStatusService
+	logger  *zap.Logger
+}
+
+func NewCustomStatusHandler(service *service.CustomStatusService, logger *zap.Logger) *CustomStatusHandler {
+	return &CustomStatusHandler{
+		service: service,
+		logger:  logger.Named("CustomStatusHandler"),
+	}
+}
+
+func (h *CustomStatusHandler) List(c *gin.Context) {
+	statuses, err := h.service.List(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CustomStatusListResponse{Statuses: statuses})
+}
+
+func (h *CustomStatusHandler) Create(c *gin.Context) {
+	var req dto.CreateCustomStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate custom status request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	status, err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, status)
+}
+
+func (h *CustomStatusHandler) Update(c *gin.Context) {
+	var req dto.UpdateCustomStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate custom status update", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	status, err := h.service.Update(c.Request.Context(), license.LicenseStatus(c.Param("name")), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func (h *CustomStatusHandler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), license.LicenseStatus(c.Param("name"))); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
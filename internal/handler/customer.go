@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type CustomerHandler struct {
+	service        *service.CustomerService
+	licenseService *service.LicenseService
+	logger         *zap.Logger
+}
+
+func NewCustomerHandler(service *service.CustomerService, licenseService *service.LicenseService, logger *zap.Logger) *CustomerHandler {
+	return &CustomerHandler{
+		service:        service,
+		licenseService: licenseService,
+		logger:         logger.Named("CustomerHandler"),
+	}
+}
+
+func (h *CustomerHandler) Create(c *gin.Context) {
+	var req dto.CreateCustomerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate create customer request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	customer, err := h.service.CreateCustomer(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, customer)
+}
+
+func (h *CustomerHandler) List(c *gin.Context) {
+	var req dto.ListCustomersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	customers, total, err := h.service.ListCustomers(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.PaginatedCustomerResponse{
+		Customers:  customers,
+		TotalCount: total,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	})
+}
+
+func (h *CustomerHandler) Get(c *gin.Context) {
+	id, err := idgen.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	customer, err := h.service.GetCustomer(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, customer)
+}
+
+func (h *CustomerHandler) Update(c *gin.Context) {
+	id, err := idgen.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.UpdateCustomerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate update customer request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	customer, err := h.service.UpdateCustomer(c.Request.Context(), id, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, customer)
+}
+
+func (h *CustomerHandler) Delete(c *gin.Context) {
+	id, err := idgen.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err := h.service.DeleteCustomer(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Licenses lists the licenses of a customer with the filters and paging of
+// the license list.
+func (h *CustomerHandler) Licenses(c *gin.Context) {
+	id, err := idgen.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.ListLicensesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	if _, err := h.service.GetCustomer(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	customerID := id.String()
+	req.CustomerID = &customerID
+	licenses, total, err := h.licenseService.ListLicenses(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	licenseResponses := make([]*dto.LicenseResponse, len(licenses))
+	for i, lic := range licenses {
+		licenseResponses[i] = dto.NewLicenseResponse(lic)
+	}
+
+	c.JSON(http.StatusOK, dto.PaginatedLicenseResponse{
+		Licenses:   licenseResponses,
+		TotalCount: total,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	})
+}
@@ -4,35 +4,64 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/domain/dashboard"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/handler/middleware"
 	"github.com/makkenzo/license-service-api/internal/service"
 	"go.uber.org/zap"
 )
 
 type DashboardHandler struct {
-	licenseService *service.LicenseService
-	logger         *zap.Logger
+	licenseService   *service.LicenseService
+	dashboardService *service.DashboardService
+	logger           *zap.Logger
 }
 
-func NewDashboardHandler(licenseService *service.LicenseService, logger *zap.Logger) *DashboardHandler {
+func NewDashboardHandler(licenseService *service.LicenseService, dashboardService *service.DashboardService, logger *zap.Logger) *DashboardHandler {
 	return &DashboardHandler{
-		licenseService: licenseService,
-		logger:         logger.Named("DashboardHandler"),
+		licenseService:   licenseService,
+		dashboardService: dashboardService,
+		logger:           logger.Named("DashboardHandler"),
 	}
 }
 
+// orgID scopes dashboard widgets to the organization that owns the caller's
+// account in the identity provider.
+func orgID(c *gin.Context) string {
+	if claims := middleware.GetUserClaims(c); claims != nil && claims.OrgID != "" {
+		return claims.OrgID
+	}
+	return dashboard.DefaultOrgID
+}
+
 // GetSummary godoc
 // @Summary      Get dashboard summary
 // @Description  Retrieves aggregated statistics about licenses for the dashboard.
 // @Tags         dashboard
 // @Accept       json
 // @Produce      json
+// @Param        widget_id query string false "Resolve parameters stored on this summary widget"
+// @Param        expiring_period_days query int false "Window for expiring licenses, overrides the widget"
 // @Success      200 {object} dto.DashboardSummaryResponse "Dashboard summary data"
 // @Failure      500 {object} map[string]string "Internal Server Error"
 // @Router       /dashboard/summary [get]
 func (h *DashboardHandler) GetSummary(c *gin.Context) {
 	h.logger.Info("Received request for dashboard summary")
 
-	summary, err := h.licenseService.GetDashboardSummary(c.Request.Context())
+	var req dto.DashboardSummaryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind dashboard summary query", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	params, err := h.dashboardService.ResolveSummary(c.Request.Context(), orgID(c), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	summary, err := h.licenseService.GetDashboardSummary(c.Request.Context(), params.ExpiringPeriodDays)
 	if err != nil {
 
 		h.logger.Error("Failed to get dashboard summary from service", zap.Error(err))
@@ -42,3 +71,89 @@ func (h *DashboardHandler) GetSummary(c *gin.Context) {
 
 	c.JSON(http.StatusOK, summary)
 }
+
+func (h *DashboardHandler) GetTimeseries(c *gin.Context) {
+	var req dto.DashboardTimeseriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind dashboard timeseries query", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	params, err := h.dashboardService.ResolveTimeseries(c.Request.Context(), orgID(c), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	resp, err := h.licenseService.GetLicenseTimeseries(c.Request.Context(), params)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *DashboardHandler) CreateWidget(c *gin.Context) {
+	var req dto.CreateWidgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate create widget request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	w, err := h.dashboardService.CreateWidget(c.Request.Context(), orgID(c), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, w)
+}
+
+func (h *DashboardHandler) ListWidgets(c *gin.Context) {
+	widgets, err := h.dashboardService.ListWidgets(c.Request.Context(), orgID(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, widgets)
+}
+
+func (h *DashboardHandler) GetWidget(c *gin.Context) {
+	w, err := h.dashboardService.GetWidget(c.Request.Context(), orgID(c), c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, w)
+}
+
+func (h *DashboardHandler) UpdateWidget(c *gin.Context) {
+	var req dto.UpdateWidgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate update widget request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	w, err := h.dashboardService.UpdateWidget(c.Request.Context(), orgID(c), c.Param("id"), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, w)
+}
+
+func (h *DashboardHandler) DeleteWidget(c *gin.Context) {
+	if err := h.dashboardService.DeleteWidget(c.Request.Context(), orgID(c), c.Param("id")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
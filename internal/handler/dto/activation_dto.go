@@ -0,0 +1,173 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/activation"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+)
+
+type ActivationRequest struct {
+	LicenseKey  string `json:"license_key" binding:"required,max=256"`
+	ProductName string `json:"product_name" binding:"required,max=255"`
+	DeviceID    string `json:"device_id" binding:"required,max=255"`
+}
+
+type ActivationResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	LicenseID   uuid.UUID  `json:"license_id"`
+	DeviceID    string     `json:"device_id"`
+	IP          string     `json:"ip,omitempty"`
+	UserAgent   string     `json:"user_agent,omitempty"`
+	ActivatedAt time.Time  `json:"activated_at"`
+	Deactivated *time.Time `json:"deactivated_at,omitempty"`
+	LastSeenAt  *time.Time `json:"last_seen_at,omitempty"`
+	AppVersion  string     `json:"app_version,omitempty"`
+	Host        *HostInfo  `json:"host,omitempty"`
+}
+
+func NewActivationResponse(a *activation.Activation) *ActivationResponse {
+	resp := &ActivationResponse{
+		ID:          a.ID,
+		LicenseID:   a.LicenseID,
+		DeviceID:    a.DeviceID,
+		IP:          a.IP,
+		UserAgent:   a.UserAgent,
+		ActivatedAt: a.ActivatedAt,
+		Deactivated: a.DeactivatedAt,
+		LastSeenAt:  a.LastSeenAt,
+		AppVersion:  a.AppVersion,
+	}
+	if a.Host != (activation.HostInfo{}) {
+		host := HostInfo(a.Host)
+		resp.Host = &host
+	}
+	return resp
+}
+
+// HeartbeatRequest is sent periodically by agents on activated devices.
+type HeartbeatRequest struct {
+	LicenseKey  string    `json:"license_key" binding:"required,max=256"`
+	ProductName string    `json:"product_name" binding:"required,max=255"`
+	DeviceID    string    `json:"device_id" binding:"required,max=255"`
+	AppVersion  string    `json:"app_version,omitempty" binding:"max=64"`
+	Host        *HostInfo `json:"host,omitempty"`
+}
+
+type HostInfo struct {
+	Hostname  string `json:"hostname,omitempty" binding:"max=255"`
+	OS        string `json:"os,omitempty" binding:"max=64"`
+	OSVersion string `json:"os_version,omitempty" binding:"max=64"`
+	Arch      string `json:"arch,omitempty" binding:"max=32"`
+}
+
+// ReclaimActivationsRequest overrides HEARTBEAT_STALEAFTER.
+type ReclaimActivationsRequest struct {
+	StaleAfterHours *int `form:"stale_after_hours" binding:"omitempty,gte=1,lte=8760"`
+}
+
+type ReclaimActivationsResponse struct {
+	LicenseID   uuid.UUID             `json:"license_id"`
+	StaleBefore time.Time             `json:"stale_before"`
+	Reclaimed   []*ActivationResponse `json:"reclaimed"`
+}
+
+func NewReclaimActivationsResponse(licenseID uuid.UUID, staleBefore time.Time, reclaimed []*activation.Activation) *ReclaimActivationsResponse {
+	resp := &ReclaimActivationsResponse{
+		LicenseID:   licenseID,
+		StaleBefore: staleBefore,
+		Reclaimed:   make([]*ActivationResponse, len(reclaimed)),
+	}
+	for i, a := range reclaimed {
+		resp.Reclaimed[i] = NewActivationResponse(a)
+	}
+	return resp
+}
+
+type ListActivationsRequest struct {
+	IncludeInactive bool `form:"include_inactive"`
+}
+
+// ActivationListResponse reports seat usage of a license: SeatsUsed counts
+// active activations only, even when inactive ones are listed.
+type ActivationListResponse struct {
+	LicenseID      uuid.UUID             `json:"license_id"`
+	MaxActivations int                   `json:"max_activations"`
+	SeatsUsed      int                   `json:"seats_used"`
+	Activations    []*ActivationResponse `json:"activations"`
+}
+
+func NewActivationListResponse(licenseID uuid.UUID, maxActivations int, activations []*activation.Activation) *ActivationListResponse {
+	resp := &ActivationListResponse{
+		LicenseID:      licenseID,
+		MaxActivations: maxActivations,
+		Activations:    make([]*ActivationResponse, len(activations)),
+	}
+	for i, a := range activations {
+		if a.Active() {
+			resp.SeatsUsed++
+		}
+		resp.Activations[i] = NewActivationResponse(a)
+	}
+	return resp
+}
+
+type UsageSummaryRequest struct {
+	ProductName string `form:"product_name" binding:"required,max=255"`
+}
+
+type SeatUsage struct {
+	Used      int `json:"used"`
+	Entitled  int `json:"entitled"`
+	Available int `json:"available"`
+}
+
+// UsageSummaryResponse is what a server product shows its own admin about
+// the license it runs under.
+type UsageSummaryResponse struct {
+	ProductName string                `json:"product_name"`
+	Status      license.LicenseStatus `json:"status"`
+	ExpiresAt   *time.Time            `json:"expires_at,omitempty"`
+	Seats       SeatUsage             `json:"seats"`
+	GeneratedAt time.Time             `json:"generated_at"`
+}
+
+func NewUsageSummaryResponse(lic *license.License, used, entitled int, now time.Time) *UsageSummaryResponse {
+	resp := &UsageSummaryResponse{
+		ProductName: lic.ProductName,
+		Status:      lic.Status,
+		Seats: SeatUsage{
+			Used:      used,
+			Entitled:  entitled,
+			Available: max(entitled-used, 0),
+		},
+		GeneratedAt: now,
+	}
+	if lic.ExpiresAt.Valid {
+		resp.ExpiresAt = &lic.ExpiresAt.Time
+	}
+	return resp
+}
+
+// OfflineChallengeResponse starts an offline activation. Code is entered on
+// the machine without network access and handed to the vendor, who answers
+// it with a signed response before ExpiresAt.
+type OfflineChallengeResponse struct {
+	ChallengeID uuid.UUID `json:"challenge_id"`
+	LicenseID   uuid.UUID `json:"license_id"`
+	DeviceID    string    `json:"device_id"`
+	Code        string    `json:"code"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// OfflineActivationResponse is the answer to a challenge: Response is a
+// compact JWS the agent verifies against the JWKS it ships with.
+type OfflineActivationResponse struct {
+	Response  string    `json:"response"`
+	KeyID     string    `json:"kid"`
+	LicenseID uuid.UUID `json:"license_id"`
+	DeviceID  string    `json:"device_id"`
+	// Created is false when the device was already activated.
+	Created bool `json:"created"`
+}
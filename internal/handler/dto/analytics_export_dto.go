@@ -0,0 +1,14 @@
+package dto
+
+type RunAnalyticsExportRequest struct {
+	// Date is the UTC day to export; yesterday when empty.
+	Date string `json:"date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+type AnalyticsExportResponse struct {
+	Date      string `json:"date"`
+	Bucket    string `json:"bucket"`
+	ObjectKey string `json:"object_key"`
+	Format    string `json:"format"`
+	Rows      int64  `json:"rows"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+)
+
+type UsageStatementRequest struct {
+	// Month is formatted as YYYY-MM and defaults to the current UTC month.
+	Month string  `form:"month" binding:"omitempty,datetime=2006-01"`
+	OrgID *string `form:"org_id" binding:"omitempty,max=255"`
+}
+
+type APIKeyUsage struct {
+	APIKeyID    uuid.UUID `json:"api_key_id"`
+	Prefix      string    `json:"prefix,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Calls       int64     `json:"calls"`
+	FailedCalls int64     `json:"failed_calls"`
+}
+
+// OrgUsageStatement is the usage of one organization. Keys breaks the
+// validate calls down by API key.
+type OrgUsageStatement struct {
+	OrgID               string         `json:"org_id"`
+	AdminCalls          int64          `json:"admin_calls"`
+	AdminFailedCalls    int64          `json:"admin_failed_calls"`
+	ValidateCalls       int64          `json:"validate_calls"`
+	ValidateFailedCalls int64          `json:"validate_failed_calls"`
+	Keys                []*APIKeyUsage `json:"keys"`
+}
+
+type UsageStatementResponse struct {
+	Month         string               `json:"month"`
+	Organizations []*OrgUsageStatement `json:"organizations"`
+}
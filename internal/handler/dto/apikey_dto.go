@@ -4,11 +4,14 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/idgen"
 )
 
 type CreateAPIKeyRequest struct {
-	Description string    `json:"description" binding:"required"`
-	ProductID   uuid.UUID `json:"product_id,omitempty"`
+	Description string   `json:"description" binding:"required"`
+	ProductID   idgen.ID `json:"product_id,omitempty"`
+	// Scopes defaults to all scopes.
+	Scopes []string `json:"scopes,omitempty" binding:"omitempty,dive,oneof=licenses:validate activations:write usage:write usage:read"`
 }
 
 type CreateAPIKeyResponse struct {
@@ -17,6 +20,9 @@ type CreateAPIKeyResponse struct {
 	Prefix      string    `json:"prefix"`
 	Description string    `json:"description"`
 	ProductID   uuid.UUID `json:"product_id,omitempty"`
+	OrgID       string    `json:"org_id,omitempty"`
+	Scopes      []string  `json:"scopes"`
+	CreatedBy   string    `json:"created_by"`
 	CreatedAt   time.Time `json:"created_at"`
 }
 
@@ -25,6 +31,9 @@ type APIKeyResponse struct {
 	Prefix      string     `json:"prefix"`
 	Description string     `json:"description"`
 	ProductID   uuid.UUID  `json:"product_id,omitempty"`
+	OrgID       string     `json:"org_id,omitempty"`
+	Scopes      []string   `json:"scopes"`
+	CreatedBy   string     `json:"created_by,omitempty"`
 	IsEnabled   bool       `json:"is_enabled"`
 	CreatedAt   time.Time  `json:"created_at"`
 	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/approval"
+)
+
+type PutProtectedKeyRequest struct {
+	Description string `json:"description" binding:"max=1000"`
+}
+
+type ListApprovalsRequest struct {
+	Status    *string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	LicenseID *string `form:"license_id"`
+	Limit     int     `form:"limit,default=20" binding:"omitempty,gte=0,lte=100"`
+	Offset    int     `form:"offset,default=0" binding:"omitempty,gte=0"`
+}
+
+// ApprovalResponse is a held license update. Changes is the update request
+// body; ProtectedKeys are the protected keys it changes.
+type ApprovalResponse struct {
+	ID            uuid.UUID       `json:"id"`
+	LicenseID     uuid.UUID       `json:"license_id"`
+	Changes       json.RawMessage `json:"changes"`
+	ProtectedKeys []string        `json:"protected_keys"`
+	RequestedBy   string          `json:"requested_by"`
+	Status        approval.Status `json:"status"`
+	Expired       bool            `json:"expired"`
+	DecidedBy     *string         `json:"decided_by,omitempty"`
+	DecidedAt     *time.Time      `json:"decided_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	ExpiresAt     time.Time       `json:"expires_at"`
+}
+
+func NewApprovalResponse(r *approval.Request, now time.Time) *ApprovalResponse {
+	resp := &ApprovalResponse{
+		ID:            r.ID,
+		LicenseID:     r.LicenseID,
+		Changes:       r.Changes,
+		ProtectedKeys: r.ProtectedKeys,
+		RequestedBy:   r.RequestedBy,
+		Status:        r.Status,
+		Expired:       r.Expired(now),
+		CreatedAt:     r.CreatedAt,
+		ExpiresAt:     r.ExpiresAt,
+	}
+	if r.DecidedBy.Valid {
+		resp.DecidedBy = &r.DecidedBy.String
+	}
+	if r.DecidedAt.Valid {
+		resp.DecidedAt = &r.DecidedAt.Time
+	}
+	return resp
+}
+
+type PaginatedApprovalResponse struct {
+	Approvals  []*ApprovalResponse `json:"approvals"`
+	TotalCount int64               `json:"totalCount"`
+	Limit      int                 `json:"limit"`
+	Offset     int                 `json:"offset"`
+}
+
+// ApproveResponse is the approved request and the license it updated.
+type ApproveResponse struct {
+	Approval *ApprovalResponse `json:"approval"`
+	License  *LicenseResponse  `json:"license"`
+}
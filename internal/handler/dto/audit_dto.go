@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditDiffRequest struct {
+	ChangedOnly bool `form:"changed_only"`
+}
+
+type AuditActorResponse struct {
+	Type      string `json:"type"`
+	ID        string `json:"id,omitempty"`
+	IP        string `json:"ip,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+}
+
+type AuditFieldDiff struct {
+	Field  string      `json:"field"`
+	Old    interface{} `json:"old"`
+	New    interface{} `json:"new"`
+	Change string      `json:"change"`
+}
+
+type AuditDiffResponse struct {
+	ID         uuid.UUID          `json:"id"`
+	EntityType string             `json:"entity_type"`
+	EntityID   uuid.UUID          `json:"entity_id"`
+	Action     string             `json:"action"`
+	Actor      AuditActorResponse `json:"actor"`
+	CreatedAt  time.Time          `json:"created_at"`
+	Fields     []AuditFieldDiff   `json:"fields"`
+}
+
+// ListAuditRequest filters the audit log. EntityID is ignored by the
+// per-license endpoint, which takes it from the path.
+type ListAuditRequest struct {
+	EntityID      *string    `form:"entity_id"`
+	Action        *string    `form:"action" binding:"omitempty,oneof=create update update_status rotate_key"`
+	ActorType     *string    `form:"actor_type" binding:"omitempty,oneof=user api_key customer system"`
+	ActorID       *string    `form:"actor_id"`
+	CreatedAfter  *time.Time `form:"created_after"`
+	CreatedBefore *time.Time `form:"created_before"`
+	Limit         int        `form:"limit,default=20" binding:"omitempty,gte=0,lte=100"`
+	Offset        int        `form:"offset,default=0" binding:"omitempty,gte=0"`
+}
+
+type AuditEntryResponse struct {
+	ID         uuid.UUID          `json:"id"`
+	EntityType string             `json:"entity_type"`
+	EntityID   uuid.UUID          `json:"entity_id"`
+	Action     string             `json:"action"`
+	Actor      AuditActorResponse `json:"actor"`
+	Before     json.RawMessage    `json:"before"`
+	After      json.RawMessage    `json:"after"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type PaginatedAuditResponse struct {
+	Entries    []*AuditEntryResponse `json:"entries"`
+	TotalCount int64                 `json:"totalCount"`
+	Limit      int                   `json:"limit"`
+	Offset     int                   `json:"offset"`
+}
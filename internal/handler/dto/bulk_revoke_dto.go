@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BulkRevokeFilter selects the licenses to revoke. At least one field must
+// be set; CreatedAfter is inclusive and CreatedBefore exclusive.
+type BulkRevokeFilter struct {
+	ProductName   *string    `json:"product_name" binding:"omitempty,max=100"`
+	Type          *string    `json:"type" binding:"omitempty,max=50"`
+	CreatedAfter  *time.Time `json:"created_after"`
+	CreatedBefore *time.Time `json:"created_before"`
+}
+
+// BulkRevokeRequest without a confirmation token only previews the revoke;
+// repeating it with the token from the preview and the same filter revokes.
+type BulkRevokeRequest struct {
+	Filter            BulkRevokeFilter `json:"filter"`
+	ConfirmationToken string           `json:"confirmation_token" binding:"omitempty,max=100"`
+}
+
+type BulkRevokeFailure struct {
+	ID   uuid.UUID `json:"id"`
+	Code string    `json:"code"`
+}
+
+type BulkRevokeResponse struct {
+	DryRun bool `json:"dry_run"`
+	// Matched counts licenses the filter selects that are not revoked yet.
+	Matched           int64               `json:"matched"`
+	ConfirmationToken string              `json:"confirmation_token,omitempty"`
+	TokenExpiresAt    *time.Time          `json:"token_expires_at,omitempty"`
+	Revoked           int                 `json:"revoked"`
+	Failed            []BulkRevokeFailure `json:"failed,omitempty"`
+	// Incomplete means the revoke stopped early; preview again to revoke
+	// the licenses that are left.
+	Incomplete bool `json:"incomplete,omitempty"`
+}
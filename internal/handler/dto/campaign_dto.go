@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/campaign"
+)
+
+// CampaignFilterRequest selects the licenses whose holders are mailed.
+// Without Statuses revoked licenses are left out; ExpiresWithinDays counts
+// from when the campaign is created.
+type CampaignFilterRequest struct {
+	ProductNames      []string `json:"product_names" binding:"max=20,dive,min=1,max=255"`
+	Statuses          []string `json:"statuses" binding:"max=20,dive,license_status"`
+	ExpiresWithinDays int      `json:"expires_within_days" binding:"omitempty,gte=1,lte=3650"`
+}
+
+type CreateCampaignRequest struct {
+	Name    string                `json:"name" binding:"required,max=255"`
+	Subject string                `json:"subject" binding:"required,max=200"`
+	Message string                `json:"message" binding:"required,max=20000"`
+	Filter  CampaignFilterRequest `json:"filter"`
+}
+
+type ListCampaignsQuery struct {
+	Limit int `form:"limit,default=20" binding:"omitempty,gte=1,lte=100"`
+}
+
+// CampaignRecipientCounts counts the recipients of a campaign by status.
+// Total is zero until the recipients have been picked.
+type CampaignRecipientCounts struct {
+	Total        int64 `json:"total"`
+	Pending      int64 `json:"pending"`
+	Sent         int64 `json:"sent"`
+	Failed       int64 `json:"failed"`
+	Unsubscribed int64 `json:"unsubscribed"`
+}
+
+type CampaignResponse struct {
+	ID          uuid.UUID                `json:"id"`
+	Name        string                   `json:"name"`
+	Subject     string                   `json:"subject"`
+	Message     string                   `json:"message"`
+	Filter      campaign.Filter          `json:"filter"`
+	Status      campaign.Status          `json:"status"`
+	RequestedBy string                   `json:"requested_by,omitempty"`
+	TaskID      string                   `json:"task_id,omitempty"`
+	Error       string                   `json:"error,omitempty"`
+	Recipients  *CampaignRecipientCounts `json:"recipients,omitempty"`
+	CreatedAt   time.Time                `json:"created_at"`
+	StartedAt   *time.Time               `json:"started_at,omitempty"`
+	FinishedAt  *time.Time               `json:"finished_at,omitempty"`
+}
+
+type CampaignListResponse struct {
+	Campaigns []CampaignResponse `json:"campaigns"`
+}
+
+type ListCampaignRecipientsQuery struct {
+	Status *campaign.RecipientStatus `form:"status" binding:"omitempty,oneof=pending sent failed unsubscribed"`
+	Limit  int                       `form:"limit,default=50" binding:"omitempty,gte=1,lte=500"`
+	Offset int                       `form:"offset,default=0" binding:"omitempty,gte=0"`
+}
+
+type CampaignRecipientResponse struct {
+	ID        uuid.UUID                `json:"id"`
+	Email     string                   `json:"email"`
+	LicenseID uuid.UUID                `json:"license_id"`
+	Batch     int                      `json:"batch"`
+	Status    campaign.RecipientStatus `json:"status"`
+	Attempts  int                      `json:"attempts"`
+	LastError string                   `json:"last_error,omitempty"`
+	SentAt    *time.Time               `json:"sent_at,omitempty"`
+}
+
+type PaginatedCampaignRecipientsResponse struct {
+	Recipients []CampaignRecipientResponse `json:"recipients"`
+	TotalCount int64                       `json:"total_count"`
+	Limit      int                         `json:"limit"`
+	Offset     int                         `json:"offset"`
+}
+
+// UnsubscribeRequest carries the token of an unsubscribe link, in the
+// query string when the link is opened and in the body when a mail client
+// posts it.
+type UnsubscribeRequest struct {
+	Token string `form:"token" json:"token" binding:"required,max=200"`
+}
+
+type UnsubscribeResponse struct {
+	Unsubscribed bool `json:"unsubscribed"`
+}
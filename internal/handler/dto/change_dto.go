@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ListChangesRequest struct {
+	Since  string `form:"since"`
+	Limit  int    `form:"limit,default=100" binding:"omitempty,gte=1,lte=1000"`
+	Fields string `form:"fields"`
+}
+
+// ExportChangesRequest streams every change after Since, so it has no
+// limit.
+type ExportChangesRequest struct {
+	Since  string `form:"since"`
+	Fields string `form:"fields"`
+}
+
+// SubscribeChangesRequest is filled from a SubscribeLicenseEvents call.
+// The stream starts after Since and never ends on its own.
+type SubscribeChangesRequest struct {
+	Since       int64
+	Fields      []string
+	ProductName *string
+	CustomerID  *string
+}
+
+type LicenseChange struct {
+	ID        uuid.UUID `json:"id"`
+	Operation string    `json:"operation"`
+	Fields    []string  `json:"fields"`
+	Version   int64     `json:"version"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type LicenseChangesResponse struct {
+	Changes    []LicenseChange `json:"changes"`
+	NextCursor string          `json:"next_cursor"`
+	HasMore    bool            `json:"has_more"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "github.com/makkenzo/license-service-api/internal/domain/comment"
+
+type CreateCommentRequest struct {
+	Body string `json:"body" binding:"required,max=10000"`
+}
+
+type ListCommentsRequest struct {
+	Limit  int `form:"limit,default=50" binding:"omitempty,gte=1,lte=200"`
+	Offset int `form:"offset,default=0" binding:"omitempty,gte=0"`
+}
+
+type PaginatedCommentResponse struct {
+	Comments   []*comment.Comment `json:"comments"`
+	TotalCount int64              `json:"totalCount"`
+	Limit      int                `json:"limit"`
+	Offset     int                `json:"offset"`
+}
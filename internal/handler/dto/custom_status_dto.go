@@ -0,0 +1,20 @@
+package dto
+
+import "github.com/makkenzo/license-service-api/internal/domain/customstatus"
+
+type CreateCustomStatusRequest struct {
+	Name        string                `json:"name" binding:"required,max=48"`
+	Behavior    customstatus.Behavior `json:"behavior" binding:"required,oneof=valid warn invalid"`
+	Description string                `json:"description" binding:"max=500"`
+}
+
+// UpdateCustomStatusRequest changes only the fields it sets; the name of a
+// status cannot change.
+type UpdateCustomStatusRequest struct {
+	Behavior    *customstatus.Behavior `json:"behavior" binding:"omitempty,oneof=valid warn invalid"`
+	Description *string                `json:"description" binding:"omitempty,max=500"`
+}
+
+type CustomStatusListResponse struct {
+	Statuses []*customstatus.Status `json:"statuses"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "github.com/makkenzo/license-service-api/internal/domain/customer"
+
+type CreateCustomerRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+	Name  string `json:"name" binding:"max=255"`
+}
+
+// UpdateCustomerRequest changes the customer on all of its licenses too.
+type UpdateCustomerRequest struct {
+	Email *string `json:"email" binding:"omitempty,email,max=255"`
+	Name  *string `json:"name" binding:"omitempty,max=255"`
+}
+
+// ListCustomersRequest searches customers; Email and Name match
+// case-insensitive substrings.
+type ListCustomersRequest struct {
+	Email  *string `form:"email" binding:"omitempty,max=255"`
+	Name   *string `form:"name" binding:"omitempty,max=255"`
+	Limit  int     `form:"limit,default=20" binding:"omitempty,gte=0,lte=100"`
+	Offset int     `form:"offset,default=0" binding:"omitempty,gte=0"`
+}
+
+type PaginatedCustomerResponse struct {
+	Customers  []*customer.Customer `json:"customers"`
+	TotalCount int64                `json:"totalCount"`
+	Limit      int                  `json:"limit"`
+	Offset     int                  `json:"offset"`
+}
@@ -1,8 +1,10 @@
 package dto
 
 import (
+	"encoding/json"
 	"time"
 
+	"github.com/makkenzo/license-service-api/internal/domain/dashboard"
 	"github.com/makkenzo/license-service-api/internal/domain/license"
 )
 
@@ -12,6 +14,47 @@ type DashboardSummaryResponse struct {
 	TypeCounts    map[string]int64                `json:"typeCounts"`
 	ExpiringSoon  ExpiringSoonSummary             `json:"expiringSoon"`
 	ProductCounts map[string]int64                `json:"productCounts"`
+	TagCounts     map[string]int64                `json:"tagCounts"`
+	// ActiveInstallations counts activations that sent a heartbeat within
+	// the window.
+	ActiveInstallations ActiveInstallationsSummary `json:"activeInstallations"`
+}
+
+// DashboardSummaryRequest resolves the parameters of WidgetID when given;
+// explicit query parameters override the stored ones.
+type DashboardSummaryRequest struct {
+	WidgetID           *string `form:"widget_id"`
+	ExpiringPeriodDays *int    `form:"expiring_period_days" binding:"omitempty,gte=1,lte=365"`
+}
+
+type DashboardTimeseriesRequest struct {
+	WidgetID    *string                `form:"widget_id"`
+	Field       *string                `form:"field" binding:"omitempty,oneof=created_at issued_at expires_at"`
+	Bucket      *string                `form:"bucket" binding:"omitempty,oneof=day week month year"`
+	Metric      *string                `form:"metric" binding:"omitempty,oneof=count customers"`
+	Status      *license.LicenseStatus `form:"status" binding:"omitempty,license_status"`
+	ProductName *string                `form:"product_name"`
+	Type        *string                `form:"type"`
+}
+
+type CreateWidgetRequest struct {
+	Kind     dashboard.WidgetKind `json:"kind" binding:"required,oneof=summary timeseries"`
+	Title    string               `json:"title" binding:"max=200"`
+	Position int                  `json:"position" binding:"gte=0"`
+	Params   json.RawMessage      `json:"params"`
+}
+
+// UpdateWidgetRequest cannot change the kind, since the stored params are
+// only meaningful for the kind they were validated against.
+type UpdateWidgetRequest struct {
+	Title    *string         `json:"title" binding:"omitempty,max=200"`
+	Position *int            `json:"position" binding:"omitempty,gte=0"`
+	Params   json.RawMessage `json:"params"`
+}
+
+type ActiveInstallationsSummary struct {
+	Count       int64 `json:"count"`
+	WindowHours int   `json:"windowHours"`
 }
 
 type ExpiringSoonSummary struct {
@@ -25,3 +68,48 @@ type LicenseInfo struct {
 	ExpiresAt   time.Time `json:"expiresAt"`
 	ProductName string    `json:"productName"`
 }
+
+type AggregateLicensesRequest struct {
+	GroupBy     string                 `form:"group_by" binding:"required,max=200"`
+	Metric      string                 `form:"metric,default=count" binding:"omitempty,oneof=count customers"`
+	Status      *license.LicenseStatus `form:"status" binding:"omitempty,license_status"`
+	ProductName *string                `form:"product_name"`
+	Type        *string                `form:"type"`
+}
+
+type AggregateLicensesResponse struct {
+	GroupBy []string             `json:"groupBy"`
+	Metric  string               `json:"metric"`
+	Total   int64                `json:"total"`
+	Groups  []AggregateGroupItem `json:"groups"`
+}
+
+// AggregateGroupItem keys Group by the requested dimension as written in
+// group_by, e.g. "product" or "created_at:month". Bucketed dates are the
+// bucket start formatted as YYYY-MM-DD.
+type AggregateGroupItem struct {
+	Group map[string]*string `json:"group"`
+	Value int64              `json:"value"`
+}
+
+func NewAggregateLicensesResponse(dims []license.AggregateDimension, metric string, rows []license.AggregateRow) *AggregateLicensesResponse {
+	resp := &AggregateLicensesResponse{
+		GroupBy: make([]string, len(dims)),
+		Metric:  metric,
+		Groups:  make([]AggregateGroupItem, len(rows)),
+	}
+	for i, dim := range dims {
+		resp.GroupBy[i] = dim.String()
+	}
+	for i, row := range rows {
+		item := AggregateGroupItem{Group: make(map[string]*string, len(dims)), Value: row.Value}
+		for j, name := range resp.GroupBy {
+			item.Group[name] = row.Group[j]
+		}
+		resp.Groups[i] = item
+		if metric == license.MetricCount {
+			resp.Total += row.Value
+		}
+	}
+	return resp
+}
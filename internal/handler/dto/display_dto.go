@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/display"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+)
+
+// DisplayRequest opts a response into display values. Locale and Timezone
+// override the customer's, kept under the "locale" and "timezone" license
+// metadata keys.
+type DisplayRequest struct {
+	Display  bool   `form:"display"`
+	Locale   string `form:"locale" binding:"omitempty,max=35"`
+	Timezone string `form:"timezone" binding:"omitempty,max=64"`
+}
+
+// DisplayValues are ready-to-show strings for the dates and numbers of a
+// response, formatted as the customer e-mails format them. Locale and
+// Timezone are the ones used after fallback.
+type DisplayValues struct {
+	Locale         string `json:"locale"`
+	Timezone       string `json:"timezone"`
+	IssuedAt       string `json:"issued_at,omitempty"`
+	StartsAt       string `json:"starts_at,omitempty"`
+	ExpiresAt      string `json:"expires_at,omitempty"`
+	RevokedAt      string `json:"revoked_at,omitempty"`
+	GraceExpiresAt string `json:"grace_expires_at,omitempty"`
+	// Expiry is the expiry relative to the time of the response, e.g.
+	// "expires in 12 days". Revoked licenses have none.
+	Expiry         string `json:"expiry,omitempty"`
+	MaxActivations string `json:"max_activations,omitempty"`
+}
+
+func NewLicenseDisplay(f *display.Formatter, lic *license.License, now time.Time) *DisplayValues {
+	d := &DisplayValues{
+		Locale:         f.Locale(),
+		Timezone:       f.Timezone(),
+		MaxActivations: f.Number(int64(lic.MaxActivations)),
+	}
+	if lic.IssuedAt.Valid {
+		d.IssuedAt = f.DateTime(lic.IssuedAt.Time)
+	}
+	if lic.StartsAt.Valid {
+		d.StartsAt = f.DateTime(lic.StartsAt.Time)
+	}
+	if lic.ExpiresAt.Valid {
+		d.ExpiresAt = f.DateTime(lic.ExpiresAt.Time)
+		if lic.Status != license.StatusRevoked {
+			d.Expiry = f.Expiry(lic.ExpiresAt.Time, now)
+		}
+	}
+	if lic.RevokedAt.Valid {
+		d.RevokedAt = f.DateTime(lic.RevokedAt.Time)
+	}
+	return d
+}
+
+// NewValidationDisplay formats only what the validation response itself
+// carries, which is less than an agent's caller may see of the license.
+func NewValidationDisplay(f *display.Formatter, resp *ValidateLicenseResponse, now time.Time) *DisplayValues {
+	d := &DisplayValues{
+		Locale:   f.Locale(),
+		Timezone: f.Timezone(),
+	}
+	if resp.StartsAt != nil {
+		d.StartsAt = f.DateTime(*resp.StartsAt)
+	}
+	if resp.ExpiresAt != nil {
+		d.ExpiresAt = f.DateTime(*resp.ExpiresAt)
+		if resp.Status == nil || *resp.Status != license.StatusRevoked {
+			d.Expiry = f.Expiry(*resp.ExpiresAt, now)
+		}
+	}
+	if resp.GraceExpiresAt != nil {
+		d.GraceExpiresAt = f.DateTime(*resp.GraceExpiresAt)
+	}
+	return d
+}
@@ -0,0 +1,16 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"github.com/makkenzo/license-service-api/internal/domain/entitlement"
+)
+
+type PutEntitlementRequest struct {
+	Type  entitlement.Type `json:"type" binding:"required"`
+	Value json.RawMessage  `json:"value" binding:"required"`
+}
+
+type EntitlementListResponse struct {
+	Entitlements []*entitlement.Entitlement `json:"entitlements"`
+}
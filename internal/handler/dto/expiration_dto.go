@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ExpirationPreviewRequest struct {
+	Days int `form:"days,default=7" binding:"gte=0,lte=365"`
+}
+
+// ExpiringLicense is an active license the expiration job will mark expired
+// at FlipsAt, the end of its grace period.
+type ExpiringLicense struct {
+	ID              uuid.UUID `json:"id"`
+	LicenseKey      string    `json:"license_key"`
+	ProductName     string    `json:"product_name"`
+	Type            string    `json:"type"`
+	CustomerEmail   *string   `json:"customer_email,omitempty"`
+	ExpiresAt       time.Time `json:"expires_at"`
+	GracePeriodDays int       `json:"grace_period_days"`
+	FlipsAt         time.Time `json:"flips_at"`
+}
+
+// ExpirationPreviewResponse lists what the expiration job would do without
+// doing it: Overdue licenses are flipped on its next run, Upcoming ones by
+// Until. The lists stop at a fixed size, the counts do not.
+type ExpirationPreviewResponse struct {
+	GeneratedAt   time.Time         `json:"generated_at"`
+	Days          int               `json:"days"`
+	Until         time.Time         `json:"until"`
+	OverdueCount  int               `json:"overdue_count"`
+	UpcomingCount int               `json:"upcoming_count"`
+	Truncated     bool              `json:"truncated"`
+	Overdue       []ExpiringLicense `json:"overdue"`
+	Upcoming      []ExpiringLicense `json:"upcoming"`
+}
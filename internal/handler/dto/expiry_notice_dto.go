@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ExpiryNotificationsRequest struct {
+	OptedOut *bool `json:"opted_out" binding:"required"`
+}
+
+type ExpiryNoticeResponse struct {
+	Kind       string    `json:"kind"`
+	DaysBefore int       `json:"days_before,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	SentAt     time.Time `json:"sent_at"`
+}
+
+type ExpiryNotificationsResponse struct {
+	LicenseID uuid.UUID              `json:"license_id"`
+	OptedOut  bool                   `json:"opted_out"`
+	Notices   []ExpiryNoticeResponse `json:"notices"`
+}
@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/extension"
+)
+
+type CreateExtensionRequest struct {
+	ExpiresAt time.Time `json:"expires_at" binding:"required"`
+	Reason    string    `json:"reason" binding:"max=2000"`
+}
+
+type DecideExtensionRequest struct {
+	Note string `json:"note" binding:"max=2000"`
+}
+
+type ListExtensionRequestsRequest struct {
+	Status *string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	Limit  int     `form:"limit,default=20" binding:"omitempty,gte=0,lte=100"`
+	Offset int     `form:"offset,default=0" binding:"omitempty,gte=0"`
+}
+
+type ExtensionRequestResponse struct {
+	ID                 uuid.UUID        `json:"id"`
+	LicenseID          uuid.UUID        `json:"license_id"`
+	RequestedExpiresAt time.Time        `json:"requested_expires_at"`
+	PreviousExpiresAt  time.Time        `json:"previous_expires_at"`
+	Reason             string           `json:"reason,omitempty"`
+	RequestedBy        string           `json:"requested_by"`
+	Status             extension.Status `json:"status"`
+	DecidedBy          *string          `json:"decided_by,omitempty"`
+	DecidedAt          *time.Time       `json:"decided_at,omitempty"`
+	DecisionNote       string           `json:"decision_note,omitempty"`
+	CreatedAt          time.Time        `json:"created_at"`
+}
+
+func NewExtensionRequestResponse(r *extension.Request) *ExtensionRequestResponse {
+	resp := &ExtensionRequestResponse{
+		ID:                 r.ID,
+		LicenseID:          r.LicenseID,
+		RequestedExpiresAt: r.RequestedExpiresAt,
+		PreviousExpiresAt:  r.PreviousExpiresAt,
+		Reason:             r.Reason,
+		RequestedBy:        r.RequestedBy,
+		Status:             r.Status,
+		DecisionNote:       r.DecisionNote,
+		CreatedAt:          r.CreatedAt,
+	}
+	if r.DecidedBy.Valid {
+		resp.DecidedBy = &r.DecidedBy.String
+	}
+	if r.DecidedAt.Valid {
+		resp.DecidedAt = &r.DecidedAt.Time
+	}
+	return resp
+}
+
+type PaginatedExtensionRequestResponse struct {
+	ExtensionRequests []*ExtensionRequestResponse `json:"extension_requests"`
+	TotalCount        int64                       `json:"totalCount"`
+	Limit             int                         `json:"limit"`
+	Offset            int                         `json:"offset"`
+}
+
+// ApproveExtensionResponse is the approved request and the license it
+// extended.
+type ApproveExtensionResponse struct {
+	ExtensionRequest *ExtensionRequestResponse `json:"extension_request"`
+	License          *LicenseResponse          `json:"license"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CheckoutRequest leases a floating seat to ClientID, e.g. a user or
+// process ID. Checking out again with the same ClientID renews the lease.
+type CheckoutRequest struct {
+	LicenseKey  string `json:"license_key" binding:"required,max=256"`
+	ProductName string `json:"product_name" binding:"required,max=255"`
+	ClientID    string `json:"client_id" binding:"required,max=255"`
+	// TTLSeconds defaults to FLOATING_LEASETTL and is capped at
+	// FLOATING_MAXLEASETTL.
+	TTLSeconds int `json:"ttl_seconds,omitempty" binding:"omitempty,gte=1"`
+}
+
+type CheckinRequest struct {
+	LicenseKey  string `json:"license_key" binding:"required,max=256"`
+	ProductName string `json:"product_name" binding:"required,max=255"`
+	ClientID    string `json:"client_id" binding:"required,max=255"`
+}
+
+// FloatingSeats reports how many seats of a floating license are checked
+// out out of how many it has.
+type FloatingSeats struct {
+	InUse int `json:"in_use"`
+	Max   int `json:"max"`
+}
+
+type CheckoutResponse struct {
+	LicenseID uuid.UUID     `json:"license_id"`
+	ClientID  string        `json:"client_id"`
+	ExpiresAt time.Time     `json:"expires_at"`
+	Seats     FloatingSeats `json:"seats"`
+}
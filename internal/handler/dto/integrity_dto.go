@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/integrity"
+)
+
+// StartIntegrityCheckRequest lists the repairs to apply; without any the
+// check only reports.
+type StartIntegrityCheckRequest struct {
+	Repairs []string `json:"repairs" binding:"omitempty,dive,oneof=expire_overdue delete_orphan_activations"`
+}
+
+type ListIntegrityChecksQuery struct {
+	Limit int `form:"limit,default=20" binding:"omitempty,gte=1,lte=100"`
+}
+
+type IntegrityCheckResponse struct {
+	ID          uuid.UUID         `json:"id"`
+	Status      integrity.Status  `json:"status"`
+	Repairs     []string          `json:"repairs"`
+	RequestedBy string            `json:"requested_by,omitempty"`
+	TaskID      string            `json:"task_id,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	StartedAt   *time.Time        `json:"started_at,omitempty"`
+	FinishedAt  *time.Time        `json:"finished_at,omitempty"`
+	Report      *integrity.Report `json:"report,omitempty"`
+}
+
+type IntegrityCheckListResponse struct {
+	Checks []IntegrityCheckResponse `json:"checks"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+// RotateKeyRequest is optional. OverlapHours defaults to KEYROTATION_OVERLAP
+// and is capped at KEYROTATION_MAXOVERLAP; zero retires the old key at once.
+type RotateKeyRequest struct {
+	OverlapHours *int `json:"overlap_hours,omitempty" binding:"omitempty,gte=0"`
+}
+
+type RotateKeyResponse struct {
+	License               *LicenseResponse `json:"license"`
+	PreviousKey           string           `json:"previous_key"`
+	PreviousKeyValidUntil time.Time        `json:"previous_key_valid_until"`
+}
@@ -6,43 +6,118 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/lastseen"
 )
 
+// CreateLicenseRequest needs ProductID or ProductName of an existing
+// product; when both are given they must agree. The customer is picked by
+// CustomerID or CustomerEmail the same way, except that an unknown e-mail
+// creates a customer named CustomerName.
+//
+// With TemplateID the template supplies the product, type, expiry, seats,
+// grace period, entitlements and metadata; fields set in the request win,
+// and metadata keys are merged over the template's.
 type CreateLicenseRequest struct {
-	Type          string                 `json:"type" binding:"required"`
-	ProductName   string                 `json:"product_name" binding:"required"`
-	CustomerName  *string                `json:"customer_name"`
-	CustomerEmail *string                `json:"customer_email" binding:"omitempty,email"`
+	TemplateID    *idgen.ID              `json:"template_id,omitempty" swaggertype:"string"`
+	Type          string                 `json:"type" binding:"required_without=TemplateID"`
+	ProductID     idgen.ID               `json:"product_id,omitempty" swaggertype:"string"`
+	ProductName   string                 `json:"product_name" binding:"omitempty,max=100"`
+	CustomerID    *idgen.ID              `json:"customer_id,omitempty" swaggertype:"string"`
+	CustomerName  *string                `json:"customer_name" binding:"omitempty,max=255"`
+	CustomerEmail *string                `json:"customer_email" binding:"omitempty,email,max=255"`
 	Metadata      json.RawMessage        `json:"metadata" swaggertype:"object"`
 	ExpiresAt     *time.Time             `json:"expires_at" binding:"omitempty,gt"`
-	InitialStatus *license.LicenseStatus `json:"initial_status,omitempty"`
+	InitialStatus *license.LicenseStatus `json:"initial_status,omitempty" binding:"omitempty,license_status"`
+	// StartsAt schedules the license to start later; until then it does not
+	// validate. Without InitialStatus a future StartsAt makes it pending.
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	// MaxActivations is the seat count, one when omitted.
+	MaxActivations *int `json:"max_activations,omitempty" binding:"omitempty,gte=1,lte=100000"`
+	// Floating leases the seats to clients through checkout instead of
+	// binding them to devices.
+	Floating bool `json:"floating,omitempty"`
+	// GracePeriodDays keeps the license valid for that many days after
+	// expires_at, zero when omitted.
+	GracePeriodDays *int `json:"grace_period_days,omitempty" binding:"omitempty,gte=0,lte=3650"`
+	// OverrideCustomerCap issues the license even if the customer already
+	// holds as many as the product's customer_license_caps allow.
+	OverrideCustomerCap bool     `json:"override_customer_cap,omitempty"`
+	Tags                []string `json:"tags,omitempty"`
+	OperatorNotes       string   `json:"operator_notes,omitempty" binding:"max=4096"`
+}
+
+// CloneLicenseRequest reissues a license under a new key. The clone copies
+// the product, type, customer, dates, seats, grace period, tags, metadata,
+// operator notes and entitlements of the source; fields set here replace
+// the copied ones, metadata as a whole.
+type CloneLicenseRequest struct {
+	Type          string          `json:"type,omitempty" binding:"max=50"`
+	CustomerID    *idgen.ID       `json:"customer_id,omitempty" swaggertype:"string"`
+	CustomerName  *string         `json:"customer_name,omitempty" binding:"omitempty,max=255"`
+	CustomerEmail *string         `json:"customer_email,omitempty" binding:"omitempty,email,max=255"`
+	Metadata      json.RawMessage `json:"metadata,omitempty" swaggertype:"object"`
+	ExpiresAt     *time.Time      `json:"expires_at,omitempty" binding:"omitempty,gt"`
+	StartsAt      *time.Time      `json:"starts_at,omitempty"`
+	// MaxActivations is the seat count; overrides the source's.
+	MaxActivations      *int     `json:"max_activations,omitempty" binding:"omitempty,gte=1,lte=100000"`
+	GracePeriodDays     *int     `json:"grace_period_days,omitempty" binding:"omitempty,gte=0,lte=3650"`
+	OverrideCustomerCap bool     `json:"override_customer_cap,omitempty"`
+	Tags                []string `json:"tags,omitempty"`
+	OperatorNotes       *string  `json:"operator_notes,omitempty" binding:"omitempty,max=4096"`
+}
+
+// GetLicenseQuery asks GET /licenses/:id to look in the archive when the
+// license is not among the live ones.
+type GetLicenseQuery struct {
+	IncludeArchived bool `form:"include_archived"`
 }
 
 type LicenseResponse struct {
-	ID            uuid.UUID             `json:"id"`
-	LicenseKey    string                `json:"license_key"`
-	Status        license.LicenseStatus `json:"status"`
-	Type          string                `json:"type"`
-	CustomerName  *string               `json:"customer_name,omitempty"`
-	CustomerEmail *string               `json:"customer_email,omitempty"`
-	ProductName   string                `json:"product_name"`
-	Metadata      json.RawMessage       `json:"metadata,omitempty" swaggertype:"object"`
-	IssuedAt      *time.Time            `json:"issued_at,omitempty"`
-	ExpiresAt     *time.Time            `json:"expires_at,omitempty"`
-	CreatedAt     time.Time             `json:"created_at"`
-	UpdatedAt     time.Time             `json:"updated_at"`
+	ID              uuid.UUID             `json:"id"`
+	LicenseKey      string                `json:"license_key"`
+	Status          license.LicenseStatus `json:"status"`
+	Type            string                `json:"type"`
+	CustomerName    *string               `json:"customer_name,omitempty"`
+	CustomerEmail   *string               `json:"customer_email,omitempty"`
+	CustomerID      *uuid.UUID            `json:"customer_id,omitempty"`
+	ParentID        *uuid.UUID            `json:"parent_id,omitempty"`
+	ProductID       uuid.UUID             `json:"product_id"`
+	ProductName     string                `json:"product_name"`
+	Metadata        json.RawMessage       `json:"metadata,omitempty" swaggertype:"object"`
+	IssuedAt        *time.Time            `json:"issued_at,omitempty"`
+	ExpiresAt       *time.Time            `json:"expires_at,omitempty"`
+	StartsAt        *time.Time            `json:"starts_at,omitempty"`
+	RevokedAt       *time.Time            `json:"revoked_at,omitempty"`
+	MaxActivations  int                   `json:"max_activations"`
+	Floating        bool                  `json:"floating"`
+	GracePeriodDays int                   `json:"grace_period_days"`
+	Tags            []string              `json:"tags"`
+	OperatorNotes   string                `json:"operator_notes,omitempty"`
+	CreatedAt       time.Time             `json:"created_at"`
+	UpdatedAt       time.Time             `json:"updated_at"`
+	// ArchivedAt is set on archived licenses.
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	// Display is set when the request asked for display values.
+	Display *DisplayValues `json:"display,omitempty"`
 }
 
 func NewLicenseResponse(lic *license.License) *LicenseResponse {
 	resp := &LicenseResponse{
-		ID:          lic.ID,
-		LicenseKey:  lic.LicenseKey,
-		Status:      lic.Status,
-		Type:        lic.Type,
-		ProductName: lic.ProductName,
-		Metadata:    lic.Metadata,
-		CreatedAt:   lic.CreatedAt,
-		UpdatedAt:   lic.UpdatedAt,
+		ID:              lic.ID,
+		LicenseKey:      lic.LicenseKey,
+		Status:          lic.Status,
+		Type:            lic.Type,
+		ProductID:       lic.ProductID,
+		ProductName:     lic.ProductName,
+		Metadata:        lic.Metadata,
+		MaxActivations:  lic.MaxActivations,
+		Floating:        lic.Floating,
+		GracePeriodDays: lic.GracePeriodDays,
+		Tags:            lic.Tags,
+		OperatorNotes:   lic.OperatorNotes,
+		CreatedAt:       lic.CreatedAt,
+		UpdatedAt:       lic.UpdatedAt,
 	}
 	if lic.CustomerName.Valid {
 		resp.CustomerName = &lic.CustomerName.String
@@ -50,24 +125,72 @@ func NewLicenseResponse(lic *license.License) *LicenseResponse {
 	if lic.CustomerEmail.Valid {
 		resp.CustomerEmail = &lic.CustomerEmail.String
 	}
+	if lic.CustomerID.Valid {
+		resp.CustomerID = &lic.CustomerID.UUID
+	}
+	if lic.ParentID.Valid {
+		resp.ParentID = &lic.ParentID.UUID
+	}
 	if lic.IssuedAt.Valid {
 		resp.IssuedAt = &lic.IssuedAt.Time
 	}
 	if lic.ExpiresAt.Valid {
 		resp.ExpiresAt = &lic.ExpiresAt.Time
 	}
+	if lic.StartsAt.Valid {
+		resp.StartsAt = &lic.StartsAt.Time
+	}
+	if lic.RevokedAt.Valid {
+		resp.RevokedAt = &lic.RevokedAt.Time
+	}
 	return resp
 }
 
+// ListLicensesRequest filters the license list. Status and ProductName take
+// several values, comma-separated or as repeated parameters, and match
+// licenses with any of them. CreatedAfter is inclusive and CreatedBefore
+// exclusive; both expiry bounds are inclusive.
 type ListLicensesRequest struct {
-	Status        *license.LicenseStatus `form:"status" binding:"omitempty,oneof=pending active inactive expired revoked"`
+	Status        []string   `form:"status" binding:"max=20,dive,max=1000"`
+	CustomerEmail *string    `form:"email" binding:"omitempty,email"`
+	CustomerID    *string    `form:"customer_id"`
+	ParentID      *string    `form:"parent_id"`
+	ProductName   []string   `form:"product_name" binding:"max=20,dive,max=1000"`
+	Type          *string    `form:"type"`
+	Tag           *string    `form:"tag" binding:"omitempty,max=64"`
+	CreatedAfter  *time.Time `form:"created_after"`
+	CreatedBefore *time.Time `form:"created_before"`
+	ExpiresAfter  *time.Time `form:"expires_after"`
+	ExpiresBefore *time.Time `form:"expires_before"`
+	Limit         int        `form:"limit,default=20" binding:"omitempty,gte=0"`
+	Offset        int        `form:"offset,default=0" binding:"omitempty,gte=0"`
+	SortBy        string     `form:"sort_by,default=created_at"`
+	SortOrder     string     `form:"sort_order,default=DESC" binding:"omitempty,oneof=ASC DESC"`
+	Sort          string     `form:"sort" binding:"omitempty,max=200"`
+}
+
+// SearchLicensesRequest finds licenses whose key, customer name, customer
+// e-mail or a metadata value contains Q, or that match it as a full-text
+// search, best matches first.
+type SearchLicensesRequest struct {
+	Q           string                 `form:"q" binding:"required,min=2,max=200"`
+	Status      *license.LicenseStatus `form:"status" binding:"omitempty,license_status"`
+	ProductName *string                `form:"product_name"`
+	Type        *string                `form:"type"`
+	Limit       int                    `form:"limit,default=20" binding:"omitempty,gte=0"`
+	Offset      int                    `form:"offset,default=0" binding:"omitempty,gte=0"`
+}
+
+// ExportLicensesRequest filters a license export like ListLicensesRequest,
+// without paging or sorting.
+type ExportLicensesRequest struct {
+	Status        *license.LicenseStatus `form:"status" binding:"omitempty,license_status"`
 	CustomerEmail *string                `form:"email" binding:"omitempty,email"`
 	ProductName   *string                `form:"product_name"`
 	Type          *string                `form:"type"`
-	Limit         int                    `form:"limit,default=20" binding:"omitempty,gte=0"`
-	Offset        int                    `form:"offset,default=0" binding:"omitempty,gte=0"`
-	SortBy        string                 `form:"sort_by,default=created_at"`
-	SortOrder     string                 `form:"sort_order,default=DESC" binding:"omitempty,oneof=ASC DESC"`
+	Tag           *string                `form:"tag" binding:"omitempty,max=64"`
+	CreatedAfter  *time.Time             `form:"created_after"`
+	CreatedBefore *time.Time             `form:"created_before"`
 }
 
 type PaginatedLicenseResponse struct {
@@ -77,30 +200,160 @@ type PaginatedLicenseResponse struct {
 	Offset     int                `json:"offset"`
 }
 
+// UpdateLicenseRequest moves a license to another customer with CustomerID
+// or CustomerEmail. CustomerName alone only changes licenses without a
+// customer; the name of a customer is changed on the customer.
 type UpdateLicenseRequest struct {
 	Type          *string         `json:"type"`
-	CustomerName  *string         `json:"customer_name"`
-	CustomerEmail *string         `json:"customer_email" binding:"omitempty,email"`
-	ProductName   *string         `json:"product_name"`
-	Metadata      json.RawMessage `json:"metadata" swaggertype:"object"`
+	CustomerID    *idgen.ID       `json:"customer_id" swaggertype:"string"`
+	CustomerName  *string         `json:"customer_name" binding:"omitempty,max=255"`
+	CustomerEmail *string         `json:"customer_email" binding:"omitempty,email,max=255"`
+	ProductID     *idgen.ID       `json:"product_id" swaggertype:"string"`
+	ProductName   *string         `json:"product_name" binding:"omitempty,max=100"`
+	Metadata      json.RawMessage `json:"metadata,omitempty" swaggertype:"object"`
 	ExpiresAt     *time.Time      `json:"expires_at" binding:"omitempty,gt"`
+	// StartsAt reschedules the license. It does not change the status; a
+	// pending license is activated once it passes.
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	// MaxActivations changes the seat count. Lowering it does not release
+	// seats already taken; new activations are refused until enough are
+	// revoked.
+	MaxActivations  *int `json:"max_activations,omitempty" binding:"omitempty,gte=1,lte=100000"`
+	GracePeriodDays *int `json:"grace_period_days,omitempty" binding:"omitempty,gte=0,lte=3650"`
+	// Floating switches the seat mode. Activations and leases taken in the
+	// old mode are kept until they are released or expire.
+	Floating *bool `json:"floating,omitempty"`
+	// Tags replaces all tags; [] removes them.
+	Tags          []string `json:"tags"`
+	OperatorNotes *string  `json:"operator_notes,omitempty" binding:"omitempty,max=4096"`
 }
 
 type UpdateLicenseStatusRequest struct {
-	Status *license.LicenseStatus `json:"status" binding:"required,oneof=pending active inactive expired revoked"`
+	Status *license.LicenseStatus `json:"status" binding:"required,license_status,ne=suspended"`
 }
 
 type ValidateLicenseRequest struct {
-	LicenseKey  string          `json:"license_key" binding:"required"`
-	ProductName string          `json:"product_name" binding:"required"`
+	LicenseKey  string          `json:"license_key" binding:"required,max=256"`
+	ProductName string          `json:"product_name" binding:"required,max=255"`
 	Metadata    json.RawMessage `json:"metadata,omitempty" swaggertype:"object"`
+	// AgentVersion is the version of the agent or SDK making the call,
+	// checked against the product's minimum agent version.
+	AgentVersion string `json:"agent_version,omitempty" binding:"omitempty,max=64"`
+	// SignedToken asks for a valid result to be returned as a signed
+	// entitlement token as well.
+	SignedToken bool `json:"signed_token,omitempty"`
+}
+
+// MaxValidateBatchSize is how many licenses one batch validation request
+// may carry, as bound on ValidateLicenseBatchRequest.Licenses.
+const MaxValidateBatchSize = 100
+
+type ValidateLicenseBatchRequest struct {
+	Licenses []ValidateLicenseRequest `json:"licenses" binding:"required,min=1,max=100,dive"`
+}
+
+type ValidateLicenseBatchResponse struct {
+	Results            []ValidateLicenseBatchResult `json:"results"`
+	ServerCapabilities *ServerCapabilities          `json:"server_capabilities"`
+}
+
+// ValidateLicenseBatchResult is the validation of one license of a batch,
+// in request order. Error is set instead of the validation fields when the
+// entry itself was rejected, e.g. for oversized metadata.
+type ValidateLicenseBatchResult struct {
+	LicenseKey  string `json:"license_key"`
+	ProductName string `json:"product_name"`
+	*ValidateLicenseResponse
+	Error *APIErrorResponse `json:"error,omitempty"`
 }
 
 type ValidateLicenseResponse struct {
 	IsValid bool `json:"is_valid"`
 
-	Status      *license.LicenseStatus `json:"status,omitempty"`
-	Reason      string                 `json:"reason,omitempty"`
-	ExpiresAt   *time.Time             `json:"expires_at,omitempty"`
-	AllowedData json.RawMessage        `json:"allowed_data,omitempty"`
+	Status *license.LicenseStatus `json:"status,omitempty"`
+	Reason string                 `json:"reason,omitempty"`
+	// StatusReason is the operator's reason when reason is suspended.
+	StatusReason string     `json:"status_reason,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	// StartsAt is when a scheduled license starts; with reason
+	// not_yet_active it tells the agent when to try again.
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	// GraceExpiresAt is set with reason in_grace_period: the license has
+	// expired and stops validating at this time unless renewed.
+	GraceExpiresAt *time.Time `json:"grace_expires_at,omitempty"`
+	// UsageMetric is set with reason usage_limit_exceeded: the metric whose
+	// monthly limit the license has reached.
+	UsageMetric string `json:"usage_metric,omitempty"`
+	// FloatingSeats is set for valid floating licenses: how many seats are
+	// checked out of how many.
+	FloatingSeats *FloatingSeats  `json:"floating_seats,omitempty"`
+	AllowedData   json.RawMessage `json:"allowed_data,omitempty"`
+	Warnings      []string        `json:"warnings,omitempty"`
+	// ChangedSinceLast tells the agent that allowed_data differs from what
+	// it received on its previous validation and cached feature flags should
+	// be refreshed. ChangeSummary lists the affected entitlements.
+	ChangedSinceLast bool              `json:"changed_since_last,omitempty"`
+	ChangeSummary    *lastseen.Changes `json:"change_summary,omitempty"`
+	// Token is the entitlement token asked for with signed_token, a compact
+	// JWS to verify with the key of its kid from /.well-known/jwks.json.
+	Token          string     `json:"token,omitempty"`
+	TokenKeyID     string     `json:"token_kid,omitempty"`
+	TokenExpiresAt *time.Time `json:"token_expires_at,omitempty"`
+	// CacheTTL and RevalidateAfter are the cache hints of the product, in
+	// seconds: how long the agent may rely on this result when the server
+	// cannot be reached, and when it should validate again.
+	CacheTTL        *int `json:"cache_ttl,omitempty"`
+	RevalidateAfter *int `json:"revalidate_after,omitempty"`
+
+	ServerCapabilities *ServerCapabilities `json:"server_capabilities,omitempty"`
+	// Display is set when the request asked for display values.
+	Display *DisplayValues `json:"display,omitempty"`
+}
+
+// ServerCapabilities lets agents and servers of different ages interoperate:
+// agents should treat a reason they do not know as a plain denial and only
+// use features that are advertised here.
+type ServerCapabilities struct {
+	Version          int      `json:"version"`
+	ServerVersion    string   `json:"server_version"`
+	SupportedReasons []string `json:"supported_reasons"`
+	Heartbeat        bool     `json:"heartbeat"`
+	OfflineTokens    bool     `json:"offline_tokens"`
+	// EntitlementChanges is set when validation responses carry
+	// changed_since_last.
+	EntitlementChanges bool `json:"entitlement_changes"`
+	// SigningAlg is the JWS algorithm of signed responses, empty when
+	// responses are not signed.
+	SigningAlg string `json:"signing_alg,omitempty"`
+}
+
+type StatusFreezeResponse struct {
+	Frozen      bool       `json:"frozen"`
+	FrozenUntil *time.Time `json:"frozen_until,omitempty"`
+	RecentFlips int64      `json:"recent_flips"`
+}
+
+// CompareLicensesRequest takes a comma-separated list of license IDs.
+type CompareLicensesRequest struct {
+	IDs           string `form:"ids" binding:"required,max=1000"`
+	DifferingOnly bool   `form:"differing_only"`
+}
+
+type ComparedLicense struct {
+	ID         uuid.UUID `json:"id"`
+	LicenseKey string    `json:"license_key"`
+}
+
+// LicenseFieldComparison has one value per compared license, in the order
+// of CompareLicensesResponse.Licenses.
+type LicenseFieldComparison struct {
+	Field  string        `json:"field"`
+	Values []interface{} `json:"values"`
+	Same   bool          `json:"same"`
+}
+
+type CompareLicensesResponse struct {
+	Licenses    []ComparedLicense        `json:"licenses"`
+	Differences int                      `json:"differences"`
+	Fields      []LicenseFieldComparison `json:"fields"`
 }
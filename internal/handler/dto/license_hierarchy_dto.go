@@ -0,0 +1,12 @@
+package dto
+
+import "encoding/json"
+
+// CreateChildLicenseRequest issues a child license; product, type, customer,
+// dates and tags are taken from the parent.
+type CreateChildLicenseRequest struct {
+	// MaxActivations is taken from the parent's seats, one when omitted.
+	MaxActivations *int            `json:"max_activations,omitempty" binding:"omitempty,gte=1,lte=100000"`
+	Metadata       json.RawMessage `json:"metadata" swaggertype:"object"`
+	OperatorNotes  string          `json:"operator_notes,omitempty" binding:"max=4096"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+import "github.com/google/uuid"
+
+// LicenseImportRow is one row of a license import file. Errors lists what
+// kept the row from becoming a request, in which case Request is nil.
+type LicenseImportRow struct {
+	Line    int
+	Request *CreateLicenseRequest
+	Errors  []FieldError
+}
+
+type LicenseImportQuery struct {
+	DryRun bool `form:"dry_run"`
+}
+
+// LicenseImportError is a problem with one row; Line counts the header as
+// line 1.
+type LicenseImportError struct {
+	Line    int    `json:"line"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type ImportedLicense struct {
+	Line       int       `json:"line"`
+	ID         uuid.UUID `json:"id"`
+	LicenseKey string    `json:"license_key"`
+}
+
+type LicenseImportResponse struct {
+	DryRun   bool                 `json:"dry_run"`
+	Rows     int                  `json:"rows"`
+	Valid    int                  `json:"valid"`
+	Imported int                  `json:"imported"`
+	Errors   []LicenseImportError `json:"errors,omitempty"`
+	Licenses []ImportedLicense    `json:"licenses,omitempty"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"github.com/makkenzo/license-service-api/internal/domain/licensetemplate"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+)
+
+// CreateLicenseTemplateRequest picks the product like CreateLicenseRequest.
+type CreateLicenseTemplateRequest struct {
+	Name            string                        `json:"name" binding:"required,max=100"`
+	ProductID       idgen.ID                      `json:"product_id,omitempty" swaggertype:"string"`
+	ProductName     string                        `json:"product_name" binding:"omitempty,max=100"`
+	Type            string                        `json:"type" binding:"required,max=100"`
+	DurationDays    *int                          `json:"duration_days,omitempty" binding:"omitempty,gte=1,lte=36500"`
+	MaxActivations  *int                          `json:"max_activations,omitempty" binding:"omitempty,gte=1,lte=100000"`
+	GracePeriodDays *int                          `json:"grace_period_days,omitempty" binding:"omitempty,gte=0,lte=3650"`
+	Entitlements    []licensetemplate.Entitlement `json:"entitlements"`
+	Metadata        json.RawMessage               `json:"metadata" swaggertype:"object"`
+}
+
+// UpdateLicenseTemplateRequest changes only the fields it sets. Zero
+// duration_days or max_activations removes the preset; entitlements and
+// metadata replace the template's ones as a whole.
+type UpdateLicenseTemplateRequest struct {
+	Name            *string                        `json:"name" binding:"omitempty,min=1,max=100"`
+	ProductID       *idgen.ID                      `json:"product_id,omitempty" swaggertype:"string"`
+	ProductName     *string                        `json:"product_name" binding:"omitempty,max=100"`
+	Type            *string                        `json:"type" binding:"omitempty,min=1,max=100"`
+	DurationDays    *int                           `json:"duration_days,omitempty" binding:"omitempty,gte=0,lte=36500"`
+	MaxActivations  *int                           `json:"max_activations,omitempty" binding:"omitempty,gte=0,lte=100000"`
+	GracePeriodDays *int                           `json:"grace_period_days,omitempty" binding:"omitempty,gte=0,lte=3650"`
+	Entitlements    *[]licensetemplate.Entitlement `json:"entitlements"`
+	Metadata        json.RawMessage                `json:"metadata" swaggertype:"object"`
+}
+
+type ListLicenseTemplatesRequest struct {
+	ProductID *string `form:"product_id"`
+}
+
+type LicenseTemplateListResponse struct {
+	Templates []*licensetemplate.Template `json:"templates"`
+}
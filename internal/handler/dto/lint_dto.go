@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type LintSeverity string
+
+const (
+	LintError   LintSeverity = "error"
+	LintWarning LintSeverity = "warning"
+)
+
+// LintIssue is one inconsistency. Kind is product, template, policy or
+// webhook and Subject names the checked item within it.
+type LintIssue struct {
+	Severity LintSeverity `json:"severity"`
+	Kind     string       `json:"kind"`
+	Subject  string       `json:"subject"`
+	Code     string       `json:"code"`
+	Message  string       `json:"message"`
+}
+
+// LintReport counts the checked items by kind and lists every issue found,
+// errors first.
+type LintReport struct {
+	CheckedAt time.Time      `json:"checked_at"`
+	Checked   map[string]int `json:"checked"`
+	Errors    int            `json:"errors"`
+	Warnings  int            `json:"warnings"`
+	Issues    []LintIssue    `json:"issues"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+// EnumsResponse lists the values clients would otherwise hardcode. Custom
+// license statuses and the validation reasons they add are included.
+type EnumsResponse struct {
+	LicenseStatuses   []LicenseStatusEnum `json:"license_statuses"`
+	LicenseTypes      []string            `json:"license_types"`
+	ValidationReasons []string            `json:"validation_reasons"`
+	EventTypes        []string            `json:"event_types"`
+	Scopes            []string            `json:"scopes"`
+	Roles             []string            `json:"roles"`
+}
+
+// LicenseStatusEnum is a license status; Behavior and Description are set
+// for custom statuses only.
+type LicenseStatusEnum struct {
+	Name        string `json:"name"`
+	Custom      bool   `json:"custom"`
+	Behavior    string `json:"behavior,omitempty"`
+	Description string `json:"description,omitempty"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+import "github.com/makkenzo/license-service-api/internal/domain/note"
+
+type ListNotesRequest struct {
+	Limit  int `form:"limit,default=20" binding:"omitempty,gte=0,lte=100"`
+	Offset int `form:"offset,default=0" binding:"omitempty,gte=0"`
+}
+
+type PaginatedNoteResponse struct {
+	Notes      []*note.Note `json:"notes"`
+	TotalCount int64        `json:"totalCount"`
+	Limit      int          `json:"limit"`
+	Offset     int          `json:"offset"`
+}
@@ -0,0 +1,104 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/product"
+	"github.com/makkenzo/license-service-api/internal/metaschema"
+)
+
+type CreateProductRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	DisplayName string `json:"display_name" binding:"max=255"`
+	Description string `json:"description" binding:"max=4096"`
+	KeyFormat   string `json:"key_format" binding:"max=64"`
+	KeyPrefix   string `json:"key_prefix" binding:"max=16"`
+	// CustomerLicenseCaps maps license types, or "*" for all types, to the
+	// most licenses one customer may hold.
+	CustomerLicenseCaps map[string]int `json:"customer_license_caps"`
+	// MetadataSchema is a JSON Schema the metadata of the product's licenses
+	// must match.
+	MetadataSchema json.RawMessage `json:"metadata_schema" swaggertype:"object"`
+}
+
+// UpdateProductRequest cannot rename a product: agents identify it by name,
+// and lifecycles and agent policies are keyed by it.
+type UpdateProductRequest struct {
+	DisplayName *string `json:"display_name" binding:"omitempty,max=255"`
+	Description *string `json:"description" binding:"omitempty,max=4096"`
+	KeyFormat   *string `json:"key_format" binding:"omitempty,max=64"`
+	KeyPrefix   *string `json:"key_prefix" binding:"omitempty,max=16"`
+	// CustomerLicenseCaps replaces all caps; {} removes them.
+	CustomerLicenseCaps map[string]int `json:"customer_license_caps"`
+	// MetadataSchema replaces the schema; null removes it.
+	MetadataSchema json.RawMessage `json:"metadata_schema" swaggertype:"object"`
+}
+
+type SetProductLifecycleRequest struct {
+	State            product.LifecycleState `json:"state" binding:"required,oneof=active deprecated eol"`
+	EOLDate          *time.Time             `json:"eol_date"`
+	EOLBehavior      product.EOLBehavior    `json:"eol_behavior" binding:"omitempty,oneof=warn deny"`
+	MigrationProduct *string                `json:"migration_product" binding:"omitempty,max=255"`
+	MigrationOffer   string                 `json:"migration_offer" binding:"max=4096"`
+}
+
+type SetAgentPolicyRequest struct {
+	MinVersion  string                   `json:"min_version" binding:"required,max=64"`
+	Enforcement product.AgentEnforcement `json:"enforcement" binding:"omitempty,oneof=warn deny"`
+}
+
+// SetCachePolicyRequest takes seconds; RevalidateAfter may not exceed
+// CacheTTL.
+type SetCachePolicyRequest struct {
+	CacheTTL        int `json:"cache_ttl" binding:"required,gte=1,lte=31536000"`
+	RevalidateAfter int `json:"revalidate_after" binding:"required,gte=1,ltefield=CacheTTL"`
+}
+
+type StartMigrationCampaignRequest struct {
+	Message string `json:"message" binding:"max=4096"`
+	DryRun  bool   `json:"dry_run"`
+}
+
+type MigrationCampaignResponse struct {
+	ProductName      string                 `json:"product_name"`
+	State            product.LifecycleState `json:"state"`
+	AffectedLicenses int64                  `json:"affected_licenses"`
+	DryRun           bool                   `json:"dry_run"`
+	TaskID           string                 `json:"task_id,omitempty"`
+}
+
+type MetadataMigrationOp struct {
+	Op    metaschema.OpKind `json:"op" binding:"required,oneof=rename set_default drop"`
+	Key   string            `json:"key" binding:"required,max=255"`
+	To    string            `json:"to" binding:"max=255"`
+	Value json.RawMessage   `json:"value" swaggertype:"object"`
+}
+
+type MigrateMetadataRequest struct {
+	Operations []MetadataMigrationOp `json:"operations" binding:"required,min=1,max=50,dive"`
+	DryRun     bool                  `json:"dry_run"`
+	// MaxDiffs caps the per-license diffs a dry run returns; the counts
+	// always cover every license.
+	MaxDiffs int `json:"max_diffs" binding:"omitempty,min=1,max=1000"`
+}
+
+type MetadataMigrationDiff struct {
+	LicenseID    uuid.UUID           `json:"license_id"`
+	LicenseKey   string              `json:"license_key"`
+	Changes      []metaschema.Change `json:"changes,omitempty"`
+	Error        string              `json:"error,omitempty"`
+	SchemaErrors []FieldError        `json:"schema_errors,omitempty"`
+}
+
+type MetadataMigrationResponse struct {
+	ProductName      string                  `json:"product_name"`
+	DryRun           bool                    `json:"dry_run"`
+	Scanned          int64                   `json:"scanned"`
+	Changed          int64                   `json:"changed"`
+	Conflicts        int64                   `json:"conflicts"`
+	SchemaViolations int64                   `json:"schema_violations"`
+	Diffs            []MetadataMigrationDiff `json:"diffs,omitempty"`
+	TaskID           string                  `json:"task_id,omitempty"`
+}
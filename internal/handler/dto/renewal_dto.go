@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateRenewalOfferRequest struct {
+	ExtendDays    int  `json:"extend_days" binding:"required,gt=0,lte=3650"`
+	ValidForHours int  `json:"valid_for_hours" binding:"omitempty,gt=0,lte=2160"`
+	SendEmail     bool `json:"send_email"`
+}
+
+type RenewalOfferResponse struct {
+	ID            uuid.UUID `json:"id"`
+	LicenseID     uuid.UUID `json:"license_id"`
+	ExtendDays    int       `json:"extend_days"`
+	URL           string    `json:"url"`
+	LinkExpiresAt time.Time `json:"link_expires_at"`
+	EmailSent     bool      `json:"email_sent"`
+}
+
+type RenewalOfferDetailsResponse struct {
+	ProductName      string     `json:"product_name"`
+	CustomerName     *string    `json:"customer_name,omitempty"`
+	ExtendDays       int        `json:"extend_days"`
+	CurrentExpiresAt *time.Time `json:"current_expires_at,omitempty"`
+	NewExpiresAt     time.Time  `json:"new_expires_at"`
+	LinkExpiresAt    time.Time  `json:"link_expires_at"`
+	ConsentText      string     `json:"consent_text"`
+	Accepted         bool       `json:"accepted"`
+	AcceptedAt       *time.Time `json:"accepted_at,omitempty"`
+}
+
+type AcceptRenewalOfferRequest struct {
+	Token   string `json:"token" binding:"required,max=256"`
+	Consent bool   `json:"consent" binding:"required"`
+}
+
+type AcceptRenewalOfferResponse struct {
+	ProductName  string    `json:"product_name"`
+	NewExpiresAt time.Time `json:"new_expires_at"`
+	AcceptedAt   time.Time `json:"accepted_at"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+type BindingFailureReportRequest struct {
+	Week  string `form:"week"`
+	Limit int    `form:"limit,default=20" binding:"omitempty,gte=1,lte=200"`
+}
+
+type BindingFailureOffender struct {
+	Endpoint string `json:"endpoint"`
+	Field    string `json:"field"`
+	Client   string `json:"client"`
+	Count    int64  `json:"count"`
+}
+
+type BindingFailureReportResponse struct {
+	Week      string                   `json:"week"`
+	Offenders []BindingFailureOffender `json:"offenders"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ListRevocationsRequest asks for the licenses of a product revoked at or
+// after Since, oldest first, so agents can page through and keep the
+// revoked_at of the last entry as their next Since.
+type ListRevocationsRequest struct {
+	ProductName string     `form:"product_name" binding:"required,max=255"`
+	Since       *time.Time `form:"since"`
+	Limit       int        `form:"limit,default=1000" binding:"omitempty,gte=0,lte=5000"`
+	Offset      int        `form:"offset,default=0" binding:"omitempty,gte=0"`
+}
+
+type Revocation struct {
+	LicenseID  uuid.UUID `json:"license_id"`
+	LicenseKey string    `json:"license_key"`
+	RevokedAt  time.Time `json:"revoked_at"`
+	Reason     string    `json:"reason,omitempty"`
+}
+
+type RevocationListResponse struct {
+	Revocations []Revocation `json:"revocations"`
+	TotalCount  int64        `json:"totalCount"`
+	Limit       int          `json:"limit"`
+	Offset      int          `json:"offset"`
+}
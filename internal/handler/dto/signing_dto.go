@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/domain/signingkey"
+)
+
+type LicenseFileResponse struct {
+	// LicenseFile is a compact JWS; verify it with the key of its kid from
+	// /.well-known/jwks.json.
+	LicenseFile string    `json:"license_file"`
+	KeyID       string    `json:"kid"`
+	IssuedAt    time.Time `json:"issued_at"`
+}
+
+type RotateSigningKeyRequest struct {
+	KeyRef string `json:"key_ref" binding:"required,max=1024"`
+}
+
+type SigningKeyResponse struct {
+	KID       string     `json:"kid"`
+	Algorithm string     `json:"algorithm"`
+	KeyRef    string     `json:"key_ref"`
+	Status    string     `json:"status"`
+	CreatedAt time.Time  `json:"created_at"`
+	RetiredAt *time.Time `json:"retired_at,omitempty"`
+}
+
+func NewSigningKeyResponse(k *signingkey.Key) *SigningKeyResponse {
+	resp := &SigningKeyResponse{
+		KID:       k.KID,
+		Algorithm: k.Algorithm,
+		KeyRef:    k.KeyRef,
+		Status:    string(k.Status),
+		CreatedAt: k.CreatedAt,
+	}
+	if k.RetiredAt.Valid {
+		resp.RetiredAt = &k.RetiredAt.Time
+	}
+	return resp
+}
+
+// PassportResponse carries a validation passport when the license is valid;
+// otherwise only is_valid and reason, as in validation responses.
+type PassportResponse struct {
+	IsValid bool   `json:"is_valid"`
+	Reason  string `json:"reason,omitempty"`
+	// Passport is a compact JWS; verify it with the key of its kid from
+	// /.well-known/jwks.json.
+	Passport     string     `json:"passport,omitempty"`
+	KeyID        string     `json:"kid,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	RefreshAfter *time.Time `json:"refresh_after,omitempty"`
+}
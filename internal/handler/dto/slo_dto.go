@@ -0,0 +1,45 @@
+package dto
+
+import "time"
+
+// SLOReportResponse shows how the validation endpoint meets its objectives.
+// Windows are written like 5m, 6h or 30d.
+type SLOReportResponse struct {
+	Endpoint    string         `json:"endpoint"`
+	Window      string         `json:"window"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	Objectives  []SLOObjective `json:"objectives"`
+}
+
+// SLOObjective is one SLO over the whole window. SLI is the fraction of
+// good requests, null without requests; ErrorBudgetRemaining goes negative
+// once the budget is spent.
+type SLOObjective struct {
+	Name                 string        `json:"name"`
+	Objective            float64       `json:"objective"`
+	LatencyThresholdMs   int64         `json:"latency_threshold_ms,omitempty"`
+	Requests             int64         `json:"requests"`
+	Bad                  int64         `json:"bad"`
+	SLI                  *float64      `json:"sli"`
+	ErrorBudgetRemaining float64       `json:"error_budget_remaining"`
+	BurnRates            []SLOBurnRate `json:"burn_rates"`
+	Alerts               []SLOAlert    `json:"alerts"`
+}
+
+// SLOBurnRate is how fast a window spends the error budget: 1 spends
+// exactly the budget over the SLO window.
+type SLOBurnRate struct {
+	Window   string  `json:"window"`
+	Requests int64   `json:"requests"`
+	Bad      int64   `json:"bad"`
+	BurnRate float64 `json:"burn_rate"`
+}
+
+// SLOAlert fires when both windows burn faster than the threshold.
+type SLOAlert struct {
+	Severity    string  `json:"severity"`
+	LongWindow  string  `json:"long_window"`
+	ShortWindow string  `json:"short_window"`
+	Threshold   float64 `json:"threshold"`
+	Firing      bool    `json:"firing"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+type StepUpRequest struct {
+	// Factor names the second factor, e.g. "totp".
+	Factor string `json:"factor" binding:"required,max=50"`
+	Code   string `json:"code" binding:"required,max=100"`
+}
+
+type StepUpResponse struct {
+	Factor     string    `json:"factor"`
+	VerifiedAt time.Time `json:"verified_at"`
+	// ExpiresAt is when destructive actions require another step-up.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// TOTPEnrollmentResponse carries the new secret, for entering by hand, and
+// the otpauth URI to show as a QR code.
+type TOTPEnrollmentResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
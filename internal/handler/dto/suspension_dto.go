@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/statushistory"
+)
+
+// StatusChangeRequest carries the operator's reason for a suspend, reinstate
+// or revoke. It is required when suspending or revoking, since agents are
+// shown it.
+type StatusChangeRequest struct {
+	Reason string `json:"reason" binding:"omitempty,max=1000"`
+}
+
+type StatusChangeResponse struct {
+	ID         uuid.UUID             `json:"id"`
+	LicenseID  uuid.UUID             `json:"license_id"`
+	FromStatus license.LicenseStatus `json:"from_status"`
+	ToStatus   license.LicenseStatus `json:"to_status"`
+	Reason     string                `json:"reason,omitempty"`
+	ChangedBy  string                `json:"changed_by,omitempty"`
+	CreatedAt  time.Time             `json:"created_at"`
+}
+
+func NewStatusChangeResponse(e *statushistory.Entry) *StatusChangeResponse {
+	return &StatusChangeResponse{
+		ID:         e.ID,
+		LicenseID:  e.LicenseID,
+		FromStatus: e.FromStatus,
+		ToStatus:   e.ToStatus,
+		Reason:     e.Reason,
+		ChangedBy:  e.ChangedBy,
+		CreatedAt:  e.CreatedAt,
+	}
+}
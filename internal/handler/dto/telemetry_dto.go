@@ -0,0 +1,16 @@
+package dto
+
+// TelemetryReport is the exact body POSTed to the telemetry endpoint. Keep it
+// free of anything that identifies the install, its customers or products.
+type TelemetryReport struct {
+	Version          string           `json:"version"`
+	LicensesTotal    int64            `json:"licenses_total"`
+	LicensesByStatus map[string]int64 `json:"licenses_by_status"`
+}
+
+type TelemetryPreviewResponse struct {
+	Enabled  bool            `json:"enabled"`
+	Endpoint string          `json:"endpoint"`
+	Interval string          `json:"interval"`
+	Payload  TelemetryReport `json:"payload"`
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/usage"
+)
+
+type UsageEvent struct {
+	Metric   string `json:"metric" binding:"required,max=100"`
+	Quantity int64  `json:"quantity" binding:"required,gte=1"`
+	// OccurredAt defaults to the time of the report.
+	OccurredAt *time.Time `json:"occurred_at,omitempty"`
+}
+
+type UsageReportRequest struct {
+	LicenseKey  string       `json:"license_key" binding:"required,max=256"`
+	ProductName string       `json:"product_name" binding:"required,max=255"`
+	Events      []UsageEvent `json:"events" binding:"required,min=1,max=100,dive"`
+}
+
+// MetricUsage is the usage of a metric in the current month. Limit is
+// absent when the license has no usage.<metric> entitlement.
+type MetricUsage struct {
+	Metric   string `json:"metric"`
+	Used     int64  `json:"used"`
+	Limit    *int64 `json:"limit,omitempty"`
+	Exceeded bool   `json:"exceeded"`
+}
+
+type UsageReportResponse struct {
+	LicenseID   uuid.UUID     `json:"license_id"`
+	Accepted    int           `json:"accepted"`
+	PeriodStart time.Time     `json:"period_start"`
+	Usage       []MetricUsage `json:"usage"`
+}
+
+type ListUsageRequest struct {
+	Metric *string `form:"metric" binding:"omitempty,max=100"`
+	Period string  `form:"period" binding:"omitempty,oneof=day month"`
+	// From is inclusive, To exclusive; both are truncated to the UTC day.
+	From *time.Time `form:"from"`
+	To   *time.Time `form:"to"`
+}
+
+type UsageListResponse struct {
+	LicenseID uuid.UUID        `json:"license_id"`
+	Period    string           `json:"period"`
+	Usage     []*usage.Counter `json:"usage"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+)
+
+// RegisterLicenseStatusValidator adds the license_status binding tag, which
+// accepts the statuses known reports, i.e. built-in and custom ones.
+func RegisterLicenseStatusValidator(known func(license.LicenseStatus) bool) error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("binding validator is not go-playground/validator")
+	}
+	return v.RegisterValidation("license_status", func(fl validator.FieldLevel) bool {
+		return known(license.LicenseStatus(fl.Field().String()))
+	})
+}
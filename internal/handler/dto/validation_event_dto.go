@@ -0,0 +1,19 @@
+package dto
+
+import "github.com/makkenzo/license-service-api/internal/domain/validationevent"
+
+type ListValidationEventsRequest struct {
+	LicenseID   *string `form:"license_id"`
+	ProductName *string `form:"product_name" binding:"omitempty,max=255"`
+	Valid       *bool   `form:"valid"`
+	Country     *string `form:"country" binding:"omitempty,len=2,uppercase"`
+	Limit       int     `form:"limit,default=20" binding:"omitempty,gte=0,lte=100"`
+	Offset      int     `form:"offset,default=0" binding:"omitempty,gte=0"`
+}
+
+type PaginatedValidationEventResponse struct {
+	Events     []*validationevent.Event `json:"events"`
+	TotalCount int64                    `json:"totalCount"`
+	Limit      int                      `json:"limit"`
+	Offset     int                      `json:"offset"`
+}
@@ -0,0 +1,127 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/webhook"
+)
+
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url,max=2048"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=license.created license.expired license.revoked validation.failed"`
+	// Secret is generated when omitted and returned once in the response.
+	Secret      string              `json:"secret" binding:"omitempty,min=16,max=256"`
+	Description string              `json:"description" binding:"max=500"`
+	IsEnabled   *bool               `json:"is_enabled"`
+	RetryPolicy *WebhookRetryPolicy `json:"retry_policy"`
+}
+
+// WebhookRetryPolicy overrides the server's delivery defaults for one
+// subscription; fields left out use the defaults. In an update it replaces
+// the whole override, so an empty object restores the defaults.
+type WebhookRetryPolicy struct {
+	MaxAttempts           *int `json:"max_attempts,omitempty" binding:"omitempty,gte=1,lte=50"`
+	InitialBackoffSeconds *int `json:"initial_backoff_seconds,omitempty" binding:"omitempty,gte=1,lte=86400"`
+	MaxBackoffSeconds     *int `json:"max_backoff_seconds,omitempty" binding:"omitempty,gte=1,lte=86400"`
+	TimeoutSeconds        *int `json:"timeout_seconds,omitempty" binding:"omitempty,gte=1,lte=120"`
+}
+
+// UpdateWebhookRequest changes the fields that are set; a new secret is not
+// echoed back.
+type UpdateWebhookRequest struct {
+	URL         *string             `json:"url" binding:"omitempty,url,max=2048"`
+	Events      []string            `json:"events" binding:"omitempty,min=1,dive,oneof=license.created license.expired license.revoked validation.failed"`
+	Secret      *string             `json:"secret" binding:"omitempty,min=16,max=256"`
+	Description *string             `json:"description" binding:"omitempty,max=500"`
+	IsEnabled   *bool               `json:"is_enabled"`
+	RetryPolicy *WebhookRetryPolicy `json:"retry_policy"`
+}
+
+type WebhookResponse struct {
+	ID          uuid.UUID                   `json:"id"`
+	URL         string                      `json:"url"`
+	Events      []string                    `json:"events"`
+	Description string                      `json:"description,omitempty"`
+	IsEnabled   bool                        `json:"is_enabled"`
+	RetryPolicy WebhookEffectiveRetryPolicy `json:"retry_policy"`
+	Health      WebhookHealthResponse       `json:"health"`
+	CreatedBy   string                      `json:"created_by,omitempty"`
+	CreatedAt   time.Time                   `json:"created_at"`
+	UpdatedAt   time.Time                   `json:"updated_at"`
+}
+
+// WebhookEffectiveRetryPolicy is the policy deliveries use, with the
+// server's defaults filled in.
+type WebhookEffectiveRetryPolicy struct {
+	MaxAttempts           int `json:"max_attempts"`
+	InitialBackoffSeconds int `json:"initial_backoff_seconds"`
+	MaxBackoffSeconds     int `json:"max_backoff_seconds"`
+	TimeoutSeconds        int `json:"timeout_seconds"`
+}
+
+type WebhookHealthResponse struct {
+	Status              webhook.HealthStatus `json:"status"`
+	ConsecutiveFailures int                  `json:"consecutive_failures"`
+	FailingSince        *time.Time           `json:"failing_since,omitempty"`
+	LastSuccessAt       *time.Time           `json:"last_success_at,omitempty"`
+	LastFailureAt       *time.Time           `json:"last_failure_at,omitempty"`
+	DisabledReason      string               `json:"disabled_reason,omitempty"`
+	DisabledAt          *time.Time           `json:"disabled_at,omitempty"`
+}
+
+type CreatedWebhookResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+
+// NewWebhookResponse describes s, which delivers with policy.
+func NewWebhookResponse(s *webhook.Subscription, policy webhook.Policy) *WebhookResponse {
+	return &WebhookResponse{
+		ID:          s.ID,
+		URL:         s.URL,
+		Events:      s.Events,
+		Description: s.Description,
+		IsEnabled:   s.IsEnabled,
+		RetryPolicy: WebhookEffectiveRetryPolicy{
+			MaxAttempts:           policy.MaxAttempts,
+			InitialBackoffSeconds: int(policy.InitialBackoff / time.Second),
+			MaxBackoffSeconds:     int(policy.MaxBackoff / time.Second),
+			TimeoutSeconds:        int(policy.Timeout / time.Second),
+		},
+		Health: WebhookHealthResponse{
+			Status:              s.HealthStatus(),
+			ConsecutiveFailures: s.Health.ConsecutiveFailures,
+			FailingSince:        s.Health.FailingSince,
+			LastSuccessAt:       s.Health.LastSuccessAt,
+			LastFailureAt:       s.Health.LastFailureAt,
+			DisabledReason:      s.Health.DisabledReason,
+			DisabledAt:          s.Health.DisabledAt,
+		},
+		CreatedBy: s.CreatedBy,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+type ListWebhookDeliveriesRequest struct {
+	Limit int `form:"limit" binding:"omitempty,gte=1,lte=200"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             uuid.UUID              `json:"id"`
+	EventID        uuid.UUID              `json:"event_id"`
+	Event          webhook.EventType      `json:"event"`
+	Status         webhook.DeliveryStatus `json:"status"`
+	Attempts       int                    `json:"attempts"`
+	ResponseStatus int                    `json:"response_status,omitempty"`
+	LastError      string                 `json:"last_error,omitempty"`
+	Payload        json.RawMessage        `json:"payload"`
+	CreatedAt      time.Time              `json:"created_at"`
+	LastAttemptAt  *time.Time             `json:"last_attempt_at,omitempty"`
+}
+
+type WebhookDeliveryListResponse struct {
+	Deliveries []*WebhookDeliveryResponse `json:"deliveries"`
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type EntitlementHandler struct {
+	service *service.EntitlementService
+	logger  *zap.Logger
+}
+
+func NewEntitlementHandler(service *service.EntitlementService, logger *zap.Logger) *EntitlementHandler {
+	return &EntitlementHandler{
+		service: service,
+		logger:  logger.Named("EntitlementHandler"),
+	}
+}
+
+func (h *EntitlementHandler) List(c *gin.Context) {
+	idStr := c.Param("id")
+	licenseID, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid license ID for entitlement list", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	entitlements, err := h.service.List(c.Request.Context(), licenseID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.EntitlementListResponse{Entitlements: entitlements})
+}
+
+func (h *EntitlementHandler) Put(c *gin.Context) {
+	idStr := c.Param("id")
+	licenseID, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid license ID for entitlement update", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.PutEntitlementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate entitlement request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	e, err := h.service.Put(c.Request.Context(), licenseID, c.Param("name"), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, e)
+}
+
+func (h *EntitlementHandler) Delete(c *gin.Context) {
+	idStr := c.Param("id")
+	licenseID, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid license ID for entitlement delete", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), licenseID, c.Param("name")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
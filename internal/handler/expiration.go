@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type ExpirationHandler struct {
+	service *service.ExpirationService
+	logger  *zap.Logger
+}
+
+func NewExpirationHandler(service *service.ExpirationService, logger *zap.Logger) *ExpirationHandler {
+	return &ExpirationHandler{
+		service: service,
+		logger:  logger.Named("ExpirationHandler"),
+	}
+}
+
+func (h *ExpirationHandler) Preview(c *gin.Context) {
+	var req dto.ExpirationPreviewRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	resp, err := h.service.Preview(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
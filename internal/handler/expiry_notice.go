@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type ExpiryNoticeHandler struct {
+	service *service.ExpiryNoticeService
+	logger  *zap.Logger
+}
+
+func NewExpiryNoticeHandler(service *service.ExpiryNoticeService, logger *zap.Logger) *ExpiryNoticeHandler {
+	return &ExpiryNoticeHandler{
+		service: service,
+		logger:  logger.Named("ExpiryNoticeHandler"),
+	}
+}
+
+func (h *ExpiryNoticeHandler) Get(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid ID format for expiry notifications", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(fmt.Errorf("%w: invalid license id format", ierr.ErrValidation))
+		return
+	}
+
+	resp, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ExpiryNoticeHandler) Update(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid ID format for expiry notifications", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(fmt.Errorf("%w: invalid license id format", ierr.ErrValidation))
+		return
+	}
+
+	var req dto.ExpiryNotificationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate expiry notifications request", zap.String("id", idStr), zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	resp, err := h.service.SetOptOut(c.Request.Context(), id, *req.OptedOut)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
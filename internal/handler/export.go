@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type ExportHandler struct {
+	service *service.ExportService
+	logger  *zap.Logger
+}
+
+func NewExportHandler(service *service.ExportService, logger *zap.Logger) *ExportHandler {
+	return &ExportHandler{
+		service: service,
+		logger:  logger.Named("ExportHandler"),
+	}
+}
+
+func (h *ExportHandler) Licenses(c *gin.Context) {
+	var req dto.ExportLicensesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	stream := newNDJSONStream(c)
+	err := h.service.ExportLicenses(c.Request.Context(), &req, func(lic *dto.LicenseResponse) error {
+		return stream.Write(lic)
+	})
+	stream.Finish(err)
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/handler/middleware"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type ExtensionHandler struct {
+	service *service.ExtensionService
+	logger  *zap.Logger
+}
+
+func NewExtensionHandler(service *service.ExtensionService, logger *zap.Logger) *ExtensionHandler {
+	return &ExtensionHandler{
+		service: service,
+		logger:  logger.Named("ExtensionHandler"),
+	}
+}
+
+func (h *ExtensionHandler) Create(c *gin.Context) {
+	id, err := idgen.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.CreateExtensionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate extension request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	r, err := h.service.Create(c.Request.Context(), id, &req, middleware.GetUserClaims(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewExtensionRequestResponse(r))
+}
+
+func (h *ExtensionHandler) ListForLicense(c *gin.Context) {
+	id, err := idgen.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	h.list(c, &id)
+}
+
+func (h *ExtensionHandler) List(c *gin.Context) {
+	h.list(c, nil)
+}
+
+func (h *ExtensionHandler) list(c *gin.Context, licenseID *uuid.UUID) {
+	var req dto.ListExtensionRequestsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	requests, total, err := h.service.List(c.Request.Context(), licenseID, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	resp := make([]*dto.ExtensionRequestResponse, len(requests))
+	for i, r := range requests {
+		resp[i] = dto.NewExtensionRequestResponse(r)
+	}
+	c.JSON(http.StatusOK, dto.PaginatedExtensionRequestResponse{
+		ExtensionRequests: resp,
+		TotalCount:        total,
+		Limit:             req.Limit,
+		Offset:            req.Offset,
+	})
+}
+
+func (h *ExtensionHandler) Get(c *gin.Context) {
+	licenseID, id, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	r, err := h.service.Get(c.Request.Context(), licenseID, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewExtensionRequestResponse(r))
+}
+
+func (h *ExtensionHandler) Approve(c *gin.Context) {
+	licenseID, id, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+	req, ok := h.bindDecision(c)
+	if !ok {
+		return
+	}
+
+	r, lic, err := h.service.Approve(c.Request.Context(), licenseID, id, req, middleware.GetUserClaims(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ApproveExtensionResponse{
+		ExtensionRequest: dto.NewExtensionRequestResponse(r),
+		License:          dto.NewLicenseResponse(lic),
+	})
+}
+
+func (h *ExtensionHandler) Reject(c *gin.Context) {
+	licenseID, id, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+	req, ok := h.bindDecision(c)
+	if !ok {
+		return
+	}
+
+	r, err := h.service.Reject(c.Request.Context(), licenseID, id, req, middleware.GetUserClaims(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewExtensionRequestResponse(r))
+}
+
+func (h *ExtensionHandler) parseIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	licenseID, err := idgen.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	requestIDStr := c.Param("requestId")
+	id, err := idgen.Parse(requestIDStr)
+	if err != nil {
+		h.logger.Warn("Invalid ID format for extension request", zap.String("id_param", requestIDStr), zap.Error(err))
+		_ = c.Error(fmt.Errorf("%w: invalid extension request id format", ierr.ErrValidation))
+		return uuid.Nil, uuid.Nil, false
+	}
+	return licenseID, id, true
+}
+
+// bindDecision reads the optional note sent with an approval or rejection;
+// the body may be left out.
+func (h *ExtensionHandler) bindDecision(c *gin.Context) (*dto.DecideExtensionRequest, bool) {
+	var req dto.DecideExtensionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Warn("Failed to bind or validate extension decision", zap.Error(err))
+		_ = c.Error(err)
+		return nil, false
+	}
+	return &req, true
+}
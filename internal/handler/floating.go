@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type FloatingHandler struct {
+	service *service.FloatingService
+	logger  *zap.Logger
+}
+
+func NewFloatingHandler(service *service.FloatingService, logger *zap.Logger) *FloatingHandler {
+	return &FloatingHandler{
+		service: service,
+		logger:  logger.Named("FloatingHandler"),
+	}
+}
+
+func (h *FloatingHandler) Checkout(c *gin.Context) {
+	var req dto.CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate checkout request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	resp, err := h.service.Checkout(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *FloatingHandler) Checkin(c *gin.Context) {
+	var req dto.CheckinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate checkin request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	if err := h.service.Checkin(c.Request.Context(), &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -5,21 +5,25 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/buildinfo"
+	"github.com/makkenzo/license-service-api/internal/config"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
 type HealthHandler struct {
-	db     *pgxpool.Pool
-	redis  *redis.Client
-	logger *zap.Logger
+	db         *pgxpool.Pool
+	redis      *redis.Client
+	deployment *config.DeploymentConfig
+	logger     *zap.Logger
 }
 
-func NewHealthHandler(db *pgxpool.Pool, redis *redis.Client, logger *zap.Logger) *HealthHandler {
+func NewHealthHandler(db *pgxpool.Pool, redis *redis.Client, deployment *config.DeploymentConfig, logger *zap.Logger) *HealthHandler {
 	return &HealthHandler{
-		db:     db,
-		redis:  redis,
-		logger: logger,
+		db:         db,
+		redis:      redis,
+		deployment: deployment,
+		logger:     logger,
 	}
 }
 
@@ -38,7 +42,10 @@ func (h *HealthHandler) Check(c *gin.Context) {
 
 	if dbStatus == "error" || redisStatus == "error" {
 		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"status": "unhealthy",
+			"status":      "unhealthy",
+			"version":     buildinfo.ServiceVersion(),
+			"instance_id": h.deployment.InstanceID,
+			"track":       h.deployment.Track,
 			"dependencies": gin.H{
 				"database": dbStatus,
 				"redis":    redisStatus,
@@ -48,7 +55,10 @@ func (h *HealthHandler) Check(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"status": "ok",
+		"status":      "ok",
+		"version":     buildinfo.ServiceVersion(),
+		"instance_id": h.deployment.InstanceID,
+		"track":       h.deployment.Track,
 		"dependencies": gin.H{
 			"database": dbStatus,
 			"redis":    redisStatus,
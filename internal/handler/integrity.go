@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/handler/middleware"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type IntegrityHandler struct {
+	service *service.IntegrityService
+	logger  *zap.Logger
+}
+
+func NewIntegrityHandler(service *service.IntegrityService, logger *zap.Logger) *IntegrityHandler {
+	return &IntegrityHandler{
+		service: service,
+		logger:  logger.Named("IntegrityHandler"),
+	}
+}
+
+func (h *IntegrityHandler) Start(c *gin.Context) {
+	// The body is optional; without it the check only reports.
+	var req dto.StartIntegrityCheckRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.logger.Warn("Failed to bind or validate integrity check request", zap.Error(err))
+			_ = c.Error(err)
+			return
+		}
+	}
+
+	requestedBy := ""
+	if claims := middleware.GetUserClaims(c); claims != nil {
+		requestedBy = claims.Subject
+	}
+
+	resp, err := h.service.StartCheck(c.Request.Context(), &req, requestedBy)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, resp)
+}
+
+func (h *IntegrityHandler) List(c *gin.Context) {
+	var req dto.ListIntegrityChecksQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	resp, err := h.service.ListChecks(c.Request.Context(), req.Limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *IntegrityHandler) Get(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid ID format for integrity check", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(fmt.Errorf("%w: invalid integrity check id format", ierr.ErrValidation))
+		return
+	}
+
+	resp, err := h.service.GetCheck(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type KeyRotationHandler struct {
+	service *service.KeyRotationService
+	logger  *zap.Logger
+}
+
+func NewKeyRotationHandler(service *service.KeyRotationService, logger *zap.Logger) *KeyRotationHandler {
+	return &KeyRotationHandler{
+		service: service,
+		logger:  logger.Named("KeyRotationHandler"),
+	}
+}
+
+func (h *KeyRotationHandler) RotateKey(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid ID format for key rotation", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	// The body is optional; without it the configured overlap applies.
+	var req dto.RotateKeyRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.logger.Warn("Failed to bind or validate key rotation request", zap.String("id", idStr), zap.Error(err))
+			_ = c.Error(err)
+			return
+		}
+	}
+
+	resp, err := h.service.RotateKey(c.Request.Context(), id, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,78 @@
+package handler_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+	"github.com/makkenzo/license-service-api/internal/domain/signingkey"
+	"github.com/makkenzo/license-service-api/internal/signing"
+	"go.uber.org/zap"
+)
+
+// signingKeys keeps the keys the keyring rotated in, newest first.
+type signingKeys struct {
+	keys []*signingkey.Key
+}
+
+func (r *signingKeys) Rotate(_ context.Context, k *signingkey.Key) error {
+	for _, existing := range r.keys {
+		if existing.Status == signingkey.StatusActive {
+			existing.Status = signingkey.StatusRetired
+			existing.RetiredAt.Time, existing.RetiredAt.Valid = time.Now().UTC(), true
+		}
+	}
+	k.Status, k.CreatedAt = signingkey.StatusActive, time.Now().UTC()
+	stored := *k
+	r.keys = append([]*signingkey.Key{&stored}, r.keys...)
+	return nil
+}
+
+func (r *signingKeys) List(context.Context) ([]*signingkey.Key, error) {
+	keys := make([]*signingkey.Key, len(r.keys))
+	for i, k := range r.keys {
+		found := *k
+		keys[i] = &found
+	}
+	return keys, nil
+}
+
+// newKeyring returns a keyring with one active key.
+func newKeyring(t *testing.T) *signing.Keyring {
+	t.Helper()
+	keyring := signing.NewKeyring(&signingKeys{}, cryptoprovider.Stdlib{}, &config.SigningConfig{
+		Issuer:              "https://licenses.example.com",
+		PassportTTL:         time.Hour,
+		PassportAudience:    "agents",
+		EntitlementTokenTTL: time.Hour,
+	}, zap.NewNop())
+	if _, err := keyring.Rotate(context.Background(), writeSigningKey(t)); err != nil {
+		t.Fatalf("rotating signing key: %v", err)
+	}
+	return keyring
+}
+
+func writeSigningKey(t *testing.T) string {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "signing.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -2,25 +2,41 @@ package handler
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/display"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
 	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/handler/middleware"
+	"github.com/makkenzo/license-service-api/internal/idgen"
 	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/jsonlimit"
 	"github.com/makkenzo/license-service-api/internal/service"
+	"github.com/makkenzo/license-service-api/internal/statusguard"
 	"go.uber.org/zap"
 )
 
 type LicenseHandler struct {
-	service *service.LicenseService
-	logger  *zap.Logger
+	service   *service.LicenseService
+	approvals *service.ApprovalService
+	archive   *service.ArchiveService
+	localizer *display.Localizer
+	logger    *zap.Logger
 }
 
-func NewLicenseHandler(service *service.LicenseService, logger *zap.Logger) *LicenseHandler {
+func NewLicenseHandler(service *service.LicenseService, approvals *service.ApprovalService, archive *service.ArchiveService, localizer *display.Localizer, logger *zap.Logger) *LicenseHandler {
 	return &LicenseHandler{
-		service: service,
-		logger:  logger.Named("LicenseHandler"),
+		service:   service,
+		approvals: approvals,
+		archive:   archive,
+		localizer: localizer,
+		logger:    logger.Named("LicenseHandler"),
 	}
 }
 
@@ -48,6 +64,34 @@ func (h *LicenseHandler) Create(c *gin.Context) {
 	c.JSON(http.StatusCreated, responseDTO)
 }
 
+func (h *LicenseHandler) Clone(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid ID format for license clone", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	// The body is optional; without it the source is copied as is.
+	var req dto.CloneLicenseRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.logger.Warn("Failed to bind or validate license clone request", zap.String("id", idStr), zap.Error(err))
+			_ = c.Error(err)
+			return
+		}
+	}
+
+	clone, err := h.service.CloneLicense(c.Request.Context(), id, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewLicenseResponse(clone))
+}
+
 func (h *LicenseHandler) List(c *gin.Context) {
 	h.logger.Debug("Received request to list licenses")
 	var req dto.ListLicensesRequest
@@ -57,6 +101,10 @@ func (h *LicenseHandler) List(c *gin.Context) {
 		_ = c.Error(err)
 		return
 	}
+	displayReq, ok := h.bindDisplay(c)
+	if !ok {
+		return
+	}
 
 	licenses, totalCount, err := h.service.ListLicenses(c.Request.Context(), &req)
 	if err != nil {
@@ -65,9 +113,11 @@ func (h *LicenseHandler) List(c *gin.Context) {
 		return
 	}
 
+	now := time.Now()
 	licenseResponses := make([]*dto.LicenseResponse, len(licenses))
 	for i, lic := range licenses {
 		licenseResponses[i] = dto.NewLicenseResponse(lic)
+		licenseResponses[i].Display = h.displayOf(displayReq, lic, now)
 	}
 
 	paginatedResponse := dto.PaginatedLicenseResponse{
@@ -80,13 +130,98 @@ func (h *LicenseHandler) List(c *gin.Context) {
 	c.JSON(http.StatusOK, paginatedResponse)
 }
 
+func (h *LicenseHandler) Search(c *gin.Context) {
+	var req dto.SearchLicensesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate search parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+	displayReq, ok := h.bindDisplay(c)
+	if !ok {
+		return
+	}
+
+	licenses, totalCount, err := h.service.SearchLicenses(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	now := time.Now()
+	licenseResponses := make([]*dto.LicenseResponse, len(licenses))
+	for i, lic := range licenses {
+		licenseResponses[i] = dto.NewLicenseResponse(lic)
+		licenseResponses[i].Display = h.displayOf(displayReq, lic, now)
+	}
+
+	c.JSON(http.StatusOK, dto.PaginatedLicenseResponse{
+		Licenses:   licenseResponses,
+		TotalCount: totalCount,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	})
+}
+
+// Capabilities is the discovery counterpart of the server_capabilities
+// field in validation responses, for agents that want to check before
+// validating.
+func (h *LicenseHandler) Capabilities(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.ServerCapabilities())
+}
+
+func (h *LicenseHandler) Aggregate(c *gin.Context) {
+	var req dto.AggregateLicensesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate aggregate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	resp, err := h.service.AggregateLicenses(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Service failed to aggregate licenses", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *LicenseHandler) Compare(c *gin.Context) {
+	var req dto.CompareLicensesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate compare query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	resp, err := h.service.CompareLicenses(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 func (h *LicenseHandler) GetByID(c *gin.Context) {
 	idStr := c.Param("id")
 	h.logger.Debug("Received request to get license by ID", zap.String("id_param", idStr))
 
-	id, err := uuid.Parse(idStr)
+	id, err := idgen.Parse(idStr)
 	if err != nil {
-		h.logger.Warn("Invalid UUID format received", zap.String("id_param", idStr), zap.Error(err))
+		h.logger.Warn("Invalid ID format received", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+	displayReq, ok := h.bindDisplay(c)
+	if !ok {
+		return
+	}
+	var query dto.GetLicenseQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		h.logger.Warn("Failed to bind get license query parameters", zap.Error(err))
 		_ = c.Error(err)
 		return
 	}
@@ -94,6 +229,10 @@ func (h *LicenseHandler) GetByID(c *gin.Context) {
 	lic, err := h.service.GetLicenseByID(c.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, ierr.ErrNotFound) {
+			if query.IncludeArchived {
+				h.getArchived(c, id, displayReq)
+				return
+			}
 			h.logger.Info("License not found by handler", zap.String("id", idStr))
 			_ = c.Error(err)
 			return
@@ -106,16 +245,51 @@ func (h *LicenseHandler) GetByID(c *gin.Context) {
 
 	h.logger.Info("License retrieved successfully via handler", zap.String("id", idStr))
 	responseDTO := dto.NewLicenseResponse(lic)
+	responseDTO.Display = h.displayOf(displayReq, lic, time.Now())
+	c.JSON(http.StatusOK, responseDTO)
+}
+
+func (h *LicenseHandler) getArchived(c *gin.Context, id uuid.UUID, displayReq *dto.DisplayRequest) {
+	archived, err := h.archive.FindArchivedByID(c.Request.Context(), id)
+	if err != nil {
+		if !errors.Is(err, ierr.ErrNotFound) {
+			h.logger.Error("Service failed to get archived license by ID", zap.String("id", id.String()), zap.Error(err))
+		}
+		_ = c.Error(err)
+		return
+	}
+
+	responseDTO := dto.NewLicenseResponse(&archived.License)
+	responseDTO.ArchivedAt = &archived.ArchivedAt
+	responseDTO.Display = h.displayOf(displayReq, &archived.License, time.Now())
 	c.JSON(http.StatusOK, responseDTO)
 }
 
+func (h *LicenseHandler) LicenseFile(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid ID format for license file", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(fmt.Errorf("%w: invalid license id format", ierr.ErrValidation))
+		return
+	}
+
+	file, err := h.service.IssueLicenseFile(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, file)
+}
+
 func (h *LicenseHandler) UpdateStatus(c *gin.Context) {
 	idStr := c.Param("id")
 	h.logger.Debug("Received request to update license status", zap.String("id_param", idStr))
 
-	id, err := uuid.Parse(idStr)
+	id, err := idgen.Parse(idStr)
 	if err != nil {
-		h.logger.Warn("Invalid UUID format for status update", zap.String("id_param", idStr), zap.Error(err))
+		h.logger.Warn("Invalid ID format for status update", zap.String("id_param", idStr), zap.Error(err))
 		_ = c.Error(err)
 		return
 	}
@@ -127,7 +301,9 @@ func (h *LicenseHandler) UpdateStatus(c *gin.Context) {
 		return
 	}
 
-	err = h.service.UpdateLicenseStatus(c.Request.Context(), id, *req.Status)
+	// An operator setting the status explicitly overrides a status freeze.
+	ctx := statusguard.WithOperatorOverride(c.Request.Context())
+	err = h.service.UpdateLicenseStatus(ctx, id, *req.Status)
 	if err != nil {
 
 		if errors.Is(err, ierr.ErrNotFound) {
@@ -156,9 +332,9 @@ func (h *LicenseHandler) Update(c *gin.Context) {
 	idStr := c.Param("id")
 	h.logger.Debug("Received request to update license", zap.String("id_param", idStr))
 
-	id, err := uuid.Parse(idStr)
+	id, err := idgen.Parse(idStr)
 	if err != nil {
-		h.logger.Warn("Invalid UUID format for update", zap.String("id_param", idStr), zap.Error(err))
+		h.logger.Warn("Invalid ID format for update", zap.String("id_param", idStr), zap.Error(err))
 		_ = c.Error(err)
 		return
 	}
@@ -171,6 +347,16 @@ func (h *LicenseHandler) Update(c *gin.Context) {
 		return
 	}
 
+	held, err := h.approvals.HoldProtectedUpdate(c.Request.Context(), id, &req, middleware.GetUserClaims(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if held != nil {
+		c.JSON(http.StatusAccepted, dto.NewApprovalResponse(held, time.Now()))
+		return
+	}
+
 	updatedLicense, err := h.service.UpdateLicense(c.Request.Context(), id, &req)
 	if err != nil {
 
@@ -199,10 +385,11 @@ func (h *LicenseHandler) Update(c *gin.Context) {
 func (h *LicenseHandler) Validate(c *gin.Context) {
 	h.logger.Debug("Received request to validate license")
 	var req dto.ValidateLicenseRequest
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Warn("Failed to bind or validate validation request body", zap.Error(err))
-		_ = c.Error(err)
+	if !h.bindValidateRequest(c, &req) {
+		return
+	}
+	displayReq, ok := h.bindDisplay(c)
+	if !ok {
 		return
 	}
 
@@ -214,18 +401,8 @@ func (h *LicenseHandler) Validate(c *gin.Context) {
 		return
 	}
 
-	resp := dto.ValidateLicenseResponse{
-		IsValid:     validationResult.IsValid,
-		Reason:      validationResult.Reason,
-		AllowedData: validationResult.ResponseData,
-	}
-
-	if validationResult.License != nil {
-		resp.Status = &validationResult.License.Status
-		if validationResult.License.ExpiresAt.Valid {
-			resp.ExpiresAt = &validationResult.License.ExpiresAt.Time
-		}
-	}
+	resp := h.validationResponse(validationResult, displayReq)
+	resp.ServerCapabilities = h.service.ServerCapabilities()
 
 	h.logger.Info("License validation processed",
 		zap.String("license_key", req.LicenseKey),
@@ -234,3 +411,189 @@ func (h *LicenseHandler) Validate(c *gin.Context) {
 	)
 	c.JSON(http.StatusOK, resp)
 }
+
+// ValidateBatch validates up to dto.MaxValidateBatchSize licenses in one
+// call. Entries the service rejects get their own error; a server-side
+// failure fails the whole batch so the agent retries it as one. The call is
+// metered as one validation per entry.
+func (h *LicenseHandler) ValidateBatch(c *gin.Context) {
+	var req dto.ValidateLicenseBatchRequest
+	if !h.bindAgentJSON(c, &req, validateBatchLimits) {
+		return
+	}
+	displayReq, ok := h.bindDisplay(c)
+	if !ok {
+		return
+	}
+	middleware.SetMeteredCalls(c, len(req.Licenses))
+
+	results, errs := h.service.ValidateLicenses(c.Request.Context(), req.Licenses)
+	resp := dto.ValidateLicenseBatchResponse{
+		Results:            make([]dto.ValidateLicenseBatchResult, len(req.Licenses)),
+		ServerCapabilities: h.service.ServerCapabilities(),
+	}
+	valid := 0
+	for i, entry := range req.Licenses {
+		item := dto.ValidateLicenseBatchResult{LicenseKey: entry.LicenseKey, ProductName: entry.ProductName}
+		if err := errs[i]; err != nil {
+			status, code, message := ierr.Describe(err)
+			if status >= http.StatusInternalServerError {
+				h.logger.Error("Service failed during batch license validation", zap.String("license_key", entry.LicenseKey), zap.Error(err))
+				_ = c.Error(err)
+				return
+			}
+			item.Error = &dto.APIErrorResponse{Code: code, Message: message}
+		} else {
+			item.ValidateLicenseResponse = h.validationResponse(results[i], displayReq)
+			if item.IsValid {
+				valid++
+			}
+		}
+		resp.Results[i] = item
+	}
+
+	h.logger.Info("Batch license validation processed", zap.Int("licenses", len(req.Licenses)), zap.Int("valid", valid))
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *LicenseHandler) validationResponse(result *service.ValidationResult, displayReq *dto.DisplayRequest) *dto.ValidateLicenseResponse {
+	resp := &dto.ValidateLicenseResponse{
+		IsValid:     result.IsValid,
+		Reason:      result.Reason,
+		AllowedData: result.ResponseData,
+		Warnings:    result.Warnings,
+
+		ChangedSinceLast: result.ChangedSinceLast,
+		ChangeSummary:    result.Changes,
+		StatusReason:     result.StatusReason,
+		UsageMetric:      result.UsageMetric,
+		FloatingSeats:    result.FloatingSeats,
+		GraceExpiresAt:   result.GraceExpiresAt,
+		Token:            result.Token,
+		TokenKeyID:       result.TokenKeyID,
+		TokenExpiresAt:   result.TokenExpiresAt,
+		CacheTTL:         result.CacheTTL,
+		RevalidateAfter:  result.RevalidateAfter,
+	}
+
+	if result.License != nil {
+		resp.Status = &result.License.Status
+		if result.License.ExpiresAt.Valid {
+			resp.ExpiresAt = &result.License.ExpiresAt.Time
+		}
+		if result.License.StartsAt.Valid {
+			resp.StartsAt = &result.License.StartsAt.Time
+		}
+		if displayReq != nil {
+			resp.Display = dto.NewValidationDisplay(h.formatter(displayReq, result.License), resp, time.Now())
+		}
+	}
+	return resp
+}
+
+// Passport validates the license and returns a short-lived signed passport
+// that edge functions can verify locally.
+func (h *LicenseHandler) Passport(c *gin.Context) {
+	var req dto.ValidateLicenseRequest
+	if !h.bindValidateRequest(c, &req) {
+		return
+	}
+
+	resp, err := h.service.IssuePassport(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Service failed to issue validation passport", zap.String("license_key", req.LicenseKey), zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// validateBatchLimits are the JSON limits of a batch validation body: the
+// default ones scaled to a full batch. The metadata of each entry is still
+// checked against the default limits.
+var validateBatchLimits = jsonlimit.Limits{
+	MaxBytes:        jsonlimit.Default.MaxBytes * dto.MaxValidateBatchSize / 4,
+	MaxDepth:        jsonlimit.Default.MaxDepth + 2,
+	MaxNumberLength: jsonlimit.Default.MaxNumberLength,
+	MaxStringLength: jsonlimit.Default.MaxStringLength,
+	MaxValues:       jsonlimit.Default.MaxValues * dto.MaxValidateBatchSize / 4,
+}
+
+// bindValidateRequest reads a validation request body under the JSON limits
+// that apply to agent traffic. It reports the error and returns false when
+// the body is rejected.
+func (h *LicenseHandler) bindValidateRequest(c *gin.Context, req *dto.ValidateLicenseRequest) bool {
+	return h.bindAgentJSON(c, req, jsonlimit.Default)
+}
+
+func (h *LicenseHandler) bindAgentJSON(c *gin.Context, req any, limits jsonlimit.Limits) bool {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(limits.MaxBytes))
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			err = jsonlimit.ErrDocumentTooBig
+		}
+		h.logger.Warn("Failed to read validation request body", zap.Error(err))
+		_ = c.Error(err)
+		return false
+	}
+
+	if err := DecodeAgentJSON(body, limits, req); err != nil {
+		h.logger.Warn("Validation request body rejected", zap.Error(err))
+		_ = c.Error(err)
+		return false
+	}
+	return true
+}
+
+// DecodeAgentJSON checks an agent request body against limits and binds it
+// into req, running its binding validation. Bodies over a limit fail with
+// one of the jsonlimit errors.
+func DecodeAgentJSON(body []byte, limits jsonlimit.Limits, req any) error {
+	if err := jsonlimit.Check(body, limits); err != nil {
+		return err
+	}
+	return binding.JSON.BindBody(body, req)
+}
+
+// bindDisplay reads the display query parameters. It returns nil when the
+// request did not ask for display values, and false after reporting
+// invalid parameters.
+func (h *LicenseHandler) bindDisplay(c *gin.Context) (*dto.DisplayRequest, bool) {
+	var req dto.DisplayRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate display parameters", zap.Error(err))
+		_ = c.Error(err)
+		return nil, false
+	}
+	if _, err := display.LoadTimezone(req.Timezone); err != nil {
+		_ = c.Error(fmt.Errorf("%w: unknown time zone %q", ierr.ErrValidation, req.Timezone))
+		return nil, false
+	}
+	if !req.Display {
+		return nil, true
+	}
+	return &req, true
+}
+
+// formatter picks the locale and time zone asked for in req, falling back
+// to the customer's.
+func (h *LicenseHandler) formatter(req *dto.DisplayRequest, lic *license.License) *display.Formatter {
+	locale, timezone := req.Locale, req.Timezone
+	if locale == "" {
+		locale = lic.Locale()
+	}
+	if timezone == "" {
+		timezone = lic.Timezone()
+	}
+	return h.localizer.For(locale, timezone)
+}
+
+func (h *LicenseHandler) displayOf(req *dto.DisplayRequest, lic *license.License, now time.Time) *dto.DisplayValues {
+	if req == nil {
+		return nil
+	}
+	return dto.NewLicenseDisplay(h.formatter(req, lic), lic, now)
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type LicenseHierarchyHandler struct {
+	service        *service.LicenseHierarchyService
+	licenseService *service.LicenseService
+	logger         *zap.Logger
+}
+
+func NewLicenseHierarchyHandler(service *service.LicenseHierarchyService, licenseService *service.LicenseService, logger *zap.Logger) *LicenseHierarchyHandler {
+	return &LicenseHierarchyHandler{
+		service:        service,
+		licenseService: licenseService,
+		logger:         logger.Named("LicenseHierarchyHandler"),
+	}
+}
+
+func (h *LicenseHierarchyHandler) CreateChild(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid ID format for child license", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	// The body is optional; without it the child gets one seat.
+	var req dto.CreateChildLicenseRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.logger.Warn("Failed to bind or validate child license request", zap.String("id", idStr), zap.Error(err))
+			_ = c.Error(err)
+			return
+		}
+	}
+
+	child, err := h.service.CreateChild(c.Request.Context(), id, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewLicenseResponse(child))
+}
+
+func (h *LicenseHierarchyHandler) ListChildren(c *gin.Context) {
+	id, err := idgen.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.ListLicensesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	if _, err := h.licenseService.GetLicenseByID(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	parentID := id.String()
+	req.ParentID = &parentID
+	licenses, total, err := h.licenseService.ListLicenses(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	licenseResponses := make([]*dto.LicenseResponse, len(licenses))
+	for i, lic := range licenses {
+		licenseResponses[i] = dto.NewLicenseResponse(lic)
+	}
+
+	c.JSON(http.StatusOK, dto.PaginatedLicenseResponse{
+		Licenses:   licenseResponses,
+		TotalCount: total,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	})
+}
@@ -0,0 +1,306 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/handler/middleware"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+// licenseImportMaxBytes bounds license import uploads.
+const licenseImportMaxBytes = 10 << 20
+
+// importColumn fills one field of a create request from a CSV cell. field
+// is the name validation errors report for it.
+type importColumn struct {
+	field string
+	set   func(req *dto.CreateLicenseRequest, value string) error
+}
+
+// licenseImportColumns are the columns an import file may have, named like
+// the fields of a create request. Empty cells leave a field unset.
+var licenseImportColumns = map[string]importColumn{
+	"template_id": {"TemplateID", func(req *dto.CreateLicenseRequest, v string) error {
+		id, err := parseImportID(v)
+		req.TemplateID = &id
+		return err
+	}},
+	"type": {"Type", func(req *dto.CreateLicenseRequest, v string) error {
+		req.Type = v
+		return nil
+	}},
+	"product_id": {"ProductID", func(req *dto.CreateLicenseRequest, v string) error {
+		var err error
+		req.ProductID, err = parseImportID(v)
+		return err
+	}},
+	"product_name": {"ProductName", func(req *dto.CreateLicenseRequest, v string) error {
+		req.ProductName = v
+		return nil
+	}},
+	"customer_id": {"CustomerID", func(req *dto.CreateLicenseRequest, v string) error {
+		id, err := parseImportID(v)
+		req.CustomerID = &id
+		return err
+	}},
+	"customer_name": {"CustomerName", func(req *dto.CreateLicenseRequest, v string) error {
+		req.CustomerName = &v
+		return nil
+	}},
+	"customer_email": {"CustomerEmail", func(req *dto.CreateLicenseRequest, v string) error {
+		req.CustomerEmail = &v
+		return nil
+	}},
+	"metadata": {"Metadata", func(req *dto.CreateLicenseRequest, v string) error {
+		if !json.Valid([]byte(v)) {
+			return errors.New("must be valid JSON")
+		}
+		req.Metadata = json.RawMessage(v)
+		return nil
+	}},
+	"expires_at": {"ExpiresAt", func(req *dto.CreateLicenseRequest, v string) error {
+		t, err := parseImportTime(v)
+		req.ExpiresAt = &t
+		return err
+	}},
+	"starts_at": {"StartsAt", func(req *dto.CreateLicenseRequest, v string) error {
+		t, err := parseImportTime(v)
+		req.StartsAt = &t
+		return err
+	}},
+	"initial_status": {"InitialStatus", func(req *dto.CreateLicenseRequest, v string) error {
+		status := license.LicenseStatus(v)
+		req.InitialStatus = &status
+		return nil
+	}},
+	"max_activations": {"MaxActivations", func(req *dto.CreateLicenseRequest, v string) error {
+		n, err := parseImportInt(v)
+		req.MaxActivations = &n
+		return err
+	}},
+	"floating": {"Floating", func(req *dto.CreateLicenseRequest, v string) error {
+		var err error
+		req.Floating, err = parseImportBool(v)
+		return err
+	}},
+	"grace_period_days": {"GracePeriodDays", func(req *dto.CreateLicenseRequest, v string) error {
+		n, err := parseImportInt(v)
+		req.GracePeriodDays = &n
+		return err
+	}},
+	"override_customer_cap": {"OverrideCustomerCap", func(req *dto.CreateLicenseRequest, v string) error {
+		var err error
+		req.OverrideCustomerCap, err = parseImportBool(v)
+		return err
+	}},
+	// Tags are separated by semicolons.
+	"tags": {"Tags", func(req *dto.CreateLicenseRequest, v string) error {
+		for _, tag := range strings.Split(v, ";") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				req.Tags = append(req.Tags, tag)
+			}
+		}
+		return nil
+	}},
+	"operator_notes": {"OperatorNotes", func(req *dto.CreateLicenseRequest, v string) error {
+		req.OperatorNotes = v
+		return nil
+	}},
+}
+
+// Import creates licenses from the CSV file uploaded as the "file" form
+// field. With dry_run=true it only reports what is wrong with which row.
+func (h *LicenseHandler) Import(c *gin.Context) {
+	var query dto.LicenseImportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		h.logger.Warn("Failed to bind license import query", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, licenseImportMaxBytes)
+	header, err := c.FormFile("file")
+	if err != nil {
+		h.logger.Warn("Failed to read license import upload", zap.Error(err))
+		_ = c.Error(licenseImportUploadError(err))
+		return
+	}
+	if c.Request.MultipartForm != nil {
+		defer func() { _ = c.Request.MultipartForm.RemoveAll() }()
+	}
+	file, err := header.Open()
+	if err != nil {
+		_ = c.Error(fmt.Errorf("opening license import upload: %w", err))
+		return
+	}
+	defer file.Close()
+
+	rows, err := readLicenseImport(file)
+	if err != nil {
+		h.logger.Warn("Failed to parse license import file", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	resp, err := h.service.ImportLicenses(c.Request.Context(), rows, query.DryRun)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if resp.DryRun {
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+	if resp.Imported < resp.Valid {
+		h.logger.Warn("License import stored only some rows", zap.Int("count", resp.Imported), zap.Int("valid", resp.Valid))
+		c.JSON(http.StatusMultiStatus, resp)
+		return
+	}
+	h.logger.Info("Licenses imported via handler", zap.Int("count", resp.Imported))
+	c.JSON(http.StatusCreated, resp)
+}
+
+func licenseImportUploadError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return fmt.Errorf("%w: license import exceeds %d bytes", ierr.ErrPayloadTooLarge, licenseImportMaxBytes)
+	}
+	return fmt.Errorf("%w: expected a multipart upload with the CSV file in the \"file\" field", ierr.ErrValidation)
+}
+
+// readLicenseImport parses a CSV file whose header row names the columns.
+// Problems with single rows are recorded on the rows, so that one report
+// lists all of them; a file that cannot be read as a whole is an error.
+func readLicenseImport(r io.Reader) ([]dto.LicenseImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: license import file is empty", ierr.ErrValidation)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: license import file is not valid CSV: %w", ierr.ErrValidation, err)
+	}
+	columns := make([]importColumn, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\uFEFF")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		col, ok := licenseImportColumns[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown column %q in license import file", ierr.ErrValidation, name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: column %q appears twice in license import file", ierr.ErrValidation, name)
+		}
+		seen[name] = true
+		columns[i] = col
+	}
+	columnNames := make(map[string]string, len(columns))
+	for name, col := range licenseImportColumns {
+		columnNames[col.field] = name
+	}
+
+	var rows []dto.LicenseImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, fmt.Errorf("%w: license import file is not valid CSV: %w", ierr.ErrValidation, err)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(rows) == service.MaxLicenseImportRows {
+			return nil, fmt.Errorf("%w: license import file has more than %d rows", ierr.ErrValidation, service.MaxLicenseImportRows)
+		}
+
+		row := dto.LicenseImportRow{Line: line}
+		if err != nil {
+			row.Errors = append(row.Errors, dto.FieldError{Message: fmt.Sprintf("row has %d fields, the header has %d", len(record), len(header))})
+			rows = append(rows, row)
+			continue
+		}
+
+		req := &dto.CreateLicenseRequest{}
+		for i, value := range record {
+			if value = strings.TrimSpace(value); value == "" {
+				continue
+			}
+			if err := columns[i].set(req, value); err != nil {
+				row.Errors = append(row.Errors, dto.FieldError{Field: columnNames[columns[i].field], Message: err.Error()})
+			}
+		}
+		if len(row.Errors) == 0 {
+			var ve validator.ValidationErrors
+			if err := binding.Validator.ValidateStruct(req); errors.As(err, &ve) {
+				for _, fe := range ve {
+					row.Errors = append(row.Errors, dto.FieldError{Field: columnNames[fe.StructField()], Message: middleware.ValidationErrorMessage(fe)})
+				}
+			} else if err != nil {
+				row.Errors = append(row.Errors, dto.FieldError{Message: err.Error()})
+			}
+		}
+		if len(row.Errors) == 0 {
+			row.Request = req
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseImportID(v string) (idgen.ID, error) {
+	var id idgen.ID
+	if err := id.UnmarshalText([]byte(v)); err != nil {
+		return id, errors.New("must be a UUID or ULID")
+	}
+	return id, nil
+}
+
+// parseImportTime accepts RFC 3339 timestamps and plain dates, which are
+// taken as midnight UTC.
+func parseImportTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errors.New("must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+}
+
+func parseImportInt(v string) (int, error) {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, errors.New("must be a whole number")
+	}
+	return n, nil
+}
+
+func parseImportBool(v string) (bool, error) {
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, errors.New("must be true or false")
+	}
+	return b, nil
+}
@@ -0,0 +1,231 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/comment"
+	"github.com/makkenzo/license-service-api/internal/domain/entitlement"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/statushistory"
+	"github.com/makkenzo/license-service-api/internal/handler"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+// licenseWrites adds the writes of status changes and child licenses to
+// licenseLookup.
+type licenseWrites struct {
+	licenseLookup
+}
+
+func (r *licenseWrites) UpdateStatus(_ context.Context, id uuid.UUID, status license.LicenseStatus) error {
+	for _, l := range r.licenses {
+		if l.ID == id {
+			l.Status = status
+			return nil
+		}
+	}
+	return errFakeNotFound
+}
+
+func (r *licenseWrites) Create(_ context.Context, l *license.License) (uuid.UUID, error) {
+	stored := *l
+	stored.ID, stored.CreatedAt, stored.UpdatedAt = uuid.New(), time.Now().UTC(), time.Now().UTC()
+	r.licenses = append(r.licenses, &stored)
+	return stored.ID, nil
+}
+
+// WithLock runs fn right away: the tests send one request at a time.
+func (r *licenseWrites) WithLock(ctx context.Context, _ uuid.UUID, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// statusHistory records status changes.
+type statusHistory struct {
+	statushistory.Repository
+	entries []*statushistory.Entry
+}
+
+func (r *statusHistory) Record(_ context.Context, e *statushistory.Entry) error {
+	e.ID, e.CreatedAt = uuid.New(), time.Now().UTC()
+	r.entries = append(r.entries, e)
+	return nil
+}
+
+func (r *statusHistory) LatestFor(_ context.Context, _ []uuid.UUID, status license.LicenseStatus) (map[uuid.UUID]*statushistory.Entry, error) {
+	latest := map[uuid.UUID]*statushistory.Entry{}
+	for _, e := range r.entries {
+		if e.ToStatus == status {
+			latest[e.LicenseID] = e
+		}
+	}
+	return latest, nil
+}
+
+func TestStatusChangeHandlers(t *testing.T) {
+	lic := newTestLicense(2)
+	licenses, history := &licenseWrites{licenseLookup{licenses: []*license.License{lic}}}, &statusHistory{}
+	suspension := handler.NewSuspensionHandler(service.NewSuspensionService(licenses, history, zap.NewNop()), zap.NewNop())
+	revocation := handler.NewRevocationHandler(service.NewRevocationService(licenses, history, zap.NewNop()), zap.NewNop())
+	id := lic.ID.String()
+
+	runCases(t, []apiCase{
+		{name: "suspend", method: http.MethodPost, pattern: "/licenses/:id/suspend", handler: suspension.Suspend,
+			target: "/licenses/" + id + "/suspend", body: `{"reason":"payment overdue"}`, status: http.StatusOK},
+		{name: "suspend again", method: http.MethodPost, pattern: "/licenses/:id/suspend", handler: suspension.Suspend,
+			target: "/licenses/" + id + "/suspend", body: `{"reason":"payment overdue"}`, status: http.StatusConflict},
+		{name: "reinstate", method: http.MethodPost, pattern: "/licenses/:id/reinstate", handler: suspension.Reinstate,
+			target: "/licenses/" + id + "/reinstate", body: `{}`, status: http.StatusOK},
+		{name: "revoke", method: http.MethodPost, pattern: "/licenses/:id/revoke", handler: revocation.Revoke,
+			target: "/licenses/" + id + "/revoke", body: `{"reason":"refunded"}`, status: http.StatusOK},
+		{name: "list revoked", method: http.MethodGet, pattern: "/licenses/revoked", handler: revocation.List,
+			target: "/licenses/revoked?product_name=acme", status: http.StatusOK},
+	})
+}
+
+func TestLicenseHierarchyHandler(t *testing.T) {
+	parent := newTestLicense(2)
+	licenses := &licenseWrites{licenseLookup{licenses: []*license.License{parent}}}
+	licenseSvc := service.NewLicenseService(licenses, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		&config.QueryConfig{MaxOffset: 10000}, zap.NewNop())
+	h := handler.NewLicenseHierarchyHandler(service.NewLicenseHierarchyService(licenses, acmeProducts{}, zap.NewNop()), licenseSvc, zap.NewNop())
+	activations := handler.NewActivationHandler(
+		service.NewActivationService(&deviceActivations{}, licenses, &config.HeartbeatConfig{StaleAfter: time.Hour}, zap.NewNop()), zap.NewNop())
+	id := parent.ID.String()
+	device := func(deviceID string) string {
+		return fmt.Sprintf(`{"license_key":%q,"product_name":"acme","device_id":%q}`, parent.LicenseKey, deviceID)
+	}
+
+	runCases(t, []apiCase{
+		{name: "create child", method: http.MethodPost, pattern: "/licenses/:id/children", handler: h.CreateChild,
+			target: "/licenses/" + id + "/children", body: `{"max_activations":1,"metadata":{"site":"berlin"}}`, status: http.StatusCreated},
+		{name: "create child over seats", method: http.MethodPost, pattern: "/licenses/:id/children", handler: h.CreateChild,
+			target: "/licenses/" + id + "/children", body: `{"max_activations":5}`, status: http.StatusConflict},
+		{name: "list children", method: http.MethodGet, pattern: "/licenses/:id/children", handler: h.ListChildren,
+			target: "/licenses/" + id + "/children", status: http.StatusOK},
+		{name: "activate parent", method: http.MethodPost, pattern: "/licenses/activate", handler: activations.Activate, target: "/licenses/activate",
+			body: device("device-1"), status: http.StatusCreated},
+		{name: "activate parent into child seats", method: http.MethodPost, pattern: "/licenses/activate", handler: activations.Activate, target: "/licenses/activate",
+			body: device("device-2"), status: http.StatusConflict},
+	})
+}
+
+// namedEntitlements keeps the entitlements of licenses by name.
+type namedEntitlements struct {
+	entitlement.Repository
+	all []*entitlement.Entitlement
+}
+
+func (r *namedEntitlements) ListByLicense(_ context.Context, licenseID uuid.UUID) ([]*entitlement.Entitlement, error) {
+	list := []*entitlement.Entitlement{}
+	for _, e := range r.all {
+		if e.LicenseID == licenseID {
+			list = append(list, e)
+		}
+	}
+	return list, nil
+}
+
+func (r *namedEntitlements) Put(_ context.Context, e *entitlement.Entitlement) error {
+	e.ID, e.CreatedAt, e.UpdatedAt = uuid.New(), time.Now().UTC(), time.Now().UTC()
+	stored := *e
+	r.all = append(r.all, &stored)
+	return nil
+}
+
+func (r *namedEntitlements) Delete(_ context.Context, licenseID uuid.UUID, name string) error {
+	for i, e := range r.all {
+		if e.LicenseID == licenseID && e.Name == name {
+			r.all = append(r.all[:i], r.all[i+1:]...)
+			return nil
+		}
+	}
+	return errFakeNotFound
+}
+
+func TestEntitlementHandler(t *testing.T) {
+	lic := newTestLicense(2)
+	svc := service.NewEntitlementService(&namedEntitlements{}, &licenseLookup{licenses: []*license.License{lic}}, zap.NewNop())
+	h := handler.NewEntitlementHandler(svc, zap.NewNop())
+	base := "/licenses/" + lic.ID.String() + "/entitlements"
+
+	runCases(t, []apiCase{
+		{name: "put", method: http.MethodPut, pattern: "/licenses/:id/entitlements/:name", handler: h.Put,
+			target: base + "/seats", body: `{"type":"integer","value":25}`, status: http.StatusOK},
+		{name: "put mismatched value", method: http.MethodPut, pattern: "/licenses/:id/entitlements/:name", handler: h.Put,
+			target: base + "/sso", body: `{"type":"boolean","value":"yes"}`, status: http.StatusBadRequest},
+		{name: "list", method: http.MethodGet, pattern: "/licenses/:id/entitlements", handler: h.List, target: base, status: http.StatusOK},
+		{name: "delete", method: http.MethodDelete, pattern: "/licenses/:id/entitlements/:name", handler: h.Delete,
+			target: base + "/seats", status: http.StatusNoContent},
+		{name: "delete missing", method: http.MethodDelete, pattern: "/licenses/:id/entitlements/:name", handler: h.Delete,
+			target: base + "/seats", status: http.StatusNotFound},
+	})
+}
+
+// licenseComments keeps the comments of licenses.
+type licenseComments struct {
+	all []*comment.Comment
+}
+
+var _ comment.Repository = (*licenseComments)(nil)
+
+func (r *licenseComments) Create(_ context.Context, c *comment.Comment) error {
+	c.ID, c.CreatedAt = uuid.New(), time.Now().UTC()
+	stored := *c
+	r.all = append(r.all, &stored)
+	return nil
+}
+
+func (r *licenseComments) FindByID(_ context.Context, licenseID, id uuid.UUID) (*comment.Comment, error) {
+	for _, c := range r.all {
+		if c.LicenseID == licenseID && c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, errFakeNotFound
+}
+
+func (r *licenseComments) List(_ context.Context, licenseID uuid.UUID, _, _ int) ([]*comment.Comment, int64, error) {
+	list := []*comment.Comment{}
+	for _, c := range r.all {
+		if c.LicenseID == licenseID {
+			list = append(list, c)
+		}
+	}
+	return list, int64(len(list)), nil
+}
+
+func (r *licenseComments) Delete(_ context.Context, id uuid.UUID) error {
+	for i, c := range r.all {
+		if c.ID == id {
+			r.all = append(r.all[:i], r.all[i+1:]...)
+			return nil
+		}
+	}
+	return errFakeNotFound
+}
+
+func TestCommentHandler(t *testing.T) {
+	lic := newTestLicense(2)
+	comments := &licenseComments{}
+	h := handler.NewCommentHandler(service.NewCommentService(comments, &licenseLookup{licenses: []*license.License{lic}}, zap.NewNop()), zap.NewNop())
+	existing := &comment.Comment{LicenseID: lic.ID, Author: testUser.Subject, AuthorName: testUser.Name, Body: "Renewal agreed by phone"}
+	if err := comments.Create(context.Background(), existing); err != nil {
+		t.Fatal(err)
+	}
+	base := "/licenses/" + lic.ID.String() + "/comments"
+
+	runCases(t, []apiCase{
+		{name: "create", method: http.MethodPost, pattern: "/licenses/:id/comments", handler: h.Create,
+			target: base, body: `{"body":"Customer asked for two more seats"}`, status: http.StatusCreated},
+		{name: "list", method: http.MethodGet, pattern: "/licenses/:id/comments", handler: h.List, target: base + "?limit=10", status: http.StatusOK},
+		{name: "delete", method: http.MethodDelete, pattern: "/licenses/:id/comments/:commentId", handler: h.Delete,
+			target: base + "/" + existing.ID.String(), status: http.StatusNoContent},
+	})
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type LicenseTemplateHandler struct {
+	service *service.LicenseTemplateService
+	logger  *zap.Logger
+}
+
+func NewLicenseTemplateHandler(service *service.LicenseTemplateService, logger *zap.Logger) *LicenseTemplateHandler {
+	return &LicenseTemplateHandler{
+		service: service,
+		logger:  logger.Named("LicenseTemplateHandler"),
+	}
+}
+
+func (h *LicenseTemplateHandler) Create(c *gin.Context) {
+	var req dto.CreateLicenseTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate license template request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	t, err := h.service.CreateTemplate(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, t)
+}
+
+func (h *LicenseTemplateHandler) List(c *gin.Context) {
+	var req dto.ListLicenseTemplatesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	templates, err := h.service.ListTemplates(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.LicenseTemplateListResponse{Templates: templates})
+}
+
+func (h *LicenseTemplateHandler) Get(c *gin.Context) {
+	id, err := idgen.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	t, err := h.service.GetTemplate(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, t)
+}
+
+func (h *LicenseTemplateHandler) Update(c *gin.Context) {
+	id, err := idgen.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.UpdateLicenseTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate license template update", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	t, err := h.service.UpdateTemplate(c.Request.Context(), id, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, t)
+}
+
+func (h *LicenseTemplateHandler) Delete(c *gin.Context) {
+	id, err := idgen.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err := h.service.DeleteTemplate(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,131 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/background"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/display"
+	"github.com/makkenzo/license-service-api/internal/domain/approval"
+	"github.com/makkenzo/license-service-api/internal/domain/customer"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/product"
+	"github.com/makkenzo/license-service-api/internal/handler"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+// licenseStore adds the updates and aggregates of the license handler to
+// licenseWrites.
+type licenseStore struct {
+	licenseWrites
+}
+
+func (r *licenseStore) Update(_ context.Context, l *license.License) error {
+	for i, existing := range r.licenses {
+		if existing.ID == l.ID {
+			l.UpdatedAt = time.Now().UTC()
+			stored := *l
+			r.licenses[i] = &stored
+			return nil
+		}
+	}
+	return errFakeNotFound
+}
+
+func (r *licenseStore) UpdateMetadata(_ context.Context, id uuid.UUID, metadata json.RawMessage) error {
+	for _, l := range r.licenses {
+		if l.ID == id {
+			l.Metadata = metadata
+			return nil
+		}
+	}
+	return errFakeNotFound
+}
+
+func (r *licenseStore) Aggregate(ctx context.Context, params license.AggregateParams) ([]license.AggregateRow, error) {
+	return productCounts{}.Aggregate(ctx, params)
+}
+
+// productPolicies is the acme product without lifecycle, agent or cache
+// policies.
+type productPolicies struct{ acmeProducts }
+
+func (productPolicies) FindLifecycle(context.Context, string) (*product.Lifecycle, error) {
+	return nil, errFakeNotFound
+}
+
+func (productPolicies) FindAgentPolicy(context.Context, string) (*product.AgentPolicy, error) {
+	return nil, errFakeNotFound
+}
+
+func (productPolicies) FindCachePolicy(context.Context, string) (*product.CachePolicy, error) {
+	return nil, errFakeNotFound
+}
+
+// newCustomers creates the customers licenses are issued to.
+type newCustomers struct{ customer.Repository }
+
+func (newCustomers) Ensure(_ context.Context, email, name string) (*customer.Customer, error) {
+	return &customer.Customer{ID: uuid.New(), Email: email, Name: name, CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC()}, nil
+}
+
+type noProtectedKeys struct{ approval.KeyRepository }
+
+func (noProtectedKeys) List(context.Context) ([]*approval.ProtectedKey, error) {
+	return nil, nil
+}
+
+func TestLicenseHandler(t *testing.T) {
+	logger := zap.NewNop()
+	lic, other := newTestLicense(2), newTestLicense(1)
+	licenses := &licenseStore{licenseWrites{licenseLookup{licenses: []*license.License{lic, other}}}}
+	pool := background.NewPool(&config.BackgroundConfig{Workers: 1, QueueSize: 16, TaskTimeout: time.Second}, logger)
+	t.Cleanup(func() { _ = pool.Shutdown(context.Background()) })
+	statuses := service.NewCustomStatusService(&statusStore{}, licenses, &config.CustomStatusesConfig{RefreshInterval: time.Minute}, logger)
+	svc := service.NewLicenseService(licenses, productPolicies{}, newCustomers{}, &deviceActivations{}, &statusHistory{}, noEntitlements{},
+		nil, nil, nil, nil, nil, nil, pool, newKeyring(t), statuses, &config.QueryConfig{MaxOffset: 10000, MaxExportRows: 1000}, logger)
+	approvals := service.NewApprovalService(nil, noProtectedKeys{}, licenses, svc, &config.ApprovalConfig{}, logger)
+	h := handler.NewLicenseHandler(svc, approvals, nil, display.NewLocalizer("en"), logger)
+	id, key := lic.ID, lic.LicenseKey
+	validate := fmt.Sprintf(`{"license_key":%q,"product_name":"acme","agent_version":"1.2.0"}`, key)
+
+	runCases(t, []apiCase{
+		{name: "create", method: http.MethodPost, pattern: "/licenses", handler: h.Create, target: "/licenses",
+			body:   `{"type":"trial","product_name":"acme","customer_email":"buyer@example.com","customer_name":"Buyer","metadata":{"seats":5},"max_activations":3}`,
+			status: http.StatusCreated},
+		{name: "create unknown product", method: http.MethodPost, pattern: "/licenses", handler: h.Create, target: "/licenses",
+			body: `{"type":"trial","product_name":"missing"}`, status: http.StatusBadRequest},
+		{name: "list", method: http.MethodGet, pattern: "/licenses", handler: h.List, target: "/licenses?limit=10&status=active", status: http.StatusOK},
+		{name: "get", method: http.MethodGet, pattern: "/licenses/:id", handler: h.GetByID, target: "/licenses/" + id.String(), status: http.StatusOK},
+		{name: "get missing", method: http.MethodGet, pattern: "/licenses/:id", handler: h.GetByID,
+			target: "/licenses/0190a0e4-0000-7000-8000-000000000001", status: http.StatusNotFound},
+		{name: "update", method: http.MethodPatch, pattern: "/licenses/:id", handler: h.Update, target: "/licenses/" + id.String(),
+			body: `{"metadata":{"plan":"enterprise"},"max_activations":4}`, status: http.StatusOK},
+		{name: "update status", method: http.MethodPatch, pattern: "/licenses/:id/status", handler: h.UpdateStatus,
+			target: "/licenses/" + other.ID.String() + "/status", body: `{"status":"inactive"}`, status: http.StatusOK},
+		{name: "clone", method: http.MethodPost, pattern: "/licenses/:id/clone", handler: h.Clone,
+			target: "/licenses/" + id.String() + "/clone", body: `{"type":"perpetual"}`, status: http.StatusCreated},
+		{name: "search", method: http.MethodGet, pattern: "/licenses/search", handler: h.Search, target: "/licenses/search?q=" + key[:4], status: http.StatusOK},
+		{name: "aggregate", method: http.MethodGet, pattern: "/licenses/aggregate", handler: h.Aggregate, target: "/licenses/aggregate?group_by=product_name", status: http.StatusOK},
+		{name: "compare", method: http.MethodGet, pattern: "/licenses/compare", handler: h.Compare,
+			target: "/licenses/compare?ids=" + id.String() + "," + other.ID.String(), status: http.StatusOK},
+		{name: "license file", method: http.MethodGet, pattern: "/licenses/:id/license-file", handler: h.LicenseFile,
+			target: "/licenses/" + id.String() + "/license-file", status: http.StatusOK},
+		{name: "capabilities", method: http.MethodGet, pattern: "/licenses/capabilities", handler: h.Capabilities, target: "/licenses/capabilities", status: http.StatusOK},
+		{name: "validate", method: http.MethodPost, pattern: "/licenses/validate", handler: h.Validate, target: "/licenses/validate", body: validate, status: http.StatusOK},
+		{name: "validate signed", method: http.MethodPost, pattern: "/licenses/validate", handler: h.Validate, target: "/licenses/validate",
+			body: fmt.Sprintf(`{"license_key":%q,"product_name":"acme","signed_token":true}`, key), status: http.StatusOK},
+		{name: "validate unknown key", method: http.MethodPost, pattern: "/licenses/validate", handler: h.Validate, target: "/licenses/validate",
+			body: `{"license_key":"NOPE-NOPE","product_name":"acme"}`, status: http.StatusOK},
+		{name: "validate batch", method: http.MethodPost, pattern: "/licenses/validate/batch", handler: h.ValidateBatch, target: "/licenses/validate/batch",
+			body: fmt.Sprintf(`{"licenses":[%s,{"license_key":"NOPE-NOPE","product_name":"acme"}]}`, validate), status: http.StatusOK},
+		{name: "passport", method: http.MethodPost, pattern: "/licenses/passport", handler: h.Passport, target: "/licenses/passport", body: validate, status: http.StatusOK},
+	})
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type LintHandler struct {
+	service *service.LintService
+	logger  *zap.Logger
+}
+
+func NewLintHandler(service *service.LintService, logger *zap.Logger) *LintHandler {
+	return &LintHandler{
+		service: service,
+		logger:  logger.Named("LintHandler"),
+	}
+}
+
+func (h *LintHandler) Lint(c *gin.Context) {
+	report, err := h.service.Lint(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type MetaHandler struct {
+	service *service.MetaService
+	logger  *zap.Logger
+}
+
+func NewMetaHandler(service *service.MetaService, logger *zap.Logger) *MetaHandler {
+	return &MetaHandler{
+		service: service,
+		logger:  logger.Named("MetaHandler"),
+	}
+}
+
+func (h *MetaHandler) Enums(c *gin.Context) {
+	resp, err := h.service.Enums(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -12,16 +12,25 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"github.com/makkenzo/license-service-api/internal/audit"
+	"github.com/makkenzo/license-service-api/internal/background"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
 	apikeyDomain "github.com/makkenzo/license-service-api/internal/domain/apikey"
+	domainaudit "github.com/makkenzo/license-service-api/internal/domain/audit"
 	"github.com/makkenzo/license-service-api/internal/ierr"
 	"github.com/makkenzo/license-service-api/internal/util"
 )
 
 const (
-	apiKeyHeader = "X-API-Key"
+	apiKeyHeader        = "X-API-Key"
+	apiKeyIDContextKey  = "apiKeyID"
+	apiKeyOrgContextKey = "apiKeyOrgID"
+	apiKeyContextKey    = "apiKey"
 )
 
-func APIKeyAuthMiddleware(apiKeyRepo apikeyDomain.Repository, logger *zap.Logger) gin.HandlerFunc {
+var ErrAPIKeyScopeRequired = ierr.ErrForbidden.Derive("API_KEY_SCOPE_REQUIRED", "the api key lacks the scope this route requires").WithDetails()
+
+func APIKeyAuthMiddleware(apiKeyRepo apikeyDomain.Repository, pool *background.Pool, crypto cryptoprovider.Provider, logger *zap.Logger) gin.HandlerFunc {
 	log := logger.Named("APIKeyAuthMiddleware")
 	return func(c *gin.Context) {
 		apiKeyFromHeader := c.GetHeader(apiKeyHeader)
@@ -55,7 +64,7 @@ func APIKeyAuthMiddleware(apiKeyRepo apikeyDomain.Repository, logger *zap.Logger
 			return
 		}
 
-		receivedKeyHash := util.HashAPIKey(apiKeyFromHeader)
+		receivedKeyHash := util.HashAPIKey(crypto, apiKeyFromHeader)
 
 		if subtle.ConstantTimeCompare([]byte(receivedKeyHash), []byte(keyRecord.KeyHash)) != 1 {
 			log.Warn("API key hash mismatch", zap.String("prefix", prefix), zap.String("key_id", keyRecord.ID.String()))
@@ -64,18 +73,50 @@ func APIKeyAuthMiddleware(apiKeyRepo apikeyDomain.Repository, logger *zap.Logger
 			return
 		}
 
-		go func(id uuid.UUID, repo apikeyDomain.Repository, l *zap.Logger) {
-			ctxAsync, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			errUpdate := repo.UpdateLastUsed(ctxAsync, id, time.Now().UTC())
+		keyID, usedAt := keyRecord.ID, time.Now().UTC()
+		pool.Submit("apikey_last_used", func(ctxAsync context.Context) {
+			errUpdate := apiKeyRepo.UpdateLastUsed(ctxAsync, keyID, usedAt)
 			if errUpdate != nil {
-				l.Error("Failed to update API key last used time asynchronously", zap.String("key_id", id.String()), zap.Error(errUpdate))
+				log.Error("Failed to update API key last used time asynchronously", zap.String("key_id", keyID.String()), zap.Error(errUpdate))
 			} else {
-				l.Debug("API key last used time updated asynchronously", zap.String("key_id", id.String()))
+				log.Debug("API key last used time updated asynchronously", zap.String("key_id", keyID.String()))
 			}
-		}(keyRecord.ID, apiKeyRepo, log)
+		})
 
 		log.Debug("API key validated successfully", zap.String("prefix", prefix), zap.String("key_id", keyRecord.ID.String()))
+		c.Set(apiKeyIDContextKey, keyRecord.ID)
+		c.Set(apiKeyOrgContextKey, keyRecord.OrgID)
+		c.Set(apiKeyContextKey, keyRecord)
+		c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), domainaudit.Actor{
+			Type:      domainaudit.ActorAPIKey,
+			ID:        keyRecord.ID.String(),
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		}))
+		c.Next()
+	}
+}
+
+// RequireAPIKeyScope rejects requests whose API key lacks scope. It must
+// run after APIKeyAuthMiddleware.
+func RequireAPIKeyScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, _ := c.Get(apiKeyContextKey)
+		key, _ := value.(*apikeyDomain.APIKey)
+		if key == nil || !key.HasScope(scope) {
+			_ = c.Error(fmt.Errorf("%w: %s", ErrAPIKeyScopeRequired, scope))
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
+
+func GetAPIKeyID(c *gin.Context) (uuid.UUID, bool) {
+	value, exists := c.Get(apiKeyIDContextKey)
+	if !exists {
+		return uuid.Nil, false
+	}
+	id, ok := value.(uuid.UUID)
+	return id, ok
+}
@@ -5,6 +5,9 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/audit"
+	"github.com/makkenzo/license-service-api/internal/authz"
+	domainaudit "github.com/makkenzo/license-service-api/internal/domain/audit"
 	"github.com/makkenzo/license-service-api/internal/ierr"
 	"github.com/makkenzo/license-service-api/internal/service"
 	"go.uber.org/zap"
@@ -16,7 +19,9 @@ const (
 	zitadelClaimsContextKey = "zitadelClaims"
 )
 
-func AuthMiddleware(authService *service.AuthService, logger *zap.Logger) gin.HandlerFunc {
+// AuthMiddleware authenticates the user and checks the route against the
+// authorization policy of enforcer, which may be nil.
+func AuthMiddleware(authService *service.AuthService, enforcer *authz.Enforcer, logger *zap.Logger) gin.HandlerFunc {
 	log := logger.Named("AuthMiddleware")
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader(authorizationHeader)
@@ -28,7 +33,7 @@ func AuthMiddleware(authService *service.AuthService, logger *zap.Logger) gin.Ha
 		}
 
 		if !strings.HasPrefix(authHeader, bearerPrefix) {
-			log.Debug("Authorization header format is invalid", zap.String("header", authHeader))
+			log.Debug("Authorization header format is invalid", zap.String("authorization", authHeader))
 			_ = c.Error(fmt.Errorf("%w: invalid authorization header format", ierr.ErrUnauthorized))
 			c.Abort()
 			return
@@ -50,13 +55,36 @@ func AuthMiddleware(authService *service.AuthService, logger *zap.Logger) gin.Ha
 			return
 		}
 
+		if !enforcer.Allows(c.Request.Method, c.FullPath(), claims.HasRole) {
+			log.Warn("Route denied by authorization policy",
+				zap.String("subject", claims.Subject),
+				zap.String("method", c.Request.Method),
+				zap.String("route", c.FullPath()),
+			)
+			_ = c.Error(authz.ErrRouteForbidden)
+			c.Abort()
+			return
+		}
+
 		log.Debug("Access Token validated, setting claims in context", zap.String("subject", claims.Subject))
-		c.Set(zitadelClaimsContextKey, claims)
+		SetUserClaims(c, claims)
 
 		c.Next()
 	}
 }
 
+// SetUserClaims makes the request authenticated as the user of claims, who
+// also becomes the audit actor.
+func SetUserClaims(c *gin.Context, claims *service.ZitadelClaims) {
+	c.Set(zitadelClaimsContextKey, claims)
+	c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), domainaudit.Actor{
+		Type:      domainaudit.ActorUser,
+		ID:        claims.Subject,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}))
+}
+
 func GetUserClaims(c *gin.Context) *service.ZitadelClaims {
 	value, exists := c.Get(zitadelClaimsContextKey)
 	if !exists {
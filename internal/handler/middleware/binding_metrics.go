@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/makkenzo/license-service-api/internal/background"
+	"github.com/makkenzo/license-service-api/internal/domain/bindingfailure"
+	"github.com/makkenzo/license-service-api/internal/jsonlimit"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+const bodyField = "_body"
+
+var bindingFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "api_binding_failures_total",
+	Help: "Requests rejected while binding or validating the payload, by endpoint and field.",
+}, []string{"endpoint", "field"})
+
+// BindingFailureMiddleware counts payload binding failures per endpoint and
+// field, and tallies them per client in the weekly failure store.
+func BindingFailureMiddleware(failures bindingfailure.Repository, pool *background.Pool, logger *zap.Logger) gin.HandlerFunc {
+	log := logger.Named("BindingFailureMiddleware")
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 {
+			return
+		}
+
+		fields := bindingFailureFields(c.Errors.Last().Err)
+		if len(fields) == 0 {
+			return
+		}
+
+		endpoint := c.Request.Method + " " + c.FullPath()
+		client := clientIdentity(c)
+		for _, field := range fields {
+			bindingFailuresTotal.WithLabelValues(endpoint, field).Inc()
+		}
+
+		pool.Submit("binding_failure_record", func(ctx context.Context) {
+			for _, field := range fields {
+				failure := bindingfailure.Failure{Endpoint: endpoint, Field: field, Client: client}
+				if err := failures.Record(ctx, failure); err != nil {
+					log.Warn("Failed to record binding failure", zap.String("endpoint", endpoint), zap.String("field", field), zap.Error(err))
+				}
+			}
+		})
+	}
+}
+
+func bindingFailureFields(err error) []string {
+	var ve validator.ValidationErrors
+	if errors.As(err, &ve) {
+		fields := make([]string, len(ve))
+		for i, fe := range ve {
+			fields[i] = fe.Field()
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		if typeErr.Field != "" {
+			return []string{typeErr.Field}
+		}
+		return []string{bodyField}
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) ||
+		errors.Is(err, jsonlimit.ErrMalformed) ||
+		errors.Is(err, jsonlimit.ErrInvalidUTF8) ||
+		errors.Is(err, jsonlimit.ErrTooDeep) ||
+		errors.Is(err, jsonlimit.ErrNumberTooLong) ||
+		errors.Is(err, jsonlimit.ErrStringTooLong) ||
+		errors.Is(err, jsonlimit.ErrTooManyValues) ||
+		errors.Is(err, jsonlimit.ErrDocumentTooBig) {
+		return []string{bodyField}
+	}
+
+	return nil
+}
+
+func clientIdentity(c *gin.Context) string {
+	if id, ok := GetAPIKeyID(c); ok {
+		return "apikey:" + id.String()
+	}
+	if claims := GetUserClaims(c); claims != nil {
+		return "user:" + claims.Subject
+	}
+	return "anonymous"
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/buildinfo"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Headers identifying the instance that served a response.
+const (
+	HeaderInstanceID     = "X-Instance-ID"
+	HeaderServiceVersion = "X-Service-Version"
+	HeaderReleaseTrack   = "X-Release-Track"
+)
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests served, by route, status and the version and release track of the instance.",
+	}, []string{"method", "route", "status", "version", "track"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Time to serve HTTP requests, by route and the version and release track of the instance.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "version", "track"})
+
+	buildInfo = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "license_service_build_info",
+		Help: "Always 1; labels the instance with its version and release track.",
+	}, []string{"version", "track", "instance_id"})
+)
+
+// DeploymentMiddleware stamps every response with the instance ID, version
+// and release track, and records request metrics labelled with the version
+// and track so canary and stable instances can be compared during a rollout.
+func DeploymentMiddleware(cfg *config.DeploymentConfig) gin.HandlerFunc {
+	version := buildinfo.ServiceVersion()
+	buildInfo.WithLabelValues(version, cfg.Track, cfg.InstanceID).Set(1)
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Header(HeaderInstanceID, cfg.InstanceID)
+		c.Header(HeaderServiceVersion, version)
+		c.Header(HeaderReleaseTrack, cfg.Track)
+
+		c.Next()
+
+		// Unmatched paths share a label so scanners cannot blow up the
+		// number of series.
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+		httpRequestsTotal.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status()), version, cfg.Track).Inc()
+		httpRequestDuration.WithLabelValues(method, route, version, cfg.Track).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/netip"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/enrichment"
+)
+
+// EnrichmentMiddleware attaches the network context of the request, looked
+// up by enricher, to the request context; see package enrichment.
+func EnrichmentMiddleware(enricher *enrichment.Enricher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ip, _ := netip.ParseAddr(c.ClientIP())
+		info := enricher.Enrich(c.Request.Context(), &enrichment.Request{
+			IP:        ip,
+			UserAgent: c.Request.UserAgent(),
+			Header:    c.Request.Header,
+		})
+		c.Request = c.Request.WithContext(enrichment.WithInfo(c.Request.Context(), info))
+		c.Next()
+	}
+}
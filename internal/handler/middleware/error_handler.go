@@ -1,8 +1,10 @@
 package middleware
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -22,65 +24,81 @@ func ErrorHandlerMiddleware(logger *zap.Logger) gin.HandlerFunc {
 		}
 
 		err := c.Errors.Last().Err
-		log.Error("Request failed", zap.Error(err))
-
-		status := http.StatusInternalServerError
-		errResponse := dto.APIErrorResponse{
-			Code:    "INTERNAL_ERROR",
-			Message: "An unexpected error occurred.",
-		}
 
 		var ve validator.ValidationErrors
-
 		if errors.As(err, &ve) {
-			status = http.StatusBadRequest
-			errResponse.Code = "VALIDATION_ERROR"
-			errResponse.Message = "Input validation failed."
-			errResponse.Details = buildValidationErrors(ve)
-		} else {
-			switch {
-			case errors.Is(err, ierr.ErrValidation):
-				status = http.StatusBadRequest
-				errResponse.Code = "VALIDATION_ERROR"
-				errResponse.Message = err.Error()
-			case errors.Is(err, ierr.ErrUnauthorized), errors.Is(err, ierr.ErrInvalidCredentials), errors.Is(err, ierr.ErrInvalidToken):
-				status = http.StatusUnauthorized
-				errResponse.Code = "UNAUTHENTICATED"
-				errResponse.Message = "Authentication required or failed."
+			log.Info("Request failed validation", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.APIErrorResponse{
+				Code:    "VALIDATION_ERROR",
+				Message: "Input validation failed.",
+				Details: buildValidationErrors(ve),
+			})
+			return
+		}
+
+		err = codeBindingError(err)
+		status, code, message := ierr.Describe(err)
+		var coded ierr.Coded
+		switch {
+		case !errors.As(err, &coded):
+			// Every error a handler reports should carry a code; seeing this
+			// means a new error case was added without declaring one.
+			log.Error("Request failed with an uncoded error", zap.Error(err))
+		case status >= http.StatusInternalServerError:
+			log.Error("Request failed", zap.Error(err), zap.String("code", code))
+		default:
+			log.Info("Request failed", zap.Error(err), zap.String("code", code))
+		}
 
-			case errors.Is(err, ierr.ErrForbidden):
-				status = http.StatusForbidden
-				errResponse.Code = "FORBIDDEN"
-				errResponse.Message = "Access denied."
-			case errors.Is(err, ierr.ErrNotFound), errors.Is(err, ierr.ErrUserNotFound):
-				status = http.StatusNotFound
-				errResponse.Code = "NOT_FOUND"
-				errResponse.Message = "The requested resource was not found."
-			case errors.Is(err, ierr.ErrConflict):
-				status = http.StatusConflict
-				errResponse.Code = "CONFLICT"
-				errResponse.Message = err.Error()
-			default:
-				errResponse.Message = err.Error()
+		errResponse := dto.APIErrorResponse{
+			Code:    code,
+			Message: message,
+		}
+		var fieldErrs *ierr.FieldErrors
+		if errors.As(err, &fieldErrs) {
+			details := make([]dto.FieldError, len(fieldErrs.Fields))
+			for i, f := range fieldErrs.Fields {
+				details[i] = dto.FieldError{Field: f.Field, Message: f.Message}
 			}
+			errResponse.Details = details
 		}
 
 		c.AbortWithStatusJSON(status, errResponse)
 	}
 }
 
+// codeBindingError gives request body decoding failures, which handlers pass
+// through straight from ShouldBindJSON, the validation code they deserve.
+func codeBindingError(err error) error {
+	var coded ierr.Coded
+	if errors.As(err, &coded) {
+		return err
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return fmt.Errorf("%w: malformed request body: %w", ierr.ErrValidation, err)
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return fmt.Errorf("%w: request body is empty or truncated", ierr.ErrValidation)
+	}
+	return err
+}
+
 func buildValidationErrors(ve validator.ValidationErrors) []dto.FieldError {
 	details := make([]dto.FieldError, len(ve))
 	for i, fe := range ve {
 		details[i] = dto.FieldError{
 			Field:   fe.Field(),
-			Message: getValidationErrorMsg(fe),
+			Message: ValidationErrorMessage(fe),
 		}
 	}
 	return details
 }
 
-func getValidationErrorMsg(fe validator.FieldError) string {
+// ValidationErrorMessage describes a failed binding rule to clients.
+func ValidationErrorMessage(fe validator.FieldError) string {
 
 	switch fe.Tag() {
 	case "required":
@@ -93,8 +111,12 @@ func getValidationErrorMsg(fe validator.FieldError) string {
 		return fmt.Sprintf("Field '%s' must be greater than or equal to %s", fe.Field(), fe.Param())
 	case "lte":
 		return fmt.Sprintf("Field '%s' must be less than or equal to %s", fe.Field(), fe.Param())
+	case "max":
+		return fmt.Sprintf("Field '%s' must be at most %s characters long", fe.Field(), fe.Param())
 	case "gt":
 		return fmt.Sprintf("Field '%s' must be greater than %s", fe.Field(), fe.Param())
+	case "license_status":
+		return fmt.Sprintf("Field '%s' must be a built-in or custom license status", fe.Field())
 	default:
 		return fmt.Sprintf("Field '%s' failed validation on the '%s' tag", fe.Field(), fe.Tag())
 	}
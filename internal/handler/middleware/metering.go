@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/apiusage"
+	"github.com/makkenzo/license-service-api/internal/metering"
+)
+
+const meteredCallsContextKey = "meteredCalls"
+
+// MeteringMiddleware counts every call authenticated as a user, as an admin
+// call of the user's organization, and every call authenticated with an API
+// key, as a validate call of the key's organization. Calls that fail
+// authentication are not counted. It must run outside ErrorHandlerMiddleware
+// so the response status is already known.
+func MeteringMiddleware(meter *metering.Meter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		at := time.Now()
+		c.Next()
+
+		failed := c.Writer.Status() >= 400
+		calls := int64(1)
+		if n := c.GetInt64(meteredCallsContextKey); n > 0 {
+			calls = n
+		}
+		if claims := GetUserClaims(c); claims != nil {
+			meter.Record(at, claims.OrgID, apiusage.APIAdmin, uuid.Nil, calls, failed)
+			return
+		}
+		if keyID, ok := GetAPIKeyID(c); ok {
+			meter.Record(at, c.GetString(apiKeyOrgContextKey), apiusage.APIValidate, keyID, calls, failed)
+		}
+	}
+}
+
+// SetMeteredCalls makes the request count as n calls, for batch endpoints
+// that do the work of n single calls.
+func SetMeteredCalls(c *gin.Context, n int) {
+	c.Set(meteredCallsContextKey, int64(n))
+}
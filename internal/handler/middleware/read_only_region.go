@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/region"
+)
+
+// replicaWriteRoutes are the writes a replica region still accepts: agents
+// validating, (de)activating and sending heartbeats close to where they
+// run. Their side effects are forwarded to the primary by the region
+// repositories.
+var replicaWriteRoutes = map[string]bool{
+	"/api/v1/licenses/validate":       true,
+	"/api/v1/licenses/validate/batch": true,
+	"/api/v1/licenses/passport":       true,
+	"/api/v1/licenses/activate":       true,
+	"/api/v1/licenses/deactivate":     true,
+	"/api/v1/licenses/heartbeat":      true,
+	// Connect calls are POSTs even when they only read.
+	"/license.v1.LicenseEventService/SubscribeLicenseEvents": true,
+}
+
+// ReadOnlyRegionMiddleware rejects management writes on a replica region,
+// pointing callers at the primary instead.
+func ReadOnlyRegionMiddleware(primaryURL string) gin.HandlerFunc {
+	readOnly := region.ErrReadOnly.Derive("READ_ONLY_REGION", "write rejected by read-only replica").
+		WithPublicMessage("This region is a read-only replica; send changes to the primary region at " + primaryURL + ".")
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		if replicaWriteRoutes[c.FullPath()] {
+			c.Next()
+			return
+		}
+		_ = c.Error(readOnly)
+		c.Abort()
+	}
+}
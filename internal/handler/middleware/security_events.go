@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/audit"
+	domainaudit "github.com/makkenzo/license-service-api/internal/domain/audit"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/siem"
+)
+
+// SecurityEventsMiddleware reports rejected requests to the SIEM: 401s and
+// 403s of callers that never authenticated as auth events, 403s of
+// authenticated callers as access events. It must run outside
+// ErrorHandlerMiddleware so the response status is already known.
+func SecurityEventsMiddleware(exporter *siem.Exporter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		status := c.Writer.Status()
+		if status != http.StatusUnauthorized && status != http.StatusForbidden {
+			return
+		}
+
+		actor := audit.ActorFrom(c.Request.Context())
+		category := siem.CategoryAccess
+		if actor.Type == domainaudit.ActorSystem {
+			category = siem.CategoryAuth
+			actor = domainaudit.Actor{Type: siem.ActorAnonymous, IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+		}
+
+		details := map[string]interface{}{
+			"method": c.Request.Method,
+			"path":   c.FullPath(),
+			"status": status,
+		}
+		if len(c.Errors) > 0 {
+			_, code, _ := ierr.Describe(c.Errors.Last().Err)
+			details["code"] = code
+		}
+
+		exporter.Publish(siem.Event{
+			Category: category,
+			Action:   "request.rejected",
+			Severity: siem.SeverityWarning,
+			Outcome:  "failure",
+			Actor:    actor,
+			Details:  details,
+		})
+	}
+}
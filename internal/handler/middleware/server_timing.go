@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/servertiming"
+)
+
+// ServerTimingMiddleware adds a Server-Timing header with the time spent in
+// the database, the cache and license policy checks to every response. It
+// is a debugging aid: the header tells clients about the internals of a
+// request, so it is off by default.
+func ServerTimingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timings := servertiming.New(time.Now())
+		c.Request = c.Request.WithContext(servertiming.NewContext(c.Request.Context(), timings))
+		writer := &serverTimingWriter{ResponseWriter: c.Writer, timings: timings}
+		c.Writer = writer
+
+		c.Next()
+
+		// Responses without a body are written after the handlers return.
+		writer.setHeader()
+	}
+}
+
+// serverTimingWriter sets the header right before the response headers go
+// out, the last moment it can.
+type serverTimingWriter struct {
+	gin.ResponseWriter
+	timings *servertiming.Timings
+	done    bool
+}
+
+func (w *serverTimingWriter) setHeader() {
+	if w.done || w.ResponseWriter.Written() {
+		return
+	}
+	w.done = true
+	w.Header().Set(servertiming.HeaderName, w.timings.Header(time.Now()))
+}
+
+func (w *serverTimingWriter) WriteHeaderNow() {
+	w.setHeader()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *serverTimingWriter) Write(b []byte) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *serverTimingWriter) WriteString(s string) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *serverTimingWriter) Flush() {
+	w.setHeader()
+	w.ResponseWriter.Flush()
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/slotracker"
+)
+
+// SLOMiddleware records the status and latency of calls to the route
+// template route with method. It must run outside ErrorHandlerMiddleware so
+// the response status is already known.
+func SLOMiddleware(tracker *slotracker.Tracker, method, route string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		at := time.Now()
+		c.Next()
+
+		if c.Request.Method == method && c.FullPath() == route {
+			tracker.Record(at, c.Writer.Status(), time.Since(at))
+		}
+	}
+}
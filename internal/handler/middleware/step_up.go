@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+// RequireStepUp lets a request through only if the user signed in or
+// stepped up recently, see service.StepUpService. It runs after
+// AuthMiddleware; with a nil stepUp every request passes.
+func RequireStepUp(stepUp *service.StepUpService, logger *zap.Logger) gin.HandlerFunc {
+	log := logger.Named("StepUpMiddleware")
+	return func(c *gin.Context) {
+		if stepUp == nil {
+			c.Next()
+			return
+		}
+
+		claims := GetUserClaims(c)
+		if claims == nil {
+			_ = c.Error(fmt.Errorf("%w: user claims missing", ierr.ErrUnauthorized))
+			c.Abort()
+			return
+		}
+
+		fresh, err := stepUp.Fresh(c.Request.Context(), claims)
+		if err != nil {
+			log.Error("Failed to check step-up", zap.String("subject", claims.Subject), zap.Error(err))
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+		if !fresh {
+			log.Info("Step-up required", zap.String("subject", claims.Subject), zap.String("route", c.FullPath()))
+			// RFC 9470 tells clients how recent the authentication must be.
+			c.Header("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_user_authentication", max_age=%d`, int(stepUp.MaxAge().Seconds())))
+			_ = c.Error(service.ErrStepUpRequired)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
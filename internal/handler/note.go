@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/domain/note"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+// inboundMaxBytes bounds webhook bodies. Mailgun forwards attachments along
+// with the message, which are read and discarded.
+const inboundMaxBytes = 25 << 20
+
+type NoteHandler struct {
+	service *service.NoteService
+	logger  *zap.Logger
+}
+
+func NewNoteHandler(service *service.NoteService, logger *zap.Logger) *NoteHandler {
+	return &NoteHandler{
+		service: service,
+		logger:  logger.Named("NoteHandler"),
+	}
+}
+
+// Mailgun receives messages forwarded by a Mailgun route. It is
+// authenticated by the Mailgun signature, not by a session.
+func (h *NoteHandler) Mailgun(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, inboundMaxBytes)
+	if err := c.Request.ParseMultipartForm(1 << 20); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		h.logger.Warn("Failed to parse Mailgun webhook", zap.Error(err))
+		_ = c.Error(inboundReadError(err))
+		return
+	}
+	if c.Request.MultipartForm != nil {
+		defer func() { _ = c.Request.MultipartForm.RemoveAll() }()
+	}
+
+	if err := h.service.IngestMailgun(c.Request.Context(), c.Request.PostForm); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SES receives the SNS messages of the topic an SES receipt rule publishes
+// to. SNS posts JSON with a text/plain content type.
+func (h *NoteHandler) SES(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, inboundMaxBytes)
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		h.logger.Warn("Failed to read SNS webhook", zap.Error(err))
+		_ = c.Error(inboundReadError(err))
+		return
+	}
+
+	if err := h.service.IngestSNS(c.Request.Context(), body); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func inboundReadError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return fmt.Errorf("%w: inbound e-mail exceeds %d bytes", ierr.ErrPayloadTooLarge, inboundMaxBytes)
+	}
+	return fmt.Errorf("%w: malformed inbound e-mail request", ierr.ErrValidation)
+}
+
+func (h *NoteHandler) ListForLicense(c *gin.Context) {
+	id, err := idgen.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.ListNotesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	notes, total, err := h.service.ListForLicense(c.Request.Context(), id, &req)
+	h.respondList(c, &req, notes, total, err)
+}
+
+func (h *NoteHandler) ListForCustomer(c *gin.Context) {
+	id, err := idgen.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.ListNotesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	notes, total, err := h.service.ListForCustomer(c.Request.Context(), id, &req)
+	h.respondList(c, &req, notes, total, err)
+}
+
+func (h *NoteHandler) respondList(c *gin.Context, req *dto.ListNotesRequest, notes []*note.Note, total int64, err error) {
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PaginatedNoteResponse{
+		Notes:      notes,
+		TotalCount: total,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	})
+}
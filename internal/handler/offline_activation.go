@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type OfflineActivationHandler struct {
+	service *service.OfflineActivationService
+	logger  *zap.Logger
+}
+
+func NewOfflineActivationHandler(service *service.OfflineActivationService, logger *zap.Logger) *OfflineActivationHandler {
+	return &OfflineActivationHandler{
+		service: service,
+		logger:  logger.Named("OfflineActivationHandler"),
+	}
+}
+
+func (h *OfflineActivationHandler) Challenge(c *gin.Context) {
+	var req dto.ActivationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate offline challenge request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	resp, err := h.service.IssueChallenge(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
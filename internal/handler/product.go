@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type ProductHandler struct {
+	service *service.ProductService
+	logger  *zap.Logger
+}
+
+func NewProductHandler(service *service.ProductService, logger *zap.Logger) *ProductHandler {
+	return &ProductHandler{
+		service: service,
+		logger:  logger.Named("ProductHandler"),
+	}
+}
+
+func (h *ProductHandler) Create(c *gin.Context) {
+	var req dto.CreateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate create product request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	p, err := h.service.CreateProduct(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, p)
+}
+
+func (h *ProductHandler) List(c *gin.Context) {
+	products, err := h.service.ListProducts(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, products)
+}
+
+func (h *ProductHandler) Get(c *gin.Context) {
+	p, err := h.service.GetProduct(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+func (h *ProductHandler) Update(c *gin.Context) {
+	var req dto.UpdateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate update product request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	p, err := h.service.UpdateProduct(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+func (h *ProductHandler) Delete(c *gin.Context) {
+	if err := h.service.DeleteProduct(c.Request.Context(), c.Param("name")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ProductHandler) ListLifecycles(c *gin.Context) {
+	lifecycles, err := h.service.ListLifecycles(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, lifecycles)
+}
+
+func (h *ProductHandler) GetLifecycle(c *gin.Context) {
+	lc, err := h.service.GetLifecycle(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, lc)
+}
+
+func (h *ProductHandler) SetLifecycle(c *gin.Context) {
+	var req dto.SetProductLifecycleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate product lifecycle request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	lc, err := h.service.SetLifecycle(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, lc)
+}
+
+func (h *ProductHandler) ListAgentPolicies(c *gin.Context) {
+	policies, err := h.service.ListAgentPolicies(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policies)
+}
+
+func (h *ProductHandler) GetAgentPolicy(c *gin.Context) {
+	p, err := h.service.GetAgentPolicy(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+func (h *ProductHandler) SetAgentPolicy(c *gin.Context) {
+	var req dto.SetAgentPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate agent policy request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	p, err := h.service.SetAgentPolicy(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+func (h *ProductHandler) DeleteAgentPolicy(c *gin.Context) {
+	if err := h.service.DeleteAgentPolicy(c.Request.Context(), c.Param("name")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ProductHandler) GetCachePolicy(c *gin.Context) {
+	p, err := h.service.GetCachePolicy(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+func (h *ProductHandler) SetCachePolicy(c *gin.Context) {
+	var req dto.SetCachePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate cache policy request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	p, err := h.service.SetCachePolicy(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+func (h *ProductHandler) DeleteCachePolicy(c *gin.Context) {
+	if err := h.service.DeleteCachePolicy(c.Request.Context(), c.Param("name")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ProductHandler) StartMigrationCampaign(c *gin.Context) {
+	var req dto.StartMigrationCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate migration campaign request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	result, err := h.service.StartMigrationCampaign(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	status := http.StatusAccepted
+	if result.DryRun {
+		status = http.StatusOK
+	}
+	c.JSON(status, result)
+}
+
+func (h *ProductHandler) MigrateMetadata(c *gin.Context) {
+	var req dto.MigrateMetadataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate metadata migration request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	result, err := h.service.MigrateMetadata(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	status := http.StatusAccepted
+	if result.DryRun {
+		status = http.StatusOK
+	}
+	c.JSON(status, result)
+}
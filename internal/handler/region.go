@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/jsonlimit"
+	"github.com/makkenzo/license-service-api/internal/region"
+	"go.uber.org/zap"
+)
+
+// RegionHandler receives writes forwarded by replica regions. It is only
+// routed on the primary and authenticates callers by the shared secret.
+type RegionHandler struct {
+	applier *region.Applier
+	crypto  cryptoprovider.Provider
+	secret  string
+	logger  *zap.Logger
+}
+
+func NewRegionHandler(applier *region.Applier, crypto cryptoprovider.Provider, secret string, logger *zap.Logger) *RegionHandler {
+	return &RegionHandler{
+		applier: applier,
+		crypto:  crypto,
+		secret:  secret,
+		logger:  logger.Named("RegionHandler"),
+	}
+}
+
+func (h *RegionHandler) ApplyWrite(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(jsonlimit.Default.MaxBytes))
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			err = jsonlimit.ErrDocumentTooBig
+		}
+		h.logger.Warn("Failed to read forwarded write", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	if err := region.Verify(h.crypto, h.secret, c.GetHeader(region.SignatureHeader), body, time.Now()); err != nil {
+		h.logger.Warn("Rejected forwarded write with bad signature", zap.String("ip", c.ClientIP()), zap.Error(err))
+		_ = c.Error(ierr.ErrUnauthorized)
+		return
+	}
+
+	var w region.Write
+	if err := json.Unmarshal(body, &w); err != nil {
+		h.logger.Warn("Failed to decode forwarded write", zap.Error(err))
+		_ = c.Error(fmt.Errorf("%w: malformed forwarded write", ierr.ErrValidation))
+		return
+	}
+
+	if err := h.applier.Apply(c.Request.Context(), &w); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/handler/middleware"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type RenewalHandler struct {
+	service *service.RenewalService
+	logger  *zap.Logger
+}
+
+func NewRenewalHandler(service *service.RenewalService, logger *zap.Logger) *RenewalHandler {
+	return &RenewalHandler{
+		service: service,
+		logger:  logger.Named("RenewalHandler"),
+	}
+}
+
+func (h *RenewalHandler) CreateOffer(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid ID format for renewal offer", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(fmt.Errorf("%w: invalid license id format", ierr.ErrValidation))
+		return
+	}
+
+	var req dto.CreateRenewalOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate create renewal offer request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	createdBy := ""
+	if claims := middleware.GetUserClaims(c); claims != nil {
+		createdBy = claims.Subject
+	}
+
+	offer, err := h.service.CreateOffer(c.Request.Context(), id, &req, createdBy)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, offer)
+}
+
+func (h *RenewalHandler) GetOffer(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		_ = c.Error(fmt.Errorf("%w: token is required", ierr.ErrValidation))
+		return
+	}
+
+	offer, err := h.service.GetOffer(c.Request.Context(), token)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, offer)
+}
+
+func (h *RenewalHandler) Accept(c *gin.Context) {
+	var req dto.AcceptRenewalOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate accept renewal request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	result, err := h.service.AcceptOffer(c.Request.Context(), &req, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type ReportHandler struct {
+	service *service.ReportService
+	logger  *zap.Logger
+}
+
+func NewReportHandler(service *service.ReportService, logger *zap.Logger) *ReportHandler {
+	return &ReportHandler{
+		service: service,
+		logger:  logger.Named("ReportHandler"),
+	}
+}
+
+func (h *ReportHandler) BindingFailures(c *gin.Context) {
+	var req dto.BindingFailureReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	report, err := h.service.BindingFailures(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Service failed to build binding failure report", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,163 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/makkenzo/license-service-api/internal/handler"
+	"go.uber.org/zap"
+)
+
+// TestRequestErrors covers the requests every handler rejects before
+// reaching its service: malformed IDs and bodies.
+func TestRequestErrors(t *testing.T) {
+	logger := zap.NewNop()
+	activationHandler := handler.NewActivationHandler(nil, logger)
+	analyticsExportHandler := handler.NewAnalyticsExportHandler(nil, logger)
+	apiKeyHandler := handler.NewAPIKeyHandler(nil, logger)
+	approvalHandler := handler.NewApprovalHandler(nil, logger)
+	auditHandler := handler.NewAuditHandler(nil, logger)
+	bulkRevokeHandler := handler.NewBulkRevokeHandler(nil, logger)
+	campaignHandler := handler.NewCampaignHandler(nil, logger)
+	commentHandler := handler.NewCommentHandler(nil, logger)
+	customStatusHandler := handler.NewCustomStatusHandler(nil, logger)
+	customerHandler := handler.NewCustomerHandler(nil, nil, logger)
+	dashboardHandler := handler.NewDashboardHandler(nil, nil, logger)
+	entitlementHandler := handler.NewEntitlementHandler(nil, logger)
+	expiryNoticeHandler := handler.NewExpiryNoticeHandler(nil, logger)
+	extensionHandler := handler.NewExtensionHandler(nil, logger)
+	floatingHandler := handler.NewFloatingHandler(nil, logger)
+	integrityHandler := handler.NewIntegrityHandler(nil, logger)
+	keyRotationHandler := handler.NewKeyRotationHandler(nil, logger)
+	licenseHandler := handler.NewLicenseHandler(nil, nil, nil, nil, logger)
+	licenseHierarchyHandler := handler.NewLicenseHierarchyHandler(nil, nil, logger)
+	licenseTemplateHandler := handler.NewLicenseTemplateHandler(nil, logger)
+	noteHandler := handler.NewNoteHandler(nil, logger)
+	offlineActivationHandler := handler.NewOfflineActivationHandler(nil, logger)
+	productHandler := handler.NewProductHandler(nil, logger)
+	renewalHandler := handler.NewRenewalHandler(nil, logger)
+	revocationHandler := handler.NewRevocationHandler(nil, logger)
+	signingKeyHandler := handler.NewSigningKeyHandler(nil, logger)
+	statusFreezeHandler := handler.NewStatusFreezeHandler(nil, logger)
+	stepUpHandler := handler.NewStepUpHandler(nil, logger)
+	suspensionHandler := handler.NewSuspensionHandler(nil, logger)
+	templateHandler := handler.NewTemplateHandler(nil, logger)
+	usageHandler := handler.NewUsageHandler(nil, logger)
+	webhookHandler := handler.NewWebhookHandler(nil, logger)
+
+	runCases(t, []apiCase{
+		{name: "POST /licenses/validate malformed body", method: http.MethodPost, pattern: "/licenses/validate", handler: licenseHandler.Validate, target: "/licenses/validate", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /licenses/validate/batch malformed body", method: http.MethodPost, pattern: "/licenses/validate/batch", handler: licenseHandler.ValidateBatch, target: "/licenses/validate/batch", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /licenses/passport malformed body", method: http.MethodPost, pattern: "/licenses/passport", handler: licenseHandler.Passport, target: "/licenses/passport", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /licenses/activate malformed body", method: http.MethodPost, pattern: "/licenses/activate", handler: activationHandler.Activate, target: "/licenses/activate", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /licenses/deactivate malformed body", method: http.MethodPost, pattern: "/licenses/deactivate", handler: activationHandler.Deactivate, target: "/licenses/deactivate", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /licenses/heartbeat malformed body", method: http.MethodPost, pattern: "/licenses/heartbeat", handler: activationHandler.Heartbeat, target: "/licenses/heartbeat", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /licenses/offline/challenge malformed body", method: http.MethodPost, pattern: "/licenses/offline/challenge", handler: offlineActivationHandler.Challenge, target: "/licenses/offline/challenge", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /licenses/usage malformed body", method: http.MethodPost, pattern: "/licenses/usage", handler: usageHandler.Report, target: "/licenses/usage", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /licenses/checkout malformed body", method: http.MethodPost, pattern: "/licenses/checkout", handler: floatingHandler.Checkout, target: "/licenses/checkout", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /licenses/checkin malformed body", method: http.MethodPost, pattern: "/licenses/checkin", handler: floatingHandler.Checkin, target: "/licenses/checkin", body: `{`, status: http.StatusBadRequest},
+		{name: "GET /licenses/:id/usage-summary invalid id", method: http.MethodGet, pattern: "/licenses/:id/usage-summary", handler: activationHandler.UsageSummary, target: "/licenses/not-a-uuid/usage-summary", status: http.StatusBadRequest},
+		{name: "POST /licenses malformed body", method: http.MethodPost, pattern: "/licenses", handler: licenseHandler.Create, target: "/licenses", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /licenses/bulk-revoke malformed body", method: http.MethodPost, pattern: "/licenses/bulk-revoke", handler: bulkRevokeHandler.BulkRevoke, target: "/licenses/bulk-revoke", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /licenses/import malformed body", method: http.MethodPost, pattern: "/licenses/import", handler: licenseHandler.Import, target: "/licenses/import", body: `{`, status: http.StatusBadRequest},
+		{name: "GET /licenses/:id invalid id", method: http.MethodGet, pattern: "/licenses/:id", handler: licenseHandler.GetByID, target: "/licenses/not-a-uuid", status: http.StatusBadRequest},
+		{name: "PATCH /licenses/:id invalid id", method: http.MethodPatch, pattern: "/licenses/:id", handler: licenseHandler.Update, target: "/licenses/not-a-uuid", body: `{}`, status: http.StatusBadRequest},
+		{name: "PATCH /licenses/:id malformed body", method: http.MethodPatch, pattern: "/licenses/:id", handler: licenseHandler.Update, target: "/licenses/0190a0e4-0000-7000-8000-000000000001", body: `{`, status: http.StatusBadRequest},
+		{name: "PATCH /licenses/:id/status invalid id", method: http.MethodPatch, pattern: "/licenses/:id/status", handler: licenseHandler.UpdateStatus, target: "/licenses/not-a-uuid/status", body: `{}`, status: http.StatusBadRequest},
+		{name: "PATCH /licenses/:id/status malformed body", method: http.MethodPatch, pattern: "/licenses/:id/status", handler: licenseHandler.UpdateStatus, target: "/licenses/0190a0e4-0000-7000-8000-000000000001/status", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/suspend invalid id", method: http.MethodPost, pattern: "/licenses/:id/suspend", handler: suspensionHandler.Suspend, target: "/licenses/not-a-uuid/suspend", body: `{}`, status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/suspend malformed body", method: http.MethodPost, pattern: "/licenses/:id/suspend", handler: suspensionHandler.Suspend, target: "/licenses/0190a0e4-0000-7000-8000-000000000001/suspend", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/reinstate invalid id", method: http.MethodPost, pattern: "/licenses/:id/reinstate", handler: suspensionHandler.Reinstate, target: "/licenses/not-a-uuid/reinstate", body: `{}`, status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/reinstate malformed body", method: http.MethodPost, pattern: "/licenses/:id/reinstate", handler: suspensionHandler.Reinstate, target: "/licenses/0190a0e4-0000-7000-8000-000000000001/reinstate", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/revoke invalid id", method: http.MethodPost, pattern: "/licenses/:id/revoke", handler: revocationHandler.Revoke, target: "/licenses/not-a-uuid/revoke", body: `{}`, status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/revoke malformed body", method: http.MethodPost, pattern: "/licenses/:id/revoke", handler: revocationHandler.Revoke, target: "/licenses/0190a0e4-0000-7000-8000-000000000001/revoke", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/rotate-key invalid id", method: http.MethodPost, pattern: "/licenses/:id/rotate-key", handler: keyRotationHandler.RotateKey, target: "/licenses/not-a-uuid/rotate-key", body: `{}`, status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/rotate-key malformed body", method: http.MethodPost, pattern: "/licenses/:id/rotate-key", handler: keyRotationHandler.RotateKey, target: "/licenses/0190a0e4-0000-7000-8000-000000000001/rotate-key", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/clone invalid id", method: http.MethodPost, pattern: "/licenses/:id/clone", handler: licenseHandler.Clone, target: "/licenses/not-a-uuid/clone", body: `{}`, status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/clone malformed body", method: http.MethodPost, pattern: "/licenses/:id/clone", handler: licenseHandler.Clone, target: "/licenses/0190a0e4-0000-7000-8000-000000000001/clone", body: `{`, status: http.StatusBadRequest},
+		{name: "GET /licenses/:id/children invalid id", method: http.MethodGet, pattern: "/licenses/:id/children", handler: licenseHierarchyHandler.ListChildren, target: "/licenses/not-a-uuid/children", status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/children invalid id", method: http.MethodPost, pattern: "/licenses/:id/children", handler: licenseHierarchyHandler.CreateChild, target: "/licenses/not-a-uuid/children", body: `{}`, status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/children malformed body", method: http.MethodPost, pattern: "/licenses/:id/children", handler: licenseHierarchyHandler.CreateChild, target: "/licenses/0190a0e4-0000-7000-8000-000000000001/children", body: `{`, status: http.StatusBadRequest},
+		{name: "GET /licenses/:id/status-freeze invalid id", method: http.MethodGet, pattern: "/licenses/:id/status-freeze", handler: statusFreezeHandler.Get, target: "/licenses/not-a-uuid/status-freeze", status: http.StatusBadRequest},
+		{name: "DELETE /licenses/:id/status-freeze invalid id", method: http.MethodDelete, pattern: "/licenses/:id/status-freeze", handler: statusFreezeHandler.Unfreeze, target: "/licenses/not-a-uuid/status-freeze", status: http.StatusBadRequest},
+		{name: "GET /licenses/:id/expiry-notifications invalid id", method: http.MethodGet, pattern: "/licenses/:id/expiry-notifications", handler: expiryNoticeHandler.Get, target: "/licenses/not-a-uuid/expiry-notifications", status: http.StatusBadRequest},
+		{name: "PUT /licenses/:id/expiry-notifications invalid id", method: http.MethodPut, pattern: "/licenses/:id/expiry-notifications", handler: expiryNoticeHandler.Update, target: "/licenses/not-a-uuid/expiry-notifications", body: `{}`, status: http.StatusBadRequest},
+		{name: "PUT /licenses/:id/expiry-notifications malformed body", method: http.MethodPut, pattern: "/licenses/:id/expiry-notifications", handler: expiryNoticeHandler.Update, target: "/licenses/0190a0e4-0000-7000-8000-000000000001/expiry-notifications", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/renewal-offers invalid id", method: http.MethodPost, pattern: "/licenses/:id/renewal-offers", handler: renewalHandler.CreateOffer, target: "/licenses/not-a-uuid/renewal-offers", body: `{}`, status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/renewal-offers malformed body", method: http.MethodPost, pattern: "/licenses/:id/renewal-offers", handler: renewalHandler.CreateOffer, target: "/licenses/0190a0e4-0000-7000-8000-000000000001/renewal-offers", body: `{`, status: http.StatusBadRequest},
+		{name: "GET /licenses/:id/certificate invalid id", method: http.MethodGet, pattern: "/licenses/:id/certificate", handler: templateHandler.Certificate, target: "/licenses/not-a-uuid/certificate", status: http.StatusBadRequest},
+		{name: "GET /licenses/:id/license-file invalid id", method: http.MethodGet, pattern: "/licenses/:id/license-file", handler: licenseHandler.LicenseFile, target: "/licenses/not-a-uuid/license-file", status: http.StatusBadRequest},
+		{name: "GET /licenses/:id/activations invalid id", method: http.MethodGet, pattern: "/licenses/:id/activations", handler: activationHandler.List, target: "/licenses/not-a-uuid/activations", status: http.StatusBadRequest},
+		{name: "GET /licenses/:id/usage invalid id", method: http.MethodGet, pattern: "/licenses/:id/usage", handler: usageHandler.List, target: "/licenses/not-a-uuid/usage", status: http.StatusBadRequest},
+		{name: "GET /licenses/:id/audit invalid id", method: http.MethodGet, pattern: "/licenses/:id/audit", handler: auditHandler.ListForLicense, target: "/licenses/not-a-uuid/audit", status: http.StatusBadRequest},
+		{name: "GET /licenses/:id/notes invalid id", method: http.MethodGet, pattern: "/licenses/:id/notes", handler: noteHandler.ListForLicense, target: "/licenses/not-a-uuid/notes", status: http.StatusBadRequest},
+		{name: "GET /licenses/:id/comments invalid id", method: http.MethodGet, pattern: "/licenses/:id/comments", handler: commentHandler.List, target: "/licenses/not-a-uuid/comments", status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/comments invalid id", method: http.MethodPost, pattern: "/licenses/:id/comments", handler: commentHandler.Create, target: "/licenses/not-a-uuid/comments", body: `{}`, status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/comments malformed body", method: http.MethodPost, pattern: "/licenses/:id/comments", handler: commentHandler.Create, target: "/licenses/0190a0e4-0000-7000-8000-000000000001/comments", body: `{`, status: http.StatusBadRequest},
+		{name: "DELETE /licenses/:id/comments/:commentId invalid id", method: http.MethodDelete, pattern: "/licenses/:id/comments/:commentId", handler: commentHandler.Delete, target: "/licenses/not-a-uuid/comments/not-a-uuid", status: http.StatusBadRequest},
+		{name: "GET /licenses/:id/extension-requests invalid id", method: http.MethodGet, pattern: "/licenses/:id/extension-requests", handler: extensionHandler.ListForLicense, target: "/licenses/not-a-uuid/extension-requests", status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/extension-requests invalid id", method: http.MethodPost, pattern: "/licenses/:id/extension-requests", handler: extensionHandler.Create, target: "/licenses/not-a-uuid/extension-requests", body: `{}`, status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/extension-requests malformed body", method: http.MethodPost, pattern: "/licenses/:id/extension-requests", handler: extensionHandler.Create, target: "/licenses/0190a0e4-0000-7000-8000-000000000001/extension-requests", body: `{`, status: http.StatusBadRequest},
+		{name: "GET /licenses/:id/extension-requests/:requestId invalid id", method: http.MethodGet, pattern: "/licenses/:id/extension-requests/:requestId", handler: extensionHandler.Get, target: "/licenses/not-a-uuid/extension-requests/not-a-uuid", status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/extension-requests/:requestId/approve invalid id", method: http.MethodPost, pattern: "/licenses/:id/extension-requests/:requestId/approve", handler: extensionHandler.Approve, target: "/licenses/not-a-uuid/extension-requests/not-a-uuid/approve", body: `{}`, status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/extension-requests/:requestId/approve malformed body", method: http.MethodPost, pattern: "/licenses/:id/extension-requests/:requestId/approve", handler: extensionHandler.Approve, target: "/licenses/0190a0e4-0000-7000-8000-000000000001/extension-requests/0190a0e4-0000-7000-8000-000000000001/approve", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/extension-requests/:requestId/reject invalid id", method: http.MethodPost, pattern: "/licenses/:id/extension-requests/:requestId/reject", handler: extensionHandler.Reject, target: "/licenses/not-a-uuid/extension-requests/not-a-uuid/reject", body: `{}`, status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/extension-requests/:requestId/reject malformed body", method: http.MethodPost, pattern: "/licenses/:id/extension-requests/:requestId/reject", handler: extensionHandler.Reject, target: "/licenses/0190a0e4-0000-7000-8000-000000000001/extension-requests/0190a0e4-0000-7000-8000-000000000001/reject", body: `{`, status: http.StatusBadRequest},
+		{name: "GET /licenses/:id/entitlements invalid id", method: http.MethodGet, pattern: "/licenses/:id/entitlements", handler: entitlementHandler.List, target: "/licenses/not-a-uuid/entitlements", status: http.StatusBadRequest},
+		{name: "PUT /licenses/:id/entitlements/:name invalid id", method: http.MethodPut, pattern: "/licenses/:id/entitlements/:name", handler: entitlementHandler.Put, target: "/licenses/not-a-uuid/entitlements/x", body: `{}`, status: http.StatusBadRequest},
+		{name: "PUT /licenses/:id/entitlements/:name malformed body", method: http.MethodPut, pattern: "/licenses/:id/entitlements/:name", handler: entitlementHandler.Put, target: "/licenses/0190a0e4-0000-7000-8000-000000000001/entitlements/x", body: `{`, status: http.StatusBadRequest},
+		{name: "DELETE /licenses/:id/entitlements/:name invalid id", method: http.MethodDelete, pattern: "/licenses/:id/entitlements/:name", handler: entitlementHandler.Delete, target: "/licenses/not-a-uuid/entitlements/x", status: http.StatusBadRequest},
+		{name: "POST /licenses/:id/activations/reclaim invalid id", method: http.MethodPost, pattern: "/licenses/:id/activations/reclaim", handler: activationHandler.ReclaimStale, target: "/licenses/not-a-uuid/activations/reclaim", body: `{}`, status: http.StatusBadRequest},
+		{name: "DELETE /licenses/:id/activations/:activationId invalid id", method: http.MethodDelete, pattern: "/licenses/:id/activations/:activationId", handler: activationHandler.Revoke, target: "/licenses/not-a-uuid/activations/not-a-uuid", status: http.StatusBadRequest},
+		{name: "POST /renewals/accept malformed body", method: http.MethodPost, pattern: "/renewals/accept", handler: renewalHandler.Accept, target: "/renewals/accept", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /campaigns/unsubscribe malformed body", method: http.MethodPost, pattern: "/campaigns/unsubscribe", handler: campaignHandler.Unsubscribe, target: "/campaigns/unsubscribe", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /campaigns malformed body", method: http.MethodPost, pattern: "/campaigns", handler: campaignHandler.Create, target: "/campaigns", body: `{`, status: http.StatusBadRequest},
+		{name: "GET /campaigns/:id invalid id", method: http.MethodGet, pattern: "/campaigns/:id", handler: campaignHandler.Get, target: "/campaigns/not-a-uuid", status: http.StatusBadRequest},
+		{name: "GET /campaigns/:id/recipients invalid id", method: http.MethodGet, pattern: "/campaigns/:id/recipients", handler: campaignHandler.Recipients, target: "/campaigns/not-a-uuid/recipients", status: http.StatusBadRequest},
+		{name: "POST /campaigns/:id/cancel invalid id", method: http.MethodPost, pattern: "/campaigns/:id/cancel", handler: campaignHandler.Cancel, target: "/campaigns/not-a-uuid/cancel", body: `{}`, status: http.StatusBadRequest},
+		{name: "POST /dashboard/widgets malformed body", method: http.MethodPost, pattern: "/dashboard/widgets", handler: dashboardHandler.CreateWidget, target: "/dashboard/widgets", body: `{`, status: http.StatusBadRequest},
+		{name: "GET /dashboard/widgets/:id invalid id", method: http.MethodGet, pattern: "/dashboard/widgets/:id", handler: dashboardHandler.GetWidget, target: "/dashboard/widgets/not-a-uuid", status: http.StatusBadRequest},
+		{name: "PATCH /dashboard/widgets/:id invalid id", method: http.MethodPatch, pattern: "/dashboard/widgets/:id", handler: dashboardHandler.UpdateWidget, target: "/dashboard/widgets/not-a-uuid", body: `{}`, status: http.StatusBadRequest},
+		{name: "PATCH /dashboard/widgets/:id malformed body", method: http.MethodPatch, pattern: "/dashboard/widgets/:id", handler: dashboardHandler.UpdateWidget, target: "/dashboard/widgets/0190a0e4-0000-7000-8000-000000000001", body: `{`, status: http.StatusBadRequest},
+		{name: "DELETE /dashboard/widgets/:id invalid id", method: http.MethodDelete, pattern: "/dashboard/widgets/:id", handler: dashboardHandler.DeleteWidget, target: "/dashboard/widgets/not-a-uuid", status: http.StatusBadRequest},
+		{name: "POST /apikeys malformed body", method: http.MethodPost, pattern: "/apikeys", handler: apiKeyHandler.Create, target: "/apikeys", body: `{`, status: http.StatusBadRequest},
+		{name: "DELETE /apikeys/:id invalid id", method: http.MethodDelete, pattern: "/apikeys/:id", handler: apiKeyHandler.Revoke, target: "/apikeys/not-a-uuid", status: http.StatusBadRequest},
+		{name: "POST /webhooks malformed body", method: http.MethodPost, pattern: "/webhooks", handler: webhookHandler.Create, target: "/webhooks", body: `{`, status: http.StatusBadRequest},
+		{name: "GET /webhooks/:id invalid id", method: http.MethodGet, pattern: "/webhooks/:id", handler: webhookHandler.Get, target: "/webhooks/not-a-uuid", status: http.StatusBadRequest},
+		{name: "PATCH /webhooks/:id invalid id", method: http.MethodPatch, pattern: "/webhooks/:id", handler: webhookHandler.Update, target: "/webhooks/not-a-uuid", body: `{}`, status: http.StatusBadRequest},
+		{name: "PATCH /webhooks/:id malformed body", method: http.MethodPatch, pattern: "/webhooks/:id", handler: webhookHandler.Update, target: "/webhooks/0190a0e4-0000-7000-8000-000000000001", body: `{`, status: http.StatusBadRequest},
+		{name: "DELETE /webhooks/:id invalid id", method: http.MethodDelete, pattern: "/webhooks/:id", handler: webhookHandler.Delete, target: "/webhooks/not-a-uuid", status: http.StatusBadRequest},
+		{name: "GET /webhooks/:id/deliveries invalid id", method: http.MethodGet, pattern: "/webhooks/:id/deliveries", handler: webhookHandler.ListDeliveries, target: "/webhooks/not-a-uuid/deliveries", status: http.StatusBadRequest},
+		{name: "POST /customers malformed body", method: http.MethodPost, pattern: "/customers", handler: customerHandler.Create, target: "/customers", body: `{`, status: http.StatusBadRequest},
+		{name: "GET /customers/:id invalid id", method: http.MethodGet, pattern: "/customers/:id", handler: customerHandler.Get, target: "/customers/not-a-uuid", status: http.StatusBadRequest},
+		{name: "PATCH /customers/:id invalid id", method: http.MethodPatch, pattern: "/customers/:id", handler: customerHandler.Update, target: "/customers/not-a-uuid", body: `{}`, status: http.StatusBadRequest},
+		{name: "PATCH /customers/:id malformed body", method: http.MethodPatch, pattern: "/customers/:id", handler: customerHandler.Update, target: "/customers/0190a0e4-0000-7000-8000-000000000001", body: `{`, status: http.StatusBadRequest},
+		{name: "DELETE /customers/:id invalid id", method: http.MethodDelete, pattern: "/customers/:id", handler: customerHandler.Delete, target: "/customers/not-a-uuid", status: http.StatusBadRequest},
+		{name: "GET /customers/:id/licenses invalid id", method: http.MethodGet, pattern: "/customers/:id/licenses", handler: customerHandler.Licenses, target: "/customers/not-a-uuid/licenses", status: http.StatusBadRequest},
+		{name: "GET /customers/:id/notes invalid id", method: http.MethodGet, pattern: "/customers/:id/notes", handler: noteHandler.ListForCustomer, target: "/customers/not-a-uuid/notes", status: http.StatusBadRequest},
+		{name: "PUT /protected-keys/:key malformed body", method: http.MethodPut, pattern: "/protected-keys/:key", handler: approvalHandler.PutProtectedKey, target: "/protected-keys/x", body: `{`, status: http.StatusBadRequest},
+		{name: "GET /approvals/:id invalid id", method: http.MethodGet, pattern: "/approvals/:id", handler: approvalHandler.Get, target: "/approvals/not-a-uuid", status: http.StatusBadRequest},
+		{name: "POST /approvals/:id/approve invalid id", method: http.MethodPost, pattern: "/approvals/:id/approve", handler: approvalHandler.Approve, target: "/approvals/not-a-uuid/approve", body: `{}`, status: http.StatusBadRequest},
+		{name: "POST /approvals/:id/reject invalid id", method: http.MethodPost, pattern: "/approvals/:id/reject", handler: approvalHandler.Reject, target: "/approvals/not-a-uuid/reject", body: `{}`, status: http.StatusBadRequest},
+		{name: "POST /license-templates malformed body", method: http.MethodPost, pattern: "/license-templates", handler: licenseTemplateHandler.Create, target: "/license-templates", body: `{`, status: http.StatusBadRequest},
+		{name: "GET /license-templates/:id invalid id", method: http.MethodGet, pattern: "/license-templates/:id", handler: licenseTemplateHandler.Get, target: "/license-templates/not-a-uuid", status: http.StatusBadRequest},
+		{name: "PATCH /license-templates/:id invalid id", method: http.MethodPatch, pattern: "/license-templates/:id", handler: licenseTemplateHandler.Update, target: "/license-templates/not-a-uuid", body: `{}`, status: http.StatusBadRequest},
+		{name: "PATCH /license-templates/:id malformed body", method: http.MethodPatch, pattern: "/license-templates/:id", handler: licenseTemplateHandler.Update, target: "/license-templates/0190a0e4-0000-7000-8000-000000000001", body: `{`, status: http.StatusBadRequest},
+		{name: "DELETE /license-templates/:id invalid id", method: http.MethodDelete, pattern: "/license-templates/:id", handler: licenseTemplateHandler.Delete, target: "/license-templates/not-a-uuid", status: http.StatusBadRequest},
+		{name: "POST /license-statuses malformed body", method: http.MethodPost, pattern: "/license-statuses", handler: customStatusHandler.Create, target: "/license-statuses", body: `{`, status: http.StatusBadRequest},
+		{name: "PATCH /license-statuses/:name malformed body", method: http.MethodPatch, pattern: "/license-statuses/:name", handler: customStatusHandler.Update, target: "/license-statuses/x", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /products malformed body", method: http.MethodPost, pattern: "/products", handler: productHandler.Create, target: "/products", body: `{`, status: http.StatusBadRequest},
+		{name: "PATCH /products/:name malformed body", method: http.MethodPatch, pattern: "/products/:name", handler: productHandler.Update, target: "/products/x", body: `{`, status: http.StatusBadRequest},
+		{name: "PUT /products/:name/lifecycle malformed body", method: http.MethodPut, pattern: "/products/:name/lifecycle", handler: productHandler.SetLifecycle, target: "/products/x/lifecycle", body: `{`, status: http.StatusBadRequest},
+		{name: "PUT /products/:name/agent-policy malformed body", method: http.MethodPut, pattern: "/products/:name/agent-policy", handler: productHandler.SetAgentPolicy, target: "/products/x/agent-policy", body: `{`, status: http.StatusBadRequest},
+		{name: "PUT /products/:name/cache-policy malformed body", method: http.MethodPut, pattern: "/products/:name/cache-policy", handler: productHandler.SetCachePolicy, target: "/products/x/cache-policy", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /products/:name/migration-campaigns malformed body", method: http.MethodPost, pattern: "/products/:name/migration-campaigns", handler: productHandler.StartMigrationCampaign, target: "/products/x/migration-campaigns", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /products/:name/metadata-migrations malformed body", method: http.MethodPost, pattern: "/products/:name/metadata-migrations", handler: productHandler.MigrateMetadata, target: "/products/x/metadata-migrations", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /signing-keys/rotate malformed body", method: http.MethodPost, pattern: "/signing-keys/rotate", handler: signingKeyHandler.Rotate, target: "/signing-keys/rotate", body: `{`, status: http.StatusBadRequest},
+		{name: "GET /audit/:id/diff invalid id", method: http.MethodGet, pattern: "/audit/:id/diff", handler: auditHandler.Diff, target: "/audit/not-a-uuid/diff", status: http.StatusBadRequest},
+		{name: "POST /admin/analytics-exports malformed body", method: http.MethodPost, pattern: "/admin/analytics-exports", handler: analyticsExportHandler.Run, target: "/admin/analytics-exports", body: `{`, status: http.StatusBadRequest},
+		{name: "POST /admin/integrity-checks malformed body", method: http.MethodPost, pattern: "/admin/integrity-checks", handler: integrityHandler.Start, target: "/admin/integrity-checks", body: `{`, status: http.StatusBadRequest},
+		{name: "GET /admin/integrity-checks/:id invalid id", method: http.MethodGet, pattern: "/admin/integrity-checks/:id", handler: integrityHandler.Get, target: "/admin/integrity-checks/not-a-uuid", status: http.StatusBadRequest},
+		{name: "POST /auth/step-up malformed body", method: http.MethodPost, pattern: "/auth/step-up", handler: stepUpHandler.StepUp, target: "/auth/step-up", body: `{`, status: http.StatusBadRequest},
+	})
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type RevocationHandler struct {
+	service *service.RevocationService
+	logger  *zap.Logger
+}
+
+func NewRevocationHandler(service *service.RevocationService, logger *zap.Logger) *RevocationHandler {
+	return &RevocationHandler{
+		service: service,
+		logger:  logger.Named("RevocationHandler"),
+	}
+}
+
+func (h *RevocationHandler) Revoke(c *gin.Context) {
+	applyStatusChange(c, h.logger, h.service.Revoke)
+}
+
+// List serves the revocation list. The ETag is a hash of the body, so an
+// agent polling with If-None-Match gets 304 until the page changes.
+func (h *RevocationHandler) List(c *gin.Context) {
+	var req dto.ListRevocationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind query parameters for revocation list", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	resp, err := h.service.ListRevocations(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
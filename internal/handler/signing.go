@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/handler/middleware"
+	"github.com/makkenzo/license-service-api/internal/signing"
+	"go.uber.org/zap"
+)
+
+type SigningKeyHandler struct {
+	keyring *signing.Keyring
+	logger  *zap.Logger
+}
+
+func NewSigningKeyHandler(keyring *signing.Keyring, logger *zap.Logger) *SigningKeyHandler {
+	return &SigningKeyHandler{
+		keyring: keyring,
+		logger:  logger.Named("SigningKeyHandler"),
+	}
+}
+
+// JWKS publishes the active and retired public keys. Agents may cache it
+// for a few minutes; a kid they do not know means the cache is stale.
+func (h *SigningKeyHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keyring.JWKS())
+}
+
+func (h *SigningKeyHandler) List(c *gin.Context) {
+	keys, err := h.keyring.Keys(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	resp := make([]*dto.SigningKeyResponse, len(keys))
+	for i, k := range keys {
+		resp[i] = dto.NewSigningKeyResponse(k)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *SigningKeyHandler) Rotate(c *gin.Context) {
+	var req dto.RotateSigningKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate rotate signing key request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	key, err := h.keyring.Rotate(c.Request.Context(), req.KeyRef)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	rotatedBy := ""
+	if claims := middleware.GetUserClaims(c); claims != nil {
+		rotatedBy = claims.Subject
+	}
+	h.logger.Info("Signing key rotated", zap.String("kid", key.KID), zap.String("rotated_by", rotatedBy))
+
+	c.JSON(http.StatusCreated, dto.NewSigningKeyResponse(key))
+}
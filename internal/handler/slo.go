@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type SLOHandler struct {
+	service *service.SLOService
+	logger  *zap.Logger
+}
+
+func NewSLOHandler(service *service.SLOService, logger *zap.Logger) *SLOHandler {
+	return &SLOHandler{
+		service: service,
+		logger:  logger.Named("SLOHandler"),
+	}
+}
+
+func (h *SLOHandler) Report(c *gin.Context) {
+	report, err := h.service.Report(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type StatusFreezeHandler struct {
+	service *service.StatusFreezeService
+	logger  *zap.Logger
+}
+
+func NewStatusFreezeHandler(service *service.StatusFreezeService, logger *zap.Logger) *StatusFreezeHandler {
+	return &StatusFreezeHandler{
+		service: service,
+		logger:  logger.Named("StatusFreezeHandler"),
+	}
+}
+
+func (h *StatusFreezeHandler) Get(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid ID format for status freeze", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(fmt.Errorf("%w: invalid license id format", ierr.ErrValidation))
+		return
+	}
+
+	state, err := h.service.GetFreeze(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, state)
+}
+
+func (h *StatusFreezeHandler) Unfreeze(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid ID format for status unfreeze", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(fmt.Errorf("%w: invalid license id format", ierr.ErrValidation))
+		return
+	}
+
+	if err := h.service.Unfreeze(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.Info("License status freeze lifted via handler", zap.String("id", idStr))
+	c.Status(http.StatusNoContent)
+}
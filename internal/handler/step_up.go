@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/handler/middleware"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type StepUpHandler struct {
+	service *service.StepUpService
+	logger  *zap.Logger
+}
+
+func NewStepUpHandler(service *service.StepUpService, logger *zap.Logger) *StepUpHandler {
+	return &StepUpHandler{
+		service: service,
+		logger:  logger.Named("StepUpHandler"),
+	}
+}
+
+func (h *StepUpHandler) StepUp(c *gin.Context) {
+	var req dto.StepUpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate step-up request", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	claims := middleware.GetUserClaims(c)
+	if claims == nil {
+		_ = c.Error(ierr.ErrUnauthorized)
+		return
+	}
+
+	resp, err := h.service.StepUp(c.Request.Context(), claims.Subject, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *StepUpHandler) EnrollTOTP(c *gin.Context) {
+	claims := middleware.GetUserClaims(c)
+	if claims == nil {
+		_ = c.Error(ierr.ErrUnauthorized)
+		return
+	}
+
+	resp, err := h.service.EnrollTOTP(c.Request.Context(), claims)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
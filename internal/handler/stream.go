@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+)
+
+const (
+	// streamFlushEvery is how many records are buffered before they are
+	// sent to the client.
+	streamFlushEvery = 100
+	// streamChunkTimeout replaces the server write timeout for streamed
+	// responses: each flush gets this long, so a long export survives while
+	// a client that stops reading is still cut off.
+	streamChunkTimeout = 30 * time.Second
+)
+
+// ndjsonStream writes a response as newline-delimited JSON. Writes block
+// while the client is not reading, which in turn holds back the database
+// cursor feeding the stream.
+type ndjsonStream struct {
+	c       *gin.Context
+	buf     *bufio.Writer
+	enc     *json.Encoder
+	rc      *http.ResponseController
+	pending int
+	started bool
+}
+
+func newNDJSONStream(c *gin.Context) *ndjsonStream {
+	buf := bufio.NewWriter(c.Writer)
+	return &ndjsonStream{
+		c:   c,
+		buf: buf,
+		enc: json.NewEncoder(buf),
+		rc:  http.NewResponseController(c.Writer),
+	}
+}
+
+func (s *ndjsonStream) Write(v interface{}) error {
+	s.start()
+	if err := s.enc.Encode(v); err != nil {
+		return err
+	}
+	s.pending++
+	if s.pending >= streamFlushEvery {
+		return s.flush()
+	}
+	return nil
+}
+
+// Finish ends the stream after the producer stopped with err. Errors before
+// the first record are reported as a regular error response; later ones
+// as a final {"error": ...} line, since the status has already been sent.
+// Nothing is written when the client has gone away.
+func (s *ndjsonStream) Finish(err error) {
+	if err != nil && s.c.Request.Context().Err() != nil {
+		return
+	}
+	if err != nil && !s.started {
+		_ = s.c.Error(err)
+		return
+	}
+	if err != nil {
+		_, code, message := ierr.Describe(err)
+		_ = s.enc.Encode(gin.H{"error": gin.H{"code": code, "message": message}})
+	}
+	s.start()
+	_ = s.flush()
+}
+
+func (s *ndjsonStream) start() {
+	if s.started {
+		return
+	}
+	s.started = true
+	s.c.Header("Content-Type", "application/x-ndjson")
+	s.c.Header("X-Content-Type-Options", "nosniff")
+	s.c.Status(http.StatusOK)
+}
+
+func (s *ndjsonStream) flush() error {
+	// Not every writer supports deadlines; the server timeout applies then.
+	_ = s.rc.SetWriteDeadline(time.Now().Add(streamChunkTimeout))
+	if err := s.buf.Flush(); err != nil {
+		return err
+	}
+	s.pending = 0
+	return s.rc.Flush()
+}
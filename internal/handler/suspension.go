@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/statushistory"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/handler/middleware"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type SuspensionHandler struct {
+	service *service.SuspensionService
+	logger  *zap.Logger
+}
+
+func NewSuspensionHandler(service *service.SuspensionService, logger *zap.Logger) *SuspensionHandler {
+	return &SuspensionHandler{
+		service: service,
+		logger:  logger.Named("SuspensionHandler"),
+	}
+}
+
+func (h *SuspensionHandler) Suspend(c *gin.Context) {
+	applyStatusChange(c, h.logger, h.service.Suspend)
+}
+
+func (h *SuspensionHandler) Reinstate(c *gin.Context) {
+	applyStatusChange(c, h.logger, h.service.Reinstate)
+}
+
+// applyStatusChange serves the operator status changes that record a
+// reason: suspend, reinstate and revoke.
+func applyStatusChange(c *gin.Context, logger *zap.Logger, apply func(ctx context.Context, id uuid.UUID, reason, changedBy string) (*statushistory.Entry, error)) {
+	idStr := c.Param("id")
+	id, err := idgen.Parse(idStr)
+	if err != nil {
+		logger.Warn("Invalid ID format for status change", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	// The body is optional for reinstate, which needs no reason.
+	var req dto.StatusChangeRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Warn("Failed to bind or validate status change request", zap.String("id", idStr), zap.Error(err))
+			_ = c.Error(err)
+			return
+		}
+	}
+
+	changedBy := ""
+	if claims := middleware.GetUserClaims(c); claims != nil {
+		changedBy = claims.Subject
+	}
+
+	entry, err := apply(c.Request.Context(), id, req.Reason, changedBy)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewStatusChangeResponse(entry))
+}
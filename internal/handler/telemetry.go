@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type TelemetryHandler struct {
+	service *service.TelemetryService
+	logger  *zap.Logger
+}
+
+func NewTelemetryHandler(service *service.TelemetryService, logger *zap.Logger) *TelemetryHandler {
+	return &TelemetryHandler{
+		service: service,
+		logger:  logger.Named("TelemetryHandler"),
+	}
+}
+
+// Preview returns the usage ping payload exactly as it would be sent, whether
+// or not telemetry is enabled.
+func (h *TelemetryHandler) Preview(c *gin.Context) {
+	preview, err := h.service.Preview(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Service failed to build telemetry preview", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
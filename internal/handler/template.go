@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type TemplateHandler struct {
+	service *service.TemplateService
+	logger  *zap.Logger
+}
+
+func NewTemplateHandler(service *service.TemplateService, logger *zap.Logger) *TemplateHandler {
+	return &TemplateHandler{
+		service: service,
+		logger:  logger.Named("TemplateHandler"),
+	}
+}
+
+func (h *TemplateHandler) Catalog(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.Catalog())
+}
+
+func (h *TemplateHandler) Certificate(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid ID format for certificate", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(fmt.Errorf("%w: invalid license id format", ierr.ErrValidation))
+		return
+	}
+
+	rendered, err := h.service.Certificate(c.Request.Context(), id, c.Query("locale"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Content-Language", rendered.Locale)
+	c.String(http.StatusOK, rendered.Body)
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type UsageHandler struct {
+	service *service.UsageService
+	logger  *zap.Logger
+}
+
+func NewUsageHandler(service *service.UsageService, logger *zap.Logger) *UsageHandler {
+	return &UsageHandler{
+		service: service,
+		logger:  logger.Named("UsageHandler"),
+	}
+}
+
+func (h *UsageHandler) Report(c *gin.Context) {
+	var req dto.UsageReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate usage report", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	resp, err := h.service.Report(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *UsageHandler) List(c *gin.Context) {
+	idStr := c.Param("id")
+	licenseID, err := idgen.Parse(idStr)
+	if err != nil {
+		h.logger.Warn("Invalid license ID for usage list", zap.String("id_param", idStr), zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.ListUsageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind usage list query", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	counters, err := h.service.ListUsage(c.Request.Context(), licenseID, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	period := req.Period
+	if period == "" {
+		period = "month"
+	}
+	c.JSON(http.StatusOK, &dto.UsageListResponse{LicenseID: licenseID, Period: period, Usage: counters})
+}
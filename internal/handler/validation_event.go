@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/service"
+	"go.uber.org/zap"
+)
+
+type ValidationEventHandler struct {
+	service *service.ValidationEventService
+	logger  *zap.Logger
+}
+
+func NewValidationEventHandler(service *service.ValidationEventService, logger *zap.Logger) *ValidationEventHandler {
+	return &ValidationEventHandler{
+		service: service,
+		logger:  logger.Named("ValidationEventHandler"),
+	}
+}
+
+func (h *ValidationEventHandler) List(c *gin.Context) {
+	var req dto.ListValidationEventsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Failed to bind or validate query parameters", zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	events, total, err := h.service.ListEvents(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PaginatedValidationEventResponse{
+		Events:     events,
+		TotalCount: total,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	})
+}
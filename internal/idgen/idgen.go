@@ -0,0 +1,203 @@
+// Package idgen generates primary keys for new records. IDs are always
+// stored in UUID columns; the ULID strategy puts a millisecond timestamp in
+// the leading 48 bits so new rows sort by creation time.
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+)
+
+const (
+	StrategyUUID = "uuid"
+	StrategyULID = "ulid"
+)
+
+type Generator interface {
+	New() uuid.UUID
+}
+
+// NewGenerator returns the generator for strategy. An empty strategy means
+// random UUIDv4, matching the column defaults in the migrations.
+func NewGenerator(strategy string) (Generator, error) {
+	switch strings.ToLower(strategy) {
+	case "", StrategyUUID:
+		return UUIDGenerator{}, nil
+	case StrategyULID:
+		return NewULIDGenerator(), nil
+	default:
+		return nil, fmt.Errorf("unknown id strategy %q, expected %s or %s", strategy, StrategyUUID, StrategyULID)
+	}
+}
+
+type UUIDGenerator struct{}
+
+func (UUIDGenerator) New() uuid.UUID {
+	return uuid.New()
+}
+
+// ULIDGenerator is monotonic: IDs generated in the same millisecond increment
+// the random part of the previous one instead of drawing fresh randomness, so
+// they still sort in generation order.
+type ULIDGenerator struct {
+	mu   sync.Mutex
+	now  func() time.Time
+	last uuid.UUID
+	ms   uint64
+}
+
+func NewULIDGenerator() *ULIDGenerator {
+	return &ULIDGenerator{now: time.Now}
+}
+
+func (g *ULIDGenerator) New() uuid.UUID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(g.now().UnixMilli())
+	if ms <= g.ms {
+		// Same millisecond, or the clock went backwards: never emit an ID
+		// that sorts before the previous one.
+		if incrementRandom(&g.last) {
+			return g.last
+		}
+		ms = g.ms + 1
+	}
+
+	var id uuid.UUID
+	putTimestamp(&id, ms)
+	if _, err := rand.Read(id[6:]); err != nil {
+		panic(fmt.Sprintf("idgen: reading random bytes: %v", err))
+	}
+	g.ms, g.last = ms, id
+	return id
+}
+
+func putTimestamp(id *uuid.UUID, ms uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], ms)
+	copy(id[:6], buf[2:])
+}
+
+// incrementRandom adds one to the 80-bit random part, reporting false on
+// overflow.
+func incrementRandom(id *uuid.UUID) bool {
+	for i := len(id) - 1; i >= 6; i-- {
+		id[i]++
+		if id[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Time returns the creation time encoded in a ULID-strategy ID. For random
+// UUIDs the result is meaningless.
+func Time(id uuid.UUID) time.Time {
+	var buf [8]byte
+	copy(buf[2:], id[:6])
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(buf[:]))).UTC()
+}
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var ErrInvalidID = fmt.Errorf("%w: invalid id format", ierr.ErrValidation)
+
+// Parse accepts both the canonical UUID form and the 26-character ULID form
+// of an ID.
+func Parse(s string) (uuid.UUID, error) {
+	if len(s) == 26 {
+		return parseULID(s)
+	}
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+	return id, nil
+}
+
+// ID is a uuid.UUID for request DTOs: it decodes from either form and
+// encodes as a canonical UUID.
+type ID uuid.UUID
+
+func (id ID) UUID() uuid.UUID {
+	return uuid.UUID(id)
+}
+
+func (id ID) MarshalText() ([]byte, error) {
+	return uuid.UUID(id).MarshalText()
+}
+
+func (id *ID) UnmarshalText(data []byte) error {
+	parsed, err := Parse(string(data))
+	if err != nil {
+		return err
+	}
+	*id = ID(parsed)
+	return nil
+}
+
+// ULIDString renders id in the 26-character Crockford base32 ULID form.
+func ULIDString(id uuid.UUID) string {
+	var out [26]byte
+	// 128 bits in 26 five-bit groups: the first character carries only the
+	// top 3 bits.
+	var acc uint64
+	bits := 2
+	pos := 0
+	for _, b := range id {
+		acc = acc<<8 | uint64(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out[pos] = crockford[(acc>>uint(bits))&0x1f]
+			pos++
+		}
+	}
+	return string(out[:])
+}
+
+func parseULID(s string) (uuid.UUID, error) {
+	var id uuid.UUID
+	if v := decodeCrockford(s[0]); v < 0 || v > 7 {
+		return uuid.Nil, fmt.Errorf("%w: ulid out of range", ErrInvalidID)
+	}
+
+	var acc uint64
+	bits := -2
+	pos := 0
+	for i := 0; i < len(s); i++ {
+		v := decodeCrockford(s[i])
+		if v < 0 {
+			return uuid.Nil, fmt.Errorf("%w: invalid ulid character %q", ErrInvalidID, s[i])
+		}
+		acc = acc<<5 | uint64(v)
+		bits += 5
+		if bits >= 8 {
+			bits -= 8
+			id[pos] = byte(acc >> uint(bits))
+			pos++
+		}
+	}
+	return id, nil
+}
+
+func decodeCrockford(c byte) int {
+	if c >= 'a' && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	switch c {
+	case 'O':
+		c = '0'
+	case 'I', 'L':
+		c = '1'
+	}
+	return strings.IndexByte(crockford, c)
+}
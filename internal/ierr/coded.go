@@ -0,0 +1,131 @@
+package ierr
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Coded is implemented by errors that know how they should reach a client.
+// The error middleware finds the outermost Coded error in a chain and
+// answers with its status and code, so a new error case only needs to be
+// declared as a Coded error to be reported correctly.
+type Coded interface {
+	error
+	Code() string
+	HTTPStatus() int
+	PublicMessage() string
+}
+
+// Error is the Coded implementation used for the package sentinels and for
+// service specific errors. Wrap it with fmt.Errorf("%w: ...") to add context.
+type Error struct {
+	code    string
+	status  int
+	message string
+	public  string
+	details bool
+	parent  error
+}
+
+var _ Coded = (*Error)(nil)
+
+func New(code string, status int, message string) *Error {
+	return &Error{code: code, status: status, message: message}
+}
+
+// Derive returns a more specific error with its own code and message that
+// still matches e with errors.Is and inherits its status and visibility.
+func (e *Error) Derive(code, message string) *Error {
+	return &Error{
+		code:    code,
+		status:  e.status,
+		message: message,
+		public:  e.public,
+		details: e.details,
+		parent:  e,
+	}
+}
+
+// WithPublicMessage sets the fixed message clients see instead of the
+// internal one.
+func (e *Error) WithPublicMessage(message string) *Error {
+	e.public = message
+	return e
+}
+
+// WithDetails makes clients see the full wrapped error text, including any
+// context added with fmt.Errorf. Meant for client errors only, whose details
+// are safe and useful to show.
+func (e *Error) WithDetails() *Error {
+	e.details = true
+	return e
+}
+
+func (e *Error) Error() string {
+	if e.parent != nil {
+		return e.parent.Error() + ": " + e.message
+	}
+	return e.message
+}
+
+func (e *Error) Unwrap() error   { return e.parent }
+func (e *Error) Code() string    { return e.code }
+func (e *Error) HTTPStatus() int { return e.status }
+
+func (e *Error) PublicMessage() string {
+	if e.public != "" {
+		return e.public
+	}
+	if e.status >= http.StatusInternalServerError {
+		return "An unexpected error occurred."
+	}
+	return e.Error()
+}
+
+// Describe resolves what a client should see for err. Errors without a Coded
+// error in their chain are reported as internal errors.
+func Describe(err error) (status int, code, message string) {
+	var coded Coded
+	if !errors.As(err, &coded) {
+		return ErrInternalServer.HTTPStatus(), ErrInternalServer.Code(), ErrInternalServer.PublicMessage()
+	}
+
+	message = coded.PublicMessage()
+	var e *Error
+	if errors.As(err, &e) && e.details {
+		message = err.Error()
+	}
+	return coded.HTTPStatus(), coded.Code(), message
+}
+
+// FieldError points at one invalid field of a request.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// FieldErrors is a client error that lists the invalid fields of a request.
+// It is reported with the code and status of its base error, and the error
+// middleware returns the fields as details.
+type FieldErrors struct {
+	base   *Error
+	Fields []FieldError
+}
+
+func NewFieldErrors(base *Error, fields []FieldError) *FieldErrors {
+	return &FieldErrors{base: base, Fields: fields}
+}
+
+func (e *FieldErrors) Error() string {
+	msg := e.base.Error()
+	for i, f := range e.Fields {
+		sep := ", "
+		if i == 0 {
+			sep = ": "
+		}
+		msg += sep + f.Field + ": " + f.Message
+	}
+	return msg
+}
+
+func (e *FieldErrors) Unwrap() error { return e.base }
@@ -1,23 +1,29 @@
 package ierr
 
-import "errors"
+import (
+	"fmt"
+	"net/http"
+)
 
 var (
-	ErrValidation     = errors.New("validation failed")
-	ErrUnauthorized   = errors.New("unauthorized")
-	ErrForbidden      = errors.New("forbidden")
-	ErrUpdateFailed   = errors.New("resource update failed")
-	ErrNotFound       = errors.New("resource not found")
-	ErrConflict       = errors.New("resource conflict")
-	ErrInternalServer = errors.New("internal server error")
+	ErrValidation      = New("VALIDATION_ERROR", http.StatusBadRequest, "validation failed").WithDetails()
+	ErrUnauthorized    = New("UNAUTHENTICATED", http.StatusUnauthorized, "unauthorized").WithPublicMessage("Authentication required or failed.")
+	ErrForbidden       = New("FORBIDDEN", http.StatusForbidden, "forbidden").WithPublicMessage("Access denied.")
+	ErrUpdateFailed    = New("INTERNAL_ERROR", http.StatusInternalServerError, "resource update failed")
+	ErrNotFound        = New("NOT_FOUND", http.StatusNotFound, "resource not found").WithPublicMessage("The requested resource was not found.")
+	ErrConflict        = New("CONFLICT", http.StatusConflict, "resource conflict").WithDetails()
+	ErrDuplicateKey    = New("CONFLICT", http.StatusConflict, "resource already exists").WithDetails()
+	ErrPayloadTooLarge = New("PAYLOAD_TOO_LARGE", http.StatusRequestEntityTooLarge, "payload too large").WithDetails()
+	ErrInternalServer  = New("INTERNAL_ERROR", http.StatusInternalServerError, "internal server error")
 
-	ErrUserNotFound       = errors.New("user not found")
-	ErrInvalidCredentials = errors.New("invalid username or password")
-	ErrInvalidToken       = errors.New("invalid or expired token")
-	ErrTokenParsingFailed = errors.New("failed to parse token")
-	ErrTokenNoClaims      = errors.New("token contains no claims")
-	ErrTokenInvalidClaims = errors.New("token contains invalid claims type")
-	ErrAPIKeyNotFound     = errors.New("api key not found or disabled")
+	ErrUserNotFound       = ErrNotFound.Derive("NOT_FOUND", "user not found")
+	ErrInvalidCredentials = ErrUnauthorized.Derive("UNAUTHENTICATED", "invalid username or password")
+	ErrInvalidToken       = ErrUnauthorized.Derive("UNAUTHENTICATED", "invalid or expired token")
+	ErrTokenParsingFailed = ErrUnauthorized.Derive("UNAUTHENTICATED", "failed to parse token")
+	ErrTokenNoClaims      = ErrUnauthorized.Derive("UNAUTHENTICATED", "token contains no claims")
+	ErrTokenInvalidClaims = ErrUnauthorized.Derive("UNAUTHENTICATED", "token contains invalid claims type")
+	ErrAPIKeyNotFound     = fmt.Errorf("%w: api key not found or disabled", ErrNotFound)
+	ErrStatusFrozen       = ErrConflict.Derive("STATUS_FROZEN", "status transitions are frozen for this license because its status changed too often")
 
-	ErrAPIKeyUpdateFailed = errors.New("api key update failed")
+	ErrAPIKeyUpdateFailed = New("INTERNAL_ERROR", http.StatusInternalServerError, "api key update failed")
 )
@@ -0,0 +1,115 @@
+// Package inbound turns e-mail received through a provider webhook into a
+// provider-neutral Email, after checking the provider signed the request.
+package inbound
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+)
+
+type Email struct {
+	// MessageID is the Message-ID header, used to ignore redeliveries.
+	MessageID  string
+	From       string
+	Subject    string
+	Recipients []string
+	// Text is the plain text body without the quoted message it replies to.
+	Text string
+}
+
+// StripQuoted cuts a reply down to what the sender wrote: everything from
+// the attribution line ("On ... wrote:"), the first quoted line or the
+// signature delimiter on is dropped.
+func StripQuoted(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	end := len(lines)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, ">") || line == "-- " || strings.HasPrefix(trimmed, "-----Original Message-----") {
+			end = i
+			break
+		}
+		if strings.HasSuffix(trimmed, "wrote:") {
+			end = i
+			// Clients wrap long attribution lines.
+			if i > 0 && strings.HasPrefix(strings.TrimSpace(lines[i-1]), "On ") && !strings.HasPrefix(trimmed, "On ") {
+				end = i - 1
+			}
+			break
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines[:end], "\n"))
+}
+
+// parseMIME reads a raw RFC 5322 message and keeps its first text/plain part.
+func parseMIME(raw []byte) (*Email, error) {
+	msg, err := mail.ReadMessage(bufio.NewReader(bytes.NewReader(raw)))
+	if err != nil {
+		return nil, fmt.Errorf("malformed e-mail: %w", err)
+	}
+	dec := new(mime.WordDecoder)
+	subject, err := dec.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+	text, err := plainText(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &Email{
+		MessageID: msg.Header.Get("Message-Id"),
+		From:      msg.Header.Get("From"),
+		Subject:   subject,
+		Text:      StripQuoted(text),
+	}, nil
+}
+
+func plainText(contentType, transferEncoding string, body io.Reader) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		parts := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := parts.NextPart()
+			if err == io.EOF {
+				return "", nil
+			}
+			if err != nil {
+				return "", fmt.Errorf("malformed multipart e-mail: %w", err)
+			}
+			text, err := plainText(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if err != nil {
+				return "", err
+			}
+			if text != "" {
+				return text, nil
+			}
+		}
+	}
+	if mediaType != "text/plain" {
+		return "", nil
+	}
+
+	switch strings.ToLower(transferEncoding) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+	text, err := io.ReadAll(body)
+	if err != nil {
+		return "", fmt.Errorf("malformed e-mail body: %w", err)
+	}
+	return string(text), nil
+}
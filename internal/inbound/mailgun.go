@@ -0,0 +1,64 @@
+package inbound
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+)
+
+var ErrBadSignature = errors.New("bad inbound e-mail signature")
+
+// signatureTolerance bounds how old a signed webhook may be, which limits
+// replays of captured requests.
+const signatureTolerance = 5 * time.Minute
+
+// VerifyMailgun checks the signature Mailgun adds to forwarded messages:
+// the hex HMAC-SHA256 of timestamp and token under the webhook signing key.
+func VerifyMailgun(crypto cryptoprovider.Provider, signingKey string, form url.Values, now time.Time) error {
+	ts, token, sig := form.Get("timestamp"), form.Get("token"), form.Get("signature")
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || token == "" || sig == "" {
+		return fmt.Errorf("%w: missing timestamp, token or signature", ErrBadSignature)
+	}
+	if d := now.Sub(time.Unix(unix, 0)); d > signatureTolerance || d < -signatureTolerance {
+		return fmt.Errorf("%w: timestamp outside tolerance", ErrBadSignature)
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, crypto.MAC([]byte(signingKey), []byte(ts+token))) {
+		return fmt.Errorf("%w: mismatch", ErrBadSignature)
+	}
+	return nil
+}
+
+// MailgunEmail reads a message forwarded by a Mailgun route. Mailgun already
+// removes the quoted reply into stripped-text; body-plain is the fallback.
+func MailgunEmail(form url.Values) *Email {
+	text := form.Get("stripped-text")
+	if text == "" {
+		text = StripQuoted(form.Get("body-plain"))
+	}
+	from := form.Get("from")
+	if from == "" {
+		from = form.Get("sender")
+	}
+	var recipients []string
+	for _, r := range strings.Split(form.Get("recipient"), ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			recipients = append(recipients, r)
+		}
+	}
+	return &Email{
+		MessageID:  form.Get("Message-Id"),
+		From:       from,
+		Subject:    form.Get("subject"),
+		Recipients: recipients,
+		Text:       strings.TrimSpace(text),
+	}
+}
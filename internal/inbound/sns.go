@@ -0,0 +1,252 @@
+package inbound
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	SNSTypeNotification             = "Notification"
+	SNSTypeSubscriptionConfirmation = "SubscriptionConfirmation"
+	SNSTypeUnsubscribeConfirmation  = "UnsubscribeConfirmation"
+)
+
+// SNSMessage is what Amazon SNS posts to an HTTPS subscription.
+type SNSMessage struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	Token            string `json:"Token"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+	SubscribeURL     string `json:"SubscribeURL"`
+}
+
+// snsHost only lets SNS endpoints serve signing certificates and
+// subscription links, so a forged message cannot point the verifier at a
+// certificate of its own or make the service fetch arbitrary URLs.
+var snsHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+func checkSNSURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || !snsHost.MatchString(u.Hostname()) {
+		return nil, fmt.Errorf("%w: %q is not an SNS url", ErrBadSignature, raw)
+	}
+	return u, nil
+}
+
+// SNSVerifier checks SNS message signatures. Signing certificates are
+// fetched once per URL and kept.
+type SNSVerifier struct {
+	client *http.Client
+
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+func NewSNSVerifier(client *http.Client) *SNSVerifier {
+	return &SNSVerifier{client: client, certs: map[string]*x509.Certificate{}}
+}
+
+func (v *SNSVerifier) Verify(ctx context.Context, m *SNSMessage, now time.Time) error {
+	var hash crypto.Hash
+	switch m.SignatureVersion {
+	case "1":
+		hash = crypto.SHA1
+	case "2":
+		hash = crypto.SHA256
+	default:
+		return fmt.Errorf("%w: unsupported signature version %q", ErrBadSignature, m.SignatureVersion)
+	}
+	sig, err := base64.StdEncoding.DecodeString(m.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrBadSignature)
+	}
+	cert, err := v.cert(ctx, m.SigningCertURL)
+	if err != nil {
+		return err
+	}
+	if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return fmt.Errorf("%w: signing certificate is not valid now", ErrBadSignature)
+	}
+	pub, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("%w: signing certificate has no RSA key", ErrBadSignature)
+	}
+
+	var digest []byte
+	if hash == crypto.SHA1 {
+		sum := sha1.Sum(m.stringToSign())
+		digest = sum[:]
+	} else {
+		sum := sha256.Sum256(m.stringToSign())
+		digest = sum[:]
+	}
+	if err := rsa.VerifyPKCS1v15(pub, hash, digest, sig); err != nil {
+		return fmt.Errorf("%w: mismatch", ErrBadSignature)
+	}
+	return nil
+}
+
+// stringToSign is the canonical form SNS signs: selected fields as
+// "name\nvalue\n" pairs in a fixed order, which differs by message type.
+func (m *SNSMessage) stringToSign() []byte {
+	var fields [][2]string
+	if m.Type == SNSTypeNotification {
+		fields = [][2]string{{"Message", m.Message}, {"MessageId", m.MessageID}}
+		if m.Subject != "" {
+			fields = append(fields, [2]string{"Subject", m.Subject})
+		}
+		fields = append(fields, [][2]string{{"Timestamp", m.Timestamp}, {"TopicArn", m.TopicArn}, {"Type", m.Type}}...)
+	} else {
+		fields = [][2]string{
+			{"Message", m.Message}, {"MessageId", m.MessageID}, {"SubscribeURL", m.SubscribeURL},
+			{"Timestamp", m.Timestamp}, {"Token", m.Token}, {"TopicArn", m.TopicArn}, {"Type", m.Type},
+		}
+	}
+	var b strings.Builder
+	for _, f := range fields {
+		b.WriteString(f[0] + "\n" + f[1] + "\n")
+	}
+	return []byte(b.String())
+}
+
+func (v *SNSVerifier) cert(ctx context.Context, rawURL string) (*x509.Certificate, error) {
+	v.mu.Lock()
+	cert, ok := v.certs[rawURL]
+	v.mu.Unlock()
+	if ok {
+		return cert, nil
+	}
+
+	u, err := checkSNSURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	body, err := v.get(ctx, u, 64<<10)
+	if err != nil {
+		return nil, fmt.Errorf("fetching SNS signing certificate: %w", err)
+	}
+	block, _ := pem.Decode(body)
+	if block == nil {
+		return nil, fmt.Errorf("%w: signing certificate is not PEM", ErrBadSignature)
+	}
+	cert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signing certificate: %v", ErrBadSignature, err)
+	}
+
+	v.mu.Lock()
+	v.certs[rawURL] = cert
+	v.mu.Unlock()
+	return cert, nil
+}
+
+// Confirm visits the SubscribeURL of a verified subscription confirmation,
+// which makes SNS start delivering the topic.
+func (v *SNSVerifier) Confirm(ctx context.Context, m *SNSMessage) error {
+	u, err := checkSNSURL(m.SubscribeURL)
+	if err != nil {
+		return err
+	}
+	if _, err := v.get(ctx, u, 64<<10); err != nil {
+		return fmt.Errorf("confirming SNS subscription: %w", err)
+	}
+	return nil
+}
+
+func (v *SNSVerifier) get(ctx context.Context, u *url.URL, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, limit))
+}
+
+// sesNotification is the SNS message body of an SES receipt rule with an
+// SNS action.
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	Mail             struct {
+		MessageID     string `json:"messageId"`
+		CommonHeaders struct {
+			From      []string `json:"from"`
+			Subject   string   `json:"subject"`
+			MessageID string   `json:"messageId"`
+		} `json:"commonHeaders"`
+	} `json:"mail"`
+	Receipt struct {
+		Recipients []string `json:"recipients"`
+		Action     struct {
+			Encoding string `json:"encoding"`
+		} `json:"action"`
+	} `json:"receipt"`
+	Content string `json:"content"`
+}
+
+// SESEmail reads the e-mail out of an SES "Received" notification. The
+// receipt rule has to include the message content, which SES does for
+// messages up to 150 KB.
+func SESEmail(m *SNSMessage) (*Email, error) {
+	var n sesNotification
+	if err := json.Unmarshal([]byte(m.Message), &n); err != nil {
+		return nil, fmt.Errorf("malformed SES notification: %w", err)
+	}
+	if n.NotificationType != "Received" {
+		return nil, fmt.Errorf("unexpected SES notification type %q", n.NotificationType)
+	}
+	if n.Content == "" {
+		return nil, fmt.Errorf("SES notification carries no message content")
+	}
+
+	raw := []byte(n.Content)
+	if strings.EqualFold(n.Receipt.Action.Encoding, "BASE64") {
+		var err error
+		if raw, err = base64.StdEncoding.DecodeString(n.Content); err != nil {
+			return nil, fmt.Errorf("malformed SES message content: %w", err)
+		}
+	}
+	email, err := parseMIME(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	email.Recipients = n.Receipt.Recipients
+	if email.MessageID == "" {
+		email.MessageID = n.Mail.CommonHeaders.MessageID
+	}
+	if email.MessageID == "" {
+		email.MessageID = n.Mail.MessageID
+	}
+	if email.From == "" && len(n.Mail.CommonHeaders.From) > 0 {
+		email.From = n.Mail.CommonHeaders.From[0]
+	}
+	return email, nil
+}
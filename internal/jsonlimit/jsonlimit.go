@@ -0,0 +1,123 @@
+// Package jsonlimit rejects JSON documents that are valid but pathological:
+// deeply nested, carrying absurdly long numbers or strings, or containing
+// invalid UTF-8 that encoding/json would otherwise silently replace.
+package jsonlimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/makkenzo/license-service-api/internal/ierr"
+)
+
+var (
+	ErrMalformed      = fmt.Errorf("%w: malformed JSON", ierr.ErrValidation)
+	ErrInvalidUTF8    = fmt.Errorf("%w: JSON contains invalid UTF-8", ierr.ErrValidation)
+	ErrTooDeep        = fmt.Errorf("%w: JSON nesting too deep", ierr.ErrValidation)
+	ErrNumberTooLong  = fmt.Errorf("%w: JSON number too long", ierr.ErrValidation)
+	ErrStringTooLong  = fmt.Errorf("%w: JSON string too long", ierr.ErrValidation)
+	ErrTooManyValues  = fmt.Errorf("%w: JSON document has too many values", ierr.ErrValidation)
+	ErrDocumentTooBig = fmt.Errorf("%w: JSON document too large", ierr.ErrPayloadTooLarge)
+)
+
+type Limits struct {
+	MaxBytes        int
+	MaxDepth        int
+	MaxNumberLength int
+	MaxStringLength int
+	MaxValues       int
+}
+
+var Default = Limits{
+	MaxBytes:        64 << 10,
+	MaxDepth:        16,
+	MaxNumberLength: 32,
+	MaxStringLength: 4096,
+	MaxValues:       2048,
+}
+
+// Check walks data token by token and returns one of the package errors on
+// the first limit it hits. Empty input is accepted.
+func Check(data []byte, limits Limits) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if limits.MaxBytes > 0 && len(data) > limits.MaxBytes {
+		return ErrDocumentTooBig
+	}
+	if !utf8.Valid(data) {
+		return ErrInvalidUTF8
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	depth, values, roots := 0, 0, 0
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		if depth == 0 {
+			roots++
+			if roots > 1 {
+				return ErrMalformed
+			}
+		}
+
+		switch v := tok.(type) {
+		case json.Delim:
+			switch v {
+			case '{', '[':
+				depth++
+				if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+					return ErrTooDeep
+				}
+			default:
+				depth--
+				continue
+			}
+		case json.Number:
+			if limits.MaxNumberLength > 0 && len(v) > limits.MaxNumberLength {
+				return ErrNumberTooLong
+			}
+		case string:
+			if limits.MaxStringLength > 0 && len(v) > limits.MaxStringLength {
+				return ErrStringTooLong
+			}
+		}
+
+		values++
+		if limits.MaxValues > 0 && values > limits.MaxValues {
+			return ErrTooManyValues
+		}
+	}
+
+	if depth != 0 || roots == 0 {
+		return ErrMalformed
+	}
+	return nil
+}
+
+// DecodeObject decodes a JSON object keeping numbers as json.Number so that
+// values round-trip without float64 precision loss.
+func DecodeObject(data []byte) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var obj map[string]interface{}
+	if err := dec.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	if dec.More() {
+		return nil, ErrMalformed
+	}
+	return obj, nil
+}
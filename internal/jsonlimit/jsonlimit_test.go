@@ -0,0 +1,114 @@
+package jsonlimit_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/makkenzo/license-service-api/internal/handler"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/jsonlimit"
+)
+
+// tight makes the fuzzer reach every limit with short inputs.
+var tight = jsonlimit.Limits{
+	MaxBytes:        512,
+	MaxDepth:        4,
+	MaxNumberLength: 8,
+	MaxStringLength: 32,
+	MaxValues:       24,
+}
+
+var limitErrors = []error{
+	jsonlimit.ErrMalformed,
+	jsonlimit.ErrInvalidUTF8,
+	jsonlimit.ErrTooDeep,
+	jsonlimit.ErrNumberTooLong,
+	jsonlimit.ErrStringTooLong,
+	jsonlimit.ErrTooManyValues,
+	jsonlimit.ErrDocumentTooBig,
+}
+
+func FuzzCheck(f *testing.F) {
+	for _, seed := range []string{
+		``,
+		`{}`,
+		`{"license_key":"LIC-1","product_name":"AwesomeApp"}`,
+		`{"license_key":"LIC-1","product_name":"AwesomeApp","metadata":{"device_id":"d1","n":12345678901234567890}}`,
+		`{"license_key":"LIC-1","product_name":"AwesomeApp","metadata":{"a":{"b":{"c":{"d":[1]}}}}}`,
+		`{"license_key":"` + strings.Repeat("k", 300) + `","product_name":"p"}`,
+		`{"license_key":"LIC-1","product_name":"p","agent_version":"1.0","signed_token":true}`,
+		`{"a":1}{"b":2}`,
+		`[[[[[[[[[[]]]]]]]]]]`,
+		"{\"license_key\":\"\xff\"}",
+		`{"metadata":1e99999999999999}`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, limits := range []jsonlimit.Limits{jsonlimit.Default, tight} {
+			checkErr := jsonlimit.Check(data, limits)
+			if checkErr != nil {
+				assertLimitError(t, checkErr)
+			}
+			if len(data) > limits.MaxBytes && !errors.Is(checkErr, jsonlimit.ErrDocumentTooBig) {
+				t.Fatalf("%d bytes over a %d byte limit: got %v", len(data), limits.MaxBytes, checkErr)
+			}
+
+			var req dto.ValidateLicenseRequest
+			err := handler.DecodeAgentJSON(data, limits, &req)
+			if checkErr != nil {
+				if err == nil || err.Error() != checkErr.Error() {
+					t.Fatalf("decoder returned %v for a body rejected with %v", err, checkErr)
+				}
+				continue
+			}
+			if err != nil {
+				continue
+			}
+			assertRoundTrip(t, &req)
+		}
+	})
+}
+
+func assertLimitError(t *testing.T, err error) {
+	t.Helper()
+	var coded ierr.Coded
+	if !errors.As(err, &coded) {
+		t.Fatalf("limit error %v is not an ierr error", err)
+	}
+	if !errors.Is(err, ierr.ErrValidation) && !errors.Is(err, ierr.ErrPayloadTooLarge) {
+		t.Fatalf("limit error %v is neither a validation nor a payload size error", err)
+	}
+	for _, target := range limitErrors {
+		if errors.Is(err, target) {
+			return
+		}
+	}
+	t.Fatalf("limit error %v is not one of the jsonlimit errors", err)
+}
+
+// assertRoundTrip checks that an accepted request encodes to JSON that
+// decodes back to the same request.
+func assertRoundTrip(t *testing.T, req *dto.ValidateLicenseRequest) {
+	t.Helper()
+	encoded, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("accepted request does not encode: %v", err)
+	}
+	var decoded dto.ValidateLicenseRequest
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("encoded request %s does not decode: %v", encoded, err)
+	}
+	again, err := json.Marshal(&decoded)
+	if err != nil {
+		t.Fatalf("decoded request does not encode: %v", err)
+	}
+	if !bytes.Equal(encoded, again) {
+		t.Fatalf("request does not round-trip:\n%s\n%s", encoded, again)
+	}
+}
@@ -0,0 +1,116 @@
+package lastseen
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// Changes lists entitlement paths such as "features.sso" or "limits.seats"
+// that differ between two validations.
+type Changes struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+}
+
+func (c *Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// Diff compares entitlement documents one level below their top-level keys:
+// objects are compared key by key and arrays of strings element by element,
+// anything else is reported as a change of the whole top-level key.
+func Diff(previous, current json.RawMessage) *Changes {
+	changes := &Changes{}
+	if bytes.Equal(previous, current) {
+		return changes
+	}
+
+	prev, cur := decode(previous), decode(current)
+	for name, curValue := range cur {
+		prevValue, ok := prev[name]
+		if !ok {
+			changes.Added = append(changes.Added, name)
+			continue
+		}
+		changes.merge(name, prevValue, curValue)
+	}
+	for name := range prev {
+		if _, ok := cur[name]; !ok {
+			changes.Removed = append(changes.Removed, name)
+		}
+	}
+
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Removed)
+	sort.Strings(changes.Changed)
+	return changes
+}
+
+func (c *Changes) merge(name string, prev, cur interface{}) {
+	prevObj, prevIsObj := prev.(map[string]interface{})
+	curObj, curIsObj := cur.(map[string]interface{})
+	if prevIsObj && curIsObj {
+		for k, v := range curObj {
+			old, ok := prevObj[k]
+			switch {
+			case !ok:
+				c.Added = append(c.Added, name+"."+k)
+			case !reflect.DeepEqual(old, v):
+				c.Changed = append(c.Changed, name+"."+k)
+			}
+		}
+		for k := range prevObj {
+			if _, ok := curObj[k]; !ok {
+				c.Removed = append(c.Removed, name+"."+k)
+			}
+		}
+		return
+	}
+
+	prevSet, prevIsSet := stringSet(prev)
+	curSet, curIsSet := stringSet(cur)
+	if prevIsSet && curIsSet {
+		for v := range curSet {
+			if !prevSet[v] {
+				c.Added = append(c.Added, name+"."+v)
+			}
+		}
+		for v := range prevSet {
+			if !curSet[v] {
+				c.Removed = append(c.Removed, name+"."+v)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(prev, cur) {
+		c.Changed = append(c.Changed, name)
+	}
+}
+
+func decode(data json.RawMessage) map[string]interface{} {
+	var m map[string]interface{}
+	if len(data) == 0 || json.Unmarshal(data, &m) != nil {
+		return map[string]interface{}{}
+	}
+	return m
+}
+
+func stringSet(v interface{}) (map[string]bool, bool) {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		set[s] = true
+	}
+	return set, true
+}
@@ -0,0 +1,90 @@
+// Package lastseen remembers, per license and agent, when the agent last
+// validated and which entitlements it was given, so validation can tell an
+// agent that its cached entitlements are stale.
+package lastseen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const keyPrefix = "last_seen:"
+
+type Entry struct {
+	SeenAt       time.Time       `json:"seen_at"`
+	Entitlements json.RawMessage `json:"entitlements,omitempty"`
+}
+
+// Store keeps one Entry per license and agent in Redis. Writes are debounced:
+// an agent that validates every few seconds with unchanged entitlements only
+// refreshes its entry once per debounce interval.
+type Store struct {
+	client   *redis.Client
+	debounce time.Duration
+	ttl      time.Duration
+	logger   *zap.Logger
+}
+
+func NewStore(client *redis.Client, cfg *config.LastSeenConfig, logger *zap.Logger) *Store {
+	return &Store{
+		client:   client,
+		debounce: cfg.Debounce,
+		ttl:      cfg.TTL,
+		logger:   logger.Named("LastSeenStore"),
+	}
+}
+
+// Get returns the entry for the agent, or nil if it has never been seen or
+// its entry expired.
+func (s *Store) Get(ctx context.Context, licenseID uuid.UUID, agent string) (*Entry, error) {
+	data, err := s.client.Get(ctx, key(licenseID, agent)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("redis error reading last seen entry: %w", err)
+	}
+
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		s.logger.Warn("Discarding malformed last seen entry", zap.String("license_id", licenseID.String()), zap.Error(err))
+		return nil, nil
+	}
+	return &e, nil
+}
+
+// Touch records that the agent was given entitlements at now. previous is the
+// entry returned by Get; the write is skipped when it is recent and carries
+// the same entitlements.
+func (s *Store) Touch(ctx context.Context, licenseID uuid.UUID, agent string, entitlements json.RawMessage, now time.Time, previous *Entry) error {
+	if previous != nil && now.Sub(previous.SeenAt) < s.debounce && bytes.Equal(previous.Entitlements, entitlements) {
+		return nil
+	}
+
+	data, err := json.Marshal(Entry{SeenAt: now, Entitlements: entitlements})
+	if err != nil {
+		return fmt.Errorf("failed to marshal last seen entry: %w", err)
+	}
+	if err := s.client.Set(ctx, key(licenseID, agent), data, s.ttl).Err(); err != nil {
+		return fmt.Errorf("redis error writing last seen entry: %w", err)
+	}
+	return nil
+}
+
+// key scopes entries by agent so two devices sharing a license each get told
+// about a change. Agents that send no device ID share one entry.
+func key(licenseID uuid.UUID, agent string) string {
+	if agent == "" {
+		agent = "-"
+	}
+	return keyPrefix + licenseID.String() + ":" + agent
+}
@@ -0,0 +1,192 @@
+// Package licensekey generates license keys from per-product format
+// patterns such as "XXXX-XXXX-XXXX-XXXC". In a pattern, X is a random
+// character, 9 a random digit and C a check character over all random
+// characters before it; anything else is copied literally.
+package licensekey
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+)
+
+// Alphabet is Crockford's base32: upper-case letters and digits without I,
+// L, O and U, so keys survive being read out or typed by hand.
+const Alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+const (
+	MaxPatternLength = 64
+	MaxPrefixLength  = 16
+	// MinEntropyBits keeps keys from being guessable; a pattern of twelve X
+	// characters is the shortest that passes.
+	MinEntropyBits = 60
+)
+
+const (
+	randomChar  = 'X'
+	randomDigit = '9'
+	checkChar   = 'C'
+)
+
+var prefixPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// Format is a parsed pattern plus an optional literal prefix. The zero
+// Format produces UUID keys, which is what licenses got before formats
+// existed.
+type Format struct {
+	pattern string
+	prefix  string
+}
+
+func Parse(pattern, prefix string) (Format, error) {
+	if len(prefix) > MaxPrefixLength || (prefix != "" && !prefixPattern.MatchString(prefix)) {
+		return Format{}, fmt.Errorf("%w: key prefix must be at most %d letters, digits, '-' or '_'", ierr.ErrValidation, MaxPrefixLength)
+	}
+	if pattern == "" {
+		return Format{prefix: prefix}, nil
+	}
+	if len(pattern) > MaxPatternLength {
+		return Format{}, fmt.Errorf("%w: key format must be at most %d characters long", ierr.ErrValidation, MaxPatternLength)
+	}
+
+	bits, random := 0.0, 0
+	for _, r := range pattern {
+		switch r {
+		case randomChar:
+			bits += math.Log2(float64(len(Alphabet)))
+			random++
+		case randomDigit:
+			bits += math.Log2(10)
+			random++
+		case checkChar:
+			if random == 0 {
+				return Format{}, fmt.Errorf("%w: key format check character C must follow random characters", ierr.ErrValidation)
+			}
+		default:
+			if r > 0x7e || r < 0x21 {
+				return Format{}, fmt.Errorf("%w: key format may only contain printable ASCII characters", ierr.ErrValidation)
+			}
+		}
+	}
+	if bits < MinEntropyBits {
+		return Format{}, fmt.Errorf("%w: key format %q is too easy to guess, use at least 12 X characters", ierr.ErrValidation, pattern)
+	}
+	return Format{pattern: pattern, prefix: prefix}, nil
+}
+
+// Generate returns a new key. Uniqueness is left to the caller, which
+// retries when storage reports a duplicate.
+func (f Format) Generate() (string, error) {
+	if f.pattern == "" {
+		return f.prefix + uuid.NewString(), nil
+	}
+
+	var b strings.Builder
+	b.Grow(len(f.prefix) + len(f.pattern))
+	b.WriteString(f.prefix)
+	var sum checksum
+	for _, r := range f.pattern {
+		switch r {
+		case randomChar:
+			c, err := randomFrom(Alphabet)
+			if err != nil {
+				return "", err
+			}
+			sum.add(c)
+			b.WriteByte(c)
+		case randomDigit:
+			c, err := randomFrom(Alphabet[:10])
+			if err != nil {
+				return "", err
+			}
+			sum.add(c)
+			b.WriteByte(c)
+		case checkChar:
+			b.WriteByte(sum.char())
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String(), nil
+}
+
+// Valid reports whether key matches the format, including its check
+// characters. Keys of the zero Format are checked for a UUID suffix only.
+func (f Format) Valid(key string) bool {
+	rest, ok := strings.CutPrefix(key, f.prefix)
+	if !ok {
+		return false
+	}
+	if f.pattern == "" {
+		return uuid.Validate(rest) == nil
+	}
+	if len(rest) != len(f.pattern) {
+		return false
+	}
+
+	var sum checksum
+	for i := 0; i < len(f.pattern); i++ {
+		c := rest[i]
+		switch f.pattern[i] {
+		case randomChar:
+			if strings.IndexByte(Alphabet, c) < 0 {
+				return false
+			}
+			sum.add(c)
+		case randomDigit:
+			if c < '0' || c > '9' {
+				return false
+			}
+			sum.add(c)
+		case checkChar:
+			if c != sum.char() {
+				return false
+			}
+		default:
+			if c != f.pattern[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// checksum is Luhn mod N over Alphabet, which catches every single
+// character typo and most swaps of adjacent characters.
+type checksum struct {
+	codes []int
+}
+
+func (s *checksum) add(c byte) {
+	s.codes = append(s.codes, strings.IndexByte(Alphabet, c))
+}
+
+func (s *checksum) char() byte {
+	n := len(Alphabet)
+	sum := 0
+	double := true
+	for i := len(s.codes) - 1; i >= 0; i-- {
+		v := s.codes[i]
+		if double {
+			v *= 2
+			v = v/n + v%n
+		}
+		sum += v
+		double = !double
+	}
+	return Alphabet[(n-sum%n)%n]
+}
+
+func randomFrom(chars string) (byte, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+	if err != nil {
+		return 0, fmt.Errorf("reading random bytes for license key: %w", err)
+	}
+	return chars[i.Int64()], nil
+}
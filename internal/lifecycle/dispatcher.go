@@ -0,0 +1,162 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/outbox"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/safego"
+	"go.uber.org/zap"
+)
+
+// OutboxConsumer is the dispatcher's cursor in outbox_cursors; migration
+// 000041 starts it at the end of the outbox.
+const OutboxConsumer = "lifecycle_hooks"
+
+// hookTimeout bounds one hook handling one event.
+const hookTimeout = 30 * time.Second
+
+// Dispatcher runs the hooks for the license changes in one database's
+// outbox. The outbox is written by trigger in the same transaction as the
+// change, so every committed change is seen even if the process that made
+// it crashes right after; the cursor advances once an event's hooks have
+// run, so after a crash an event may be handled twice.
+type Dispatcher struct {
+	outbox       outbox.Repository
+	licenses     license.Repository
+	hooks        []LicenseLifecycleHook
+	batchSize    int
+	pollInterval time.Duration
+	logger       *zap.Logger
+}
+
+func NewDispatcher(outboxRepo outbox.Repository, licenses license.Repository, hooks []LicenseLifecycleHook, cfg *config.LifecycleHooksConfig, logger *zap.Logger) *Dispatcher {
+	return &Dispatcher{
+		outbox:       outboxRepo,
+		licenses:     licenses,
+		hooks:        hooks,
+		batchSize:    cfg.BatchSize,
+		pollInterval: cfg.PollInterval,
+		logger:       logger.Named("LifecycleDispatcher"),
+	}
+}
+
+// Run polls the outbox until ctx is cancelled. A batch that fails is
+// retried on the next tick from the first event not yet handled.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	d.logger.Info("Lifecycle hook dispatcher started", zap.Int("hooks", len(d.hooks)), zap.Duration("poll_interval", d.pollInterval))
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := d.RunOnce(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					d.logger.Error("Lifecycle hook dispatch failed", zap.Error(err))
+				}
+				break
+			}
+			if n < d.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			d.logger.Info("Lifecycle hook dispatcher stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce handles the next batch of outbox events and returns how many it
+// handled.
+func (d *Dispatcher) RunOnce(ctx context.Context) (int, error) {
+	cursor, err := d.outbox.GetCursor(ctx, OutboxConsumer)
+	if err != nil {
+		return 0, err
+	}
+	events, err := d.outbox.ListAfter(ctx, outbox.Query{AfterID: cursor, Limit: d.batchSize})
+	if err != nil {
+		return 0, err
+	}
+
+	handled, lastID := 0, cursor
+	for _, e := range events {
+		if err = d.dispatch(ctx, e); err != nil {
+			err = fmt.Errorf("dispatching outbox event %d: %w", e.ID, err)
+			break
+		}
+		handled, lastID = handled+1, e.ID
+	}
+	if lastID != cursor {
+		if errSave := d.outbox.SaveCursor(ctx, OutboxConsumer, lastID); errSave != nil {
+			return handled, errors.Join(err, errSave)
+		}
+	}
+	return handled, err
+}
+
+// dispatch runs the hooks for the lifecycle event an outbox event is, if
+// any. Hook errors are logged and not retried; only failing to load the
+// license fails the event.
+func (d *Dispatcher) dispatch(ctx context.Context, e *outbox.Event) error {
+	var eventType EventType
+	switch e.Operation {
+	case outbox.OperationInsert:
+		eventType = EventCreated
+	case outbox.OperationUpdate:
+		var ok bool
+		eventType, ok = transition(license.LicenseStatus(e.PreviousStatus), license.LicenseStatus(e.Status))
+		if !ok || e.PreviousStatus == "" {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	lic, err := d.licenses.FindByID(ctx, e.LicenseID)
+	if errors.Is(err, ierr.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// The license may have changed again since; the event describes it
+	// with the status this change gave it.
+	lic.Status = license.LicenseStatus(e.Status)
+	event := Event{
+		Type:           eventType,
+		License:        lic,
+		PreviousStatus: license.LicenseStatus(e.PreviousStatus),
+		OccurredAt:     e.CreatedAt.UTC(),
+	}
+
+	var wg sync.WaitGroup
+	for _, hook := range d.hooks {
+		wg.Add(1)
+		safego.Go(d.logger, "lifecycle_hook", func() {
+			defer wg.Done()
+			hookCtx, cancel := context.WithTimeout(ctx, hookTimeout)
+			defer cancel()
+			if err := hook.Handle(hookCtx, event); err != nil {
+				d.logger.Error("License lifecycle hook failed",
+					zap.String("hook", hook.Name()),
+					zap.String("event", string(eventType)),
+					zap.String("license_id", lic.ID.String()),
+					zap.Error(err),
+				)
+			}
+		})
+	}
+	wg.Wait()
+	return nil
+}
@@ -0,0 +1,61 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/makkenzo/license-service-api/internal/display"
+	"github.com/makkenzo/license-service-api/internal/notify"
+	"github.com/makkenzo/license-service-api/internal/templates"
+)
+
+// EmailHook e-mails the customer of a license about its lifecycle events,
+// rendered from the license_event template in the license's locale.
+// Licenses without a customer e-mail are skipped.
+type EmailHook struct {
+	mailer    notify.Mailer
+	renderer  *templates.Renderer
+	localizer *display.Localizer
+	events    map[EventType]bool
+}
+
+func NewEmailHook(mailer notify.Mailer, renderer *templates.Renderer, localizer *display.Localizer, events map[EventType]bool) *EmailHook {
+	return &EmailHook{
+		mailer:    mailer,
+		renderer:  renderer,
+		localizer: localizer,
+		events:    events,
+	}
+}
+
+var _ LicenseLifecycleHook = (*EmailHook)(nil)
+
+func (h *EmailHook) Name() string { return "email" }
+
+func (h *EmailHook) Handle(ctx context.Context, event Event) error {
+	lic := event.License
+	if !h.events[event.Type] || !lic.CustomerEmail.Valid || lic.CustomerEmail.String == "" {
+		return nil
+	}
+
+	data := templates.LicenseEventData{
+		CustomerName: lic.CustomerName.String,
+		ProductName:  lic.ProductName,
+		LicenseKey:   lic.LicenseKey,
+		Event:        string(event.Type),
+	}
+	if lic.ExpiresAt.Valid {
+		data.ExpiresAt = &lic.ExpiresAt.Time
+		data.ExpiresOn = h.localizer.For(lic.Locale(), lic.Timezone()).Date(lic.ExpiresAt.Time)
+	}
+	rendered, err := h.renderer.Render(templates.LicenseEvent, lic.Locale(), data)
+	if err != nil {
+		return fmt.Errorf("failed to render license event e-mail: %w", err)
+	}
+	return h.mailer.Send(ctx, notify.Message{
+		To:        lic.CustomerEmail.String,
+		Subject:   rendered.Subject,
+		Body:      rendered.Body,
+		LicenseID: lic.ID,
+	})
+}
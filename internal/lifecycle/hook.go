@@ -0,0 +1,79 @@
+// Package lifecycle runs hooks when a license is created, activated, expires
+// or is revoked. Hooks are the extension point for side effects of those
+// events, such as webhooks and e-mails: Dispatcher fires them for every
+// status change recorded in the license outbox, whether it comes from an
+// operator, validation traffic or the expiration job, so they never slow down
+// or fail the change itself and are not lost if the process stops right
+// after it.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+)
+
+type EventType string
+
+const (
+	EventCreated   EventType = "created"
+	EventActivated EventType = "activated"
+	EventExpired   EventType = "expired"
+	EventRevoked   EventType = "revoked"
+)
+
+var knownEvents = map[EventType]bool{EventCreated: true, EventActivated: true, EventExpired: true, EventRevoked: true}
+
+// ParseEvents turns configured event names into a set.
+func ParseEvents(names []string) (map[EventType]bool, error) {
+	events := make(map[EventType]bool, len(names))
+	for _, name := range names {
+		e := EventType(strings.ToLower(strings.TrimSpace(name)))
+		if e == "" {
+			continue
+		}
+		if !knownEvents[e] {
+			return nil, fmt.Errorf("unknown license lifecycle event %q, expected created, activated, expired or revoked", name)
+		}
+		events[e] = true
+	}
+	return events, nil
+}
+
+type Event struct {
+	Type    EventType
+	License *license.License
+	// PreviousStatus is the status the license had before the change; empty
+	// for EventCreated.
+	PreviousStatus license.LicenseStatus
+	OccurredAt     time.Time
+}
+
+// LicenseLifecycleHook is run for every lifecycle event and ignores the ones
+// it is not interested in. Handle runs on a background worker with the
+// pool's task timeout; errors are logged and the event is not retried. The
+// event is shared between hooks and must not be modified.
+type LicenseLifecycleHook interface {
+	Name() string
+	Handle(ctx context.Context, event Event) error
+}
+
+// transition returns the event a status change from before to after is.
+func transition(before, after license.LicenseStatus) (EventType, bool) {
+	if before == after {
+		return "", false
+	}
+	switch after {
+	case license.StatusActive:
+		return EventActivated, true
+	case license.StatusExpired:
+		return EventExpired, true
+	case license.StatusRevoked:
+		return EventRevoked, true
+	default:
+		return "", false
+	}
+}
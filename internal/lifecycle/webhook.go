@@ -0,0 +1,140 @@
+package lifecycle
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+)
+
+// WebhookSignatureHeader carries "t=<unix seconds>,v1=<hex HMAC>" over
+// "<t>.<body>", keyed with the webhook secret, so receivers can check that
+// an event comes from this service and reject stale replays.
+const WebhookSignatureHeader = "X-License-Signature"
+
+type webhookPayload struct {
+	Event          EventType      `json:"event"`
+	OccurredAt     time.Time      `json:"occurred_at"`
+	PreviousStatus string         `json:"previous_status,omitempty"`
+	License        WebhookLicense `json:"license"`
+}
+
+// WebhookLicense is how webhooks describe a license.
+type WebhookLicense struct {
+	ID           uuid.UUID  `json:"id"`
+	LicenseKey   string     `json:"license_key"`
+	Status       string     `json:"status"`
+	Type         string     `json:"type"`
+	ProductID    uuid.UUID  `json:"product_id"`
+	ProductName  string     `json:"product_name"`
+	CustomerID   *uuid.UUID `json:"customer_id,omitempty"`
+	CustomerName string     `json:"customer_name,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	ParentID     *uuid.UUID `json:"parent_id,omitempty"`
+}
+
+// WebhookHook posts lifecycle events to an HTTP endpoint. Any 2xx answer
+// counts as delivered.
+type WebhookHook struct {
+	url    string
+	secret string
+	events map[EventType]bool
+	crypto cryptoprovider.Provider
+	http   *http.Client
+}
+
+func NewWebhookHook(cfg *config.LifecycleHooksConfig, crypto cryptoprovider.Provider) (*WebhookHook, error) {
+	events, err := ParseEvents(cfg.WebhookEvents)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookHook{
+		url:    cfg.WebhookURL,
+		secret: cfg.WebhookSecret,
+		events: events,
+		crypto: crypto,
+		http:   &http.Client{Timeout: cfg.WebhookTimeout},
+	}, nil
+}
+
+var _ LicenseLifecycleHook = (*WebhookHook)(nil)
+
+func (h *WebhookHook) Name() string { return "webhook" }
+
+func (h *WebhookHook) Handle(ctx context.Context, event Event) error {
+	if !h.events[event.Type] {
+		return nil
+	}
+	body, err := json.Marshal(newWebhookPayload(event))
+	if err != nil {
+		return fmt.Errorf("encoding webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(h.crypto, h.secret, body, time.Now()))
+	}
+
+	resp, err := h.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		answer, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		return fmt.Errorf("webhook answered %d: %s", resp.StatusCode, answer)
+	}
+	return nil
+}
+
+// SignWebhook returns the WebhookSignatureHeader value for body sent at now.
+func SignWebhook(crypto cryptoprovider.Provider, secret string, body []byte, now time.Time) string {
+	ts := strconv.FormatInt(now.Unix(), 10)
+	mac := crypto.MAC([]byte(secret), append([]byte(ts+"."), body...))
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac)
+}
+
+func newWebhookPayload(event Event) webhookPayload {
+	return webhookPayload{
+		Event:          event.Type,
+		OccurredAt:     event.OccurredAt,
+		PreviousStatus: string(event.PreviousStatus),
+		License:        NewWebhookLicense(event.License),
+	}
+}
+
+func NewWebhookLicense(lic *license.License) WebhookLicense {
+	wl := WebhookLicense{
+		ID:           lic.ID,
+		LicenseKey:   lic.LicenseKey,
+		Status:       string(lic.Status),
+		Type:         lic.Type,
+		ProductID:    lic.ProductID,
+		ProductName:  lic.ProductName,
+		CustomerName: lic.CustomerName.String,
+	}
+	if lic.CustomerID.Valid {
+		wl.CustomerID = &lic.CustomerID.UUID
+	}
+	if lic.ExpiresAt.Valid {
+		wl.ExpiresAt = &lic.ExpiresAt.Time
+	}
+	if lic.ParentID.Valid {
+		wl.ParentID = &lic.ParentID.UUID
+	}
+	return wl
+}
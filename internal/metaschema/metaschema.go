@@ -0,0 +1,96 @@
+// Package metaschema checks license metadata against the JSON Schema a
+// product defines for it. Schemas use the OpenAPI 3 dialect of JSON Schema
+// that kin-openapi validates, which covers type, properties, required,
+// additionalProperties, enum, pattern, bounds and the *Of combinators.
+package metaschema
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+)
+
+var ErrMetadataInvalid = ierr.ErrValidation.Derive("METADATA_SCHEMA_VIOLATION", "metadata does not match the product's metadata schema")
+
+// serviceKeys are written into metadata by the service itself, so schemas
+// do not have to allow for them.
+var serviceKeys = []string{"last_validated_at", "last_ip"}
+
+type Schema struct {
+	schema *openapi3.Schema
+}
+
+// Compile parses a metadata schema and checks that it is well formed.
+func Compile(raw json.RawMessage) (*Schema, error) {
+	var s openapi3.Schema
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("%w: metadata_schema is not a JSON Schema object: %v", ierr.ErrValidation, err)
+	}
+	if err := s.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("%w: metadata_schema is invalid: %v", ierr.ErrValidation, err)
+	}
+	return &Schema{schema: &s}, nil
+}
+
+// Validate checks metadata against the schema and returns an
+// *ierr.FieldErrors naming every offending field. Absent metadata is
+// checked as an empty object, so required properties are enforced.
+func (s *Schema) Validate(metadata json.RawMessage) error {
+	var value interface{} = map[string]interface{}{}
+	if len(bytes.TrimSpace(metadata)) > 0 && !bytes.Equal(bytes.TrimSpace(metadata), []byte("null")) {
+		if err := json.Unmarshal(metadata, &value); err != nil {
+			return fmt.Errorf("%w: metadata is not valid JSON", ierr.ErrValidation)
+		}
+	}
+	if obj, ok := value.(map[string]interface{}); ok {
+		for _, k := range serviceKeys {
+			delete(obj, k)
+		}
+	}
+
+	err := s.schema.VisitJSON(value, openapi3.MultiErrors())
+	if err == nil {
+		return nil
+	}
+	fields := fieldErrors(err, nil)
+	if len(fields) == 0 {
+		fields = []ierr.FieldError{{Field: "metadata", Message: err.Error()}}
+	}
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+	return ierr.NewFieldErrors(ErrMetadataInvalid, fields)
+}
+
+func fieldErrors(err error, fields []ierr.FieldError) []ierr.FieldError {
+	var multi openapi3.MultiError
+	if errors.As(err, &multi) {
+		for _, e := range multi {
+			fields = fieldErrors(e, fields)
+		}
+		return fields
+	}
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		path := "metadata"
+		if ptr := schemaErr.JSONPointer(); len(ptr) > 0 {
+			path += "." + strings.Join(ptr, ".")
+		}
+		return append(fields, ierr.FieldError{Field: path, Message: schemaErr.Reason})
+	}
+	return append(fields, ierr.FieldError{Field: "metadata", Message: err.Error()})
+}
+
+// Allows reports whether metadata may carry key at the top level: the
+// schema either declares it or does not forbid additional properties.
+func (s *Schema) Allows(key string) bool {
+	if _, ok := s.schema.Properties[key]; ok {
+		return true
+	}
+	return s.schema.AdditionalProperties.Has == nil || *s.schema.AdditionalProperties.Has || s.schema.AdditionalProperties.Schema != nil
+}
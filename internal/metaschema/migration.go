@@ -0,0 +1,135 @@
+package metaschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/makkenzo/license-service-api/internal/ierr"
+)
+
+var ErrMigrationConflict = ierr.ErrConflict.Derive("METADATA_MIGRATION_CONFLICT", "metadata cannot be migrated without overwriting a key")
+
+type OpKind string
+
+const (
+	// OpRename moves the value of Key to To. Licenses that already have To
+	// are left alone and reported as conflicts.
+	OpRename OpKind = "rename"
+	// OpSetDefault sets Key to Value where it is absent or null.
+	OpSetDefault OpKind = "set_default"
+	// OpDrop removes Key.
+	OpDrop OpKind = "drop"
+)
+
+// Op is one step of a metadata migration. Keys name top-level metadata
+// properties.
+type Op struct {
+	Op    OpKind          `json:"op"`
+	Key   string          `json:"key"`
+	To    string          `json:"to,omitempty"`
+	Value json.RawMessage `json:"value,omitempty" swaggertype:"object"`
+}
+
+// Mapping is an ordered list of operations; each sees the result of the
+// ones before it.
+type Mapping []Op
+
+// Change is the difference a migration makes to one key. Before is absent
+// for added keys and After for removed ones.
+type Change struct {
+	Key    string          `json:"key"`
+	Before json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After  json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+}
+
+// Check reports the first malformed operation. Keys the service writes
+// itself cannot be migrated.
+func (m Mapping) Check() error {
+	for i, op := range m {
+		if op.Key == "" {
+			return fmt.Errorf("%w: operations[%d]: key is required", ierr.ErrValidation, i)
+		}
+		for _, k := range serviceKeys {
+			if op.Key == k || op.To == k {
+				return fmt.Errorf("%w: operations[%d]: %s is maintained by the service and cannot be migrated", ierr.ErrValidation, i, k)
+			}
+		}
+		switch op.Op {
+		case OpRename:
+			if op.To == "" || op.To == op.Key {
+				return fmt.Errorf("%w: operations[%d]: rename needs a to key different from key", ierr.ErrValidation, i)
+			}
+		case OpSetDefault:
+			if len(bytes.TrimSpace(op.Value)) == 0 || !json.Valid(op.Value) || bytes.Equal(bytes.TrimSpace(op.Value), []byte("null")) {
+				return fmt.Errorf("%w: operations[%d]: set_default needs a non-null JSON value", ierr.ErrValidation, i)
+			}
+		case OpDrop:
+		default:
+			return fmt.Errorf("%w: operations[%d]: unknown op %q, expected rename, set_default or drop", ierr.ErrValidation, i, op.Op)
+		}
+	}
+	return nil
+}
+
+// Apply runs the mapping against metadata and returns the migrated metadata
+// with the changes it makes, sorted by key. Absent metadata is migrated as
+// an empty object. When nothing changes the input is returned as is.
+func (m Mapping) Apply(metadata json.RawMessage) (json.RawMessage, []Change, error) {
+	before := map[string]json.RawMessage{}
+	if trimmed := bytes.TrimSpace(metadata); len(trimmed) > 0 && !bytes.Equal(trimmed, []byte("null")) {
+		if err := json.Unmarshal(trimmed, &before); err != nil {
+			return nil, nil, fmt.Errorf("%w: metadata is not a JSON object", ierr.ErrValidation)
+		}
+	}
+
+	after := make(map[string]json.RawMessage, len(before))
+	for k, v := range before {
+		after[k] = v
+	}
+	for _, op := range m {
+		switch op.Op {
+		case OpRename:
+			v, ok := after[op.Key]
+			if !ok {
+				continue
+			}
+			if _, taken := after[op.To]; taken {
+				return nil, nil, fmt.Errorf("%w: renaming %s would overwrite %s", ErrMigrationConflict, op.Key, op.To)
+			}
+			after[op.To] = v
+			delete(after, op.Key)
+		case OpSetDefault:
+			if v, ok := after[op.Key]; !ok || bytes.Equal(bytes.TrimSpace(v), []byte("null")) {
+				after[op.Key] = op.Value
+			}
+		case OpDrop:
+			delete(after, op.Key)
+		}
+	}
+
+	var changes []Change
+	for k, v := range before {
+		if w, ok := after[k]; !ok {
+			changes = append(changes, Change{Key: k, Before: v})
+		} else if !bytes.Equal(v, w) {
+			changes = append(changes, Change{Key: k, Before: v, After: w})
+		}
+	}
+	for k, w := range after {
+		if _, ok := before[k]; !ok {
+			changes = append(changes, Change{Key: k, After: w})
+		}
+	}
+	if len(changes) == 0 {
+		return metadata, nil, nil
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+
+	migrated, err := json.Marshal(after)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding migrated metadata: %w", err)
+	}
+	return migrated, changes, nil
+}
@@ -0,0 +1,107 @@
+// Package notify sends e-mail to license customers.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+	"github.com/makkenzo/license-service-api/internal/safego"
+	"go.uber.org/zap"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+	// LicenseID, when set, gets the message a Reply-To address that routes
+	// the customer's reply back to the license as a note.
+	LicenseID uuid.UUID
+	// UnsubscribeURL, when set, is advertised in a List-Unsubscribe header
+	// so mail clients can offer their own unsubscribe button.
+	UnsubscribeURL string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewMailer returns an SMTP mailer, or a mailer that only logs messages when
+// no SMTP host is configured.
+func NewMailer(cfg *config.NotifyConfig, crypto cryptoprovider.Provider, logger *zap.Logger) Mailer {
+	if cfg.SMTPHost == "" {
+		return &LogMailer{logger: logger.Named("LogMailer")}
+	}
+	return &SMTPMailer{cfg: cfg, crypto: crypto, logger: logger.Named("SMTPMailer")}
+}
+
+type SMTPMailer struct {
+	cfg    *config.NotifyConfig
+	crypto cryptoprovider.Provider
+	logger *zap.Logger
+}
+
+var _ Mailer = (*SMTPMailer)(nil)
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	addr := net.JoinHostPort(m.cfg.SMTPHost, strconv.Itoa(m.cfg.SMTPPort))
+
+	var auth smtp.Auth
+	if m.cfg.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", m.cfg.SMTPUsername, m.cfg.SMTPPassword, m.cfg.SMTPHost)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	if msg.LicenseID != uuid.Nil {
+		if replyTo, ok := ReplyAddress(m.crypto, m.cfg, msg.LicenseID); ok {
+			fmt.Fprintf(&b, "Reply-To: %s\r\n", replyTo)
+		}
+	}
+	if msg.UnsubscribeURL != "" {
+		fmt.Fprintf(&b, "List-Unsubscribe: <%s>\r\n", msg.UnsubscribeURL)
+	}
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(msg.Body)
+
+	errCh := make(chan error, 1)
+	safego.Go(m.logger, "smtp_send", func() {
+		errCh <- smtp.SendMail(addr, auth, m.cfg.From, []string{msg.To}, []byte(b.String()))
+	})
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			m.logger.Error("Failed to send e-mail", zap.String("to", msg.To), zap.String("subject", msg.Subject), zap.Error(err))
+			return fmt.Errorf("smtp error sending mail: %w", err)
+		}
+		m.logger.Info("E-mail sent", zap.String("to", msg.To), zap.String("subject", msg.Subject))
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type LogMailer struct {
+	logger *zap.Logger
+}
+
+var _ Mailer = (*LogMailer)(nil)
+
+func (m *LogMailer) Send(_ context.Context, msg Message) error {
+	m.logger.Info("SMTP not configured, e-mail not sent",
+		zap.String("to", msg.To),
+		zap.String("subject", msg.Subject),
+		zap.String("body", msg.Body),
+	)
+	return nil
+}
@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"net/mail"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+)
+
+var (
+	ErrNotReplyAddress   = errors.New("not a reply address")
+	ErrReplyTagSignature = errors.New("reply address tag signature mismatch")
+)
+
+// replyMACLen keeps the tag short enough for the 64 character limit on the
+// local part of an address.
+const replyMACLen = 8
+
+// ReplyAddress returns where replies to mail about licenseID should go: the
+// configured reply address plus-tagged with the license ID and its
+// HMAC-SHA256, e.g. replies+<tag>@inbound.example.com. The tag is lowercase
+// hex because some mail servers change the case of the local part. ok is
+// false when reply addresses are not configured.
+func ReplyAddress(crypto cryptoprovider.Provider, cfg *config.NotifyConfig, licenseID uuid.UUID) (addr string, ok bool) {
+	if cfg.ReplyAddress == "" || cfg.ReplySecret == "" {
+		return "", false
+	}
+	local, domain, found := strings.Cut(cfg.ReplyAddress, "@")
+	if !found {
+		return "", false
+	}
+	mac := crypto.MAC([]byte(cfg.ReplySecret), licenseID[:])[:replyMACLen]
+	return local + "+" + hex.EncodeToString(licenseID[:]) + hex.EncodeToString(mac) + "@" + domain, true
+}
+
+// LicenseFromReplyAddress returns the license a reply address was issued
+// for. addr may carry a display name.
+func LicenseFromReplyAddress(crypto cryptoprovider.Provider, cfg *config.NotifyConfig, addr string) (uuid.UUID, error) {
+	baseLocal, baseDomain, found := strings.Cut(cfg.ReplyAddress, "@")
+	if !found || cfg.ReplySecret == "" {
+		return uuid.Nil, ErrNotReplyAddress
+	}
+	if parsed, err := mail.ParseAddress(addr); err == nil {
+		addr = parsed.Address
+	}
+	local, domain, found := strings.Cut(addr, "@")
+	if !found || !strings.EqualFold(domain, baseDomain) {
+		return uuid.Nil, ErrNotReplyAddress
+	}
+	prefix, tag, found := strings.Cut(local, "+")
+	if !found || !strings.EqualFold(prefix, baseLocal) {
+		return uuid.Nil, ErrNotReplyAddress
+	}
+
+	raw, err := hex.DecodeString(strings.ToLower(tag))
+	if err != nil || len(raw) != 16+replyMACLen {
+		return uuid.Nil, ErrNotReplyAddress
+	}
+	licenseID, err := uuid.FromBytes(raw[:16])
+	if err != nil {
+		return uuid.Nil, ErrNotReplyAddress
+	}
+	if !hmac.Equal(raw[16:], crypto.MAC([]byte(cfg.ReplySecret), licenseID[:])[:replyMACLen]) {
+		return uuid.Nil, ErrReplyTagSignature
+	}
+	return licenseID, nil
+}
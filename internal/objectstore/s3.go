@@ -0,0 +1,154 @@
+// Package objectstore uploads files to S3 or an S3-compatible store such as
+// MinIO. It implements the one call the service needs, a signed PUT, so it
+// does not pull in an SDK.
+package objectstore
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+)
+
+// Store keeps uploaded files.
+type Store interface {
+	Put(ctx context.Context, key string, body io.ReadSeeker, contentType string) error
+}
+
+type S3Client struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	crypto    cryptoprovider.Provider
+	http      *http.Client
+}
+
+// NewS3Client returns a client for cfg.Bucket. Without an endpoint it talks
+// to AWS in cfg.Region; path-style addressing is for stores that do not
+// serve buckets as subdomains.
+func NewS3Client(cfg *config.S3Config, crypto cryptoprovider.Provider) (*S3Client, error) {
+	if cfg.Bucket == "" || cfg.Region == "" {
+		return nil, fmt.Errorf("S3 bucket and region are required")
+	}
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	u, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+	return &S3Client{
+		endpoint:  u,
+		region:    cfg.Region,
+		bucket:    cfg.Bucket,
+		accessKey: cfg.AccessKeyID,
+		secretKey: cfg.SecretAccessKey,
+		pathStyle: cfg.PathStyle,
+		crypto:    crypto,
+		http:      &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+var _ Store = (*S3Client)(nil)
+
+func (c *S3Client) Bucket() string { return c.bucket }
+
+// Put uploads body as object key. The payload is sent unsigned, which S3
+// accepts over TLS, so large files are streamed instead of hashed first.
+func (c *S3Client) Put(ctx context.Context, key string, body io.ReadSeeker, contentType string) error {
+	size, err := body.Seek(0, io.SeekEnd)
+	if err == nil {
+		_, err = body.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		return fmt.Errorf("sizing object %s: %w", key, err)
+	}
+
+	u := *c.endpoint
+	u.Path, u.RawPath = "/"+key, "/"+escapePath(key)
+	if c.pathStyle {
+		u.Path, u.RawPath = "/"+c.bucket+u.Path, "/"+c.bucket+u.RawPath
+	} else {
+		u.Host = c.bucket + "." + u.Host
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), body)
+	if err != nil {
+		return fmt.Errorf("building S3 request: %w", err)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	c.sign(req, "UNSIGNED-PAYLOAD", time.Now().UTC())
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("uploading %s to S3: %w", key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		answer, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		return fmt.Errorf("S3 answered %d uploading %s: %s", resp.StatusCode, key, answer)
+	}
+	return nil
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to req.
+func (c *S3Client) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	const signedHeaders = "content-type;host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		"",
+		"content-type:" + req.Header.Get("Content-Type"),
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + c.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(c.crypto.Hash([]byte(canonicalRequest)))
+
+	key := c.crypto.MAC([]byte("AWS4"+c.secretKey), []byte(date))
+	for _, part := range []string{c.region, "s3", "aws4_request"} {
+		key = c.crypto.MAC(key, []byte(part))
+	}
+	signature := hex.EncodeToString(c.crypto.MAC(key, []byte(stringToSign)))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+c.accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// escapePath percent-encodes every byte of key except the unreserved
+// characters and "/", as S3 expects in the canonical request.
+func escapePath(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		ch := key[i]
+		if ch >= 'A' && ch <= 'Z' || ch >= 'a' && ch <= 'z' || ch >= '0' && ch <= '9' ||
+			ch == '-' || ch == '_' || ch == '.' || ch == '~' || ch == '/' {
+			b.WriteByte(ch)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", ch)
+	}
+	return b.String()
+}
@@ -0,0 +1,146 @@
+package region
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/makkenzo/license-service-api/internal/audit"
+	"github.com/makkenzo/license-service-api/internal/domain/activation"
+	"github.com/makkenzo/license-service-api/internal/domain/apikey"
+	domainaudit "github.com/makkenzo/license-service-api/internal/domain/audit"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+// Applier applies writes forwarded by replica regions on the primary,
+// through the same repositories a local request would use, so they are
+// audited and evict caches as usual.
+type Applier struct {
+	licenses    license.Repository
+	activations activation.Repository
+	apiKeys     apikey.Repository
+	logger      *zap.Logger
+}
+
+func NewApplier(licenses license.Repository, activations activation.Repository, apiKeys apikey.Repository, logger *zap.Logger) *Applier {
+	return &Applier{
+		licenses:    licenses,
+		activations: activations,
+		apiKeys:     apiKeys,
+		logger:      logger.Named("RegionApplier"),
+	}
+}
+
+func (a *Applier) Apply(ctx context.Context, w *Write) error {
+	ctx = audit.WithActor(ctx, domainaudit.Actor{Type: domainaudit.ActorSystem, ID: "region:" + w.Region})
+
+	var err error
+	switch w.Op {
+	case OpUpdateMetadata:
+		err = a.updateMetadata(ctx, w)
+	case OpUpdateStatus:
+		err = a.expire(ctx, w)
+	case OpActivate:
+		err = a.activate(ctx, w)
+	case OpDeactivate:
+		_, err = a.activations.Deactivate(ctx, w.LicenseID, w.DeviceID)
+		if errors.Is(err, ierr.ErrNotFound) {
+			err = nil
+		}
+	case OpHeartbeat:
+		err = a.heartbeat(ctx, w)
+	case OpAPIKeyUsed:
+		err = a.apiKeys.UpdateLastUsed(ctx, w.APIKeyID, w.At)
+	default:
+		return fmt.Errorf("%w: unknown forwarded write %q", ierr.ErrValidation, w.Op)
+	}
+	if err != nil {
+		a.logger.Warn("Failed to apply forwarded write", zap.String("op", w.Op), zap.String("region", w.Region), zap.Error(err))
+		return err
+	}
+	a.logger.Debug("Applied forwarded write", zap.String("op", w.Op), zap.String("region", w.Region))
+	return nil
+}
+
+func (a *Applier) updateMetadata(ctx context.Context, w *Write) error {
+	lic, err := a.licenses.FindByID(ctx, w.LicenseID)
+	if err != nil {
+		return err
+	}
+	merged := make(map[string]json.RawMessage, len(w.MetadataPatch))
+	if len(lic.Metadata) > 0 {
+		if err := json.Unmarshal(lic.Metadata, &merged); err != nil {
+			merged = make(map[string]json.RawMessage, len(w.MetadataPatch))
+		}
+	}
+	for key, value := range w.MetadataPatch {
+		if string(value) == "null" {
+			delete(merged, key)
+		} else {
+			merged[key] = value
+		}
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("encoding merged metadata: %w", err)
+	}
+	return a.licenses.UpdateMetadata(ctx, w.LicenseID, data)
+}
+
+// expire is the only status change replicas make. It is skipped when the
+// license is no longer active or was renewed after the replica saw it.
+func (a *Applier) expire(ctx context.Context, w *Write) error {
+	if w.Status != license.StatusExpired {
+		return fmt.Errorf("%w: replicas may only forward expiry, not %q", ierr.ErrValidation, w.Status)
+	}
+	lic, err := a.licenses.FindByID(ctx, w.LicenseID)
+	if err != nil {
+		return err
+	}
+	if lic.Status != license.StatusActive || !lic.Lapsed(w.At) {
+		return nil
+	}
+	return a.licenses.UpdateStatus(ctx, w.LicenseID, license.StatusExpired)
+}
+
+// activate re-checks the seat limit against the primary's own data.
+func (a *Applier) activate(ctx context.Context, w *Write) error {
+	lic, err := a.licenses.FindByID(ctx, w.LicenseID)
+	if err != nil {
+		return err
+	}
+	maxActive, err := license.OwnSeats(ctx, a.licenses, lic)
+	if err != nil {
+		return err
+	}
+	act := &activation.Activation{
+		ID:        w.ActivationID,
+		LicenseID: w.LicenseID,
+		DeviceID:  w.DeviceID,
+		IP:        w.IP,
+		UserAgent: w.UserAgent,
+	}
+	_, err = a.activations.Activate(ctx, act, maxActive)
+	return err
+}
+
+// heartbeat is dropped when the device was deactivated in the meantime.
+func (a *Applier) heartbeat(ctx context.Context, w *Write) error {
+	hb := &activation.Heartbeat{
+		LicenseID:  w.LicenseID,
+		DeviceID:   w.DeviceID,
+		AppVersion: w.AppVersion,
+		At:         w.At,
+	}
+	if w.Host != nil {
+		hb.Host = *w.Host
+	}
+	_, err := a.activations.Heartbeat(ctx, hb)
+	if errors.Is(err, ierr.ErrNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package region
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+)
+
+// WritesPath is where the primary region accepts forwarded writes.
+const WritesPath = "/api/v1/internal/region/writes"
+
+// Client delivers forwarded writes to the primary region.
+type Client struct {
+	url    string
+	secret string
+	crypto cryptoprovider.Provider
+	http   *http.Client
+}
+
+func NewClient(cfg *config.RegionConfig, crypto cryptoprovider.Provider) *Client {
+	return &Client{
+		url:    strings.TrimRight(cfg.PrimaryURL, "/") + WritesPath,
+		secret: cfg.SharedSecret,
+		crypto: crypto,
+		http:   &http.Client{Timeout: cfg.ForwardTimeout},
+	}
+}
+
+// Send posts payload to the primary. A 4xx answer means the primary
+// refused the write for good, e.g. a seat limit, and is not retried;
+// 401 is, so writes survive the shared secret being rolled out.
+func (c *Client) Send(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(c.crypto, c.secret, payload, time.Now()))
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting to primary region: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+
+	switch {
+	case resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusUnauthorized:
+		return fmt.Errorf("primary region rejected write with %d: %s: %w", resp.StatusCode, body, asynq.SkipRetry)
+	default:
+		return fmt.Errorf("primary region answered %d: %s", resp.StatusCode, body)
+	}
+}
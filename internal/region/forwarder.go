@@ -0,0 +1,57 @@
+package region
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/makkenzo/license-service-api/internal/tasks"
+	"go.uber.org/zap"
+)
+
+// Forwarder queues writes in the local region's Redis; the region forward
+// task then delivers them to the primary, so agents never wait on the
+// primary and a primary outage only delays the writes.
+type Forwarder struct {
+	client *asynq.Client
+	region string
+	logger *zap.Logger
+}
+
+func NewForwarder(client *asynq.Client, region string, logger *zap.Logger) *Forwarder {
+	return &Forwarder{
+		client: client,
+		region: region,
+		logger: logger.Named("RegionForwarder"),
+	}
+}
+
+// Forward queues w. A non-empty dedupKey drops w while an earlier write with
+// the same key is still waiting to be delivered.
+func (f *Forwarder) Forward(ctx context.Context, w *Write, dedupKey string) error {
+	w.Region = f.region
+	if w.At.IsZero() {
+		w.At = time.Now().UTC()
+	}
+	payload, err := json.Marshal(w)
+	if err != nil {
+		return fmt.Errorf("encoding forwarded write: %w", err)
+	}
+
+	var opts []asynq.Option
+	if dedupKey != "" {
+		opts = append(opts, asynq.TaskID("region:"+dedupKey))
+	}
+	if _, err := f.client.EnqueueContext(ctx, tasks.NewRegionForwardTask(payload, opts...)); err != nil {
+		if errors.Is(err, asynq.ErrTaskIDConflict) {
+			return nil
+		}
+		f.logger.Error("Failed to queue write for the primary region", zap.String("op", w.Op), zap.Error(err))
+		return fmt.Errorf("queueing %s for the primary region: %w", w.Op, err)
+	}
+	f.logger.Debug("Queued write for the primary region", zap.String("op", w.Op))
+	return nil
+}
@@ -0,0 +1,256 @@
+package region
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/activation"
+	"github.com/makkenzo/license-service-api/internal/domain/apikey"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+)
+
+// LicenseRepository is the license store of a replica region: reads go to
+// the replica, the writes validation makes are forwarded and everything
+// else is refused.
+type LicenseRepository struct {
+	license.Repository
+	forwarder *Forwarder
+}
+
+func NewLicenseRepository(repo license.Repository, forwarder *Forwarder) *LicenseRepository {
+	return &LicenseRepository{Repository: repo, forwarder: forwarder}
+}
+
+var _ license.Repository = (*LicenseRepository)(nil)
+
+func (r *LicenseRepository) Create(ctx context.Context, lic *license.License) (uuid.UUID, error) {
+	return uuid.Nil, ErrReadOnly
+}
+
+func (r *LicenseRepository) CreateBatch(ctx context.Context, licenses []*license.License) error {
+	return ErrReadOnly
+}
+
+func (r *LicenseRepository) Update(ctx context.Context, lic *license.License) error {
+	return ErrReadOnly
+}
+
+func (r *LicenseRepository) RotateKey(ctx context.Context, id uuid.UUID, newKey func() (string, error), oldKeyValidUntil time.Time) (*license.License, error) {
+	return nil, ErrReadOnly
+}
+
+// UpdateStatus only happens here when validation finds a lapsed license;
+// until replication catches up every validation would repeat it, hence the
+// dedup key.
+func (r *LicenseRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status license.LicenseStatus) error {
+	w := &Write{Op: OpUpdateStatus, LicenseID: id, Status: status}
+	return r.forwarder.Forward(ctx, w, fmt.Sprintf("status:%s:%s", id, status))
+}
+
+// UpdateMetadata forwards only the keys that differ from the replica's
+// copy, so it cannot undo metadata changes made on the primary that have
+// not been replicated yet.
+func (r *LicenseRepository) UpdateMetadata(ctx context.Context, id uuid.UUID, metadata json.RawMessage) error {
+	current, err := r.Repository.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	patch, err := metadataPatch(current.Metadata, metadata)
+	if err != nil {
+		return fmt.Errorf("%w: metadata must be a JSON object: %v", ierr.ErrValidation, err)
+	}
+	if len(patch) == 0 {
+		return nil
+	}
+	return r.forwarder.Forward(ctx, &Write{Op: OpUpdateMetadata, LicenseID: id, MetadataPatch: patch}, "")
+}
+
+// ActivationRepository checks seats against the replica and forwards the
+// activation; the primary checks again when it applies it, so two regions
+// racing for the last seat cannot both keep it.
+type ActivationRepository struct {
+	activation.Repository
+	forwarder *Forwarder
+	ids       idgen.Generator
+}
+
+func NewActivationRepository(repo activation.Repository, forwarder *Forwarder, ids idgen.Generator) *ActivationRepository {
+	return &ActivationRepository{Repository: repo, forwarder: forwarder, ids: ids}
+}
+
+var _ activation.Repository = (*ActivationRepository)(nil)
+
+func (r *ActivationRepository) Activate(ctx context.Context, a *activation.Activation, maxActive int) (bool, error) {
+	active, err := r.Repository.ListActive(ctx, a.LicenseID)
+	if err != nil {
+		return false, err
+	}
+	for _, existing := range active {
+		if existing.DeviceID == a.DeviceID {
+			*a = *existing
+			return false, nil
+		}
+	}
+	if len(active) >= maxActive {
+		return false, fmt.Errorf("%w: %d of %d seat(s) in use", activation.ErrSeatLimitExceeded, len(active), maxActive)
+	}
+
+	// The ID is chosen here so the primary stores the activation under the
+	// one the agent was given.
+	a.ID = r.ids.New()
+	a.ActivatedAt = time.Now().UTC()
+	w := &Write{
+		Op:           OpActivate,
+		At:           a.ActivatedAt,
+		LicenseID:    a.LicenseID,
+		ActivationID: a.ID,
+		DeviceID:     a.DeviceID,
+		IP:           a.IP,
+		UserAgent:    a.UserAgent,
+	}
+	if err := r.forwarder.Forward(ctx, w, ""); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *ActivationRepository) Deactivate(ctx context.Context, licenseID uuid.UUID, deviceID string) (*activation.Activation, error) {
+	active, err := r.Repository.ListActive(ctx, licenseID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range active {
+		if a.DeviceID != deviceID {
+			continue
+		}
+		now := time.Now().UTC()
+		a.DeactivatedAt = &now
+		w := &Write{Op: OpDeactivate, At: now, LicenseID: licenseID, DeviceID: deviceID}
+		if err := r.forwarder.Forward(ctx, w, ""); err != nil {
+			return nil, err
+		}
+		return a, nil
+	}
+	return nil, ierr.ErrNotFound
+}
+
+func (r *ActivationRepository) Revoke(ctx context.Context, licenseID, activationID uuid.UUID) (*activation.Activation, error) {
+	return nil, ErrReadOnly
+}
+
+func (r *ActivationRepository) Heartbeat(ctx context.Context, hb *activation.Heartbeat) (*activation.Activation, error) {
+	active, err := r.Repository.ListActive(ctx, hb.LicenseID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range active {
+		if a.DeviceID != hb.DeviceID {
+			continue
+		}
+		host := hb.Host
+		w := &Write{
+			Op:         OpHeartbeat,
+			At:         hb.At,
+			LicenseID:  hb.LicenseID,
+			DeviceID:   hb.DeviceID,
+			AppVersion: hb.AppVersion,
+			Host:       &host,
+		}
+		if err := r.forwarder.Forward(ctx, w, ""); err != nil {
+			return nil, err
+		}
+		a.LastSeenAt, a.AppVersion, a.Host = &hb.At, hb.AppVersion, hb.Host
+		return a, nil
+	}
+	return nil, ierr.ErrNotFound
+}
+
+func (r *ActivationRepository) ReclaimStale(ctx context.Context, licenseID uuid.UUID, before time.Time) ([]*activation.Activation, error) {
+	return nil, ErrReadOnly
+}
+
+func (r *ActivationRepository) DeleteByLicense(ctx context.Context, licenseID uuid.UUID) (int64, error) {
+	return 0, ErrReadOnly
+}
+
+// apiKeyUsedInterval limits how often the last-used time of one API key is
+// forwarded; every validation bumps it.
+const apiKeyUsedInterval = time.Minute
+
+type APIKeyRepository struct {
+	apikey.Repository
+	forwarder *Forwarder
+
+	mu        sync.Mutex
+	forwarded map[uuid.UUID]time.Time
+}
+
+func NewAPIKeyRepository(repo apikey.Repository, forwarder *Forwarder) *APIKeyRepository {
+	return &APIKeyRepository{Repository: repo, forwarder: forwarder, forwarded: make(map[uuid.UUID]time.Time)}
+}
+
+var _ apikey.Repository = (*APIKeyRepository)(nil)
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *apikey.APIKey) (uuid.UUID, error) {
+	return uuid.Nil, ErrReadOnly
+}
+
+func (r *APIKeyRepository) Disable(ctx context.Context, id uuid.UUID) error {
+	return ErrReadOnly
+}
+
+func (r *APIKeyRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID, lastUsed time.Time) error {
+	r.mu.Lock()
+	if last, ok := r.forwarded[id]; ok && lastUsed.Sub(last) < apiKeyUsedInterval {
+		r.mu.Unlock()
+		return nil
+	}
+	r.forwarded[id] = lastUsed
+	r.mu.Unlock()
+
+	return r.forwarder.Forward(ctx, &Write{Op: OpAPIKeyUsed, At: lastUsed, APIKeyID: id}, "")
+}
+
+// metadataPatch returns the top-level keys of next that differ from
+// current, with null for keys next dropped.
+func metadataPatch(current, next json.RawMessage) (map[string]json.RawMessage, error) {
+	var before, after map[string]json.RawMessage
+	if len(current) > 0 {
+		if err := json.Unmarshal(current, &before); err != nil {
+			before = nil
+		}
+	}
+	if err := json.Unmarshal(next, &after); err != nil {
+		return nil, err
+	}
+
+	patch := make(map[string]json.RawMessage)
+	for key, value := range after {
+		if old, ok := before[key]; !ok || !sameJSON(old, value) {
+			patch[key] = value
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			patch[key] = json.RawMessage("null")
+		}
+	}
+	return patch, nil
+}
+
+// sameJSON compares by value: the replica's copy comes back from JSONB
+// with different spacing than a document encoded by Go.
+func sameJSON(a, b json.RawMessage) bool {
+	var x, y interface{}
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
@@ -0,0 +1,58 @@
+package region
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+)
+
+// SignatureHeader carries "t=<unix seconds>,v1=<hex HMAC>" over
+// "<t>.<body>", keyed with the shared secret of the regions.
+const SignatureHeader = "X-Region-Signature"
+
+// signatureTolerance bounds clock skew and how long a captured request can
+// be replayed. Forwarded writes are idempotent, so a replay within it is
+// harmless.
+const signatureTolerance = 5 * time.Minute
+
+var ErrBadSignature = errors.New("invalid region signature")
+
+func Sign(crypto cryptoprovider.Provider, secret string, body []byte, now time.Time) string {
+	ts := strconv.FormatInt(now.Unix(), 10)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(crypto.MAC([]byte(secret), signedPayload(ts, body)))
+}
+
+func Verify(crypto cryptoprovider.Provider, secret, header string, body []byte, now time.Time) error {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			sig = value
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || sig == "" {
+		return fmt.Errorf("%w: malformed header", ErrBadSignature)
+	}
+	if d := now.Sub(time.Unix(unix, 0)); d > signatureTolerance || d < -signatureTolerance {
+		return fmt.Errorf("%w: timestamp outside tolerance", ErrBadSignature)
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, crypto.MAC([]byte(secret), signedPayload(ts, body))) {
+		return fmt.Errorf("%w: mismatch", ErrBadSignature)
+	}
+	return nil
+}
+
+func signedPayload(ts string, body []byte) []byte {
+	return append([]byte(ts+"."), body...)
+}
@@ -0,0 +1,51 @@
+// Package region lets secondary regions serve agents from a read-only
+// replica of the primary database. Writes agents cause there (activations,
+// heartbeats, metadata, last-used times) are queued locally and forwarded to the
+// primary region, which applies them through its normal repositories.
+package region
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/activation"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+)
+
+var ErrReadOnly = ierr.New("READ_ONLY_REGION", http.StatusServiceUnavailable, "this region is a read-only replica").
+	WithPublicMessage("This region only serves license validation; send changes to the primary region.")
+
+const (
+	OpUpdateMetadata = "license.update_metadata"
+	OpUpdateStatus   = "license.update_status"
+	OpActivate       = "activation.activate"
+	OpDeactivate     = "activation.deactivate"
+	OpHeartbeat      = "activation.heartbeat"
+	OpAPIKeyUsed     = "apikey.update_last_used"
+)
+
+// Write is one forwarded change. Only the fields of its Op are set.
+type Write struct {
+	Op     string    `json:"op"`
+	Region string    `json:"region"`
+	At     time.Time `json:"at"`
+
+	LicenseID uuid.UUID `json:"license_id"`
+	// MetadataPatch holds the top-level metadata keys the replica changed;
+	// null removes a key. The primary merges it into its current metadata,
+	// so keys changed there in the meantime are not overwritten.
+	MetadataPatch map[string]json.RawMessage `json:"metadata_patch,omitempty"`
+	Status        license.LicenseStatus      `json:"status,omitempty"`
+
+	ActivationID uuid.UUID            `json:"activation_id"`
+	DeviceID     string               `json:"device_id,omitempty"`
+	IP           string               `json:"ip,omitempty"`
+	UserAgent    string               `json:"user_agent,omitempty"`
+	AppVersion   string               `json:"app_version,omitempty"`
+	Host         *activation.HostInfo `json:"host,omitempty"`
+
+	APIKeyID uuid.UUID `json:"api_key_id"`
+}
@@ -0,0 +1,108 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/service"
+	licensev1 "github.com/makkenzo/license-service-api/pkg/api/license/v1"
+	"github.com/makkenzo/license-service-api/pkg/api/license/v1/licensev1connect"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// LicenseEventServer serves license.v1.LicenseEventService over Connect,
+// gRPC and gRPC-Web.
+type LicenseEventServer struct {
+	changes *service.ChangeFeedService
+	logger  *zap.Logger
+}
+
+func NewLicenseEventServer(changes *service.ChangeFeedService, logger *zap.Logger) *LicenseEventServer {
+	return &LicenseEventServer{
+		changes: changes,
+		logger:  logger.Named("LicenseEventServer"),
+	}
+}
+
+var _ licensev1connect.LicenseEventServiceHandler = (*LicenseEventServer)(nil)
+
+// Handler serves the procedures of the service. Streams stay open
+// indefinitely, so the server write timeout is lifted for them.
+func (s *LicenseEventServer) Handler() http.Handler {
+	_, h := licensev1connect.NewLicenseEventServiceHandler(s)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Not every writer supports deadlines; the server timeout applies then.
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+		h.ServeHTTP(w, r)
+	})
+}
+
+func (s *LicenseEventServer) SubscribeLicenseEvents(ctx context.Context, req *connect.Request[licensev1.SubscribeLicenseEventsRequest], stream *connect.ServerStream[licensev1.SubscribeLicenseEventsResponse]) error {
+	params := &dto.SubscribeChangesRequest{
+		Since:  req.Msg.GetSince(),
+		Fields: req.Msg.GetFields(),
+	}
+	if productName := req.Msg.GetProductName(); productName != "" {
+		params.ProductName = &productName
+	}
+	if customerID := req.Msg.GetCustomerId(); customerID != "" {
+		params.CustomerID = &customerID
+	}
+
+	err := s.changes.Subscribe(ctx, params, func(change dto.LicenseChange) error {
+		return stream.Send(&licensev1.SubscribeLicenseEventsResponse{Event: licenseEvent(change)})
+	})
+	if ctx.Err() != nil {
+		return nil
+	}
+	s.logger.Warn("License event subscription failed", zap.Error(err))
+	return connectError(err)
+}
+
+var operations = map[string]licensev1.Operation{
+	"insert": licensev1.Operation_OPERATION_INSERT,
+	"update": licensev1.Operation_OPERATION_UPDATE,
+	"delete": licensev1.Operation_OPERATION_DELETE,
+}
+
+func licenseEvent(change dto.LicenseChange) *licensev1.LicenseEvent {
+	return &licensev1.LicenseEvent{
+		Version:       change.Version,
+		LicenseId:     change.ID.String(),
+		Operation:     operations[change.Operation],
+		ChangedFields: change.Fields,
+		ChangedAt:     timestamppb.New(change.ChangedAt),
+	}
+}
+
+// connectError reports err with the Connect code matching the HTTP status
+// the REST API would use, and the same public message.
+func connectError(err error) error {
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return connectErr
+	}
+	status, _, message := ierr.Describe(err)
+	code := connect.CodeInternal
+	switch status {
+	case http.StatusBadRequest:
+		code = connect.CodeInvalidArgument
+	case http.StatusUnauthorized:
+		code = connect.CodeUnauthenticated
+	case http.StatusForbidden:
+		code = connect.CodePermissionDenied
+	case http.StatusNotFound:
+		code = connect.CodeNotFound
+	case http.StatusConflict:
+		code = connect.CodeFailedPrecondition
+	case http.StatusServiceUnavailable:
+		code = connect.CodeUnavailable
+	}
+	return connect.NewError(code, errors.New(message))
+}
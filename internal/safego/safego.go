@@ -0,0 +1,34 @@
+// Package safego runs asynchronous work so that a panic is logged and
+// counted instead of taking the whole process down.
+package safego
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var panicsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "background_task_panics_total",
+	Help: "Panics recovered in background goroutines and tasks.",
+}, []string{"task"})
+
+// Go starts fn in a new goroutine guarded by Run.
+func Go(logger *zap.Logger, name string, fn func()) {
+	go Run(logger, name, fn)
+}
+
+// Run calls fn on the current goroutine and recovers a panic from it. It
+// reports whether fn returned normally.
+func Run(logger *zap.Logger, name string, fn func()) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicsTotal.WithLabelValues(name).Inc()
+			logger.Error("Background task panicked", zap.String("task", name), zap.Any("panic", r), zap.Stack("stack"))
+			ok = false
+		}
+	}()
+
+	fn()
+	return true
+}
@@ -0,0 +1,226 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/outbox"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+const outboxConsumer = "search_indexer"
+
+var licenseMapping = []byte(`{
+  "mappings": {
+    "properties": {
+      "license_key":    {"type": "search_as_you_type"},
+      "status":         {"type": "keyword"},
+      "type":           {"type": "keyword"},
+      "customer_name":  {"type": "search_as_you_type"},
+      "customer_email": {"type": "search_as_you_type"},
+      "product_name":   {"type": "text", "fields": {"raw": {"type": "keyword"}}},
+      "metadata":       {"type": "flattened"},
+      "issued_at":      {"type": "date"},
+      "expires_at":     {"type": "date"},
+      "created_at":     {"type": "date"},
+      "updated_at":     {"type": "date"}
+    }
+  }
+}`)
+
+var customerMapping = []byte(`{
+  "mappings": {
+    "properties": {
+      "email":      {"type": "search_as_you_type"},
+      "name":       {"type": "search_as_you_type"},
+      "updated_at": {"type": "date"}
+    }
+  }
+}`)
+
+type licenseDocument struct {
+	LicenseKey    string      `json:"license_key"`
+	Status        string      `json:"status"`
+	Type          string      `json:"type"`
+	CustomerName  *string     `json:"customer_name,omitempty"`
+	CustomerEmail *string     `json:"customer_email,omitempty"`
+	ProductName   string      `json:"product_name"`
+	Metadata      interface{} `json:"metadata,omitempty"`
+	IssuedAt      *time.Time  `json:"issued_at,omitempty"`
+	ExpiresAt     *time.Time  `json:"expires_at,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+type customerDocument struct {
+	Email     string    `json:"email"`
+	Name      *string   `json:"name,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Indexer mirrors licenses, and the customers derived from them, into
+// OpenSearch by tailing the license outbox.
+type Indexer struct {
+	outbox       outbox.Repository
+	licenses     license.Repository
+	client       *Client
+	licenseIndex string
+	customerIdx  string
+	batchSize    int
+	pollInterval time.Duration
+	logger       *zap.Logger
+}
+
+func NewIndexer(cfg *config.SearchConfig, outboxRepo outbox.Repository, licenses license.Repository, client *Client, logger *zap.Logger) *Indexer {
+	return &Indexer{
+		outbox:       outboxRepo,
+		licenses:     licenses,
+		client:       client,
+		licenseIndex: cfg.IndexPrefix + "-licenses",
+		customerIdx:  cfg.IndexPrefix + "-customers",
+		batchSize:    cfg.BatchSize,
+		pollInterval: cfg.PollInterval,
+		logger:       logger.Named("SearchIndexer"),
+	}
+}
+
+// Run polls the outbox until ctx is cancelled. Failed batches are retried on
+// the next tick since the cursor only advances after a successful bulk write.
+func (ix *Indexer) Run(ctx context.Context) error {
+	if err := ix.client.EnsureIndex(ctx, ix.licenseIndex, licenseMapping); err != nil {
+		return err
+	}
+	if err := ix.client.EnsureIndex(ctx, ix.customerIdx, customerMapping); err != nil {
+		return err
+	}
+	ix.logger.Info("Search indexer started", zap.String("license_index", ix.licenseIndex), zap.String("customer_index", ix.customerIdx))
+
+	ticker := time.NewTicker(ix.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := ix.RunOnce(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					ix.logger.Error("Search indexing batch failed", zap.Error(err))
+				}
+				break
+			}
+			if n < ix.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			ix.logger.Info("Search indexer stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce indexes the next batch of outbox events and returns how many
+// events it consumed.
+func (ix *Indexer) RunOnce(ctx context.Context) (int, error) {
+	cursor, err := ix.outbox.GetCursor(ctx, outboxConsumer)
+	if err != nil {
+		return 0, err
+	}
+
+	events, err := ix.outbox.ListAfter(ctx, outbox.Query{AfterID: cursor, Limit: ix.batchSize})
+	if err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	seen := make(map[uuid.UUID]bool, len(events))
+	actions := make([]BulkAction, 0, len(events))
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		if seen[event.LicenseID] {
+			continue
+		}
+		seen[event.LicenseID] = true
+
+		built, err := ix.actionsFor(ctx, event)
+		if err != nil {
+			return 0, err
+		}
+		actions = append(actions, built...)
+	}
+
+	if err := ix.client.Bulk(ctx, actions); err != nil {
+		return 0, fmt.Errorf("failed to index outbox batch: %w", err)
+	}
+
+	lastID := events[len(events)-1].ID
+	if err := ix.outbox.SaveCursor(ctx, outboxConsumer, lastID); err != nil {
+		return 0, err
+	}
+
+	ix.logger.Debug("Indexed outbox batch", zap.Int("events", len(events)), zap.Int("actions", len(actions)), zap.Int64("cursor", lastID))
+	return len(events), nil
+}
+
+func (ix *Indexer) actionsFor(ctx context.Context, event *outbox.Event) ([]BulkAction, error) {
+	deleteAction := []BulkAction{{Index: ix.licenseIndex, ID: event.LicenseID.String()}}
+	if event.Operation == outbox.OperationDelete {
+		return deleteAction, nil
+	}
+
+	lic, err := ix.licenses.FindByID(ctx, event.LicenseID)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return deleteAction, nil
+		}
+		return nil, fmt.Errorf("failed to load license %s for indexing: %w", event.LicenseID, err)
+	}
+
+	doc := licenseDocument{
+		LicenseKey:  lic.LicenseKey,
+		Status:      string(lic.Status),
+		Type:        lic.Type,
+		ProductName: lic.ProductName,
+		CreatedAt:   lic.CreatedAt,
+		UpdatedAt:   lic.UpdatedAt,
+	}
+	if lic.CustomerName.Valid {
+		doc.CustomerName = &lic.CustomerName.String
+	}
+	if lic.CustomerEmail.Valid {
+		doc.CustomerEmail = &lic.CustomerEmail.String
+	}
+	if lic.IssuedAt.Valid {
+		doc.IssuedAt = &lic.IssuedAt.Time
+	}
+	if lic.ExpiresAt.Valid {
+		doc.ExpiresAt = &lic.ExpiresAt.Time
+	}
+	var meta map[string]interface{}
+	if err := lic.GetMetadata(&meta); err == nil && len(meta) > 0 {
+		doc.Metadata = meta
+	}
+
+	actions := []BulkAction{{Index: ix.licenseIndex, ID: lic.ID.String(), Doc: doc}}
+
+	if lic.CustomerEmail.Valid && lic.CustomerEmail.String != "" {
+		email := strings.ToLower(lic.CustomerEmail.String)
+		actions = append(actions, BulkAction{
+			Index: ix.customerIdx,
+			ID:    email,
+			Doc:   customerDocument{Email: email, Name: doc.CustomerName, UpdatedAt: lic.UpdatedAt},
+		})
+	}
+	return actions, nil
+}
@@ -0,0 +1,139 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+)
+
+// Client is a minimal OpenSearch/Elasticsearch client covering the bulk and
+// index APIs the indexer needs.
+type Client struct {
+	baseURL  string
+	username string
+	password string
+	http     *http.Client
+}
+
+func NewClient(cfg *config.SearchConfig) *Client {
+	return &Client{
+		baseURL:  strings.TrimRight(cfg.URL, "/"),
+		username: cfg.Username,
+		password: cfg.Password,
+		http:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type BulkAction struct {
+	Index string
+	ID    string
+	// Doc is indexed as a full replacement; a nil Doc deletes the document.
+	Doc interface{}
+}
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		ID     string `json:"_id"`
+		Status int    `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+func (c *Client) Bulk(ctx context.Context, actions []BulkAction) error {
+	if len(actions) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, a := range actions {
+		op := "index"
+		if a.Doc == nil {
+			op = "delete"
+		}
+		if err := enc.Encode(map[string]map[string]string{op: {"_index": a.Index, "_id": a.ID}}); err != nil {
+			return fmt.Errorf("failed to encode bulk action: %w", err)
+		}
+		if a.Doc != nil {
+			if err := enc.Encode(a.Doc); err != nil {
+				return fmt.Errorf("failed to encode bulk document %s/%s: %w", a.Index, a.ID, err)
+			}
+		}
+	}
+
+	respBody, err := c.do(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", &body)
+	if err != nil {
+		return err
+	}
+
+	var resp bulkResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return fmt.Errorf("failed to decode bulk response: %w", err)
+	}
+	if !resp.Errors {
+		return nil
+	}
+	for _, item := range resp.Items {
+		for op, result := range item {
+			// Deleting a document that was never indexed is fine.
+			if op == "delete" && result.Status == http.StatusNotFound {
+				continue
+			}
+			if result.Error != nil {
+				return fmt.Errorf("bulk %s of %s failed: %s: %s", op, result.ID, result.Error.Type, result.Error.Reason)
+			}
+		}
+	}
+	return nil
+}
+
+// EnsureIndex creates index with the given mapping unless it already exists.
+func (c *Client) EnsureIndex(ctx context.Context, index string, mapping []byte) error {
+	_, err := c.do(ctx, http.MethodHead, "/"+index, "", nil)
+	if err == nil {
+		return nil
+	}
+	if _, err := c.do(ctx, http.MethodPut, "/"+index, "application/json", bytes.NewReader(mapping)); err != nil {
+		return fmt.Errorf("failed to create index %s: %w", index, err)
+	}
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, method, path, contentType string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build search request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("search request %s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read search response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("search request %s %s returned %d: %s", method, path, resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+	return respBody, nil
+}
@@ -0,0 +1,29 @@
+package servertiming
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// QueryTracer is a pgx tracer adding the time of every query to the db
+// metric of the request that ran it.
+type QueryTracer struct{}
+
+var _ pgx.QueryTracer = QueryTracer{}
+
+type queryStartKey struct{}
+
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	if FromContext(ctx) == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, queryStartKey{}, time.Now())
+}
+
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryEndData) {
+	if start, ok := ctx.Value(queryStartKey{}).(time.Time); ok {
+		FromContext(ctx).Add(MetricDB, time.Since(start))
+	}
+}
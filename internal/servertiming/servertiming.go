@@ -0,0 +1,101 @@
+// Package servertiming collects where the time of a request goes and
+// reports it in a Server-Timing response header, which browser developer
+// tools show next to the request. Nothing is collected unless the request
+// context carries Timings, so the hooks in the database and cache layers
+// cost next to nothing when the header is off.
+package servertiming
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const HeaderName = "Server-Timing"
+
+// Metrics reported in the header. They overlap: policy includes the
+// database and cache calls the license checks make, and total is the whole
+// request up to the first byte of the response.
+const (
+	MetricDB     = "db"
+	MetricCache  = "cache"
+	MetricPolicy = "policy"
+	MetricTotal  = "total"
+)
+
+var metricOrder = []string{MetricDB, MetricCache, MetricPolicy}
+
+// Timings accumulates the time of one request per metric. It is safe for
+// concurrent use by the goroutines serving the request.
+type Timings struct {
+	start     time.Time
+	mu        sync.Mutex
+	durations map[string]time.Duration
+	counts    map[string]int
+}
+
+func New(start time.Time) *Timings {
+	return &Timings{
+		start:     start,
+		durations: make(map[string]time.Duration),
+		counts:    make(map[string]int),
+	}
+}
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, t *Timings) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the Timings attached by NewContext, or nil.
+func FromContext(ctx context.Context) *Timings {
+	t, _ := ctx.Value(contextKey{}).(*Timings)
+	return t
+}
+
+func (t *Timings) Add(metric string, d time.Duration) {
+	t.mu.Lock()
+	t.durations[metric] += d
+	t.counts[metric]++
+	t.mu.Unlock()
+}
+
+// Track times one call for metric of the request of ctx; call the returned
+// function when it is done.
+func Track(ctx context.Context, metric string) func() {
+	t := FromContext(ctx)
+	if t == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() { t.Add(metric, time.Since(start)) }
+}
+
+// Header formats the metrics that were recorded, followed by total up to
+// now, e.g. `db;desc="3 calls";dur=12.5, total;dur=20.1`.
+func (t *Timings) Header(now time.Time) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	parts := make([]string, 0, len(metricOrder)+1)
+	for _, metric := range metricOrder {
+		n := t.counts[metric]
+		if n == 0 {
+			continue
+		}
+		calls := "calls"
+		if n == 1 {
+			calls = "call"
+		}
+		parts = append(parts, fmt.Sprintf(`%s;desc="%d %s";dur=%s`, metric, n, calls, millis(t.durations[metric])))
+	}
+	parts = append(parts, MetricTotal+";dur="+millis(now.Sub(t.start)))
+	return strings.Join(parts, ", ")
+}
+
+func millis(d time.Duration) string {
+	return fmt.Sprintf("%.1f", float64(d.Microseconds())/1000)
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/activation"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+var errActivationLicenseNotFound = fmt.Errorf("%w: license not found for this product", ierr.ErrNotFound)
+
+type ActivationService struct {
+	activations activation.Repository
+	licenses    license.Repository
+	heartbeat   *config.HeartbeatConfig
+	logger      *zap.Logger
+}
+
+func NewActivationService(activations activation.Repository, licenses license.Repository, heartbeat *config.HeartbeatConfig, logger *zap.Logger) *ActivationService {
+	return &ActivationService{
+		activations: activations,
+		licenses:    licenses,
+		heartbeat:   heartbeat,
+		logger:      logger.Named("ActivationService"),
+	}
+}
+
+// Activate binds the license to the device. Activating an already bound
+// device is idempotent and reports created=false.
+func (s *ActivationService) Activate(ctx context.Context, req *dto.ActivationRequest, ip, userAgent string) (*activation.Activation, bool, error) {
+	lic, err := s.findLicense(ctx, req.LicenseKey, req.ProductName)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := checkActivatable(lic); err != nil {
+		return nil, false, err
+	}
+	maxActive, err := license.OwnSeats(ctx, s.licenses, lic)
+	if err != nil {
+		return nil, false, err
+	}
+
+	a := &activation.Activation{
+		LicenseID: lic.ID,
+		DeviceID:  req.DeviceID,
+		IP:        ip,
+		UserAgent: userAgent,
+	}
+	created, err := s.activations.Activate(ctx, a, maxActive)
+	if errors.Is(err, activation.ErrSeatLimitExceeded) && s.heartbeat.AutoReclaim && s.reclaimForActivation(ctx, lic.ID) {
+		created, err = s.activations.Activate(ctx, a, maxActive)
+	}
+	if err != nil {
+		if errors.Is(err, activation.ErrSeatLimitExceeded) {
+			s.logger.Info("Activation rejected, no free seats",
+				zap.String("license_id", lic.ID.String()),
+				zap.String("device_id", req.DeviceID),
+				zap.Int("max_activations", maxActive),
+			)
+			return nil, false, err
+		}
+		return nil, false, fmt.Errorf("repository error activating license %s: %w", lic.ID, err)
+	}
+
+	if created {
+		s.logger.Info("License activated", zap.String("license_id", lic.ID.String()), zap.String("device_id", req.DeviceID))
+	}
+	return a, created, nil
+}
+
+func (s *ActivationService) Deactivate(ctx context.Context, req *dto.ActivationRequest) (*activation.Activation, error) {
+	lic, err := s.findLicense(ctx, req.LicenseKey, req.ProductName)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := s.activations.Deactivate(ctx, lic.ID, req.DeviceID)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, fmt.Errorf("%w: device is not activated for this license", ierr.ErrNotFound)
+		}
+		return nil, fmt.Errorf("repository error deactivating license %s: %w", lic.ID, err)
+	}
+
+	s.logger.Info("License deactivated", zap.String("license_id", lic.ID.String()), zap.String("device_id", req.DeviceID))
+	return a, nil
+}
+
+// reclaimForActivation frees the stale seats of a full license and reports
+// whether any were freed. Failures only leave the seats taken.
+func (s *ActivationService) reclaimForActivation(ctx context.Context, licenseID uuid.UUID) bool {
+	reclaimed, err := s.activations.ReclaimStale(ctx, licenseID, time.Now().Add(-s.heartbeat.StaleAfter))
+	if err != nil {
+		s.logger.Warn("Failed to reclaim stale activations", zap.String("license_id", licenseID.String()), zap.Error(err))
+		return false
+	}
+	for _, a := range reclaimed {
+		s.logger.Info("Stale activation reclaimed",
+			zap.String("license_id", licenseID.String()),
+			zap.String("activation_id", a.ID.String()),
+			zap.String("device_id", a.DeviceID),
+			zap.Time("last_activity", a.LastActivity()),
+		)
+	}
+	return len(reclaimed) > 0
+}
+
+// Heartbeat records that the agent on an activated device is still running.
+// Devices that are not activated get not found, so the agent knows to
+// activate again.
+func (s *ActivationService) Heartbeat(ctx context.Context, req *dto.HeartbeatRequest) (*activation.Activation, error) {
+	lic, err := s.findLicense(ctx, req.LicenseKey, req.ProductName)
+	if err != nil {
+		return nil, err
+	}
+
+	hb := &activation.Heartbeat{
+		LicenseID:  lic.ID,
+		DeviceID:   req.DeviceID,
+		AppVersion: req.AppVersion,
+		At:         time.Now().UTC(),
+	}
+	if req.Host != nil {
+		hb.Host = activation.HostInfo(*req.Host)
+	}
+	a, err := s.activations.Heartbeat(ctx, hb)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, fmt.Errorf("%w: device is not activated for this license", ierr.ErrNotFound)
+		}
+		return nil, fmt.Errorf("repository error recording heartbeat for license %s: %w", lic.ID, err)
+	}
+	return a, nil
+}
+
+// ReclaimStale deactivates the activations of a license that have not been
+// seen for staleAfter, or HEARTBEAT_STALEAFTER when it is zero.
+func (s *ActivationService) ReclaimStale(ctx context.Context, licenseID uuid.UUID, staleAfter time.Duration) ([]*activation.Activation, time.Time, error) {
+	if staleAfter <= 0 {
+		staleAfter = s.heartbeat.StaleAfter
+	}
+	before := time.Now().UTC().Add(-staleAfter)
+
+	if _, err := s.licenses.FindByID(ctx, licenseID); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, before, err
+		}
+		return nil, before, fmt.Errorf("repository error finding license %s: %w", licenseID, err)
+	}
+
+	reclaimed, err := s.activations.ReclaimStale(ctx, licenseID, before)
+	if err != nil {
+		return nil, before, fmt.Errorf("repository error reclaiming activations of license %s: %w", licenseID, err)
+	}
+
+	s.logger.Info("Stale activations reclaimed",
+		zap.String("license_id", licenseID.String()),
+		zap.Time("stale_before", before),
+		zap.Int("count", len(reclaimed)),
+	)
+	return reclaimed, before, nil
+}
+
+// ListActivations returns the activations of a license together with the
+// license itself, so callers can report seat usage.
+func (s *ActivationService) ListActivations(ctx context.Context, licenseID uuid.UUID, includeInactive bool) (*dto.ActivationListResponse, error) {
+	lic, err := s.licenses.FindByID(ctx, licenseID)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error finding license %s: %w", licenseID, err)
+	}
+
+	activations, err := s.activations.List(ctx, licenseID, includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing activations of license %s: %w", licenseID, err)
+	}
+	maxActive, err := license.OwnSeats(ctx, s.licenses, lic)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewActivationListResponse(lic.ID, maxActive, activations), nil
+}
+
+// RevokeActivation frees the seat held by one activation, for example a
+// device that was lost and can no longer deactivate itself.
+func (s *ActivationService) RevokeActivation(ctx context.Context, licenseID, activationID uuid.UUID) (*activation.Activation, error) {
+	a, err := s.activations.Revoke(ctx, licenseID, activationID)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, fmt.Errorf("%w: no active activation %s for license %s", ierr.ErrNotFound, activationID, licenseID)
+		}
+		return nil, fmt.Errorf("repository error revoking activation %s: %w", activationID, err)
+	}
+
+	s.logger.Info("Activation revoked",
+		zap.String("license_id", licenseID.String()),
+		zap.String("activation_id", activationID.String()),
+		zap.String("device_id", a.DeviceID),
+	)
+	return a, nil
+}
+
+// UsageSummary reports how many seats of a license are in use out of how
+// many it is entitled to. Like the other agent-facing calls it hides
+// licenses of other products.
+func (s *ActivationService) UsageSummary(ctx context.Context, key, productName string) (*dto.UsageSummaryResponse, error) {
+	lic, err := s.findLicense(ctx, key, productName)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.activations.ListActive(ctx, lic.ID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing activations of license %s: %w", lic.ID, err)
+	}
+	maxActive, err := license.OwnSeats(ctx, s.licenses, lic)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewUsageSummaryResponse(lic, len(active), maxActive, time.Now().UTC()), nil
+}
+
+func checkActivatable(lic *license.License) error {
+	if lic.Floating {
+		return fmt.Errorf("%w: floating licenses are checked out, not activated", ierr.ErrConflict)
+	}
+	if lic.Status != license.StatusActive || lic.Lapsed(time.Now()) {
+		return fmt.Errorf("%w: license is not active", ierr.ErrConflict)
+	}
+	return nil
+}
+
+// findLicense reports a product mismatch as not found so agents cannot probe
+// keys of other products.
+func (s *ActivationService) findLicense(ctx context.Context, key, productName string) (*license.License, error) {
+	lic, err := s.licenses.FindByKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, errActivationLicenseNotFound
+		}
+		return nil, fmt.Errorf("repository error finding license by key: %w", err)
+	}
+	if lic.ProductName != productName {
+		return nil, errActivationLicenseNotFound
+	}
+	return lic, nil
+}
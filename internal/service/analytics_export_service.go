@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+	"github.com/makkenzo/license-service-api/internal/domain/analytics"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/objectstore"
+	"github.com/makkenzo/license-service-api/internal/tabular"
+	"go.uber.org/zap"
+)
+
+var ErrAnalyticsExportDisabled = ierr.New("ANALYTICS_EXPORT_DISABLED", http.StatusServiceUnavailable, "analytics export is disabled or not configured").
+	WithPublicMessage("The analytics export is not configured on this server.")
+
+// licenseActivityColumns is the layout of the license_activity dataset.
+// Columns may be added at the end; renaming or removing one breaks the
+// queries of data teams.
+var licenseActivityColumns = []tabular.Column{
+	{Name: "day", Type: tabular.Date},
+	{Name: "license_pseudonym", Type: tabular.String},
+	{Name: "product_name", Type: tabular.String},
+	{Name: "validations", Type: tabular.Int64},
+	{Name: "failed_validations", Type: tabular.Int64},
+	{Name: "distinct_devices", Type: tabular.Int64},
+	{Name: "countries", Type: tabular.Int64},
+	{Name: "devices_activated", Type: tabular.Int64},
+	{Name: "devices_deactivated", Type: tabular.Int64},
+	{Name: "active_devices", Type: tabular.Int64},
+}
+
+// AnalyticsExportService exports daily license activity for data teams
+// without customer identities: licenses appear under a keyed pseudonym
+// that stays the same from day to day, and nothing about customers, keys,
+// devices or IP addresses leaves the service.
+type AnalyticsExportService struct {
+	repo   analytics.Repository
+	store  objectstore.Store
+	crypto cryptoprovider.Provider
+	cfg    *config.AnalyticsExportConfig
+	logger *zap.Logger
+}
+
+// NewAnalyticsExportService takes a nil store when no bucket is configured.
+func NewAnalyticsExportService(repo analytics.Repository, store objectstore.Store, crypto cryptoprovider.Provider, cfg *config.AnalyticsExportConfig, logger *zap.Logger) *AnalyticsExportService {
+	return &AnalyticsExportService{
+		repo:   repo,
+		store:  store,
+		crypto: crypto,
+		cfg:    cfg,
+		logger: logger.Named("AnalyticsExportService"),
+	}
+}
+
+// Active reports whether exports can run.
+func (s *AnalyticsExportService) Active() bool {
+	return s.cfg.Enabled && s.store != nil && s.cfg.PseudonymSecret != ""
+}
+
+// ExportPreviousDay exports yesterday, UTC. The worker runs it on schedule.
+func (s *AnalyticsExportService) ExportPreviousDay(ctx context.Context) error {
+	_, err := s.Export(ctx, time.Now().UTC().AddDate(0, 0, -1))
+	return err
+}
+
+// Run exports the day asked for, yesterday by default.
+func (s *AnalyticsExportService) Run(ctx context.Context, req *dto.RunAnalyticsExportRequest) (*dto.AnalyticsExportResponse, error) {
+	day := time.Now().UTC().AddDate(0, 0, -1)
+	if req.Date != "" {
+		parsed, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid date %q", ierr.ErrValidation, req.Date)
+		}
+		day = parsed
+	}
+	return s.Export(ctx, day)
+}
+
+// Export writes the license activity of the UTC day of day and uploads it,
+// replacing an earlier export of the same day.
+func (s *AnalyticsExportService) Export(ctx context.Context, day time.Time) (*dto.AnalyticsExportResponse, error) {
+	if !s.Active() {
+		return nil, ErrAnalyticsExportDisabled
+	}
+	y, m, d := day.UTC().Date()
+	from := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	if to.After(time.Now()) {
+		return nil, fmt.Errorf("%w: only days that are over can be exported", ierr.ErrValidation)
+	}
+
+	format := strings.ToLower(s.cfg.Format)
+	file, err := os.CreateTemp("", "license-activity-*."+format)
+	if err != nil {
+		return nil, fmt.Errorf("creating export file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+
+	w, err := tabular.NewWriter(format, file, licenseActivityColumns)
+	if err != nil {
+		return nil, err
+	}
+	var rows int64
+	err = s.repo.LicenseActivity(ctx, from, to, func(a *analytics.LicenseActivity) error {
+		rows++
+		return w.WriteRow(from, s.pseudonym(a.LicenseID), a.ProductName, a.Validations, a.FailedValidations,
+			a.DistinctDevices, a.Countries, a.DevicesActivated, a.DevicesDeactivated, a.ActiveDevices)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repository error aggregating license activity: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("writing export file: %w", err)
+	}
+
+	date := from.Format("2006-01-02")
+	key := fmt.Sprintf("%slicense_activity/dt=%s/license_activity.%s", s.cfg.Prefix, date, format)
+	if err := s.store.Put(ctx, key, file, tabular.ContentType(format)); err != nil {
+		s.logger.Error("Failed to upload analytics export", zap.String("key", key), zap.Error(err))
+		return nil, fmt.Errorf("uploading analytics export: %w", err)
+	}
+
+	s.logger.Info("Analytics export uploaded", zap.String("date", date), zap.String("key", key), zap.Int64("rows", rows))
+	return &dto.AnalyticsExportResponse{
+		Date:      date,
+		Bucket:    s.cfg.S3.Bucket,
+		ObjectKey: key,
+		Format:    format,
+		Rows:      rows,
+	}, nil
+}
+
+// pseudonym is the first 128 bits of the HMAC of the license ID under the
+// configured secret: stable across exports, but not reversible or
+// guessable without the secret.
+func (s *AnalyticsExportService) pseudonym(id uuid.UUID) string {
+	return hex.EncodeToString(s.crypto.MAC([]byte(s.cfg.PseudonymSecret), id[:])[:16])
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/apikey"
+	"github.com/makkenzo/license-service-api/internal/domain/apiusage"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/tabular"
+	"go.uber.org/zap"
+)
+
+const usageStatementMonthLayout = "2006-01"
+
+// usageStatementColumns is the layout of the usage statement export, one
+// row per organization, API and API key. Billing systems import it, so
+// columns may only be added at the end.
+var usageStatementColumns = []tabular.Column{
+	{Name: "month", Type: tabular.String},
+	{Name: "org_id", Type: tabular.String},
+	{Name: "api", Type: tabular.String},
+	{Name: "api_key_id", Type: tabular.String},
+	{Name: "api_key_prefix", Type: tabular.String},
+	{Name: "calls", Type: tabular.Int64},
+	{Name: "failed_calls", Type: tabular.Int64},
+}
+
+// APIUsageService reports the metered API calls of organizations, see
+// metering.Meter. Calls of the last flush interval are not included yet.
+type APIUsageService struct {
+	repo   apiusage.Repository
+	keys   apikey.Repository
+	logger *zap.Logger
+}
+
+func NewAPIUsageService(repo apiusage.Repository, keys apikey.Repository, logger *zap.Logger) *APIUsageService {
+	return &APIUsageService{
+		repo:   repo,
+		keys:   keys,
+		logger: logger.Named("APIUsageService"),
+	}
+}
+
+func (s *APIUsageService) Statement(ctx context.Context, req *dto.UsageStatementRequest) (*dto.UsageStatementResponse, error) {
+	month, err := statementMonth(req.Month)
+	if err != nil {
+		return nil, err
+	}
+	counters, err := s.repo.ListMonth(ctx, month, req.OrgID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing API usage: %w", err)
+	}
+	keys, err := s.keysByID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.UsageStatementResponse{Month: month.Format(usageStatementMonthLayout), Organizations: []*dto.OrgUsageStatement{}}
+	var org *dto.OrgUsageStatement
+	for _, c := range counters {
+		if org == nil || org.OrgID != c.OrgID {
+			org = &dto.OrgUsageStatement{OrgID: c.OrgID, Keys: []*dto.APIKeyUsage{}}
+			resp.Organizations = append(resp.Organizations, org)
+		}
+		switch c.API {
+		case apiusage.APIAdmin:
+			org.AdminCalls += c.Calls
+			org.AdminFailedCalls += c.FailedCalls
+		case apiusage.APIValidate:
+			org.ValidateCalls += c.Calls
+			org.ValidateFailedCalls += c.FailedCalls
+			usage := &dto.APIKeyUsage{APIKeyID: c.APIKeyID, Calls: c.Calls, FailedCalls: c.FailedCalls}
+			if key := keys[c.APIKeyID]; key != nil {
+				usage.Prefix, usage.Description = key.Prefix, key.Description
+			}
+			org.Keys = append(org.Keys, usage)
+		}
+	}
+	return resp, nil
+}
+
+// ExportStatement writes the counters of the statement as CSV.
+func (s *APIUsageService) ExportStatement(ctx context.Context, req *dto.UsageStatementRequest, out io.Writer) error {
+	month, err := statementMonth(req.Month)
+	if err != nil {
+		return err
+	}
+	counters, err := s.repo.ListMonth(ctx, month, req.OrgID)
+	if err != nil {
+		return fmt.Errorf("repository error listing API usage: %w", err)
+	}
+	keys, err := s.keysByID(ctx)
+	if err != nil {
+		return err
+	}
+
+	w, err := tabular.NewWriter(tabular.FormatCSV, out, usageStatementColumns)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ierr.ErrInternalServer, err)
+	}
+	monthStr := month.Format(usageStatementMonthLayout)
+	for _, c := range counters {
+		var keyID, prefix string
+		if c.APIKeyID != uuid.Nil {
+			keyID = c.APIKeyID.String()
+			if key := keys[c.APIKeyID]; key != nil {
+				prefix = key.Prefix
+			}
+		}
+		if err := w.WriteRow(monthStr, c.OrgID, c.API, keyID, prefix, c.Calls, c.FailedCalls); err != nil {
+			return fmt.Errorf("writing usage statement: %w", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("writing usage statement: %w", err)
+	}
+	s.logger.Info("Usage statement exported", zap.String("month", monthStr), zap.Int("rows", len(counters)))
+	return nil
+}
+
+func (s *APIUsageService) keysByID(ctx context.Context) (map[uuid.UUID]*apikey.APIKey, error) {
+	keys, err := s.keys.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing api keys: %w", err)
+	}
+	byID := make(map[uuid.UUID]*apikey.APIKey, len(keys))
+	for _, key := range keys {
+		byID[key.ID] = key
+	}
+	return byID, nil
+}
+
+func statementMonth(month string) (time.Time, error) {
+	if month == "" {
+		return apiusage.MonthOf(time.Now()), nil
+	}
+	t, err := time.Parse(usageStatementMonthLayout, month)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: month must be formatted as YYYY-MM", ierr.ErrValidation)
+	}
+	return t, nil
+}
@@ -2,32 +2,82 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/authz"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
 	"github.com/makkenzo/license-service-api/internal/domain/apikey"
+	"github.com/makkenzo/license-service-api/internal/domain/product"
 	"github.com/makkenzo/license-service-api/internal/handler/dto"
 	"github.com/makkenzo/license-service-api/internal/ierr"
 	"github.com/makkenzo/license-service-api/internal/util"
 	"go.uber.org/zap"
 )
 
+var ErrAPIKeyScopeForbidden = ierr.ErrForbidden.Derive("API_KEY_SCOPE_FORBIDDEN", "the user's roles do not allow creating api keys with this scope").WithDetails()
+
 type APIKeyService struct {
-	repo   apikey.Repository
-	logger *zap.Logger
+	repo     apikey.Repository
+	products product.Repository
+	crypto   cryptoprovider.Provider
+	authz    *authz.Enforcer
+	logger   *zap.Logger
 }
 
-func NewAPIKeyService(repo apikey.Repository, logger *zap.Logger) *APIKeyService {
+func NewAPIKeyService(repo apikey.Repository, products product.Repository, crypto cryptoprovider.Provider, authz *authz.Enforcer, logger *zap.Logger) *APIKeyService {
 	return &APIKeyService{
-		repo:   repo,
-		logger: logger.Named("APIKeyService"),
+		repo:     repo,
+		products: products,
+		crypto:   crypto,
+		authz:    authz,
+		logger:   logger.Named("APIKeyService"),
 	}
 }
 
-func (s *APIKeyService) CreateAPIKey(ctx context.Context, description string, productID *uuid.UUID) (*dto.CreateAPIKeyResponse, string, error) {
+// CreateAPIKey creates a key with scopes, or with all scopes if none are
+// given. The authorization policy decides which roles may grant each scope;
+// the key records who created it and calls made with it are billed to the
+// creator's organization.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, description string, productID *uuid.UUID, scopes []string, claims *ZitadelClaims) (*dto.CreateAPIKeyResponse, string, error) {
 	s.logger.Info("Generating new API key", zap.String("description", description))
 
-	fullKey, prefix, keyHash, err := util.GenerateAPIKey()
+	if claims == nil || claims.Subject == "" {
+		return nil, "", ierr.ErrUnauthorized
+	}
+	for _, scope := range scopes {
+		if !apikey.ValidScope(scope) {
+			return nil, "", fmt.Errorf("%w: unknown scope %q", ierr.ErrValidation, scope)
+		}
+	}
+	if len(scopes) > 0 {
+		// Dedupe and keep the documented order.
+		requested := scopes
+		scopes = slices.DeleteFunc(slices.Clone(apikey.AllScopes), func(scope string) bool {
+			return !slices.Contains(requested, scope)
+		})
+	} else {
+		scopes = apikey.AllScopes
+	}
+	for _, scope := range scopes {
+		if !s.authz.AllowsScope(scope, claims.HasRole) {
+			s.logger.Warn("API key scope denied", zap.String("scope", scope), zap.String("user", claims.Subject))
+			return nil, "", fmt.Errorf("%w: %s", ErrAPIKeyScopeForbidden, scope)
+		}
+	}
+
+	if productID != nil {
+		if _, err := s.products.FindProductByID(ctx, *productID); err != nil {
+			if errors.Is(err, ierr.ErrNotFound) {
+				return nil, "", fmt.Errorf("%w: product %s does not exist", ierr.ErrValidation, productID)
+			}
+			return nil, "", fmt.Errorf("repository error finding product %s: %w", productID, err)
+		}
+	}
+
+	fullKey, prefix, keyHash, err := util.GenerateAPIKey(s.crypto)
 	if err != nil {
 		s.logger.Error("Failed to generate api key components", zap.Error(err))
 		return nil, "", fmt.Errorf("%w: failed generating key: %v", ierr.ErrInternalServer, err)
@@ -37,6 +87,9 @@ func (s *APIKeyService) CreateAPIKey(ctx context.Context, description string, pr
 		KeyHash:     keyHash,
 		Prefix:      prefix,
 		Description: description,
+		OrgID:       claims.OrgID,
+		Scopes:      scopes,
+		CreatedBy:   claims.Subject,
 		IsEnabled:   true,
 	}
 	if productID != nil {
@@ -56,6 +109,9 @@ func (s *APIKeyService) CreateAPIKey(ctx context.Context, description string, pr
 		FullKey:     fullKey,
 		Prefix:      prefix,
 		Description: description,
+		OrgID:       claims.OrgID,
+		Scopes:      scopes,
+		CreatedBy:   claims.Subject,
 	}
 	if productID != nil {
 		resp.ProductID = *productID
@@ -81,6 +137,9 @@ func (s *APIKeyService) ListAPIKeys(ctx context.Context) ([]*dto.APIKeyResponse,
 			Prefix:      key.Prefix,
 			Description: key.Description,
 			ProductID:   key.ProductID,
+			OrgID:       key.OrgID,
+			Scopes:      key.Scopes,
+			CreatedBy:   key.CreatedBy,
 			IsEnabled:   key.IsEnabled,
 			CreatedAt:   key.CreatedAt,
 			LastUsedAt:  key.LastUsedAt,
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/audit"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/approval"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrElevatedRoleRequired = ierr.ErrForbidden.Derive("ELEVATED_ROLE_REQUIRED", "this operation requires the elevated role")
+	ErrSelfApproval         = ierr.ErrForbidden.Derive("SELF_APPROVAL", "a change cannot be approved by the user who requested it")
+	ErrApprovalStale        = ierr.ErrConflict.Derive("APPROVAL_STALE", "the license changed after the change was requested, request it again")
+	ErrApprovalExpired      = ierr.ErrConflict.Derive("APPROVAL_EXPIRED", "the change request expired, request it again")
+)
+
+// protectableFields are the license fields that can be protected besides
+// metadata paths; they decide what a license entitles its holder to.
+var protectableFields = map[string]bool{
+	"type":              true,
+	"expires_at":        true,
+	"starts_at":         true,
+	"max_activations":   true,
+	"grace_period_days": true,
+	"floating":          true,
+}
+
+// ApprovalService guards protected license keys. Updates that touch one are
+// applied directly only for callers with the elevated role; anyone else's
+// update is held as a change request until a second user approves it.
+type ApprovalService struct {
+	requests       approval.Repository
+	keys           approval.KeyRepository
+	licenses       license.Repository
+	licenseService *LicenseService
+	cfg            *config.ApprovalConfig
+	logger         *zap.Logger
+}
+
+func NewApprovalService(requests approval.Repository, keys approval.KeyRepository, licenses license.Repository, licenseService *LicenseService, cfg *config.ApprovalConfig, logger *zap.Logger) *ApprovalService {
+	return &ApprovalService{
+		requests:       requests,
+		keys:           keys,
+		licenses:       licenses,
+		licenseService: licenseService,
+		cfg:            cfg,
+		logger:         logger.Named("ApprovalService"),
+	}
+}
+
+func (s *ApprovalService) ListProtectedKeys(ctx context.Context) ([]*approval.ProtectedKey, error) {
+	keys, err := s.keys.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing protected keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (s *ApprovalService) PutProtectedKey(ctx context.Context, key string, req *dto.PutProtectedKeyRequest, claims *ZitadelClaims) (*approval.ProtectedKey, error) {
+	if !claims.HasRole(s.cfg.ElevatedRole) {
+		return nil, ErrElevatedRoleRequired
+	}
+	if err := validateProtectedKey(key); err != nil {
+		return nil, err
+	}
+
+	k := &approval.ProtectedKey{Key: key, Description: req.Description, CreatedBy: claims.Subject}
+	if err := s.keys.Put(ctx, k); err != nil {
+		return nil, fmt.Errorf("repository error saving protected key %s: %w", key, err)
+	}
+	s.logger.Info("Protected key saved", zap.String("key", key), zap.String("by", claims.Subject))
+	return k, nil
+}
+
+func (s *ApprovalService) DeleteProtectedKey(ctx context.Context, key string, claims *ZitadelClaims) error {
+	if !claims.HasRole(s.cfg.ElevatedRole) {
+		return ErrElevatedRoleRequired
+	}
+	if err := s.keys.Delete(ctx, key); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("repository error deleting protected key %s: %w", key, err)
+	}
+	s.logger.Info("Protected key deleted", zap.String("key", key), zap.String("by", claims.Subject))
+	return nil
+}
+
+func validateProtectedKey(key string) error {
+	if protectableFields[key] {
+		return nil
+	}
+	path, ok := strings.CutPrefix(key, "metadata.")
+	if !ok || path == "" || strings.HasPrefix(path, ".") || strings.HasSuffix(path, ".") || strings.Contains(path, "..") {
+		return fmt.Errorf("%w: protected key must be one of %s or a metadata path such as metadata.limits.seats", ierr.ErrValidation, strings.Join(sortedKeys(protectableFields), ", "))
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// HoldProtectedUpdate returns a pending change request instead of letting
+// req through when it changes a protected key and claims lack the elevated
+// role. A nil request means the update may be applied directly.
+func (s *ApprovalService) HoldProtectedUpdate(ctx context.Context, id uuid.UUID, req *dto.UpdateLicenseRequest, claims *ZitadelClaims) (*approval.Request, error) {
+	if claims.HasRole(s.cfg.ElevatedRole) {
+		return nil, nil
+	}
+	keys, err := s.keys.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing protected keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	lic, err := s.licenses.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error fetching license %s: %w", id, err)
+	}
+	touched, err := protectedChanges(lic, req, keys)
+	if err != nil {
+		return nil, err
+	}
+	if len(touched) == 0 {
+		return nil, nil
+	}
+
+	changes, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode held license update: %w", err)
+	}
+	held := &approval.Request{
+		LicenseID:     id,
+		Changes:       changes,
+		ProtectedKeys: touched,
+		RequestedBy:   claims.Subject,
+		BaseUpdatedAt: lic.UpdatedAt,
+		ExpiresAt:     time.Now().Add(s.cfg.TTL).UTC(),
+	}
+	if err := s.requests.Create(ctx, held); err != nil {
+		return nil, fmt.Errorf("repository error creating change request for license %s: %w", id, err)
+	}
+
+	s.logger.Info("License update held for approval",
+		zap.String("approval_id", held.ID.String()),
+		zap.String("license_id", id.String()),
+		zap.Strings("protected_keys", touched),
+		zap.String("requested_by", claims.Subject),
+	)
+	return held, nil
+}
+
+// protectedChanges returns the protected keys req would change on lic.
+func protectedChanges(lic *license.License, req *dto.UpdateLicenseRequest, keys []*approval.ProtectedKey) ([]string, error) {
+	changed := map[string]bool{}
+	if req.Type != nil && *req.Type != lic.Type {
+		changed["type"] = true
+	}
+	if req.ExpiresAt != nil && (!lic.ExpiresAt.Valid || !lic.ExpiresAt.Time.Equal(*req.ExpiresAt)) {
+		changed["expires_at"] = true
+	}
+	if req.StartsAt != nil && (!lic.StartsAt.Valid || !lic.StartsAt.Time.Equal(*req.StartsAt)) {
+		changed["starts_at"] = true
+	}
+	if req.MaxActivations != nil && *req.MaxActivations != lic.MaxActivations {
+		changed["max_activations"] = true
+	}
+	if req.GracePeriodDays != nil && *req.GracePeriodDays != lic.GracePeriodDays {
+		changed["grace_period_days"] = true
+	}
+	if req.Floating != nil && *req.Floating != lic.Floating {
+		changed["floating"] = true
+	}
+
+	var metadataPaths []string
+	if req.Metadata != nil {
+		diffs, err := audit.Diff(wrapMetadata(lic.Metadata), wrapMetadata(req.Metadata))
+		if err != nil {
+			return nil, fmt.Errorf("%w: metadata is not valid JSON", ierr.ErrValidation)
+		}
+		for _, d := range diffs {
+			if d.Change != audit.ChangeUnchanged {
+				metadataPaths = append(metadataPaths, d.Field)
+			}
+		}
+	}
+
+	var touched []string
+	for _, k := range keys {
+		if changed[k.Key] {
+			touched = append(touched, k.Key)
+			continue
+		}
+		for _, path := range metadataPaths {
+			if path == k.Key || strings.HasPrefix(path, k.Key+".") || strings.HasPrefix(k.Key, path+".") {
+				touched = append(touched, k.Key)
+				break
+			}
+		}
+	}
+	return touched, nil
+}
+
+func wrapMetadata(metadata json.RawMessage) json.RawMessage {
+	if len(metadata) == 0 {
+		metadata = json.RawMessage("null")
+	}
+	return json.RawMessage(`{"metadata":` + string(metadata) + `}`)
+}
+
+func (s *ApprovalService) ListRequests(ctx context.Context, req *dto.ListApprovalsRequest) ([]*approval.Request, int64, error) {
+	params := approval.ListParams{Limit: req.Limit, Offset: req.Offset}
+	if params.Limit <= 0 {
+		params.Limit = 20
+		req.Limit = 20
+	}
+	if req.Status != nil {
+		status := approval.Status(*req.Status)
+		params.Status = &status
+	}
+	if req.LicenseID != nil {
+		id, err := idgen.Parse(*req.LicenseID)
+		if err != nil {
+			return nil, 0, err
+		}
+		params.LicenseID = &id
+	}
+
+	requests, total, err := s.requests.List(ctx, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("repository error listing change requests: %w", err)
+	}
+	return requests, total, nil
+}
+
+func (s *ApprovalService) GetRequest(ctx context.Context, id uuid.UUID) (*approval.Request, error) {
+	r, err := s.requests.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error finding change request %s: %w", id, err)
+	}
+	return r, nil
+}
+
+// Approve applies a held update on behalf of a second user. The update is
+// refused when the license changed since it was requested, because it
+// would overwrite those changes with values nobody reviewed against them.
+func (s *ApprovalService) Approve(ctx context.Context, id uuid.UUID, claims *ZitadelClaims) (*approval.Request, *license.License, error) {
+	held, err := s.GetRequest(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if held.Status != approval.StatusPending {
+		return nil, nil, fmt.Errorf("%w: change request is already %s", ierr.ErrConflict, held.Status)
+	}
+	if held.RequestedBy == claims.Subject {
+		return nil, nil, ErrSelfApproval
+	}
+	if held.Expired(time.Now()) {
+		return nil, nil, ErrApprovalExpired
+	}
+
+	var req dto.UpdateLicenseRequest
+	if err := json.Unmarshal(held.Changes, &req); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode held license update %s: %w", id, err)
+	}
+	lic, err := s.licenses.FindByID(ctx, held.LicenseID)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, nil, fmt.Errorf("%w: license %s no longer exists", ierr.ErrNotFound, held.LicenseID)
+		}
+		return nil, nil, fmt.Errorf("repository error fetching license %s: %w", held.LicenseID, err)
+	}
+	if !lic.UpdatedAt.Equal(held.BaseUpdatedAt) {
+		return nil, nil, ErrApprovalStale
+	}
+
+	decided, err := s.requests.Decide(ctx, id, approval.StatusApproved, claims.Subject)
+	if err != nil {
+		return nil, nil, err
+	}
+	updated, err := s.licenseService.UpdateLicense(ctx, held.LicenseID, &req)
+	if err != nil {
+		if reopenErr := s.requests.Reopen(context.WithoutCancel(ctx), id); reopenErr != nil {
+			s.logger.Error("Failed to reopen change request after failed update", zap.String("approval_id", id.String()), zap.Error(reopenErr))
+		}
+		return nil, nil, err
+	}
+
+	s.logger.Info("License update approved",
+		zap.String("approval_id", id.String()),
+		zap.String("license_id", held.LicenseID.String()),
+		zap.String("requested_by", held.RequestedBy),
+		zap.String("approved_by", claims.Subject),
+	)
+	return decided, updated, nil
+}
+
+// Reject discards a held update. The requester may reject, i.e. withdraw,
+// their own request.
+func (s *ApprovalService) Reject(ctx context.Context, id uuid.UUID, claims *ZitadelClaims) (*approval.Request, error) {
+	if _, err := s.GetRequest(ctx, id); err != nil {
+		return nil, err
+	}
+	decided, err := s.requests.Decide(ctx, id, approval.StatusRejected, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("License update rejected", zap.String("approval_id", id.String()), zap.String("rejected_by", claims.Subject))
+	return decided, nil
+}
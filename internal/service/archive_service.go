@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/archive"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"go.uber.org/zap"
+)
+
+// ArchiveService moves cold licenses, expired or revoked and unchanged for
+// the archive age, out of the licenses table together with their events,
+// keeping the hot tables small. Archived licenses stay readable by ID.
+type ArchiveService struct {
+	licenses license.Archiver
+	events   archive.EventRepository
+	cfg      *config.ArchiveConfig
+	logger   *zap.Logger
+}
+
+func NewArchiveService(licenses license.Archiver, events archive.EventRepository, cfg *config.ArchiveConfig, logger *zap.Logger) *ArchiveService {
+	return &ArchiveService{
+		licenses: licenses,
+		events:   events,
+		cfg:      cfg,
+		logger:   logger.Named("ArchiveService"),
+	}
+}
+
+// ArchiveLicenses archives candidates batch by batch until none are left and
+// returns how many licenses were archived. The events of a batch are moved
+// first, as deleting a license deletes its remaining events; the events of
+// licenses that were not moved after all are restored.
+func (s *ArchiveService) ArchiveLicenses(ctx context.Context) (int, error) {
+	cutoff := time.Now().UTC().AddDate(-s.cfg.MinAgeYears, 0, 0)
+	archived := 0
+	for {
+		ids, err := s.licenses.ArchiveCandidates(ctx, cutoff, s.cfg.BatchSize)
+		if err != nil {
+			return archived, fmt.Errorf("failed to list archive candidates: %w", err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		counts, err := s.events.Archive(ctx, ids)
+		if err != nil {
+			return archived, fmt.Errorf("failed to archive license events: %w", err)
+		}
+
+		moved, err := s.licenses.Archive(ctx, ids, cutoff)
+		s.restoreEvents(ctx, ids, moved)
+		if err != nil {
+			return archived + len(moved), fmt.Errorf("failed to archive licenses: %w", err)
+		}
+
+		archived += len(moved)
+		s.logger.Info("Archived licenses",
+			zap.Int("licenses", len(moved)),
+			zap.Int("candidates", len(ids)),
+			zap.Int64("activations", counts.Activations),
+			zap.Int64("status_changes", counts.StatusChanges),
+			zap.Int64("validation_events", counts.ValidationEvents),
+		)
+
+		// A batch where nothing could be moved would be listed again.
+		if len(ids) < s.cfg.BatchSize || len(moved) == 0 {
+			break
+		}
+	}
+	return archived, nil
+}
+
+// restoreEvents moves back the events of the ids not in moved. It runs even
+// when ctx is canceled, since those events would otherwise stay archived
+// for licenses that are not.
+func (s *ArchiveService) restoreEvents(ctx context.Context, ids, moved []uuid.UUID) {
+	done := make(map[uuid.UUID]struct{}, len(moved))
+	for _, id := range moved {
+		done[id] = struct{}{}
+	}
+	var left []uuid.UUID
+	for _, id := range ids {
+		if _, ok := done[id]; !ok {
+			left = append(left, id)
+		}
+	}
+	if len(left) == 0 {
+		return
+	}
+
+	if err := s.events.Restore(context.WithoutCancel(ctx), left); err != nil {
+		s.logger.Error("Failed to restore events of licenses left unarchived",
+			zap.Stringers("license_ids", left), zap.Error(err))
+	}
+}
+
+func (s *ArchiveService) FindArchivedByID(ctx context.Context, id uuid.UUID) (*license.Archived, error) {
+	return s.licenses.FindArchivedByID(ctx, id)
+}
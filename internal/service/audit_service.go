@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/audit"
+	domainaudit "github.com/makkenzo/license-service-api/internal/domain/audit"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type AuditService struct {
+	repo     domainaudit.Repository
+	licenses license.Repository
+	logger   *zap.Logger
+}
+
+func NewAuditService(repo domainaudit.Repository, licenses license.Repository, logger *zap.Logger) *AuditService {
+	return &AuditService{
+		repo:     repo,
+		licenses: licenses,
+		logger:   logger.Named("AuditService"),
+	}
+}
+
+func (s *AuditService) List(ctx context.Context, req *dto.ListAuditRequest) ([]*dto.AuditEntryResponse, int64, error) {
+	params, err := auditListParams(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	if req.EntityID != nil {
+		id, err := idgen.Parse(*req.EntityID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: invalid entity_id format", ierr.ErrValidation)
+		}
+		params.EntityID = &id
+	}
+	return s.list(ctx, params)
+}
+
+// ListForLicense returns the audit trail of one license, newest first.
+func (s *AuditService) ListForLicense(ctx context.Context, id uuid.UUID, req *dto.ListAuditRequest) ([]*dto.AuditEntryResponse, int64, error) {
+	if _, err := s.licenses.FindByID(ctx, id); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, 0, err
+		}
+		return nil, 0, fmt.Errorf("repository error finding license %s: %w", id, err)
+	}
+
+	params, err := auditListParams(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	entityType := domainaudit.EntityLicense
+	params.EntityType = &entityType
+	params.EntityID = &id
+	return s.list(ctx, params)
+}
+
+func (s *AuditService) list(ctx context.Context, params domainaudit.ListParams) ([]*dto.AuditEntryResponse, int64, error) {
+	entries, total, err := s.repo.List(ctx, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("repository error listing audit entries: %w", err)
+	}
+
+	resp := make([]*dto.AuditEntryResponse, len(entries))
+	for i, entry := range entries {
+		resp[i] = &dto.AuditEntryResponse{
+			ID:         entry.ID,
+			EntityType: entry.EntityType,
+			EntityID:   entry.EntityID,
+			Action:     entry.Action,
+			Actor:      auditActorResponse(entry.Actor),
+			Before:     entry.Before,
+			After:      entry.After,
+			CreatedAt:  entry.CreatedAt,
+		}
+	}
+	return resp, total, nil
+}
+
+func auditListParams(req *dto.ListAuditRequest) (domainaudit.ListParams, error) {
+	params := domainaudit.ListParams{
+		Action:        req.Action,
+		ActorID:       req.ActorID,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+		Limit:         req.Limit,
+		Offset:        req.Offset,
+	}
+	if req.ActorType != nil {
+		actorType := domainaudit.ActorType(*req.ActorType)
+		params.ActorType = &actorType
+	}
+	if params.CreatedAfter != nil && params.CreatedBefore != nil && !params.CreatedAfter.Before(*params.CreatedBefore) {
+		return params, fmt.Errorf("%w: created_after must be before created_before", ierr.ErrValidation)
+	}
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+	return params, nil
+}
+
+func auditActorResponse(actor domainaudit.Actor) dto.AuditActorResponse {
+	return dto.AuditActorResponse{
+		Type:      string(actor.Type),
+		ID:        actor.ID,
+		IP:        actor.IP,
+		UserAgent: actor.UserAgent,
+	}
+}
+
+func (s *AuditService) Diff(ctx context.Context, id uuid.UUID, req *dto.AuditDiffRequest) (*dto.AuditDiffResponse, error) {
+	entry, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error finding audit entry %s: %w", id, err)
+	}
+
+	diffs, err := audit.Diff(entry.Before, entry.After)
+	if err != nil {
+		s.logger.Error("Failed to diff audit entry snapshots", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("failed to diff audit entry %s: %w", id, err)
+	}
+
+	resp := &dto.AuditDiffResponse{
+		ID:         entry.ID,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Action:     entry.Action,
+		Actor:      auditActorResponse(entry.Actor),
+		CreatedAt:  entry.CreatedAt,
+		Fields:     make([]dto.AuditFieldDiff, 0, len(diffs)),
+	}
+	for _, d := range diffs {
+		if req.ChangedOnly && d.Change == audit.ChangeUnchanged {
+			continue
+		}
+		resp.Fields = append(resp.Fields, dto.AuditFieldDiff{
+			Field:  d.Field,
+			Old:    d.Old,
+			New:    d.New,
+			Change: d.Change,
+		})
+	}
+	return resp, nil
+}
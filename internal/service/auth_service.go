@@ -23,6 +23,20 @@ type ZitadelClaims struct {
 	ClientID          string                            `json:"client_id"`
 	Audience          []string                          `json:"aud"`
 	Subject           string                            `json:"sub"`
+	OrgID             string                            `json:"urn:zitadel:iam:user:resourceowner:id"`
+	// AuthTime is when the user last signed in, in Unix seconds; zero when
+	// the token does not say.
+	AuthTime int64 `json:"auth_time"`
+}
+
+// HasRole reports whether the token grants the project role. Zitadel keys
+// the roles claim by role name.
+func (c *ZitadelClaims) HasRole(role string) bool {
+	if c == nil || role == "" {
+		return false
+	}
+	_, ok := c.Roles[role]
+	return ok
 }
 
 type AuthService struct {
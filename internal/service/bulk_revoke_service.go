@@ -0,0 +1,222 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+	"github.com/makkenzo/license-service-api/internal/domain/confirmation"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+const (
+	bulkRevokeTokenTTL = 10 * time.Minute
+	bulkRevokePageSize = 500
+	// MaxBulkRevoke bounds one request; larger batches have to be split by
+	// narrowing the filter, e.g. by creation date.
+	MaxBulkRevoke = 10000
+)
+
+// bulkRevokePreview is what a confirmation token stands for.
+type bulkRevokePreview struct {
+	Filter      dto.BulkRevokeFilter `json:"filter"`
+	Matched     int64                `json:"matched"`
+	RequestedBy string               `json:"requested_by"`
+}
+
+// BulkRevokeService revokes every license matching a filter, for example a
+// batch issued by a compromised key generator. A revoke always starts with a
+// preview whose token has to be sent back, so the caller has seen how many
+// licenses are affected before anything changes.
+type BulkRevokeService struct {
+	repo          license.Repository
+	confirmations confirmation.Repository
+	crypto        cryptoprovider.Provider
+	logger        *zap.Logger
+}
+
+func NewBulkRevokeService(repo license.Repository, confirmations confirmation.Repository, crypto cryptoprovider.Provider, logger *zap.Logger) *BulkRevokeService {
+	return &BulkRevokeService{
+		repo:          repo,
+		confirmations: confirmations,
+		crypto:        crypto,
+		logger:        logger.Named("BulkRevokeService"),
+	}
+}
+
+func (s *BulkRevokeService) BulkRevoke(ctx context.Context, req *dto.BulkRevokeRequest, requestedBy string) (*dto.BulkRevokeResponse, error) {
+	filter, err := normalizeBulkRevokeFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	matched, err := s.count(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if matched > MaxBulkRevoke {
+		return nil, fmt.Errorf("%w: filter matches %d licenses, at most %d can be revoked at once", ierr.ErrValidation, matched, MaxBulkRevoke)
+	}
+
+	if req.ConfirmationToken == "" {
+		return s.preview(ctx, filter, matched, requestedBy)
+	}
+
+	data, err := s.confirmations.Take(ctx, req.ConfirmationToken)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, fmt.Errorf("%w: confirmation token is unknown, used or expired, preview the revoke again", ierr.ErrValidation)
+		}
+		return nil, fmt.Errorf("failed to read bulk revoke confirmation: %w", err)
+	}
+	var preview bulkRevokePreview
+	if err := json.Unmarshal(data, &preview); err != nil {
+		return nil, fmt.Errorf("failed to decode bulk revoke confirmation: %w", err)
+	}
+	if preview.RequestedBy != requestedBy || !sameBulkRevokeFilter(preview.Filter, filter) {
+		return nil, fmt.Errorf("%w: confirmation token was issued for a different filter or user", ierr.ErrValidation)
+	}
+	if preview.Matched != matched {
+		return nil, fmt.Errorf("%w: filter matched %d licenses at preview and %d now, preview the revoke again", ierr.ErrConflict, preview.Matched, matched)
+	}
+
+	// Once confirmed the revoke runs to the end even if the caller goes
+	// away, so a dropped connection cannot leave half a batch behind.
+	return s.revoke(context.WithoutCancel(ctx), filter, matched, requestedBy), nil
+}
+
+func (s *BulkRevokeService) preview(ctx context.Context, filter dto.BulkRevokeFilter, matched int64, requestedBy string) (*dto.BulkRevokeResponse, error) {
+	resp := &dto.BulkRevokeResponse{DryRun: true, Matched: matched}
+	if matched == 0 {
+		return resp, nil
+	}
+
+	raw := make([]byte, 24)
+	if _, err := io.ReadFull(s.crypto.Rand(), raw); err != nil {
+		return nil, fmt.Errorf("failed to generate confirmation token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	data, err := json.Marshal(bulkRevokePreview{Filter: filter, Matched: matched, RequestedBy: requestedBy})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bulk revoke confirmation: %w", err)
+	}
+	if err := s.confirmations.Save(ctx, token, data, bulkRevokeTokenTTL); err != nil {
+		return nil, fmt.Errorf("failed to save bulk revoke confirmation: %w", err)
+	}
+
+	expiresAt := time.Now().Add(bulkRevokeTokenTTL).UTC()
+	resp.ConfirmationToken = token
+	resp.TokenExpiresAt = &expiresAt
+	s.logger.Info("Bulk revoke previewed", zap.Int64("matched", matched), zap.String("requested_by", requestedBy))
+	return resp, nil
+}
+
+// revoke goes through UpdateStatus one license at a time so that every
+// revoke is audited, evicted from caches and checked by the status guard
+// like a single one. Failures are reported per license.
+func (s *BulkRevokeService) revoke(ctx context.Context, filter dto.BulkRevokeFilter, matched int64, requestedBy string) *dto.BulkRevokeResponse {
+	resp := &dto.BulkRevokeResponse{Matched: matched}
+
+	params := bulkRevokeListParams(filter)
+	params.SortBy = "id"
+	params.SortOrder = "ASC"
+	params.Limit = bulkRevokePageSize
+	// The filter never looks at status, so revoking does not shift pages.
+	for params.Offset = 0; ; params.Offset += bulkRevokePageSize {
+		page, _, err := s.repo.List(ctx, params)
+		if err != nil {
+			s.logger.Error("Bulk revoke stopped: failed to list licenses", zap.Int("offset", params.Offset), zap.Error(err))
+			resp.Incomplete = true
+			break
+		}
+		for _, lic := range page {
+			if lic.Status == license.StatusRevoked {
+				continue
+			}
+			if err := s.repo.UpdateStatus(ctx, lic.ID, license.StatusRevoked); err != nil {
+				s.logger.Warn("Bulk revoke failed for license", zap.String("id", lic.ID.String()), zap.Error(err))
+				_, code, _ := ierr.Describe(err)
+				resp.Failed = append(resp.Failed, dto.BulkRevokeFailure{ID: lic.ID, Code: code})
+				continue
+			}
+			resp.Revoked++
+			// Children are revoked with their parent but only counted when
+			// the filter selects them too.
+			if _, err := revokeChildren(ctx, s.repo, lic.ID); err != nil {
+				s.logger.Warn("Bulk revoke failed for child licenses", zap.String("parent_id", lic.ID.String()), zap.Error(err))
+			}
+		}
+		if len(page) < bulkRevokePageSize {
+			break
+		}
+	}
+
+	s.logger.Info("Bulk revoke completed",
+		zap.Int64("matched", matched),
+		zap.Int("revoked", resp.Revoked),
+		zap.Int("failed", len(resp.Failed)),
+		zap.String("requested_by", requestedBy),
+	)
+	return resp
+}
+
+// count returns how many licenses the filter selects that are not revoked.
+func (s *BulkRevokeService) count(ctx context.Context, filter dto.BulkRevokeFilter) (int64, error) {
+	params := bulkRevokeListParams(filter)
+	params.Limit = 1
+	_, total, err := s.repo.List(ctx, params)
+	if err != nil {
+		return 0, fmt.Errorf("repository error counting licenses for bulk revoke: %w", err)
+	}
+
+	revoked := license.StatusRevoked
+	params.Status = &revoked
+	_, alreadyRevoked, err := s.repo.List(ctx, params)
+	if err != nil {
+		return 0, fmt.Errorf("repository error counting revoked licenses for bulk revoke: %w", err)
+	}
+	return total - alreadyRevoked, nil
+}
+
+func normalizeBulkRevokeFilter(f dto.BulkRevokeFilter) (dto.BulkRevokeFilter, error) {
+	if f.ProductName == nil && f.Type == nil && f.CreatedAfter == nil && f.CreatedBefore == nil {
+		return f, fmt.Errorf("%w: filter must set at least one of product_name, type, created_after, created_before", ierr.ErrValidation)
+	}
+	if f.CreatedAfter != nil {
+		t := f.CreatedAfter.UTC()
+		f.CreatedAfter = &t
+	}
+	if f.CreatedBefore != nil {
+		t := f.CreatedBefore.UTC()
+		f.CreatedBefore = &t
+	}
+	if f.CreatedAfter != nil && f.CreatedBefore != nil && !f.CreatedAfter.Before(*f.CreatedBefore) {
+		return f, fmt.Errorf("%w: created_after must be before created_before", ierr.ErrValidation)
+	}
+	return f, nil
+}
+
+func sameBulkRevokeFilter(a, b dto.BulkRevokeFilter) bool {
+	aj, errA := json.Marshal(a)
+	bj, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(aj, bj)
+}
+
+func bulkRevokeListParams(f dto.BulkRevokeFilter) license.ListParams {
+	return license.ListParams{
+		ProductName:   f.ProductName,
+		Type:          f.Type,
+		CreatedAfter:  f.CreatedAfter,
+		CreatedBefore: f.CreatedBefore,
+	}
+}
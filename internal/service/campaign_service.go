@@ -0,0 +1,469 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+	"github.com/makkenzo/license-service-api/internal/domain/campaign"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/notify"
+	"github.com/makkenzo/license-service-api/internal/tasks"
+	"github.com/makkenzo/license-service-api/internal/templates"
+	"github.com/makkenzo/license-service-api/internal/util"
+	"go.uber.org/zap"
+)
+
+// campaignPageSize is how many licenses are loaded at a time while the
+// recipients of a campaign are picked.
+const campaignPageSize = 500
+
+var ErrCampaignsDisabled = ierr.New("CAMPAIGNS_DISABLED", http.StatusServiceUnavailable, "e-mail campaigns are disabled: no unsubscribe secret is configured").
+	WithPublicMessage("E-mail campaigns are not enabled on this server.")
+
+// CampaignService sends bulk e-mail campaigns to license holders. Creating
+// a campaign only stores it; the worker picks the recipients and sends
+// them in batches spaced BatchInterval apart so the mail server is not
+// flooded.
+type CampaignService struct {
+	campaigns campaign.Repository
+	licenses  license.Repository
+	mailer    notify.Mailer
+	renderer  *templates.Renderer
+	crypto    cryptoprovider.Provider
+	tasks     *asynq.Client
+	cfg       *config.CampaignsConfig
+	logger    *zap.Logger
+}
+
+func NewCampaignService(campaigns campaign.Repository, licenses license.Repository, mailer notify.Mailer, renderer *templates.Renderer, crypto cryptoprovider.Provider, taskClient *asynq.Client, cfg *config.CampaignsConfig, logger *zap.Logger) *CampaignService {
+	return &CampaignService{
+		campaigns: campaigns,
+		licenses:  licenses,
+		mailer:    mailer,
+		renderer:  renderer,
+		crypto:    crypto,
+		tasks:     taskClient,
+		cfg:       cfg,
+		logger:    logger.Named("CampaignService"),
+	}
+}
+
+func (s *CampaignService) CreateCampaign(ctx context.Context, req *dto.CreateCampaignRequest, requestedBy string) (*dto.CampaignResponse, error) {
+	if s.cfg.UnsubscribeSecret == "" {
+		return nil, ErrCampaignsDisabled
+	}
+	if strings.ContainsAny(req.Subject, "\r\n") {
+		return nil, fmt.Errorf("%w: subject must be a single line", ierr.ErrValidation)
+	}
+
+	c := &campaign.Campaign{
+		Name:    req.Name,
+		Subject: req.Subject,
+		Message: req.Message,
+		Filter: campaign.Filter{
+			ProductNames:      req.Filter.ProductNames,
+			Statuses:          req.Filter.Statuses,
+			ExpiresWithinDays: req.Filter.ExpiresWithinDays,
+		},
+		RequestedBy: requestedBy,
+	}
+	if err := s.campaigns.Create(ctx, c); err != nil {
+		return nil, fmt.Errorf("repository error creating campaign: %w", err)
+	}
+
+	task, err := tasks.NewCampaignPrepareTask(tasks.CampaignPreparePayload{CampaignID: c.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create campaign task: %w", err)
+	}
+	info, err := s.tasks.EnqueueContext(ctx, task)
+	if err != nil {
+		s.logger.Error("Failed to enqueue campaign", zap.String("campaign_id", c.ID.String()), zap.Error(err))
+		if failErr := s.campaigns.SetStatus(ctx, c.ID, campaign.StatusFailed, "could not be enqueued", campaign.StatusPending); failErr != nil {
+			s.logger.Error("Failed to mark campaign failed", zap.String("campaign_id", c.ID.String()), zap.Error(failErr))
+		}
+		return nil, fmt.Errorf("failed to enqueue campaign: %w", err)
+	}
+
+	s.logger.Info("Campaign enqueued",
+		zap.String("campaign_id", c.ID.String()),
+		zap.String("task_id", info.ID),
+		zap.String("requested_by", requestedBy),
+	)
+	resp := campaignResponse(c)
+	resp.TaskID = info.ID
+	return resp, nil
+}
+
+func (s *CampaignService) findCampaign(ctx context.Context, id uuid.UUID) (*campaign.Campaign, error) {
+	c, err := s.campaigns.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error finding campaign %s: %w", id, err)
+	}
+	return c, nil
+}
+
+// GetCampaign returns the campaign with its recipients counted by status.
+func (s *CampaignService) GetCampaign(ctx context.Context, id uuid.UUID) (*dto.CampaignResponse, error) {
+	c, err := s.findCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.campaigns.CountRecipients(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("repository error counting campaign recipients: %w", err)
+	}
+	resp := campaignResponse(c)
+	resp.Recipients = &dto.CampaignRecipientCounts{
+		Pending:      counts[campaign.RecipientPending],
+		Sent:         counts[campaign.RecipientSent],
+		Failed:       counts[campaign.RecipientFailed],
+		Unsubscribed: counts[campaign.RecipientUnsubscribed],
+	}
+	for _, n := range counts {
+		resp.Recipients.Total += n
+	}
+	return resp, nil
+}
+
+// ListCampaigns returns the most recent campaigns without their recipient
+// counts.
+func (s *CampaignService) ListCampaigns(ctx context.Context, limit int) (*dto.CampaignListResponse, error) {
+	campaigns, err := s.campaigns.List(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing campaigns: %w", err)
+	}
+	resp := &dto.CampaignListResponse{Campaigns: make([]dto.CampaignResponse, 0, len(campaigns))}
+	for _, c := range campaigns {
+		resp.Campaigns = append(resp.Campaigns, *campaignResponse(c))
+	}
+	return resp, nil
+}
+
+func (s *CampaignService) ListRecipients(ctx context.Context, id uuid.UUID, q *dto.ListCampaignRecipientsQuery) (*dto.PaginatedCampaignRecipientsResponse, error) {
+	if _, err := s.findCampaign(ctx, id); err != nil {
+		return nil, err
+	}
+	recipients, total, err := s.campaigns.ListRecipients(ctx, id, campaign.RecipientQuery{Status: q.Status, Limit: q.Limit, Offset: q.Offset})
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing campaign recipients: %w", err)
+	}
+	resp := &dto.PaginatedCampaignRecipientsResponse{
+		Recipients: make([]dto.CampaignRecipientResponse, 0, len(recipients)),
+		TotalCount: total,
+		Limit:      q.Limit,
+		Offset:     q.Offset,
+	}
+	for _, r := range recipients {
+		resp.Recipients = append(resp.Recipients, dto.CampaignRecipientResponse{
+			ID:        r.ID,
+			Email:     r.Email,
+			LicenseID: r.LicenseID,
+			Batch:     r.Batch,
+			Status:    r.Status,
+			Attempts:  r.Attempts,
+			LastError: r.LastError,
+			SentAt:    r.SentAt,
+		})
+	}
+	return resp, nil
+}
+
+// CancelCampaign stops a campaign that has not finished. Batches already
+// queued skip it; mail that went out stays sent.
+func (s *CampaignService) CancelCampaign(ctx context.Context, id uuid.UUID, cancelledBy string) (*dto.CampaignResponse, error) {
+	if err := s.campaigns.SetStatus(ctx, id, campaign.StatusCancelled, "", campaign.StatusPending, campaign.StatusSending); err != nil {
+		if errors.Is(err, ierr.ErrConflict) || errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error cancelling campaign %s: %w", id, err)
+	}
+	s.logger.Info("Campaign cancelled", zap.String("campaign_id", id.String()), zap.String("cancelled_by", cancelledBy))
+	return s.GetCampaign(ctx, id)
+}
+
+// Unsubscribe stops all future campaigns to the address the token was
+// sent to. Using a link twice is harmless.
+func (s *CampaignService) Unsubscribe(ctx context.Context, token string) (*dto.UnsubscribeResponse, error) {
+	if s.cfg.UnsubscribeSecret == "" {
+		return nil, ErrCampaignsDisabled
+	}
+	id, err := util.VerifyUnsubscribeToken(s.crypto, []byte(s.cfg.UnsubscribeSecret), token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid unsubscribe link", ierr.ErrValidation)
+	}
+	r, err := s.campaigns.FindRecipient(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, fmt.Errorf("%w: invalid unsubscribe link", ierr.ErrValidation)
+		}
+		return nil, fmt.Errorf("repository error finding campaign recipient %s: %w", id, err)
+	}
+	if err := s.campaigns.Unsubscribe(ctx, r.Email, r.CampaignID); err != nil {
+		return nil, fmt.Errorf("repository error unsubscribing: %w", err)
+	}
+	s.logger.Info("Address unsubscribed from campaigns", zap.String("campaign_id", r.CampaignID.String()), zap.String("recipient_id", r.ID.String()))
+	return &dto.UnsubscribeResponse{Unsubscribed: true}, nil
+}
+
+// PrepareCampaign picks the recipients of a campaign, one per address, and
+// queues its batches. A retry adds only the addresses still missing, and
+// batches that are already queued are not queued again.
+func (s *CampaignService) PrepareCampaign(ctx context.Context, id uuid.UUID, lastAttempt bool) error {
+	c, err := s.campaigns.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			s.logger.Warn("Campaign disappeared before it ran", zap.String("campaign_id", id.String()))
+			return nil
+		}
+		return fmt.Errorf("repository error finding campaign %s: %w", id, err)
+	}
+	if c.Status != campaign.StatusPending && c.Status != campaign.StatusSending {
+		return nil
+	}
+
+	err = s.prepare(ctx, c)
+	if err != nil && lastAttempt {
+		if failErr := s.campaigns.SetStatus(ctx, id, campaign.StatusFailed, err.Error(), campaign.StatusPending, campaign.StatusSending); failErr != nil {
+			s.logger.Error("Failed to mark campaign failed", zap.String("campaign_id", id.String()), zap.Error(failErr))
+		}
+	}
+	return err
+}
+
+func (s *CampaignService) prepare(ctx context.Context, c *campaign.Campaign) error {
+	params := license.ListParams{
+		ProductNames: c.Filter.ProductNames,
+		SortBy:       "created_at",
+		SortOrder:    "ASC",
+		Limit:        campaignPageSize,
+	}
+	for _, st := range c.Filter.Statuses {
+		params.Statuses = append(params.Statuses, license.LicenseStatus(st))
+	}
+	if days := c.Filter.ExpiresWithinDays; days > 0 {
+		before := c.CreatedAt.AddDate(0, 0, days)
+		params.ExpiresAfter, params.ExpiresBefore = &c.CreatedAt, &before
+	}
+
+	batchSize := max(s.cfg.BatchSize, 1)
+	seen := make(map[string]struct{})
+	for {
+		licenses, _, err := s.licenses.List(ctx, params)
+		if err != nil {
+			return fmt.Errorf("repository error listing licenses: %w", err)
+		}
+
+		recipients := make([]*campaign.Recipient, 0, len(licenses))
+		for _, lic := range licenses {
+			if len(params.Statuses) == 0 && lic.Status == license.StatusRevoked {
+				continue
+			}
+			if !lic.CustomerEmail.Valid || lic.CustomerEmail.String == "" {
+				continue
+			}
+			email := strings.ToLower(lic.CustomerEmail.String)
+			if _, ok := seen[email]; ok {
+				continue
+			}
+			seen[email] = struct{}{}
+			recipients = append(recipients, &campaign.Recipient{
+				CampaignID: c.ID,
+				Email:      email,
+				LicenseID:  lic.ID,
+				Batch:      (len(seen) - 1) / batchSize,
+			})
+		}
+		if len(recipients) > 0 {
+			if err := s.campaigns.AddRecipients(ctx, recipients); err != nil {
+				return fmt.Errorf("repository error adding campaign recipients: %w", err)
+			}
+		}
+
+		if len(licenses) < params.Limit {
+			break
+		}
+		params.Offset += params.Limit
+	}
+
+	if len(seen) == 0 {
+		s.logger.Info("Campaign matched no recipients", zap.String("campaign_id", c.ID.String()))
+		return s.finish(ctx, c.ID)
+	}
+	if err := s.campaigns.SetStatus(ctx, c.ID, campaign.StatusSending, "", campaign.StatusPending, campaign.StatusSending); err != nil {
+		if errors.Is(err, ierr.ErrConflict) {
+			// Cancelled while the recipients were picked.
+			return nil
+		}
+		return fmt.Errorf("repository error starting campaign %s: %w", c.ID, err)
+	}
+
+	batches := (len(seen) + batchSize - 1) / batchSize
+	for b := 0; b < batches; b++ {
+		task, err := tasks.NewCampaignSendTask(tasks.CampaignSendPayload{CampaignID: c.ID, Batch: b},
+			asynq.MaxRetry(s.cfg.MaxRetries),
+			asynq.ProcessIn(time.Duration(b)*s.cfg.BatchInterval),
+			asynq.TaskID(fmt.Sprintf("campaign:%s:%d", c.ID, b)),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create campaign batch task: %w", err)
+		}
+		if _, err := s.tasks.EnqueueContext(ctx, task); err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+			return fmt.Errorf("failed to enqueue campaign batch %d: %w", b, err)
+		}
+	}
+
+	s.logger.Info("Campaign recipients picked",
+		zap.String("campaign_id", c.ID.String()),
+		zap.Int("recipients", len(seen)),
+		zap.Int("batches", batches),
+	)
+	return nil
+}
+
+// SendCampaignBatch mails the pending recipients of a batch. Failed sends
+// stay pending and fail the task so asynq retries them; on the last
+// attempt they are marked failed instead. The campaign completes once no
+// recipient is pending.
+func (s *CampaignService) SendCampaignBatch(ctx context.Context, id uuid.UUID, batch int, lastAttempt bool) error {
+	c, err := s.campaigns.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("repository error finding campaign %s: %w", id, err)
+	}
+	if c.Status != campaign.StatusSending {
+		return nil
+	}
+
+	recipients, err := s.campaigns.BatchRecipients(ctx, id, batch)
+	if err != nil {
+		return fmt.Errorf("repository error loading campaign batch %d: %w", batch, err)
+	}
+	emails := make([]string, 0, len(recipients))
+	for _, r := range recipients {
+		emails = append(emails, r.Email)
+	}
+	unsubscribed, err := s.campaigns.Unsubscribed(ctx, emails)
+	if err != nil {
+		return fmt.Errorf("repository error checking unsubscribes: %w", err)
+	}
+
+	sent, failed := 0, 0
+	for _, r := range recipients {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if unsubscribed[r.Email] {
+			r.Status = campaign.RecipientUnsubscribed
+		} else if err := s.sendTo(ctx, c, r); err != nil {
+			s.logger.Warn("Failed to send campaign e-mail", zap.String("campaign_id", id.String()), zap.String("recipient_id", r.ID.String()), zap.Error(err))
+			r.LastError = err.Error()
+			if lastAttempt || errors.Is(err, ierr.ErrNotFound) {
+				r.Status = campaign.RecipientFailed
+			} else {
+				failed++
+			}
+		} else {
+			now := time.Now().UTC()
+			r.Status, r.SentAt, r.LastError = campaign.RecipientSent, &now, ""
+			sent++
+		}
+		if err := s.campaigns.UpdateRecipient(ctx, r); err != nil {
+			return fmt.Errorf("repository error updating campaign recipient %s: %w", r.ID, err)
+		}
+	}
+
+	s.logger.Info("Campaign batch sent",
+		zap.String("campaign_id", id.String()),
+		zap.Int("batch", batch),
+		zap.Int("sent", sent),
+		zap.Int("failed", failed),
+	)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d campaign e-mails in batch %d failed", failed, len(recipients), batch)
+	}
+	return s.finish(ctx, id)
+}
+
+// sendTo renders the campaign for the recipient's license and mails it.
+// It returns ierr.ErrNotFound when the license has been deleted.
+func (s *CampaignService) sendTo(ctx context.Context, c *campaign.Campaign, r *campaign.Recipient) error {
+	r.Attempts++
+	lic, err := s.licenses.FindByID(ctx, r.LicenseID)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return fmt.Errorf("%w: license no longer exists", ierr.ErrNotFound)
+		}
+		return fmt.Errorf("repository error finding license %s: %w", r.LicenseID, err)
+	}
+
+	unsubscribeURL := s.cfg.UnsubscribeURL + "?token=" + url.QueryEscape(util.SignUnsubscribeToken(s.crypto, []byte(s.cfg.UnsubscribeSecret), r.ID))
+	rendered, err := s.renderer.Render(templates.Campaign, lic.Locale(), templates.CampaignData{
+		CustomerName:   lic.CustomerName.String,
+		ProductName:    lic.ProductName,
+		Subject:        c.Subject,
+		Message:        c.Message,
+		UnsubscribeURL: unsubscribeURL,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render campaign e-mail: %w", err)
+	}
+	return s.mailer.Send(ctx, notify.Message{
+		To:             r.Email,
+		Subject:        rendered.Subject,
+		Body:           rendered.Body,
+		LicenseID:      lic.ID,
+		UnsubscribeURL: unsubscribeURL,
+	})
+}
+
+// finish completes the campaign when no recipient is left pending. Batches
+// finishing at the same time may both try; the later one gets a conflict.
+func (s *CampaignService) finish(ctx context.Context, id uuid.UUID) error {
+	counts, err := s.campaigns.CountRecipients(ctx, id)
+	if err != nil {
+		return fmt.Errorf("repository error counting campaign recipients: %w", err)
+	}
+	if counts[campaign.RecipientPending] > 0 {
+		return nil
+	}
+	err = s.campaigns.SetStatus(ctx, id, campaign.StatusCompleted, "", campaign.StatusPending, campaign.StatusSending)
+	if err != nil && !errors.Is(err, ierr.ErrConflict) {
+		return fmt.Errorf("repository error completing campaign %s: %w", id, err)
+	}
+	if err == nil {
+		s.logger.Info("Campaign completed", zap.String("campaign_id", id.String()))
+	}
+	return nil
+}
+
+func campaignResponse(c *campaign.Campaign) *dto.CampaignResponse {
+	return &dto.CampaignResponse{
+		ID:          c.ID,
+		Name:        c.Name,
+		Subject:     c.Subject,
+		Message:     c.Message,
+		Filter:      c.Filter,
+		Status:      c.Status,
+		RequestedBy: c.RequestedBy,
+		Error:       c.Error,
+		CreatedAt:   c.CreatedAt,
+		StartedAt:   c.StartedAt,
+		FinishedAt:  c.FinishedAt,
+	}
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/outbox"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+var changeFeedFields = map[string]bool{
+	"license_key": true, "status": true, "type": true, "customer_name": true,
+	"customer_email": true, "customer_id": true, "product_name": true, "metadata": true,
+	"issued_at": true, "expires_at": true, "starts_at": true,
+	"floating": true,
+}
+
+// subscriptionBatchSize is how many outbox events a subscription reads per
+// query while catching up.
+const subscriptionBatchSize = 500
+
+var ErrTooManySubscriptions = ierr.New("TOO_MANY_SUBSCRIPTIONS", http.StatusServiceUnavailable, "too many license event subscriptions, try again later").
+	WithPublicMessage("Too many license event subscriptions are open; try again later.")
+
+// ChangeFeedService exposes the license outbox as an incremental sync feed.
+// The cursor is the outbox event ID, which also serves as the version of the
+// license as of that change.
+type ChangeFeedService struct {
+	outbox        outbox.Repository
+	pollInterval  time.Duration
+	subscriptions chan struct{}
+	logger        *zap.Logger
+}
+
+func NewChangeFeedService(outboxRepo outbox.Repository, cfg *config.ChangeFeedConfig, logger *zap.Logger) *ChangeFeedService {
+	pollInterval := cfg.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = 2 * time.Second
+	}
+	return &ChangeFeedService{
+		outbox:        outboxRepo,
+		pollInterval:  pollInterval,
+		subscriptions: make(chan struct{}, max(cfg.MaxSubscriptions, 1)),
+		logger:        logger.Named("ChangeFeedService"),
+	}
+}
+
+func (s *ChangeFeedService) ListChanges(ctx context.Context, req *dto.ListChangesRequest) (*dto.LicenseChangesResponse, error) {
+	since, fields, err := parseChangeFeedQuery(req.Since, req.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ask for one extra event to tell the client whether to keep paging.
+	events, err := s.outbox.ListAfter(ctx, outbox.Query{AfterID: since, Limit: req.Limit + 1, Fields: fields})
+	if err != nil {
+		s.logger.Error("Failed to list license changes", zap.Int64("since", since), zap.Error(err))
+		return nil, fmt.Errorf("repository error listing license changes: %w", err)
+	}
+
+	resp := &dto.LicenseChangesResponse{
+		Changes:    make([]dto.LicenseChange, 0, len(events)),
+		NextCursor: strconv.FormatInt(since, 10),
+	}
+	if len(events) > req.Limit {
+		events = events[:req.Limit]
+		resp.HasMore = true
+	}
+	for _, e := range events {
+		resp.Changes = append(resp.Changes, licenseChange(e))
+	}
+	if len(events) > 0 {
+		resp.NextCursor = strconv.FormatInt(events[len(events)-1].ID, 10)
+	}
+	return resp, nil
+}
+
+// StreamChanges emits every change after req.Since in cursor order. The
+// version of the last change emitted is the cursor to resume from.
+func (s *ChangeFeedService) StreamChanges(ctx context.Context, req *dto.ExportChangesRequest, emit func(dto.LicenseChange) error) error {
+	since, fields, err := parseChangeFeedQuery(req.Since, req.Fields)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	err = s.outbox.StreamAfter(ctx, outbox.Query{AfterID: since, Fields: fields}, func(e *outbox.Event) error {
+		count++
+		return emit(licenseChange(e))
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			s.logger.Info("License change export cancelled by client", zap.Int64("since", since), zap.Int("streamed", count))
+			return err
+		}
+		return fmt.Errorf("repository error streaming license changes: %w", err)
+	}
+	s.logger.Info("License change export completed", zap.Int64("since", since), zap.Int("streamed", count))
+	return nil
+}
+
+// Subscribe emits every change after req.Since and then polls for new ones
+// until ctx is cancelled or emit fails.
+func (s *ChangeFeedService) Subscribe(ctx context.Context, req *dto.SubscribeChangesRequest, emit func(dto.LicenseChange) error) error {
+	if req.Since < 0 {
+		return fmt.Errorf("%w: invalid since cursor", ierr.ErrValidation)
+	}
+	if err := checkChangeFeedFields(req.Fields); err != nil {
+		return err
+	}
+	q := outbox.Query{AfterID: req.Since, Limit: subscriptionBatchSize, Fields: req.Fields, ProductName: req.ProductName}
+	if req.CustomerID != nil {
+		customerID, err := idgen.Parse(*req.CustomerID)
+		if err != nil {
+			return fmt.Errorf("%w: invalid customer_id format", ierr.ErrValidation)
+		}
+		q.CustomerID = &customerID
+	}
+
+	select {
+	case s.subscriptions <- struct{}{}:
+		defer func() { <-s.subscriptions }()
+	default:
+		return ErrTooManySubscriptions
+	}
+	s.logger.Info("License event subscription opened", zap.Int64("since", req.Since))
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			events, err := s.outbox.ListAfter(ctx, q)
+			if err != nil {
+				if ctx.Err() != nil {
+					break
+				}
+				return fmt.Errorf("repository error listing license changes: %w", err)
+			}
+			for _, e := range events {
+				if err := emit(licenseChange(e)); err != nil {
+					return err
+				}
+				q.AfterID = e.ID
+			}
+			if len(events) < q.Limit {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("License event subscription closed", zap.Int64("last_version", q.AfterID))
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func parseChangeFeedQuery(sinceParam, fieldsParam string) (int64, []string, error) {
+	var since int64
+	if sinceParam != "" {
+		parsed, err := strconv.ParseInt(sinceParam, 10, 64)
+		if err != nil || parsed < 0 {
+			return 0, nil, fmt.Errorf("%w: invalid since cursor", ierr.ErrValidation)
+		}
+		since = parsed
+	}
+
+	var fields []string
+	if fieldsParam != "" {
+		for _, f := range strings.Split(fieldsParam, ",") {
+			fields = append(fields, strings.TrimSpace(f))
+		}
+	}
+	if err := checkChangeFeedFields(fields); err != nil {
+		return 0, nil, err
+	}
+	return since, fields, nil
+}
+
+func checkChangeFeedFields(fields []string) error {
+	for _, f := range fields {
+		if !changeFeedFields[f] {
+			return fmt.Errorf("%w: unknown field %q", ierr.ErrValidation, f)
+		}
+	}
+	return nil
+}
+
+func licenseChange(e *outbox.Event) dto.LicenseChange {
+	return dto.LicenseChange{
+		ID:        e.LicenseID,
+		Operation: string(e.Operation),
+		Fields:    e.ChangedFields,
+		Version:   e.ID,
+		ChangedAt: e.CreatedAt,
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/comment"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+// CommentService keeps the comment threads operators use to coordinate on
+// a license. Only the author of a comment can delete it.
+type CommentService struct {
+	comments comment.Repository
+	licenses license.Repository
+	logger   *zap.Logger
+}
+
+func NewCommentService(comments comment.Repository, licenses license.Repository, logger *zap.Logger) *CommentService {
+	return &CommentService{
+		comments: comments,
+		licenses: licenses,
+		logger:   logger.Named("CommentService"),
+	}
+}
+
+func (s *CommentService) Create(ctx context.Context, licenseID uuid.UUID, req *dto.CreateCommentRequest, claims *ZitadelClaims) (*comment.Comment, error) {
+	if claims == nil || claims.Subject == "" {
+		return nil, ierr.ErrUnauthorized
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, fmt.Errorf("%w: comment body is empty", ierr.ErrValidation)
+	}
+	if err := s.checkLicense(ctx, licenseID); err != nil {
+		return nil, err
+	}
+
+	c := &comment.Comment{
+		LicenseID:  licenseID,
+		Author:     claims.Subject,
+		AuthorName: commentAuthorName(claims),
+		Body:       body,
+	}
+	if err := s.comments.Create(ctx, c); err != nil {
+		return nil, fmt.Errorf("repository error creating comment: %w", err)
+	}
+	s.logger.Info("License comment added", zap.String("license_id", licenseID.String()), zap.String("comment_id", c.ID.String()), zap.String("by", claims.Subject))
+	return c, nil
+}
+
+// commentAuthorName is the name shown next to a comment: the user's name,
+// else their username or e-mail address.
+func commentAuthorName(claims *ZitadelClaims) string {
+	for _, name := range []string{claims.Name, claims.PreferredUsername, claims.Email} {
+		if name != "" {
+			return truncate(name, 255)
+		}
+	}
+	return ""
+}
+
+func (s *CommentService) List(ctx context.Context, licenseID uuid.UUID, req *dto.ListCommentsRequest) ([]*comment.Comment, int64, error) {
+	if err := s.checkLicense(ctx, licenseID); err != nil {
+		return nil, 0, err
+	}
+	comments, total, err := s.comments.List(ctx, licenseID, req.Limit, req.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("repository error listing comments: %w", err)
+	}
+	return comments, total, nil
+}
+
+func (s *CommentService) Delete(ctx context.Context, licenseID, id uuid.UUID, claims *ZitadelClaims) error {
+	if claims == nil || claims.Subject == "" {
+		return ierr.ErrUnauthorized
+	}
+	c, err := s.comments.FindByID(ctx, licenseID, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("repository error finding comment %s: %w", id, err)
+	}
+	if c.Author != claims.Subject {
+		return fmt.Errorf("%w: only the author can delete a comment", ierr.ErrForbidden)
+	}
+	if err := s.comments.Delete(ctx, id); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("repository error deleting comment %s: %w", id, err)
+	}
+	s.logger.Info("License comment deleted", zap.String("license_id", licenseID.String()), zap.String("comment_id", id.String()), zap.String("by", claims.Subject))
+	return nil
+}
+
+func (s *CommentService) checkLicense(ctx context.Context, licenseID uuid.UUID) error {
+	if _, err := s.licenses.FindByID(ctx, licenseID); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("repository error finding license %s: %w", licenseID, err)
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/customstatus"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+// CustomStatusService manages the custom license statuses and keeps a
+// snapshot of them in memory, so that request binding and validation look
+// them up without a query. Run reloads the snapshot to pick up changes made
+// on other instances.
+type CustomStatusService struct {
+	repo     customstatus.Repository
+	licenses license.Repository
+	cfg      *config.CustomStatusesConfig
+	logger   *zap.Logger
+
+	mu       sync.RWMutex
+	statuses map[license.LicenseStatus]*customstatus.Status
+}
+
+func NewCustomStatusService(repo customstatus.Repository, licenses license.Repository, cfg *config.CustomStatusesConfig, logger *zap.Logger) *CustomStatusService {
+	return &CustomStatusService{
+		repo:     repo,
+		licenses: licenses,
+		cfg:      cfg,
+		logger:   logger.Named("CustomStatusService"),
+		statuses: map[license.LicenseStatus]*customstatus.Status{},
+	}
+}
+
+// Load replaces the snapshot with the statuses in the repository.
+func (s *CustomStatusService) Load(ctx context.Context) error {
+	list, err := s.repo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("loading custom statuses: %w", err)
+	}
+	statuses := make(map[license.LicenseStatus]*customstatus.Status, len(list))
+	for _, status := range list {
+		statuses[status.Name] = status
+	}
+
+	s.mu.Lock()
+	s.statuses = statuses
+	s.mu.Unlock()
+	return nil
+}
+
+// Run reloads the snapshot every cfg.RefreshInterval until ctx is done.
+func (s *CustomStatusService) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.Load(ctx); err != nil && ctx.Err() == nil {
+				s.logger.Error("Failed to reload custom statuses", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (s *CustomStatusService) Lookup(name license.LicenseStatus) (*customstatus.Status, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	status, ok := s.statuses[name]
+	return status, ok
+}
+
+// Known reports whether name is a built-in status or an existing custom one.
+func (s *CustomStatusService) Known(name license.LicenseStatus) bool {
+	if name.IsBuiltin() {
+		return true
+	}
+	_, ok := s.Lookup(name)
+	return ok
+}
+
+// Admits reports whether validation accepts a license in the custom status
+// name, and the warning to return with it for BehaviorWarn.
+func (s *CustomStatusService) Admits(name license.LicenseStatus) (bool, string) {
+	status, ok := s.Lookup(name)
+	if !ok {
+		return false, ""
+	}
+	switch status.Behavior {
+	case customstatus.BehaviorValid:
+		return true, ""
+	case customstatus.BehaviorWarn:
+		return true, fmt.Sprintf("license status is %s", name)
+	default:
+		return false, ""
+	}
+}
+
+// DenyingNames returns the custom statuses validation denies, which are
+// validation reasons too.
+func (s *CustomStatusService) DenyingNames() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var names []string
+	for name, status := range s.statuses {
+		if status.Behavior == customstatus.BehaviorInvalid {
+			names = append(names, string(name))
+		}
+	}
+	return names
+}
+
+func (s *CustomStatusService) List(ctx context.Context) ([]*customstatus.Status, error) {
+	statuses, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing custom statuses: %w", err)
+	}
+	return statuses, nil
+}
+
+func (s *CustomStatusService) Create(ctx context.Context, req *dto.CreateCustomStatusRequest) (*customstatus.Status, error) {
+	status := &customstatus.Status{
+		Name:        license.LicenseStatus(strings.TrimSpace(req.Name)),
+		Behavior:    req.Behavior,
+		Description: strings.TrimSpace(req.Description),
+	}
+	if err := customstatus.ValidateName(status.Name); err != nil {
+		return nil, err
+	}
+	if !status.Behavior.Valid() {
+		return nil, fmt.Errorf("%w: behavior must be valid, warn or invalid", ierr.ErrValidation)
+	}
+	if err := s.repo.Create(ctx, status); err != nil {
+		if errors.Is(err, ierr.ErrDuplicateKey) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error creating custom status: %w", err)
+	}
+
+	s.logger.Info("Custom license status created", zap.String("name", string(status.Name)), zap.String("behavior", string(status.Behavior)))
+	s.reload(ctx)
+	return status, nil
+}
+
+func (s *CustomStatusService) Update(ctx context.Context, name license.LicenseStatus, req *dto.UpdateCustomStatusRequest) (*customstatus.Status, error) {
+	if name.IsBuiltin() {
+		return nil, fmt.Errorf("%w: built-in status %s cannot be changed", ierr.ErrValidation, name)
+	}
+	current, ok := s.Lookup(name)
+	if !ok {
+		// The snapshot may lag behind another instance.
+		s.reload(ctx)
+		if current, ok = s.Lookup(name); !ok {
+			return nil, fmt.Errorf("%w: custom status %s not found", ierr.ErrNotFound, name)
+		}
+	}
+
+	status := *current
+	if req.Behavior != nil {
+		status.Behavior = *req.Behavior
+	}
+	if req.Description != nil {
+		status.Description = strings.TrimSpace(*req.Description)
+	}
+	if !status.Behavior.Valid() {
+		return nil, fmt.Errorf("%w: behavior must be valid, warn or invalid", ierr.ErrValidation)
+	}
+	if err := s.repo.Update(ctx, &status); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error updating custom status %s: %w", name, err)
+	}
+
+	s.logger.Info("Custom license status updated", zap.String("name", string(name)), zap.String("behavior", string(status.Behavior)))
+	s.reload(ctx)
+	return &status, nil
+}
+
+// Delete removes a custom status no license is in.
+func (s *CustomStatusService) Delete(ctx context.Context, name license.LicenseStatus) error {
+	if name.IsBuiltin() {
+		return fmt.Errorf("%w: built-in status %s cannot be deleted", ierr.ErrValidation, name)
+	}
+	_, total, err := s.licenses.List(ctx, license.ListParams{Status: &name, Limit: 1})
+	if err != nil {
+		return fmt.Errorf("repository error counting licenses in status %s: %w", name, err)
+	}
+	if total > 0 {
+		return fmt.Errorf("%w: %d license(s) are in status %s; move them to another status first", ierr.ErrConflict, total, name)
+	}
+	if err := s.repo.Delete(ctx, name); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return fmt.Errorf("%w: custom status %s not found", ierr.ErrNotFound, name)
+		}
+		return fmt.Errorf("repository error deleting custom status %s: %w", name, err)
+	}
+
+	s.logger.Info("Custom license status deleted", zap.String("name", string(name)))
+	s.reload(ctx)
+	return nil
+}
+
+func (s *CustomStatusService) reload(ctx context.Context) {
+	if err := s.Load(ctx); err != nil {
+		s.logger.Warn("Failed to reload custom statuses after a change", zap.Error(err))
+	}
+}
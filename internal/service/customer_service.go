@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/customer"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type CustomerService struct {
+	customers customer.Repository
+	logger    *zap.Logger
+}
+
+func NewCustomerService(customers customer.Repository, logger *zap.Logger) *CustomerService {
+	return &CustomerService{
+		customers: customers,
+		logger:    logger.Named("CustomerService"),
+	}
+}
+
+func (s *CustomerService) CreateCustomer(ctx context.Context, req *dto.CreateCustomerRequest) (*customer.Customer, error) {
+	c := &customer.Customer{
+		Email: strings.TrimSpace(req.Email),
+		Name:  strings.TrimSpace(req.Name),
+	}
+	if err := s.customers.Create(ctx, c); err != nil {
+		if errors.Is(err, ierr.ErrDuplicateKey) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error creating customer: %w", err)
+	}
+
+	s.logger.Info("Customer created", zap.String("id", c.ID.String()))
+	return c, nil
+}
+
+func (s *CustomerService) ListCustomers(ctx context.Context, req *dto.ListCustomersRequest) ([]*customer.Customer, int64, error) {
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	customers, total, err := s.customers.List(ctx, customer.ListParams{
+		Email:  req.Email,
+		Name:   req.Name,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("repository error listing customers: %w", err)
+	}
+	return customers, total, nil
+}
+
+func (s *CustomerService) GetCustomer(ctx context.Context, id uuid.UUID) (*customer.Customer, error) {
+	c, err := s.customers.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error finding customer %s: %w", id, err)
+	}
+	return c, nil
+}
+
+// UpdateCustomer also rewrites the customer's name and e-mail on its
+// licenses. Cached licenses may show the old values until they expire from
+// the cache.
+func (s *CustomerService) UpdateCustomer(ctx context.Context, id uuid.UUID, req *dto.UpdateCustomerRequest) (*customer.Customer, error) {
+	c, err := s.GetCustomer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Email != nil {
+		c.Email = strings.TrimSpace(*req.Email)
+	}
+	if req.Name != nil {
+		c.Name = strings.TrimSpace(*req.Name)
+	}
+	if err := s.customers.Update(ctx, c); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) || errors.Is(err, ierr.ErrDuplicateKey) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error updating customer %s: %w", id, err)
+	}
+
+	s.logger.Info("Customer updated", zap.String("id", id.String()))
+	return c, nil
+}
+
+// DeleteCustomer only removes customers without licenses; the database
+// refuses to delete one that licenses still reference.
+func (s *CustomerService) DeleteCustomer(ctx context.Context, id uuid.UUID) error {
+	if err := s.customers.Delete(ctx, id); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) || errors.Is(err, ierr.ErrConflict) {
+			return err
+		}
+		return fmt.Errorf("repository error deleting customer %s: %w", id, err)
+	}
+
+	s.logger.Info("Customer deleted", zap.String("id", id.String()))
+	return nil
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/makkenzo/license-service-api/internal/domain/dashboard"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type DashboardService struct {
+	widgets dashboard.Repository
+	logger  *zap.Logger
+}
+
+func NewDashboardService(widgets dashboard.Repository, logger *zap.Logger) *DashboardService {
+	return &DashboardService{
+		widgets: widgets,
+		logger:  logger.Named("DashboardService"),
+	}
+}
+
+func (s *DashboardService) CreateWidget(ctx context.Context, orgID string, req *dto.CreateWidgetRequest) (*dashboard.Widget, error) {
+	params, err := dashboard.DecodeParams(req.Kind, req.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.widgets.Count(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error counting dashboard widgets: %w", err)
+	}
+	if count >= dashboard.MaxWidgetsPerOrg {
+		return nil, fmt.Errorf("%w: a dashboard can hold at most %d widgets", ierr.ErrConflict, dashboard.MaxWidgetsPerOrg)
+	}
+
+	w := &dashboard.Widget{
+		OrgID:    orgID,
+		Kind:     req.Kind,
+		Title:    req.Title,
+		Position: req.Position,
+		Params:   params,
+	}
+	if err := s.widgets.Create(ctx, w); err != nil {
+		return nil, fmt.Errorf("repository error creating dashboard widget: %w", err)
+	}
+
+	s.logger.Info("Dashboard widget created", zap.String("id", w.ID.String()), zap.String("org_id", orgID), zap.String("kind", string(w.Kind)))
+	return w, nil
+}
+
+func (s *DashboardService) ListWidgets(ctx context.Context, orgID string) ([]*dashboard.Widget, error) {
+	widgets, err := s.widgets.List(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing dashboard widgets: %w", err)
+	}
+	return widgets, nil
+}
+
+func (s *DashboardService) GetWidget(ctx context.Context, orgID, id string) (*dashboard.Widget, error) {
+	widgetID, err := idgen.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	w, err := s.widgets.FindByID(ctx, orgID, widgetID)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error finding dashboard widget %s: %w", id, err)
+	}
+	return w, nil
+}
+
+func (s *DashboardService) UpdateWidget(ctx context.Context, orgID, id string, req *dto.UpdateWidgetRequest) (*dashboard.Widget, error) {
+	w, err := s.GetWidget(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		w.Title = *req.Title
+	}
+	if req.Position != nil {
+		w.Position = *req.Position
+	}
+	if req.Params != nil {
+		if w.Params, err = dashboard.DecodeParams(w.Kind, req.Params); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.widgets.Update(ctx, w); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error updating dashboard widget %s: %w", id, err)
+	}
+
+	s.logger.Info("Dashboard widget updated", zap.String("id", w.ID.String()), zap.String("org_id", orgID))
+	return w, nil
+}
+
+func (s *DashboardService) DeleteWidget(ctx context.Context, orgID, id string) error {
+	widgetID, err := idgen.Parse(id)
+	if err != nil {
+		return err
+	}
+	if err := s.widgets.Delete(ctx, orgID, widgetID); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("repository error deleting dashboard widget %s: %w", id, err)
+	}
+
+	s.logger.Info("Dashboard widget deleted", zap.String("id", id), zap.String("org_id", orgID))
+	return nil
+}
+
+// ResolveSummary merges the stored parameters of the requested widget with
+// the explicit query parameters, which take precedence.
+func (s *DashboardService) ResolveSummary(ctx context.Context, orgID string, req *dto.DashboardSummaryRequest) (dashboard.SummaryParams, error) {
+	var params dashboard.SummaryParams
+	if req.WidgetID != nil {
+		if err := s.storedParams(ctx, orgID, *req.WidgetID, dashboard.KindSummary, &params); err != nil {
+			return params, err
+		}
+	}
+	if req.ExpiringPeriodDays != nil {
+		params.ExpiringPeriodDays = *req.ExpiringPeriodDays
+	}
+	return params, nil
+}
+
+// ResolveTimeseries works like ResolveSummary. Without a widget, field and
+// bucket must be given explicitly.
+func (s *DashboardService) ResolveTimeseries(ctx context.Context, orgID string, req *dto.DashboardTimeseriesRequest) (dashboard.TimeseriesParams, error) {
+	var params dashboard.TimeseriesParams
+	if req.WidgetID != nil {
+		if err := s.storedParams(ctx, orgID, *req.WidgetID, dashboard.KindTimeseries, &params); err != nil {
+			return params, err
+		}
+	}
+	if req.Field != nil {
+		params.Field = *req.Field
+	}
+	if req.Bucket != nil {
+		params.Bucket = *req.Bucket
+	}
+	if req.Metric != nil {
+		params.Metric = *req.Metric
+	}
+	if req.Status != nil {
+		status := string(*req.Status)
+		params.Status = &status
+	}
+	if req.ProductName != nil {
+		params.ProductName = req.ProductName
+	}
+	if req.Type != nil {
+		params.Type = req.Type
+	}
+	return params, params.Validate()
+}
+
+func (s *DashboardService) storedParams(ctx context.Context, orgID, widgetID string, kind dashboard.WidgetKind, params interface{}) error {
+	w, err := s.GetWidget(ctx, orgID, widgetID)
+	if err != nil {
+		return err
+	}
+	if w.Kind != kind {
+		return fmt.Errorf("%w: widget %s is a %s widget, not %s", ierr.ErrValidation, widgetID, w.Kind, kind)
+	}
+	if err := json.Unmarshal(w.Params, params); err != nil {
+		s.logger.Error("Stored dashboard widget params are unreadable", zap.String("id", widgetID), zap.Error(err))
+		return fmt.Errorf("decoding params of dashboard widget %s: %w", widgetID, err)
+	}
+	return nil
+}
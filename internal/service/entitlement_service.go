@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/entitlement"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type EntitlementService struct {
+	entitlements entitlement.Repository
+	licenses     license.Repository
+	logger       *zap.Logger
+}
+
+func NewEntitlementService(entitlements entitlement.Repository, licenses license.Repository, logger *zap.Logger) *EntitlementService {
+	return &EntitlementService{
+		entitlements: entitlements,
+		licenses:     licenses,
+		logger:       logger.Named("EntitlementService"),
+	}
+}
+
+func (s *EntitlementService) List(ctx context.Context, licenseID uuid.UUID) ([]*entitlement.Entitlement, error) {
+	if err := s.checkLicense(ctx, licenseID); err != nil {
+		return nil, err
+	}
+	entitlements, err := s.entitlements.ListByLicense(ctx, licenseID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing entitlements of license %s: %w", licenseID, err)
+	}
+	return entitlements, nil
+}
+
+func (s *EntitlementService) Put(ctx context.Context, licenseID uuid.UUID, name string, req *dto.PutEntitlementRequest) (*entitlement.Entitlement, error) {
+	if err := entitlement.ValidateName(name); err != nil {
+		return nil, err
+	}
+	value, err := entitlement.NormalizeValue(req.Type, req.Value)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkLicense(ctx, licenseID); err != nil {
+		return nil, err
+	}
+
+	e := &entitlement.Entitlement{LicenseID: licenseID, Name: name, Type: req.Type, Value: value}
+	if err := s.entitlements.Put(ctx, e); err != nil {
+		return nil, fmt.Errorf("repository error saving entitlement %s of license %s: %w", name, licenseID, err)
+	}
+	s.logger.Info("Entitlement saved", zap.String("license_id", licenseID.String()), zap.String("name", name), zap.String("type", string(e.Type)))
+	return e, nil
+}
+
+func (s *EntitlementService) Delete(ctx context.Context, licenseID uuid.UUID, name string) error {
+	if err := s.entitlements.Delete(ctx, licenseID, name); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return fmt.Errorf("%w: license %s has no entitlement %q", ierr.ErrNotFound, licenseID, name)
+		}
+		return fmt.Errorf("repository error deleting entitlement %s of license %s: %w", name, licenseID, err)
+	}
+	s.logger.Info("Entitlement deleted", zap.String("license_id", licenseID.String()), zap.String("name", name))
+	return nil
+}
+
+func (s *EntitlementService) checkLicense(ctx context.Context, licenseID uuid.UUID) error {
+	if _, err := s.licenses.FindByID(ctx, licenseID); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("repository error finding license %s: %w", licenseID, err)
+	}
+	return nil
+}
+
+// allowedData is what agents receive as allowed_data: the entitlements of a
+// license keyed by name with their typed values, or nil when it has none.
+func allowedData(entitlements []*entitlement.Entitlement) (json.RawMessage, error) {
+	if len(entitlements) == 0 {
+		return nil, nil
+	}
+	values := make(map[string]json.RawMessage, len(entitlements))
+	for _, e := range entitlements {
+		values[e.Name] = e.Value
+	}
+	return json.Marshal(map[string]interface{}{"entitlements": values})
+}
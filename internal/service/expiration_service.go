@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"go.uber.org/zap"
+)
+
+// maxExpirationPreviewLicenses caps each list of the expiration preview.
+const maxExpirationPreviewLicenses = 1000
+
+// ExpirationService answers what the expiration job would do, using the
+// same rule: an active license is marked expired once License.Lapsed.
+type ExpirationService struct {
+	repo   license.Repository
+	logger *zap.Logger
+}
+
+func NewExpirationService(repo license.Repository, logger *zap.Logger) *ExpirationService {
+	return &ExpirationService{
+		repo:   repo,
+		logger: logger.Named("ExpirationService"),
+	}
+}
+
+func (s *ExpirationService) Preview(ctx context.Context, req *dto.ExpirationPreviewRequest) (*dto.ExpirationPreviewResponse, error) {
+	now := time.Now().UTC()
+	until := now.AddDate(0, 0, req.Days)
+	resp := &dto.ExpirationPreviewResponse{
+		GeneratedAt: now,
+		Days:        req.Days,
+		Until:       until,
+		Overdue:     []dto.ExpiringLicense{},
+		Upcoming:    []dto.ExpiringLicense{},
+	}
+
+	// A grace period only delays the flip, so every license that lapses by
+	// until has expired by then.
+	params := license.ListParams{
+		Status:        ptr(license.StatusActive),
+		ExpiresBefore: &until,
+		SortBy:        "expires_at",
+		SortOrder:     "ASC",
+		Limit:         1000,
+	}
+	for {
+		licenses, _, err := s.repo.List(ctx, params)
+		if err != nil {
+			s.logger.Error("Failed to list licenses for expiration preview", zap.Error(err))
+			return nil, fmt.Errorf("repository error listing expiring licenses: %w", err)
+		}
+
+		for _, lic := range licenses {
+			switch {
+			case lic.Lapsed(now):
+				resp.OverdueCount++
+				resp.Overdue = appendExpiring(resp, resp.Overdue, lic)
+			case lic.Lapsed(until):
+				resp.UpcomingCount++
+				resp.Upcoming = appendExpiring(resp, resp.Upcoming, lic)
+			}
+		}
+
+		if len(licenses) < params.Limit {
+			break
+		}
+		params.Offset += params.Limit
+	}
+
+	for _, list := range [][]dto.ExpiringLicense{resp.Overdue, resp.Upcoming} {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].FlipsAt.Before(list[j].FlipsAt) })
+	}
+
+	s.logger.Info("Expiration preview built",
+		zap.Int("days", req.Days),
+		zap.Int("overdue", resp.OverdueCount),
+		zap.Int("upcoming", resp.UpcomingCount),
+	)
+	return resp, nil
+}
+
+func appendExpiring(resp *dto.ExpirationPreviewResponse, list []dto.ExpiringLicense, lic *license.License) []dto.ExpiringLicense {
+	if len(list) >= maxExpirationPreviewLicenses {
+		resp.Truncated = true
+		return list
+	}
+	flipsAt, _ := lic.GraceExpiresAt()
+	item := dto.ExpiringLicense{
+		ID:              lic.ID,
+		LicenseKey:      lic.LicenseKey,
+		ProductName:     lic.ProductName,
+		Type:            lic.Type,
+		ExpiresAt:       lic.ExpiresAt.Time.UTC(),
+		GracePeriodDays: lic.GracePeriodDays,
+		FlipsAt:         flipsAt,
+	}
+	if lic.CustomerEmail.Valid {
+		item.CustomerEmail = &lic.CustomerEmail.String
+	}
+	return append(list, item)
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/expirynotice"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+// ExpiryNoticeService shows which expiry e-mails a license's customer got
+// and lets the customer opt out of them.
+type ExpiryNoticeService struct {
+	licenses license.Repository
+	notices  expirynotice.Repository
+	logger   *zap.Logger
+}
+
+func NewExpiryNoticeService(licenses license.Repository, notices expirynotice.Repository, logger *zap.Logger) *ExpiryNoticeService {
+	return &ExpiryNoticeService{
+		licenses: licenses,
+		notices:  notices,
+		logger:   logger.Named("ExpiryNoticeService"),
+	}
+}
+
+func (s *ExpiryNoticeService) Get(ctx context.Context, id uuid.UUID) (*dto.ExpiryNotificationsResponse, error) {
+	if err := s.ensureLicense(ctx, id); err != nil {
+		return nil, err
+	}
+
+	optedOut, err := s.notices.OptedOut(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("repository error reading expiry e-mail opt-out for license %s: %w", id, err)
+	}
+	notices, err := s.notices.ListByLicense(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing expiry notices for license %s: %w", id, err)
+	}
+
+	resp := &dto.ExpiryNotificationsResponse{
+		LicenseID: id,
+		OptedOut:  optedOut,
+		Notices:   make([]dto.ExpiryNoticeResponse, 0, len(notices)),
+	}
+	for _, n := range notices {
+		resp.Notices = append(resp.Notices, dto.ExpiryNoticeResponse{
+			Kind:       string(n.Kind),
+			DaysBefore: n.DaysBefore,
+			ExpiresAt:  n.ExpiresAt,
+			SentAt:     n.SentAt,
+		})
+	}
+	return resp, nil
+}
+
+func (s *ExpiryNoticeService) SetOptOut(ctx context.Context, id uuid.UUID, optOut bool) (*dto.ExpiryNotificationsResponse, error) {
+	s.logger.Info("Setting expiry e-mail opt-out", zap.String("id", id.String()), zap.Bool("opt_out", optOut))
+
+	if err := s.ensureLicense(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.notices.SetOptOut(ctx, id, optOut); err != nil {
+		return nil, fmt.Errorf("repository error setting expiry e-mail opt-out for license %s: %w", id, err)
+	}
+	return s.Get(ctx, id)
+}
+
+func (s *ExpiryNoticeService) ensureLicense(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.licenses.FindByID(ctx, id); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("repository error finding license %s: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+// ErrExportTooLarge stops exports that reach the configured maximum of
+// licenses.
+var ErrExportTooLarge = ierr.ErrValidation.Derive("EXPORT_TOO_LARGE", "export is too large")
+
+// ExportService streams licenses for bulk exports. Licenses are read
+// through a database cursor and handed on one at a time, so memory use does
+// not grow with the size of the export.
+type ExportService struct {
+	licenses license.Exporter
+	limits   *config.QueryConfig
+	logger   *zap.Logger
+}
+
+func NewExportService(licenses license.Exporter, limits *config.QueryConfig, logger *zap.Logger) *ExportService {
+	return &ExportService{
+		licenses: licenses,
+		limits:   limits,
+		logger:   logger.Named("ExportService"),
+	}
+}
+
+// ExportLicenses streams the licenses matching req to emit. An export is not
+// counted up front, since a count is as expensive as the export itself;
+// reaching MaxExportRows stops it with ErrExportTooLarge, which reaches the
+// client as the last line of the stream.
+func (s *ExportService) ExportLicenses(ctx context.Context, req *dto.ExportLicensesRequest, emit func(*dto.LicenseResponse) error) error {
+	if req.CreatedAfter != nil && req.CreatedBefore != nil && !req.CreatedAfter.Before(*req.CreatedBefore) {
+		return fmt.Errorf("%w: created_after must be before created_before", ierr.ErrValidation)
+	}
+	params := license.ListParams{
+		Status:        req.Status,
+		CustomerEmail: req.CustomerEmail,
+		ProductName:   req.ProductName,
+		Type:          req.Type,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+	}
+	if req.Tag != nil {
+		params.Tag = ptr(license.NormalizeTag(*req.Tag))
+	}
+
+	count := 0
+	err := s.licenses.Export(ctx, params, func(lic *license.License) error {
+		if s.limits.MaxExportRows > 0 && int64(count) >= s.limits.MaxExportRows {
+			s.logger.Warn("Stopped oversized license export", zap.Int64("max_rows", s.limits.MaxExportRows))
+			return fmt.Errorf("%w: export stopped after %d licenses, the most one export may stream; split it with created_after and created_before",
+				ErrExportTooLarge, s.limits.MaxExportRows)
+		}
+		count++
+		return emit(dto.NewLicenseResponse(lic))
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			s.logger.Info("License export cancelled by client", zap.Int("streamed", count))
+			return err
+		}
+		if errors.Is(err, ErrExportTooLarge) {
+			return err
+		}
+		return fmt.Errorf("repository error exporting licenses: %w", err)
+	}
+	s.logger.Info("License export completed", zap.Int("streamed", count))
+	return nil
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/extension"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrExtensionApproverRequired = ierr.ErrForbidden.Derive("EXTENSION_APPROVER_REQUIRED", "deciding extension requests requires the extension approver role")
+	ErrExtensionPending          = ierr.ErrConflict.Derive("EXTENSION_PENDING", "the license already has a pending extension request")
+	ErrExtensionStale            = ierr.ErrConflict.Derive("EXTENSION_STALE", "the license already expires on or after the requested date")
+)
+
+// ExtensionService runs the two-step extension of license expiry: anyone
+// may request a later expires_at, but only users with the extension
+// approver role can grant it, and only granting changes the license.
+type ExtensionService struct {
+	requests extension.Repository
+	licenses license.Repository
+	cfg      *config.ApprovalConfig
+	logger   *zap.Logger
+}
+
+func NewExtensionService(requests extension.Repository, licenses license.Repository, cfg *config.ApprovalConfig, logger *zap.Logger) *ExtensionService {
+	return &ExtensionService{
+		requests: requests,
+		licenses: licenses,
+		cfg:      cfg,
+		logger:   logger.Named("ExtensionService"),
+	}
+}
+
+func (s *ExtensionService) Create(ctx context.Context, licenseID uuid.UUID, req *dto.CreateExtensionRequest, claims *ZitadelClaims) (*extension.Request, error) {
+	if claims == nil || claims.Subject == "" {
+		return nil, ierr.ErrUnauthorized
+	}
+	lic, err := s.findLicense(ctx, licenseID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExtendable(lic); err != nil {
+		return nil, err
+	}
+	expiresAt := req.ExpiresAt.UTC()
+	if !expiresAt.After(lic.ExpiresAt.Time) {
+		return nil, fmt.Errorf("%w: expires_at must be after the current expiry %s", ierr.ErrValidation, lic.ExpiresAt.Time.UTC().Format(time.RFC3339))
+	}
+
+	r := &extension.Request{
+		LicenseID:          licenseID,
+		RequestedExpiresAt: expiresAt,
+		PreviousExpiresAt:  lic.ExpiresAt.Time,
+		Reason:             strings.TrimSpace(req.Reason),
+		RequestedBy:        claims.Subject,
+	}
+	if err := s.requests.Create(ctx, r); err != nil {
+		if errors.Is(err, ierr.ErrDuplicateKey) {
+			return nil, ErrExtensionPending
+		}
+		return nil, fmt.Errorf("repository error creating extension request for license %s: %w", licenseID, err)
+	}
+
+	s.logger.Info("License extension requested",
+		zap.String("extension_id", r.ID.String()),
+		zap.String("license_id", licenseID.String()),
+		zap.Time("requested_expires_at", expiresAt),
+		zap.String("requested_by", claims.Subject),
+	)
+	return r, nil
+}
+
+// checkExtendable rejects licenses whose expiry cannot be extended on their
+// own.
+func checkExtendable(lic *license.License) error {
+	if lic.Status == license.StatusRevoked {
+		return fmt.Errorf("%w: revoked licenses cannot be extended", ierr.ErrConflict)
+	}
+	if lic.IsChild() {
+		return fmt.Errorf("%w: child licenses are extended with their parent license", ierr.ErrConflict)
+	}
+	if !lic.ExpiresAt.Valid {
+		return fmt.Errorf("%w: license does not expire", ierr.ErrConflict)
+	}
+	return nil
+}
+
+// List returns the extension requests of licenseID, or of all licenses
+// when it is nil.
+func (s *ExtensionService) List(ctx context.Context, licenseID *uuid.UUID, req *dto.ListExtensionRequestsRequest) ([]*extension.Request, int64, error) {
+	params := extension.ListParams{LicenseID: licenseID, Limit: req.Limit, Offset: req.Offset}
+	if params.Limit <= 0 {
+		params.Limit = 20
+		req.Limit = 20
+	}
+	if req.Status != nil {
+		status := extension.Status(*req.Status)
+		params.Status = &status
+	}
+	if licenseID != nil {
+		if _, err := s.findLicense(ctx, *licenseID); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	requests, total, err := s.requests.List(ctx, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("repository error listing extension requests: %w", err)
+	}
+	return requests, total, nil
+}
+
+func (s *ExtensionService) Get(ctx context.Context, licenseID, id uuid.UUID) (*extension.Request, error) {
+	r, err := s.requests.FindByID(ctx, licenseID, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error finding extension request %s: %w", id, err)
+	}
+	return r, nil
+}
+
+// Approve grants a pending request and moves the expiry of the license to
+// the requested date, reactivating it when it had expired. The request is
+// refused when the license meanwhile expires on or after that date.
+func (s *ExtensionService) Approve(ctx context.Context, licenseID, id uuid.UUID, req *dto.DecideExtensionRequest, claims *ZitadelClaims) (*extension.Request, *license.License, error) {
+	if !claims.HasRole(s.cfg.ExtensionApproverRole) {
+		return nil, nil, ErrExtensionApproverRequired
+	}
+	r, err := s.Get(ctx, licenseID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.Status != extension.StatusPending {
+		return nil, nil, fmt.Errorf("%w: extension request is already %s", ierr.ErrConflict, r.Status)
+	}
+	if r.RequestedBy == claims.Subject {
+		return nil, nil, ErrSelfApproval
+	}
+
+	lic, err := s.findLicense(ctx, licenseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkExtendable(lic); err != nil {
+		return nil, nil, err
+	}
+	if !r.RequestedExpiresAt.After(lic.ExpiresAt.Time) {
+		return nil, nil, ErrExtensionStale
+	}
+
+	decided, err := s.requests.Decide(ctx, id, extension.StatusApproved, claims.Subject, strings.TrimSpace(req.Note))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lic.ExpiresAt.Time = r.RequestedExpiresAt
+	if lic.Status == license.StatusExpired && r.RequestedExpiresAt.After(time.Now()) {
+		lic.Status = license.StatusActive
+	}
+	if err := s.licenses.Update(ctx, lic); err != nil {
+		if reopenErr := s.requests.Reopen(context.WithoutCancel(ctx), id); reopenErr != nil {
+			s.logger.Error("Failed to reopen extension request after failed update", zap.String("extension_id", id.String()), zap.Error(reopenErr))
+		}
+		return nil, nil, fmt.Errorf("repository error extending license %s: %w", licenseID, err)
+	}
+	if _, err := syncChildren(ctx, s.licenses, lic); err != nil {
+		// The extension itself stands; the children catch up with the next
+		// change to the parent.
+		s.logger.Error("License extended but its child licenses were not", zap.String("license_id", licenseID.String()), zap.Error(err))
+	}
+
+	s.logger.Info("License extension approved",
+		zap.String("extension_id", id.String()),
+		zap.String("license_id", licenseID.String()),
+		zap.Time("new_expires_at", r.RequestedExpiresAt),
+		zap.String("requested_by", r.RequestedBy),
+		zap.String("approved_by", claims.Subject),
+	)
+	return decided, lic, nil
+}
+
+// Reject turns a pending request down. Besides approvers, the requester
+// may reject, i.e. withdraw, their own request.
+func (s *ExtensionService) Reject(ctx context.Context, licenseID, id uuid.UUID, req *dto.DecideExtensionRequest, claims *ZitadelClaims) (*extension.Request, error) {
+	r, err := s.Get(ctx, licenseID, id)
+	if err != nil {
+		return nil, err
+	}
+	if claims == nil || (r.RequestedBy != claims.Subject && !claims.HasRole(s.cfg.ExtensionApproverRole)) {
+		return nil, ErrExtensionApproverRequired
+	}
+	decided, err := s.requests.Decide(ctx, id, extension.StatusRejected, claims.Subject, strings.TrimSpace(req.Note))
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("License extension rejected", zap.String("extension_id", id.String()), zap.String("rejected_by", claims.Subject))
+	return decided, nil
+}
+
+func (s *ExtensionService) findLicense(ctx context.Context, id uuid.UUID) (*license.License, error) {
+	lic, err := s.licenses.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error finding license %s: %w", id, err)
+	}
+	return lic, nil
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/lease"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+var (
+	errFloatingLicenseNotFound = fmt.Errorf("%w: license not found for this product", ierr.ErrNotFound)
+
+	ErrNotFloating = ierr.ErrConflict.Derive("LICENSE_NOT_FLOATING", "license is not a floating license, activate it on a device instead")
+)
+
+// FloatingService hands out the seats of floating licenses as leases, so
+// that at most max_activations clients use the license at once.
+type FloatingService struct {
+	leases   lease.Repository
+	licenses license.Repository
+	cfg      *config.FloatingConfig
+	logger   *zap.Logger
+}
+
+func NewFloatingService(leases lease.Repository, licenses license.Repository, cfg *config.FloatingConfig, logger *zap.Logger) *FloatingService {
+	return &FloatingService{
+		leases:   leases,
+		licenses: licenses,
+		cfg:      cfg,
+		logger:   logger.Named("FloatingService"),
+	}
+}
+
+func (s *FloatingService) Checkout(ctx context.Context, req *dto.CheckoutRequest) (*dto.CheckoutResponse, error) {
+	lic, err := s.findLicense(ctx, req.LicenseKey, req.ProductName)
+	if err != nil {
+		return nil, err
+	}
+	if !lic.Floating {
+		return nil, ErrNotFloating
+	}
+	if lic.Status != license.StatusActive || lic.Lapsed(time.Now()) {
+		return nil, fmt.Errorf("%w: license is not active", ierr.ErrConflict)
+	}
+
+	ttl := s.cfg.LeaseTTL
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+	if s.cfg.MaxLeaseTTL > 0 && ttl > s.cfg.MaxLeaseTTL {
+		ttl = s.cfg.MaxLeaseTTL
+	}
+
+	maxActive, err := license.OwnSeats(ctx, s.licenses, lic)
+	if err != nil {
+		return nil, err
+	}
+	l, inUse, err := s.leases.Checkout(ctx, lic.ID, req.ClientID, ttl, maxActive)
+	if err != nil {
+		if errors.Is(err, lease.ErrNoFreeSeat) {
+			s.logger.Info("Checkout rejected, no free floating seats",
+				zap.String("license_id", lic.ID.String()),
+				zap.String("client_id", req.ClientID),
+				zap.Int("max_activations", maxActive),
+			)
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error checking out license %s: %w", lic.ID, err)
+	}
+
+	return &dto.CheckoutResponse{
+		LicenseID: lic.ID,
+		ClientID:  l.ClientID,
+		ExpiresAt: l.ExpiresAt,
+		Seats:     dto.FloatingSeats{InUse: inUse, Max: maxActive},
+	}, nil
+}
+
+func (s *FloatingService) Checkin(ctx context.Context, req *dto.CheckinRequest) error {
+	lic, err := s.findLicense(ctx, req.LicenseKey, req.ProductName)
+	if err != nil {
+		return err
+	}
+	if err := s.leases.Checkin(ctx, lic.ID, req.ClientID); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return fmt.Errorf("%w: client holds no seat of this license", ierr.ErrNotFound)
+		}
+		return fmt.Errorf("repository error checking in license %s: %w", lic.ID, err)
+	}
+	return nil
+}
+
+// findLicense reports a product mismatch as not found, like activation.
+func (s *FloatingService) findLicense(ctx context.Context, key, productName string) (*license.License, error) {
+	lic, err := s.licenses.FindByKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, errFloatingLicenseNotFound
+		}
+		return nil, fmt.Errorf("repository error finding license by key: %w", err)
+	}
+	if lic.ProductName != productName {
+		return nil, errFloatingLicenseNotFound
+	}
+	return lic, nil
+}
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/makkenzo/license-service-api/internal/domain/activation"
+	"github.com/makkenzo/license-service-api/internal/domain/integrity"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/product"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/metaschema"
+	"github.com/makkenzo/license-service-api/internal/tasks"
+	"go.uber.org/zap"
+)
+
+// integrityPageSize is how many licenses, or licenses with activations, an
+// integrity check loads at a time.
+const integrityPageSize = 500
+
+// IntegrityService runs on-demand integrity checks in the worker. A check
+// scans every license and activation for data that breaks the service's
+// invariants and, when asked, repairs what can be repaired safely.
+type IntegrityService struct {
+	runs        integrity.Repository
+	licenses    license.Repository
+	products    product.Repository
+	activations activation.Repository
+	tasks       *asynq.Client
+	logger      *zap.Logger
+}
+
+func NewIntegrityService(runs integrity.Repository, licenses license.Repository, products product.Repository, activations activation.Repository, taskClient *asynq.Client, logger *zap.Logger) *IntegrityService {
+	return &IntegrityService{
+		runs:        runs,
+		licenses:    licenses,
+		products:    products,
+		activations: activations,
+		tasks:       taskClient,
+		logger:      logger.Named("IntegrityService"),
+	}
+}
+
+func (s *IntegrityService) StartCheck(ctx context.Context, req *dto.StartIntegrityCheckRequest, requestedBy string) (*dto.IntegrityCheckResponse, error) {
+	repairs := slices.Clone(req.Repairs)
+	slices.Sort(repairs)
+	run := &integrity.Run{Repairs: slices.Compact(repairs), RequestedBy: requestedBy}
+	if _, err := s.runs.Create(ctx, run); err != nil {
+		return nil, fmt.Errorf("repository error creating integrity check: %w", err)
+	}
+
+	task, err := tasks.NewIntegrityCheckTask(tasks.IntegrityCheckPayload{CheckID: run.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create integrity check task: %w", err)
+	}
+	info, err := s.tasks.EnqueueContext(ctx, task)
+	if err != nil {
+		s.logger.Error("Failed to enqueue integrity check", zap.String("check_id", run.ID.String()), zap.Error(err))
+		if failErr := s.runs.Fail(ctx, run.ID, "could not be enqueued"); failErr != nil {
+			s.logger.Error("Failed to mark integrity check failed", zap.String("check_id", run.ID.String()), zap.Error(failErr))
+		}
+		return nil, fmt.Errorf("failed to enqueue integrity check: %w", err)
+	}
+
+	s.logger.Info("Integrity check enqueued",
+		zap.String("check_id", run.ID.String()),
+		zap.String("task_id", info.ID),
+		zap.Strings("repairs", run.Repairs),
+		zap.String("requested_by", requestedBy),
+	)
+	resp := integrityCheckResponse(run)
+	resp.TaskID = info.ID
+	return resp, nil
+}
+
+func (s *IntegrityService) GetCheck(ctx context.Context, id uuid.UUID) (*dto.IntegrityCheckResponse, error) {
+	run, err := s.runs.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error finding integrity check %s: %w", id, err)
+	}
+	return integrityCheckResponse(run), nil
+}
+
+// ListChecks returns the most recent checks without their reports.
+func (s *IntegrityService) ListChecks(ctx context.Context, limit int) (*dto.IntegrityCheckListResponse, error) {
+	runs, err := s.runs.List(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing integrity checks: %w", err)
+	}
+	resp := &dto.IntegrityCheckListResponse{Checks: make([]dto.IntegrityCheckResponse, 0, len(runs))}
+	for _, run := range runs {
+		check := integrityCheckResponse(run)
+		check.Report = nil
+		resp.Checks = append(resp.Checks, *check)
+	}
+	return resp, nil
+}
+
+func integrityCheckResponse(run *integrity.Run) *dto.IntegrityCheckResponse {
+	return &dto.IntegrityCheckResponse{
+		ID:          run.ID,
+		Status:      run.Status,
+		Repairs:     run.Repairs,
+		RequestedBy: run.RequestedBy,
+		Error:       run.Error,
+		CreatedAt:   run.CreatedAt,
+		StartedAt:   run.StartedAt,
+		FinishedAt:  run.FinishedAt,
+		Report:      run.Report,
+	}
+}
+
+// RunIntegrityCheck runs the check with the given ID and stores its report.
+// Checks that already finished are left alone, so a redelivered task does
+// not repair twice.
+func (s *IntegrityService) RunIntegrityCheck(ctx context.Context, id uuid.UUID) error {
+	run, err := s.runs.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			s.logger.Warn("Integrity check disappeared before it ran", zap.String("check_id", id.String()))
+			return nil
+		}
+		return fmt.Errorf("repository error finding integrity check %s: %w", id, err)
+	}
+	if run.Status == integrity.StatusCompleted || run.Status == integrity.StatusFailed {
+		return nil
+	}
+	if err := s.runs.Start(ctx, id); err != nil {
+		return fmt.Errorf("repository error starting integrity check %s: %w", id, err)
+	}
+
+	s.logger.Info("Running integrity check", zap.String("check_id", id.String()), zap.Strings("repairs", run.Repairs))
+	check := &integrityCheck{
+		service: s,
+		now:     time.Now().UTC(),
+		repairs: make(map[string]bool, len(run.Repairs)),
+		report: &integrity.Report{
+			Scanned: map[string]int64{},
+			Counts:  map[string]int64{},
+			Issues:  []integrity.Issue{},
+		},
+	}
+	for _, r := range run.Repairs {
+		check.repairs[r] = true
+	}
+
+	if err := check.run(ctx); err != nil {
+		// The task's context may be what ran out.
+		if failErr := s.runs.Fail(context.WithoutCancel(ctx), id, err.Error()); failErr != nil {
+			s.logger.Error("Failed to mark integrity check failed", zap.String("check_id", id.String()), zap.Error(failErr))
+		}
+		return err
+	}
+	if err := s.runs.Finish(ctx, id, check.report); err != nil {
+		return fmt.Errorf("repository error storing integrity check report %s: %w", id, err)
+	}
+
+	s.logger.Info("Integrity check finished",
+		zap.String("check_id", id.String()),
+		zap.Any("counts", check.report.Counts),
+		zap.Int64("repaired", check.report.Repaired),
+	)
+	return nil
+}
+
+type integrityCheck struct {
+	service *IntegrityService
+	now     time.Time
+	repairs map[string]bool
+	report  *integrity.Report
+}
+
+// add records a violation. With repair set, the violation can be fixed by
+// that repair, which fix applies if the check was asked to.
+func (c *integrityCheck) add(check, subject, repair string, fix func() error, format string, args ...interface{}) {
+	issue := integrity.Issue{
+		Check:   check,
+		Subject: subject,
+		Message: fmt.Sprintf(format, args...),
+		Repair:  repair,
+	}
+	if repair != "" && c.repairs[repair] {
+		if err := fix(); err != nil {
+			c.service.logger.Warn("Integrity repair failed", zap.String("repair", repair), zap.String("subject", subject), zap.Error(err))
+			issue.RepairError = err.Error()
+		} else {
+			issue.Repaired = true
+			c.report.Repaired++
+		}
+	}
+
+	c.report.Counts[check]++
+	if len(c.report.Issues) < integrity.MaxIssues {
+		c.report.Issues = append(c.report.Issues, issue)
+	} else {
+		c.report.Truncated = true
+	}
+}
+
+func (c *integrityCheck) run(ctx context.Context) error {
+	products, err := c.service.products.ListProducts(ctx)
+	if err != nil {
+		return fmt.Errorf("repository error listing products: %w", err)
+	}
+	byID := make(map[uuid.UUID]*product.Product, len(products))
+	schemas := make(map[uuid.UUID]*metaschema.Schema, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+		if len(p.MetadataSchema) == 0 {
+			continue
+		}
+		// Schemas that do not compile are reported by the lint endpoint.
+		if schema, err := metaschema.Compile(p.MetadataSchema); err == nil {
+			schemas[p.ID] = schema
+		}
+	}
+
+	if err := c.checkLicenses(ctx, byID, schemas); err != nil {
+		return err
+	}
+	return c.checkActivations(ctx)
+}
+
+func (c *integrityCheck) checkLicenses(ctx context.Context, products map[uuid.UUID]*product.Product, schemas map[uuid.UUID]*metaschema.Schema) error {
+	params := license.ListParams{
+		SortBy:    "created_at",
+		SortOrder: "ASC",
+		Limit:     integrityPageSize,
+	}
+	for {
+		licenses, _, err := c.service.licenses.List(ctx, params)
+		if err != nil {
+			return fmt.Errorf("repository error listing licenses: %w", err)
+		}
+
+		for _, lic := range licenses {
+			c.report.Scanned["licenses"]++
+			subject := lic.ID.String()
+
+			if lic.Status == license.StatusActive && lic.Lapsed(c.now) {
+				c.add(integrity.CheckOverdueActive, subject, integrity.RepairExpireOverdue,
+					func() error { return c.service.licenses.UpdateStatus(ctx, lic.ID, license.StatusExpired) },
+					"license is active but expired on %s and its %d day grace period has ended", lic.ExpiresAt.Time.UTC().Format(time.DateOnly), lic.GracePeriodDays)
+			}
+
+			prod := products[lic.ProductID]
+			if prod == nil {
+				c.add(integrity.CheckMissingProduct, subject, "", nil,
+					"license references product %s (%s), which does not exist; recreate the product or move the license to another one", lic.ProductName, lic.ProductID)
+				continue
+			}
+			if schema := schemas[prod.ID]; schema != nil {
+				if err := schema.Validate(lic.Metadata); err != nil {
+					c.add(integrity.CheckMetadataSchema, subject, "", nil,
+						"metadata does not match the schema of product %s: %s; fix it or run a metadata migration", prod.Name, schemaViolations(err))
+				}
+			}
+		}
+
+		if len(licenses) < params.Limit {
+			return nil
+		}
+		params.Offset += params.Limit
+	}
+}
+
+func schemaViolations(err error) string {
+	var fieldErrs *ierr.FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		return err.Error()
+	}
+	parts := make([]string, 0, len(fieldErrs.Fields))
+	for _, f := range fieldErrs.Fields {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// checkActivations looks for activations of licenses that do not exist.
+// Activations are kept in the primary database without a foreign key, so
+// with sharding nothing else prevents them.
+func (c *integrityCheck) checkActivations(ctx context.Context) error {
+	after := uuid.Nil
+	for {
+		ids, err := c.service.activations.ListLicenseIDs(ctx, after, integrityPageSize)
+		if err != nil {
+			return fmt.Errorf("repository error listing licenses with activations: %w", err)
+		}
+
+		for _, id := range ids {
+			c.report.Scanned["activated_licenses"]++
+			_, err := c.service.licenses.FindByID(ctx, id)
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, ierr.ErrNotFound) {
+				return fmt.Errorf("repository error finding license %s: %w", id, err)
+			}
+			c.add(integrity.CheckOrphanActivation, id.String(), integrity.RepairDeleteOrphanActivations,
+				func() error {
+					_, err := c.service.activations.DeleteByLicense(ctx, id)
+					return err
+				},
+				"activations reference license %s, which does not exist", id)
+		}
+
+		if len(ids) < integrityPageSize {
+			return nil
+		}
+		after = ids[len(ids)-1]
+	}
+}
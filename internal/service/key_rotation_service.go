@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/product"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/licensekey"
+	"go.uber.org/zap"
+)
+
+// KeyRotationService replaces the key of a license, e.g. after it leaked.
+// The old key keeps validating for an overlap window so that installations
+// can switch to the new one without an outage.
+type KeyRotationService struct {
+	repo     license.Repository
+	products product.Repository
+	cfg      *config.KeyRotationConfig
+	logger   *zap.Logger
+}
+
+func NewKeyRotationService(repo license.Repository, products product.Repository, cfg *config.KeyRotationConfig, logger *zap.Logger) *KeyRotationService {
+	return &KeyRotationService{
+		repo:     repo,
+		products: products,
+		cfg:      cfg,
+		logger:   logger.Named("KeyRotationService"),
+	}
+}
+
+func (s *KeyRotationService) RotateKey(ctx context.Context, id uuid.UUID, req *dto.RotateKeyRequest) (*dto.RotateKeyResponse, error) {
+	lic, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error fetching license %s: %w", id, err)
+	}
+	if lic.Status == license.StatusRevoked {
+		return nil, fmt.Errorf("%w: revoked licenses cannot get a new key", ierr.ErrConflict)
+	}
+
+	prod, err := s.products.FindProductByID(ctx, lic.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error finding product of license %s: %w", id, err)
+	}
+	keyFormat, err := licensekey.Parse(prod.KeyFormat, prod.KeyPrefix)
+	if err != nil {
+		s.logger.Error("Product has an unusable license key format", zap.String("product", prod.Name), zap.Error(err))
+		return nil, fmt.Errorf("license key format of product %s: %w", prod.Name, err)
+	}
+
+	overlap := s.cfg.Overlap
+	if req.OverlapHours != nil {
+		overlap = time.Duration(*req.OverlapHours) * time.Hour
+	}
+	if s.cfg.MaxOverlap > 0 && overlap > s.cfg.MaxOverlap {
+		overlap = s.cfg.MaxOverlap
+	}
+	validUntil := time.Now().UTC().Add(overlap)
+
+	var rotated *license.License
+	for attempt := 1; ; attempt++ {
+		rotated, err = s.repo.RotateKey(ctx, id, keyFormat.Generate, validUntil)
+		if err == nil {
+			break
+		}
+		if errors.Is(err, ierr.ErrDuplicateKey) && attempt < maxKeyGenerationAttempts {
+			s.logger.Warn("Generated license key already exists, retrying", zap.String("product", prod.Name), zap.Int("attempt", attempt))
+			continue
+		}
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		s.logger.Error("Failed to rotate license key", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("repository error rotating key of license %s: %w", id, err)
+	}
+
+	s.logger.Info("License key rotated",
+		zap.String("id", id.String()),
+		zap.String("previous_key", lic.LicenseKey),
+		zap.String("license_key", rotated.LicenseKey),
+		zap.Duration("overlap", overlap),
+	)
+	return &dto.RotateKeyResponse{
+		License:               dto.NewLicenseResponse(rotated),
+		PreviousKey:           lic.LicenseKey,
+		PreviousKeyValidUntil: validUntil,
+	}, nil
+}
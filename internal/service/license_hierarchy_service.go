@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/entitlement"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/product"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/licensekey"
+	"go.uber.org/zap"
+)
+
+// LicenseHierarchyService issues child licenses from an enterprise parent
+// license. Children share the parent's seats: together they may hold at
+// most the parent's max_activations, and the seats they hold are taken from
+// the parent's own devices. They follow its expiry and inherit its
+// entitlements; revoking or renewing the parent cascades to them.
+type LicenseHierarchyService struct {
+	repo     license.Repository
+	products product.Repository
+	logger   *zap.Logger
+}
+
+func NewLicenseHierarchyService(repo license.Repository, products product.Repository, logger *zap.Logger) *LicenseHierarchyService {
+	return &LicenseHierarchyService{
+		repo:     repo,
+		products: products,
+		logger:   logger.Named("LicenseHierarchyService"),
+	}
+}
+
+// CreateChild holds the lock of the parent, so concurrent creates cannot
+// both take its last seats and a revoke of the parent sees every child.
+func (s *LicenseHierarchyService) CreateChild(ctx context.Context, parentID uuid.UUID, req *dto.CreateChildLicenseRequest) (*license.License, error) {
+	var created *license.License
+	err := s.repo.WithLock(ctx, parentID, func(ctx context.Context) error {
+		var err error
+		created, err = s.createChild(ctx, parentID, req)
+		return err
+	})
+	return created, err
+}
+
+func (s *LicenseHierarchyService) createChild(ctx context.Context, parentID uuid.UUID, req *dto.CreateChildLicenseRequest) (*license.License, error) {
+	parent, err := s.repo.FindByID(ctx, parentID)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error fetching license %s: %w", parentID, err)
+	}
+	if parent.IsChild() {
+		return nil, fmt.Errorf("%w: license %s is a child license and cannot have children", ierr.ErrValidation, parentID)
+	}
+	if parent.Status != license.StatusActive && parent.Status != license.StatusPending {
+		return nil, fmt.Errorf("%w: parent license is %s; child licenses are issued only from active or pending licenses", ierr.ErrConflict, parent.Status)
+	}
+
+	seats := license.DefaultMaxActivations
+	if req.MaxActivations != nil {
+		seats = *req.MaxActivations
+	}
+	if err := checkChildSeats(ctx, s.repo, parent, uuid.Nil, seats); err != nil {
+		return nil, err
+	}
+
+	prod, err := s.products.FindProductByID(ctx, parent.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error finding product of license %s: %w", parentID, err)
+	}
+	if err := checkMetadataSchema(prod, req.Metadata); err != nil {
+		return nil, err
+	}
+	keyFormat, err := licensekey.Parse(prod.KeyFormat, prod.KeyPrefix)
+	if err != nil {
+		s.logger.Error("Product has an unusable license key format", zap.String("product", prod.Name), zap.Error(err))
+		return nil, fmt.Errorf("license key format of product %s: %w", prod.Name, err)
+	}
+
+	child := &license.License{
+		Status:          parent.Status,
+		Type:            parent.Type,
+		CustomerName:    parent.CustomerName,
+		CustomerEmail:   parent.CustomerEmail,
+		CustomerID:      parent.CustomerID,
+		ProductID:       parent.ProductID,
+		ProductName:     parent.ProductName,
+		Metadata:        req.Metadata,
+		ExpiresAt:       parent.ExpiresAt,
+		StartsAt:        parent.StartsAt,
+		MaxActivations:  seats,
+		GracePeriodDays: parent.GracePeriodDays,
+		Tags:            parent.Tags,
+		OperatorNotes:   req.OperatorNotes,
+		Floating:        parent.Floating,
+		ParentID:        uuid.NullUUID{UUID: parent.ID, Valid: true},
+	}
+	if child.Status == license.StatusActive || child.StartsAt.Valid {
+		child.IssuedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+
+	var insertedID uuid.UUID
+	for attempt := 1; ; attempt++ {
+		child.LicenseKey, err = keyFormat.Generate()
+		if err != nil {
+			return nil, err
+		}
+		insertedID, err = s.repo.Create(ctx, child)
+		if err == nil {
+			break
+		}
+		if errors.Is(err, ierr.ErrDuplicateKey) && attempt < maxKeyGenerationAttempts {
+			s.logger.Warn("Generated license key already exists, retrying", zap.String("product", prod.Name), zap.Int("attempt", attempt))
+			continue
+		}
+		return nil, fmt.Errorf("repository error creating child license: %w", err)
+	}
+
+	created, err := s.repo.FindByID(ctx, insertedID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve created child license (id: %s): %w", insertedID, err)
+	}
+
+	s.logger.Info("Child license created",
+		zap.String("id", created.ID.String()),
+		zap.String("parent_id", parentID.String()),
+		zap.Int("max_activations", seats),
+	)
+	return created, nil
+}
+
+// checkChildSeats checks that the children of parent that are not revoked,
+// except, fit into its seats when another child takes seats of them. Callers
+// hold the lock of parent until the child is stored.
+func checkChildSeats(ctx context.Context, repo license.Repository, parent *license.License, except uuid.UUID, seats int) error {
+	children, err := license.ListChildren(ctx, repo, parent.ID)
+	if err != nil {
+		return err
+	}
+	used := 0
+	for _, child := range children {
+		if child.Status != license.StatusRevoked && child.ID != except {
+			used += child.MaxActivations
+		}
+	}
+	if used+seats > parent.MaxActivations {
+		return fmt.Errorf("%w: parent license has %d of %d seats left for child licenses", ierr.ErrConflict, max(parent.MaxActivations-used, 0), parent.MaxActivations)
+	}
+	return nil
+}
+
+// revokeChildren revokes the child licenses of parentID that are not revoked
+// yet and returns them with the status they had before. It holds the lock
+// of the parent, so a child created meanwhile is revoked too.
+func revokeChildren(ctx context.Context, repo license.Repository, parentID uuid.UUID) ([]*license.License, error) {
+	var revoked []*license.License
+	err := repo.WithLock(ctx, parentID, func(ctx context.Context) error {
+		children, err := license.ListChildren(ctx, repo, parentID)
+		if err != nil {
+			return err
+		}
+		for _, child := range children {
+			if child.Status == license.StatusRevoked {
+				continue
+			}
+			if err := repo.UpdateStatus(ctx, child.ID, license.StatusRevoked); err != nil {
+				return fmt.Errorf("repository error revoking child license %s: %w", child.ID, err)
+			}
+			revoked = append(revoked, child)
+		}
+		return nil
+	})
+	return revoked, err
+}
+
+// syncChildren copies the expiry and grace period of parent to its child
+// licenses that are not revoked, and makes expired children active again
+// when the parent is active, e.g. after a renewal. It returns how many
+// children were changed. It holds the lock of the parent, so a child
+// created meanwhile is synced too.
+func syncChildren(ctx context.Context, repo license.Repository, parent *license.License) (int, error) {
+	if parent.IsChild() {
+		return 0, nil
+	}
+	changed := 0
+	err := repo.WithLock(ctx, parent.ID, func(ctx context.Context) error {
+		children, err := license.ListChildren(ctx, repo, parent.ID)
+		if err != nil {
+			return err
+		}
+		for _, child := range children {
+			if child.Status == license.StatusRevoked {
+				continue
+			}
+			update := child.GracePeriodDays != parent.GracePeriodDays ||
+				child.ExpiresAt.Valid != parent.ExpiresAt.Valid ||
+				!child.ExpiresAt.Time.Equal(parent.ExpiresAt.Time)
+			child.ExpiresAt = parent.ExpiresAt
+			child.GracePeriodDays = parent.GracePeriodDays
+			if child.Status == license.StatusExpired && parent.Status == license.StatusActive {
+				child.Status = license.StatusActive
+				update = true
+			}
+			if !update {
+				continue
+			}
+			if err := repo.Update(ctx, child); err != nil {
+				return fmt.Errorf("repository error updating child license %s: %w", child.ID, err)
+			}
+			changed++
+		}
+		return nil
+	})
+	return changed, err
+}
+
+// licenseEntitlements returns the entitlements agents get for lic. A child
+// license inherits those of its parent, except where it has its own of the
+// same name.
+func licenseEntitlements(ctx context.Context, repo entitlement.Repository, lic *license.License) ([]*entitlement.Entitlement, error) {
+	own, err := repo.ListByLicense(ctx, lic.ID)
+	if err != nil || !lic.IsChild() {
+		return own, err
+	}
+	inherited, err := repo.ListByLicense(ctx, lic.ParentID.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(own))
+	for _, e := range own {
+		names[e.Name] = true
+	}
+	for _, e := range inherited {
+		if !names[e.Name] {
+			own = append(own, e)
+		}
+	}
+	return own, nil
+}
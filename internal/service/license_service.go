@@ -7,51 +7,236 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
+	"github.com/makkenzo/license-service-api/internal/audit"
+	"github.com/makkenzo/license-service-api/internal/background"
+	"github.com/makkenzo/license-service-api/internal/buildinfo"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/activation"
+	"github.com/makkenzo/license-service-api/internal/domain/customer"
+	"github.com/makkenzo/license-service-api/internal/domain/dashboard"
+	"github.com/makkenzo/license-service-api/internal/domain/entitlement"
+	"github.com/makkenzo/license-service-api/internal/domain/lease"
 	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/licensetemplate"
+	"github.com/makkenzo/license-service-api/internal/domain/product"
+	"github.com/makkenzo/license-service-api/internal/domain/statushistory"
+	"github.com/makkenzo/license-service-api/internal/domain/usage"
 	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/idgen"
 	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/jsonlimit"
+	"github.com/makkenzo/license-service-api/internal/lastseen"
+	"github.com/makkenzo/license-service-api/internal/licensekey"
+	"github.com/makkenzo/license-service-api/internal/metaschema"
+	"github.com/makkenzo/license-service-api/internal/servertiming"
+	"github.com/makkenzo/license-service-api/internal/signing"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 )
 
 const defaultExpiringPeriodDays = 30
 
+// activeInstallationWindow is how recently an activation must have sent a
+// heartbeat to count as an active installation on the dashboard.
+const activeInstallationWindow = 24 * time.Hour
+
+const maxKeyGenerationAttempts = 3
+
+// ErrQueryTooExpensive rejects list requests whose cost grows with the offset
+// rather than with the page size.
+var ErrQueryTooExpensive = ierr.ErrValidation.Derive("QUERY_TOO_EXPENSIVE", "query is too expensive")
+
 type LicenseService struct {
-	repo   license.Repository
-	logger *zap.Logger
+	repo        license.Repository
+	products    product.Repository
+	customers   customer.Repository
+	activations activation.Repository
+	history     statushistory.Repository
+	// entitlements and templates live in the primary database even with
+	// sharding.
+	entitlements entitlement.Repository
+	templates    licensetemplate.Repository
+	// usage, like entitlements, is kept in the primary database.
+	usage  usage.Repository
+	leases lease.Repository
+	// lastSeen is nil when entitlement change tracking is disabled.
+	lastSeen *lastseen.Store
+	// validationEvents is nil when validation events are not recorded.
+	validationEvents *ValidationEventService
+	// webhooks is nil where validation.failed webhooks are not dispatched.
+	webhooks   *WebhookService
+	background *background.Pool
+	keyring    *signing.Keyring
+	statuses   *CustomStatusService
+	limits     *config.QueryConfig
+	logger     *zap.Logger
 }
 
-func NewLicenseService(repo license.Repository, logger *zap.Logger) *LicenseService {
+func NewLicenseService(repo license.Repository, products product.Repository, customers customer.Repository, activations activation.Repository, history statushistory.Repository, entitlements entitlement.Repository, templates licensetemplate.Repository, usageRepo usage.Repository, leases lease.Repository, lastSeen *lastseen.Store, validationEvents *ValidationEventService, webhooks *WebhookService, pool *background.Pool, keyring *signing.Keyring, statuses *CustomStatusService, limits *config.QueryConfig, logger *zap.Logger) *LicenseService {
 	return &LicenseService{
-		repo:   repo,
-		logger: logger.Named("LicenseService"),
+		repo:             repo,
+		products:         products,
+		customers:        customers,
+		activations:      activations,
+		history:          history,
+		entitlements:     entitlements,
+		templates:        templates,
+		usage:            usageRepo,
+		leases:           leases,
+		lastSeen:         lastSeen,
+		validationEvents: validationEvents,
+		webhooks:         webhooks,
+		background:       pool,
+		keyring:          keyring,
+		statuses:         statuses,
+		limits:           limits,
+		logger:           logger.Named("LicenseService"),
 	}
 }
 
 func (s *LicenseService) CreateLicense(ctx context.Context, req *dto.CreateLicenseRequest) (*license.License, error) {
-	s.logger.Info("Attempting to create a new license", zap.String("product", req.ProductName), zap.Any("type", req.Type))
+	draft, err := s.draftLicense(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	req, newLicense, prod := draft.request, draft.license, draft.product
+	s.logger.Info("Attempting to create a new license", zap.String("product", prod.Name), zap.Any("type", req.Type))
+
+	cust, err := s.resolveCustomer(ctx, req.CustomerID, req.CustomerEmail, req.CustomerName)
+	if err != nil {
+		return nil, err
+	}
+	if cust != nil {
+		setCustomer(newLicense, cust)
+	}
+
+	if cust != nil && len(prod.CustomerLicenseCaps) > 0 {
+		if req.OverrideCustomerCap {
+			s.logger.Warn("Customer license cap overridden", zap.String("product", prod.Name), zap.String("type", req.Type), zap.String("customer_id", cust.ID.String()))
+		} else if err := s.checkCustomerLicenseCaps(ctx, prod, req.Type, cust.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	var insertedID uuid.UUID
+	for attempt := 1; ; attempt++ {
+		newLicense.LicenseKey, err = draft.keyFormat.Generate()
+		if err != nil {
+			return nil, err
+		}
+		insertedID, err = s.repo.Create(ctx, newLicense)
+		if err == nil {
+			break
+		}
+		// Formats are required to carry enough randomness that a collision
+		// is rare; a repeated one points at something else.
+		if errors.Is(err, ierr.ErrDuplicateKey) && attempt < maxKeyGenerationAttempts {
+			s.logger.Warn("Generated license key already exists, retrying", zap.String("product", prod.Name), zap.Int("attempt", attempt))
+			continue
+		}
+
+		s.logger.Error("Failed to create license via repository", zap.Error(err))
+
+		return nil, fmt.Errorf("repository error during license creation: %w", err)
+	}
+
+	createdLicense, err := s.repo.FindByID(ctx, insertedID)
+	if err != nil {
+		s.logger.Error("Failed to find newly created license by ID", zap.String("id", insertedID.String()), zap.Error(err))
+
+		return nil, fmt.Errorf("failed to retrieve created license (id: %s): %w", insertedID, err)
+	}
+	if err := s.copyTemplateEntitlements(ctx, draft.template, insertedID); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("License created successfully", zap.String("id", createdLicense.ID.String()), zap.String("license_key", createdLicense.LicenseKey))
+	return createdLicense, nil
+}
+
+// licenseDraft is a license checked for creation that has no customer and
+// no key yet.
+type licenseDraft struct {
+	// request is the create request with its template applied.
+	request   *dto.CreateLicenseRequest
+	license   *license.License
+	product   *product.Product
+	keyFormat licensekey.Format
+	template  *licensetemplate.Template
+}
+
+// draftLicense applies the template of req and checks everything about the
+// new license that does not depend on its customer. It does not write.
+func (s *LicenseService) draftLicense(ctx context.Context, req *dto.CreateLicenseRequest) (*licenseDraft, error) {
+	var tmpl *licensetemplate.Template
+	if req.TemplateID != nil {
+		var err error
+		if req, tmpl, err = s.applyTemplate(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	prod, err := resolveProduct(ctx, s.products, req.ProductID.UUID(), req.ProductName)
+	if err != nil {
+		return nil, err
+	}
+	if tmpl != nil && prod.ID != tmpl.ProductID {
+		return nil, fmt.Errorf("%w: product differs from the product of license template %s", ierr.ErrValidation, tmpl.Name)
+	}
+	if err := checkMetadataSchema(prod, req.Metadata); err != nil {
+		return nil, err
+	}
 
-	licenseKey := uuid.NewString()
+	keyFormat, err := licensekey.Parse(prod.KeyFormat, prod.KeyPrefix)
+	if err != nil {
+		s.logger.Error("Product has an unusable license key format", zap.String("product", prod.Name), zap.Error(err))
+		return nil, fmt.Errorf("license key format of product %s: %w", prod.Name, err)
+	}
 
 	newLicense := &license.License{
-		LicenseKey:  licenseKey,
 		Type:        req.Type,
-		ProductName: req.ProductName,
+		ProductID:   prod.ID,
+		ProductName: prod.Name,
 		Metadata:    req.Metadata,
+
+		MaxActivations: license.DefaultMaxActivations,
+		OperatorNotes:  req.OperatorNotes,
+		Floating:       req.Floating,
+	}
+	if newLicense.Tags, err = license.NormalizeTags(req.Tags); err != nil {
+		return nil, err
+	}
+	if req.MaxActivations != nil {
+		newLicense.MaxActivations = *req.MaxActivations
+	}
+	if req.GracePeriodDays != nil {
+		newLicense.GracePeriodDays = *req.GracePeriodDays
+	}
+
+	if req.StartsAt != nil {
+		if req.ExpiresAt != nil && !req.StartsAt.Before(*req.ExpiresAt) {
+			return nil, fmt.Errorf("%w: starts_at must be before expires_at", ierr.ErrValidation)
+		}
+		newLicense.StartsAt = sql.NullTime{Time: *req.StartsAt, Valid: true}
 	}
 
 	if req.InitialStatus != nil {
 
 		newLicense.Status = *req.InitialStatus
+	} else if newLicense.NotYetActive(time.Now()) {
+		// The expiry worker activates it once starts_at passes.
+		newLicense.Status = license.StatusPending
 	} else {
 
 		newLicense.Status = license.StatusActive
 	}
 
-	if newLicense.Status == license.StatusActive {
+	if newLicense.Status == license.StatusActive || newLicense.StartsAt.Valid {
 		now := time.Now()
 		newLicense.IssuedAt = sql.NullTime{Time: now, Valid: true}
 	}
@@ -59,43 +244,470 @@ func (s *LicenseService) CreateLicense(ctx context.Context, req *dto.CreateLicen
 	if req.CustomerName != nil {
 		newLicense.CustomerName = sql.NullString{String: *req.CustomerName, Valid: true}
 	}
-	if req.CustomerEmail != nil {
-		newLicense.CustomerEmail = sql.NullString{String: *req.CustomerEmail, Valid: true}
-	}
 	if req.ExpiresAt != nil {
 		newLicense.ExpiresAt = sql.NullTime{Time: *req.ExpiresAt, Valid: true}
 	}
 
-	insertedID, err := s.repo.Create(ctx, newLicense)
+	return &licenseDraft{request: req, license: newLicense, product: prod, keyFormat: keyFormat, template: tmpl}, nil
+}
+
+func (s *LicenseService) copyTemplateEntitlements(ctx context.Context, tmpl *licensetemplate.Template, licenseID uuid.UUID) error {
+	if tmpl == nil {
+		return nil
+	}
+	for _, te := range tmpl.Entitlements {
+		e := &entitlement.Entitlement{LicenseID: licenseID, Name: te.Name, Type: te.Type, Value: te.Value}
+		if err := s.entitlements.Put(ctx, e); err != nil {
+			s.logger.Error("Failed to copy template entitlement to new license", zap.String("id", licenseID.String()), zap.String("name", te.Name), zap.Error(err))
+			return fmt.Errorf("license %s was created, but copying entitlement %s of template %s failed: %w", licenseID, te.Name, tmpl.Name, err)
+		}
+	}
+	return nil
+}
+
+// MaxLicenseImportRows bounds one import; larger files have to be split.
+const MaxLicenseImportRows = 10000
+
+// ErrLicenseImportRejected refuses an import with invalid rows; none of its
+// licenses are created.
+var ErrLicenseImportRejected = ierr.ErrValidation.Derive("LICENSE_IMPORT_REJECTED", "license import has invalid rows")
+
+// ImportLicenses creates a license for every row, or for none if any row is
+// invalid. Rows are checked like CreateLicense requests, and customer license
+// caps count the licenses earlier rows add as well. A dry run only reports.
+// Otherwise customers named by e-mail are created as needed and the licenses
+// are stored in one transaction, one per shard when licenses are sharded.
+func (s *LicenseService) ImportLicenses(ctx context.Context, rows []dto.LicenseImportRow, dryRun bool) (*dto.LicenseImportResponse, error) {
+	if len(rows) > MaxLicenseImportRows {
+		return nil, fmt.Errorf("%w: import has %d rows, at most %d are allowed", ierr.ErrValidation, len(rows), MaxLicenseImportRows)
+	}
+
+	resp := &dto.LicenseImportResponse{DryRun: dryRun, Rows: len(rows)}
+	imp := &licenseImport{
+		service:   s,
+		customers: make(map[string]*customer.Customer),
+		held:      make(map[importCapKey]int64),
+		added:     make(map[importCapKey]int64),
+	}
+	var drafts []*importDraft
+	for _, row := range rows {
+		for _, fe := range row.Errors {
+			resp.Errors = append(resp.Errors, dto.LicenseImportError{Line: row.Line, Field: fe.Field, Message: fe.Message})
+		}
+		if len(row.Errors) > 0 {
+			continue
+		}
+		draft, err := imp.check(ctx, row.Request)
+		if err != nil {
+			rowErrors, ok := importRowErrors(row.Line, err)
+			if !ok {
+				return nil, err
+			}
+			resp.Errors = append(resp.Errors, rowErrors...)
+			continue
+		}
+		draft.line = row.Line
+		drafts = append(drafts, draft)
+	}
+	resp.Valid = len(drafts)
+
+	if dryRun {
+		return resp, nil
+	}
+	if len(resp.Errors) > 0 {
+		fields := make([]ierr.FieldError, len(resp.Errors))
+		for i, e := range resp.Errors {
+			fields[i] = ierr.FieldError{Field: fmt.Sprintf("line %d", e.Line), Message: e.Message}
+			if e.Field != "" {
+				fields[i].Field += ": " + e.Field
+			}
+		}
+		return nil, ierr.NewFieldErrors(ErrLicenseImportRejected, fields)
+	}
+
+	for _, d := range drafts {
+		if d.customer != nil && d.customer.ID == uuid.Nil {
+			// Rows sharing the e-mail share the customer, so it is created
+			// once.
+			ensured, err := s.customers.Ensure(ctx, d.customer.Email, d.customer.Name)
+			if err != nil {
+				return nil, fmt.Errorf("repository error ensuring customer: %w", err)
+			}
+			*d.customer = *ensured
+		}
+		if d.customer != nil {
+			setCustomer(d.license, d.customer)
+		}
+	}
+
+	var storeErr error
+	for attempt := 1; ; attempt++ {
+		var unstored []*license.License
+		for _, d := range drafts {
+			if d.license.ID != uuid.Nil {
+				continue
+			}
+			key, err := d.keyFormat.Generate()
+			if err != nil {
+				return nil, err
+			}
+			d.license.LicenseKey = key
+			unstored = append(unstored, d.license)
+		}
+		storeErr = s.repo.CreateBatch(ctx, unstored)
+		if storeErr == nil || !errors.Is(storeErr, ierr.ErrDuplicateKey) || attempt >= maxKeyGenerationAttempts {
+			break
+		}
+		s.logger.Warn("Generated license key already exists during import, retrying", zap.Int("attempt", attempt))
+	}
+	if storeErr != nil {
+		stored := 0
+		for _, d := range drafts {
+			if d.license.ID != uuid.Nil {
+				stored++
+			}
+		}
+		s.logger.Error("Failed to store imported licenses", zap.Int("stored", stored), zap.Int("rows", len(drafts)), zap.Error(storeErr))
+		if stored == 0 {
+			return nil, fmt.Errorf("repository error importing licenses: %w", storeErr)
+		}
+	}
+
+	// Licenses a failed batch could not take back stay stored; the response
+	// lists them and reports the other rows as not imported.
+	for _, d := range drafts {
+		if d.license.ID == uuid.Nil {
+			resp.Errors = append(resp.Errors, dto.LicenseImportError{Line: d.line, Message: "not imported: storing the licenses failed, import this row again"})
+			continue
+		}
+		if err := s.copyTemplateEntitlements(ctx, d.template, d.license.ID); err != nil {
+			return nil, err
+		}
+		resp.Licenses = append(resp.Licenses, dto.ImportedLicense{Line: d.line, ID: d.license.ID, LicenseKey: d.license.LicenseKey})
+	}
+	resp.Imported = len(resp.Licenses)
+
+	s.logger.Info("Licenses imported", zap.Int("count", resp.Imported))
+	return resp, nil
+}
+
+// licenseImport checks the rows of one import. It looks customers up
+// without creating them and keeps the license counts caps are checked
+// against, so later rows see the licenses earlier ones add.
+type licenseImport struct {
+	service *LicenseService
+	// customers maps lower-cased e-mails to customers; one without an ID
+	// does not exist yet.
+	customers map[string]*customer.Customer
+	held      map[importCapKey]int64
+	added     map[importCapKey]int64
+}
+
+// importCapKey identifies a customer license cap count. Customers that do
+// not exist yet are identified by e-mail.
+type importCapKey struct {
+	customer string
+	product  uuid.UUID
+	capKey   string
+}
+
+type importDraft struct {
+	*licenseDraft
+	line     int
+	customer *customer.Customer
+}
+
+func (imp *licenseImport) check(ctx context.Context, req *dto.CreateLicenseRequest) (*importDraft, error) {
+	s := imp.service
+	draft, err := s.draftLicense(ctx, req)
 	if err != nil {
+		return nil, err
+	}
+	req, prod := draft.request, draft.product
 
-		s.logger.Error("Failed to create license via repository", zap.Error(err))
+	cust, err := imp.customer(ctx, req)
+	if err != nil || cust == nil {
+		return &importDraft{licenseDraft: draft}, err
+	}
 
-		return nil, fmt.Errorf("repository error during license creation: %w", err)
+	customerKey := cust.ID.String()
+	if cust.ID == uuid.Nil {
+		customerKey = strings.ToLower(cust.Email)
+	}
+	capKeyFor := func(typeFilter *string) importCapKey {
+		if typeFilter == nil {
+			return importCapKey{customerKey, prod.ID, product.AllLicenseTypes}
+		}
+		return importCapKey{customerKey, prod.ID, *typeFilter}
+	}
+	if len(prod.CustomerLicenseCaps) > 0 && !req.OverrideCustomerCap {
+		err := s.enforceCustomerLicenseCaps(prod, req.Type, cust.ID, func(typeFilter *string) (int64, error) {
+			key := capKeyFor(typeFilter)
+			held, ok := imp.held[key]
+			if !ok && cust.ID != uuid.Nil {
+				var err error
+				if held, err = s.countCustomerLicenses(ctx, prod.Name, typeFilter, cust.ID); err != nil {
+					return 0, err
+				}
+				imp.held[key] = held
+			}
+			return held + imp.added[key], nil
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
+	imp.added[capKeyFor(&req.Type)]++
+	imp.added[capKeyFor(nil)]++
 
-	createdLicense, err := s.repo.FindByID(ctx, insertedID)
+	return &importDraft{licenseDraft: draft, customer: cust}, nil
+}
+
+// customer resolves the customer of req like resolveCustomer, except that a
+// customer named by an unknown e-mail is returned without an ID instead of
+// being created.
+func (imp *licenseImport) customer(ctx context.Context, req *dto.CreateLicenseRequest) (*customer.Customer, error) {
+	if req.CustomerID != nil || req.CustomerEmail == nil {
+		return imp.service.resolveCustomer(ctx, req.CustomerID, req.CustomerEmail, nil)
+	}
+
+	email := strings.TrimSpace(*req.CustomerEmail)
+	if cust, ok := imp.customers[strings.ToLower(email)]; ok {
+		return cust, nil
+	}
+	// The e-mail filter matches substrings.
+	matches, _, err := imp.service.customers.List(ctx, customer.ListParams{Email: &email, Limit: 100})
 	if err != nil {
-		s.logger.Error("Failed to find newly created license by ID", zap.String("id", insertedID.String()), zap.Error(err))
+		return nil, fmt.Errorf("repository error finding customer: %w", err)
+	}
+	var cust *customer.Customer
+	for _, m := range matches {
+		if strings.EqualFold(m.Email, email) {
+			cust = m
+			break
+		}
+	}
+	if cust == nil {
+		cust = &customer.Customer{Email: email}
+		if req.CustomerName != nil {
+			cust.Name = strings.TrimSpace(*req.CustomerName)
+		}
+	}
+	imp.customers[strings.ToLower(email)] = cust
+	return cust, nil
+}
 
-		return nil, fmt.Errorf("failed to retrieve created license (id: %s): %w", insertedID, err)
+// importRowErrors turns a client error about a row into row errors. Other
+// errors abort the import.
+func importRowErrors(line int, err error) ([]dto.LicenseImportError, bool) {
+	status, _, message := ierr.Describe(err)
+	if status >= http.StatusInternalServerError {
+		return nil, false
 	}
+	var fieldErrs *ierr.FieldErrors
+	if errors.As(err, &fieldErrs) && len(fieldErrs.Fields) > 0 {
+		rowErrors := make([]dto.LicenseImportError, len(fieldErrs.Fields))
+		for i, f := range fieldErrs.Fields {
+			rowErrors[i] = dto.LicenseImportError{Line: line, Field: f.Field, Message: f.Message}
+		}
+		return rowErrors, true
+	}
+	return []dto.LicenseImportError{{Line: line, Message: message}}, true
+}
 
-	s.logger.Info("License created successfully", zap.String("id", createdLicense.ID.String()), zap.String("key", createdLicense.LicenseKey))
-	return createdLicense, nil
+// applyTemplate returns a copy of req with the fields it leaves out taken
+// from the license template it names.
+func (s *LicenseService) applyTemplate(ctx context.Context, req *dto.CreateLicenseRequest) (*dto.CreateLicenseRequest, *licensetemplate.Template, error) {
+	tmpl, err := s.templates.FindByID(ctx, req.TemplateID.UUID())
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, nil, fmt.Errorf("%w: license template does not exist", ierr.ErrValidation)
+		}
+		return nil, nil, fmt.Errorf("repository error finding license template: %w", err)
+	}
+
+	applied := *req
+	if applied.ProductID.UUID() == uuid.Nil && applied.ProductName == "" {
+		applied.ProductID = idgen.ID(tmpl.ProductID)
+	}
+	if applied.Type == "" {
+		applied.Type = tmpl.Type
+	}
+	if applied.ExpiresAt == nil && tmpl.DurationDays != nil {
+		// A scheduled license gets the full duration from its start.
+		start := time.Now().UTC()
+		if applied.StartsAt != nil && applied.StartsAt.After(start) {
+			start = applied.StartsAt.UTC()
+		}
+		expiresAt := start.AddDate(0, 0, *tmpl.DurationDays)
+		applied.ExpiresAt = &expiresAt
+	}
+	if applied.MaxActivations == nil {
+		applied.MaxActivations = tmpl.MaxActivations
+	}
+	if applied.GracePeriodDays == nil {
+		applied.GracePeriodDays = tmpl.GracePeriodDays
+	}
+	if len(tmpl.Metadata) > 0 {
+		overrides, _ := decodeMetadata(req.Metadata)
+		if applied.Metadata, err = MergeMetadata(tmpl.Metadata, overrides); err != nil {
+			return nil, nil, fmt.Errorf("merging metadata of license template %s: %w", tmpl.Name, err)
+		}
+	}
+	return &applied, tmpl, nil
+}
+
+// CloneLicense issues a new license with a new key from license id and the
+// overrides in req, e.g. to reissue a license a customer lost. The clone is
+// created like any other license, so it counts against customer license
+// caps and starts out active or pending whatever the source's status. The
+// source is left as it is; revoke it separately if its key must stop
+// working.
+func (s *LicenseService) CloneLicense(ctx context.Context, id uuid.UUID, req *dto.CloneLicenseRequest) (*license.License, error) {
+	src, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error fetching license %s: %w", id, err)
+	}
+	if src.IsChild() {
+		return nil, fmt.Errorf("%w: license %s is a child license; issue another child from its parent instead", ierr.ErrValidation, id)
+	}
+	entitlements, err := s.entitlements.ListByLicense(ctx, src.ID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing entitlements of license %s: %w", id, err)
+	}
+
+	create := &dto.CreateLicenseRequest{
+		Type:                src.Type,
+		ProductID:           idgen.ID(src.ProductID),
+		Metadata:            src.Metadata,
+		MaxActivations:      &src.MaxActivations,
+		Floating:            src.Floating,
+		GracePeriodDays:     &src.GracePeriodDays,
+		OverrideCustomerCap: req.OverrideCustomerCap,
+		Tags:                src.Tags,
+		OperatorNotes:       src.OperatorNotes,
+	}
+	if src.ExpiresAt.Valid {
+		create.ExpiresAt = &src.ExpiresAt.Time
+	}
+	if src.StartsAt.Valid {
+		create.StartsAt = &src.StartsAt.Time
+	}
+	if req.CustomerID != nil || req.CustomerEmail != nil || req.CustomerName != nil {
+		create.CustomerID, create.CustomerEmail, create.CustomerName = req.CustomerID, req.CustomerEmail, req.CustomerName
+	} else if src.CustomerID.Valid {
+		customerID := idgen.ID(src.CustomerID.UUID)
+		create.CustomerID = &customerID
+	} else {
+		if src.CustomerName.Valid {
+			create.CustomerName = &src.CustomerName.String
+		}
+		if src.CustomerEmail.Valid {
+			create.CustomerEmail = &src.CustomerEmail.String
+		}
+	}
+	if req.Type != "" {
+		create.Type = req.Type
+	}
+	if len(req.Metadata) > 0 {
+		create.Metadata = req.Metadata
+	}
+	if req.ExpiresAt != nil {
+		create.ExpiresAt = req.ExpiresAt
+	}
+	if req.StartsAt != nil {
+		create.StartsAt = req.StartsAt
+	}
+	if req.MaxActivations != nil {
+		create.MaxActivations = req.MaxActivations
+	}
+	if req.GracePeriodDays != nil {
+		create.GracePeriodDays = req.GracePeriodDays
+	}
+	if req.Tags != nil {
+		create.Tags = req.Tags
+	}
+	if req.OperatorNotes != nil {
+		create.OperatorNotes = *req.OperatorNotes
+	}
+	if create.ExpiresAt != nil && !create.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: license %s has expired; set expires_at for the clone", ierr.ErrValidation, id)
+	}
+
+	clone, err := s.CreateLicense(ctx, create)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entitlements {
+		copied := &entitlement.Entitlement{LicenseID: clone.ID, Name: e.Name, Type: e.Type, Value: e.Value}
+		if err := s.entitlements.Put(ctx, copied); err != nil {
+			s.logger.Error("Failed to copy entitlement to cloned license", zap.String("id", clone.ID.String()), zap.String("name", e.Name), zap.Error(err))
+			return nil, fmt.Errorf("license %s was created, but copying entitlement %s of license %s failed: %w", clone.ID, e.Name, id, err)
+		}
+	}
+
+	s.logger.Info("License cloned", zap.String("id", clone.ID.String()), zap.String("source_id", id.String()), zap.Int("entitlements", len(entitlements)))
+	return clone, nil
 }
 
 func (s *LicenseService) ListLicenses(ctx context.Context, req *dto.ListLicensesRequest) ([]*license.License, int64, error) {
 	params := license.ListParams{
-		Status:        req.Status,
 		CustomerEmail: req.CustomerEmail,
-		ProductName:   req.ProductName,
+		ProductNames:  splitListFilter(req.ProductName),
 		Type:          req.Type,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+		ExpiresAfter:  req.ExpiresAfter,
+		ExpiresBefore: req.ExpiresBefore,
 		Limit:         req.Limit,
 		Offset:        req.Offset,
 		SortBy:        req.SortBy,
 		SortOrder:     req.SortOrder,
 	}
+	for _, status := range splitListFilter(req.Status) {
+		if !s.statuses.Known(license.LicenseStatus(status)) {
+			return nil, 0, fmt.Errorf("%w: unknown license status %q", ierr.ErrValidation, status)
+		}
+		params.Statuses = append(params.Statuses, license.LicenseStatus(status))
+	}
+	if len(params.Statuses) > maxListFilterValues || len(params.ProductNames) > maxListFilterValues {
+		return nil, 0, fmt.Errorf("%w: status and product_name take at most %d values each", ierr.ErrValidation, maxListFilterValues)
+	}
+	if req.CreatedAfter != nil && req.CreatedBefore != nil && !req.CreatedAfter.Before(*req.CreatedBefore) {
+		return nil, 0, fmt.Errorf("%w: created_after must be before created_before", ierr.ErrValidation)
+	}
+	if req.ExpiresAfter != nil && req.ExpiresBefore != nil && req.ExpiresAfter.After(*req.ExpiresBefore) {
+		return nil, 0, fmt.Errorf("%w: expires_after must not be after expires_before", ierr.ErrValidation)
+	}
+	if req.Tag != nil {
+		params.Tag = ptr(license.NormalizeTag(*req.Tag))
+	}
+
+	if req.CustomerID != nil {
+		customerID, err := idgen.Parse(*req.CustomerID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: invalid customer_id format", ierr.ErrValidation)
+		}
+		params.CustomerID = &customerID
+	}
+	if req.ParentID != nil {
+		parentID, err := idgen.Parse(*req.ParentID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: invalid parent_id format", ierr.ErrValidation)
+		}
+		params.ParentID = &parentID
+	}
+
+	if req.Sort != "" {
+		sortFields, err := license.ParseSort(req.Sort)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: %v", ierr.ErrValidation, err)
+		}
+		params.Sort = sortFields
+	}
 
 	if params.Limit <= 0 || params.Limit > 100 {
 		params.Limit = 20
@@ -103,12 +715,18 @@ func (s *LicenseService) ListLicenses(ctx context.Context, req *dto.ListLicenses
 	if params.Offset < 0 {
 		params.Offset = 0
 	}
+	if err := s.checkListCost(params); err != nil {
+		return nil, 0, err
+	}
 
 	s.logger.Debug("Listing licenses with params", zap.Any("params", params))
 
 	licenses, totalCount, err := s.repo.List(ctx, params)
 	if err != nil {
 		s.logger.Error("Failed to list licenses via repository", zap.Error(err))
+		if errors.Is(err, ierr.ErrValidation) {
+			return nil, 0, err
+		}
 		return nil, 0, fmt.Errorf("repository error during license listing: %w", err)
 	}
 
@@ -116,20 +734,397 @@ func (s *LicenseService) ListLicenses(ctx context.Context, req *dto.ListLicenses
 	return licenses, totalCount, nil
 }
 
+// SearchLicenses lists the licenses matching req.Q, ordered by
+// license.SearchRank and then newest first.
+func (s *LicenseService) SearchLicenses(ctx context.Context, req *dto.SearchLicensesRequest) ([]*license.License, int64, error) {
+	query := strings.TrimSpace(req.Q)
+	if len(query) < 2 {
+		return nil, 0, fmt.Errorf("%w: q must have at least 2 characters", ierr.ErrValidation)
+	}
+	params := license.ListParams{
+		Status:      req.Status,
+		ProductName: req.ProductName,
+		Type:        req.Type,
+		Query:       &query,
+		Limit:       req.Limit,
+		Offset:      req.Offset,
+		SortBy:      "created_at",
+		SortOrder:   "DESC",
+	}
+	if params.Limit <= 0 || params.Limit > 100 {
+		params.Limit = 20
+	}
+	if err := s.checkListCost(params); err != nil {
+		return nil, 0, err
+	}
+
+	licenses, totalCount, err := s.repo.List(ctx, params)
+	if err != nil {
+		s.logger.Error("Failed to search licenses via repository", zap.Error(err))
+		return nil, 0, fmt.Errorf("repository error during license search: %w", err)
+	}
+	return licenses, totalCount, nil
+}
+
+// maxListFilterValues is how many values a multi-value list filter takes.
+const maxListFilterValues = 20
+
+// splitListFilter splits the comma-separated values of a multi-value query
+// parameter, which may also be repeated, dropping empty and duplicate ones.
+func splitListFilter(values []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" && !seen[v] {
+				seen[v] = true
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
+
+// checkListCost rejects pages so deep that the database would have to skip
+// most of the table to reach them. Filters shrink what is skipped, but
+// callers after the whole data set are better served by the export, which
+// walks a cursor instead of re-scanning for every page.
+func (s *LicenseService) checkListCost(params license.ListParams) error {
+	if s.limits.MaxOffset <= 0 || params.Offset <= s.limits.MaxOffset {
+		return nil
+	}
+	hint := "narrow the filter"
+	if params.Status == nil && len(params.Statuses) == 0 && params.CustomerEmail == nil && params.CustomerID == nil && params.ParentID == nil &&
+		params.ProductName == nil && len(params.ProductNames) == 0 && params.Type == nil {
+		hint = "filter by status, email, product_name or type"
+	}
+	s.logger.Warn("Rejected deep license list page", zap.Int("offset", params.Offset), zap.Int("max_offset", s.limits.MaxOffset))
+	return fmt.Errorf("%w: offset %d exceeds the maximum of %d; %s, or read every license through the license export, which pages through a database cursor",
+		ErrQueryTooExpensive, params.Offset, s.limits.MaxOffset, hint)
+}
+
+func (s *LicenseService) AggregateLicenses(ctx context.Context, req *dto.AggregateLicensesRequest) (*dto.AggregateLicensesResponse, error) {
+	dims, err := license.ParseGroupBy(req.GroupBy)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ierr.ErrValidation, err)
+	}
+
+	metric := req.Metric
+	if metric == "" {
+		metric = license.MetricCount
+	}
+
+	params := license.AggregateParams{
+		GroupBy:     dims,
+		Metric:      metric,
+		Status:      req.Status,
+		ProductName: req.ProductName,
+		Type:        req.Type,
+	}
+
+	rows, err := s.repo.Aggregate(ctx, params)
+	if err != nil {
+		s.logger.Error("Failed to aggregate licenses via repository", zap.Error(err))
+		if errors.Is(err, ierr.ErrValidation) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error during license aggregation: %w", err)
+	}
+
+	s.logger.Info("Licenses aggregated successfully", zap.String("group_by", req.GroupBy), zap.Int("groups", len(rows)))
+	return dto.NewAggregateLicensesResponse(dims, metric, rows), nil
+}
+
+// GetLicenseTimeseries is an aggregation over a single bucketed date column.
+func (s *LicenseService) GetLicenseTimeseries(ctx context.Context, params dashboard.TimeseriesParams) (*dto.AggregateLicensesResponse, error) {
+	req := &dto.AggregateLicensesRequest{
+		GroupBy:     params.Field + ":" + params.Bucket,
+		Metric:      params.Metric,
+		ProductName: params.ProductName,
+		Type:        params.Type,
+	}
+	if params.Status != nil {
+		status := license.LicenseStatus(*params.Status)
+		req.Status = &status
+	}
+	return s.AggregateLicenses(ctx, req)
+}
+
+// checkCustomerLicenseCaps refuses a new license of licenseType when the
+// customer already holds as many non-revoked licenses of the product as its
+// caps allow. Concurrent creations for the same customer can both pass.
+func (s *LicenseService) checkCustomerLicenseCaps(ctx context.Context, prod *product.Product, licenseType string, customerID uuid.UUID) error {
+	return s.enforceCustomerLicenseCaps(prod, licenseType, customerID, func(typeFilter *string) (int64, error) {
+		return s.countCustomerLicenses(ctx, prod.Name, typeFilter, customerID)
+	})
+}
+
+// enforceCustomerLicenseCaps checks the caps of prod against held, which
+// counts the customer's licenses of a type, or of any type for a nil filter.
+func (s *LicenseService) enforceCustomerLicenseCaps(prod *product.Product, licenseType string, customerID uuid.UUID, held func(typeFilter *string) (int64, error)) error {
+	check := func(capKey string, typeFilter *string) error {
+		max, ok := prod.CustomerLicenseCaps[capKey]
+		if !ok {
+			return nil
+		}
+		held, err := held(typeFilter)
+		if err != nil {
+			return err
+		}
+		if held >= int64(max) {
+			s.logger.Info("Customer license cap reached",
+				zap.String("product", prod.Name),
+				zap.String("cap", capKey),
+				zap.Int("max", max),
+				zap.String("customer_id", customerID.String()),
+			)
+			return fmt.Errorf("%w: customer holds %d of at most %d %s licenses of %s", product.ErrCustomerLicenseCap, held, max, capKey, prod.Name)
+		}
+		return nil
+	}
+
+	if err := check(licenseType, &licenseType); err != nil {
+		return err
+	}
+	return check(product.AllLicenseTypes, nil)
+}
+
+func (s *LicenseService) countCustomerLicenses(ctx context.Context, productName string, licenseType *string, customerID uuid.UUID) (int64, error) {
+	params := license.ListParams{
+		ProductName: &productName,
+		Type:        licenseType,
+		CustomerID:  &customerID,
+		Limit:       1,
+	}
+	_, total, err := s.repo.List(ctx, params)
+	if err != nil {
+		return 0, fmt.Errorf("repository error counting customer licenses: %w", err)
+	}
+
+	revoked := license.StatusRevoked
+	params.Status = &revoked
+	_, alreadyRevoked, err := s.repo.List(ctx, params)
+	if err != nil {
+		return 0, fmt.Errorf("repository error counting revoked customer licenses: %w", err)
+	}
+	return total - alreadyRevoked, nil
+}
+
 func (s *LicenseService) GetLicenseByID(ctx context.Context, id uuid.UUID) (*license.License, error) {
 	s.logger.Debug("Attempting to get license by ID", zap.String("id", id.String()))
 
-	lic, err := s.repo.FindByID(ctx, id)
+	lic, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			s.logger.Info("License not found by ID", zap.String("id", id.String()))
+			return nil, ierr.ErrNotFound
+		}
+		s.logger.Error("Failed to get license by ID from repository", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("repository error fetching license by ID %s: %w", id, err)
+	}
+	s.logger.Info("License retrieved successfully by ID", zap.String("id", id.String()))
+	return lic, nil
+}
+
+// IssueLicenseFile signs the current state of a license for agents that
+// verify it offline against the published JWKS.
+func (s *LicenseService) IssueLicenseFile(ctx context.Context, id uuid.UUID) (*dto.LicenseFileResponse, error) {
+	lic, err := s.GetLicenseByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if lic.Status == license.StatusRevoked {
+		return nil, fmt.Errorf("%w: revoked licenses cannot be issued as license files", ierr.ErrConflict)
+	}
+	maxActive, err := license.OwnSeats(ctx, s.repo, lic)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	claims := signing.LicenseClaims{
+		Issuer:         s.keyring.Issuer(),
+		Subject:        lic.ID.String(),
+		IssuedAt:       now.Unix(),
+		LicenseKey:     lic.LicenseKey,
+		Product:        lic.ProductName,
+		Type:           lic.Type,
+		Status:         string(lic.Status),
+		CustomerName:   lic.CustomerName.String,
+		MaxActivations: maxActive,
+	}
+	if lic.ExpiresAt.Valid {
+		claims.ExpiresAt = lic.ExpiresAt.Time.Unix()
+		claims.GracePeriodDays = lic.GracePeriodDays
+	}
+	if lic.StartsAt.Valid {
+		claims.NotBefore = lic.StartsAt.Time.Unix()
+	}
+	entitlements, err := licenseEntitlements(ctx, s.entitlements, lic)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing entitlements of license %s: %w", id, err)
+	}
+	if claims.AllowedData, err = allowedData(entitlements); err != nil {
+		return nil, fmt.Errorf("encoding allowed_data of license %s: %w", id, err)
+	}
+
+	token, kid, err := s.keyring.Sign(signing.LicenseFileType, claims)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Issued license file", zap.String("id", id.String()), zap.String("kid", kid))
+	return &dto.LicenseFileResponse{LicenseFile: token, KeyID: kid, IssuedAt: now}, nil
+}
+
+// MaxComparedLicenses bounds how many licenses one comparison loads.
+const MaxComparedLicenses = 10
+
+// CompareLicenses lines up the fields and entitlements of several licenses.
+// The license key and timestamps maintained by the database are left out;
+// they always differ.
+func (s *LicenseService) CompareLicenses(ctx context.Context, req *dto.CompareLicensesRequest) (*dto.CompareLicensesResponse, error) {
+	ids, err := parseIDList(req.IDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) < 2 || len(ids) > MaxComparedLicenses {
+		return nil, fmt.Errorf("%w: ids must list 2 to %d different licenses", ierr.ErrValidation, MaxComparedLicenses)
+	}
+
+	resp := &dto.CompareLicensesResponse{Licenses: make([]dto.ComparedLicense, len(ids))}
+	snapshots := make([]json.RawMessage, len(ids))
+	for i, id := range ids {
+		lic, err := s.GetLicenseByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, ierr.ErrNotFound) {
+				return nil, fmt.Errorf("%w: license %s not found", ierr.ErrNotFound, id)
+			}
+			return nil, err
+		}
+		entitlements, err := s.entitlements.ListByLicense(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("repository error listing entitlements of license %s: %w", id, err)
+		}
+		if snapshots[i], err = comparisonSnapshot(lic, entitlements); err != nil {
+			return nil, fmt.Errorf("encoding license %s for comparison: %w", id, err)
+		}
+		resp.Licenses[i] = dto.ComparedLicense{ID: lic.ID, LicenseKey: lic.LicenseKey}
+	}
+
+	fields, err := audit.Compare(snapshots...)
+	if err != nil {
+		return nil, fmt.Errorf("comparing licenses: %w", err)
+	}
+	resp.Fields = make([]dto.LicenseFieldComparison, 0, len(fields))
+	for _, f := range fields {
+		if !f.Same {
+			resp.Differences++
+		} else if req.DifferingOnly {
+			continue
+		}
+		resp.Fields = append(resp.Fields, dto.LicenseFieldComparison{Field: f.Field, Values: f.Values, Same: f.Same})
+	}
+	return resp, nil
+}
+
+// comparisonSnapshot is the audit snapshot of lic with the license key
+// swapped for the fields the audit log leaves out and the entitlements
+// keyed by name.
+func comparisonSnapshot(lic *license.License, entitlements []*entitlement.Entitlement) (json.RawMessage, error) {
+	var snap map[string]interface{}
+	if err := json.Unmarshal(audit.Snapshot(lic), &snap); err != nil {
+		return nil, err
+	}
+	delete(snap, "license_key")
+	snap["product_id"] = lic.ProductID
+	snap["max_activations"] = lic.MaxActivations
+	snap["grace_period_days"] = lic.GracePeriodDays
+
+	values := make(map[string]json.RawMessage, len(entitlements))
+	for _, e := range entitlements {
+		values[e.Name] = e.Value
+	}
+	snap["entitlements"] = values
+	return json.Marshal(snap)
+}
+
+// parseIDList parses a comma-separated list of IDs, rejecting duplicates.
+func parseIDList(list string) ([]uuid.UUID, error) {
+	parts := strings.Split(list, ",")
+	ids := make([]uuid.UUID, 0, len(parts))
+	seen := make(map[uuid.UUID]bool, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := idgen.Parse(part)
+		if err != nil {
+			return nil, err
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("%w: license %s is listed twice", ierr.ErrValidation, id)
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// IssuePassport validates the license like ValidateLicense and, when it is
+// valid, signs a short-lived validation passport for edge verification.
+// Invalid licenses get the validation result without a passport.
+func (s *LicenseService) IssuePassport(ctx context.Context, req *dto.ValidateLicenseRequest) (*dto.PassportResponse, error) {
+	if s.keyring.Algorithm() == "" {
+		return nil, signing.ErrSigningDisabled
+	}
+
+	// The passport stands in for an entitlement token here.
+	validateReq := *req
+	validateReq.SignedToken = false
+	result, err := s.ValidateLicense(ctx, &validateReq)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.PassportResponse{IsValid: result.IsValid, Reason: result.Reason}
+	if !result.IsValid {
+		return resp, nil
+	}
+
+	lic := result.License
+	now := time.Now().UTC()
+	expiresAt := now.Add(s.keyring.PassportTTL())
+	if end, ok := lic.GraceExpiresAt(); ok && end.Before(expiresAt) {
+		expiresAt = end
+	}
+	claims := signing.PassportClaims{
+		Issuer:        s.keyring.Issuer(),
+		Subject:       lic.ID.String(),
+		Audience:      s.keyring.PassportAudience(),
+		IssuedAt:      now.Unix(),
+		ExpiresAt:     expiresAt.Unix(),
+		Product:       lic.ProductName,
+		Type:          lic.Type,
+		InGracePeriod: lic.InGracePeriod(now),
+		AllowedData:   result.ResponseData,
+	}
+	if meta, ok := decodeMetadata(req.Metadata); ok {
+		claims.DeviceID, _ = meta[MetaKeyDeviceID].(string)
+	}
+
+	token, kid, err := s.keyring.Sign(signing.PassportType, claims)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, ierr.ErrNotFound) {
-			s.logger.Info("License not found by ID", zap.String("id", id.String()))
-			return nil, ierr.ErrNotFound
-		}
-		s.logger.Error("Failed to get license by ID from repository", zap.String("id", id.String()), zap.Error(err))
-		return nil, fmt.Errorf("repository error fetching license by ID %s: %w", id, err)
+		return nil, err
 	}
-	s.logger.Info("License retrieved successfully by ID", zap.String("id", id.String()))
-	return lic, nil
+	// Refreshing at two thirds of the lifetime leaves room for a failed
+	// refresh before the passport runs out.
+	refreshAfter := now.Add(expiresAt.Sub(now) * 2 / 3)
+	resp.Passport = token
+	resp.KeyID = kid
+	resp.ExpiresAt = &expiresAt
+	resp.RefreshAfter = &refreshAfter
+	s.logger.Info("Issued validation passport", zap.String("license_id", lic.ID.String()), zap.String("kid", kid), zap.Time("expires_at", expiresAt))
+	return resp, nil
 }
 
 func (s *LicenseService) UpdateLicenseStatus(ctx context.Context, id uuid.UUID, newStatus license.LicenseStatus) error {
@@ -138,15 +1133,45 @@ func (s *LicenseService) UpdateLicenseStatus(ctx context.Context, id uuid.UUID,
 		zap.String("new_status", string(newStatus)),
 	)
 
-	err := s.repo.UpdateStatus(ctx, id, newStatus)
+	current, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("repository error fetching license %s for status update: %w", id, err)
+	}
+	if current.Status == newStatus {
+		s.logger.Info("License already has requested status", zap.String("id", id.String()), zap.String("status", string(newStatus)))
+		return nil
+	}
+	if !s.statuses.Known(newStatus) {
+		return fmt.Errorf("%w: unknown license status %s", ierr.ErrValidation, newStatus)
+	}
+	if !current.Status.CanTransitionTo(newStatus) {
+		allowed := formatStatuses(current.Status.NextStatuses())
+		if current.Status.AcceptsCustom() {
+			allowed += " or a custom status"
+		}
+		return fmt.Errorf("%w: cannot change status from %s to %s, allowed next statuses: %s",
+			license.ErrInvalidStatusTransition, current.Status, newStatus, allowed)
+	}
+
+	err = s.repo.UpdateStatus(ctx, id, newStatus)
 	if err != nil {
 
-		if errors.Is(err, ierr.ErrNotFound) || errors.Is(err, ierr.ErrUpdateFailed) {
+		if errors.Is(err, ierr.ErrNotFound) || errors.Is(err, ierr.ErrUpdateFailed) || errors.Is(err, license.ErrInvalidStatusTransition) {
 			return err
 		}
 
 		return fmt.Errorf("repository error updating status for license %s: %w", id, err)
 	}
+	if newStatus == license.StatusRevoked {
+		children, err := revokeChildren(ctx, s.repo, id)
+		if err != nil {
+			s.logger.Error("License revoked but not all of its child licenses", zap.String("id", id.String()), zap.Int("children_revoked", len(children)), zap.Error(err))
+			return fmt.Errorf("license %s was revoked, but revoking its child licenses failed: %w", id, err)
+		}
+	}
 
 	s.logger.Info("License status update successful in service",
 		zap.String("id", id.String()),
@@ -156,12 +1181,23 @@ func (s *LicenseService) UpdateLicenseStatus(ctx context.Context, id uuid.UUID,
 	return nil
 }
 
+func formatStatuses(statuses []license.LicenseStatus) string {
+	if len(statuses) == 0 {
+		return "none"
+	}
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+	return strings.Join(names, ", ")
+}
+
 func (s *LicenseService) UpdateLicense(ctx context.Context, id uuid.UUID, req *dto.UpdateLicenseRequest) (*license.License, error) {
 	s.logger.Debug("Attempting to update license", zap.String("id", id.String()))
 
 	currentLicense, err := s.repo.FindByID(ctx, id)
 	if err != nil {
-		if errors.Is(err, ierr.ErrNotFound) || errors.Is(err, pgx.ErrNoRows) {
+		if errors.Is(err, ierr.ErrNotFound) {
 			s.logger.Warn("License not found for update", zap.String("id", id.String()))
 			return nil, ierr.ErrNotFound
 		}
@@ -170,35 +1206,87 @@ func (s *LicenseService) UpdateLicense(ctx context.Context, id uuid.UUID, req *d
 	}
 
 	updated := false
+	var prod *product.Product
 
 	if req.Type != nil && currentLicense.Type != *req.Type {
 		currentLicense.Type = *req.Type
 		updated = true
 	}
-	if req.ProductName != nil && currentLicense.ProductName != *req.ProductName {
-		currentLicense.ProductName = *req.ProductName
-		updated = true
+	if req.ProductID != nil || req.ProductName != nil {
+		var productID uuid.UUID
+		var productName string
+		if req.ProductID != nil {
+			productID = req.ProductID.UUID()
+		}
+		if req.ProductName != nil {
+			productName = *req.ProductName
+		}
+		prod, err = resolveProduct(ctx, s.products, productID, productName)
+		if err != nil {
+			return nil, err
+		}
+		if currentLicense.ProductID != prod.ID {
+			currentLicense.ProductID = prod.ID
+			currentLicense.ProductName = prod.Name
+			updated = true
+		}
 	}
 
-	if req.CustomerName != nil {
-
-		if !currentLicense.CustomerName.Valid || currentLicense.CustomerName.String != *req.CustomerName {
-			currentLicense.CustomerName = sql.NullString{String: *req.CustomerName, Valid: true}
+	if req.CustomerID != nil || req.CustomerEmail != nil {
+		cust, err := s.resolveCustomer(ctx, req.CustomerID, req.CustomerEmail, req.CustomerName)
+		if err != nil {
+			return nil, err
+		}
+		if setCustomer(currentLicense, cust) {
 			updated = true
 		}
-	}
-	if req.CustomerEmail != nil {
-		if !currentLicense.CustomerEmail.Valid || currentLicense.CustomerEmail.String != *req.CustomerEmail {
-			currentLicense.CustomerEmail = sql.NullString{String: *req.CustomerEmail, Valid: true}
+	} else if req.CustomerName != nil {
+		if currentLicense.CustomerID.Valid {
+			return nil, fmt.Errorf("%w: the customer name of this license comes from customer %s, rename it with PATCH /api/v1/customers/%s", ierr.ErrValidation, currentLicense.CustomerID.UUID, currentLicense.CustomerID.UUID)
+		}
+		if !currentLicense.CustomerName.Valid || currentLicense.CustomerName.String != *req.CustomerName {
+			currentLicense.CustomerName = sql.NullString{String: *req.CustomerName, Valid: true}
 			updated = true
 		}
 	}
+	expiryChanged := false
 	if req.ExpiresAt != nil {
 		if !currentLicense.ExpiresAt.Valid || !currentLicense.ExpiresAt.Time.Equal(*req.ExpiresAt) {
 			currentLicense.ExpiresAt = sql.NullTime{Time: *req.ExpiresAt, Valid: true}
+			updated, expiryChanged = true, true
+		}
+	}
+	if req.StartsAt != nil {
+		if !currentLicense.StartsAt.Valid || !currentLicense.StartsAt.Time.Equal(*req.StartsAt) {
+			currentLicense.StartsAt = sql.NullTime{Time: *req.StartsAt, Valid: true}
 			updated = true
 		}
 	}
+	if req.StartsAt != nil || req.ExpiresAt != nil {
+		if currentLicense.StartsAt.Valid && currentLicense.ExpiresAt.Valid && !currentLicense.StartsAt.Time.Before(currentLicense.ExpiresAt.Time) {
+			return nil, fmt.Errorf("%w: starts_at must be before expires_at", ierr.ErrValidation)
+		}
+	}
+
+	seatsGrow := false
+	if req.MaxActivations != nil && currentLicense.MaxActivations != *req.MaxActivations {
+		seatsGrow = currentLicense.IsChild() && *req.MaxActivations > currentLicense.MaxActivations
+		currentLicense.MaxActivations = *req.MaxActivations
+		updated = true
+	}
+
+	if req.GracePeriodDays != nil && currentLicense.GracePeriodDays != *req.GracePeriodDays {
+		currentLicense.GracePeriodDays = *req.GracePeriodDays
+		updated, expiryChanged = true, true
+	}
+	if expiryChanged && currentLicense.IsChild() {
+		return nil, fmt.Errorf("%w: child licenses follow the expiry of their parent license", ierr.ErrValidation)
+	}
+
+	if req.Floating != nil && currentLicense.Floating != *req.Floating {
+		currentLicense.Floating = *req.Floating
+		updated = true
+	}
 
 	if req.Metadata != nil {
 
@@ -206,27 +1294,221 @@ func (s *LicenseService) UpdateLicense(ctx context.Context, id uuid.UUID, req *d
 		updated = true
 	}
 
+	if req.Tags != nil {
+		tags, err := license.NormalizeTags(req.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Equal(currentLicense.Tags, tags) {
+			currentLicense.Tags = tags
+			updated = true
+		}
+	}
+	if req.OperatorNotes != nil && currentLicense.OperatorNotes != *req.OperatorNotes {
+		currentLicense.OperatorNotes = *req.OperatorNotes
+		updated = true
+	}
+
 	if !updated {
 		s.logger.Info("No fields to update for license", zap.String("id", id.String()))
 		return currentLicense, nil
 	}
 
-	err = s.repo.Update(ctx, currentLicense)
+	// Metadata is checked when it changes or the license moves to another
+	// product; untouched metadata predating a schema is left alone.
+	if req.Metadata != nil || prod != nil {
+		if prod == nil {
+			if prod, err = s.products.FindProductByID(ctx, currentLicense.ProductID); err != nil {
+				return nil, fmt.Errorf("repository error finding product of license %s: %w", id, err)
+			}
+		}
+		if err := checkMetadataSchema(prod, currentLicense.Metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	store := func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, currentLicense); err != nil {
+			s.logger.Error("Repository failed to update license", zap.String("id", id.String()), zap.Error(err))
+			return fmt.Errorf("repository error updating license %s: %w", id, err)
+		}
+		return nil
+	}
+	if seatsGrow {
+		// The lock of the parent keeps other children from taking the
+		// seats between the check and the update.
+		err = s.repo.WithLock(ctx, currentLicense.ParentID.UUID, func(ctx context.Context) error {
+			if err := s.checkChildSeats(ctx, currentLicense, currentLicense.MaxActivations); err != nil {
+				return err
+			}
+			return store(ctx)
+		})
+	} else {
+		err = store(ctx)
+	}
 	if err != nil {
+		return nil, err
+	}
 
-		s.logger.Error("Repository failed to update license", zap.String("id", id.String()), zap.Error(err))
-		return nil, fmt.Errorf("repository error updating license %s: %w", id, err)
+	if expiryChanged {
+		if _, err := syncChildren(ctx, s.repo, currentLicense); err != nil {
+			s.logger.Error("License updated but its child licenses were not", zap.String("id", id.String()), zap.Error(err))
+			return nil, fmt.Errorf("license %s was updated, but updating its child licenses failed: %w", id, err)
+		}
 	}
 
 	s.logger.Info("License updated successfully in service", zap.String("id", id.String()))
 	return currentLicense, nil
 }
 
+// checkChildSeats checks that child still fits into the seats of its parent
+// with the given seat count.
+func (s *LicenseService) checkChildSeats(ctx context.Context, child *license.License, seats int) error {
+	parent, err := s.repo.FindByID(ctx, child.ParentID.UUID)
+	if err != nil {
+		return fmt.Errorf("repository error fetching parent license %s: %w", child.ParentID.UUID, err)
+	}
+	return checkChildSeats(ctx, s.repo, parent, child.ID, seats)
+}
+
 type ValidationResult struct {
 	IsValid      bool
 	Reason       string
+	Warnings     []string
 	License      *license.License
 	ResponseData json.RawMessage
+	// ChangedSinceLast is set when ResponseData differs from what the same
+	// agent was given on its previous validation; Changes says how.
+	ChangedSinceLast bool
+	Changes          *lastseen.Changes
+	// StatusReason is the operator's reason for a suspended license.
+	StatusReason string
+	// GraceExpiresAt is set when the license is valid only because it is
+	// still within its grace period.
+	GraceExpiresAt *time.Time
+	// UsageMetric is the metric whose monthly limit was reached when the
+	// reason is usage_limit_exceeded.
+	UsageMetric string
+	// FloatingSeats is set for valid floating licenses.
+	FloatingSeats *dto.FloatingSeats
+	// Token is the signed entitlement token of a valid result, when the
+	// request asked for one.
+	Token          string
+	TokenKeyID     string
+	TokenExpiresAt *time.Time
+	// CacheTTL and RevalidateAfter are the cache hints of the product's
+	// cache policy, in seconds; nil when it has none.
+	CacheTTL        *int
+	RevalidateAfter *int
+}
+
+// Validation reasons returned to agents. Reasons for non-active licenses are
+// the license status itself (pending, inactive, revoked).
+const (
+	ReasonValid            = "valid"
+	ReasonInGracePeriod    = "in_grace_period"
+	ReasonNotFound         = "not_found"
+	ReasonProductMismatch  = "product_mismatch"
+	ReasonExpired          = "expired"
+	ReasonNotYetActive     = "not_yet_active"
+	ReasonProductEOL       = "product_eol"
+	ReasonAgentOutdated    = "agent_outdated"
+	ReasonDeviceIDRequired = "device_id_required"
+	ReasonDeviceIDMismatch = "device_id_mismatch"
+	ReasonDeviceNotActive  = "device_not_activated"
+	ReasonSeatLimit        = "seat_limit_exceeded"
+	ReasonUserIDRequired   = "user_id_required"
+	ReasonUserIDMismatch   = "user_id_mismatch"
+	ReasonUsageLimit       = "usage_limit_exceeded"
+)
+
+// CapabilitiesVersion is bumped whenever the meaning of an existing
+// capability changes; new capabilities are additive and do not bump it.
+const CapabilitiesVersion = 1
+
+var supportedReasons = []string{
+	ReasonValid,
+	ReasonInGracePeriod,
+	ReasonNotFound,
+	ReasonProductMismatch,
+	string(license.StatusPending),
+	string(license.StatusInactive),
+	string(license.StatusRevoked),
+	string(license.StatusSuspended),
+	ReasonExpired,
+	ReasonNotYetActive,
+	ReasonProductEOL,
+	ReasonAgentOutdated,
+	ReasonDeviceIDRequired,
+	ReasonDeviceIDMismatch,
+	ReasonDeviceNotActive,
+	ReasonSeatLimit,
+	ReasonUserIDRequired,
+	ReasonUserIDMismatch,
+	ReasonUsageLimit,
+}
+
+// ServerCapabilities describes what this server can do so agents can skip
+// features it lacks and treat unknown reasons as a generic denial.
+func (s *LicenseService) ServerCapabilities() *dto.ServerCapabilities {
+	reasons := make([]string, len(supportedReasons))
+	copy(reasons, supportedReasons)
+	reasons = append(reasons, s.statuses.DenyingNames()...)
+	return &dto.ServerCapabilities{
+		Version:          CapabilitiesVersion,
+		ServerVersion:    buildinfo.ServiceVersion(),
+		SupportedReasons: reasons,
+		Heartbeat:        true,
+		OfflineTokens:    s.keyring.Algorithm() != "",
+		SigningAlg:       s.keyring.Algorithm(),
+
+		EntitlementChanges: s.lastSeen != nil,
+	}
+}
+
+// exceededUsageLimit returns the metric whose usage.<metric> limit the
+// license has reached this month. It fails open: an unreadable counter does
+// not deny the license.
+func (s *LicenseService) exceededUsageLimit(ctx context.Context, id uuid.UUID, entitlements []*entitlement.Entitlement, now time.Time) string {
+	limits := usageLimits(entitlements)
+	if len(limits) == 0 {
+		return ""
+	}
+	totals, err := s.usage.Totals(ctx, id, usage.PeriodMonth.Start(now))
+	if err != nil {
+		s.logger.Warn("Failed to sum usage during validation, not enforcing usage limits", zap.String("license_id", id.String()), zap.Error(err))
+		return ""
+	}
+	return firstExceededMetric(limits, totals)
+}
+
+// floatingSeats reports the seats checked out of a floating license. A failed
+// count only leaves it out of the response.
+func (s *LicenseService) floatingSeats(ctx context.Context, lic *license.License) *dto.FloatingSeats {
+	inUse, err := s.leases.InUse(ctx, lic.ID)
+	if err != nil {
+		s.logger.Warn("Failed to count floating seats during validation", zap.String("license_id", lic.ID.String()), zap.Error(err))
+		return nil
+	}
+	maxActive, err := license.OwnSeats(ctx, s.repo, lic)
+	if err != nil {
+		s.logger.Warn("Failed to count seats of child licenses during validation", zap.String("license_id", lic.ID.String()), zap.Error(err))
+		return nil
+	}
+	return &dto.FloatingSeats{InUse: inUse, Max: maxActive}
+}
+
+// suspensionReason looks up why the license was suspended. A failed lookup
+// only loses the reason; the license is denied either way.
+func (s *LicenseService) suspensionReason(ctx context.Context, id uuid.UUID) string {
+	entry, err := s.history.Latest(ctx, id, license.StatusSuspended)
+	if err != nil {
+		if !errors.Is(err, ierr.ErrNotFound) {
+			s.logger.Warn("Failed to look up suspension reason", zap.String("license_id", id.String()), zap.Error(err))
+		}
+		return ""
+	}
+	return entry.Reason
 }
 
 const (
@@ -234,28 +1516,146 @@ const (
 	MetaKeyUserID          = "user_id"
 	MetaKeyIPAddress       = "ip_address"
 	MetaKeyLastValidatedAt = "last_validated_at"
-	MetaKeyFeatures        = "features"
-	MetaKeyLimits          = "limits"
 )
 
+// ValidateLicense checks a license key for an agent, records the outcome as
+// a validation event and dispatches failures to webhooks.
 func (s *LicenseService) ValidateLicense(ctx context.Context, req *dto.ValidateLicenseRequest) (*ValidationResult, error) {
+	result, err := s.validateLicense(ctx, req)
+	if err == nil {
+		s.addCacheHints(ctx, req.ProductName, result)
+	}
+	if err == nil && req.SignedToken {
+		s.signValidation(req, result)
+	}
+	if err == nil && s.validationEvents != nil {
+		s.validationEvents.Record(ctx, req, result)
+	}
+	if err == nil && !result.IsValid && s.webhooks != nil {
+		s.webhooks.ValidationFailed(req, result)
+	}
+	return result, err
+}
+
+// addCacheHints sets the hints of the product's cache policy on result. A
+// valid result must not be relied on past the grace period of its license,
+// so both hints end there. Like checkAgentVersion it fails open: without a
+// policy, or when it cannot be loaded, the hints are left out.
+func (s *LicenseService) addCacheHints(ctx context.Context, productName string, result *ValidationResult) {
+	policy, err := s.products.FindCachePolicy(ctx, productName)
+	if err != nil {
+		if !errors.Is(err, ierr.ErrNotFound) {
+			s.logger.Warn("Failed to load product cache policy during validation", zap.String("product_name", productName), zap.Error(err))
+		}
+		return
+	}
+
+	ttl, revalidate := policy.CacheTTL, policy.RevalidateAfter
+	if result.IsValid {
+		if end, ok := result.License.GraceExpiresAt(); ok {
+			left := max(int(time.Until(end)/time.Second), 0)
+			ttl, revalidate = min(ttl, left), min(revalidate, left)
+		}
+	}
+	result.CacheTTL, result.RevalidateAfter = &ttl, &revalidate
+}
+
+// signValidation adds an entitlement token to a valid result. It is bounded
+// by the grace period of the license like a passport. Without an active
+// signing key the result goes out unsigned with a warning, so agents asking
+// for tokens keep validating.
+func (s *LicenseService) signValidation(req *dto.ValidateLicenseRequest, result *ValidationResult) {
+	if !result.IsValid {
+		return
+	}
+	if s.keyring.Algorithm() == "" {
+		result.Warnings = append(result.Warnings, "signed tokens are not available on this server")
+		return
+	}
+
+	lic := result.License
+	now := time.Now().UTC()
+	expiresAt := now.Add(s.keyring.EntitlementTokenTTL())
+	if end, ok := lic.GraceExpiresAt(); ok && end.Before(expiresAt) {
+		expiresAt = end
+	}
+	claims := signing.EntitlementClaims{
+		Issuer:       s.keyring.Issuer(),
+		Subject:      lic.ID.String(),
+		IssuedAt:     now.Unix(),
+		ExpiresAt:    expiresAt.Unix(),
+		Product:      lic.ProductName,
+		Type:         lic.Type,
+		Status:       string(lic.Status),
+		Reason:       result.Reason,
+		Entitlements: result.ResponseData,
+	}
+	if lic.ExpiresAt.Valid {
+		claims.LicenseExpiresAt = lic.ExpiresAt.Time.Unix()
+	}
+	if result.GraceExpiresAt != nil {
+		claims.GraceExpiresAt = result.GraceExpiresAt.Unix()
+	}
+	if meta, ok := decodeMetadata(req.Metadata); ok {
+		claims.DeviceID, _ = meta[MetaKeyDeviceID].(string)
+	}
+
+	token, kid, err := s.keyring.Sign(signing.EntitlementTokenType, claims)
+	if err != nil {
+		s.logger.Error("Failed to sign entitlement token", zap.String("license_id", lic.ID.String()), zap.Error(err))
+		result.Warnings = append(result.Warnings, "signed tokens are not available on this server")
+		return
+	}
+	result.Token = token
+	result.TokenKeyID = kid
+	result.TokenExpiresAt = &expiresAt
+}
+
+// validateBatchConcurrency is how many licenses of a batch are validated at
+// the same time.
+const validateBatchConcurrency = 8
+
+// ValidateLicenses validates each of reqs as ValidateLicense does. Results
+// and errors are in request order; an entry has either a result or an error.
+func (s *LicenseService) ValidateLicenses(ctx context.Context, reqs []dto.ValidateLicenseRequest) ([]*ValidationResult, []error) {
+	results := make([]*ValidationResult, len(reqs))
+	errs := make([]error, len(reqs))
+	var g errgroup.Group
+	g.SetLimit(validateBatchConcurrency)
+	for i := range reqs {
+		g.Go(func() error {
+			results[i], errs[i] = s.ValidateLicense(ctx, &reqs[i])
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return results, errs
+}
+
+func (s *LicenseService) validateLicense(ctx context.Context, req *dto.ValidateLicenseRequest) (*ValidationResult, error) {
+	defer servertiming.Track(ctx, servertiming.MetricPolicy)()
 	s.logger.Info("Attempting to validate license key",
 		zap.String("license_key", req.LicenseKey),
 		zap.String("product_name", req.ProductName),
 	)
 
+	if err := jsonlimit.Check(req.Metadata, jsonlimit.Default); err != nil {
+		s.logger.Warn("Agent metadata rejected by JSON limits", zap.String("license_key", req.LicenseKey), zap.Error(err))
+		return nil, err
+	}
+
 	result := &ValidationResult{IsValid: false}
 
 	lic, err := s.repo.FindByKey(ctx, req.LicenseKey)
 	if err != nil {
-		if errors.Is(err, ierr.ErrNotFound) || errors.Is(err, pgx.ErrNoRows) {
+		if errors.Is(err, ierr.ErrNotFound) {
 			s.logger.Info("License key not found during validation", zap.String("license_key", req.LicenseKey))
-			result.Reason = "not_found"
+			result.Reason = ReasonNotFound
 			return result, nil
 		}
 
 		s.logger.Error("Repository error finding license by key during validation", zap.String("license_key", req.LicenseKey), zap.Error(err))
-		return nil, fmt.Errorf("repository error validating key %s: %w", req.LicenseKey, err)
+		return nil, fmt.Errorf("repository error finding license to validate: %w", err)
 	}
 
 	result.License = lic
@@ -266,11 +1666,38 @@ func (s *LicenseService) ValidateLicense(ctx context.Context, req *dto.ValidateL
 			zap.String("expected_product", req.ProductName),
 			zap.String("actual_product", lic.ProductName),
 		)
-		result.Reason = "product_mismatch"
+		result.Reason = ReasonProductMismatch
 		return result, nil
 	}
 
-	if lic.Status != license.StatusActive {
+	now := time.Now().UTC()
+	if lic.Status == license.StatusPending || lic.Status == license.StatusActive {
+		if lic.NotYetActive(now) {
+			s.logger.Info("License is scheduled to start later",
+				zap.String("license_key", req.LicenseKey),
+				zap.Time("starts_at", lic.StartsAt.Time),
+			)
+			result.Reason = ReasonNotYetActive
+			return result, nil
+		}
+		if lic.Due(now) {
+			// The expiry worker has not activated the license yet; do it now
+			// rather than deny an agent whose license has started.
+			lic.Status = license.StatusActive
+			lId := lic.ID
+			s.background.Submit("license_start", func(bgCtx context.Context) {
+				if err := s.repo.UpdateStatus(bgCtx, lId, license.StatusActive); err != nil {
+					s.logger.Error("Background status update to active failed", zap.String("license_id", lId.String()), zap.Error(err))
+				}
+			})
+		}
+	}
+
+	admitted, statusWarning := false, ""
+	if !lic.Status.IsBuiltin() {
+		admitted, statusWarning = s.statuses.Admits(lic.Status)
+	}
+	if lic.Status != license.StatusActive && !admitted {
 		s.logger.Info("License has non-active status during validation",
 			zap.String("license_key", req.LicenseKey),
 			zap.String("status", string(lic.Status)),
@@ -278,50 +1705,103 @@ func (s *LicenseService) ValidateLicense(ctx context.Context, req *dto.ValidateL
 		result.Reason = string(lic.Status)
 
 		if lic.Status == license.StatusExpired {
-			result.Reason = "expired"
+			result.Reason = ReasonExpired
+		}
+		if lic.Status == license.StatusSuspended {
+			result.StatusReason = s.suspensionReason(ctx, lic.ID)
 		}
 		return result, nil
 	}
 
-	now := time.Now().UTC()
-	if lic.ExpiresAt.Valid && now.After(lic.ExpiresAt.Time.UTC()) {
+	if lic.Lapsed(now) {
 		s.logger.Info("License has expired (date check)",
 			zap.String("license_key", req.LicenseKey),
 			zap.Time("expires_at", lic.ExpiresAt.Time),
 		)
-		result.Reason = "expired"
-
-		go func(lId uuid.UUID, r license.Repository, l *zap.Logger) {
-			bgCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-			defer cancel()
-			l.Info("Attempting background status update to expired", zap.String("license_id", lId.String()))
-			if err := r.UpdateStatus(bgCtx, lId, license.StatusExpired); err != nil {
-				l.Error("Background status update to expired failed", zap.String("license_id", lId.String()), zap.Error(err))
+		result.Reason = ReasonExpired
+
+		lId := lic.ID
+		s.background.Submit("license_expire", func(bgCtx context.Context) {
+			s.logger.Info("Attempting background status update to expired", zap.String("license_id", lId.String()))
+			if err := s.repo.UpdateStatus(bgCtx, lId, license.StatusExpired); err != nil {
+				s.logger.Error("Background status update to expired failed", zap.String("license_id", lId.String()), zap.Error(err))
 			}
-		}(lic.ID, s.repo, s.logger)
+		})
+
+		return result, nil
+	}
+
+	lifecycleWarning, deny := s.checkProductLifecycle(ctx, lic.ProductName, now)
+	if deny {
+		s.logger.Info("License denied because product reached end of life",
+			zap.String("license_key", req.LicenseKey),
+			zap.String("product_name", lic.ProductName),
+		)
+		result.Reason = ReasonProductEOL
+		return result, nil
+	}
 
+	agentWarning, deny := s.checkAgentVersion(ctx, lic.ProductName, req.AgentVersion)
+	if deny {
+		s.logger.Info("License denied because agent is older than the product minimum",
+			zap.String("license_key", req.LicenseKey),
+			zap.String("product_name", lic.ProductName),
+			zap.String("agent_version", req.AgentVersion),
+		)
+		result.Reason = ReasonAgentOutdated
 		return result, nil
 	}
 
-	var agentMeta map[string]interface{}
-	var licenseMeta map[string]interface{}
-	agentMetaValid := req.Metadata != nil && json.Unmarshal(req.Metadata, &agentMeta) == nil
-	licenseMetaValid := lic.Metadata != nil && json.Unmarshal(lic.Metadata, &licenseMeta) == nil
+	agentMeta, agentMetaValid := decodeMetadata(req.Metadata)
+	licenseMeta, licenseMetaValid := decodeMetadata(lic.Metadata)
+
+	activated, err := s.activations.ListActive(ctx, lic.ID)
+	if err != nil {
+		s.logger.Error("Failed to load activations during validation", zap.String("license_key", req.LicenseKey), zap.Error(err))
+		return nil, fmt.Errorf("repository error loading activations of license %s: %w", lic.ID, err)
+	}
+	if len(activated) > 0 {
+		agentDeviceID, _ := agentMeta[MetaKeyDeviceID].(string)
+		if agentDeviceID == "" {
+			s.logger.Warn("Device ID required for activated license but not provided", zap.String("license_key", req.LicenseKey))
+			result.Reason = ReasonDeviceIDRequired
+			return result, nil
+		}
+		if !deviceActivated(activated, agentDeviceID) {
+			maxActive, err := license.OwnSeats(ctx, s.repo, lic)
+			if err != nil {
+				return nil, err
+			}
+			s.logger.Warn("Validation from a device the license is not activated on",
+				zap.String("license_key", req.LicenseKey),
+				zap.String("agent_device", agentDeviceID),
+				zap.Int("seats_used", len(activated)),
+				zap.Int("max_activations", maxActive),
+			)
+			result.Reason = ReasonDeviceNotActive
+			if len(activated) >= maxActive {
+				result.Reason = ReasonSeatLimit
+			}
+			return result, nil
+		}
+	}
 
 	if licenseMetaValid {
 		licenseDeviceID, hasDeviceBinding := licenseMeta[MetaKeyDeviceID].(string)
 		licenseUserID, hasUserBinding := licenseMeta[MetaKeyUserID].(string)
 
-		if hasDeviceBinding && licenseDeviceID != "" {
+		// The metadata device binding predates activations and only applies
+		// to licenses that have never been activated.
+		if hasDeviceBinding && licenseDeviceID != "" && len(activated) == 0 {
 			if !agentMetaValid {
 				s.logger.Warn("Device ID required but not provided by agent", zap.String("license_key", req.LicenseKey))
-				result.Reason = "device_id_required"
+				result.Reason = ReasonDeviceIDRequired
 				return result, nil
 			}
 			agentDeviceID, agentHasDeviceID := agentMeta[MetaKeyDeviceID].(string)
 			if !agentHasDeviceID || agentDeviceID == "" {
 				s.logger.Warn("Device ID required but empty in agent request", zap.String("license_key", req.LicenseKey))
-				result.Reason = "device_id_required"
+				result.Reason = ReasonDeviceIDRequired
 				return result, nil
 			}
 			if agentDeviceID != licenseDeviceID {
@@ -330,7 +1810,7 @@ func (s *LicenseService) ValidateLicense(ctx context.Context, req *dto.ValidateL
 					zap.String("agent_device", agentDeviceID),
 					zap.String("license_device", licenseDeviceID),
 				)
-				result.Reason = "device_id_mismatch"
+				result.Reason = ReasonDeviceIDMismatch
 				return result, nil
 			}
 		}
@@ -338,7 +1818,7 @@ func (s *LicenseService) ValidateLicense(ctx context.Context, req *dto.ValidateL
 		if hasUserBinding && licenseUserID != "" {
 			if !agentMetaValid {
 				s.logger.Warn("User ID required but not provided by agent", zap.String("license_key", req.LicenseKey))
-				result.Reason = "user_id_required"
+				result.Reason = ReasonUserIDRequired
 				return result, nil
 			}
 
@@ -346,7 +1826,7 @@ func (s *LicenseService) ValidateLicense(ctx context.Context, req *dto.ValidateL
 
 			if !agentHasUserID || agentUserID == "" {
 				s.logger.Warn("User ID required but empty in agent request", zap.String("license_key", req.LicenseKey))
-				result.Reason = "user_id_required"
+				result.Reason = ReasonUserIDRequired
 				return result, nil
 			}
 
@@ -356,35 +1836,66 @@ func (s *LicenseService) ValidateLicense(ctx context.Context, req *dto.ValidateL
 					zap.String("agent_user", agentUserID),
 					zap.String("license_user", licenseUserID),
 				)
-				result.Reason = "user_id_mismatch"
+				result.Reason = ReasonUserIDMismatch
 				return result, nil
 			}
 		}
 	}
 
+	// Unlike the checks above, entitlements do not fail open: a valid
+	// license without them would look to the agent as if they were revoked.
+	entitlements, err := licenseEntitlements(ctx, s.entitlements, lic)
+	if err != nil {
+		s.logger.Error("Failed to load entitlements during validation", zap.String("license_key", req.LicenseKey), zap.Error(err))
+		return nil, fmt.Errorf("repository error listing entitlements of license %s: %w", lic.ID, err)
+	}
+	if metric := s.exceededUsageLimit(ctx, lic.ID, entitlements, now); metric != "" {
+		s.logger.Info("Validation denied, usage limit reached",
+			zap.String("license_key", req.LicenseKey),
+			zap.String("metric", metric),
+		)
+		result.Reason = ReasonUsageLimit
+		result.UsageMetric = metric
+		return result, nil
+	}
+
 	s.logger.Info("License validation successful", zap.String("license_key", req.LicenseKey))
 	result.IsValid = true
-	result.Reason = "valid"
-
-	if licenseMetaValid {
-		allowedDataMap := make(map[string]interface{})
-		if features, ok := licenseMeta[MetaKeyFeatures]; ok {
-			allowedDataMap[MetaKeyFeatures] = features
-		}
-		if limits, ok := licenseMeta[MetaKeyLimits]; ok {
-			allowedDataMap[MetaKeyLimits] = limits
-		}
+	if lic.Floating {
+		result.FloatingSeats = s.floatingSeats(ctx, lic)
+	}
+	result.Reason = ReasonValid
+	if lic.InGracePeriod(now) {
+		graceEnd, _ := lic.GraceExpiresAt()
+		result.Reason = ReasonInGracePeriod
+		result.GraceExpiresAt = &graceEnd
+	}
+	if lifecycleWarning != "" {
+		result.Warnings = append(result.Warnings, lifecycleWarning)
+	}
+	if agentWarning != "" {
+		result.Warnings = append(result.Warnings, agentWarning)
+	}
+	if statusWarning != "" {
+		result.Warnings = append(result.Warnings, statusWarning)
+	}
+	// FindByKey also resolves keys that were rotated away during their
+	// overlap window.
+	if lic.LicenseKey != req.LicenseKey {
+		result.Warnings = append(result.Warnings, "license key has been replaced and will stop working soon; switch to the new key")
+	}
 
-		if len(allowedDataMap) > 0 {
-			allowedBytes, errJson := json.Marshal(allowedDataMap)
-			if errJson == nil {
-				result.ResponseData = allowedBytes
-			} else {
-				s.logger.Error("Failed to marshal allowed_data", zap.String("license_key", req.LicenseKey), zap.Error(errJson))
-			}
-		}
+	allowedBytes, errJson := allowedData(entitlements)
+	if errJson == nil {
+		result.ResponseData = allowedBytes
+	} else {
+		s.logger.Error("Failed to marshal allowed_data", zap.String("license_key", req.LicenseKey), zap.Error(errJson))
 	}
 
+	agentDeviceID, _ := agentMeta[MetaKeyDeviceID].(string)
+	result.Changes = s.entitlementChanges(ctx, lic.ID, agentDeviceID, result.ResponseData, now)
+	result.ChangedSinceLast = result.Changes != nil
+
 	updateData := make(map[string]interface{})
 	updateData[MetaKeyLastValidatedAt] = now
 
@@ -397,46 +1908,218 @@ func (s *LicenseService) ValidateLicense(ctx context.Context, req *dto.ValidateL
 	}
 
 	if len(updateData) > 0 {
-		go func(lId uuid.UUID, currentMeta []byte, dataToUpdate map[string]interface{}, r license.Repository, l *zap.Logger) {
-			bgCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-			defer cancel()
-			l.Debug("Attempting background metadata update", zap.String("license_id", lId.String()))
-
-			mergedMetaMap := make(map[string]interface{})
-			if currentMeta != nil {
-				_ = json.Unmarshal(currentMeta, &mergedMetaMap)
-			}
-			for k, v := range dataToUpdate {
-				mergedMetaMap[k] = v
-			}
+		lId, currentMeta := lic.ID, []byte(lic.Metadata)
+		s.background.Submit("license_metadata_update", func(bgCtx context.Context) {
+			s.logger.Debug("Attempting background metadata update", zap.String("license_id", lId.String()))
 
-			newMetaBytes, errMarshal := json.Marshal(mergedMetaMap)
+			newMetaBytes, errMarshal := MergeMetadata(currentMeta, updateData)
 			if errMarshal != nil {
-				l.Error("Failed to marshal metadata for background update", zap.String("license_id", lId.String()), zap.Error(errMarshal))
+				s.logger.Error("Failed to marshal metadata for background update", zap.String("license_id", lId.String()), zap.Error(errMarshal))
 				return
 			}
 
 			if bytes.Equal(currentMeta, newMetaBytes) {
-				l.Debug("Metadata hasn't changed, skipping background update", zap.String("license_id", lId.String()))
+				s.logger.Debug("Metadata hasn't changed, skipping background update", zap.String("license_id", lId.String()))
 				return
 			}
 
-			if err := r.UpdateMetadata(bgCtx, lId, newMetaBytes); err != nil {
-				l.Error("Background metadata update failed", zap.String("license_id", lId.String()), zap.Error(err))
+			if err := s.repo.UpdateMetadata(bgCtx, lId, newMetaBytes); err != nil {
+				s.logger.Error("Background metadata update failed", zap.String("license_id", lId.String()), zap.Error(err))
 			} else {
-				l.Info("Background metadata update successful", zap.String("license_id", lId.String()))
+				s.logger.Info("Background metadata update successful", zap.String("license_id", lId.String()))
 			}
-
-		}(lic.ID, lic.Metadata, updateData, s.repo, s.logger)
+		})
 	}
 
 	return result, nil
 }
 
-func (s *LicenseService) GetDashboardSummary(ctx context.Context) (*dto.DashboardSummaryResponse, error) {
+// resolveProduct finds the product a license is issued for by ID or name.
+// A product that does not exist is a validation error of the request.
+func resolveProduct(ctx context.Context, products product.Repository, id uuid.UUID, name string) (*product.Product, error) {
+	var prod *product.Product
+	var err error
+	switch {
+	case id != uuid.Nil:
+		prod, err = products.FindProductByID(ctx, id)
+	case name != "":
+		prod, err = products.FindProductByName(ctx, name)
+	default:
+		return nil, fmt.Errorf("%w: product_id or product_name is required", ierr.ErrValidation)
+	}
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, fmt.Errorf("%w: product does not exist, create it under /api/v1/products first", ierr.ErrValidation)
+		}
+		return nil, fmt.Errorf("repository error finding product: %w", err)
+	}
+	if name != "" && prod.Name != name {
+		return nil, fmt.Errorf("%w: product_id and product_name refer to different products", ierr.ErrValidation)
+	}
+	return prod, nil
+}
+
+// resolveCustomer finds the customer a license is issued to, by ID or by
+// e-mail; when both are given they must agree. An unknown e-mail creates a
+// customer with name, while an existing customer keeps its own name. It
+// returns nil when neither is given.
+func (s *LicenseService) resolveCustomer(ctx context.Context, id *idgen.ID, email, name *string) (*customer.Customer, error) {
+	if id != nil {
+		cust, err := s.customers.FindByID(ctx, id.UUID())
+		if err != nil {
+			if errors.Is(err, ierr.ErrNotFound) {
+				return nil, fmt.Errorf("%w: customer does not exist, create it under /api/v1/customers first", ierr.ErrValidation)
+			}
+			return nil, fmt.Errorf("repository error finding customer: %w", err)
+		}
+		if email != nil && !strings.EqualFold(cust.Email, strings.TrimSpace(*email)) {
+			return nil, fmt.Errorf("%w: customer_id and customer_email refer to different customers", ierr.ErrValidation)
+		}
+		return cust, nil
+	}
+	if email == nil {
+		return nil, nil
+	}
+
+	customerName := ""
+	if name != nil {
+		customerName = strings.TrimSpace(*name)
+	}
+	cust, err := s.customers.Ensure(ctx, strings.TrimSpace(*email), customerName)
+	if err != nil {
+		return nil, fmt.Errorf("repository error ensuring customer: %w", err)
+	}
+	return cust, nil
+}
+
+// setCustomer points lic at cust and copies its name and e-mail, reporting
+// whether anything changed.
+func setCustomer(lic *license.License, cust *customer.Customer) bool {
+	name := sql.NullString{String: cust.Name, Valid: cust.Name != ""}
+	email := sql.NullString{String: cust.Email, Valid: true}
+	if lic.CustomerID.Valid && lic.CustomerID.UUID == cust.ID && lic.CustomerName == name && lic.CustomerEmail == email {
+		return false
+	}
+	lic.CustomerID = uuid.NullUUID{UUID: cust.ID, Valid: true}
+	lic.CustomerName = name
+	lic.CustomerEmail = email
+	return true
+}
+
+// entitlementChanges compares the entitlements an agent is about to receive
+// with the ones recorded at its previous validation and returns nil when
+// nothing changed. Like the other validation checks it fails open: a store
+// error means no change is reported.
+func (s *LicenseService) entitlementChanges(ctx context.Context, licenseID uuid.UUID, agent string, entitlements json.RawMessage, now time.Time) *lastseen.Changes {
+	if s.lastSeen == nil {
+		return nil
+	}
+
+	previous, err := s.lastSeen.Get(ctx, licenseID, agent)
+	if err != nil {
+		s.logger.Warn("Failed to load last seen entry during validation", zap.String("license_id", licenseID.String()), zap.Error(err))
+		return nil
+	}
+
+	s.background.Submit("last_seen_touch", func(bgCtx context.Context) {
+		if err := s.lastSeen.Touch(bgCtx, licenseID, agent, entitlements, now, previous); err != nil {
+			s.logger.Warn("Failed to record last seen entry", zap.String("license_id", licenseID.String()), zap.Error(err))
+		}
+	})
+
+	if previous == nil {
+		return nil
+	}
+	changes := lastseen.Diff(previous.Entitlements, entitlements)
+	if changes.Empty() {
+		return nil
+	}
+	return changes
+}
+
+// checkProductLifecycle fails open: if the lifecycle cannot be loaded the
+// product is treated as active rather than failing validation.
+func (s *LicenseService) checkProductLifecycle(ctx context.Context, productName string, now time.Time) (string, bool) {
+	lc, err := s.products.FindLifecycle(ctx, productName)
+	if err != nil {
+		if !errors.Is(err, ierr.ErrNotFound) {
+			s.logger.Warn("Failed to load product lifecycle during validation", zap.String("product_name", productName), zap.Error(err))
+		}
+		return "", false
+	}
+	return lc.Evaluate(now)
+}
+
+// checkAgentVersion fails open like checkProductLifecycle: a product without
+// a policy, or a policy that cannot be loaded, accepts any agent.
+func (s *LicenseService) checkAgentVersion(ctx context.Context, productName, agentVersion string) (string, bool) {
+	policy, err := s.products.FindAgentPolicy(ctx, productName)
+	if err != nil {
+		if !errors.Is(err, ierr.ErrNotFound) {
+			s.logger.Warn("Failed to load product agent policy during validation", zap.String("product_name", productName), zap.Error(err))
+		}
+		return "", false
+	}
+	return policy.Evaluate(agentVersion)
+}
+
+func deviceActivated(activations []*activation.Activation, deviceID string) bool {
+	for _, a := range activations {
+		if a.DeviceID == deviceID {
+			return true
+		}
+	}
+	return false
+}
+
+// checkMetadataSchema validates metadata against the metadata schema of
+// prod, if it has one.
+func checkMetadataSchema(prod *product.Product, metadata json.RawMessage) error {
+	if len(prod.MetadataSchema) == 0 {
+		return nil
+	}
+	schema, err := metaschema.Compile(prod.MetadataSchema)
+	if err != nil {
+		return fmt.Errorf("metadata schema of product %s: %w", prod.Name, err)
+	}
+	return schema.Validate(metadata)
+}
+
+func decodeMetadata(data json.RawMessage) (map[string]interface{}, bool) {
+	if len(data) == 0 {
+		return nil, false
+	}
+	meta, err := jsonlimit.DecodeObject(data)
+	if err != nil || meta == nil {
+		return nil, false
+	}
+	return meta, true
+}
+
+// MergeMetadata overlays updates on top of the current metadata object.
+// Numbers are decoded as json.Number so values the agent never touched are
+// written back byte-for-byte instead of going through float64. Metadata that
+// is not a JSON object is replaced.
+func MergeMetadata(current json.RawMessage, updates map[string]interface{}) ([]byte, error) {
+	merged, ok := decodeMetadata(current)
+	if !ok {
+		merged = make(map[string]interface{}, len(updates))
+	}
+	for k, v := range updates {
+		merged[k] = v
+	}
+	return json.Marshal(merged)
+}
+
+// GetDashboardSummary counts licenses expiring within expiringPeriodDays, or
+// within defaultExpiringPeriodDays when it is zero.
+func (s *LicenseService) GetDashboardSummary(ctx context.Context, expiringPeriodDays int) (*dto.DashboardSummaryResponse, error) {
 	s.logger.Info("Requesting dashboard summary data")
 
-	summaryData, err := s.repo.GetDashboardSummary(ctx, defaultExpiringPeriodDays)
+	if expiringPeriodDays <= 0 {
+		expiringPeriodDays = defaultExpiringPeriodDays
+	}
+	summaryData, err := s.repo.GetDashboardSummary(ctx, expiringPeriodDays)
 	if err != nil {
 		s.logger.Error("Failed to get dashboard summary from repository", zap.Error(err))
 		return nil, fmt.Errorf("repository error fetching dashboard summary: %w", err)
@@ -447,12 +2130,23 @@ func (s *LicenseService) GetDashboardSummary(ctx context.Context) (*dto.Dashboar
 		StatusCounts:  summaryData.StatusCounts,
 		TypeCounts:    summaryData.TypeCounts,
 		ProductCounts: summaryData.ProductCounts,
+		TagCounts:     summaryData.TagCounts,
 		ExpiringSoon: dto.ExpiringSoonSummary{
 			Count:      summaryData.ExpiringSoonCount,
-			PeriodDays: defaultExpiringPeriodDays,
+			PeriodDays: expiringPeriodDays,
 		},
 	}
 
+	installations, err := s.activations.CountSeenSince(ctx, time.Now().Add(-activeInstallationWindow))
+	if err != nil {
+		s.logger.Error("Failed to count active installations", zap.Error(err))
+		return nil, fmt.Errorf("repository error counting active installations: %w", err)
+	}
+	response.ActiveInstallations = dto.ActiveInstallationsSummary{
+		Count:       installations,
+		WindowHours: int(activeInstallationWindow / time.Hour),
+	}
+
 	if summaryData.NextToExpireKey != nil && summaryData.NextToExpireDate != nil && summaryData.NextToExpireProd != nil {
 		response.ExpiringSoon.NextToExpire = &dto.LicenseInfo{
 			LicenseKey:  *summaryData.NextToExpireKey,
@@ -496,7 +2190,7 @@ func CheckAndExpireLicenses(ctx context.Context, repo license.Repository, logger
 
 		foundExpiredInBatch := 0
 		for _, lic := range activeLicenses {
-			if lic.ExpiresAt.Valid && lic.ExpiresAt.Time.UTC().Before(now) {
+			if lic.Lapsed(now) {
 				log.Info("Found expired license during startup check, updating status",
 					zap.String("license_id", lic.ID.String()),
 					zap.Time("expires_at", lic.ExpiresAt.Time),
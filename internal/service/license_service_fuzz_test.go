@@ -0,0 +1,83 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/jsonlimit"
+)
+
+func FuzzMergeMetadata(f *testing.F) {
+	for _, seed := range [][2]string{
+		{``, `{"device_id":"d1"}`},
+		{`{"last_ip":"10.0.0.1","big":12345678901234567890}`, `{"last_ip":"10.0.0.2"}`},
+		{`{"nested":{"a":[1,2.5,-3e10]}}`, `{"nested":null}`},
+		{`[1,2,3]`, `{"a":"b"}`},
+		{`null`, `{}`},
+		{`"text"`, `{"html":"<&>"}`},
+		{`{"a":1}`, `{"a":{"b":{"c":{"d":{"e":{"f":{"g":{"h":{"i":{"j":{"k":{"l":{"m":{"n":{"o":{"p":{"q":1}}}}}}}}}}}}}}}}}`},
+		{"{\"a\":\"\xff\"}", `{"b":1}`},
+	} {
+		f.Add([]byte(seed[0]), []byte(seed[1]))
+	}
+
+	f.Fuzz(func(t *testing.T, current, updatesJSON []byte) {
+		// Updates are agent metadata, which validation checks against the
+		// JSON limits before decoding.
+		if err := jsonlimit.Check(updatesJSON, jsonlimit.Default); err != nil {
+			var coded ierr.Coded
+			if !errors.As(err, &coded) {
+				t.Fatalf("limit error %v is not an ierr error", err)
+			}
+			return
+		}
+		updates, err := jsonlimit.DecodeObject(updatesJSON)
+		if err != nil {
+			var coded ierr.Coded
+			if !errors.As(err, &coded) {
+				t.Fatalf("decode error %v is not an ierr error", err)
+			}
+			return
+		}
+
+		merged, err := MergeMetadata(current, updates)
+		if err != nil {
+			t.Fatalf("MergeMetadata(%q, %q): %v", current, updatesJSON, err)
+		}
+
+		out, err := jsonlimit.DecodeObject(merged)
+		if err != nil || out == nil {
+			t.Fatalf("merged metadata %s is not a JSON object: %v", merged, err)
+		}
+		again, err := json.Marshal(out)
+		if err != nil {
+			t.Fatalf("merged metadata %s does not encode again: %v", merged, err)
+		}
+		if !bytes.Equal(merged, again) {
+			t.Fatalf("merged metadata does not round-trip:\n%s\n%s", merged, again)
+		}
+
+		for k, v := range updates {
+			assertSameValue(t, k, v, out[k])
+		}
+		if base, ok := decodeMetadata(current); ok {
+			for k, v := range base {
+				if _, updated := updates[k]; !updated {
+					assertSameValue(t, k, v, out[k])
+				}
+			}
+		}
+	})
+}
+
+func assertSameValue(t *testing.T, key string, want, got interface{}) {
+	t.Helper()
+	wantJSON, _ := json.Marshal(want)
+	gotJSON, _ := json.Marshal(got)
+	if !bytes.Equal(wantJSON, gotJSON) {
+		t.Fatalf("metadata key %q is %s after the merge, want %s", key, gotJSON, wantJSON)
+	}
+}
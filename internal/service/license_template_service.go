@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/entitlement"
+	"github.com/makkenzo/license-service-api/internal/domain/licensetemplate"
+	"github.com/makkenzo/license-service-api/internal/domain/product"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/jsonlimit"
+	"go.uber.org/zap"
+)
+
+type LicenseTemplateService struct {
+	templates licensetemplate.Repository
+	products  product.Repository
+	logger    *zap.Logger
+}
+
+func NewLicenseTemplateService(templates licensetemplate.Repository, products product.Repository, logger *zap.Logger) *LicenseTemplateService {
+	return &LicenseTemplateService{
+		templates: templates,
+		products:  products,
+		logger:    logger.Named("LicenseTemplateService"),
+	}
+}
+
+func (s *LicenseTemplateService) CreateTemplate(ctx context.Context, req *dto.CreateLicenseTemplateRequest) (*licensetemplate.Template, error) {
+	prod, err := resolveProduct(ctx, s.products, req.ProductID.UUID(), req.ProductName)
+	if err != nil {
+		return nil, err
+	}
+	entitlements, err := normalizeTemplateEntitlements(req.Entitlements)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTemplateMetadata(req.Metadata); err != nil {
+		return nil, err
+	}
+
+	t := &licensetemplate.Template{
+		Name:            strings.TrimSpace(req.Name),
+		ProductID:       prod.ID,
+		ProductName:     prod.Name,
+		Type:            req.Type,
+		DurationDays:    req.DurationDays,
+		MaxActivations:  req.MaxActivations,
+		GracePeriodDays: req.GracePeriodDays,
+		Entitlements:    entitlements,
+		Metadata:        req.Metadata,
+	}
+	if t.Name == "" {
+		return nil, fmt.Errorf("%w: template name must not be blank", ierr.ErrValidation)
+	}
+	if err := s.templates.Create(ctx, t); err != nil {
+		if errors.Is(err, ierr.ErrDuplicateKey) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error creating license template: %w", err)
+	}
+
+	s.logger.Info("License template created", zap.String("id", t.ID.String()), zap.String("name", t.Name))
+	return t, nil
+}
+
+func (s *LicenseTemplateService) ListTemplates(ctx context.Context, req *dto.ListLicenseTemplatesRequest) ([]*licensetemplate.Template, error) {
+	var productID *uuid.UUID
+	if req.ProductID != nil {
+		id, err := idgen.Parse(*req.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid product_id format", ierr.ErrValidation)
+		}
+		productID = &id
+	}
+	templates, err := s.templates.List(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing license templates: %w", err)
+	}
+	return templates, nil
+}
+
+func (s *LicenseTemplateService) GetTemplate(ctx context.Context, id uuid.UUID) (*licensetemplate.Template, error) {
+	t, err := s.templates.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error finding license template %s: %w", id, err)
+	}
+	return t, nil
+}
+
+// UpdateTemplate only affects licenses created afterwards.
+func (s *LicenseTemplateService) UpdateTemplate(ctx context.Context, id uuid.UUID, req *dto.UpdateLicenseTemplateRequest) (*licensetemplate.Template, error) {
+	t, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ProductID != nil || req.ProductName != nil {
+		var productID uuid.UUID
+		var productName string
+		if req.ProductID != nil {
+			productID = req.ProductID.UUID()
+		}
+		if req.ProductName != nil {
+			productName = *req.ProductName
+		}
+		prod, err := resolveProduct(ctx, s.products, productID, productName)
+		if err != nil {
+			return nil, err
+		}
+		t.ProductID, t.ProductName = prod.ID, prod.Name
+	}
+	if req.Name != nil {
+		if t.Name = strings.TrimSpace(*req.Name); t.Name == "" {
+			return nil, fmt.Errorf("%w: template name must not be blank", ierr.ErrValidation)
+		}
+	}
+	if req.Type != nil {
+		t.Type = *req.Type
+	}
+	if req.DurationDays != nil {
+		t.DurationDays = nilIfZero(*req.DurationDays)
+	}
+	if req.MaxActivations != nil {
+		t.MaxActivations = nilIfZero(*req.MaxActivations)
+	}
+	if req.GracePeriodDays != nil {
+		t.GracePeriodDays = req.GracePeriodDays
+	}
+	if req.Entitlements != nil {
+		if t.Entitlements, err = normalizeTemplateEntitlements(*req.Entitlements); err != nil {
+			return nil, err
+		}
+	}
+	if req.Metadata != nil {
+		if err := validateTemplateMetadata(req.Metadata); err != nil {
+			return nil, err
+		}
+		t.Metadata = req.Metadata
+	}
+
+	if err := s.templates.Update(ctx, t); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) || errors.Is(err, ierr.ErrDuplicateKey) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error updating license template %s: %w", id, err)
+	}
+
+	s.logger.Info("License template updated", zap.String("id", id.String()))
+	return t, nil
+}
+
+// DeleteTemplate leaves licenses created from the template as they are.
+func (s *LicenseTemplateService) DeleteTemplate(ctx context.Context, id uuid.UUID) error {
+	if err := s.templates.Delete(ctx, id); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("repository error deleting license template %s: %w", id, err)
+	}
+
+	s.logger.Info("License template deleted", zap.String("id", id.String()))
+	return nil
+}
+
+func normalizeTemplateEntitlements(in []licensetemplate.Entitlement) ([]licensetemplate.Entitlement, error) {
+	out := make([]licensetemplate.Entitlement, 0, len(in))
+	seen := make(map[string]bool, len(in))
+	for _, e := range in {
+		if err := entitlement.ValidateName(e.Name); err != nil {
+			return nil, err
+		}
+		if seen[e.Name] {
+			return nil, fmt.Errorf("%w: entitlement %q is listed twice", ierr.ErrValidation, e.Name)
+		}
+		seen[e.Name] = true
+
+		value, err := entitlement.NormalizeValue(e.Type, e.Value)
+		if err != nil {
+			return nil, fmt.Errorf("entitlement %q: %w", e.Name, err)
+		}
+		out = append(out, licensetemplate.Entitlement{Name: e.Name, Type: e.Type, Value: value})
+	}
+	return out, nil
+}
+
+func validateTemplateMetadata(meta []byte) error {
+	if len(meta) == 0 {
+		return nil
+	}
+	if err := jsonlimit.Check(meta, jsonlimit.Default); err != nil {
+		return err
+	}
+	if _, ok := decodeMetadata(meta); !ok {
+		return fmt.Errorf("%w: metadata must be a JSON object", ierr.ErrValidation)
+	}
+	return nil
+}
+
+func nilIfZero(n int) *int {
+	if n == 0 {
+		return nil
+	}
+	return &n
+}
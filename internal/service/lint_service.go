@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/domain/entitlement"
+	"github.com/makkenzo/license-service-api/internal/domain/licensetemplate"
+	"github.com/makkenzo/license-service-api/internal/domain/product"
+	"github.com/makkenzo/license-service-api/internal/domain/usage"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/licensekey"
+	"github.com/makkenzo/license-service-api/internal/metaschema"
+	"go.uber.org/zap"
+)
+
+// lintWebhookTimeout bounds each webhook reachability probe.
+const lintWebhookTimeout = 5 * time.Second
+
+const (
+	lintKindProduct  = "product"
+	lintKindTemplate = "template"
+	lintKindPolicy   = "policy"
+	lintKindWebhook  = "webhook"
+)
+
+// LintWebhook is an outgoing endpoint configured for the service, named
+// after the setting it comes from.
+type LintWebhook struct {
+	Name string
+	URL  string
+}
+
+// LintService checks products, license templates, product policies and
+// webhooks for inconsistencies that their own validation cannot catch,
+// mostly because they reference each other and one side changed later.
+type LintService struct {
+	products  product.Repository
+	templates licensetemplate.Repository
+	webhooks  []LintWebhook
+	http      *http.Client
+	logger    *zap.Logger
+}
+
+func NewLintService(products product.Repository, templates licensetemplate.Repository, webhooks []LintWebhook, logger *zap.Logger) *LintService {
+	return &LintService{
+		products:  products,
+		templates: templates,
+		webhooks:  webhooks,
+		http:      &http.Client{Timeout: lintWebhookTimeout},
+		logger:    logger.Named("LintService"),
+	}
+}
+
+type lintReport struct {
+	*dto.LintReport
+}
+
+func (r lintReport) add(severity dto.LintSeverity, kind, subject, code, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, dto.LintIssue{
+		Severity: severity,
+		Kind:     kind,
+		Subject:  subject,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (s *LintService) Lint(ctx context.Context) (*dto.LintReport, error) {
+	report := lintReport{&dto.LintReport{
+		CheckedAt: time.Now().UTC(),
+		Checked:   map[string]int{},
+		Issues:    []dto.LintIssue{},
+	}}
+
+	products, err := s.products.ListProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing products: %w", err)
+	}
+	byName := make(map[string]*product.Product, len(products))
+	byID := make(map[string]*product.Product, len(products))
+	schemas := make(map[string]*metaschema.Schema, len(products))
+	for _, p := range products {
+		byName[p.Name] = p
+		byID[p.ID.String()] = p
+		if schema := s.lintProduct(report, p); schema != nil {
+			schemas[p.Name] = schema
+		}
+	}
+	report.Checked[lintKindProduct] = len(products)
+
+	lifecycles, err := s.products.ListLifecycles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing product lifecycles: %w", err)
+	}
+	eolDenied := s.lintLifecycles(report, lifecycles, byName)
+
+	policies, err := s.products.ListAgentPolicies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing agent policies: %w", err)
+	}
+	for _, p := range policies {
+		if byName[p.ProductName] == nil {
+			report.add(dto.LintError, lintKindPolicy, p.ProductName, "unknown_product", "agent policy is set for product %s, which does not exist", p.ProductName)
+		}
+		if _, err := product.CompareVersions(p.MinVersion, p.MinVersion); err != nil {
+			report.add(dto.LintError, lintKindPolicy, p.ProductName, "invalid_min_version", "agent policy min_version %q is not a dotted numeric version", p.MinVersion)
+		}
+	}
+
+	// Device and user bindings are policies read from license metadata; a
+	// schema that forbids the keys makes them impossible to set.
+	for _, p := range products {
+		schema := schemas[p.Name]
+		if schema == nil {
+			continue
+		}
+		for _, key := range []string{MetaKeyDeviceID, MetaKeyUserID} {
+			if !schema.Allows(key) {
+				report.add(dto.LintWarning, lintKindPolicy, p.Name, "binding_key_not_in_schema", "metadata schema forbids %q, so licenses of the product cannot be bound by it", key)
+			}
+		}
+	}
+	report.Checked[lintKindPolicy] = len(lifecycles) + len(policies)
+
+	templates, err := s.templates.List(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing license templates: %w", err)
+	}
+	for _, t := range templates {
+		s.lintTemplate(report, t, byID[t.ProductID.String()], schemas, eolDenied)
+	}
+	report.Checked[lintKindTemplate] = len(templates)
+
+	for _, w := range s.webhooks {
+		s.lintWebhook(ctx, report, w)
+	}
+	report.Checked[lintKindWebhook] = len(s.webhooks)
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		a, b := report.Issues[i], report.Issues[j]
+		if a.Severity != b.Severity {
+			return a.Severity == dto.LintError
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Subject < b.Subject
+	})
+	for _, issue := range report.Issues {
+		if issue.Severity == dto.LintError {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+
+	s.logger.Info("Configuration lint finished", zap.Int("errors", report.Errors), zap.Int("warnings", report.Warnings))
+	return report.LintReport, nil
+}
+
+// lintProduct returns the compiled metadata schema of p, if it has a valid
+// one.
+func (s *LintService) lintProduct(report lintReport, p *product.Product) *metaschema.Schema {
+	if _, err := licensekey.Parse(p.KeyFormat, p.KeyPrefix); err != nil {
+		report.add(dto.LintError, lintKindProduct, p.Name, "invalid_key_format", "key format %q with prefix %q cannot generate keys: %v", p.KeyFormat, p.KeyPrefix, err)
+	}
+	if err := product.ValidateCustomerLicenseCaps(p.CustomerLicenseCaps); err != nil {
+		report.add(dto.LintError, lintKindProduct, p.Name, "invalid_customer_license_caps", "%v", err)
+	}
+	if len(p.MetadataSchema) == 0 {
+		return nil
+	}
+	schema, err := metaschema.Compile(p.MetadataSchema)
+	if err != nil {
+		report.add(dto.LintError, lintKindProduct, p.Name, "invalid_metadata_schema", "%v", err)
+		return nil
+	}
+	return schema
+}
+
+// lintLifecycles returns the products whose licenses are denied for being
+// end of life.
+func (s *LintService) lintLifecycles(report lintReport, lifecycles []*product.Lifecycle, products map[string]*product.Product) map[string]bool {
+	states := make(map[string]product.LifecycleState, len(lifecycles))
+	for _, lc := range lifecycles {
+		states[lc.ProductName] = lc.State
+	}
+
+	now := time.Now()
+	eolDenied := make(map[string]bool)
+	for _, lc := range lifecycles {
+		if products[lc.ProductName] == nil {
+			report.add(dto.LintError, lintKindPolicy, lc.ProductName, "unknown_product", "lifecycle is set for product %s, which does not exist", lc.ProductName)
+		}
+		if _, deny := lc.Evaluate(now); deny {
+			eolDenied[lc.ProductName] = true
+		}
+		if lc.MigrationProduct == nil {
+			continue
+		}
+		target := *lc.MigrationProduct
+		switch {
+		case target == lc.ProductName:
+			report.add(dto.LintError, lintKindPolicy, lc.ProductName, "migration_product_self", "lifecycle names the product itself as its migration product")
+		case products[target] == nil:
+			report.add(dto.LintError, lintKindPolicy, lc.ProductName, "unknown_migration_product", "lifecycle names migration product %s, which does not exist", target)
+		case states[target] != "" && states[target] != product.StateActive:
+			report.add(dto.LintWarning, lintKindPolicy, lc.ProductName, "migration_product_retired", "migration product %s is %s itself", target, states[target])
+		}
+	}
+	return eolDenied
+}
+
+func (s *LintService) lintTemplate(report lintReport, t *licensetemplate.Template, prod *product.Product, schemas map[string]*metaschema.Schema, eolDenied map[string]bool) {
+	if prod == nil {
+		report.add(dto.LintError, lintKindTemplate, t.Name, "unknown_product", "template references product %s, which does not exist", t.ProductID)
+		return
+	}
+	if eolDenied[prod.Name] {
+		report.add(dto.LintWarning, lintKindTemplate, t.Name, "product_end_of_life", "product %s is end of life and denies validation, licenses created from the template will not validate", prod.Name)
+	}
+
+	seen := make(map[string]bool, len(t.Entitlements))
+	for _, e := range t.Entitlements {
+		if err := entitlement.ValidateName(e.Name); err != nil {
+			report.add(dto.LintError, lintKindTemplate, t.Name, "invalid_entitlement", "entitlement %q: %v", e.Name, err)
+			continue
+		}
+		if seen[e.Name] {
+			report.add(dto.LintError, lintKindTemplate, t.Name, "duplicate_entitlement", "entitlement %q is listed twice", e.Name)
+		}
+		seen[e.Name] = true
+		if _, err := entitlement.NormalizeValue(e.Type, e.Value); err != nil {
+			report.add(dto.LintError, lintKindTemplate, t.Name, "invalid_entitlement", "entitlement %q: %v", e.Name, err)
+			continue
+		}
+		if metric, ok := strings.CutPrefix(e.Name, usage.LimitPrefix); ok {
+			if err := usage.ValidateMetric(metric); err != nil {
+				report.add(dto.LintWarning, lintKindTemplate, t.Name, "usage_limit_ignored", "entitlement %q does not name a usage metric and is not enforced: %v", e.Name, err)
+			} else if e.Type != entitlement.TypeInteger {
+				report.add(dto.LintWarning, lintKindTemplate, t.Name, "usage_limit_ignored", "usage limit %q is a %s, only integer limits are enforced", e.Name, e.Type)
+			}
+		}
+	}
+
+	if schema := schemas[prod.Name]; schema != nil {
+		if err := schema.Validate(t.Metadata); err != nil {
+			var fieldErrs *ierr.FieldErrors
+			if !errors.As(err, &fieldErrs) {
+				report.add(dto.LintError, lintKindTemplate, t.Name, "metadata_schema_violation", "template metadata: %v", err)
+				return
+			}
+			for _, f := range fieldErrs.Fields {
+				report.add(dto.LintError, lintKindTemplate, t.Name, "metadata_schema_violation", "%s: %s; licenses created from the template are rejected by the schema of product %s", f.Field, f.Message, prod.Name)
+			}
+		}
+	}
+}
+
+// lintWebhook probes w with a HEAD request. Any HTTP answer counts as
+// reachable, as receivers commonly reject methods other than POST.
+func (s *LintService) lintWebhook(ctx context.Context, report lintReport, w LintWebhook) {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		report.add(dto.LintError, lintKindWebhook, w.Name, "invalid_url", "%q is not an http(s) URL", w.URL)
+		return
+	}
+	if u.Scheme == "http" {
+		report.add(dto.LintWarning, lintKindWebhook, w.Name, "insecure_url", "%s is not served over TLS", u.Redacted())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.String(), nil)
+	if err != nil {
+		report.add(dto.LintError, lintKindWebhook, w.Name, "invalid_url", "%v", err)
+		return
+	}
+	resp, err := s.http.Do(req)
+	if err != nil {
+		s.logger.Warn("Webhook unreachable during lint", zap.String("webhook", w.Name), zap.Error(err))
+		report.add(dto.LintError, lintKindWebhook, w.Name, "unreachable", "%s is unreachable: %v", u.Redacted(), err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		report.add(dto.LintWarning, lintKindWebhook, w.Name, "server_error", "%s answered %d", u.Redacted(), resp.StatusCode)
+	}
+}
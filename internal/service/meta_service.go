@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/makkenzo/license-service-api/internal/authz"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/apikey"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/webhook"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"go.uber.org/zap"
+)
+
+// MetaService describes the service to its clients, so that frontends and
+// SDKs read enumerations from the server instead of hardcoding them.
+type MetaService struct {
+	licenseService *LicenseService
+	licenses       license.Repository
+	statuses       *CustomStatusService
+	authz          *authz.Enforcer
+	cfg            *config.ApprovalConfig
+	logger         *zap.Logger
+}
+
+func NewMetaService(licenseService *LicenseService, licenses license.Repository, statuses *CustomStatusService, enforcer *authz.Enforcer, cfg *config.ApprovalConfig, logger *zap.Logger) *MetaService {
+	return &MetaService{
+		licenseService: licenseService,
+		licenses:       licenses,
+		statuses:       statuses,
+		authz:          enforcer,
+		cfg:            cfg,
+		logger:         logger.Named("MetaService"),
+	}
+}
+
+// Enums returns the current enumerations. License types are free-form, so
+// they are the types of existing licenses. Scopes are the API key scopes
+// and roles the OIDC project roles the service checks, from configuration
+// and the authorization policy.
+func (s *MetaService) Enums(ctx context.Context) (*dto.EnumsResponse, error) {
+	resp := &dto.EnumsResponse{}
+
+	for _, status := range license.BuiltinStatuses() {
+		resp.LicenseStatuses = append(resp.LicenseStatuses, dto.LicenseStatusEnum{Name: string(status)})
+	}
+	custom, err := s.statuses.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range custom {
+		resp.LicenseStatuses = append(resp.LicenseStatuses, dto.LicenseStatusEnum{
+			Name:        string(status.Name),
+			Custom:      true,
+			Behavior:    string(status.Behavior),
+			Description: status.Description,
+		})
+	}
+
+	rows, err := s.licenses.Aggregate(ctx, license.AggregateParams{
+		GroupBy: []license.AggregateDimension{{Name: "type"}},
+		Metric:  license.MetricCount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing license types: %w", err)
+	}
+	resp.LicenseTypes = make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.Group[0] != nil {
+			resp.LicenseTypes = append(resp.LicenseTypes, *row.Group[0])
+		}
+	}
+	slices.Sort(resp.LicenseTypes)
+
+	resp.ValidationReasons = s.licenseService.ServerCapabilities().SupportedReasons
+	resp.EventTypes = make([]string, len(webhook.Events))
+	for i, event := range webhook.Events {
+		resp.EventTypes[i] = string(event)
+	}
+
+	resp.Scopes = apikey.AllScopes
+	resp.Roles = []string{}
+	for _, role := range append([]string{s.cfg.ElevatedRole, s.cfg.ExtensionApproverRole}, s.authz.Roles()...) {
+		if role != "" && !slices.Contains(resp.Roles, role) {
+			resp.Roles = append(resp.Roles, role)
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+	"github.com/makkenzo/license-service-api/internal/domain/customer"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/note"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/inbound"
+	"github.com/makkenzo/license-service-api/internal/notify"
+	"go.uber.org/zap"
+)
+
+var ErrInboundEmailDisabled = ierr.New("INBOUND_EMAIL_DISABLED", http.StatusServiceUnavailable, "inbound e-mail is disabled: webhook key or reply address is not configured").
+	WithPublicMessage("Inbound e-mail is not accepted by this server.")
+
+// maxNoteBodyLength keeps a pasted log or a long thread from bloating the
+// notes table; the rest of a reply is cut off.
+const maxNoteBodyLength = 32 << 10
+
+type NoteService struct {
+	notes     note.Repository
+	licenses  license.Repository
+	customers customer.Repository
+	crypto    cryptoprovider.Provider
+	sns       *inbound.SNSVerifier
+	cfg       *config.NotifyConfig
+	logger    *zap.Logger
+}
+
+func NewNoteService(notes note.Repository, licenses license.Repository, customers customer.Repository, crypto cryptoprovider.Provider, cfg *config.NotifyConfig, logger *zap.Logger) *NoteService {
+	return &NoteService{
+		notes:     notes,
+		licenses:  licenses,
+		customers: customers,
+		crypto:    crypto,
+		sns:       inbound.NewSNSVerifier(&http.Client{Timeout: 10 * time.Second}),
+		cfg:       cfg,
+		logger:    logger.Named("NoteService"),
+	}
+}
+
+// IngestMailgun takes a message forwarded by a Mailgun route.
+func (s *NoteService) IngestMailgun(ctx context.Context, form url.Values) error {
+	if s.cfg.MailgunSigningKey == "" || s.cfg.ReplySecret == "" {
+		return ErrInboundEmailDisabled
+	}
+	if err := inbound.VerifyMailgun(s.crypto, s.cfg.MailgunSigningKey, form, time.Now()); err != nil {
+		s.logger.Warn("Rejected Mailgun webhook", zap.Error(err))
+		return ierr.ErrUnauthorized
+	}
+	return s.ingest(ctx, inbound.MailgunEmail(form))
+}
+
+// IngestSNS takes an SNS message of the topic SES publishes received e-mail
+// to. Subscription confirmations for that topic are confirmed right away.
+func (s *NoteService) IngestSNS(ctx context.Context, body []byte) error {
+	if s.cfg.SESTopicARN == "" || s.cfg.ReplySecret == "" {
+		return ErrInboundEmailDisabled
+	}
+
+	var msg inbound.SNSMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return fmt.Errorf("%w: malformed SNS message", ierr.ErrValidation)
+	}
+	if msg.TopicArn != s.cfg.SESTopicARN {
+		s.logger.Warn("Rejected SNS message for unexpected topic", zap.String("topic_arn", msg.TopicArn))
+		return ierr.ErrForbidden
+	}
+	if err := s.sns.Verify(ctx, &msg, time.Now()); err != nil {
+		s.logger.Warn("Rejected SNS message", zap.String("type", msg.Type), zap.Error(err))
+		if errors.Is(err, inbound.ErrBadSignature) {
+			return ierr.ErrUnauthorized
+		}
+		return fmt.Errorf("failed to verify SNS message: %w", err)
+	}
+
+	switch msg.Type {
+	case inbound.SNSTypeSubscriptionConfirmation:
+		if err := s.sns.Confirm(ctx, &msg); err != nil {
+			return err
+		}
+		s.logger.Info("Confirmed SNS subscription", zap.String("topic_arn", msg.TopicArn))
+		return nil
+	case inbound.SNSTypeNotification:
+		email, err := inbound.SESEmail(&msg)
+		if err != nil {
+			s.logger.Warn("Ignoring unreadable SES notification", zap.String("message_id", msg.MessageID), zap.Error(err))
+			return nil
+		}
+		return s.ingest(ctx, email)
+	default:
+		s.logger.Info("Ignoring SNS message", zap.String("type", msg.Type), zap.String("topic_arn", msg.TopicArn))
+		return nil
+	}
+}
+
+// ingest attaches email to the license its reply address was issued for.
+// Mail that cannot be attached is logged and dropped rather than refused,
+// since providers would only keep redelivering it.
+func (s *NoteService) ingest(ctx context.Context, email *inbound.Email) error {
+	licenseID, ok := s.replyLicense(email.Recipients)
+	if !ok {
+		s.logger.Warn("Ignoring inbound e-mail without a valid reply address",
+			zap.Strings("recipients", email.Recipients),
+			zap.String("from", email.From),
+		)
+		return nil
+	}
+	if email.Text == "" {
+		s.logger.Info("Ignoring empty reply", zap.String("license_id", licenseID.String()), zap.String("from", email.From))
+		return nil
+	}
+
+	lic, err := s.licenses.FindByID(ctx, licenseID)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			s.logger.Warn("Ignoring reply to deleted license", zap.String("license_id", licenseID.String()))
+			return nil
+		}
+		return fmt.Errorf("repository error finding license %s: %w", licenseID, err)
+	}
+
+	n := &note.Note{
+		LicenseID:  uuid.NullUUID{UUID: lic.ID, Valid: true},
+		CustomerID: lic.CustomerID,
+		Source:     note.SourceEmail,
+		Author:     truncate(email.From, 255),
+		Subject:    email.Subject,
+		Body:       truncate(email.Text, maxNoteBodyLength),
+	}
+	if email.MessageID != "" {
+		messageID := truncate(email.MessageID, 998)
+		n.ExternalID = &messageID
+	}
+	if err := s.notes.Create(ctx, n); err != nil {
+		if errors.Is(err, ierr.ErrDuplicateKey) {
+			s.logger.Info("Ignoring redelivered e-mail", zap.String("message_id", email.MessageID))
+			return nil
+		}
+		return fmt.Errorf("repository error creating note for license %s: %w", licenseID, err)
+	}
+
+	s.logger.Info("Attached e-mail reply as note",
+		zap.String("note_id", n.ID.String()),
+		zap.String("license_id", licenseID.String()),
+	)
+	return nil
+}
+
+func (s *NoteService) replyLicense(recipients []string) (uuid.UUID, bool) {
+	for _, r := range recipients {
+		licenseID, err := notify.LicenseFromReplyAddress(s.crypto, s.cfg, r)
+		if err == nil {
+			return licenseID, true
+		}
+		if errors.Is(err, notify.ErrReplyTagSignature) {
+			s.logger.Warn("Reply address with bad signature", zap.String("recipient", r))
+		}
+	}
+	return uuid.Nil, false
+}
+
+func (s *NoteService) ListForLicense(ctx context.Context, licenseID uuid.UUID, req *dto.ListNotesRequest) ([]*note.Note, int64, error) {
+	if _, err := s.licenses.FindByID(ctx, licenseID); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, 0, err
+		}
+		return nil, 0, fmt.Errorf("repository error finding license %s: %w", licenseID, err)
+	}
+	return s.list(ctx, note.ListParams{LicenseID: &licenseID}, req)
+}
+
+// ListForCustomer lists the notes on all licenses of the customer.
+func (s *NoteService) ListForCustomer(ctx context.Context, customerID uuid.UUID, req *dto.ListNotesRequest) ([]*note.Note, int64, error) {
+	if _, err := s.customers.FindByID(ctx, customerID); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, 0, err
+		}
+		return nil, 0, fmt.Errorf("repository error finding customer %s: %w", customerID, err)
+	}
+	return s.list(ctx, note.ListParams{CustomerID: &customerID}, req)
+}
+
+func (s *NoteService) list(ctx context.Context, params note.ListParams, req *dto.ListNotesRequest) ([]*note.Note, int64, error) {
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	params.Limit = req.Limit
+	params.Offset = req.Offset
+	notes, total, err := s.notes.List(ctx, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("repository error listing notes: %w", err)
+	}
+	return notes, total, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+	"github.com/makkenzo/license-service-api/internal/domain/entitlement"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/offlineactivation"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/signing"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrOfflineChallengeConsumed = ierr.ErrConflict.Derive("OFFLINE_CHALLENGE_CONSUMED", "offline activation challenge has already been answered")
+	ErrOfflineChallengeExpired  = ierr.ErrConflict.Derive("OFFLINE_CHALLENGE_EXPIRED", "offline activation challenge has expired")
+)
+
+var errOfflineChallengeNotFound = fmt.Errorf("%w: unknown offline activation challenge", ierr.ErrNotFound)
+
+// offlineActivationUserAgent marks activations made through an offline
+// challenge, which have no request of the device to take it from.
+const offlineActivationUserAgent = "offline-activation"
+
+// OfflineActivationService activates machines without network access. The
+// server issues a challenge for the device; the vendor answers it out of
+// band with cmd/offlineactivate, which activates the device and signs a
+// response the agent verifies against the JWKS. Each challenge is answered
+// once.
+type OfflineActivationService struct {
+	challenges   offlineactivation.Repository
+	licenses     license.Repository
+	entitlements entitlement.Repository
+	activations  *ActivationService
+	keyring      *signing.Keyring
+	crypto       cryptoprovider.Provider
+	cfg          *config.OfflineActivationConfig
+	logger       *zap.Logger
+}
+
+func NewOfflineActivationService(challenges offlineactivation.Repository, licenses license.Repository, entitlements entitlement.Repository, activations *ActivationService, keyring *signing.Keyring, crypto cryptoprovider.Provider, cfg *config.OfflineActivationConfig, logger *zap.Logger) *OfflineActivationService {
+	return &OfflineActivationService{
+		challenges:   challenges,
+		licenses:     licenses,
+		entitlements: entitlements,
+		activations:  activations,
+		keyring:      keyring,
+		crypto:       crypto,
+		cfg:          cfg,
+		logger:       logger.Named("OfflineActivationService"),
+	}
+}
+
+// IssueChallenge checks that the device could be activated now and records
+// a challenge for it. Seats are only taken when the challenge is answered.
+func (s *OfflineActivationService) IssueChallenge(ctx context.Context, req *dto.ActivationRequest) (*dto.OfflineChallengeResponse, error) {
+	if s.keyring.Algorithm() == "" {
+		return nil, signing.ErrSigningDisabled
+	}
+	lic, err := s.activations.findLicense(ctx, req.LicenseKey, req.ProductName)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkActivatable(lic); err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, 24)
+	if _, err := io.ReadFull(s.crypto.Rand(), raw); err != nil {
+		return nil, fmt.Errorf("failed to generate offline activation nonce: %w", err)
+	}
+	c := &offlineactivation.Challenge{
+		LicenseID: lic.ID,
+		DeviceID:  req.DeviceID,
+		Nonce:     base64.RawURLEncoding.EncodeToString(raw),
+		ExpiresAt: time.Now().UTC().Add(s.cfg.ChallengeTTL),
+	}
+	if err := s.challenges.Create(ctx, c); err != nil {
+		return nil, fmt.Errorf("repository error creating offline activation challenge: %w", err)
+	}
+
+	s.logger.Info("Issued offline activation challenge",
+		zap.String("challenge_id", c.ID.String()),
+		zap.String("license_id", lic.ID.String()),
+		zap.String("device_id", c.DeviceID),
+	)
+	return &dto.OfflineChallengeResponse{
+		ChallengeID: c.ID,
+		LicenseID:   lic.ID,
+		DeviceID:    c.DeviceID,
+		Code:        signing.OfflineChallenge{ID: c.ID.String(), Nonce: c.Nonce}.Code(),
+		ExpiresAt:   c.ExpiresAt,
+	}, nil
+}
+
+// Respond answers the challenge of code: it activates the device, consumes
+// the challenge and signs the response. Activating is idempotent, so a
+// response lost after the challenge was consumed only needs a new challenge.
+func (s *OfflineActivationService) Respond(ctx context.Context, code string) (*dto.OfflineActivationResponse, error) {
+	challenge, err := signing.ParseOfflineChallenge(code)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ierr.ErrValidation, err)
+	}
+	id, err := idgen.Parse(challenge.ID)
+	if err != nil {
+		return nil, errOfflineChallengeNotFound
+	}
+	c, err := s.challenges.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, errOfflineChallengeNotFound
+		}
+		return nil, fmt.Errorf("repository error finding offline activation challenge: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(c.Nonce), []byte(challenge.Nonce)) != 1 {
+		return nil, errOfflineChallengeNotFound
+	}
+	if c.ConsumedAt.Valid {
+		return nil, ErrOfflineChallengeConsumed
+	}
+	if !c.Usable(time.Now()) {
+		return nil, ErrOfflineChallengeExpired
+	}
+
+	lic, err := s.licenses.FindByID(ctx, c.LicenseID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error finding license %s of offline activation challenge: %w", c.LicenseID, err)
+	}
+	maxActive, err := license.OwnSeats(ctx, s.licenses, lic)
+	if err != nil {
+		return nil, err
+	}
+	a, created, err := s.activations.Activate(ctx, &dto.ActivationRequest{
+		LicenseKey:  lic.LicenseKey,
+		ProductName: lic.ProductName,
+		DeviceID:    c.DeviceID,
+	}, "", offlineActivationUserAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.challenges.Consume(ctx, c.ID); err != nil {
+		if errors.Is(err, ierr.ErrConflict) {
+			return nil, ErrOfflineChallengeConsumed
+		}
+		return nil, fmt.Errorf("repository error consuming offline activation challenge: %w", err)
+	}
+
+	claims := signing.OfflineActivationClaims{
+		Issuer:         s.keyring.Issuer(),
+		Subject:        lic.ID.String(),
+		IssuedAt:       time.Now().UTC().Unix(),
+		ChallengeID:    challenge.ID,
+		Nonce:          c.Nonce,
+		DeviceID:       c.DeviceID,
+		Product:        lic.ProductName,
+		Type:           lic.Type,
+		MaxActivations: maxActive,
+	}
+	if lic.ExpiresAt.Valid {
+		claims.ExpiresAt = lic.ExpiresAt.Time.Unix()
+		claims.GracePeriodDays = lic.GracePeriodDays
+	}
+	if lic.StartsAt.Valid {
+		claims.NotBefore = lic.StartsAt.Time.Unix()
+	}
+	entitlements, err := licenseEntitlements(ctx, s.entitlements, lic)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing entitlements of license %s: %w", lic.ID, err)
+	}
+	if claims.AllowedData, err = allowedData(entitlements); err != nil {
+		return nil, fmt.Errorf("encoding allowed_data of license %s: %w", lic.ID, err)
+	}
+
+	token, kid, err := s.keyring.Sign(signing.OfflineActivationType, claims)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Answered offline activation challenge",
+		zap.String("challenge_id", c.ID.String()),
+		zap.String("license_id", lic.ID.String()),
+		zap.String("device_id", c.DeviceID),
+		zap.String("kid", kid),
+	)
+	return &dto.OfflineActivationResponse{
+		Response:  token,
+		KeyID:     kid,
+		LicenseID: lic.ID,
+		DeviceID:  a.DeviceID,
+		Created:   created,
+	}, nil
+}
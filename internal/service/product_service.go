@@ -0,0 +1,535 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/product"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/licensekey"
+	"github.com/makkenzo/license-service-api/internal/metaschema"
+	"github.com/makkenzo/license-service-api/internal/tasks"
+	"go.uber.org/zap"
+)
+
+type ProductService struct {
+	products product.Repository
+	licenses license.Repository
+	tasks    *asynq.Client
+	logger   *zap.Logger
+}
+
+func NewProductService(products product.Repository, licenses license.Repository, taskClient *asynq.Client, logger *zap.Logger) *ProductService {
+	return &ProductService{
+		products: products,
+		licenses: licenses,
+		tasks:    taskClient,
+		logger:   logger.Named("ProductService"),
+	}
+}
+
+func (s *ProductService) CreateProduct(ctx context.Context, req *dto.CreateProductRequest) (*product.Product, error) {
+	if err := product.ValidateName(req.Name); err != nil {
+		return nil, err
+	}
+	if _, err := licensekey.Parse(req.KeyFormat, req.KeyPrefix); err != nil {
+		return nil, err
+	}
+	if err := product.ValidateCustomerLicenseCaps(req.CustomerLicenseCaps); err != nil {
+		return nil, err
+	}
+	schema, err := metadataSchema(req.MetadataSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &product.Product{
+		Name:                req.Name,
+		DisplayName:         req.DisplayName,
+		Description:         req.Description,
+		KeyFormat:           req.KeyFormat,
+		KeyPrefix:           req.KeyPrefix,
+		CustomerLicenseCaps: req.CustomerLicenseCaps,
+		MetadataSchema:      schema,
+	}
+	if p.DisplayName == "" {
+		p.DisplayName = p.Name
+	}
+	if p.CustomerLicenseCaps == nil {
+		p.CustomerLicenseCaps = map[string]int{}
+	}
+	if err := s.products.CreateProduct(ctx, p); err != nil {
+		if errors.Is(err, ierr.ErrDuplicateKey) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error creating product %s: %w", req.Name, err)
+	}
+
+	s.logger.Info("Product created", zap.String("id", p.ID.String()), zap.String("name", p.Name))
+	return p, nil
+}
+
+func (s *ProductService) ListProducts(ctx context.Context) ([]*product.Product, error) {
+	products, err := s.products.ListProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing products: %w", err)
+	}
+	return products, nil
+}
+
+func (s *ProductService) GetProduct(ctx context.Context, name string) (*product.Product, error) {
+	p, err := s.products.FindProductByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error finding product %s: %w", name, err)
+	}
+	return p, nil
+}
+
+func (s *ProductService) UpdateProduct(ctx context.Context, name string, req *dto.UpdateProductRequest) (*product.Product, error) {
+	p, err := s.GetProduct(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DisplayName != nil {
+		p.DisplayName = *req.DisplayName
+	}
+	if req.Description != nil {
+		p.Description = *req.Description
+	}
+	if req.KeyFormat != nil {
+		p.KeyFormat = *req.KeyFormat
+	}
+	if req.KeyPrefix != nil {
+		p.KeyPrefix = *req.KeyPrefix
+	}
+	if req.CustomerLicenseCaps != nil {
+		if err := product.ValidateCustomerLicenseCaps(req.CustomerLicenseCaps); err != nil {
+			return nil, err
+		}
+		// Lowering a cap does not touch licenses customers already hold.
+		p.CustomerLicenseCaps = req.CustomerLicenseCaps
+	}
+	if req.MetadataSchema != nil {
+		// Licenses are only checked against the new schema when their
+		// metadata next changes.
+		if p.MetadataSchema, err = metadataSchema(req.MetadataSchema); err != nil {
+			return nil, err
+		}
+	}
+	// Existing license keys stay as they are; the format only applies to
+	// licenses created from now on.
+	if _, err := licensekey.Parse(p.KeyFormat, p.KeyPrefix); err != nil {
+		return nil, err
+	}
+	if err := s.products.UpdateProduct(ctx, p); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error updating product %s: %w", name, err)
+	}
+
+	s.logger.Info("Product updated", zap.String("name", name))
+	return p, nil
+}
+
+// metadataSchema checks raw is a usable schema. JSON null means no schema
+// and comes back as nil.
+func metadataSchema(raw json.RawMessage) (json.RawMessage, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	if _, err := metaschema.Compile(raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// DeleteProduct only removes products nothing refers to; the database
+// refuses to delete a product that licenses or API keys still use.
+func (s *ProductService) DeleteProduct(ctx context.Context, name string) error {
+	p, err := s.GetProduct(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := s.products.DeleteProduct(ctx, p.ID); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) || errors.Is(err, ierr.ErrConflict) {
+			return err
+		}
+		return fmt.Errorf("repository error deleting product %s: %w", name, err)
+	}
+
+	s.logger.Info("Product deleted", zap.String("name", name))
+	return nil
+}
+
+func (s *ProductService) ListLifecycles(ctx context.Context) ([]*product.Lifecycle, error) {
+	lifecycles, err := s.products.ListLifecycles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing product lifecycles: %w", err)
+	}
+	return lifecycles, nil
+}
+
+func (s *ProductService) GetLifecycle(ctx context.Context, productName string) (*product.Lifecycle, error) {
+	lc, err := s.products.FindLifecycle(ctx, productName)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error finding lifecycle for product %s: %w", productName, err)
+	}
+	return lc, nil
+}
+
+func (s *ProductService) SetLifecycle(ctx context.Context, productName string, req *dto.SetProductLifecycleRequest) (*product.Lifecycle, error) {
+	s.logger.Info("Setting product lifecycle", zap.String("product_name", productName), zap.String("state", string(req.State)))
+
+	if productName == "" || len(productName) > 255 {
+		return nil, fmt.Errorf("%w: product name must be 1 to 255 characters long", ierr.ErrValidation)
+	}
+
+	behavior := req.EOLBehavior
+	if behavior == "" {
+		behavior = product.EOLWarn
+	}
+	if behavior == product.EOLDeny && req.State == product.StateEOL && req.EOLDate == nil {
+		return nil, fmt.Errorf("%w: eol_date is required when eol_behavior is deny", ierr.ErrValidation)
+	}
+
+	lc := &product.Lifecycle{
+		ProductName:      productName,
+		State:            req.State,
+		EOLDate:          req.EOLDate,
+		EOLBehavior:      behavior,
+		MigrationProduct: req.MigrationProduct,
+		MigrationOffer:   req.MigrationOffer,
+	}
+	if err := s.products.UpsertLifecycle(ctx, lc); err != nil {
+		return nil, fmt.Errorf("repository error saving lifecycle for product %s: %w", productName, err)
+	}
+	return lc, nil
+}
+
+func (s *ProductService) ListAgentPolicies(ctx context.Context) ([]*product.AgentPolicy, error) {
+	policies, err := s.products.ListAgentPolicies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing product agent policies: %w", err)
+	}
+	return policies, nil
+}
+
+func (s *ProductService) GetAgentPolicy(ctx context.Context, productName string) (*product.AgentPolicy, error) {
+	p, err := s.products.FindAgentPolicy(ctx, productName)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error finding agent policy for product %s: %w", productName, err)
+	}
+	return p, nil
+}
+
+func (s *ProductService) SetAgentPolicy(ctx context.Context, productName string, req *dto.SetAgentPolicyRequest) (*product.AgentPolicy, error) {
+	s.logger.Info("Setting product agent policy",
+		zap.String("product_name", productName),
+		zap.String("min_version", req.MinVersion),
+		zap.String("enforcement", string(req.Enforcement)),
+	)
+
+	if productName == "" || len(productName) > 255 {
+		return nil, fmt.Errorf("%w: product name must be 1 to 255 characters long", ierr.ErrValidation)
+	}
+	if _, err := product.CompareVersions(req.MinVersion, req.MinVersion); err != nil {
+		return nil, fmt.Errorf("%w: min_version: %v", ierr.ErrValidation, err)
+	}
+
+	enforcement := req.Enforcement
+	if enforcement == "" {
+		enforcement = product.AgentWarn
+	}
+
+	p := &product.AgentPolicy{
+		ProductName: productName,
+		MinVersion:  req.MinVersion,
+		Enforcement: enforcement,
+	}
+	if err := s.products.UpsertAgentPolicy(ctx, p); err != nil {
+		return nil, fmt.Errorf("repository error saving agent policy for product %s: %w", productName, err)
+	}
+	return p, nil
+}
+
+func (s *ProductService) DeleteAgentPolicy(ctx context.Context, productName string) error {
+	if err := s.products.DeleteAgentPolicy(ctx, productName); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("repository error deleting agent policy for product %s: %w", productName, err)
+	}
+	s.logger.Info("Product agent policy removed", zap.String("product_name", productName))
+	return nil
+}
+
+func (s *ProductService) GetCachePolicy(ctx context.Context, productName string) (*product.CachePolicy, error) {
+	p, err := s.products.FindCachePolicy(ctx, productName)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error finding cache policy for product %s: %w", productName, err)
+	}
+	return p, nil
+}
+
+func (s *ProductService) SetCachePolicy(ctx context.Context, productName string, req *dto.SetCachePolicyRequest) (*product.CachePolicy, error) {
+	s.logger.Info("Setting product cache policy",
+		zap.String("product_name", productName),
+		zap.Int("cache_ttl", req.CacheTTL),
+		zap.Int("revalidate_after", req.RevalidateAfter),
+	)
+
+	if productName == "" || len(productName) > 255 {
+		return nil, fmt.Errorf("%w: product name must be 1 to 255 characters long", ierr.ErrValidation)
+	}
+
+	p := &product.CachePolicy{
+		ProductName:     productName,
+		CacheTTL:        req.CacheTTL,
+		RevalidateAfter: req.RevalidateAfter,
+	}
+	if err := s.products.UpsertCachePolicy(ctx, p); err != nil {
+		return nil, fmt.Errorf("repository error saving cache policy for product %s: %w", productName, err)
+	}
+	return p, nil
+}
+
+func (s *ProductService) DeleteCachePolicy(ctx context.Context, productName string) error {
+	if err := s.products.DeleteCachePolicy(ctx, productName); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("repository error deleting cache policy for product %s: %w", productName, err)
+	}
+	s.logger.Info("Product cache policy removed", zap.String("product_name", productName))
+	return nil
+}
+
+// StartMigrationCampaign enqueues a task that e-mails the customers of a
+// deprecated or EOL product. With DryRun it only reports how many licenses
+// the campaign would cover.
+func (s *ProductService) StartMigrationCampaign(ctx context.Context, productName string, req *dto.StartMigrationCampaignRequest) (*dto.MigrationCampaignResponse, error) {
+	lc, err := s.GetLifecycle(ctx, productName)
+	if err != nil {
+		return nil, err
+	}
+	if lc.State == product.StateActive {
+		return nil, fmt.Errorf("%w: product %s is active, mark it deprecated or eol first", ierr.ErrConflict, productName)
+	}
+
+	_, total, err := s.licenses.List(ctx, license.ListParams{ProductName: &productName, Limit: 1})
+	if err != nil {
+		return nil, fmt.Errorf("repository error counting licenses for product %s: %w", productName, err)
+	}
+
+	resp := &dto.MigrationCampaignResponse{
+		ProductName:      productName,
+		State:            lc.State,
+		AffectedLicenses: total,
+		DryRun:           req.DryRun,
+	}
+	if req.DryRun {
+		return resp, nil
+	}
+
+	task, err := tasks.NewMigrationCampaignTask(tasks.MigrationCampaignPayload{ProductName: productName, Message: req.Message})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration campaign task: %w", err)
+	}
+	info, err := s.tasks.EnqueueContext(ctx, task)
+	if err != nil {
+		if errors.Is(err, asynq.ErrDuplicateTask) {
+			return nil, fmt.Errorf("%w: a migration campaign for product %s is already running", ierr.ErrConflict, productName)
+		}
+		s.logger.Error("Failed to enqueue migration campaign", zap.String("product_name", productName), zap.Error(err))
+		return nil, fmt.Errorf("failed to enqueue migration campaign: %w", err)
+	}
+	resp.TaskID = info.ID
+
+	s.logger.Info("Migration campaign enqueued",
+		zap.String("product_name", productName),
+		zap.String("task_id", info.ID),
+		zap.Int64("affected_licenses", total),
+	)
+	return resp, nil
+}
+
+// defaultMetadataMigrationDiffs is how many per-license diffs a dry run of a
+// metadata migration returns unless the request asks for another number.
+const defaultMetadataMigrationDiffs = 100
+
+// MigrateMetadata transforms the metadata of every license of a product with
+// a declarative mapping. With DryRun it reports what the mapping would change
+// license by license; otherwise a task applies it in the background.
+func (s *ProductService) MigrateMetadata(ctx context.Context, productName string, req *dto.MigrateMetadataRequest) (*dto.MetadataMigrationResponse, error) {
+	prod, err := s.GetProduct(ctx, productName)
+	if err != nil {
+		return nil, err
+	}
+	mapping := make(metaschema.Mapping, len(req.Operations))
+	for i, op := range req.Operations {
+		mapping[i] = metaschema.Op{Op: op.Op, Key: op.Key, To: op.To, Value: op.Value}
+	}
+	if err := mapping.Check(); err != nil {
+		return nil, err
+	}
+
+	if req.DryRun {
+		maxDiffs := req.MaxDiffs
+		if maxDiffs == 0 {
+			maxDiffs = defaultMetadataMigrationDiffs
+		}
+		return s.migrateMetadata(ctx, prod, mapping, false, maxDiffs)
+	}
+
+	task, err := tasks.NewMetadataMigrationTask(tasks.MetadataMigrationPayload{ProductName: productName, Operations: mapping})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metadata migration task: %w", err)
+	}
+	info, err := s.tasks.EnqueueContext(ctx, task)
+	if err != nil {
+		if errors.Is(err, asynq.ErrDuplicateTask) {
+			return nil, fmt.Errorf("%w: the same metadata migration for product %s is already running", ierr.ErrConflict, productName)
+		}
+		s.logger.Error("Failed to enqueue metadata migration", zap.String("product_name", productName), zap.Error(err))
+		return nil, fmt.Errorf("failed to enqueue metadata migration: %w", err)
+	}
+
+	s.logger.Info("Metadata migration enqueued",
+		zap.String("product_name", productName),
+		zap.String("task_id", info.ID),
+		zap.Int("operations", len(mapping)),
+	)
+	return &dto.MetadataMigrationResponse{ProductName: productName, TaskID: info.ID}, nil
+}
+
+// ApplyMetadataMigration runs a metadata migration enqueued by
+// MigrateMetadata.
+func (s *ProductService) ApplyMetadataMigration(ctx context.Context, productName string, mapping metaschema.Mapping) error {
+	prod, err := s.GetProduct(ctx, productName)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			s.logger.Warn("Product disappeared before metadata migration ran", zap.String("product_name", productName))
+			return nil
+		}
+		return err
+	}
+
+	result, err := s.migrateMetadata(ctx, prod, mapping, true, 0)
+	if err != nil {
+		return err
+	}
+	s.logger.Info("Metadata migration finished",
+		zap.String("product_name", productName),
+		zap.Int64("scanned", result.Scanned),
+		zap.Int64("changed", result.Changed),
+		zap.Int64("conflicts", result.Conflicts),
+		zap.Int64("schema_violations", result.SchemaViolations),
+	)
+	return nil
+}
+
+// migrateMetadata runs mapping over the licenses of prod and counts what it
+// changes. Only with apply is the migrated metadata written; licenses whose
+// metadata cannot be migrated are left alone either way. Results that do not
+// match the product's schema are reported but still written, since the
+// schema may be updated after the data it describes.
+func (s *ProductService) migrateMetadata(ctx context.Context, prod *product.Product, mapping metaschema.Mapping, apply bool, maxDiffs int) (*dto.MetadataMigrationResponse, error) {
+	var schema *metaschema.Schema
+	if len(prod.MetadataSchema) > 0 {
+		var err error
+		if schema, err = metaschema.Compile(prod.MetadataSchema); err != nil {
+			return nil, fmt.Errorf("metadata schema of product %s: %w", prod.Name, err)
+		}
+	}
+
+	resp := &dto.MetadataMigrationResponse{ProductName: prod.Name, DryRun: !apply}
+	addDiff := func(diff dto.MetadataMigrationDiff) {
+		if len(resp.Diffs) < maxDiffs {
+			resp.Diffs = append(resp.Diffs, diff)
+		}
+	}
+
+	params := license.ListParams{
+		ProductName: &prod.Name,
+		SortBy:      "id",
+		SortOrder:   "ASC",
+		Limit:       500,
+	}
+	for {
+		licenses, _, err := s.licenses.List(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("repository error listing licenses for product %s: %w", prod.Name, err)
+		}
+
+		for _, lic := range licenses {
+			resp.Scanned++
+			diff := dto.MetadataMigrationDiff{LicenseID: lic.ID, LicenseKey: lic.LicenseKey}
+
+			migrated, changes, err := mapping.Apply(lic.Metadata)
+			if err != nil {
+				resp.Conflicts++
+				diff.Error = err.Error()
+				addDiff(diff)
+				continue
+			}
+			if len(changes) == 0 {
+				continue
+			}
+			diff.Changes = changes
+			if schema != nil {
+				if err := schema.Validate(migrated); err != nil {
+					resp.SchemaViolations++
+					diff.SchemaErrors = schemaFieldErrors(err)
+				}
+			}
+
+			if apply {
+				if err := s.licenses.UpdateMetadata(ctx, lic.ID, migrated); err != nil {
+					if errors.Is(err, ierr.ErrNotFound) {
+						continue
+					}
+					return nil, fmt.Errorf("repository error updating metadata of license %s: %w", lic.ID, err)
+				}
+			}
+			resp.Changed++
+			addDiff(diff)
+		}
+
+		if len(licenses) < params.Limit {
+			break
+		}
+		params.Offset += params.Limit
+	}
+	return resp, nil
+}
+
+func schemaFieldErrors(err error) []dto.FieldError {
+	var fieldErrs *ierr.FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		return []dto.FieldError{{Field: "metadata", Message: err.Error()}}
+	}
+	fields := make([]dto.FieldError, len(fieldErrs.Fields))
+	for i, f := range fieldErrs.Fields {
+		fields[i] = dto.FieldError{Field: f.Field, Message: f.Message}
+	}
+	return fields
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/audit"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+	domainaudit "github.com/makkenzo/license-service-api/internal/domain/audit"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/renewal"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/notify"
+	"github.com/makkenzo/license-service-api/internal/templates"
+	"github.com/makkenzo/license-service-api/internal/util"
+	"go.uber.org/zap"
+)
+
+var ErrRenewalLinksDisabled = ierr.New("RENEWAL_LINKS_DISABLED", http.StatusServiceUnavailable, "renewal links are disabled: renewal signing secret is not configured").
+	WithPublicMessage("Renewal links are not available on this server.")
+
+type RenewalService struct {
+	offers   renewal.Repository
+	licenses license.Repository
+	mailer   notify.Mailer
+	renderer *templates.Renderer
+	crypto   cryptoprovider.Provider
+	cfg      *config.RenewalConfig
+	logger   *zap.Logger
+}
+
+func NewRenewalService(offers renewal.Repository, licenses license.Repository, mailer notify.Mailer, renderer *templates.Renderer, crypto cryptoprovider.Provider, cfg *config.RenewalConfig, logger *zap.Logger) *RenewalService {
+	return &RenewalService{
+		offers:   offers,
+		licenses: licenses,
+		mailer:   mailer,
+		renderer: renderer,
+		crypto:   crypto,
+		cfg:      cfg,
+		logger:   logger.Named("RenewalService"),
+	}
+}
+
+func (s *RenewalService) CreateOffer(ctx context.Context, licenseID uuid.UUID, req *dto.CreateRenewalOfferRequest, createdBy string) (*dto.RenewalOfferResponse, error) {
+	if s.cfg.SigningSecret == "" {
+		return nil, ErrRenewalLinksDisabled
+	}
+
+	lic, err := s.findLicense(ctx, licenseID)
+	if err != nil {
+		return nil, err
+	}
+	if lic.Status == license.StatusRevoked {
+		return nil, fmt.Errorf("%w: revoked licenses cannot be renewed", ierr.ErrConflict)
+	}
+	if lic.IsChild() {
+		return nil, fmt.Errorf("%w: child licenses are renewed with their parent license", ierr.ErrConflict)
+	}
+	if !lic.ExpiresAt.Valid {
+		return nil, fmt.Errorf("%w: license does not expire", ierr.ErrConflict)
+	}
+	if req.SendEmail && !lic.CustomerEmail.Valid {
+		return nil, fmt.Errorf("%w: license has no customer e-mail to send the offer to", ierr.ErrValidation)
+	}
+
+	validFor := s.cfg.LinkTTL
+	if req.ValidForHours > 0 {
+		validFor = time.Duration(req.ValidForHours) * time.Hour
+	}
+
+	offer := &renewal.Offer{
+		LicenseID:     licenseID,
+		ExtendDays:    req.ExtendDays,
+		LinkExpiresAt: time.Now().Add(validFor).Truncate(time.Second),
+		CreatedBy:     createdBy,
+	}
+	if _, err := s.offers.Create(ctx, offer); err != nil {
+		return nil, fmt.Errorf("repository error creating renewal offer for license %s: %w", licenseID, err)
+	}
+
+	link, err := s.OfferURL(offer)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.RenewalOfferResponse{
+		ID:            offer.ID,
+		LicenseID:     licenseID,
+		ExtendDays:    offer.ExtendDays,
+		URL:           link,
+		LinkExpiresAt: offer.LinkExpiresAt,
+	}
+
+	if req.SendEmail {
+		msg, err := s.renewalOfferMessage(lic, offer, link)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.mailer.Send(ctx, *msg); err != nil {
+			return nil, fmt.Errorf("failed to send renewal offer e-mail: %w", err)
+		}
+		resp.EmailSent = true
+	}
+
+	s.logger.Info("Renewal offer created",
+		zap.String("offer_id", offer.ID.String()),
+		zap.String("license_id", licenseID.String()),
+		zap.Int("extend_days", offer.ExtendDays),
+		zap.Bool("email_sent", resp.EmailSent),
+	)
+	return resp, nil
+}
+
+// OfferURL builds the signed self-service link for offer. Notification e-mails
+// use it to embed renewal links.
+func (s *RenewalService) OfferURL(offer *renewal.Offer) (string, error) {
+	if s.cfg.SigningSecret == "" {
+		return "", ErrRenewalLinksDisabled
+	}
+	base, err := url.Parse(s.cfg.BaseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid renewal base url: %w", err)
+	}
+	q := base.Query()
+	q.Set("token", util.SignRenewalToken(s.crypto, []byte(s.cfg.SigningSecret), offer.ID, offer.LinkExpiresAt))
+	base.RawQuery = q.Encode()
+	return base.String(), nil
+}
+
+func (s *RenewalService) GetOffer(ctx context.Context, token string) (*dto.RenewalOfferDetailsResponse, error) {
+	offer, err := s.offerFromToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	lic, err := s.findLicense(ctx, offer.LicenseID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.RenewalOfferDetailsResponse{
+		ProductName:   lic.ProductName,
+		ExtendDays:    offer.ExtendDays,
+		NewExpiresAt:  renewedExpiry(lic, offer, time.Now()),
+		LinkExpiresAt: offer.LinkExpiresAt,
+		ConsentText:   renewal.ConsentText,
+		Accepted:      offer.Accepted(),
+	}
+	if lic.CustomerName.Valid {
+		resp.CustomerName = &lic.CustomerName.String
+	}
+	if lic.ExpiresAt.Valid {
+		resp.CurrentExpiresAt = &lic.ExpiresAt.Time
+	}
+	if offer.Accepted() {
+		resp.AcceptedAt = &offer.Consent.AcceptedAt
+	}
+	return resp, nil
+}
+
+// AcceptOffer records the customer's consent and extends the license. The
+// consent is written first so that a link clicked twice renews only once.
+func (s *RenewalService) AcceptOffer(ctx context.Context, req *dto.AcceptRenewalOfferRequest, clientIP, userAgent string) (*dto.AcceptRenewalOfferResponse, error) {
+	if !req.Consent {
+		return nil, fmt.Errorf("%w: consent is required to accept the renewal offer", ierr.ErrValidation)
+	}
+
+	offer, err := s.offerFromToken(ctx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+	if offer.Accepted() {
+		return nil, fmt.Errorf("%w: renewal offer already accepted", ierr.ErrConflict)
+	}
+
+	lic, err := s.findLicense(ctx, offer.LicenseID)
+	if err != nil {
+		return nil, err
+	}
+	if lic.Status == license.StatusRevoked {
+		return nil, fmt.Errorf("%w: license has been revoked", ierr.ErrConflict)
+	}
+	if !lic.ExpiresAt.Valid {
+		return nil, fmt.Errorf("%w: license does not expire", ierr.ErrConflict)
+	}
+
+	now := time.Now()
+	consent := &renewal.Consent{
+		AcceptedAt: now,
+		IP:         clientIP,
+		UserAgent:  userAgent,
+		Text:       renewal.ConsentText,
+	}
+	if err := s.offers.Accept(ctx, offer.ID, consent); err != nil {
+		if errors.Is(err, ierr.ErrConflict) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error accepting renewal offer %s: %w", offer.ID, err)
+	}
+
+	newExpiry := renewedExpiry(lic, offer, now)
+	lic.ExpiresAt.Time, lic.ExpiresAt.Valid = newExpiry, true
+	if lic.Status == license.StatusExpired {
+		lic.Status = license.StatusActive
+	}
+
+	updateCtx := audit.WithActor(ctx, domainaudit.Actor{
+		Type:      domainaudit.ActorCustomer,
+		ID:        "renewal_offer:" + offer.ID.String(),
+		IP:        clientIP,
+		UserAgent: userAgent,
+	})
+	if err := s.licenses.Update(updateCtx, lic); err != nil {
+		s.logger.Error("Failed to extend license after renewal consent, reverting consent",
+			zap.String("offer_id", offer.ID.String()),
+			zap.String("license_id", lic.ID.String()),
+			zap.Error(err),
+		)
+		if revertErr := s.offers.RevokeAcceptance(ctx, offer.ID); revertErr != nil {
+			s.logger.Error("Failed to revert renewal consent", zap.String("offer_id", offer.ID.String()), zap.Error(revertErr))
+		}
+		return nil, fmt.Errorf("repository error extending license %s: %w", lic.ID, err)
+	}
+
+	if _, err := syncChildren(updateCtx, s.licenses, lic); err != nil {
+		// The renewal itself stands; failing it now would take back the
+		// consent the customer just gave.
+		s.logger.Error("License renewed but its child licenses were not", zap.String("license_id", lic.ID.String()), zap.Error(err))
+	}
+
+	s.logger.Info("Renewal offer accepted",
+		zap.String("offer_id", offer.ID.String()),
+		zap.String("license_id", lic.ID.String()),
+		zap.Time("new_expires_at", newExpiry),
+	)
+
+	return &dto.AcceptRenewalOfferResponse{
+		ProductName:  lic.ProductName,
+		NewExpiresAt: newExpiry,
+		AcceptedAt:   now,
+	}, nil
+}
+
+func (s *RenewalService) offerFromToken(ctx context.Context, token string) (*renewal.Offer, error) {
+	if s.cfg.SigningSecret == "" {
+		return nil, ErrRenewalLinksDisabled
+	}
+
+	offerID, _, err := util.VerifyRenewalToken(s.crypto, []byte(s.cfg.SigningSecret), token, time.Now())
+	if err != nil {
+		s.logger.Warn("Rejected renewal token", zap.Error(err))
+		if errors.Is(err, util.ErrRenewalTokenExpired) {
+			return nil, fmt.Errorf("%w: renewal link has expired", ierr.ErrValidation)
+		}
+		return nil, fmt.Errorf("%w: invalid renewal link", ierr.ErrValidation)
+	}
+
+	offer, err := s.offers.FindByID(ctx, offerID)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error finding renewal offer %s: %w", offerID, err)
+	}
+	return offer, nil
+}
+
+func (s *RenewalService) findLicense(ctx context.Context, id uuid.UUID) (*license.License, error) {
+	lic, err := s.licenses.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error finding license %s: %w", id, err)
+	}
+	return lic, nil
+}
+
+// renewedExpiry extends from the current expiry, or from now when the license
+// has already lapsed.
+func renewedExpiry(lic *license.License, offer *renewal.Offer, now time.Time) time.Time {
+	from := now
+	if lic.ExpiresAt.Valid && lic.ExpiresAt.Time.After(now) {
+		from = lic.ExpiresAt.Time
+	}
+	return from.AddDate(0, 0, offer.ExtendDays)
+}
+
+func (s *RenewalService) renewalOfferMessage(lic *license.License, offer *renewal.Offer, link string) (*notify.Message, error) {
+	rendered, err := s.renderer.Render(templates.RenewalOffer, lic.Locale(), templates.RenewalOfferData{
+		CustomerName:  lic.CustomerName.String,
+		ProductName:   lic.ProductName,
+		ExtendDays:    offer.ExtendDays,
+		URL:           link,
+		LinkExpiresAt: offer.LinkExpiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render renewal offer e-mail: %w", err)
+	}
+	return &notify.Message{
+		To:        lic.CustomerEmail.String,
+		Subject:   rendered.Subject,
+		Body:      rendered.Body,
+		LicenseID: lic.ID,
+	}, nil
+}
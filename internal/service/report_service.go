@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/domain/bindingfailure"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type ReportService struct {
+	bindingFailures bindingfailure.Repository
+	logger          *zap.Logger
+}
+
+func NewReportService(bindingFailures bindingfailure.Repository, logger *zap.Logger) *ReportService {
+	return &ReportService{
+		bindingFailures: bindingFailures,
+		logger:          logger.Named("ReportService"),
+	}
+}
+
+// BindingFailures returns the top binding failure offenders for an ISO week,
+// defaulting to the current week.
+func (s *ReportService) BindingFailures(ctx context.Context, req *dto.BindingFailureReportRequest) (*dto.BindingFailureReportResponse, error) {
+	week := bindingfailure.WeekKey(time.Now())
+	if req.Week != "" {
+		parsed, err := bindingfailure.ParseWeekKey(req.Week)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ierr.ErrValidation, err)
+		}
+		week = parsed
+	}
+
+	offenders, err := s.bindingFailures.Top(ctx, week, req.Limit)
+	if err != nil {
+		s.logger.Error("Failed to load binding failure report", zap.String("week", week), zap.Error(err))
+		return nil, fmt.Errorf("repository error loading binding failures: %w", err)
+	}
+
+	resp := &dto.BindingFailureReportResponse{
+		Week:      week,
+		Offenders: make([]dto.BindingFailureOffender, len(offenders)),
+	}
+	for i, o := range offenders {
+		resp.Offenders[i] = dto.BindingFailureOffender{
+			Endpoint: o.Endpoint,
+			Field:    o.Field,
+			Client:   o.Client,
+			Count:    o.Count,
+		}
+	}
+	return resp, nil
+}
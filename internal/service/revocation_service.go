@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/statushistory"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+// RevocationService revokes licenses with a recorded reason and publishes
+// the revocation list that offline agents sync. revoked_at is set by the
+// database whenever a license becomes revoked, so licenses revoked through
+// the status endpoint or bulk revoke are listed too, only without a reason.
+type RevocationService struct {
+	repo    license.Repository
+	history statushistory.Repository
+	logger  *zap.Logger
+}
+
+func NewRevocationService(repo license.Repository, history statushistory.Repository, logger *zap.Logger) *RevocationService {
+	return &RevocationService{
+		repo:    repo,
+		history: history,
+		logger:  logger.Named("RevocationService"),
+	}
+}
+
+func (s *RevocationService) Revoke(ctx context.Context, id uuid.UUID, reason, changedBy string) (*statushistory.Entry, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: reason is required to revoke a license", ierr.ErrValidation)
+	}
+
+	lic, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error fetching license %s: %w", id, err)
+	}
+	if !lic.Status.CanTransitionTo(license.StatusRevoked) {
+		return nil, fmt.Errorf("%w: license is %s and cannot be revoked", license.ErrInvalidStatusTransition, lic.Status)
+	}
+
+	if err := s.repo.UpdateStatus(ctx, id, license.StatusRevoked); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) || errors.Is(err, ierr.ErrUpdateFailed) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error revoking license %s: %w", id, err)
+	}
+
+	entry := &statushistory.Entry{
+		LicenseID:  id,
+		FromStatus: lic.Status,
+		ToStatus:   license.StatusRevoked,
+		Reason:     reason,
+		ChangedBy:  changedBy,
+	}
+	if err := s.history.Record(ctx, entry); err != nil {
+		// The license is revoked and listed; only the reason is lost.
+		s.logger.Error("License revoked but its history entry was not recorded", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("failed to record revocation of license %s: %w", id, err)
+	}
+
+	s.logger.Info("License revoked by operator",
+		zap.String("id", id.String()),
+		zap.String("from_status", string(lic.Status)),
+		zap.String("changed_by", changedBy),
+	)
+
+	children, err := revokeChildren(ctx, s.repo, id)
+	for _, child := range children {
+		childEntry := &statushistory.Entry{
+			LicenseID:  child.ID,
+			FromStatus: child.Status,
+			ToStatus:   license.StatusRevoked,
+			Reason:     fmt.Sprintf("parent license %s revoked: %s", id, reason),
+			ChangedBy:  changedBy,
+		}
+		if recordErr := s.history.Record(ctx, childEntry); recordErr != nil {
+			s.logger.Warn("Child license revoked but its history entry was not recorded", zap.String("id", child.ID.String()), zap.Error(recordErr))
+		}
+	}
+	if err != nil {
+		s.logger.Error("License revoked but not all of its child licenses", zap.String("id", id.String()), zap.Int("children_revoked", len(children)), zap.Error(err))
+		return nil, fmt.Errorf("license %s was revoked, but revoking its child licenses failed: %w", id, err)
+	}
+	return entry, nil
+}
+
+// ListRevocations returns the product's revoked licenses, oldest revocation
+// first. Since is inclusive, so an agent resuming from the last revoked_at
+// it saw gets that entry again rather than missing a revocation made in the
+// same instant.
+func (s *RevocationService) ListRevocations(ctx context.Context, req *dto.ListRevocationsRequest) (*dto.RevocationListResponse, error) {
+	if req.Limit <= 0 {
+		req.Limit = 1000
+	}
+	status := license.StatusRevoked
+	licenses, total, err := s.repo.List(ctx, license.ListParams{
+		Status:       &status,
+		ProductName:  &req.ProductName,
+		RevokedSince: req.Since,
+		Limit:        req.Limit,
+		Offset:       req.Offset,
+		Sort:         []license.SortField{{Column: "revoked_at", Order: "ASC"}, {Column: "id", Order: "ASC"}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing revoked licenses: %w", err)
+	}
+
+	ids := make([]uuid.UUID, len(licenses))
+	for i, lic := range licenses {
+		ids[i] = lic.ID
+	}
+	entries, err := s.history.LatestFor(ctx, ids, license.StatusRevoked)
+	if err != nil {
+		return nil, fmt.Errorf("repository error looking up revocation reasons: %w", err)
+	}
+
+	resp := &dto.RevocationListResponse{
+		Revocations: make([]dto.Revocation, 0, len(licenses)),
+		TotalCount:  total,
+		Limit:       req.Limit,
+		Offset:      req.Offset,
+	}
+	for _, lic := range licenses {
+		rev := dto.Revocation{
+			LicenseID:  lic.ID,
+			LicenseKey: lic.LicenseKey,
+			RevokedAt:  lic.RevokedAt.Time,
+		}
+		if entry, ok := entries[lic.ID]; ok {
+			rev.Reason = entry.Reason
+		}
+		resp.Revocations = append(resp.Revocations, rev)
+	}
+	return resp, nil
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/slo"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+// SLOEndpoint is the endpoint whose SLOs are tracked.
+const SLOEndpoint = "POST /api/v1/licenses/validate"
+
+var ErrSLODisabled = ierr.New("SLO_DISABLED", http.StatusServiceUnavailable, "slo tracking is disabled").
+	WithPublicMessage("SLO tracking is not enabled on this server.")
+
+// sloAlert is a multiwindow burn rate alert that fires when the long
+// window has spent budget of the error budget, and the short window shows
+// it is still being spent.
+type sloAlert struct {
+	severity    string
+	long, short time.Duration
+	budget      float64
+}
+
+var sloAlerts = []sloAlert{
+	{severity: "page", long: time.Hour, short: 5 * time.Minute, budget: 0.02},
+	{severity: "page", long: 6 * time.Hour, short: 30 * time.Minute, budget: 0.05},
+	{severity: "ticket", long: 3 * 24 * time.Hour, short: 6 * time.Hour, budget: 0.10},
+}
+
+// sloMinuteWindows is the longest window counted in minute buckets; longer
+// windows are counted in whole hours.
+const sloMinuteWindows = 6 * time.Hour
+
+type SLOService struct {
+	repo   slo.Repository
+	cfg    *config.SLOConfig
+	logger *zap.Logger
+}
+
+func NewSLOService(repo slo.Repository, cfg *config.SLOConfig, logger *zap.Logger) *SLOService {
+	return &SLOService{
+		repo:   repo,
+		cfg:    cfg,
+		logger: logger.Named("SLOService"),
+	}
+}
+
+// Report returns the availability and latency SLOs of SLOEndpoint with
+// their burn rates over the alert windows and the whole SLO window.
+func (s *SLOService) Report(ctx context.Context) (*dto.SLOReportResponse, error) {
+	if !s.cfg.Enabled {
+		return nil, ErrSLODisabled
+	}
+	now := time.Now().UTC()
+
+	var windows []time.Duration
+	for _, a := range sloAlerts {
+		windows = appendWindow(windows, a.short)
+		windows = appendWindow(windows, a.long)
+	}
+	windows = appendWindow(windows, s.cfg.Window)
+
+	counts := make(map[time.Duration]slo.Counts, len(windows))
+	for _, w := range windows {
+		resolution := slo.Minute
+		if w > sloMinuteWindows {
+			resolution = slo.Hour
+		}
+		c, err := s.repo.Sum(ctx, resolution, now.Add(-w), now.Add(resolution))
+		if err != nil {
+			return nil, fmt.Errorf("reading slo counts for %s: %w", formatWindow(w), err)
+		}
+		counts[w] = c
+	}
+
+	availability := func(c slo.Counts) (int64, int64) { return c.Requests, c.Errors }
+	latency := func(c slo.Counts) (int64, int64) { return c.Requests - c.Errors, c.Slow }
+	resp := &dto.SLOReportResponse{
+		Endpoint:    SLOEndpoint,
+		Window:      formatWindow(s.cfg.Window),
+		GeneratedAt: now,
+		Objectives: []dto.SLOObjective{
+			s.objective("availability", s.cfg.AvailabilityObjective, windows, counts, availability),
+			s.objective("latency", s.cfg.LatencyObjective, windows, counts, latency),
+		},
+	}
+	resp.Objectives[1].LatencyThresholdMs = s.cfg.LatencyThreshold.Milliseconds()
+	return resp, nil
+}
+
+// objective builds one SLO; split picks the requests the SLO applies to and
+// the bad ones among them.
+func (s *SLOService) objective(name string, target float64, windows []time.Duration, counts map[time.Duration]slo.Counts, split func(slo.Counts) (int64, int64)) dto.SLOObjective {
+	burn := func(w time.Duration) float64 {
+		requests, bad := split(counts[w])
+		if requests == 0 || target >= 1 {
+			return 0
+		}
+		return float64(bad) / float64(requests) / (1 - target)
+	}
+
+	requests, bad := split(counts[s.cfg.Window])
+	o := dto.SLOObjective{
+		Name:                 name,
+		Objective:            target,
+		Requests:             requests,
+		Bad:                  bad,
+		ErrorBudgetRemaining: roundRate(1 - burn(s.cfg.Window)),
+	}
+	if requests > 0 {
+		sli := roundRate(1 - float64(bad)/float64(requests))
+		o.SLI = &sli
+	}
+	for _, w := range windows {
+		requests, bad := split(counts[w])
+		o.BurnRates = append(o.BurnRates, dto.SLOBurnRate{
+			Window:   formatWindow(w),
+			Requests: requests,
+			Bad:      bad,
+			BurnRate: roundRate(burn(w)),
+		})
+	}
+	for _, a := range sloAlerts {
+		// The budget share is scaled to the SLO window, so a 30 day window
+		// gives the usual thresholds of 14.4, 6 and 1.
+		threshold := a.budget * float64(s.cfg.Window) / float64(a.long)
+		o.Alerts = append(o.Alerts, dto.SLOAlert{
+			Severity:    a.severity,
+			LongWindow:  formatWindow(a.long),
+			ShortWindow: formatWindow(a.short),
+			Threshold:   roundRate(threshold),
+			Firing:      burn(a.long) > threshold && burn(a.short) > threshold,
+		})
+	}
+	return o
+}
+
+// appendWindow inserts w into the sorted windows unless it is there.
+func appendWindow(windows []time.Duration, w time.Duration) []time.Duration {
+	for i, cur := range windows {
+		if cur == w {
+			return windows
+		}
+		if cur > w {
+			return append(windows[:i], append([]time.Duration{w}, windows[i:]...)...)
+		}
+	}
+	return append(windows, w)
+}
+
+func formatWindow(w time.Duration) string {
+	switch {
+	case w%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", w/(24*time.Hour))
+	case w%time.Hour == 0:
+		return fmt.Sprintf("%dh", w/time.Hour)
+	case w%time.Minute == 0:
+		return fmt.Sprintf("%dm", w/time.Minute)
+	}
+	return w.String()
+}
+
+func roundRate(f float64) float64 {
+	return math.Round(f*1e6) / 1e6
+}
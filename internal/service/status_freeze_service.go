@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/statusguard"
+	"go.uber.org/zap"
+)
+
+type StatusFreezeService struct {
+	repo   license.Repository
+	guard  *statusguard.Guard
+	logger *zap.Logger
+}
+
+func NewStatusFreezeService(repo license.Repository, guard *statusguard.Guard, logger *zap.Logger) *StatusFreezeService {
+	return &StatusFreezeService{
+		repo:   repo,
+		guard:  guard,
+		logger: logger.Named("StatusFreezeService"),
+	}
+}
+
+func (s *StatusFreezeService) GetFreeze(ctx context.Context, id uuid.UUID) (*dto.StatusFreezeResponse, error) {
+	if err := s.ensureLicense(ctx, id); err != nil {
+		return nil, err
+	}
+
+	state, err := s.guard.State(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to read status freeze", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("failed to read status freeze for license %s: %w", id, err)
+	}
+
+	return &dto.StatusFreezeResponse{
+		Frozen:      state.Frozen,
+		FrozenUntil: state.Until,
+		RecentFlips: state.RecentFlips,
+	}, nil
+}
+
+func (s *StatusFreezeService) Unfreeze(ctx context.Context, id uuid.UUID) error {
+	s.logger.Info("Attempting to lift status freeze", zap.String("id", id.String()))
+
+	if err := s.ensureLicense(ctx, id); err != nil {
+		return err
+	}
+	if err := s.guard.Unfreeze(ctx, id); err != nil {
+		s.logger.Error("Failed to lift status freeze", zap.String("id", id.String()), zap.Error(err))
+		return fmt.Errorf("failed to lift status freeze for license %s: %w", id, err)
+	}
+	return nil
+}
+
+func (s *StatusFreezeService) ensureLicense(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("repository error finding license %s: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+	"github.com/makkenzo/license-service-api/internal/domain/stepup"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/totp"
+	"go.uber.org/zap"
+)
+
+// maxStepUpFailures failed challenges lock a user out of step-up until the
+// max age has passed without another failure.
+const maxStepUpFailures = 5
+
+var ErrStepUpRequired = ierr.ErrUnauthorized.Derive("STEP_UP_REQUIRED", "recent authentication required").
+	WithPublicMessage("This action requires a recent sign-in or a step-up challenge at /api/v1/auth/step-up.")
+
+var ErrStepUpFailed = ierr.ErrForbidden.Derive("STEP_UP_FAILED", "step-up challenge failed").
+	WithPublicMessage("The step-up code is invalid or was already used.")
+
+var ErrStepUpLocked = ierr.New("STEP_UP_LOCKED", http.StatusTooManyRequests, "too many failed step-up challenges, try again later")
+
+// StepUpFactor is a second factor users can prove to step up. Verify
+// returns ErrStepUpFailed for a wrong code.
+type StepUpFactor interface {
+	Name() string
+	Verify(ctx context.Context, subject, code string) error
+}
+
+// StepUpService decides whether a user authenticated recently enough for a
+// destructive action: either the token's auth_time or a passed challenge of
+// one of the factors must lie within the max age.
+type StepUpService struct {
+	factors map[string]StepUpFactor
+	totp    *TOTPFactor
+	grants  stepup.GrantRepository
+	maxAge  time.Duration
+	logger  *zap.Logger
+}
+
+func NewStepUpService(cfg *config.StepUpConfig, grants stepup.GrantRepository, totpFactor *TOTPFactor, logger *zap.Logger) *StepUpService {
+	s := &StepUpService{
+		factors: make(map[string]StepUpFactor),
+		totp:    totpFactor,
+		grants:  grants,
+		maxAge:  cfg.MaxAge,
+		logger:  logger.Named("StepUpService"),
+	}
+	s.AddFactor(totpFactor)
+	return s
+}
+
+// AddFactor offers another second factor under its name.
+func (s *StepUpService) AddFactor(f StepUpFactor) {
+	s.factors[f.Name()] = f
+}
+
+func (s *StepUpService) MaxAge() time.Duration { return s.maxAge }
+
+// Fresh reports whether the user signed in or passed a challenge within
+// the max age.
+func (s *StepUpService) Fresh(ctx context.Context, claims *ZitadelClaims) (bool, error) {
+	now := time.Now()
+	if claims.AuthTime > 0 && now.Sub(time.Unix(claims.AuthTime, 0)) <= s.maxAge {
+		return true, nil
+	}
+	at, err := s.grants.Find(ctx, claims.Subject)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("checking step-up of %s: %w", claims.Subject, err)
+	}
+	return now.Sub(at) <= s.maxAge, nil
+}
+
+func (s *StepUpService) StepUp(ctx context.Context, subject string, req *dto.StepUpRequest) (*dto.StepUpResponse, error) {
+	factor, ok := s.factors[req.Factor]
+	if !ok {
+		names := make([]string, 0, len(s.factors))
+		for name := range s.factors {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return nil, fmt.Errorf("%w: unknown step-up factor %q, expected one of %s", ierr.ErrValidation, req.Factor, strings.Join(names, ", "))
+	}
+
+	failures, err := s.grants.Failures(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	if failures >= maxStepUpFailures {
+		s.logger.Warn("Step-up refused after too many failures", zap.String("subject", subject))
+		return nil, ErrStepUpLocked
+	}
+
+	if err := factor.Verify(ctx, subject, req.Code); err != nil {
+		if errors.Is(err, ErrStepUpFailed) {
+			s.logger.Warn("Step-up challenge failed", zap.String("subject", subject), zap.String("factor", req.Factor))
+			if err := s.grants.AddFailure(ctx, subject, s.maxAge); err != nil {
+				s.logger.Error("Failed to count step-up failure", zap.String("subject", subject), zap.Error(err))
+			}
+		}
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if err := s.grants.Save(ctx, subject, now, s.maxAge); err != nil {
+		return nil, err
+	}
+	s.logger.Info("User stepped up", zap.String("subject", subject), zap.String("factor", req.Factor))
+	return &dto.StepUpResponse{Factor: req.Factor, VerifiedAt: now, ExpiresAt: now.Add(s.maxAge)}, nil
+}
+
+// EnrollTOTP gives the user a new TOTP secret. Replacing an enrolled
+// secret requires a fresh authentication, so a stolen token cannot swap it.
+func (s *StepUpService) EnrollTOTP(ctx context.Context, claims *ZitadelClaims) (*dto.TOTPEnrollmentResponse, error) {
+	enrolled, err := s.totp.Enrolled(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if enrolled {
+		fresh, err := s.Fresh(ctx, claims)
+		if err != nil {
+			return nil, err
+		}
+		if !fresh {
+			return nil, ErrStepUpRequired
+		}
+	}
+
+	account := claims.Email
+	if account == "" {
+		account = claims.Subject
+	}
+	return s.totp.Enroll(ctx, claims.Subject, account)
+}
+
+// TOTPFactor verifies codes of authenticator apps.
+type TOTPFactor struct {
+	secrets stepup.TOTPRepository
+	crypto  cryptoprovider.Provider
+	issuer  string
+	logger  *zap.Logger
+}
+
+func NewTOTPFactor(secrets stepup.TOTPRepository, crypto cryptoprovider.Provider, issuer string, logger *zap.Logger) *TOTPFactor {
+	return &TOTPFactor{
+		secrets: secrets,
+		crypto:  crypto,
+		issuer:  issuer,
+		logger:  logger.Named("TOTPFactor"),
+	}
+}
+
+var _ StepUpFactor = (*TOTPFactor)(nil)
+
+func (f *TOTPFactor) Name() string { return "totp" }
+
+func (f *TOTPFactor) Verify(ctx context.Context, subject, code string) error {
+	secret, err := f.secrets.Find(ctx, subject)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return fmt.Errorf("%w: no TOTP secret is enrolled, enroll one at /api/v1/auth/step-up/totp", ierr.ErrValidation)
+		}
+		return err
+	}
+	counter, ok := totp.Verify(secret.Secret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return ErrStepUpFailed
+	}
+	if err := f.secrets.Use(ctx, subject, int64(counter)); err != nil {
+		if errors.Is(err, ierr.ErrConflict) {
+			return ErrStepUpFailed
+		}
+		return err
+	}
+	return nil
+}
+
+func (f *TOTPFactor) Enrolled(ctx context.Context, subject string) (bool, error) {
+	_, err := f.secrets.Find(ctx, subject)
+	if errors.Is(err, ierr.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (f *TOTPFactor) Enroll(ctx context.Context, subject, account string) (*dto.TOTPEnrollmentResponse, error) {
+	secret, err := totp.NewSecret(f.crypto.Rand())
+	if err != nil {
+		return nil, err
+	}
+	if err := f.secrets.Put(ctx, &stepup.TOTPSecret{Subject: subject, Secret: secret}); err != nil {
+		return nil, err
+	}
+	f.logger.Info("TOTP secret enrolled", zap.String("subject", subject))
+	return &dto.TOTPEnrollmentResponse{
+		Secret: totp.EncodeSecret(secret),
+		URI:    totp.URI(f.issuer, account, secret),
+	}, nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/statushistory"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+// SuspensionService temporarily takes active licenses out of service, e.g.
+// while a payment dispute is open, and puts them back. Unlike revoking, a
+// suspension is meant to be lifted; the reason is shown to agents.
+type SuspensionService struct {
+	repo    license.Repository
+	history statushistory.Repository
+	logger  *zap.Logger
+}
+
+func NewSuspensionService(repo license.Repository, history statushistory.Repository, logger *zap.Logger) *SuspensionService {
+	return &SuspensionService{
+		repo:    repo,
+		history: history,
+		logger:  logger.Named("SuspensionService"),
+	}
+}
+
+func (s *SuspensionService) Suspend(ctx context.Context, id uuid.UUID, reason, changedBy string) (*statushistory.Entry, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: reason is required to suspend a license", ierr.ErrValidation)
+	}
+	return s.transition(ctx, id, license.StatusActive, license.StatusSuspended, "suspended", reason, changedBy)
+}
+
+func (s *SuspensionService) Reinstate(ctx context.Context, id uuid.UUID, reason, changedBy string) (*statushistory.Entry, error) {
+	return s.transition(ctx, id, license.StatusSuspended, license.StatusActive, "reinstated", strings.TrimSpace(reason), changedBy)
+}
+
+func (s *SuspensionService) transition(ctx context.Context, id uuid.UUID, from, to license.LicenseStatus, verb, reason, changedBy string) (*statushistory.Entry, error) {
+	lic, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error fetching license %s: %w", id, err)
+	}
+	if lic.Status != from {
+		return nil, fmt.Errorf("%w: license is %s, only %s licenses can be %s", ierr.ErrConflict, lic.Status, from, verb)
+	}
+
+	if err := s.repo.UpdateStatus(ctx, id, to); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) || errors.Is(err, ierr.ErrUpdateFailed) || errors.Is(err, license.ErrInvalidStatusTransition) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error updating status for license %s: %w", id, err)
+	}
+
+	entry := &statushistory.Entry{
+		LicenseID:  id,
+		FromStatus: from,
+		ToStatus:   to,
+		Reason:     reason,
+		ChangedBy:  changedBy,
+	}
+	if err := s.history.Record(ctx, entry); err != nil {
+		// The status has already changed; the audit log still has the
+		// transition, only the reason is lost.
+		s.logger.Error("License status changed but its history entry was not recorded",
+			zap.String("id", id.String()),
+			zap.String("to_status", string(to)),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("failed to record status change of license %s: %w", id, err)
+	}
+
+	s.logger.Info("License status changed by operator",
+		zap.String("id", id.String()),
+		zap.String("from_status", string(from)),
+		zap.String("to_status", string(to)),
+		zap.String("changed_by", changedBy),
+	)
+	return entry, nil
+}
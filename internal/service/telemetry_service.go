@@ -0,0 +1,114 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/buildinfo"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+var ErrTelemetryDisabled = ierr.New("TELEMETRY_DISABLED", http.StatusServiceUnavailable, "telemetry is disabled or has no endpoint").
+	WithPublicMessage("Telemetry is disabled on this server.")
+
+// TelemetryService builds and sends the opt-in usage ping. Preview and Send
+// share BuildReport so the preview shows byte for byte what would be sent.
+type TelemetryService struct {
+	repo     license.Repository
+	cfg      *config.TelemetryConfig
+	endpoint string
+	client   *http.Client
+	logger   *zap.Logger
+}
+
+func NewTelemetryService(repo license.Repository, cfg *config.TelemetryConfig, logger *zap.Logger) *TelemetryService {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = buildinfo.TelemetryEndpoint
+	}
+	return &TelemetryService{
+		repo:     repo,
+		cfg:      cfg,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		logger:   logger.Named("TelemetryService"),
+	}
+}
+
+// Active reports whether pings will actually be sent.
+func (s *TelemetryService) Active() bool {
+	return s.cfg.Enabled && s.endpoint != ""
+}
+
+func (s *TelemetryService) BuildReport(ctx context.Context) (*dto.TelemetryReport, error) {
+	summary, err := s.repo.GetDashboardSummary(ctx, defaultExpiringPeriodDays)
+	if err != nil {
+		return nil, fmt.Errorf("repository error collecting telemetry counts: %w", err)
+	}
+
+	report := &dto.TelemetryReport{
+		Version:          buildinfo.ServiceVersion(),
+		LicensesTotal:    summary.TotalCount,
+		LicensesByStatus: make(map[string]int64, len(summary.StatusCounts)),
+	}
+	for status, count := range summary.StatusCounts {
+		report.LicensesByStatus[string(status)] = count
+	}
+	return report, nil
+}
+
+func (s *TelemetryService) Preview(ctx context.Context) (*dto.TelemetryPreviewResponse, error) {
+	report, err := s.BuildReport(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TelemetryPreviewResponse{
+		Enabled:  s.Active(),
+		Endpoint: s.endpoint,
+		Interval: s.cfg.Interval.String(),
+		Payload:  *report,
+	}, nil
+}
+
+func (s *TelemetryService) Send(ctx context.Context) error {
+	if !s.Active() {
+		return ErrTelemetryDisabled
+	}
+
+	report, err := s.BuildReport(ctx)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry report: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build telemetry request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "license-service/"+report.Version)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("telemetry request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint responded with status %d", resp.StatusCode)
+	}
+
+	s.logger.Info("Telemetry ping sent", zap.Int64("licenses_total", report.LicensesTotal))
+	return nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/templates"
+	"go.uber.org/zap"
+)
+
+type TemplateService struct {
+	licenses license.Repository
+	renderer *templates.Renderer
+	logger   *zap.Logger
+}
+
+func NewTemplateService(licenses license.Repository, renderer *templates.Renderer, logger *zap.Logger) *TemplateService {
+	return &TemplateService{
+		licenses: licenses,
+		renderer: renderer,
+		logger:   logger.Named("TemplateService"),
+	}
+}
+
+func (s *TemplateService) Catalog() []templates.TemplateInfo {
+	return s.renderer.Catalog()
+}
+
+// Certificate renders the license certificate in locale, or in the customer
+// locale stored on the license when locale is empty.
+func (s *TemplateService) Certificate(ctx context.Context, id uuid.UUID, locale string) (*templates.Rendered, error) {
+	lic, err := s.licenses.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error finding license %s: %w", id, err)
+	}
+
+	if locale == "" {
+		locale = lic.Locale()
+	}
+
+	data := templates.CertificateData{
+		CustomerName:  lic.CustomerName.String,
+		CustomerEmail: lic.CustomerEmail.String,
+		ProductName:   lic.ProductName,
+		LicenseKey:    lic.LicenseKey,
+		LicenseType:   lic.Type,
+		Status:        string(lic.Status),
+		GeneratedAt:   time.Now(),
+	}
+	if lic.IssuedAt.Valid {
+		data.IssuedAt = &lic.IssuedAt.Time
+	}
+	if lic.ExpiresAt.Valid {
+		data.ExpiresAt = &lic.ExpiresAt.Time
+	}
+
+	rendered, err := s.renderer.Render(templates.Certificate, locale, data)
+	if err != nil {
+		s.logger.Error("Failed to render license certificate", zap.String("id", id.String()), zap.String("locale", locale), zap.Error(err))
+		return nil, fmt.Errorf("failed to render certificate for license %s: %w", id, err)
+	}
+	return rendered, nil
+}
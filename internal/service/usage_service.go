@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/entitlement"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/usage"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxUsageBackdate bounds how late an agent may report usage, so that
+	// the counters of closed months stay put.
+	maxUsageBackdate = 31 * 24 * time.Hour
+	// maxUsageClockSkew tolerates agents whose clocks run a little ahead.
+	maxUsageClockSkew = 5 * time.Minute
+)
+
+var errUsageLicenseNotFound = fmt.Errorf("%w: license not found for this product", ierr.ErrNotFound)
+
+// UsageService meters what agents report they used of a license, such as
+// API calls or exported documents. Limits are integer entitlements named
+// usage.<metric> and apply per calendar month; see usage.LimitPrefix.
+type UsageService struct {
+	usage        usage.Repository
+	licenses     license.Repository
+	entitlements entitlement.Repository
+	logger       *zap.Logger
+}
+
+func NewUsageService(usageRepo usage.Repository, licenses license.Repository, entitlements entitlement.Repository, logger *zap.Logger) *UsageService {
+	return &UsageService{
+		usage:        usageRepo,
+		licenses:     licenses,
+		entitlements: entitlements,
+		logger:       logger.Named("UsageService"),
+	}
+}
+
+// Report adds usage events to the license's counters and returns this
+// month's usage of the reported metrics. Usage is accepted over the limit;
+// it is the next validation that is denied.
+func (s *UsageService) Report(ctx context.Context, req *dto.UsageReportRequest) (*dto.UsageReportResponse, error) {
+	lic, err := s.licenses.FindByKey(ctx, req.LicenseKey)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, errUsageLicenseNotFound
+		}
+		return nil, fmt.Errorf("repository error finding license by key: %w", err)
+	}
+	// Like activation, a product mismatch is reported as not found so
+	// agents cannot probe keys of other products.
+	if lic.ProductName != req.ProductName {
+		return nil, errUsageLicenseNotFound
+	}
+	now := time.Now()
+	if lic.Status != license.StatusActive || lic.Lapsed(now) {
+		return nil, fmt.Errorf("%w: license is not active", ierr.ErrConflict)
+	}
+
+	events := make([]usage.Event, len(req.Events))
+	for i, e := range req.Events {
+		if err := usage.ValidateMetric(e.Metric); err != nil {
+			return nil, err
+		}
+		occurredAt := now
+		if e.OccurredAt != nil {
+			occurredAt = *e.OccurredAt
+		}
+		if occurredAt.After(now.Add(maxUsageClockSkew)) {
+			return nil, fmt.Errorf("%w: occurred_at of event %d is in the future", ierr.ErrValidation, i)
+		}
+		if occurredAt.Before(now.Add(-maxUsageBackdate)) {
+			return nil, fmt.Errorf("%w: occurred_at of event %d is more than 31 days ago", ierr.ErrValidation, i)
+		}
+		events[i] = usage.Event{Metric: e.Metric, Quantity: e.Quantity, OccurredAt: occurredAt}
+	}
+
+	if err := s.usage.Record(ctx, lic.ID, events); err != nil {
+		return nil, fmt.Errorf("repository error recording usage of license %s: %w", lic.ID, err)
+	}
+	s.logger.Debug("Usage recorded", zap.String("license_id", lic.ID.String()), zap.Int("events", len(events)))
+
+	periodStart := usage.PeriodMonth.Start(now)
+	totals, err := s.usage.Totals(ctx, lic.ID, periodStart)
+	if err != nil {
+		return nil, fmt.Errorf("repository error summing usage of license %s: %w", lic.ID, err)
+	}
+	entitlements, err := licenseEntitlements(ctx, s.entitlements, lic)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing entitlements of license %s: %w", lic.ID, err)
+	}
+	limits := usageLimits(entitlements)
+
+	resp := &dto.UsageReportResponse{
+		LicenseID:   lic.ID,
+		Accepted:    len(events),
+		PeriodStart: periodStart,
+	}
+	seen := make(map[string]bool, len(events))
+	for _, e := range events {
+		if seen[e.Metric] {
+			continue
+		}
+		seen[e.Metric] = true
+		status := dto.MetricUsage{Metric: e.Metric, Used: totals[e.Metric]}
+		if limit, ok := limits[e.Metric]; ok {
+			status.Limit = &limit
+			status.Exceeded = status.Used >= limit
+		}
+		resp.Usage = append(resp.Usage, status)
+	}
+	sort.Slice(resp.Usage, func(i, j int) bool { return resp.Usage[i].Metric < resp.Usage[j].Metric })
+	return resp, nil
+}
+
+func (s *UsageService) ListUsage(ctx context.Context, licenseID uuid.UUID, req *dto.ListUsageRequest) ([]*usage.Counter, error) {
+	if _, err := s.licenses.FindByID(ctx, licenseID); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("repository error finding license %s: %w", licenseID, err)
+	}
+
+	params := usage.ListParams{
+		LicenseID: licenseID,
+		Metric:    req.Metric,
+		Period:    usage.PeriodMonth,
+	}
+	if req.Period != "" {
+		params.Period = usage.Period(req.Period)
+	}
+	if req.From != nil {
+		from := usage.PeriodDay.Start(*req.From)
+		params.From = &from
+	}
+	if req.To != nil {
+		to := usage.PeriodDay.Start(*req.To)
+		params.To = &to
+	}
+	counters, err := s.usage.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing usage of license %s: %w", licenseID, err)
+	}
+	return counters, nil
+}
+
+// usageLimits reads the monthly limits from the usage.<metric> integer
+// entitlements.
+func usageLimits(entitlements []*entitlement.Entitlement) map[string]int64 {
+	limits := make(map[string]int64)
+	for _, e := range entitlements {
+		metric, ok := strings.CutPrefix(e.Name, usage.LimitPrefix)
+		if !ok || metric == "" || e.Type != entitlement.TypeInteger {
+			continue
+		}
+		limit, err := strconv.ParseInt(string(e.Value), 10, 64)
+		if err != nil {
+			continue
+		}
+		limits[metric] = limit
+	}
+	return limits
+}
+
+// firstExceededMetric returns the first metric, by name, whose usage this
+// month has reached its limit, or "" if none has.
+func firstExceededMetric(limits, totals map[string]int64) string {
+	metrics := make([]string, 0, len(limits))
+	for metric := range limits {
+		metrics = append(metrics, metric)
+	}
+	sort.Strings(metrics)
+	for _, metric := range metrics {
+		if totals[metric] >= limits[metric] {
+			return metric
+		}
+	}
+	return ""
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/background"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/validationevent"
+	"github.com/makkenzo/license-service-api/internal/enrichment"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+// ValidationEventService stores the outcome of license validations for
+// analytics. Failures are always kept; successful validations are sampled
+// per product so that very busy products do not flood the table.
+type ValidationEventService struct {
+	events       validationevent.Repository
+	background   *background.Pool
+	sampleRate   int
+	productRates map[string]int
+	logger       *zap.Logger
+}
+
+func NewValidationEventService(events validationevent.Repository, pool *background.Pool, cfg *config.ValidationEventsConfig, logger *zap.Logger) (*ValidationEventService, error) {
+	productRates, err := parseProductSampleRates(cfg.ProductSampleRates)
+	if err != nil {
+		return nil, err
+	}
+	sampleRate := cfg.SuccessSampleRate
+	if sampleRate < 1 {
+		sampleRate = 1
+	}
+	return &ValidationEventService{
+		events:       events,
+		background:   pool,
+		sampleRate:   sampleRate,
+		productRates: productRates,
+		logger:       logger.Named("ValidationEventService"),
+	}, nil
+}
+
+// parseProductSampleRates reads "name=N" entries.
+func parseProductSampleRates(entries []string) (map[string]int, error) {
+	rates := make(map[string]int, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		rate, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || strings.TrimSpace(name) == "" || err != nil || rate < 1 {
+			return nil, fmt.Errorf("invalid product sample rate %q, expected name=N with N at least 1", entry)
+		}
+		rates[strings.TrimSpace(name)] = rate
+	}
+	return rates, nil
+}
+
+// Record stores the outcome of one validation in the background, with the
+// network context ctx carries from package enrichment. A successful
+// validation of a product sampled at N is kept with probability 1/N and
+// recorded with sample_rate N.
+func (s *ValidationEventService) Record(ctx context.Context, req *dto.ValidateLicenseRequest, result *ValidationResult) {
+	ev := &validationevent.Event{
+		ProductName:  req.ProductName,
+		Valid:        result.IsValid,
+		Reason:       result.Reason,
+		AgentVersion: req.AgentVersion,
+		SampleRate:   1,
+	}
+	if result.IsValid {
+		if rate := s.rateFor(req.ProductName); rate > 1 {
+			if rand.IntN(rate) != 0 {
+				return
+			}
+			ev.SampleRate = rate
+		}
+	}
+	if result.License != nil {
+		ev.LicenseID = uuid.NullUUID{UUID: result.License.ID, Valid: true}
+	}
+	if meta, ok := decodeMetadata(req.Metadata); ok {
+		deviceID, _ := meta[MetaKeyDeviceID].(string)
+		ip, _ := meta[MetaKeyIPAddress].(string)
+		ev.DeviceID, ev.IPAddress = truncate(deviceID, 255), truncate(ip, 64)
+	}
+	if info := enrichment.InfoFrom(ctx); info != nil {
+		ev.ASN = info.ASN
+		ev.ASOrg = truncate(info.ASOrg, 255)
+		ev.Country = info.Country
+		ev.UserAgent = truncate(info.UserAgent, 512)
+		ev.TLSFingerprint = truncate(info.TLSFingerprint, 128)
+	}
+
+	s.background.Submit("validation_event", func(bgCtx context.Context) {
+		if err := s.events.Record(bgCtx, ev); err != nil {
+			s.logger.Error("Failed to record validation event", zap.String("product_name", ev.ProductName), zap.Error(err))
+		}
+	})
+}
+
+func (s *ValidationEventService) rateFor(productName string) int {
+	if rate, ok := s.productRates[productName]; ok {
+		return rate
+	}
+	return s.sampleRate
+}
+
+func (s *ValidationEventService) ListEvents(ctx context.Context, req *dto.ListValidationEventsRequest) ([]*validationevent.Event, int64, error) {
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	params := validationevent.ListParams{
+		ProductName: req.ProductName,
+		Valid:       req.Valid,
+		Country:     req.Country,
+		Limit:       req.Limit,
+		Offset:      req.Offset,
+	}
+	if req.LicenseID != nil {
+		licenseID, err := idgen.Parse(*req.LicenseID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: invalid license_id format", ierr.ErrValidation)
+		}
+		params.LicenseID = &licenseID
+	}
+
+	events, total, err := s.events.List(ctx, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("repository error listing validation events: %w", err)
+	}
+	return events, total, nil
+}
@@ -0,0 +1,542 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/makkenzo/license-service-api/internal/background"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+	"github.com/makkenzo/license-service-api/internal/domain/webhook"
+	"github.com/makkenzo/license-service-api/internal/handler/dto"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/lifecycle"
+	"github.com/makkenzo/license-service-api/internal/notify"
+	"github.com/makkenzo/license-service-api/internal/tasks"
+	"go.uber.org/zap"
+)
+
+const (
+	// WebhookEventHeader and WebhookDeliveryHeader tell receivers what they
+	// got; the delivery ID stays the same across retries.
+	WebhookEventHeader    = "X-Webhook-Event"
+	WebhookDeliveryHeader = "X-Webhook-Delivery"
+
+	// webhookSubscriptionsTTL is how long an instance dispatches to a cached
+	// list of subscriptions; changes made on other instances apply after it.
+	webhookSubscriptionsTTL = 30 * time.Second
+
+	defaultWebhookDeliveriesLimit = 50
+)
+
+// webhookEvent is the body of every delivery.
+type webhookEvent struct {
+	ID         uuid.UUID         `json:"id"`
+	Event      webhook.EventType `json:"event"`
+	OccurredAt time.Time         `json:"occurred_at"`
+	Data       any               `json:"data"`
+}
+
+type licenseEventData struct {
+	PreviousStatus string                   `json:"previous_status,omitempty"`
+	License        lifecycle.WebhookLicense `json:"license"`
+}
+
+type validationFailedData struct {
+	LicenseKey   string                    `json:"license_key"`
+	ProductName  string                    `json:"product_name"`
+	Reason       string                    `json:"reason"`
+	AgentVersion string                    `json:"agent_version,omitempty"`
+	License      *lifecycle.WebhookLicense `json:"license,omitempty"`
+}
+
+// WebhookService manages webhook subscriptions and dispatches events to
+// them: every matching subscription gets a delivery that the worker sends
+// and retries, see tasks.WebhookDeliverHandler. Endpoints that keep failing
+// are disabled with an alert.
+type WebhookService struct {
+	repo   webhook.Repository
+	tasks  *asynq.Client
+	pool   *background.Pool
+	crypto cryptoprovider.Provider
+	mailer notify.Mailer
+	cfg    *config.WebhooksConfig
+	http   *http.Client
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	subs     []*webhook.Subscription
+	loadedAt time.Time
+}
+
+func NewWebhookService(repo webhook.Repository, taskClient *asynq.Client, pool *background.Pool, crypto cryptoprovider.Provider, mailer notify.Mailer, cfg *config.WebhooksConfig, logger *zap.Logger) *WebhookService {
+	return &WebhookService{
+		repo:   repo,
+		tasks:  taskClient,
+		pool:   pool,
+		crypto: crypto,
+		mailer: mailer,
+		cfg:    cfg,
+		// Each request gets the timeout of its subscription's policy.
+		http:   &http.Client{},
+		logger: logger.Named("WebhookService"),
+	}
+}
+
+var _ lifecycle.LicenseLifecycleHook = (*WebhookService)(nil)
+
+func (s *WebhookService) Create(ctx context.Context, req *dto.CreateWebhookRequest, createdBy string) (*dto.CreatedWebhookResponse, error) {
+	if err := checkWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	retryPolicy := retryPolicyOf(req.RetryPolicy)
+	if err := s.checkRetryPolicy(retryPolicy); err != nil {
+		return nil, err
+	}
+	secret := req.Secret
+	if secret == "" {
+		raw := make([]byte, 32)
+		if _, err := io.ReadFull(s.crypto.Rand(), raw); err != nil {
+			return nil, fmt.Errorf("%w: generating webhook secret: %v", ierr.ErrInternalServer, err)
+		}
+		secret = "whsec_" + hex.EncodeToString(raw)
+	}
+
+	sub := &webhook.Subscription{
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      req.Events,
+		Description: req.Description,
+		IsEnabled:   req.IsEnabled == nil || *req.IsEnabled,
+		RetryPolicy: retryPolicy,
+		CreatedBy:   createdBy,
+	}
+	if err := s.repo.Create(ctx, sub); err != nil {
+		return nil, fmt.Errorf("repository error creating webhook subscription: %w", err)
+	}
+	s.invalidate()
+
+	s.logger.Info("Webhook subscription created", zap.String("id", sub.ID.String()), zap.String("url", sub.URL), zap.Strings("events", sub.Events))
+	return &dto.CreatedWebhookResponse{WebhookResponse: *dto.NewWebhookResponse(sub, s.policy(sub)), Secret: secret}, nil
+}
+
+func (s *WebhookService) List(ctx context.Context) ([]*dto.WebhookResponse, error) {
+	subs, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing webhook subscriptions: %w", err)
+	}
+	resp := make([]*dto.WebhookResponse, len(subs))
+	for i, sub := range subs {
+		resp[i] = dto.NewWebhookResponse(sub, s.policy(sub))
+	}
+	return resp, nil
+}
+
+func (s *WebhookService) Get(ctx context.Context, id uuid.UUID) (*dto.WebhookResponse, error) {
+	sub, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("repository error finding webhook subscription %s: %w", id, err)
+	}
+	return dto.NewWebhookResponse(sub, s.policy(sub)), nil
+}
+
+func (s *WebhookService) Update(ctx context.Context, id uuid.UUID, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error) {
+	sub, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("repository error finding webhook subscription %s: %w", id, err)
+	}
+	if req.URL != nil {
+		if err := checkWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		sub.URL = *req.URL
+	}
+	if len(req.Events) > 0 {
+		sub.Events = req.Events
+	}
+	if req.Secret != nil {
+		sub.Secret = *req.Secret
+	}
+	if req.Description != nil {
+		sub.Description = *req.Description
+	}
+	if req.IsEnabled != nil {
+		sub.IsEnabled = *req.IsEnabled
+	}
+	if req.RetryPolicy != nil {
+		sub.RetryPolicy = retryPolicyOf(req.RetryPolicy)
+		if err := s.checkRetryPolicy(sub.RetryPolicy); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.Update(ctx, sub); err != nil {
+		return nil, fmt.Errorf("repository error updating webhook subscription %s: %w", id, err)
+	}
+	s.invalidate()
+
+	s.logger.Info("Webhook subscription updated", zap.String("id", id.String()))
+	return dto.NewWebhookResponse(sub, s.policy(sub)), nil
+}
+
+// Delete deletes the subscription with its delivery log; pending retries
+// are dropped.
+func (s *WebhookService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("repository error deleting webhook subscription %s: %w", id, err)
+	}
+	s.invalidate()
+
+	s.logger.Info("Webhook subscription deleted", zap.String("id", id.String()))
+	return nil
+}
+
+func (s *WebhookService) ListDeliveries(ctx context.Context, id uuid.UUID, req *dto.ListWebhookDeliveriesRequest) (*dto.WebhookDeliveryListResponse, error) {
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("repository error finding webhook subscription %s: %w", id, err)
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultWebhookDeliveriesLimit
+	}
+	deliveries, err := s.repo.ListDeliveries(ctx, id, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing webhook deliveries: %w", err)
+	}
+
+	resp := &dto.WebhookDeliveryListResponse{Deliveries: make([]*dto.WebhookDeliveryResponse, len(deliveries))}
+	for i, d := range deliveries {
+		resp.Deliveries[i] = &dto.WebhookDeliveryResponse{
+			ID:             d.ID,
+			EventID:        d.EventID,
+			Event:          d.Event,
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			ResponseStatus: d.ResponseStatus,
+			LastError:      d.LastError,
+			Payload:        d.Payload,
+			CreatedAt:      d.CreatedAt,
+			LastAttemptAt:  d.LastAttemptAt,
+		}
+	}
+	return resp, nil
+}
+
+func retryPolicyOf(req *dto.WebhookRetryPolicy) webhook.RetryPolicy {
+	if req == nil {
+		return webhook.RetryPolicy{}
+	}
+	return webhook.RetryPolicy{
+		MaxAttempts:           req.MaxAttempts,
+		InitialBackoffSeconds: req.InitialBackoffSeconds,
+		MaxBackoffSeconds:     req.MaxBackoffSeconds,
+		TimeoutSeconds:        req.TimeoutSeconds,
+	}
+}
+
+// checkRetryPolicy rejects a policy whose backoff, with the defaults filled
+// in, starts above its maximum.
+func (s *WebhookService) checkRetryPolicy(p webhook.RetryPolicy) error {
+	policy := s.policy(&webhook.Subscription{RetryPolicy: p})
+	if policy.InitialBackoff > policy.MaxBackoff {
+		return fmt.Errorf("%w: initial backoff %s exceeds max backoff %s", ierr.ErrValidation, policy.InitialBackoff, policy.MaxBackoff)
+	}
+	return nil
+}
+
+// policy returns the delivery policy of sub, using the WEBHOOKS_* settings
+// for what it does not override.
+func (s *WebhookService) policy(sub *webhook.Subscription) webhook.Policy {
+	policy := webhook.Policy{
+		MaxAttempts:    s.cfg.MaxRetries + 1,
+		InitialBackoff: s.cfg.InitialBackoff,
+		MaxBackoff:     s.cfg.MaxBackoff,
+		Timeout:        s.cfg.Timeout,
+	}
+	o := sub.RetryPolicy
+	if o.MaxAttempts != nil {
+		policy.MaxAttempts = *o.MaxAttempts
+	}
+	if o.InitialBackoffSeconds != nil {
+		policy.InitialBackoff = time.Duration(*o.InitialBackoffSeconds) * time.Second
+	}
+	if o.MaxBackoffSeconds != nil {
+		policy.MaxBackoff = time.Duration(*o.MaxBackoffSeconds) * time.Second
+	}
+	if o.TimeoutSeconds != nil {
+		policy.Timeout = time.Duration(*o.TimeoutSeconds) * time.Second
+	}
+	return policy
+}
+
+func checkWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: webhook url must be an absolute http or https URL", ierr.ErrValidation)
+	}
+	return nil
+}
+
+func (s *WebhookService) Name() string { return "webhook_subscriptions" }
+
+// Handle dispatches license.created, license.expired and license.revoked.
+func (s *WebhookService) Handle(ctx context.Context, event lifecycle.Event) error {
+	var eventType webhook.EventType
+	switch event.Type {
+	case lifecycle.EventCreated:
+		eventType = webhook.EventLicenseCreated
+	case lifecycle.EventExpired:
+		eventType = webhook.EventLicenseExpired
+	case lifecycle.EventRevoked:
+		eventType = webhook.EventLicenseRevoked
+	default:
+		return nil
+	}
+	return s.dispatch(ctx, eventType, event.OccurredAt, licenseEventData{
+		PreviousStatus: string(event.PreviousStatus),
+		License:        lifecycle.NewWebhookLicense(event.License),
+	})
+}
+
+// ValidationFailed dispatches validation.failed on the background pool.
+func (s *WebhookService) ValidationFailed(req *dto.ValidateLicenseRequest, result *ValidationResult) {
+	data := validationFailedData{
+		LicenseKey:   req.LicenseKey,
+		ProductName:  req.ProductName,
+		Reason:       result.Reason,
+		AgentVersion: req.AgentVersion,
+	}
+	if result.License != nil {
+		lic := lifecycle.NewWebhookLicense(result.License)
+		data.License = &lic
+	}
+	occurredAt := time.Now().UTC()
+	s.pool.Submit("webhook_validation_failed", func(bgCtx context.Context) {
+		if err := s.dispatch(bgCtx, webhook.EventValidationFailed, occurredAt, data); err != nil {
+			s.logger.Error("Failed to dispatch validation.failed webhooks", zap.String("product_name", req.ProductName), zap.Error(err))
+		}
+	})
+}
+
+func (s *WebhookService) dispatch(ctx context.Context, eventType webhook.EventType, occurredAt time.Time, data any) error {
+	subs, err := s.subscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	var targets []*webhook.Subscription
+	for _, sub := range subs {
+		if sub.Wants(eventType) {
+			targets = append(targets, sub)
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	eventID := uuid.New()
+	payload, err := json.Marshal(webhookEvent{ID: eventID, Event: eventType, OccurredAt: occurredAt, Data: data})
+	if err != nil {
+		return fmt.Errorf("encoding webhook event: %w", err)
+	}
+
+	var errs []error
+	for _, sub := range targets {
+		d := &webhook.Delivery{SubscriptionID: sub.ID, EventID: eventID, Event: eventType, Payload: payload}
+		if err := s.repo.CreateDelivery(ctx, d); err != nil {
+			errs = append(errs, fmt.Errorf("recording delivery to %s: %w", sub.ID, err))
+			continue
+		}
+		if err := s.enqueueDelivery(ctx, d.ID, 1, s.policy(sub), 0); err != nil {
+			errs = append(errs, fmt.Errorf("enqueueing delivery %s: %w", d.ID, err))
+			_ = s.repo.RecordAttempt(ctx, d.ID, webhook.Attempt{Status: webhook.DeliveryFailed, Error: "not enqueued: " + err.Error(), At: time.Now().UTC()})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// subscriptions returns all subscriptions, from the cache while it is fresh.
+func (s *WebhookService) subscriptions(ctx context.Context) ([]*webhook.Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subs != nil && time.Since(s.loadedAt) < webhookSubscriptionsTTL {
+		return s.subs, nil
+	}
+	subs, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository error listing webhook subscriptions: %w", err)
+	}
+	s.subs, s.loadedAt = subs, time.Now()
+	return subs, nil
+}
+
+func (s *WebhookService) invalidate() {
+	s.mu.Lock()
+	s.subs = nil
+	s.mu.Unlock()
+}
+
+// enqueueDelivery schedules the given attempt of a delivery after delay.
+// Each attempt is its own task, named after the attempt, so an attempt is
+// not scheduled twice.
+func (s *WebhookService) enqueueDelivery(ctx context.Context, id uuid.UUID, attempt int, policy webhook.Policy, delay time.Duration) error {
+	task, err := tasks.NewWebhookDeliverTask(tasks.WebhookDeliverPayload{DeliveryID: id},
+		asynq.TaskID(fmt.Sprintf("webhook:%s:%d", id, attempt)),
+		asynq.Timeout(policy.Timeout+10*time.Second),
+		asynq.ProcessIn(delay),
+	)
+	if err != nil {
+		return err
+	}
+	if _, err := s.tasks.EnqueueContext(ctx, task); err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+		return err
+	}
+	return nil
+}
+
+// Deliver sends a pending delivery once and records the attempt. A failed
+// attempt schedules the next one after the subscription's backoff, until
+// its attempts run out. Deliveries of deleted subscriptions are dropped and
+// those of disabled ones fail.
+func (s *WebhookService) Deliver(ctx context.Context, id uuid.UUID) error {
+	d, err := s.repo.FindDelivery(ctx, id)
+	if errors.Is(err, ierr.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("repository error finding webhook delivery %s: %w", id, err)
+	}
+	if d.Status != webhook.DeliveryPending {
+		return nil
+	}
+	sub, err := s.repo.FindByID(ctx, d.SubscriptionID)
+	if errors.Is(err, ierr.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("repository error finding webhook subscription %s: %w", d.SubscriptionID, err)
+	}
+	if !sub.IsEnabled {
+		return s.repo.RecordAttempt(ctx, id, webhook.Attempt{Status: webhook.DeliveryFailed, Error: "subscription is disabled", At: time.Now().UTC()})
+	}
+
+	policy := s.policy(sub)
+	attemptNo := d.Attempts + 1
+	responseStatus, sendErr := s.send(ctx, sub, d, policy.Timeout)
+	attempt := webhook.Attempt{Status: webhook.DeliverySucceeded, ResponseStatus: responseStatus, At: time.Now().UTC()}
+	if sendErr != nil {
+		s.logger.Warn("Webhook delivery attempt failed",
+			zap.String("delivery_id", id.String()),
+			zap.String("subscription_id", sub.ID.String()),
+			zap.Int("attempt", attemptNo),
+			zap.Error(sendErr),
+		)
+		attempt.Status, attempt.Error = webhook.DeliveryPending, truncate(sendErr.Error(), 1000)
+		if attemptNo >= policy.MaxAttempts {
+			attempt.Status = webhook.DeliveryFailed
+		}
+	}
+	if err := s.repo.RecordAttempt(ctx, id, attempt); err != nil {
+		s.logger.Error("Failed to record webhook delivery attempt", zap.String("delivery_id", id.String()), zap.Error(err))
+	}
+	s.recordHealth(ctx, sub, sendErr == nil, attempt.At)
+
+	if attempt.Status != webhook.DeliveryPending {
+		return nil
+	}
+	// Jitter spreads out the retries of deliveries that failed together.
+	delay := policy.Backoff(attemptNo)
+	delay += time.Duration(rand.Int64N(int64(delay)/10 + 1))
+	if err := s.enqueueDelivery(ctx, id, attemptNo+1, policy, delay); err != nil {
+		return fmt.Errorf("scheduling webhook delivery %s attempt %d: %w", id, attemptNo+1, err)
+	}
+	return nil
+}
+
+// recordHealth updates the subscription's health after an attempt and
+// disables it once it has failed every attempt for WEBHOOKS_DISABLEAFTER.
+func (s *WebhookService) recordHealth(ctx context.Context, sub *webhook.Subscription, succeeded bool, at time.Time) {
+	health, err := s.repo.RecordHealth(ctx, sub.ID, succeeded, at)
+	if err != nil {
+		if !errors.Is(err, ierr.ErrNotFound) {
+			s.logger.Error("Failed to record webhook subscription health", zap.String("subscription_id", sub.ID.String()), zap.Error(err))
+		}
+		return
+	}
+	if succeeded || s.cfg.DisableAfter <= 0 || health.FailingSince == nil || at.Sub(*health.FailingSince) < s.cfg.DisableAfter {
+		return
+	}
+
+	reason := fmt.Sprintf("every delivery attempt failed since %s (%d attempts)", health.FailingSince.UTC().Format(time.RFC3339), health.ConsecutiveFailures)
+	disabled, err := s.repo.Disable(ctx, sub.ID, reason, at)
+	if err != nil {
+		s.logger.Error("Failed to disable failing webhook subscription", zap.String("subscription_id", sub.ID.String()), zap.Error(err))
+		return
+	}
+	if !disabled {
+		return
+	}
+	s.invalidate()
+	s.logger.Error("Webhook subscription disabled after failing continuously",
+		zap.String("subscription_id", sub.ID.String()),
+		zap.String("url", sub.URL),
+		zap.Timep("failing_since", health.FailingSince),
+		zap.Int("consecutive_failures", health.ConsecutiveFailures),
+	)
+	s.alert(ctx, sub, reason)
+}
+
+// alert mails WEBHOOKS_ALERTEMAIL that sub was disabled.
+func (s *WebhookService) alert(ctx context.Context, sub *webhook.Subscription, reason string) {
+	if s.cfg.AlertEmail == "" {
+		return
+	}
+	body := fmt.Sprintf("The webhook subscription %s was disabled: %s.\n\nURL: %s\nDescription: %s\n\n"+
+		"Deliveries to it fail until it is enabled again with PATCH /api/v1/webhooks/%s and {\"is_enabled\": true}.\n",
+		sub.ID, reason, sub.URL, sub.Description, sub.ID)
+	err := s.mailer.Send(ctx, notify.Message{
+		To:      s.cfg.AlertEmail,
+		Subject: "Webhook disabled: " + sub.URL,
+		Body:    body,
+	})
+	if err != nil {
+		s.logger.Error("Failed to send webhook alert e-mail", zap.String("subscription_id", sub.ID.String()), zap.Error(err))
+	}
+}
+
+// send posts the delivery and returns the response status. Any 2xx answer
+// counts as delivered.
+func (s *WebhookService) send(ctx context.Context, sub *webhook.Subscription, d *webhook.Delivery, timeout time.Duration) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("building webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, string(d.Event))
+	req.Header.Set(WebhookDeliveryHeader, d.ID.String())
+	req.Header.Set(lifecycle.WebhookSignatureHeader, lifecycle.SignWebhook(s.crypto, sub.Secret, d.Payload, time.Now()))
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("posting webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		answer, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		return resp.StatusCode, fmt.Errorf("webhook answered %d: %s", resp.StatusCode, answer)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,50 @@
+package siem
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/audit"
+)
+
+// AuditRepository forwards every recorded audit entry to the exporter once
+// it is stored, so the SIEM never sees a mutation the audit log lacks.
+type AuditRepository struct {
+	audit.Repository
+	exporter *Exporter
+}
+
+func NewAuditRepository(repo audit.Repository, exporter *Exporter) *AuditRepository {
+	return &AuditRepository{Repository: repo, exporter: exporter}
+}
+
+var _ audit.Repository = (*AuditRepository)(nil)
+
+func (r *AuditRepository) Record(ctx context.Context, entry *audit.Entry) error {
+	if err := r.Repository.Record(ctx, entry); err != nil {
+		return err
+	}
+
+	details := map[string]interface{}{"audit_id": entry.ID}
+	if len(entry.Before) > 0 {
+		details["before"] = entry.Before
+	}
+	if len(entry.After) > 0 {
+		details["after"] = entry.After
+	}
+	ev := Event{
+		Time:       entry.CreatedAt.UTC(),
+		Category:   CategoryAudit,
+		Action:     entry.EntityType + "." + entry.Action,
+		Severity:   SeverityInfo,
+		Outcome:    "success",
+		Actor:      entry.Actor,
+		EntityType: entry.EntityType,
+		Details:    details,
+	}
+	if entry.EntityID != uuid.Nil {
+		ev.EntityID = entry.EntityID.String()
+	}
+	r.exporter.Publish(ev)
+	return nil
+}
@@ -0,0 +1,47 @@
+// Package siem streams audit and security events to an external SIEM over
+// syslog or an HTTP collector such as Splunk HEC or Logstash.
+package siem
+
+import (
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/domain/audit"
+)
+
+type Category string
+
+const (
+	// CategoryAudit covers recorded license mutations.
+	CategoryAudit Category = "audit"
+	// CategoryAuth covers rejected credentials: JWTs and API keys.
+	CategoryAuth Category = "auth"
+	// CategoryAccess covers authenticated requests that were denied.
+	CategoryAccess Category = "access"
+)
+
+var knownCategories = map[Category]bool{CategoryAudit: true, CategoryAuth: true, CategoryAccess: true}
+
+// ActorAnonymous marks events of callers that presented no valid
+// credentials.
+const ActorAnonymous audit.ActorType = "anonymous"
+
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+)
+
+// Event is the record sent to the SIEM. Field names follow the audit API so
+// that the same queries work on both.
+type Event struct {
+	Time       time.Time              `json:"time"`
+	Category   Category               `json:"category"`
+	Action     string                 `json:"action"`
+	Severity   Severity               `json:"severity"`
+	Outcome    string                 `json:"outcome"`
+	Actor      audit.Actor            `json:"actor"`
+	EntityType string                 `json:"entity_type,omitempty"`
+	EntityID   string                 `json:"entity_id,omitempty"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+}
@@ -0,0 +1,229 @@
+package siem
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var (
+	exportedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "siem_events_exported_total",
+		Help: "Events delivered to the SIEM, by category.",
+	}, []string{"category"})
+	droppedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "siem_events_dropped_total",
+		Help: "Events not delivered to the SIEM, by category and reason (buffer_full, send_failed).",
+	}, []string{"category", "reason"})
+	bufferLength = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "siem_buffer_length",
+		Help: "Events waiting to be sent to the SIEM.",
+	})
+)
+
+// Sink delivers a batch of events. A returned error means none of the batch
+// can be assumed delivered; the exporter retries the whole batch.
+type Sink interface {
+	Send(ctx context.Context, events []Event) error
+	Close() error
+}
+
+// Exporter buffers events in memory and sends them in batches from a single
+// goroutine. When the SIEM is slow or down the buffer fills up and Publish
+// starts dropping events instead of slowing down requests; drops are counted
+// in siem_events_dropped_total.
+type Exporter struct {
+	sink          Sink
+	categories    map[Category]bool
+	buffer        chan Event
+	batchSize     int
+	flushInterval time.Duration
+	blockTimeout  time.Duration
+	maxRetries    int
+	timeout       time.Duration
+	logger        *zap.Logger
+}
+
+func NewExporter(cfg *config.SIEMConfig, logger *zap.Logger) (*Exporter, error) {
+	categories := make(map[Category]bool, len(cfg.Categories))
+	for _, name := range cfg.Categories {
+		c := Category(strings.ToLower(strings.TrimSpace(name)))
+		if c == "" {
+			continue
+		}
+		if !knownCategories[c] {
+			return nil, fmt.Errorf("unknown SIEM event category %q, expected audit, auth or access", name)
+		}
+		categories[c] = true
+	}
+
+	sink, err := newSink(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Exporter{
+		sink:          sink,
+		categories:    categories,
+		buffer:        make(chan Event, max(cfg.BufferSize, 1)),
+		batchSize:     max(cfg.BatchSize, 1),
+		flushInterval: cfg.FlushInterval,
+		blockTimeout:  cfg.BlockTimeout,
+		maxRetries:    cfg.MaxRetries,
+		timeout:       cfg.Timeout,
+		logger:        logger.Named("SIEMExporter"),
+	}, nil
+}
+
+func newSink(cfg *config.SIEMConfig) (Sink, error) {
+	switch strings.ToLower(cfg.Transport) {
+	case "syslog":
+		return NewSyslogSink(cfg.Network, cfg.Address, cfg.Timeout)
+	case "http":
+		return NewHTTPSink(cfg.URL, cfg.Token, cfg.Format, cfg.Timeout)
+	default:
+		return nil, fmt.Errorf("unknown SIEM transport %q, expected syslog or http", cfg.Transport)
+	}
+}
+
+// Publish queues ev for export. It is safe to call on a nil Exporter, which
+// is what callers hold when the export is disabled.
+func (e *Exporter) Publish(ev Event) {
+	if e == nil || !e.categories[ev.Category] {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now().UTC()
+	}
+
+	select {
+	case e.buffer <- ev:
+		bufferLength.Inc()
+		return
+	default:
+	}
+
+	if e.blockTimeout > 0 {
+		timer := time.NewTimer(e.blockTimeout)
+		defer timer.Stop()
+		select {
+		case e.buffer <- ev:
+			bufferLength.Inc()
+			return
+		case <-timer.C:
+		}
+	}
+	droppedTotal.WithLabelValues(string(ev.Category), "buffer_full").Inc()
+	e.logger.Debug("SIEM buffer full, dropping event", zap.String("category", string(ev.Category)), zap.String("action", ev.Action))
+}
+
+// Run sends batches until ctx is cancelled, then flushes what is still
+// buffered within the configured timeout and closes the sink.
+func (e *Exporter) Run(ctx context.Context) error {
+	e.logger.Info("SIEM exporter started", zap.Int("buffer_size", cap(e.buffer)), zap.Int("batch_size", e.batchSize))
+	defer func() {
+		if err := e.sink.Close(); err != nil {
+			e.logger.Warn("Failed to close SIEM sink", zap.Error(err))
+		}
+	}()
+
+	ticker := time.NewTicker(e.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Event, 0, e.batchSize)
+	for {
+		select {
+		case ev := <-e.buffer:
+			bufferLength.Dec()
+			batch = append(batch, ev)
+			if len(batch) < e.batchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		case <-ctx.Done():
+			e.drain(batch)
+			e.logger.Info("SIEM exporter stopped")
+			return nil
+		}
+
+		e.send(ctx, batch)
+		batch = batch[:0]
+	}
+}
+
+func (e *Exporter) drain(batch []Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	for {
+		select {
+		case ev := <-e.buffer:
+			bufferLength.Dec()
+			batch = append(batch, ev)
+			if len(batch) < e.batchSize {
+				continue
+			}
+		default:
+		}
+		if len(batch) == 0 {
+			return
+		}
+		if ctx.Err() != nil || !e.sendOnce(ctx, batch) {
+			e.dropBatch(batch)
+		}
+		batch = batch[:0]
+	}
+}
+
+// send retries with exponential backoff. Events keep arriving in the buffer
+// meanwhile, which is where backpressure shows up.
+func (e *Exporter) send(ctx context.Context, batch []Event) {
+	backoff := 500 * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		if e.sendOnce(ctx, batch) {
+			return
+		}
+		if attempt >= e.maxRetries {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			e.drain(batch)
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	e.dropBatch(batch)
+}
+
+func (e *Exporter) sendOnce(ctx context.Context, batch []Event) bool {
+	sendCtx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+	if err := e.sink.Send(sendCtx, batch); err != nil {
+		e.logger.Warn("Failed to send events to SIEM", zap.Int("events", len(batch)), zap.Error(err))
+		return false
+	}
+	for _, ev := range batch {
+		exportedTotal.WithLabelValues(string(ev.Category)).Inc()
+	}
+	return true
+}
+
+func (e *Exporter) dropBatch(batch []Event) {
+	if len(batch) == 0 {
+		return
+	}
+	for _, ev := range batch {
+		droppedTotal.WithLabelValues(string(ev.Category), "send_failed").Inc()
+	}
+	e.logger.Error("Dropping events that could not be sent to SIEM", zap.Int("events", len(batch)))
+}
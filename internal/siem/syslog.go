@@ -0,0 +1,123 @@
+package siem
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	syslogFacilityAudit = 13
+	syslogAppName       = "license-service"
+)
+
+var syslogSeverity = map[Severity]int{SeverityInfo: 6, SeverityWarning: 4}
+
+// SyslogSink writes RFC 5424 messages with the JSON encoded event as the
+// message. Over tcp and tls messages are framed by octet counting (RFC 6587);
+// over udp each message is one datagram. The connection is re-established
+// after a write error.
+type SyslogSink struct {
+	network  string
+	address  string
+	timeout  time.Duration
+	hostname string
+	conn     net.Conn
+}
+
+func NewSyslogSink(network, address string, timeout time.Duration) (*SyslogSink, error) {
+	switch network {
+	case "udp", "tcp", "tls":
+	default:
+		return nil, fmt.Errorf("unknown SIEM syslog network %q, expected udp, tcp or tls", network)
+	}
+	if address == "" {
+		return nil, fmt.Errorf("SIEM syslog address is required")
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &SyslogSink{network: network, address: address, timeout: timeout, hostname: hostname}, nil
+}
+
+func (s *SyslogSink) Send(ctx context.Context, events []Event) error {
+	if s.conn == nil {
+		if err := s.dial(ctx); err != nil {
+			return err
+		}
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.conn.SetWriteDeadline(deadline)
+	}
+
+	for _, ev := range events {
+		msg, err := s.format(ev)
+		if err != nil {
+			return err
+		}
+		if s.network != "udp" {
+			msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+		}
+		if _, err := s.conn.Write(msg); err != nil {
+			_ = s.conn.Close()
+			s.conn = nil
+			return fmt.Errorf("writing to syslog %s: %w", s.address, err)
+		}
+	}
+	return nil
+}
+
+func (s *SyslogSink) dial(ctx context.Context) error {
+	dialer := &net.Dialer{Timeout: s.timeout}
+	var conn net.Conn
+	var err error
+	if s.network == "tls" {
+		host, _, splitErr := net.SplitHostPort(s.address)
+		if splitErr != nil {
+			host = s.address
+		}
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}).DialContext(ctx, "tcp", s.address)
+	} else {
+		conn, err = dialer.DialContext(ctx, s.network, s.address)
+	}
+	if err != nil {
+		return fmt.Errorf("connecting to syslog %s: %w", s.address, err)
+	}
+	s.conn = conn
+	return nil
+}
+
+func (s *SyslogSink) format(ev Event) ([]byte, error) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return nil, fmt.Errorf("encoding SIEM event: %w", err)
+	}
+	severity, ok := syslogSeverity[ev.Severity]
+	if !ok {
+		severity = syslogSeverity[SeverityInfo]
+	}
+	header := fmt.Sprintf("<%d>1 %s %s %s %d %s - ",
+		syslogFacilityAudit*8+severity,
+		ev.Time.UTC().Format(time.RFC3339Nano),
+		s.hostname,
+		syslogAppName,
+		os.Getpid(),
+		ev.Category,
+	)
+	return append([]byte(header), body...), nil
+}
+
+func (s *SyslogSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -0,0 +1,27 @@
+package signing
+
+import "encoding/json"
+
+// EntitlementTokenType is the JWS "typ" of entitlement tokens.
+const EntitlementTokenType = "entitlement+jwt"
+
+// EntitlementClaims is the payload of an entitlement token: a successful
+// validation signed for the agent that asked for it, so it can cache the
+// result and check it against the JWKS between validations instead of
+// trusting an unsigned copy. Like a passport it holds no license key.
+type EntitlementClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	Product   string `json:"product"`
+	Type      string `json:"type"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason"`
+	// LicenseExpiresAt is the expires_at of the license; GraceExpiresAt is
+	// set when it only validates because of its grace period.
+	LicenseExpiresAt int64           `json:"license_expires_at,omitempty"`
+	GraceExpiresAt   int64           `json:"grace_expires_at,omitempty"`
+	DeviceID         string          `json:"device_id,omitempty"`
+	Entitlements     json.RawMessage `json:"entitlements,omitempty"`
+}
@@ -0,0 +1,71 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+)
+
+// JWK is the public part of a signing key as published in the JWKS.
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y,omitempty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func publicJWK(pub crypto.PublicKey, alg string) (JWK, error) {
+	b64 := base64.RawURLEncoding.EncodeToString
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		return JWK{Kty: "OKP", Crv: "Ed25519", X: b64(k), Use: "sig", Alg: alg}, nil
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		x, y := make([]byte, size), make([]byte, size)
+		k.X.FillBytes(x)
+		k.Y.FillBytes(y)
+		return JWK{Kty: "EC", Crv: k.Curve.Params().Name, X: b64(x), Y: b64(y), Use: "sig", Alg: alg}, nil
+	default:
+		return JWK{}, fmt.Errorf("unsupported public key type %T", pub)
+	}
+}
+
+// parseJWK rebuilds the JWK of a stored key from its PKIX encoding.
+func parseJWK(der []byte, alg, kid string) (JWK, error) {
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return JWK{}, fmt.Errorf("parsing public key %s: %w", kid, err)
+	}
+	jwk, err := publicJWK(pub, alg)
+	if err != nil {
+		return JWK{}, err
+	}
+	jwk.Kid = kid
+	return jwk, nil
+}
+
+// thumbprint is the RFC 7638 JWK thumbprint, used as the kid so the same
+// key always gets the same ID.
+func thumbprint(p cryptoprovider.Provider, jwk JWK) string {
+	// RFC 7638 hashes the required members in lexicographic order, which is
+	// the order encoding/json uses for maps.
+	members := map[string]string{"crv": jwk.Crv, "kty": jwk.Kty, "x": jwk.X}
+	if jwk.Kty == "EC" {
+		members["y"] = jwk.Y
+	}
+	data, _ := json.Marshal(members)
+	return base64.RawURLEncoding.EncodeToString(p.Hash(data))
+}
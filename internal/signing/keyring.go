@@ -0,0 +1,230 @@
+// Package signing signs offline license files with rotating keys. The key
+// set lives in the signing_keys table so that every instance signs with the
+// same active key and publishes the same JWKS; private keys stay with the
+// crypto provider and are only referenced.
+package signing
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+	"github.com/makkenzo/license-service-api/internal/domain/signingkey"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+var ErrSigningDisabled = ierr.New("SIGNING_DISABLED", http.StatusServiceUnavailable, "license signing is disabled: no active signing key").
+	WithPublicMessage("Signed license files are not available on this server.")
+
+type activeKey struct {
+	kid    string
+	signer cryptoprovider.Signer
+}
+
+type Keyring struct {
+	repo     signingkey.Repository
+	provider cryptoprovider.Provider
+	cfg      *config.SigningConfig
+	logger   *zap.Logger
+
+	mu     sync.RWMutex
+	active *activeKey
+	jwks   JWKS
+}
+
+func NewKeyring(repo signingkey.Repository, provider cryptoprovider.Provider, cfg *config.SigningConfig, logger *zap.Logger) *Keyring {
+	return &Keyring{
+		repo:     repo,
+		provider: provider,
+		cfg:      cfg,
+		logger:   logger.Named("SigningKeyring"),
+		jwks:     JWKS{Keys: []JWK{}},
+	}
+}
+
+// Load reads the key set from the database. The private key is only
+// reopened when the active kid changed, so calling Load often is cheap.
+func (k *Keyring) Load(ctx context.Context) error {
+	keys, err := k.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	k.mu.RLock()
+	current := k.active
+	k.mu.RUnlock()
+
+	var active *activeKey
+	jwks := JWKS{Keys: make([]JWK, 0, len(keys))}
+	for _, key := range keys {
+		if key.Status == signingkey.StatusActive {
+			if current != nil && current.kid == key.KID {
+				active = current
+			} else if active, err = k.open(key); err != nil {
+				return err
+			}
+		} else if k.cfg.PublishRetiredFor > 0 && key.RetiredAt.Valid && time.Since(key.RetiredAt.Time) > k.cfg.PublishRetiredFor {
+			continue
+		}
+
+		jwk, err := parseJWK(key.PublicKey, key.Algorithm, key.KID)
+		if err != nil {
+			return err
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	k.mu.Lock()
+	k.active = active
+	k.jwks = jwks
+	k.mu.Unlock()
+
+	if active != nil && (current == nil || current.kid != active.kid) {
+		k.logger.Info("Signing with key", zap.String("kid", active.kid))
+	}
+	return nil
+}
+
+// open loads the private half of key and checks it still matches the
+// public key recorded at rotation.
+func (k *Keyring) open(key *signingkey.Key) (*activeKey, error) {
+	signer, err := k.provider.Signer(key.KeyRef)
+	if err != nil {
+		return nil, fmt.Errorf("opening signing key %s: %w", key.KID, err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return nil, fmt.Errorf("encoding public key of %s: %w", key.KID, err)
+	}
+	if string(der) != string(key.PublicKey) {
+		return nil, fmt.Errorf("signing key %s at %s does not match its recorded public key", key.KID, key.KeyRef)
+	}
+	return &activeKey{kid: key.KID, signer: signer}, nil
+}
+
+// Run reloads the key set every RefreshInterval so rotations made by other
+// instances or the CLI take effect without a restart.
+func (k *Keyring) Run(ctx context.Context) error {
+	ticker := time.NewTicker(k.cfg.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := k.Load(ctx); err != nil && ctx.Err() == nil {
+				k.logger.Error("Failed to reload signing keys", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Rotate makes the key at keyRef the active one. The previous key is
+// retired but stays in the JWKS, so files it signed keep verifying.
+func (k *Keyring) Rotate(ctx context.Context, keyRef string) (*signingkey.Key, error) {
+	signer, err := k.provider.Signer(keyRef)
+	if err != nil {
+		return nil, fmt.Errorf("%w: opening signing key: %v", ierr.ErrValidation, err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return nil, fmt.Errorf("%w: encoding public key: %v", ierr.ErrValidation, err)
+	}
+	jwk, err := publicJWK(signer.Public(), signer.Algorithm())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ierr.ErrValidation, err)
+	}
+
+	key := &signingkey.Key{
+		KID:       thumbprint(k.provider, jwk),
+		Algorithm: signer.Algorithm(),
+		KeyRef:    keyRef,
+		PublicKey: der,
+	}
+	if err := k.repo.Rotate(ctx, key); err != nil {
+		return nil, err
+	}
+	k.logger.Info("Rotated signing key", zap.String("kid", key.KID), zap.String("algorithm", key.Algorithm))
+
+	if err := k.Load(ctx); err != nil {
+		return nil, fmt.Errorf("reloading signing keys after rotation: %w", err)
+	}
+	return key, nil
+}
+
+func (k *Keyring) Keys(ctx context.Context) ([]*signingkey.Key, error) {
+	return k.repo.List(ctx)
+}
+
+// Issuer is the "iss" claim of everything signed with the keyring.
+func (k *Keyring) Issuer() string {
+	return k.cfg.Issuer
+}
+
+// PassportTTL is how long validation passports are valid.
+func (k *Keyring) PassportTTL() time.Duration {
+	return k.cfg.PassportTTL
+}
+
+// PassportAudience is the "aud" claim of validation passports, empty when
+// none is configured.
+func (k *Keyring) PassportAudience() string {
+	return k.cfg.PassportAudience
+}
+
+// EntitlementTokenTTL is how long entitlement tokens are valid.
+func (k *Keyring) EntitlementTokenTTL() time.Duration {
+	return k.cfg.EntitlementTokenTTL
+}
+
+func (k *Keyring) JWKS() JWKS {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.jwks
+}
+
+// Algorithm is the JWS algorithm of the active key, empty when signing is
+// disabled.
+func (k *Keyring) Algorithm() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if k.active == nil {
+		return ""
+	}
+	return k.active.signer.Algorithm()
+}
+
+// Sign returns claims as a compact JWS signed with the active key, and the
+// kid it was signed with.
+func (k *Keyring) Sign(typ string, claims interface{}) (token, kid string, err error) {
+	k.mu.RLock()
+	active := k.active
+	k.mu.RUnlock()
+	if active == nil {
+		return "", "", ErrSigningDisabled
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": active.signer.Algorithm(), "kid": active.kid, "typ": typ})
+	if err != nil {
+		return "", "", fmt.Errorf("encoding JWS header: %w", err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", "", fmt.Errorf("encoding JWS payload: %w", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	sig, err := active.signer.Sign([]byte(signingInput))
+	if err != nil {
+		return "", "", fmt.Errorf("signing with key %s: %w", active.kid, err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), active.kid, nil
+}
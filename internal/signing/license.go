@@ -0,0 +1,26 @@
+package signing
+
+import "encoding/json"
+
+// LicenseFileType is the JWS "typ" of offline license files.
+const LicenseFileType = "license+jwt"
+
+// LicenseClaims is the payload of an offline license file. Agents verify it
+// against the JWKS and then trust it without calling the server.
+type LicenseClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	// NotBefore is the starts_at of a scheduled license.
+	NotBefore int64 `json:"nbf,omitempty"`
+	// GracePeriodDays is how long after exp the license keeps working.
+	GracePeriodDays int             `json:"grace_period_days,omitempty"`
+	LicenseKey      string          `json:"license_key"`
+	Product         string          `json:"product"`
+	Type            string          `json:"type"`
+	Status          string          `json:"status"`
+	CustomerName    string          `json:"customer_name,omitempty"`
+	MaxActivations  int             `json:"max_activations"`
+	AllowedData     json.RawMessage `json:"allowed_data,omitempty"`
+}
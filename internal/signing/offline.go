@@ -0,0 +1,83 @@
+package signing
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// OfflineActivationType is the JWS "typ" of offline activation responses.
+const OfflineActivationType = "offline-activation+jwt"
+
+var ErrChallengeMismatch = errors.New("the response answers another challenge or device")
+
+// OfflineChallenge is what a machine without network access holds while it
+// waits for its activation response: the customer carries its code to the
+// vendor and the response must echo its nonce.
+type OfflineChallenge struct {
+	ID    string `json:"id"`
+	Nonce string `json:"nonce"`
+}
+
+// Code encodes the challenge for copying by hand or in a file.
+func (c OfflineChallenge) Code() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func ParseOfflineChallenge(code string) (OfflineChallenge, error) {
+	var c OfflineChallenge
+	data, err := base64.RawURLEncoding.DecodeString(code)
+	if err == nil {
+		err = json.Unmarshal(data, &c)
+	}
+	if err != nil || c.ID == "" || c.Nonce == "" {
+		return OfflineChallenge{}, errors.New("not an offline activation challenge code")
+	}
+	return c, nil
+}
+
+// OfflineActivationClaims is the payload of an offline activation response:
+// a license file bound to the device and challenge it answers. ExpiresAt is
+// the expiry of the license.
+type OfflineActivationClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	// NotBefore is the starts_at of a scheduled license.
+	NotBefore int64 `json:"nbf,omitempty"`
+	// ChallengeID is the ID of the challenge answered.
+	ChallengeID     string          `json:"jti"`
+	Nonce           string          `json:"nonce"`
+	DeviceID        string          `json:"device_id"`
+	GracePeriodDays int             `json:"grace_period_days,omitempty"`
+	Product         string          `json:"product"`
+	Type            string          `json:"type"`
+	MaxActivations  int             `json:"max_activations"`
+	AllowedData     json.RawMessage `json:"allowed_data,omitempty"`
+}
+
+// VerifyOfflineActivation checks a response the way an agent does: its
+// signature against jwks, and that it answers challenge for deviceID. Dates
+// are left to the caller, as for license files.
+func VerifyOfflineActivation(token string, jwks JWKS, challenge OfflineChallenge, deviceID string) (*OfflineActivationClaims, error) {
+	header, payload, err := Verify(token, jwks)
+	if err != nil {
+		return nil, err
+	}
+	if header.Typ != OfflineActivationType {
+		return nil, fmt.Errorf("%w: typ is %q, not %q", ErrMalformedToken, header.Typ, OfflineActivationType)
+	}
+
+	var claims OfflineActivationClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("%w: payload: %v", ErrMalformedToken, err)
+	}
+	if claims.ChallengeID != challenge.ID || subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(challenge.Nonce)) != 1 || claims.DeviceID != deviceID {
+		return nil, ErrChallengeMismatch
+	}
+	return &claims, nil
+}
@@ -0,0 +1,26 @@
+package signing
+
+import "encoding/json"
+
+// PassportType is the JWS "typ" of validation passports.
+const PassportType = "passport+jwt"
+
+// PassportClaims is the payload of a validation passport: proof that a
+// license validated a moment ago, for CDN edge functions and reverse proxies
+// that gate content by checking it against the JWKS instead of calling the
+// server on every request. Unlike a license file it holds no license key and
+// expires within minutes, so it can travel in cookies and headers.
+type PassportClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	Audience  string `json:"aud,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	Product   string `json:"product"`
+	Type      string `json:"type"`
+	// InGracePeriod is set when the license has expired and only validates
+	// because of its grace period.
+	InGracePeriod bool            `json:"in_grace_period,omitempty"`
+	DeviceID      string          `json:"device_id,omitempty"`
+	AllowedData   json.RawMessage `json:"allowed_data,omitempty"`
+}
@@ -0,0 +1,111 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+)
+
+var (
+	ErrMalformedToken = errors.New("not a compact JWS")
+	ErrUnknownKey     = errors.New("signed with a key that is not in the JWKS")
+	ErrBadSignature   = errors.New("signature does not match the contents")
+)
+
+// Header is the protected header of the tokens the Keyring signs.
+type Header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+// Verify checks the signature of a compact JWS against the keys of a JWKS
+// and returns its header and payload. Claims are left to the caller.
+func Verify(token string, jwks JWKS) (*Header, []byte, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return nil, nil, ErrMalformedToken
+	}
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: header: %v", ErrMalformedToken, err)
+	}
+	var header Header
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, nil, fmt.Errorf("%w: header: %v", ErrMalformedToken, err)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: payload: %v", ErrMalformedToken, err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: signature: %v", ErrMalformedToken, err)
+	}
+
+	var jwk *JWK
+	for i := range jwks.Keys {
+		if jwks.Keys[i].Kid == header.Kid {
+			jwk = &jwks.Keys[i]
+			break
+		}
+	}
+	if jwk == nil {
+		return &header, payload, fmt.Errorf("%w: kid %q", ErrUnknownKey, header.Kid)
+	}
+	if jwk.Alg != "" && jwk.Alg != header.Alg {
+		return &header, payload, fmt.Errorf("%w: token uses %s but key %s is %s", ErrBadSignature, header.Alg, jwk.Kid, jwk.Alg)
+	}
+	pub, err := jwk.PublicKey()
+	if err != nil {
+		return &header, payload, err
+	}
+
+	signingInput := []byte(parts[0] + "." + parts[1])
+	var ok bool
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		ok = header.Alg == "EdDSA" && ed25519.Verify(k, signingInput, sig)
+	case *ecdsa.PublicKey:
+		ok = header.Alg == "ES256" && cryptoprovider.VerifyES256(k, signingInput, sig)
+	}
+	if !ok {
+		return &header, payload, ErrBadSignature
+	}
+	return &header, payload, nil
+}
+
+// PublicKey decodes the key the JWK describes.
+func (j JWK) PublicKey() (crypto.PublicKey, error) {
+	b64 := base64.RawURLEncoding.DecodeString
+	switch {
+	case j.Kty == "OKP" && j.Crv == "Ed25519":
+		x, err := b64(j.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key %s", j.Kid)
+		}
+		return ed25519.PublicKey(x), nil
+	case j.Kty == "EC" && j.Crv == elliptic.P256().Params().Name:
+		x, errX := b64(j.X)
+		y, errY := b64(j.Y)
+		if errX != nil || errY != nil {
+			return nil, fmt.Errorf("invalid EC key %s", j.Kid)
+		}
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, fmt.Errorf("invalid EC key %s", j.Kid)
+		}
+		return pub, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s/%s of key %s", j.Kty, j.Crv, j.Kid)
+	}
+}
@@ -0,0 +1,106 @@
+// Package slotracker counts the requests, server errors and slow responses
+// of the license validation endpoint for its SLOs. Like metering, counts
+// are kept in memory per minute and added to Redis every flush interval,
+// so tracking costs a request no round trip and all instances add up to
+// the same counters.
+package slotracker
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/slo"
+	"go.uber.org/zap"
+)
+
+// finalFlushTimeout bounds the flush when the tracker stops.
+const finalFlushTimeout = 10 * time.Second
+
+type Tracker struct {
+	repo             slo.Repository
+	flushInterval    time.Duration
+	latencyThreshold time.Duration
+	logger           *zap.Logger
+
+	mu     sync.Mutex
+	counts map[time.Time]slo.Counts
+}
+
+func NewTracker(repo slo.Repository, cfg *config.SLOConfig, logger *zap.Logger) *Tracker {
+	return &Tracker{
+		repo:             repo,
+		flushInterval:    cfg.FlushInterval,
+		latencyThreshold: cfg.LatencyThreshold,
+		logger:           logger.Named("SLOTracker"),
+		counts:           make(map[time.Time]slo.Counts),
+	}
+}
+
+// Record counts a request that started at the given time and was answered
+// with status after latency.
+func (t *Tracker) Record(at time.Time, status int, latency time.Duration) {
+	c := slo.Counts{Requests: 1}
+	switch {
+	case status >= http.StatusInternalServerError:
+		c.Errors = 1
+	case latency > t.latencyThreshold:
+		c.Slow = 1
+	}
+	minute := at.UTC().Truncate(slo.Minute)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cur := t.counts[minute]
+	cur.Add(c)
+	t.counts[minute] = cur
+}
+
+// Run flushes the counts every flush interval until ctx is done, then
+// flushes once more.
+func (t *Tracker) Run(ctx context.Context) error {
+	t.logger.Info("SLO tracker started", zap.Duration("flush_interval", t.flushInterval))
+	ticker := time.NewTicker(t.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			t.flush(ctx)
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), finalFlushTimeout)
+			t.flush(flushCtx)
+			cancel()
+			t.logger.Info("SLO tracker stopped")
+			return nil
+		}
+	}
+}
+
+// flush writes the counts gathered since the last flush. Counts that could
+// not be written are put back and written with the next flush.
+func (t *Tracker) flush(ctx context.Context) {
+	t.mu.Lock()
+	pending := t.counts
+	t.counts = make(map[time.Time]slo.Counts, len(pending))
+	t.mu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	err := t.repo.Add(ctx, pending)
+	if err == nil {
+		return
+	}
+	t.logger.Warn("Failed to flush SLO counts, keeping them for the next flush", zap.Int("minutes", len(pending)), zap.Error(err))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for minute, c := range pending {
+		cur := t.counts[minute]
+		cur.Add(c)
+		t.counts[minute] = cur
+	}
+}
@@ -0,0 +1,132 @@
+// Package startup brings up the dependencies of the service one stage at a
+// time, retrying each with backoff, and reports through /readyz which stages
+// are done so that orchestrators hold traffic back until the service is
+// ready instead of restarting it.
+package startup
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// StageStatus is the progress of one startup stage.
+type StageStatus struct {
+	Name      string     `json:"name"`
+	Ready     bool       `json:"ready"`
+	Attempts  int        `json:"attempts"`
+	LastError string     `json:"last_error,omitempty"`
+	ReadyAt   *time.Time `json:"ready_at,omitempty"`
+}
+
+// Readiness tracks the startup stages. The service is ready once MarkReady
+// is called after the last stage, and stops being ready on MarkDraining.
+type Readiness struct {
+	mu     sync.RWMutex
+	stages []*StageStatus
+	ready  bool
+	status string
+}
+
+func NewReadiness() *Readiness {
+	return &Readiness{status: "starting"}
+}
+
+func (r *Readiness) Ready() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.ready
+}
+
+// MarkReady is called once every stage has completed and the API is served.
+func (r *Readiness) MarkReady() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ready, r.status = true, "ready"
+}
+
+// MarkDraining takes the service out of rotation while it shuts down.
+func (r *Readiness) MarkDraining() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ready, r.status = false, "draining"
+}
+
+func (r *Readiness) stage(name string) *StageStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.stages {
+		if s.Name == name {
+			return s
+		}
+	}
+	s := &StageStatus{Name: name}
+	r.stages = append(r.stages, s)
+	return s
+}
+
+func (r *Readiness) recordAttempt(s *StageStatus, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s.Attempts++
+	if err != nil {
+		s.LastError = err.Error()
+		return
+	}
+	now := time.Now().UTC()
+	s.Ready, s.LastError, s.ReadyAt = true, "", &now
+}
+
+type report struct {
+	Status string        `json:"status"`
+	Stages []StageStatus `json:"stages"`
+}
+
+func (r *Readiness) report() (bool, report) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	rep := report{Status: r.status, Stages: make([]StageStatus, len(r.stages))}
+	for i, s := range r.stages {
+		rep.Stages[i] = *s
+	}
+	return r.ready, rep
+}
+
+// ServeHTTP answers /readyz: 200 once ready, 503 with the stage progress
+// before that and while draining.
+func (r *Readiness) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	ready, rep := r.report()
+	status := http.StatusOK
+	if !ready {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, rep)
+}
+
+// StartingHandler serves requests until the API router is in place: /readyz
+// reports the stage progress, /healthz answers 200 so that liveness probes
+// do not restart an instance that is still waiting for its dependencies, and
+// everything else gets 503 with Retry-After.
+func (r *Readiness) StartingHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/readyz", r)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		_, rep := r.report()
+		writeJSON(w, http.StatusOK, rep)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "5")
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"code":    "SERVICE_STARTING",
+			"message": "The service is starting, try again shortly.",
+		})
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,78 @@
+package startup
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/config"
+	"go.uber.org/zap"
+)
+
+// Retry runs connect until it succeeds, waiting between attempts with
+// exponential backoff from cfg.InitialBackoff up to cfg.MaxBackoff. It gives
+// up with the last error once cfg.Timeout has passed; a zero timeout tries
+// only once.
+func Retry(ctx context.Context, r *Readiness, cfg *config.StartupConfig, logger *zap.Logger, name string, connect func(context.Context) error) error {
+	s := r.stage(name)
+	deadline := time.Now().Add(cfg.Timeout)
+	backoff := cfg.InitialBackoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+
+	for {
+		err := connect(ctx)
+		r.recordAttempt(s, err)
+		if err == nil {
+			if s.Attempts > 1 {
+				logger.Info("Startup dependency became available", zap.String("stage", name), zap.Int("attempts", s.Attempts))
+			}
+			return nil
+		}
+
+		wait := min(backoff, time.Until(deadline))
+		if wait <= 0 {
+			return fmt.Errorf("%s not available after %d attempt(s): %w", name, s.Attempts, err)
+		}
+		logger.Warn("Startup dependency not available yet, retrying",
+			zap.String("stage", name),
+			zap.Int("attempt", s.Attempts),
+			zap.Duration("retry_in", wait),
+			zap.Error(err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s not available, startup cancelled: %w", name, err)
+		case <-time.After(wait):
+		}
+		if cfg.MaxBackoff > 0 {
+			backoff = min(backoff*2, cfg.MaxBackoff)
+		} else {
+			backoff *= 2
+		}
+	}
+}
+
+// Switch serves one handler until Serve hands it another, so the HTTP server
+// can listen before the API router exists.
+type Switch struct {
+	h atomic.Pointer[http.Handler]
+}
+
+func NewSwitch(initial http.Handler) *Switch {
+	s := &Switch{}
+	s.Serve(initial)
+	return s
+}
+
+func (s *Switch) Serve(h http.Handler) {
+	s.h.Store(&h)
+}
+
+func (s *Switch) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	(*s.h.Load()).ServeHTTP(w, req)
+}
@@ -0,0 +1,17 @@
+package statusguard
+
+import "context"
+
+type overrideKey struct{}
+
+// WithOperatorOverride marks ctx as an explicit status change by an operator,
+// which goes through even on frozen licenses. Only the handlers of such
+// changes set it; other writes by the same user are still held back.
+func WithOperatorOverride(ctx context.Context) context.Context {
+	return context.WithValue(ctx, overrideKey{}, true)
+}
+
+func operatorOverride(ctx context.Context) bool {
+	override, _ := ctx.Value(overrideKey{}).(bool)
+	return override
+}
@@ -0,0 +1,132 @@
+// Package statusguard freezes status transitions on licenses whose status
+// keeps flipping, which almost always means two automations are fighting
+// over the same license.
+package statusguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	lastStatusPrefix = "status_guard:last:"
+	flipsPrefix      = "status_guard:flips:"
+	frozenPrefix     = "status_guard:frozen:"
+	flipWindow       = time.Hour
+)
+
+var frozenTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "license_status_frozen_total",
+	Help: "Licenses whose status transitions were frozen because of flapping.",
+})
+
+type FreezeState struct {
+	Frozen      bool
+	Until       *time.Time
+	RecentFlips int64
+}
+
+type Guard struct {
+	client         *redis.Client
+	maxFlips       int64
+	freezeDuration time.Duration
+	logger         *zap.Logger
+}
+
+func NewGuard(client *redis.Client, cfg *config.StatusGuardConfig, logger *zap.Logger) *Guard {
+	return &Guard{
+		client:         client,
+		maxFlips:       int64(cfg.MaxFlipsPerHour),
+		freezeDuration: cfg.FreezeDuration,
+		logger:         logger.Named("StatusGuard"),
+	}
+}
+
+func (g *Guard) IsFrozen(ctx context.Context, id uuid.UUID) (bool, error) {
+	n, err := g.client.Exists(ctx, frozenPrefix+id.String()).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis error checking status freeze: %w", err)
+	}
+	return n > 0, nil
+}
+
+// RecordTransition notes that id moved to status. A transition only counts as
+// a flip when the previously recorded status differs. Once the flips in the
+// last hour exceed the limit the license is frozen and true is returned.
+func (g *Guard) RecordTransition(ctx context.Context, id uuid.UUID, status license.LicenseStatus) (bool, error) {
+	previous, err := g.client.SetArgs(ctx, lastStatusPrefix+id.String(), string(status), redis.SetArgs{Get: true, TTL: 24 * time.Hour}).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return false, fmt.Errorf("redis error recording status: %w", err)
+	}
+	if previous == "" || previous == string(status) {
+		return false, nil
+	}
+
+	now := time.Now()
+	key := flipsPrefix + id.String()
+	pipe := g.client.TxPipeline()
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixNano()), Member: strconv.FormatInt(now.UnixNano(), 10)})
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.Add(-flipWindow).UnixNano(), 10))
+	count := pipe.ZCard(ctx, key)
+	pipe.Expire(ctx, key, flipWindow)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, fmt.Errorf("redis error counting status flips: %w", err)
+	}
+
+	if count.Val() <= g.maxFlips {
+		return false, nil
+	}
+
+	set, err := g.client.SetNX(ctx, frozenPrefix+id.String(), now.Add(g.freezeDuration).UTC().Format(time.RFC3339), g.freezeDuration).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis error freezing status: %w", err)
+	}
+	if set {
+		frozenTotal.Inc()
+		g.logger.Error("License status is flapping, freezing status transitions",
+			zap.String("license_id", id.String()),
+			zap.Int64("flips_last_hour", count.Val()),
+			zap.Duration("freeze_duration", g.freezeDuration),
+		)
+	}
+	return true, nil
+}
+
+func (g *Guard) State(ctx context.Context, id uuid.UUID) (*FreezeState, error) {
+	pipe := g.client.Pipeline()
+	frozen := pipe.Get(ctx, frozenPrefix+id.String())
+	flips := pipe.ZCount(ctx, flipsPrefix+id.String(), strconv.FormatInt(time.Now().Add(-flipWindow).UnixNano(), 10), "+inf")
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("redis error reading status freeze: %w", err)
+	}
+
+	state := &FreezeState{RecentFlips: flips.Val()}
+	if until, err := frozen.Result(); err == nil {
+		state.Frozen = true
+		if t, errParse := time.Parse(time.RFC3339, until); errParse == nil {
+			state.Until = &t
+		}
+	}
+	return state, nil
+}
+
+// Unfreeze lifts a freeze and forgets recent flips, so the license starts
+// from a clean slate.
+func (g *Guard) Unfreeze(ctx context.Context, id uuid.UUID) error {
+	if err := g.client.Del(ctx, frozenPrefix+id.String(), flipsPrefix+id.String()).Err(); err != nil {
+		return fmt.Errorf("redis error lifting status freeze: %w", err)
+	}
+	g.logger.Info("Status freeze lifted", zap.String("license_id", id.String()))
+	return nil
+}
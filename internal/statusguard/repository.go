@@ -0,0 +1,82 @@
+package statusguard
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+// LicenseRepository rejects status changes on frozen licenses and records
+// every successful one with the guard, whether it comes through UpdateStatus
+// or a full Update. Freezes hold back automations only: explicit status
+// changes by an operator, marked with WithOperatorOverride, always go
+// through.
+type LicenseRepository struct {
+	license.Repository
+	guard  *Guard
+	logger *zap.Logger
+}
+
+func NewLicenseRepository(repo license.Repository, guard *Guard, logger *zap.Logger) *LicenseRepository {
+	return &LicenseRepository{
+		Repository: repo,
+		guard:      guard,
+		logger:     logger.Named("StatusGuardedLicenseRepository"),
+	}
+}
+
+var _ license.Repository = (*LicenseRepository)(nil)
+
+func (r *LicenseRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status license.LicenseStatus) error {
+	if err := r.checkFrozen(ctx, id, status); err != nil {
+		return err
+	}
+	if err := r.Repository.UpdateStatus(ctx, id, status); err != nil {
+		return err
+	}
+	r.record(ctx, id, status)
+	return nil
+}
+
+func (r *LicenseRepository) Update(ctx context.Context, lic *license.License) error {
+	current, err := r.Repository.FindByID(ctx, lic.ID)
+	if err != nil {
+		return err
+	}
+	if current.Status == lic.Status {
+		return r.Repository.Update(ctx, lic)
+	}
+
+	if err := r.checkFrozen(ctx, lic.ID, lic.Status); err != nil {
+		return err
+	}
+	if err := r.Repository.Update(ctx, lic); err != nil {
+		return err
+	}
+	r.record(ctx, lic.ID, lic.Status)
+	return nil
+}
+
+func (r *LicenseRepository) checkFrozen(ctx context.Context, id uuid.UUID, status license.LicenseStatus) error {
+	if operatorOverride(ctx) {
+		return nil
+	}
+	frozen, err := r.guard.IsFrozen(ctx, id)
+	if err != nil {
+		r.logger.Warn("Failed to check status freeze, allowing transition", zap.String("license_id", id.String()), zap.Error(err))
+	}
+	if frozen {
+		r.logger.Warn("Rejected status transition on frozen license", zap.String("license_id", id.String()), zap.String("status", string(status)))
+		return ierr.ErrStatusFrozen
+	}
+	return nil
+}
+
+func (r *LicenseRepository) record(ctx context.Context, id uuid.UUID, status license.LicenseStatus) {
+	if _, err := r.guard.RecordTransition(ctx, id, status); err != nil {
+		r.logger.Warn("Failed to record status transition", zap.String("license_id", id.String()), zap.Error(err))
+	}
+}
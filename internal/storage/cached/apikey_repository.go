@@ -0,0 +1,78 @@
+package cached
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/cache"
+	"github.com/makkenzo/license-service-api/internal/domain/apikey"
+	"go.uber.org/zap"
+)
+
+const (
+	apiKeyPrefixPrefix = "apikey:prefix:"
+	apiKeyIDPrefix     = "apikey:id:"
+)
+
+// APIKeyRepository caches enabled API keys by prefix so the agent-facing
+// endpoints don't hit the database on every request.
+type APIKeyRepository struct {
+	apikey.Repository
+	cache  cache.Cache
+	ttl    time.Duration
+	logger *zap.Logger
+}
+
+func NewAPIKeyRepository(repo apikey.Repository, c cache.Cache, ttl time.Duration, logger *zap.Logger) *APIKeyRepository {
+	return &APIKeyRepository{
+		Repository: repo,
+		cache:      c,
+		ttl:        ttl,
+		logger:     logger.Named("CachedAPIKeyRepository"),
+	}
+}
+
+var _ apikey.Repository = (*APIKeyRepository)(nil)
+
+func (r *APIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*apikey.APIKey, error) {
+	cached, err := r.cache.Get(ctx, apiKeyPrefixPrefix+prefix)
+	if err == nil {
+		var key apikey.APIKey
+		if errUnmarshal := json.Unmarshal(cached, &key); errUnmarshal == nil {
+			return &key, nil
+		}
+		r.logger.Warn("Failed to decode cached api key, falling back to repository", zap.String("prefix", prefix))
+	} else if !errors.Is(err, cache.ErrMiss) {
+		r.logger.Warn("Failed to read api key from cache, falling back to repository", zap.String("prefix", prefix), zap.Error(err))
+	}
+
+	key, err := r.Repository.FindByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(key)
+	if err == nil {
+		_ = r.cache.Set(ctx, apiKeyPrefixPrefix+prefix, data, r.ttl)
+		_ = r.cache.Set(ctx, apiKeyIDPrefix+key.ID.String(), []byte(prefix), r.ttl)
+	}
+	return key, nil
+}
+
+func (r *APIKeyRepository) Disable(ctx context.Context, id uuid.UUID) error {
+	if err := r.Repository.Disable(ctx, id); err != nil {
+		return err
+	}
+
+	prefix, err := r.cache.Get(ctx, apiKeyIDPrefix+id.String())
+	if err != nil {
+		return nil
+	}
+	if err := r.cache.Delete(ctx, apiKeyPrefixPrefix+string(prefix), apiKeyIDPrefix+id.String()); err != nil {
+		r.logger.Warn("Failed to evict disabled api key from cache", zap.String("id", id.String()), zap.Error(err))
+	}
+	return nil
+}
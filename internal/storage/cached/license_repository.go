@@ -0,0 +1,218 @@
+package cached
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/cache"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"go.uber.org/zap"
+)
+
+// The version in the license prefixes is bumped whenever the cached License
+// gains a field that writes depend on, so entries from older releases are
+// never written back.
+const (
+	licenseKeyPrefix = "license:v2:key:"
+	licenseIDPrefix  = "license:v2:id:"
+	aggregatePrefix  = "license:aggregate:"
+)
+
+// LicenseRepository wraps a license.Repository with a read-through cache for
+// lookups by license key, which is the hot path of license validation.
+type LicenseRepository struct {
+	license.Repository
+	cache        cache.Cache
+	ttl          time.Duration
+	aggregateTTL time.Duration
+	logger       *zap.Logger
+}
+
+func NewLicenseRepository(repo license.Repository, c cache.Cache, ttl, aggregateTTL time.Duration, logger *zap.Logger) *LicenseRepository {
+	return &LicenseRepository{
+		Repository:   repo,
+		cache:        c,
+		ttl:          ttl,
+		aggregateTTL: aggregateTTL,
+		logger:       logger.Named("CachedLicenseRepository"),
+	}
+}
+
+var _ license.Repository = (*LicenseRepository)(nil)
+
+func (r *LicenseRepository) FindByKey(ctx context.Context, key string) (*license.License, error) {
+	cached, err := r.cache.Get(ctx, licenseKeyPrefix+key)
+	if err == nil {
+		var lic license.License
+		if errUnmarshal := json.Unmarshal(cached, &lic); errUnmarshal == nil {
+			return &lic, nil
+		}
+		r.logger.Warn("Failed to decode cached license, falling back to repository", zap.String("license_key", key))
+	} else if !errors.Is(err, cache.ErrMiss) {
+		r.logger.Warn("Failed to read license from cache, falling back to repository", zap.String("license_key", key), zap.Error(err))
+	}
+
+	lic, err := r.Repository.FindByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store(ctx, lic)
+	return lic, nil
+}
+
+func (r *LicenseRepository) Update(ctx context.Context, lic *license.License) error {
+	if err := r.Repository.Update(ctx, lic); err != nil {
+		return err
+	}
+	r.evict(ctx, lic.LicenseKey, lic.ID)
+	return nil
+}
+
+func (r *LicenseRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status license.LicenseStatus) error {
+	if err := r.Repository.UpdateStatus(ctx, id, status); err != nil {
+		return err
+	}
+	r.evictByID(ctx, id)
+	return nil
+}
+
+// RotateKey evicts the entry of the replaced key. Lookups by that key are not
+// cached again: store files licenses under their current key.
+func (r *LicenseRepository) RotateKey(ctx context.Context, id uuid.UUID, newKey func() (string, error), oldKeyValidUntil time.Time) (*license.License, error) {
+	lic, err := r.Repository.RotateKey(ctx, id, newKey, oldKeyValidUntil)
+	if err != nil {
+		return nil, err
+	}
+	r.evictByID(ctx, id)
+	return lic, nil
+}
+
+// UpdateMetadata evicts the entry like the other writes. Patching the cached
+// copy instead would write back a License read from the cache, which a
+// concurrent Update or UpdateStatus may have evicted in the meantime, and so
+// bring a revoked license back for the whole TTL.
+func (r *LicenseRepository) UpdateMetadata(ctx context.Context, id uuid.UUID, metadata json.RawMessage) error {
+	if err := r.Repository.UpdateMetadata(ctx, id, metadata); err != nil {
+		return err
+	}
+	r.evictByID(ctx, id)
+	return nil
+}
+
+// Aggregate caches results for aggregateTTL keyed by the normalized query.
+// Entries are not evicted on writes; a short TTL keeps dashboards close
+// enough to live data without hitting the database on every refresh.
+func (r *LicenseRepository) Aggregate(ctx context.Context, params license.AggregateParams) ([]license.AggregateRow, error) {
+	if r.aggregateTTL <= 0 {
+		return r.Repository.Aggregate(ctx, params)
+	}
+
+	key := aggregatePrefix + aggregateCacheKey(params)
+	cached, err := r.cache.Get(ctx, key)
+	if err == nil {
+		var rows []license.AggregateRow
+		if errUnmarshal := json.Unmarshal(cached, &rows); errUnmarshal == nil {
+			return rows, nil
+		}
+		r.logger.Warn("Failed to decode cached aggregate, falling back to repository", zap.String("key", key))
+	} else if !errors.Is(err, cache.ErrMiss) {
+		r.logger.Warn("Failed to read aggregate from cache, falling back to repository", zap.String("key", key), zap.Error(err))
+	}
+
+	rows, err := r.Repository.Aggregate(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(rows)
+	if err != nil {
+		r.logger.Warn("Failed to encode aggregate for cache", zap.Error(err))
+		return rows, nil
+	}
+	if err := r.cache.Set(ctx, key, data, r.aggregateTTL); err != nil {
+		r.logger.Warn("Failed to write aggregate to cache", zap.String("key", key), zap.Error(err))
+	}
+	return rows, nil
+}
+
+func aggregateCacheKey(params license.AggregateParams) string {
+	dims := make([]string, len(params.GroupBy))
+	for i, dim := range params.GroupBy {
+		dims[i] = dim.String()
+	}
+
+	parts := []string{strings.Join(dims, ","), params.Metric}
+	for _, filter := range []*string{(*string)(params.Status), params.ProductName, params.Type} {
+		if filter == nil {
+			parts = append(parts, "")
+		} else {
+			parts = append(parts, "="+*filter)
+		}
+	}
+	return strings.Join(parts, "|")
+}
+
+// Warm loads the topN most recently validated active licenses into the cache.
+func (r *LicenseRepository) Warm(ctx context.Context, topN int) (int, error) {
+	r.logger.Info("Pre-warming license cache", zap.Int("top_n", topN))
+
+	licenses, err := r.Repository.ListRecentlyValidated(ctx, topN)
+	if err != nil {
+		return 0, fmt.Errorf("repository error listing recently validated licenses: %w", err)
+	}
+
+	for _, lic := range licenses {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		r.store(ctx, lic)
+	}
+
+	r.logger.Info("License cache pre-warm finished", zap.Int("warmed", len(licenses)))
+	return len(licenses), nil
+}
+
+func (r *LicenseRepository) store(ctx context.Context, lic *license.License) {
+	data, err := json.Marshal(lic)
+	if err != nil {
+		r.logger.Warn("Failed to encode license for cache", zap.String("id", lic.ID.String()), zap.Error(err))
+		return
+	}
+
+	if err := r.cache.Set(ctx, licenseKeyPrefix+lic.LicenseKey, data, r.ttl); err != nil {
+		r.logger.Warn("Failed to write license to cache", zap.String("id", lic.ID.String()), zap.Error(err))
+		return
+	}
+	if err := r.cache.Set(ctx, licenseIDPrefix+lic.ID.String(), []byte(lic.LicenseKey), r.ttl); err != nil {
+		r.logger.Warn("Failed to write license key mapping to cache", zap.String("id", lic.ID.String()), zap.Error(err))
+	}
+}
+
+func (r *LicenseRepository) keyForID(ctx context.Context, id uuid.UUID) (string, bool) {
+	key, err := r.cache.Get(ctx, licenseIDPrefix+id.String())
+	if err != nil {
+		if !errors.Is(err, cache.ErrMiss) {
+			r.logger.Warn("Failed to resolve cached license key by ID", zap.String("id", id.String()), zap.Error(err))
+		}
+		return "", false
+	}
+	return string(key), true
+}
+
+func (r *LicenseRepository) evict(ctx context.Context, key string, id uuid.UUID) {
+	if err := r.cache.Delete(ctx, licenseKeyPrefix+key, licenseIDPrefix+id.String()); err != nil {
+		r.logger.Warn("Failed to evict license from cache", zap.String("id", id.String()), zap.Error(err))
+	}
+}
+
+func (r *LicenseRepository) evictByID(ctx context.Context, id uuid.UUID) {
+	if key, ok := r.keyForID(ctx, id); ok {
+		r.evict(ctx, key, id)
+	}
+}
@@ -0,0 +1,62 @@
+package cached
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/cache"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"go.uber.org/zap"
+)
+
+// oneLicense stores a single license, standing in for the database behind
+// the cache.
+type oneLicense struct {
+	license.Repository
+	lic license.License
+}
+
+func (r *oneLicense) FindByKey(_ context.Context, _ string) (*license.License, error) {
+	lic := r.lic
+	return &lic, nil
+}
+
+func (r *oneLicense) UpdateMetadata(_ context.Context, _ uuid.UUID, metadata json.RawMessage) error {
+	r.lic.Metadata = metadata
+	return nil
+}
+
+func TestUpdateMetadataDoesNotWriteBackCachedLicense(t *testing.T) {
+	ctx := context.Background()
+	mem, err := cache.NewMemoryCache(1<<20, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(mem.Close)
+	db := &oneLicense{lic: license.License{ID: uuid.New(), LicenseKey: "KEY-1", Status: license.StatusActive, Metadata: json.RawMessage(`{}`)}}
+	repo := NewLicenseRepository(db, mem, time.Hour, 0, zap.NewNop())
+
+	if _, err := repo.FindByKey(ctx, "KEY-1"); err != nil {
+		t.Fatal(err)
+	}
+	// A revoke on another instance whose eviction ran before the metadata
+	// write below got to the cache.
+	db.lic.Status = license.StatusRevoked
+
+	if err := repo.UpdateMetadata(ctx, db.lic.ID, json.RawMessage(`{"last_ip":"10.0.0.1"}`)); err != nil {
+		t.Fatal(err)
+	}
+	got, err := repo.FindByKey(ctx, "KEY-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != license.StatusRevoked {
+		t.Errorf("status after UpdateMetadata = %s, want %s", got.Status, license.StatusRevoked)
+	}
+	if string(got.Metadata) != `{"last_ip":"10.0.0.1"}` {
+		t.Errorf("metadata after UpdateMetadata = %s", got.Metadata)
+	}
+}
@@ -0,0 +1,177 @@
+package cached
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/cache"
+	"github.com/makkenzo/license-service-api/internal/domain/product"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+const (
+	productLifecyclePrefix   = "product:lifecycle:"
+	productAgentPolicyPrefix = "product:agent_policy:"
+	productCachePolicyPrefix = "product:cache_policy:"
+)
+
+// ProductRepository caches lifecycle, agent policy and cache policy lookups,
+// including misses, because every license validation asks for all three.
+type ProductRepository struct {
+	product.Repository
+	cache  cache.Cache
+	ttl    time.Duration
+	logger *zap.Logger
+}
+
+func NewProductRepository(repo product.Repository, c cache.Cache, ttl time.Duration, logger *zap.Logger) *ProductRepository {
+	return &ProductRepository{
+		Repository: repo,
+		cache:      c,
+		ttl:        ttl,
+		logger:     logger.Named("CachedProductRepository"),
+	}
+}
+
+var _ product.Repository = (*ProductRepository)(nil)
+
+func (r *ProductRepository) FindLifecycle(ctx context.Context, productName string) (*product.Lifecycle, error) {
+	key := productLifecyclePrefix + productName
+
+	cached, err := r.cache.Get(ctx, key)
+	if err == nil {
+		var lc *product.Lifecycle
+		if errUnmarshal := json.Unmarshal(cached, &lc); errUnmarshal == nil {
+			if lc == nil {
+				return nil, ierr.ErrNotFound
+			}
+			return lc, nil
+		}
+		r.logger.Warn("Failed to decode cached product lifecycle, falling back to repository", zap.String("product_name", productName))
+	} else if !errors.Is(err, cache.ErrMiss) {
+		r.logger.Warn("Failed to read product lifecycle from cache, falling back to repository", zap.String("product_name", productName), zap.Error(err))
+	}
+
+	lc, err := r.Repository.FindLifecycle(ctx, productName)
+	if err != nil && !errors.Is(err, ierr.ErrNotFound) {
+		return nil, err
+	}
+
+	if data, errMarshal := json.Marshal(lc); errMarshal == nil {
+		_ = r.cache.Set(ctx, key, data, r.ttl)
+	}
+	return lc, err
+}
+
+func (r *ProductRepository) UpsertLifecycle(ctx context.Context, lc *product.Lifecycle) error {
+	if err := r.Repository.UpsertLifecycle(ctx, lc); err != nil {
+		return err
+	}
+	if err := r.cache.Delete(ctx, productLifecyclePrefix+lc.ProductName); err != nil {
+		r.logger.Warn("Failed to evict product lifecycle from cache", zap.String("product_name", lc.ProductName), zap.Error(err))
+	}
+	return nil
+}
+
+func (r *ProductRepository) FindAgentPolicy(ctx context.Context, productName string) (*product.AgentPolicy, error) {
+	key := productAgentPolicyPrefix + productName
+
+	cached, err := r.cache.Get(ctx, key)
+	if err == nil {
+		var p *product.AgentPolicy
+		if errUnmarshal := json.Unmarshal(cached, &p); errUnmarshal == nil {
+			if p == nil {
+				return nil, ierr.ErrNotFound
+			}
+			return p, nil
+		}
+		r.logger.Warn("Failed to decode cached product agent policy, falling back to repository", zap.String("product_name", productName))
+	} else if !errors.Is(err, cache.ErrMiss) {
+		r.logger.Warn("Failed to read product agent policy from cache, falling back to repository", zap.String("product_name", productName), zap.Error(err))
+	}
+
+	p, err := r.Repository.FindAgentPolicy(ctx, productName)
+	if err != nil && !errors.Is(err, ierr.ErrNotFound) {
+		return nil, err
+	}
+
+	if data, errMarshal := json.Marshal(p); errMarshal == nil {
+		_ = r.cache.Set(ctx, key, data, r.ttl)
+	}
+	return p, err
+}
+
+func (r *ProductRepository) UpsertAgentPolicy(ctx context.Context, p *product.AgentPolicy) error {
+	if err := r.Repository.UpsertAgentPolicy(ctx, p); err != nil {
+		return err
+	}
+	r.evictAgentPolicy(ctx, p.ProductName)
+	return nil
+}
+
+func (r *ProductRepository) DeleteAgentPolicy(ctx context.Context, productName string) error {
+	if err := r.Repository.DeleteAgentPolicy(ctx, productName); err != nil {
+		return err
+	}
+	r.evictAgentPolicy(ctx, productName)
+	return nil
+}
+
+func (r *ProductRepository) evictAgentPolicy(ctx context.Context, productName string) {
+	if err := r.cache.Delete(ctx, productAgentPolicyPrefix+productName); err != nil {
+		r.logger.Warn("Failed to evict product agent policy from cache", zap.String("product_name", productName), zap.Error(err))
+	}
+}
+
+func (r *ProductRepository) FindCachePolicy(ctx context.Context, productName string) (*product.CachePolicy, error) {
+	key := productCachePolicyPrefix + productName
+
+	cached, err := r.cache.Get(ctx, key)
+	if err == nil {
+		var p *product.CachePolicy
+		if errUnmarshal := json.Unmarshal(cached, &p); errUnmarshal == nil {
+			if p == nil {
+				return nil, ierr.ErrNotFound
+			}
+			return p, nil
+		}
+		r.logger.Warn("Failed to decode cached product cache policy, falling back to repository", zap.String("product_name", productName))
+	} else if !errors.Is(err, cache.ErrMiss) {
+		r.logger.Warn("Failed to read product cache policy from cache, falling back to repository", zap.String("product_name", productName), zap.Error(err))
+	}
+
+	p, err := r.Repository.FindCachePolicy(ctx, productName)
+	if err != nil && !errors.Is(err, ierr.ErrNotFound) {
+		return nil, err
+	}
+
+	if data, errMarshal := json.Marshal(p); errMarshal == nil {
+		_ = r.cache.Set(ctx, key, data, r.ttl)
+	}
+	return p, err
+}
+
+func (r *ProductRepository) UpsertCachePolicy(ctx context.Context, p *product.CachePolicy) error {
+	if err := r.Repository.UpsertCachePolicy(ctx, p); err != nil {
+		return err
+	}
+	r.evictCachePolicy(ctx, p.ProductName)
+	return nil
+}
+
+func (r *ProductRepository) DeleteCachePolicy(ctx context.Context, productName string) error {
+	if err := r.Repository.DeleteCachePolicy(ctx, productName); err != nil {
+		return err
+	}
+	r.evictCachePolicy(ctx, productName)
+	return nil
+}
+
+func (r *ProductRepository) evictCachePolicy(ctx context.Context, productName string) {
+	if err := r.cache.Delete(ctx, productCachePolicyPrefix+productName); err != nil {
+		r.logger.Warn("Failed to evict product cache policy from cache", zap.String("product_name", productName), zap.Error(err))
+	}
+}
@@ -0,0 +1,52 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/apikey"
+)
+
+const apiKeyRepositoryName = "apikey"
+
+type APIKeyRepository struct {
+	repo   apikey.Repository
+	tracer Tracer
+}
+
+func NewAPIKeyRepository(repo apikey.Repository, tracer Tracer) *APIKeyRepository {
+	return &APIKeyRepository{repo: repo, tracer: tracer}
+}
+
+var _ apikey.Repository = (*APIKeyRepository)(nil)
+
+func (r *APIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*apikey.APIKey, error) {
+	return observe(ctx, r.tracer, apiKeyRepositoryName, "FindByPrefix", func(ctx context.Context) (*apikey.APIKey, error) {
+		return r.repo.FindByPrefix(ctx, prefix)
+	})
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *apikey.APIKey) (uuid.UUID, error) {
+	return observe(ctx, r.tracer, apiKeyRepositoryName, "Create", func(ctx context.Context) (uuid.UUID, error) {
+		return r.repo.Create(ctx, key)
+	})
+}
+
+func (r *APIKeyRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID, lastUsed time.Time) error {
+	return observeErr(ctx, r.tracer, apiKeyRepositoryName, "UpdateLastUsed", func(ctx context.Context) error {
+		return r.repo.UpdateLastUsed(ctx, id, lastUsed)
+	})
+}
+
+func (r *APIKeyRepository) List(ctx context.Context) ([]*apikey.APIKey, error) {
+	return observe(ctx, r.tracer, apiKeyRepositoryName, "List", func(ctx context.Context) ([]*apikey.APIKey, error) {
+		return r.repo.List(ctx)
+	})
+}
+
+func (r *APIKeyRepository) Disable(ctx context.Context, id uuid.UUID) error {
+	return observeErr(ctx, r.tracer, apiKeyRepositoryName, "Disable", func(ctx context.Context) error {
+		return r.repo.Disable(ctx, id)
+	})
+}
@@ -0,0 +1,122 @@
+// Package instrumented wraps repositories with Prometheus timings, error
+// counters and trace spans. The decorators forward every call through
+// observe, so a new repository method only needs a one-line forwarder.
+package instrumented
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var (
+	operationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "repository_operation_duration_seconds",
+		Help:    "Duration of repository calls, by repository and operation.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"repository", "operation"})
+	operationErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "repository_operation_errors_total",
+		Help: "Failed repository calls, by repository, operation and error kind. Not-found results are not counted.",
+	}, []string{"repository", "operation", "kind"})
+)
+
+// Span is one traced repository call.
+type Span interface {
+	End(err error)
+}
+
+// Tracer starts spans around repository calls. It is deliberately tiny so
+// an OpenTelemetry tracer can be adapted to it without this package
+// depending on one.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+type NopTracer struct{}
+
+func (NopTracer) Start(ctx context.Context, _ string) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+type nopSpan struct{}
+
+func (nopSpan) End(error) {}
+
+// LogTracer writes spans to the log: calls slower than slowThreshold at warn
+// level, everything else at debug.
+type LogTracer struct {
+	slowThreshold time.Duration
+	logger        *zap.Logger
+}
+
+func NewLogTracer(slowThreshold time.Duration, logger *zap.Logger) *LogTracer {
+	return &LogTracer{
+		slowThreshold: slowThreshold,
+		logger:        logger.Named("RepositoryTracer"),
+	}
+}
+
+func (t *LogTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, &logSpan{tracer: t, name: name, start: time.Now()}
+}
+
+type logSpan struct {
+	tracer *LogTracer
+	name   string
+	start  time.Time
+}
+
+func (s *logSpan) End(err error) {
+	elapsed := time.Since(s.start)
+	fields := []zap.Field{zap.String("span", s.name), zap.Duration("duration", elapsed)}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	if s.tracer.slowThreshold > 0 && elapsed >= s.tracer.slowThreshold {
+		s.tracer.logger.Warn("Slow repository call", fields...)
+		return
+	}
+	s.tracer.logger.Debug("Repository call", fields...)
+}
+
+func observe[T any](ctx context.Context, tracer Tracer, repository, operation string, call func(context.Context) (T, error)) (T, error) {
+	ctx, span := tracer.Start(ctx, repository+"."+operation)
+	start := time.Now()
+
+	result, err := call(ctx)
+
+	operationDuration.WithLabelValues(repository, operation).Observe(time.Since(start).Seconds())
+	if err != nil && !errors.Is(err, ierr.ErrNotFound) {
+		operationErrors.WithLabelValues(repository, operation, errorKind(err)).Inc()
+	}
+	span.End(err)
+	return result, err
+}
+
+func observeErr(ctx context.Context, tracer Tracer, repository, operation string, call func(context.Context) error) error {
+	_, err := observe(ctx, tracer, repository, operation, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, call(ctx)
+	})
+	return err
+}
+
+func errorKind(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, ierr.ErrValidation):
+		return "validation"
+	case errors.Is(err, ierr.ErrConflict), errors.Is(err, ierr.ErrDuplicateKey):
+		return "conflict"
+	default:
+		return "internal"
+	}
+}
@@ -0,0 +1,108 @@
+package instrumented
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+)
+
+const licenseRepositoryName = "license"
+
+type LicenseRepository struct {
+	repo   license.Repository
+	tracer Tracer
+}
+
+func NewLicenseRepository(repo license.Repository, tracer Tracer) *LicenseRepository {
+	return &LicenseRepository{repo: repo, tracer: tracer}
+}
+
+var _ license.Repository = (*LicenseRepository)(nil)
+
+func (r *LicenseRepository) Create(ctx context.Context, lic *license.License) (uuid.UUID, error) {
+	return observe(ctx, r.tracer, licenseRepositoryName, "Create", func(ctx context.Context) (uuid.UUID, error) {
+		return r.repo.Create(ctx, lic)
+	})
+}
+
+func (r *LicenseRepository) CreateBatch(ctx context.Context, licenses []*license.License) error {
+	return observeErr(ctx, r.tracer, licenseRepositoryName, "CreateBatch", func(ctx context.Context) error {
+		return r.repo.CreateBatch(ctx, licenses)
+	})
+}
+
+func (r *LicenseRepository) FindByID(ctx context.Context, id uuid.UUID) (*license.License, error) {
+	return observe(ctx, r.tracer, licenseRepositoryName, "FindByID", func(ctx context.Context) (*license.License, error) {
+		return r.repo.FindByID(ctx, id)
+	})
+}
+
+func (r *LicenseRepository) FindByKey(ctx context.Context, key string) (*license.License, error) {
+	return observe(ctx, r.tracer, licenseRepositoryName, "FindByKey", func(ctx context.Context) (*license.License, error) {
+		return r.repo.FindByKey(ctx, key)
+	})
+}
+
+type listResult struct {
+	licenses []*license.License
+	total    int64
+}
+
+func (r *LicenseRepository) List(ctx context.Context, params license.ListParams) ([]*license.License, int64, error) {
+	res, err := observe(ctx, r.tracer, licenseRepositoryName, "List", func(ctx context.Context) (listResult, error) {
+		licenses, total, err := r.repo.List(ctx, params)
+		return listResult{licenses: licenses, total: total}, err
+	})
+	return res.licenses, res.total, err
+}
+
+func (r *LicenseRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status license.LicenseStatus) error {
+	return observeErr(ctx, r.tracer, licenseRepositoryName, "UpdateStatus", func(ctx context.Context) error {
+		return r.repo.UpdateStatus(ctx, id, status)
+	})
+}
+
+func (r *LicenseRepository) Update(ctx context.Context, lic *license.License) error {
+	return observeErr(ctx, r.tracer, licenseRepositoryName, "Update", func(ctx context.Context) error {
+		return r.repo.Update(ctx, lic)
+	})
+}
+
+func (r *LicenseRepository) GetDashboardSummary(ctx context.Context, expiringPeriodDays int) (*license.DashboardSummaryData, error) {
+	return observe(ctx, r.tracer, licenseRepositoryName, "GetDashboardSummary", func(ctx context.Context) (*license.DashboardSummaryData, error) {
+		return r.repo.GetDashboardSummary(ctx, expiringPeriodDays)
+	})
+}
+
+func (r *LicenseRepository) UpdateMetadata(ctx context.Context, id uuid.UUID, metadata json.RawMessage) error {
+	return observeErr(ctx, r.tracer, licenseRepositoryName, "UpdateMetadata", func(ctx context.Context) error {
+		return r.repo.UpdateMetadata(ctx, id, metadata)
+	})
+}
+
+func (r *LicenseRepository) ListRecentlyValidated(ctx context.Context, limit int) ([]*license.License, error) {
+	return observe(ctx, r.tracer, licenseRepositoryName, "ListRecentlyValidated", func(ctx context.Context) ([]*license.License, error) {
+		return r.repo.ListRecentlyValidated(ctx, limit)
+	})
+}
+
+func (r *LicenseRepository) Aggregate(ctx context.Context, params license.AggregateParams) ([]license.AggregateRow, error) {
+	return observe(ctx, r.tracer, licenseRepositoryName, "Aggregate", func(ctx context.Context) ([]license.AggregateRow, error) {
+		return r.repo.Aggregate(ctx, params)
+	})
+}
+
+func (r *LicenseRepository) RotateKey(ctx context.Context, id uuid.UUID, newKey func() (string, error), oldKeyValidUntil time.Time) (*license.License, error) {
+	return observe(ctx, r.tracer, licenseRepositoryName, "RotateKey", func(ctx context.Context) (*license.License, error) {
+		return r.repo.RotateKey(ctx, id, newKey, oldKeyValidUntil)
+	})
+}
+
+// WithLock is not observed on its own: it lasts as long as fn, whose
+// repository calls are.
+func (r *LicenseRepository) WithLock(ctx context.Context, id uuid.UUID, fn func(ctx context.Context) error) error {
+	return r.repo.WithLock(ctx, id, fn)
+}
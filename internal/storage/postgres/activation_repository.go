@@ -0,0 +1,259 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/activation"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type ActivationRepository struct {
+	db     *pgxpool.Pool
+	ids    idgen.Generator
+	logger *zap.Logger
+}
+
+func NewActivationRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *ActivationRepository {
+	return &ActivationRepository{
+		db:     db,
+		ids:    ids,
+		logger: logger.Named("ActivationRepository"),
+	}
+}
+
+var _ activation.Repository = (*ActivationRepository)(nil)
+
+const activationColumns = `id, license_id, device_id, ip, user_agent, activated_at, deactivated_at, last_seen_at, app_version, host_info`
+
+func scanActivation(row pgx.Row) (*activation.Activation, error) {
+	var a activation.Activation
+	if err := row.Scan(&a.ID, &a.LicenseID, &a.DeviceID, &a.IP, &a.UserAgent, &a.ActivatedAt, &a.DeactivatedAt, &a.LastSeenAt, &a.AppVersion, &a.Host); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Activate serializes activations of the same license with a transaction
+// scoped advisory lock, so concurrent activations from different devices
+// cannot both slip under maxActive.
+func (r *ActivationRepository) Activate(ctx context.Context, a *activation.Activation, maxActive int) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("database error starting activation: %w", mapError(err))
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, a.LicenseID.String()); err != nil {
+		r.logger.Error("Failed to lock license for activation", zap.String("license_id", a.LicenseID.String()), zap.Error(err))
+		return false, fmt.Errorf("database error locking license for activation: %w", mapError(err))
+	}
+
+	existing, err := scanActivation(tx.QueryRow(ctx,
+		`SELECT `+activationColumns+` FROM license_activations
+        WHERE license_id = $1 AND device_id = $2 AND deactivated_at IS NULL`,
+		a.LicenseID, a.DeviceID,
+	))
+	if err == nil {
+		*a = *existing
+		return false, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		r.logger.Error("Failed to look up activation", zap.String("license_id", a.LicenseID.String()), zap.Error(err))
+		return false, fmt.Errorf("database error looking up activation: %w", mapError(err))
+	}
+
+	var active int
+	if err := tx.QueryRow(ctx,
+		`SELECT COUNT(*) FROM license_activations WHERE license_id = $1 AND deactivated_at IS NULL`,
+		a.LicenseID,
+	).Scan(&active); err != nil {
+		r.logger.Error("Failed to count activations", zap.String("license_id", a.LicenseID.String()), zap.Error(err))
+		return false, fmt.Errorf("database error counting activations: %w", mapError(err))
+	}
+	if active >= maxActive {
+		return false, fmt.Errorf("%w: %d of %d seat(s) in use", activation.ErrSeatLimitExceeded, active, maxActive)
+	}
+
+	// Activations forwarded by a replica region arrive with their ID.
+	id := a.ID
+	if id == uuid.Nil {
+		id = r.ids.New()
+	}
+	err = tx.QueryRow(ctx, `
+        INSERT INTO license_activations (id, license_id, device_id, ip, user_agent)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, activated_at
+    `, id, a.LicenseID, a.DeviceID, a.IP, a.UserAgent).Scan(&a.ID, &a.ActivatedAt)
+	if err != nil {
+		r.logger.Error("Failed to insert activation", zap.String("license_id", a.LicenseID.String()), zap.Error(err))
+		return false, fmt.Errorf("database error creating activation: %w", mapError(err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("database error committing activation: %w", mapError(err))
+	}
+	return true, nil
+}
+
+func (r *ActivationRepository) Deactivate(ctx context.Context, licenseID uuid.UUID, deviceID string) (*activation.Activation, error) {
+	a, err := scanActivation(r.db.QueryRow(ctx, `
+        UPDATE license_activations SET deactivated_at = NOW()
+        WHERE license_id = $1 AND device_id = $2 AND deactivated_at IS NULL
+        RETURNING `+activationColumns,
+		licenseID, deviceID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to deactivate device", zap.String("license_id", licenseID.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error deactivating device: %w", mapError(err))
+	}
+	return a, nil
+}
+
+func (r *ActivationRepository) Revoke(ctx context.Context, licenseID, activationID uuid.UUID) (*activation.Activation, error) {
+	a, err := scanActivation(r.db.QueryRow(ctx, `
+        UPDATE license_activations SET deactivated_at = NOW()
+        WHERE id = $1 AND license_id = $2 AND deactivated_at IS NULL
+        RETURNING `+activationColumns,
+		activationID, licenseID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to revoke activation", zap.String("activation_id", activationID.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error revoking activation: %w", mapError(err))
+	}
+	return a, nil
+}
+
+// Heartbeat never moves last_seen_at back, so heartbeats forwarded late by a
+// replica region cannot hide a newer one.
+func (r *ActivationRepository) Heartbeat(ctx context.Context, hb *activation.Heartbeat) (*activation.Activation, error) {
+	a, err := scanActivation(r.db.QueryRow(ctx, `
+        UPDATE license_activations
+        SET last_seen_at = GREATEST(last_seen_at, $3), app_version = $4, host_info = $5
+        WHERE license_id = $1 AND device_id = $2 AND deactivated_at IS NULL
+        RETURNING `+activationColumns,
+		hb.LicenseID, hb.DeviceID, hb.At, hb.AppVersion, hb.Host,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to record heartbeat", zap.String("license_id", hb.LicenseID.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error recording heartbeat: %w", mapError(err))
+	}
+	return a, nil
+}
+
+func (r *ActivationRepository) ReclaimStale(ctx context.Context, licenseID uuid.UUID, before time.Time) ([]*activation.Activation, error) {
+	return r.list(ctx, licenseID, `
+        UPDATE license_activations SET deactivated_at = NOW()
+        WHERE license_id = $1 AND deactivated_at IS NULL
+          AND COALESCE(last_seen_at, activated_at) < $2
+        RETURNING `+activationColumns,
+		before,
+	)
+}
+
+func (r *ActivationRepository) CountSeenSince(ctx context.Context, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.QueryRow(ctx, `
+        SELECT COUNT(*) FROM license_activations
+        WHERE deactivated_at IS NULL AND last_seen_at >= $1
+    `, since).Scan(&count)
+	if err != nil {
+		r.logger.Error("Failed to count recently seen activations", zap.Error(err))
+		return 0, fmt.Errorf("database error counting recently seen activations: %w", mapError(err))
+	}
+	return count, nil
+}
+
+func (r *ActivationRepository) ListActive(ctx context.Context, licenseID uuid.UUID) ([]*activation.Activation, error) {
+	return r.list(ctx, licenseID, `
+        SELECT `+activationColumns+` FROM license_activations
+        WHERE license_id = $1 AND deactivated_at IS NULL
+        ORDER BY activated_at ASC
+    `)
+}
+
+func (r *ActivationRepository) List(ctx context.Context, licenseID uuid.UUID, includeInactive bool) ([]*activation.Activation, error) {
+	return r.list(ctx, licenseID, `
+        SELECT `+activationColumns+` FROM license_activations
+        WHERE license_id = $1 AND ($2 OR deactivated_at IS NULL)
+        ORDER BY activated_at DESC
+    `, includeInactive)
+}
+
+// list runs a query over the activations of one license; licenseID must be
+// its first argument.
+func (r *ActivationRepository) list(ctx context.Context, licenseID uuid.UUID, query string, args ...interface{}) ([]*activation.Activation, error) {
+	rows, err := r.db.Query(ctx, query, append([]interface{}{licenseID}, args...)...)
+	if err != nil {
+		r.logger.Error("Failed to list activations", zap.String("license_id", licenseID.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error listing activations: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	activations := make([]*activation.Activation, 0)
+	for rows.Next() {
+		a, err := scanActivation(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan activation row", zap.Error(err))
+			return nil, fmt.Errorf("database scan error listing activations: %w", err)
+		}
+		activations = append(activations, a)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("Error iterating activation rows", zap.Error(err))
+		return nil, fmt.Errorf("database error iterating activations: %w", mapError(err))
+	}
+	return activations, nil
+}
+
+func (r *ActivationRepository) ListLicenseIDs(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx, `
+        SELECT DISTINCT license_id FROM license_activations
+        WHERE license_id > $1
+        ORDER BY license_id
+        LIMIT $2
+    `, after, limit)
+	if err != nil {
+		r.logger.Error("Failed to list licenses with activations", zap.Error(err))
+		return nil, fmt.Errorf("database error listing licenses with activations: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	ids := make([]uuid.UUID, 0, limit)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("database scan error listing licenses with activations: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("database error iterating licenses with activations: %w", mapError(err))
+	}
+	return ids, nil
+}
+
+func (r *ActivationRepository) DeleteByLicense(ctx context.Context, licenseID uuid.UUID) (int64, error) {
+	cmdTag, err := r.db.Exec(ctx, `DELETE FROM license_activations WHERE license_id = $1`, licenseID)
+	if err != nil {
+		r.logger.Error("Failed to delete activations", zap.String("license_id", licenseID.String()), zap.Error(err))
+		return 0, fmt.Errorf("database error deleting activations: %w", mapError(err))
+	}
+	return cmdTag.RowsAffected(), nil
+}
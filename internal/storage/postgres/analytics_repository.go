@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/analytics"
+	"go.uber.org/zap"
+)
+
+// AnalyticsRepository aggregates validation events and activations, which
+// live in the primary database even when licenses are sharded.
+type AnalyticsRepository struct {
+	db     *pgxpool.Pool
+	logger *zap.Logger
+}
+
+func NewAnalyticsRepository(db *pgxpool.Pool, logger *zap.Logger) *AnalyticsRepository {
+	return &AnalyticsRepository{
+		db:     db,
+		logger: logger.Named("AnalyticsRepository"),
+	}
+}
+
+var _ analytics.Repository = (*AnalyticsRepository)(nil)
+
+func (r *AnalyticsRepository) LicenseActivity(ctx context.Context, from, to time.Time, fn func(*analytics.LicenseActivity) error) error {
+	query := `
+        WITH validations AS (
+            SELECT license_id,
+                   MAX(product_name) AS product_name,
+                   SUM(sample_rate) AS validations,
+                   COUNT(*) FILTER (WHERE NOT valid) AS failed_validations,
+                   COUNT(DISTINCT NULLIF(device_id, '')) AS distinct_devices,
+                   COUNT(DISTINCT NULLIF(country, '')) AS countries
+            FROM validation_events
+            WHERE license_id IS NOT NULL AND created_at >= $1 AND created_at < $2
+            GROUP BY license_id
+        ), devices AS (
+            SELECT license_id,
+                   COUNT(*) FILTER (WHERE activated_at >= $1) AS activated,
+                   COUNT(*) FILTER (WHERE deactivated_at < $2) AS deactivated,
+                   COUNT(*) FILTER (WHERE deactivated_at IS NULL OR deactivated_at >= $2) AS active
+            FROM license_activations
+            WHERE activated_at < $2 AND (deactivated_at IS NULL OR deactivated_at >= $1)
+            GROUP BY license_id
+        )
+        SELECT COALESCE(v.license_id, d.license_id), COALESCE(v.product_name, ''),
+               COALESCE(v.validations, 0), COALESCE(v.failed_validations, 0),
+               COALESCE(v.distinct_devices, 0), COALESCE(v.countries, 0),
+               COALESCE(d.activated, 0), COALESCE(d.deactivated, 0), COALESCE(d.active, 0)
+        FROM validations v
+        FULL OUTER JOIN devices d ON d.license_id = v.license_id
+        ORDER BY 1`
+
+	err := streamCursor(ctx, r.db, query, []interface{}{from, to}, func(rows pgx.Rows) error {
+		var a analytics.LicenseActivity
+		if err := rows.Scan(&a.LicenseID, &a.ProductName, &a.Validations, &a.FailedValidations,
+			&a.DistinctDevices, &a.Countries, &a.DevicesActivated, &a.DevicesDeactivated, &a.ActiveDevices); err != nil {
+			return fmt.Errorf("database error scanning license activity: %w", mapError(err))
+		}
+		return fn(&a)
+	})
+	if err != nil && ctx.Err() == nil {
+		r.logger.Error("License activity aggregation failed", zap.Time("from", from), zap.Time("to", to), zap.Error(err))
+	}
+	return err
+}
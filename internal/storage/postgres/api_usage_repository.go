@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/apiusage"
+	"go.uber.org/zap"
+)
+
+type APIUsageRepository struct {
+	db     *pgxpool.Pool
+	logger *zap.Logger
+}
+
+func NewAPIUsageRepository(db *pgxpool.Pool, logger *zap.Logger) *APIUsageRepository {
+	return &APIUsageRepository{
+		db:     db,
+		logger: logger.Named("APIUsageRepository"),
+	}
+}
+
+var _ apiusage.Repository = (*APIUsageRepository)(nil)
+
+// Add upserts the counters in key order, so that instances flushing at the
+// same time lock rows in the same order and cannot deadlock.
+func (r *APIUsageRepository) Add(ctx context.Context, counters []*apiusage.Counter) error {
+	if len(counters) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(counters)
+	slices.SortFunc(sorted, func(a, b *apiusage.Counter) int {
+		return cmp.Or(
+			a.Month.Compare(b.Month),
+			cmp.Compare(a.OrgID, b.OrgID),
+			cmp.Compare(a.API, b.API),
+			bytes.Compare(a.APIKeyID[:], b.APIKeyID[:]),
+		)
+	})
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("database error starting API usage update: %w", mapError(err))
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	batch := &pgx.Batch{}
+	for _, c := range sorted {
+		batch.Queue(`
+            INSERT INTO api_usage (month, org_id, api, api_key_id, calls, failed_calls)
+            VALUES ($1, $2, $3, $4, $5, $6)
+            ON CONFLICT (month, org_id, api, api_key_id) DO UPDATE
+            SET calls = api_usage.calls + EXCLUDED.calls,
+                failed_calls = api_usage.failed_calls + EXCLUDED.failed_calls,
+                updated_at = NOW()
+        `, c.Month, c.OrgID, c.API, c.APIKeyID, c.Calls, c.FailedCalls)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		r.logger.Error("Failed to add API usage", zap.Int("counters", len(counters)), zap.Error(err))
+		return fmt.Errorf("database error adding API usage: %w", mapError(err))
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("database error committing API usage: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *APIUsageRepository) ListMonth(ctx context.Context, month time.Time, orgID *string) ([]*apiusage.Counter, error) {
+	rows, err := r.db.Query(ctx, `
+        SELECT month, org_id, api, api_key_id, calls, failed_calls
+        FROM api_usage
+        WHERE month = $1 AND ($2::text IS NULL OR org_id = $2)
+        ORDER BY org_id, api, api_key_id
+    `, month, orgID)
+	if err != nil {
+		r.logger.Error("Failed to list API usage", zap.Time("month", month), zap.Error(err))
+		return nil, fmt.Errorf("database error listing API usage: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	counters := []*apiusage.Counter{}
+	for rows.Next() {
+		var c apiusage.Counter
+		if err := rows.Scan(&c.Month, &c.OrgID, &c.API, &c.APIKeyID, &c.Calls, &c.FailedCalls); err != nil {
+			return nil, fmt.Errorf("database error scanning API usage: %w", mapError(err))
+		}
+		counters = append(counters, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("database iteration error listing API usage: %w", err)
+	}
+	return counters, nil
+}
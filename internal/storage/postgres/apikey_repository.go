@@ -8,22 +8,24 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/makkenzo/license-service-api/internal/domain/apikey"
+	"github.com/makkenzo/license-service-api/internal/idgen"
 	"github.com/makkenzo/license-service-api/internal/ierr"
 	"go.uber.org/zap"
 )
 
 type APIKeyRepository struct {
 	db     *pgxpool.Pool
+	ids    idgen.Generator
 	logger *zap.Logger
 }
 
-func NewAPIKeyRepository(db *pgxpool.Pool, logger *zap.Logger) *APIKeyRepository {
+func NewAPIKeyRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *APIKeyRepository {
 	return &APIKeyRepository{
 		db:     db,
+		ids:    ids,
 		logger: logger.Named("APIKeyRepository"),
 	}
 }
@@ -32,7 +34,7 @@ var _ apikey.Repository = (*APIKeyRepository)(nil)
 
 func (r *APIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*apikey.APIKey, error) {
 	query := `
-		SELECT id, key_hash, prefix, description, product_id, is_enabled, created_at, last_used_at
+		SELECT id, key_hash, prefix, description, product_id, org_id, scopes, created_by, is_enabled, created_at, last_used_at
 		FROM api_keys
 		WHERE prefix = $1 AND is_enabled = TRUE
 	`
@@ -48,6 +50,9 @@ func (r *APIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*ap
 		&key.Prefix,
 		&key.Description,
 		&productID,
+		&key.OrgID,
+		&key.Scopes,
+		&key.CreatedBy,
 		&key.IsEnabled,
 		&key.CreatedAt,
 		&lastUsed,
@@ -59,7 +64,7 @@ func (r *APIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*ap
 			return nil, ierr.ErrAPIKeyNotFound
 		}
 		r.logger.Error("Failed to find api key by prefix", zap.String("prefix", prefix), zap.Error(err))
-		return nil, fmt.Errorf("db error finding api key: %w", err)
+		return nil, fmt.Errorf("db error finding api key: %w", mapError(err))
 	}
 
 	if productID.Valid {
@@ -74,11 +79,15 @@ func (r *APIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*ap
 
 func (r *APIKeyRepository) Create(ctx context.Context, key *apikey.APIKey) (uuid.UUID, error) {
 	query := `
-		INSERT INTO api_keys (key_hash, prefix, description, product_id, is_enabled)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO api_keys (id, key_hash, prefix, description, product_id, org_id, scopes, created_by, is_enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id
 	`
 	var insertedID uuid.UUID
+	scopes := key.Scopes
+	if len(scopes) == 0 {
+		scopes = apikey.AllScopes
+	}
 	var productIDArg interface{}
 
 	if key.ProductID != uuid.Nil {
@@ -88,23 +97,25 @@ func (r *APIKeyRepository) Create(ctx context.Context, key *apikey.APIKey) (uuid
 	}
 
 	err := r.db.QueryRow(ctx, query,
+		r.ids.New(),
 		key.KeyHash,
 		key.Prefix,
 		key.Description,
 		productIDArg,
+		key.OrgID,
+		scopes,
+		key.CreatedBy,
 		key.IsEnabled,
 	).Scan(&insertedID)
 
 	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-
-			constraint := pgErr.ConstraintName
+		err = mapError(err)
+		if errors.Is(err, ierr.ErrDuplicateKey) {
 			r.logger.Warn("Failed to create API key due to unique constraint violation",
-				zap.String("constraint", constraint),
 				zap.String("prefix", key.Prefix),
+				zap.Error(err),
 			)
-			return uuid.Nil, fmt.Errorf("api key constraint violation (%s)", constraint)
+			return uuid.Nil, fmt.Errorf("api key constraint violation: %w", err)
 		}
 		r.logger.Error("Failed to create api key in database", zap.Error(err))
 		return uuid.Nil, fmt.Errorf("db error creating api key: %w", err)
@@ -119,7 +130,7 @@ func (r *APIKeyRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID, las
 	cmdTag, err := r.db.Exec(ctx, query, lastUsed, id)
 	if err != nil {
 		r.logger.Error("Failed to update api key last_used_at", zap.String("id", id.String()), zap.Error(err))
-		return fmt.Errorf("db error updating last used time: %w", err)
+		return fmt.Errorf("db error updating last used time: %w", mapError(err))
 	}
 	if cmdTag.RowsAffected() == 0 {
 
@@ -131,14 +142,14 @@ func (r *APIKeyRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID, las
 
 func (r *APIKeyRepository) List(ctx context.Context) ([]*apikey.APIKey, error) {
 	query := `
-		SELECT id, key_hash, prefix, description, product_id, is_enabled, created_at, last_used_at
+		SELECT id, key_hash, prefix, description, product_id, org_id, scopes, created_by, is_enabled, created_at, last_used_at
 		FROM api_keys
 		ORDER BY created_at DESC
 	`
 	rows, err := r.db.Query(ctx, query)
 	if err != nil {
 		r.logger.Error("Failed to query list of api keys", zap.Error(err))
-		return nil, fmt.Errorf("db error listing api keys: %w", err)
+		return nil, fmt.Errorf("db error listing api keys: %w", mapError(err))
 	}
 	defer rows.Close()
 
@@ -150,7 +161,7 @@ func (r *APIKeyRepository) List(ctx context.Context) ([]*apikey.APIKey, error) {
 
 		err := rows.Scan(
 			&key.ID, &key.KeyHash, &key.Prefix, &key.Description,
-			&productID, &key.IsEnabled, &key.CreatedAt, &lastUsed,
+			&productID, &key.OrgID, &key.Scopes, &key.CreatedBy, &key.IsEnabled, &key.CreatedAt, &lastUsed,
 		)
 		if err != nil {
 			r.logger.Error("Failed to scan api key row during list", zap.Error(err))
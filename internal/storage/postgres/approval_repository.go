@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/approval"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type ApprovalRepository struct {
+	db     *pgxpool.Pool
+	ids    idgen.Generator
+	logger *zap.Logger
+}
+
+func NewApprovalRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *ApprovalRepository {
+	return &ApprovalRepository{
+		db:     db,
+		ids:    ids,
+		logger: logger.Named("ApprovalRepository"),
+	}
+}
+
+var _ approval.Repository = (*ApprovalRepository)(nil)
+
+const approvalColumns = `id, license_id, changes, protected_keys, requested_by, base_updated_at, status, decided_by, decided_at, created_at, expires_at`
+
+func scanApproval(row pgx.Row) (*approval.Request, error) {
+	var a approval.Request
+	err := row.Scan(&a.ID, &a.LicenseID, &a.Changes, &a.ProtectedKeys, &a.RequestedBy, &a.BaseUpdatedAt,
+		&a.Status, &a.DecidedBy, &a.DecidedAt, &a.CreatedAt, &a.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *ApprovalRepository) Create(ctx context.Context, a *approval.Request) error {
+	a.ID = r.ids.New()
+	a.Status = approval.StatusPending
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO license_change_approvals (id, license_id, changes, protected_keys, requested_by, base_updated_at, expires_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING created_at
+    `, a.ID, a.LicenseID, a.Changes, a.ProtectedKeys, a.RequestedBy, a.BaseUpdatedAt, a.ExpiresAt).Scan(&a.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create approval request", zap.String("license_id", a.LicenseID.String()), zap.Error(err))
+		return fmt.Errorf("database error creating approval request: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *ApprovalRepository) FindByID(ctx context.Context, id uuid.UUID) (*approval.Request, error) {
+	a, err := scanApproval(r.db.QueryRow(ctx, `SELECT `+approvalColumns+` FROM license_change_approvals WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find approval request", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error finding approval request: %w", mapError(err))
+	}
+	return a, nil
+}
+
+func (r *ApprovalRepository) List(ctx context.Context, params approval.ListParams) ([]*approval.Request, int64, error) {
+	conditions := make([]string, 0, 2)
+	args := make([]interface{}, 0, 4)
+	if params.Status != nil {
+		args = append(args, *params.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if params.LicenseID != nil {
+		args = append(args, *params.LicenseID)
+		conditions = append(conditions, fmt.Sprintf("license_id = $%d", len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM license_change_approvals`+where, args...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count approval requests", zap.Error(err))
+		return nil, 0, fmt.Errorf("database error counting approval requests: %w", mapError(err))
+	}
+	if total == 0 {
+		return []*approval.Request{}, 0, nil
+	}
+
+	query := `SELECT ` + approvalColumns + ` FROM license_change_approvals` + where + fmt.Sprintf(`
+        ORDER BY created_at DESC, id DESC
+        LIMIT $%d OFFSET $%d
+    `, len(args)+1, len(args)+2)
+	rows, err := r.db.Query(ctx, query, append(args, params.Limit, params.Offset)...)
+	if err != nil {
+		r.logger.Error("Failed to list approval requests", zap.Error(err))
+		return nil, 0, fmt.Errorf("database error listing approval requests: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	requests := make([]*approval.Request, 0, params.Limit)
+	for rows.Next() {
+		a, err := scanApproval(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("database error scanning approval request: %w", mapError(err))
+		}
+		requests = append(requests, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("database iteration error listing approval requests: %w", err)
+	}
+	return requests, total, nil
+}
+
+func (r *ApprovalRepository) Decide(ctx context.Context, id uuid.UUID, status approval.Status, decidedBy string) (*approval.Request, error) {
+	a, err := scanApproval(r.db.QueryRow(ctx, `
+        UPDATE license_change_approvals
+        SET status = $2, decided_by = $3, decided_at = NOW()
+        WHERE id = $1 AND status = 'pending'
+        RETURNING `+approvalColumns, id, status, decidedBy))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: approval request was already decided", ierr.ErrConflict)
+		}
+		r.logger.Error("Failed to decide approval request", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error deciding approval request: %w", mapError(err))
+	}
+	return a, nil
+}
+
+func (r *ApprovalRepository) Reopen(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `
+        UPDATE license_change_approvals
+        SET status = 'pending', decided_by = NULL, decided_at = NULL
+        WHERE id = $1
+    `, id)
+	if err != nil {
+		r.logger.Error("Failed to reopen approval request", zap.String("id", id.String()), zap.Error(err))
+		return fmt.Errorf("database error reopening approval request: %w", mapError(err))
+	}
+	return nil
+}
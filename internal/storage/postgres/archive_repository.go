@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/archive"
+	"go.uber.org/zap"
+)
+
+type ArchiveRepository struct {
+	db     *pgxpool.Pool
+	logger *zap.Logger
+}
+
+func NewArchiveRepository(db *pgxpool.Pool, logger *zap.Logger) *ArchiveRepository {
+	return &ArchiveRepository{
+		db:     db,
+		logger: logger.Named("ArchiveRepository"),
+	}
+}
+
+var _ archive.EventRepository = (*ArchiveRepository)(nil)
+
+// archivedEventTables are the tables whose rows move with their license. Each
+// has an archive table named with an _archive suffix and the same columns.
+var archivedEventTables = []string{"license_activations", "license_status_history", "validation_events"}
+
+func (r *ArchiveRepository) Archive(ctx context.Context, licenseIDs []uuid.UUID) (archive.EventCounts, error) {
+	var counts archive.EventCounts
+	moved, err := r.move(ctx, licenseIDs, "", "_archive")
+	if err != nil {
+		return counts, err
+	}
+	counts.Activations, counts.StatusChanges, counts.ValidationEvents = moved[0], moved[1], moved[2]
+	return counts, nil
+}
+
+func (r *ArchiveRepository) Restore(ctx context.Context, licenseIDs []uuid.UUID) error {
+	_, err := r.move(ctx, licenseIDs, "_archive", "")
+	return err
+}
+
+// move moves the rows of licenseIDs from each event table with suffix from
+// to the one with suffix to, in one transaction, and returns the number of
+// rows moved per table.
+func (r *ArchiveRepository) move(ctx context.Context, licenseIDs []uuid.UUID, from, to string) ([]int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("database error starting event archive: %w", mapError(err))
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	moved := make([]int64, len(archivedEventTables))
+	for i, table := range archivedEventTables {
+		query := `
+        WITH moved AS (
+            DELETE FROM ` + pgx.Identifier{table + from}.Sanitize() + ` WHERE license_id = ANY($1)
+            RETURNING *
+        )
+        INSERT INTO ` + pgx.Identifier{table + to}.Sanitize() + `
+        SELECT * FROM moved
+    `
+		tag, err := tx.Exec(ctx, query, licenseIDs)
+		if err != nil {
+			r.logger.Error("Failed to move license events",
+				zap.String("from", table+from), zap.String("to", table+to), zap.Error(err))
+			return nil, fmt.Errorf("database error moving %s events: %w", table+from, mapError(err))
+		}
+		moved[i] = tag.RowsAffected()
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("database error committing event archive: %w", mapError(err))
+	}
+	return moved, nil
+}
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/audit"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type AuditRepository struct {
+	db     *pgxpool.Pool
+	ids    idgen.Generator
+	logger *zap.Logger
+}
+
+func NewAuditRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *AuditRepository {
+	return &AuditRepository{
+		db:     db,
+		ids:    ids,
+		logger: logger.Named("AuditRepository"),
+	}
+}
+
+var _ audit.Repository = (*AuditRepository)(nil)
+
+func (r *AuditRepository) Record(ctx context.Context, entry *audit.Entry) error {
+	query := `
+        INSERT INTO audit_log (id, entity_type, entity_id, action, actor_type, actor_id, source_ip, user_agent, before, after)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+        RETURNING id, created_at
+    `
+	err := r.db.QueryRow(ctx, query,
+		r.ids.New(), entry.EntityType, entry.EntityID, entry.Action,
+		entry.Actor.Type, entry.Actor.ID, entry.Actor.IP, entry.Actor.UserAgent,
+		nullableJSON(entry.Before), nullableJSON(entry.After),
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to record audit entry",
+			zap.String("entity_type", entry.EntityType),
+			zap.String("entity_id", entry.EntityID.String()),
+			zap.String("action", entry.Action),
+			zap.Error(err),
+		)
+		return fmt.Errorf("database error recording audit entry: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *AuditRepository) FindByID(ctx context.Context, id uuid.UUID) (*audit.Entry, error) {
+	query := `
+        SELECT id, entity_type, entity_id, action, actor_type, actor_id, source_ip, user_agent, before, after, created_at
+        FROM audit_log
+        WHERE id = $1
+    `
+	var entry audit.Entry
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&entry.ID, &entry.EntityType, &entry.EntityID, &entry.Action,
+		&entry.Actor.Type, &entry.Actor.ID, &entry.Actor.IP, &entry.Actor.UserAgent,
+		&entry.Before, &entry.After, &entry.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find audit entry", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error finding audit entry: %w", mapError(err))
+	}
+	return &entry, nil
+}
+
+func (r *AuditRepository) List(ctx context.Context, params audit.ListParams) ([]*audit.Entry, int64, error) {
+	conditions := make([]string, 0, 7)
+	args := make([]interface{}, 0, 9)
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if params.EntityType != nil {
+		add("entity_type = $%d", *params.EntityType)
+	}
+	if params.EntityID != nil {
+		add("entity_id = $%d", *params.EntityID)
+	}
+	if params.Action != nil {
+		add("action = $%d", *params.Action)
+	}
+	if params.ActorType != nil {
+		add("actor_type = $%d", *params.ActorType)
+	}
+	if params.ActorID != nil {
+		add("actor_id = $%d", *params.ActorID)
+	}
+	if params.CreatedAfter != nil {
+		add("created_at >= $%d", *params.CreatedAfter)
+	}
+	if params.CreatedBefore != nil {
+		add("created_at < $%d", *params.CreatedBefore)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM audit_log`+where, args...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count audit entries", zap.Error(err))
+		return nil, 0, fmt.Errorf("database error counting audit entries: %w", mapError(err))
+	}
+	if total == 0 {
+		return []*audit.Entry{}, 0, nil
+	}
+
+	query := `
+        SELECT id, entity_type, entity_id, action, actor_type, actor_id, source_ip, user_agent, before, after, created_at
+        FROM audit_log` + where + fmt.Sprintf(`
+        ORDER BY created_at DESC, id DESC
+        LIMIT $%d OFFSET $%d
+    `, len(args)+1, len(args)+2)
+	rows, err := r.db.Query(ctx, query, append(args, params.Limit, params.Offset)...)
+	if err != nil {
+		r.logger.Error("Failed to list audit entries", zap.Error(err))
+		return nil, 0, fmt.Errorf("database error listing audit entries: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	entries := make([]*audit.Entry, 0, params.Limit)
+	for rows.Next() {
+		var entry audit.Entry
+		if err := rows.Scan(
+			&entry.ID, &entry.EntityType, &entry.EntityID, &entry.Action,
+			&entry.Actor.Type, &entry.Actor.ID, &entry.Actor.IP, &entry.Actor.UserAgent,
+			&entry.Before, &entry.After, &entry.CreatedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("database error scanning audit entry: %w", mapError(err))
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("database iteration error listing audit entries: %w", err)
+	}
+	return entries, total, nil
+}
+
+func nullableJSON(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return data
+}
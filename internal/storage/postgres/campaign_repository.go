@@ -0,0 +1,308 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/campaign"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type CampaignRepository struct {
+	db     *pgxpool.Pool
+	ids    idgen.Generator
+	logger *zap.Logger
+}
+
+func NewCampaignRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *CampaignRepository {
+	return &CampaignRepository{
+		db:     db,
+		ids:    ids,
+		logger: logger.Named("CampaignRepository"),
+	}
+}
+
+var _ campaign.Repository = (*CampaignRepository)(nil)
+
+const campaignColumns = `id, name, subject, message, filter, status, requested_by, error, created_at, started_at, finished_at`
+
+func scanCampaign(row pgx.Row) (*campaign.Campaign, error) {
+	var c campaign.Campaign
+	if err := row.Scan(&c.ID, &c.Name, &c.Subject, &c.Message, &c.Filter, &c.Status, &c.RequestedBy, &c.Error,
+		&c.CreatedAt, &c.StartedAt, &c.FinishedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *CampaignRepository) Create(ctx context.Context, c *campaign.Campaign) error {
+	c.ID = r.ids.New()
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO email_campaigns (id, name, subject, message, filter, requested_by)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING status, created_at
+    `, c.ID, c.Name, c.Subject, c.Message, c.Filter, c.RequestedBy).Scan(&c.Status, &c.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create e-mail campaign", zap.String("name", c.Name), zap.Error(err))
+		return fmt.Errorf("database error creating e-mail campaign: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *CampaignRepository) FindByID(ctx context.Context, id uuid.UUID) (*campaign.Campaign, error) {
+	c, err := scanCampaign(r.db.QueryRow(ctx, `SELECT `+campaignColumns+` FROM email_campaigns WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find e-mail campaign", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error finding e-mail campaign: %w", mapError(err))
+	}
+	return c, nil
+}
+
+func (r *CampaignRepository) List(ctx context.Context, limit int) ([]*campaign.Campaign, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+campaignColumns+` FROM email_campaigns ORDER BY created_at DESC LIMIT $1`, limit)
+	if err != nil {
+		r.logger.Error("Failed to list e-mail campaigns", zap.Error(err))
+		return nil, fmt.Errorf("database error listing e-mail campaigns: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	campaigns := make([]*campaign.Campaign, 0)
+	for rows.Next() {
+		c, err := scanCampaign(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan e-mail campaign row", zap.Error(err))
+			return nil, fmt.Errorf("database scan error listing e-mail campaigns: %w", err)
+		}
+		campaigns = append(campaigns, c)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("Error iterating e-mail campaign rows", zap.Error(err))
+		return nil, fmt.Errorf("database error iterating e-mail campaigns: %w", mapError(err))
+	}
+	return campaigns, nil
+}
+
+func (r *CampaignRepository) SetStatus(ctx context.Context, id uuid.UUID, status campaign.Status, reason string, from ...campaign.Status) error {
+	fromStatuses := make([]string, len(from))
+	for i, s := range from {
+		fromStatuses[i] = string(s)
+	}
+	cmdTag, err := r.db.Exec(ctx, `
+        UPDATE email_campaigns
+        SET status = $2,
+            error = $3,
+            started_at = CASE WHEN $2 = 'sending' THEN COALESCE(started_at, NOW()) ELSE started_at END,
+            finished_at = CASE WHEN $2 IN ('completed', 'cancelled', 'failed') THEN NOW() ELSE finished_at END
+        WHERE id = $1 AND status = ANY($4)
+    `, id, string(status), reason, fromStatuses)
+	if err != nil {
+		r.logger.Error("Failed to set e-mail campaign status", zap.String("id", id.String()), zap.String("status", string(status)), zap.Error(err))
+		return fmt.Errorf("database error setting e-mail campaign status: %w", mapError(err))
+	}
+	if cmdTag.RowsAffected() > 0 {
+		return nil
+	}
+	c, err := r.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: e-mail campaign is %s", ierr.ErrConflict, c.Status)
+}
+
+func (r *CampaignRepository) AddRecipients(ctx context.Context, recipients []*campaign.Recipient) error {
+	if len(recipients) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(recipients))
+	campaignIDs := make([]uuid.UUID, len(recipients))
+	emails := make([]string, len(recipients))
+	licenseIDs := make([]uuid.UUID, len(recipients))
+	batches := make([]int32, len(recipients))
+	for i, rcpt := range recipients {
+		rcpt.ID = r.ids.New()
+		ids[i], campaignIDs[i], emails[i], licenseIDs[i], batches[i] = rcpt.ID, rcpt.CampaignID, rcpt.Email, rcpt.LicenseID, int32(rcpt.Batch)
+	}
+	_, err := r.db.Exec(ctx, `
+        INSERT INTO email_campaign_recipients (id, campaign_id, email, license_id, batch)
+        SELECT * FROM unnest($1::uuid[], $2::uuid[], $3::text[], $4::uuid[], $5::int[])
+        ON CONFLICT (campaign_id, email) DO NOTHING
+    `, ids, campaignIDs, emails, licenseIDs, batches)
+	if err != nil {
+		r.logger.Error("Failed to add e-mail campaign recipients", zap.String("campaign_id", recipients[0].CampaignID.String()), zap.Int("recipients", len(recipients)), zap.Error(err))
+		return fmt.Errorf("database error adding e-mail campaign recipients: %w", mapError(err))
+	}
+	return nil
+}
+
+const campaignRecipientColumns = `id, campaign_id, email, license_id, batch, status, attempts, last_error, sent_at, created_at, updated_at`
+
+func scanCampaignRecipient(row pgx.Row) (*campaign.Recipient, error) {
+	var rcpt campaign.Recipient
+	if err := row.Scan(&rcpt.ID, &rcpt.CampaignID, &rcpt.Email, &rcpt.LicenseID, &rcpt.Batch, &rcpt.Status,
+		&rcpt.Attempts, &rcpt.LastError, &rcpt.SentAt, &rcpt.CreatedAt, &rcpt.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &rcpt, nil
+}
+
+func (r *CampaignRepository) FindRecipient(ctx context.Context, id uuid.UUID) (*campaign.Recipient, error) {
+	rcpt, err := scanCampaignRecipient(r.db.QueryRow(ctx, `SELECT `+campaignRecipientColumns+` FROM email_campaign_recipients WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find e-mail campaign recipient", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error finding e-mail campaign recipient: %w", mapError(err))
+	}
+	return rcpt, nil
+}
+
+func (r *CampaignRepository) BatchRecipients(ctx context.Context, campaignID uuid.UUID, batch int) ([]*campaign.Recipient, error) {
+	rows, err := r.db.Query(ctx, `
+        SELECT `+campaignRecipientColumns+`
+        FROM email_campaign_recipients
+        WHERE campaign_id = $1 AND batch = $2 AND status = 'pending'
+        ORDER BY email
+    `, campaignID, batch)
+	if err != nil {
+		r.logger.Error("Failed to list e-mail campaign batch", zap.String("campaign_id", campaignID.String()), zap.Int("batch", batch), zap.Error(err))
+		return nil, fmt.Errorf("database error listing e-mail campaign batch: %w", mapError(err))
+	}
+	return r.collectRecipients(rows)
+}
+
+func (r *CampaignRepository) collectRecipients(rows pgx.Rows) ([]*campaign.Recipient, error) {
+	defer rows.Close()
+	recipients := make([]*campaign.Recipient, 0)
+	for rows.Next() {
+		rcpt, err := scanCampaignRecipient(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan e-mail campaign recipient row", zap.Error(err))
+			return nil, fmt.Errorf("database scan error listing e-mail campaign recipients: %w", err)
+		}
+		recipients = append(recipients, rcpt)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("Error iterating e-mail campaign recipient rows", zap.Error(err))
+		return nil, fmt.Errorf("database error iterating e-mail campaign recipients: %w", mapError(err))
+	}
+	return recipients, nil
+}
+
+func (r *CampaignRepository) UpdateRecipient(ctx context.Context, rcpt *campaign.Recipient) error {
+	err := r.db.QueryRow(ctx, `
+        UPDATE email_campaign_recipients SET status = $2, attempts = $3, last_error = $4, sent_at = $5
+        WHERE id = $1
+        RETURNING updated_at
+    `, rcpt.ID, rcpt.Status, rcpt.Attempts, rcpt.LastError, rcpt.SentAt).Scan(&rcpt.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to update e-mail campaign recipient", zap.String("id", rcpt.ID.String()), zap.Error(err))
+		return fmt.Errorf("database error updating e-mail campaign recipient: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *CampaignRepository) ListRecipients(ctx context.Context, campaignID uuid.UUID, q campaign.RecipientQuery) ([]*campaign.Recipient, int64, error) {
+	where, args := ` WHERE campaign_id = $1`, []interface{}{campaignID}
+	if q.Status != nil {
+		args = append(args, *q.Status)
+		where += ` AND status = $2`
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM email_campaign_recipients`+where, args...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count e-mail campaign recipients", zap.String("campaign_id", campaignID.String()), zap.Error(err))
+		return nil, 0, fmt.Errorf("database error counting e-mail campaign recipients: %w", mapError(err))
+	}
+	if total == 0 {
+		return []*campaign.Recipient{}, 0, nil
+	}
+
+	query := `
+        SELECT ` + campaignRecipientColumns + `
+        FROM email_campaign_recipients` + where + fmt.Sprintf(`
+        ORDER BY batch, email
+        LIMIT $%d OFFSET $%d
+    `, len(args)+1, len(args)+2)
+	rows, err := r.db.Query(ctx, query, append(args, q.Limit, q.Offset)...)
+	if err != nil {
+		r.logger.Error("Failed to list e-mail campaign recipients", zap.String("campaign_id", campaignID.String()), zap.Error(err))
+		return nil, 0, fmt.Errorf("database error listing e-mail campaign recipients: %w", mapError(err))
+	}
+	recipients, err := r.collectRecipients(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return recipients, total, nil
+}
+
+func (r *CampaignRepository) CountRecipients(ctx context.Context, campaignID uuid.UUID) (map[campaign.RecipientStatus]int64, error) {
+	rows, err := r.db.Query(ctx, `SELECT status, COUNT(*) FROM email_campaign_recipients WHERE campaign_id = $1 GROUP BY status`, campaignID)
+	if err != nil {
+		r.logger.Error("Failed to count e-mail campaign recipients", zap.String("campaign_id", campaignID.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error counting e-mail campaign recipients: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	counts := make(map[campaign.RecipientStatus]int64)
+	for rows.Next() {
+		var status campaign.RecipientStatus
+		var n int64
+		if err := rows.Scan(&status, &n); err != nil {
+			return nil, fmt.Errorf("database scan error counting e-mail campaign recipients: %w", err)
+		}
+		counts[status] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("database error counting e-mail campaign recipients: %w", mapError(err))
+	}
+	return counts, nil
+}
+
+func (r *CampaignRepository) Unsubscribe(ctx context.Context, email string, campaignID uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `
+        INSERT INTO email_unsubscribes (email, campaign_id) VALUES ($1, $2)
+        ON CONFLICT (email) DO NOTHING
+    `, email, campaignID)
+	if err != nil {
+		r.logger.Error("Failed to record e-mail unsubscribe", zap.String("campaign_id", campaignID.String()), zap.Error(err))
+		return fmt.Errorf("database error recording e-mail unsubscribe: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *CampaignRepository) Unsubscribed(ctx context.Context, emails []string) (map[string]bool, error) {
+	unsubscribed := make(map[string]bool)
+	if len(emails) == 0 {
+		return unsubscribed, nil
+	}
+	rows, err := r.db.Query(ctx, `SELECT email FROM email_unsubscribes WHERE email = ANY($1)`, emails)
+	if err != nil {
+		r.logger.Error("Failed to look up e-mail unsubscribes", zap.Int("emails", len(emails)), zap.Error(err))
+		return nil, fmt.Errorf("database error looking up e-mail unsubscribes: %w", mapError(err))
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, fmt.Errorf("database error scanning e-mail unsubscribe: %w", mapError(err))
+		}
+		unsubscribed[email] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("database iteration error looking up e-mail unsubscribes: %w", err)
+	}
+	return unsubscribed, nil
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/comment"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type CommentRepository struct {
+	db     *pgxpool.Pool
+	ids    idgen.Generator
+	logger *zap.Logger
+}
+
+func NewCommentRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *CommentRepository {
+	return &CommentRepository{
+		db:     db,
+		ids:    ids,
+		logger: logger.Named("CommentRepository"),
+	}
+}
+
+var _ comment.Repository = (*CommentRepository)(nil)
+
+const commentColumns = `id, license_id, author, author_name, body, created_at`
+
+func scanComment(row pgx.Row) (*comment.Comment, error) {
+	var c comment.Comment
+	if err := row.Scan(&c.ID, &c.LicenseID, &c.Author, &c.AuthorName, &c.Body, &c.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *CommentRepository) Create(ctx context.Context, c *comment.Comment) error {
+	c.ID = r.ids.New()
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO license_comments (id, license_id, author, author_name, body)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING created_at
+    `, c.ID, c.LicenseID, c.Author, c.AuthorName, c.Body).Scan(&c.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create license comment", zap.String("license_id", c.LicenseID.String()), zap.Error(err))
+		return fmt.Errorf("database error creating license comment: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *CommentRepository) FindByID(ctx context.Context, licenseID, id uuid.UUID) (*comment.Comment, error) {
+	c, err := scanComment(r.db.QueryRow(ctx, `SELECT `+commentColumns+` FROM license_comments WHERE id = $1 AND license_id = $2`, id, licenseID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find license comment", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error finding license comment: %w", mapError(err))
+	}
+	return c, nil
+}
+
+func (r *CommentRepository) List(ctx context.Context, licenseID uuid.UUID, limit, offset int) ([]*comment.Comment, int64, error) {
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM license_comments WHERE license_id = $1`, licenseID).Scan(&total); err != nil {
+		r.logger.Error("Failed to count license comments", zap.String("license_id", licenseID.String()), zap.Error(err))
+		return nil, 0, fmt.Errorf("database error counting license comments: %w", mapError(err))
+	}
+	if total == 0 {
+		return []*comment.Comment{}, 0, nil
+	}
+
+	rows, err := r.db.Query(ctx, `
+        SELECT `+commentColumns+`
+        FROM license_comments
+        WHERE license_id = $1
+        ORDER BY created_at ASC, id ASC
+        LIMIT $2 OFFSET $3
+    `, licenseID, limit, offset)
+	if err != nil {
+		r.logger.Error("Failed to list license comments", zap.String("license_id", licenseID.String()), zap.Error(err))
+		return nil, 0, fmt.Errorf("database error listing license comments: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	comments := make([]*comment.Comment, 0, limit)
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("database error scanning license comment: %w", mapError(err))
+		}
+		comments = append(comments, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("database iteration error listing license comments: %w", err)
+	}
+	return comments, total, nil
+}
+
+func (r *CommentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	cmdTag, err := r.db.Exec(ctx, `DELETE FROM license_comments WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete license comment", zap.String("id", id.String()), zap.Error(err))
+		return fmt.Errorf("database error deleting license comment: %w", mapError(err))
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ierr.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// cursorChunkSize is how many rows a streaming read fetches at a time. Only
+// one chunk is held in memory, and the next one is not fetched before the
+// caller has consumed the previous, so a slow consumer slows the query down
+// instead of piling rows up.
+const cursorChunkSize = 500
+
+// streamCursor runs query through a server-side cursor in a read-only
+// transaction and calls scan for every row. It stops at the first error from
+// scan or, checked before every row, when ctx is cancelled, e.g. because the
+// client went away.
+func streamCursor(ctx context.Context, db *pgxpool.Pool, query string, args []interface{}, scan func(pgx.Rows) error) error {
+	tx, err := db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("database error starting streaming read: %w", mapError(err))
+	}
+	// Nothing was written, so rolling back just closes the cursor.
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+
+	if _, err := tx.Exec(ctx, "DECLARE stream_cursor NO SCROLL CURSOR FOR "+query, args...); err != nil {
+		return fmt.Errorf("database error declaring cursor: %w", mapError(err))
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM stream_cursor", cursorChunkSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rows, err := tx.Query(ctx, fetch)
+		if err != nil {
+			return fmt.Errorf("database error fetching from cursor: %w", mapError(err))
+		}
+		n := 0
+		for rows.Next() {
+			n++
+			if err := ctx.Err(); err != nil {
+				rows.Close()
+				return err
+			}
+			if err := scan(rows); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("database error iterating cursor: %w", mapError(err))
+		}
+		if n < cursorChunkSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/customstatus"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type CustomStatusRepository struct {
+	db     *pgxpool.Pool
+	logger *zap.Logger
+}
+
+func NewCustomStatusRepository(db *pgxpool.Pool, logger *zap.Logger) *CustomStatusRepository {
+	return &CustomStatusRepository{
+		db:     db,
+		logger: logger.Named("CustomStatusRepository"),
+	}
+}
+
+var _ customstatus.Repository = (*CustomStatusRepository)(nil)
+
+func (r *CustomStatusRepository) Create(ctx context.Context, s *customstatus.Status) error {
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO custom_license_statuses (name, behavior, description)
+        VALUES ($1, $2, $3)
+        RETURNING created_at, updated_at
+    `, s.Name, s.Behavior, s.Description).Scan(&s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		err = mapError(err)
+		if errors.Is(err, ierr.ErrDuplicateKey) {
+			return fmt.Errorf("%w: custom status '%s' already exists", ierr.ErrDuplicateKey, s.Name)
+		}
+		r.logger.Error("Failed to create custom status", zap.String("name", string(s.Name)), zap.Error(err))
+		return fmt.Errorf("database error creating custom status: %w", err)
+	}
+	return nil
+}
+
+func (r *CustomStatusRepository) List(ctx context.Context) ([]*customstatus.Status, error) {
+	rows, err := r.db.Query(ctx, `
+        SELECT name, behavior, description, created_at, updated_at
+        FROM custom_license_statuses
+        ORDER BY name
+    `)
+	if err != nil {
+		r.logger.Error("Failed to list custom statuses", zap.Error(err))
+		return nil, fmt.Errorf("database error listing custom statuses: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	statuses := []*customstatus.Status{}
+	for rows.Next() {
+		var s customstatus.Status
+		if err := rows.Scan(&s.Name, &s.Behavior, &s.Description, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("database error scanning custom status: %w", mapError(err))
+		}
+		statuses = append(statuses, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("database iteration error listing custom statuses: %w", err)
+	}
+	return statuses, nil
+}
+
+func (r *CustomStatusRepository) Update(ctx context.Context, s *customstatus.Status) error {
+	err := r.db.QueryRow(ctx, `
+        UPDATE custom_license_statuses SET behavior = $1, description = $2
+        WHERE name = $3
+        RETURNING created_at, updated_at
+    `, s.Behavior, s.Description, s.Name).Scan(&s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: custom status %s not found for update", ierr.ErrNotFound, s.Name)
+		}
+		r.logger.Error("Failed to update custom status", zap.String("name", string(s.Name)), zap.Error(err))
+		return fmt.Errorf("database error updating custom status: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *CustomStatusRepository) Delete(ctx context.Context, name license.LicenseStatus) error {
+	cmdTag, err := r.db.Exec(ctx, `DELETE FROM custom_license_statuses WHERE name = $1`, name)
+	if err != nil {
+		r.logger.Error("Failed to delete custom status", zap.String("name", string(name)), zap.Error(err))
+		return fmt.Errorf("database error deleting custom status: %w", mapError(err))
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ierr.ErrNotFound
+	}
+	return nil
+}
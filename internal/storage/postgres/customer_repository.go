@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/customer"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type CustomerRepository struct {
+	db     *pgxpool.Pool
+	ids    idgen.Generator
+	logger *zap.Logger
+}
+
+func NewCustomerRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *CustomerRepository {
+	return &CustomerRepository{
+		db:     db,
+		ids:    ids,
+		logger: logger.Named("CustomerRepository"),
+	}
+}
+
+var _ customer.Repository = (*CustomerRepository)(nil)
+
+const customerColumns = `id, email, name, created_at, updated_at`
+
+func scanCustomer(row pgx.Row) (*customer.Customer, error) {
+	var c customer.Customer
+	if err := row.Scan(&c.ID, &c.Email, &c.Name, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *CustomerRepository) Create(ctx context.Context, c *customer.Customer) error {
+	c.ID = r.ids.New()
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO customers (id, email, name)
+        VALUES ($1, $2, $3)
+        RETURNING created_at, updated_at
+    `, c.ID, c.Email, c.Name).Scan(&c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		err = mapError(err)
+		if errors.Is(err, ierr.ErrDuplicateKey) {
+			return fmt.Errorf("%w: customer with e-mail '%s' already exists", ierr.ErrDuplicateKey, c.Email)
+		}
+		r.logger.Error("Failed to create customer", zap.String("email", c.Email), zap.Error(err))
+		return fmt.Errorf("database error creating customer: %w", err)
+	}
+	return nil
+}
+
+func (r *CustomerRepository) Ensure(ctx context.Context, email, name string) (*customer.Customer, error) {
+	c, err := scanCustomer(r.db.QueryRow(ctx, `
+        INSERT INTO customers (id, email, name)
+        VALUES ($1, $2, $3)
+        ON CONFLICT DO NOTHING
+        RETURNING `+customerColumns, r.ids.New(), email, name))
+	if err == nil {
+		return c, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		r.logger.Error("Failed to ensure customer", zap.String("email", email), zap.Error(err))
+		return nil, fmt.Errorf("database error ensuring customer: %w", mapError(err))
+	}
+
+	c, err = scanCustomer(r.db.QueryRow(ctx, `SELECT `+customerColumns+` FROM customers WHERE lower(email) = lower($1)`, email))
+	if err != nil {
+		r.logger.Error("Failed to find customer by e-mail", zap.String("email", email), zap.Error(err))
+		return nil, fmt.Errorf("database error finding customer: %w", mapError(err))
+	}
+	return c, nil
+}
+
+func (r *CustomerRepository) FindByID(ctx context.Context, id uuid.UUID) (*customer.Customer, error) {
+	c, err := scanCustomer(r.db.QueryRow(ctx, `SELECT `+customerColumns+` FROM customers WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find customer by ID", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error finding customer: %w", mapError(err))
+	}
+	return c, nil
+}
+
+func (r *CustomerRepository) List(ctx context.Context, params customer.ListParams) ([]*customer.Customer, int64, error) {
+	conditions := make([]string, 0, 2)
+	args := make([]interface{}, 0, 4)
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if params.Email != nil {
+		add("email ILIKE $%d", "%"+escapeLike(*params.Email)+"%")
+	}
+	if params.Name != nil {
+		add("name ILIKE $%d", "%"+escapeLike(*params.Name)+"%")
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM customers`+where, args...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count customers", zap.Error(err))
+		return nil, 0, fmt.Errorf("database error counting customers: %w", mapError(err))
+	}
+	if total == 0 {
+		return []*customer.Customer{}, 0, nil
+	}
+
+	query := `SELECT ` + customerColumns + ` FROM customers` + where + fmt.Sprintf(`
+        ORDER BY lower(email) ASC
+        LIMIT $%d OFFSET $%d
+    `, len(args)+1, len(args)+2)
+	rows, err := r.db.Query(ctx, query, append(args, params.Limit, params.Offset)...)
+	if err != nil {
+		r.logger.Error("Failed to list customers", zap.Error(err))
+		return nil, 0, fmt.Errorf("database error listing customers: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	customers := make([]*customer.Customer, 0, params.Limit)
+	for rows.Next() {
+		c, err := scanCustomer(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("database error scanning customer: %w", mapError(err))
+		}
+		customers = append(customers, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("database iteration error listing customers: %w", err)
+	}
+	return customers, total, nil
+}
+
+func (r *CustomerRepository) Update(ctx context.Context, c *customer.Customer) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("database error starting customer update: %w", mapError(err))
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	err = tx.QueryRow(ctx, `
+        UPDATE customers SET email = $1, name = $2
+        WHERE id = $3
+        RETURNING updated_at
+    `, c.Email, c.Name, c.ID).Scan(&c.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: customer with ID %s not found for update", ierr.ErrNotFound, c.ID)
+		}
+		err = mapError(err)
+		if errors.Is(err, ierr.ErrDuplicateKey) {
+			return fmt.Errorf("%w: customer with e-mail '%s' already exists", ierr.ErrDuplicateKey, c.Email)
+		}
+		r.logger.Error("Failed to update customer", zap.String("id", c.ID.String()), zap.Error(err))
+		return fmt.Errorf("database error updating customer: %w", err)
+	}
+	if err := r.syncLicenses(ctx, tx, c); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("database error committing customer update: %w", mapError(err))
+	}
+	return nil
+}
+
+// syncLicenses rewrites the customer copy held by the customer's licenses.
+// Only licenses that differ are touched, so the change feed does not report
+// changes that did not happen.
+func (r *CustomerRepository) syncLicenses(ctx context.Context, tx pgx.Tx, c *customer.Customer) error {
+	cmdTag, err := tx.Exec(ctx, `
+        UPDATE licenses SET customer_name = NULLIF($1, ''), customer_email = $2
+        WHERE customer_id = $3
+          AND (customer_name IS DISTINCT FROM NULLIF($1, '') OR customer_email IS DISTINCT FROM $2)
+    `, c.Name, c.Email, c.ID)
+	if err != nil {
+		r.logger.Error("Failed to update licenses of customer", zap.String("id", c.ID.String()), zap.Error(err))
+		return fmt.Errorf("database error updating licenses of customer: %w", mapError(err))
+	}
+	if n := cmdTag.RowsAffected(); n > 0 {
+		r.logger.Info("Updated customer on licenses", zap.String("id", c.ID.String()), zap.Int64("licenses", n))
+	}
+	return nil
+}
+
+func (r *CustomerRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	cmdTag, err := r.db.Exec(ctx, `DELETE FROM customers WHERE id = $1`, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+			return fmt.Errorf("%w: customer still has licenses", ierr.ErrConflict)
+		}
+		r.logger.Error("Failed to delete customer", zap.String("id", id.String()), zap.Error(err))
+		return fmt.Errorf("database error deleting customer: %w", mapError(err))
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ierr.ErrNotFound
+	}
+	return nil
+}
+
+// escapeLike makes s match literally inside a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
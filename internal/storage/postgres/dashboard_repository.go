@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/dashboard"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type DashboardRepository struct {
+	db     *pgxpool.Pool
+	ids    idgen.Generator
+	logger *zap.Logger
+}
+
+func NewDashboardRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *DashboardRepository {
+	return &DashboardRepository{
+		db:     db,
+		ids:    ids,
+		logger: logger.Named("DashboardRepository"),
+	}
+}
+
+var _ dashboard.Repository = (*DashboardRepository)(nil)
+
+const widgetColumns = `id, org_id, kind, title, position, params, created_at, updated_at`
+
+func scanWidget(row pgx.Row) (*dashboard.Widget, error) {
+	var w dashboard.Widget
+	if err := row.Scan(&w.ID, &w.OrgID, &w.Kind, &w.Title, &w.Position, &w.Params, &w.CreatedAt, &w.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *DashboardRepository) Create(ctx context.Context, w *dashboard.Widget) error {
+	w.ID = r.ids.New()
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO dashboard_widgets (id, org_id, kind, title, position, params)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING created_at, updated_at
+    `, w.ID, w.OrgID, w.Kind, w.Title, w.Position, w.Params).Scan(&w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create dashboard widget", zap.String("org_id", w.OrgID), zap.Error(err))
+		return fmt.Errorf("database error creating dashboard widget: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *DashboardRepository) FindByID(ctx context.Context, orgID string, id uuid.UUID) (*dashboard.Widget, error) {
+	w, err := scanWidget(r.db.QueryRow(ctx, `SELECT `+widgetColumns+` FROM dashboard_widgets WHERE id = $1 AND org_id = $2`, id, orgID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find dashboard widget", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error finding dashboard widget: %w", mapError(err))
+	}
+	return w, nil
+}
+
+func (r *DashboardRepository) List(ctx context.Context, orgID string) ([]*dashboard.Widget, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+widgetColumns+` FROM dashboard_widgets WHERE org_id = $1 ORDER BY position ASC, created_at ASC`, orgID)
+	if err != nil {
+		r.logger.Error("Failed to list dashboard widgets", zap.String("org_id", orgID), zap.Error(err))
+		return nil, fmt.Errorf("database error listing dashboard widgets: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	widgets := make([]*dashboard.Widget, 0)
+	for rows.Next() {
+		w, err := scanWidget(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan dashboard widget row", zap.Error(err))
+			return nil, fmt.Errorf("database scan error listing dashboard widgets: %w", err)
+		}
+		widgets = append(widgets, w)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("Error iterating dashboard widget rows", zap.Error(err))
+		return nil, fmt.Errorf("database error iterating dashboard widgets: %w", mapError(err))
+	}
+	return widgets, nil
+}
+
+func (r *DashboardRepository) Count(ctx context.Context, orgID string) (int, error) {
+	var count int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM dashboard_widgets WHERE org_id = $1`, orgID).Scan(&count); err != nil {
+		r.logger.Error("Failed to count dashboard widgets", zap.String("org_id", orgID), zap.Error(err))
+		return 0, fmt.Errorf("database error counting dashboard widgets: %w", mapError(err))
+	}
+	return count, nil
+}
+
+func (r *DashboardRepository) Update(ctx context.Context, w *dashboard.Widget) error {
+	err := r.db.QueryRow(ctx, `
+        UPDATE dashboard_widgets SET title = $1, position = $2, params = $3
+        WHERE id = $4 AND org_id = $5
+        RETURNING updated_at
+    `, w.Title, w.Position, w.Params, w.ID, w.OrgID).Scan(&w.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: dashboard widget with ID %s not found for update", ierr.ErrNotFound, w.ID)
+		}
+		r.logger.Error("Failed to update dashboard widget", zap.String("id", w.ID.String()), zap.Error(err))
+		return fmt.Errorf("database error updating dashboard widget: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *DashboardRepository) Delete(ctx context.Context, orgID string, id uuid.UUID) error {
+	cmdTag, err := r.db.Exec(ctx, `DELETE FROM dashboard_widgets WHERE id = $1 AND org_id = $2`, id, orgID)
+	if err != nil {
+		r.logger.Error("Failed to delete dashboard widget", zap.String("id", id.String()), zap.Error(err))
+		return fmt.Errorf("database error deleting dashboard widget: %w", mapError(err))
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ierr.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/entitlement"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type EntitlementRepository struct {
+	db     *pgxpool.Pool
+	ids    idgen.Generator
+	logger *zap.Logger
+}
+
+func NewEntitlementRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *EntitlementRepository {
+	return &EntitlementRepository{
+		db:     db,
+		ids:    ids,
+		logger: logger.Named("EntitlementRepository"),
+	}
+}
+
+var _ entitlement.Repository = (*EntitlementRepository)(nil)
+
+func (r *EntitlementRepository) ListByLicense(ctx context.Context, licenseID uuid.UUID) ([]*entitlement.Entitlement, error) {
+	rows, err := r.db.Query(ctx, `
+        SELECT id, license_id, name, type, value, created_at, updated_at
+        FROM license_entitlements
+        WHERE license_id = $1
+        ORDER BY name
+    `, licenseID)
+	if err != nil {
+		r.logger.Error("Failed to list entitlements", zap.String("license_id", licenseID.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error listing entitlements: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	entitlements := []*entitlement.Entitlement{}
+	for rows.Next() {
+		var e entitlement.Entitlement
+		if err := rows.Scan(&e.ID, &e.LicenseID, &e.Name, &e.Type, &e.Value, &e.CreatedAt, &e.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("database error scanning entitlement: %w", mapError(err))
+		}
+		entitlements = append(entitlements, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("database iteration error listing entitlements: %w", err)
+	}
+	return entitlements, nil
+}
+
+func (r *EntitlementRepository) Put(ctx context.Context, e *entitlement.Entitlement) error {
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO license_entitlements (id, license_id, name, type, value)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (license_id, name) DO UPDATE SET type = EXCLUDED.type, value = EXCLUDED.value
+        RETURNING id, created_at, updated_at
+    `, r.ids.New(), e.LicenseID, e.Name, e.Type, e.Value).Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Failed to put entitlement", zap.String("license_id", e.LicenseID.String()), zap.String("name", e.Name), zap.Error(err))
+		return fmt.Errorf("database error saving entitlement: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *EntitlementRepository) Delete(ctx context.Context, licenseID uuid.UUID, name string) error {
+	cmdTag, err := r.db.Exec(ctx, `DELETE FROM license_entitlements WHERE license_id = $1 AND name = $2`, licenseID, name)
+	if err != nil {
+		r.logger.Error("Failed to delete entitlement", zap.String("license_id", licenseID.String()), zap.String("name", name), zap.Error(err))
+		return fmt.Errorf("database error deleting entitlement: %w", mapError(err))
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ierr.ErrNotFound
+	}
+	return nil
+}
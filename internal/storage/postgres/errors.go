@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+)
+
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgCheckViolation       = "23514"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// mapError translates driver-level errors into the domain errors from ierr so
+// services never have to know about pgx or Postgres error codes. Errors that
+// have no domain meaning are returned unchanged.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ierr.ErrNotFound
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return fmt.Errorf("%w: constraint %s", ierr.ErrDuplicateKey, pgErr.ConstraintName)
+		case pgForeignKeyViolation:
+			return fmt.Errorf("%w: referenced record does not exist (%s)", ierr.ErrConflict, pgErr.ConstraintName)
+		case pgCheckViolation:
+			return fmt.Errorf("%w: constraint %s", ierr.ErrValidation, pgErr.ConstraintName)
+		case pgSerializationFailure, pgDeadlockDetected:
+			return fmt.Errorf("%w: concurrent modification, retry the request", ierr.ErrConflict)
+		}
+	}
+	return err
+}
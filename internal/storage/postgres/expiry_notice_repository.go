@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/expirynotice"
+	"go.uber.org/zap"
+)
+
+// ExpiryNoticeRepository keeps the expiry e-mail log and opt-outs in the
+// primary database, also when licenses are sharded.
+type ExpiryNoticeRepository struct {
+	db     *pgxpool.Pool
+	logger *zap.Logger
+}
+
+func NewExpiryNoticeRepository(db *pgxpool.Pool, logger *zap.Logger) *ExpiryNoticeRepository {
+	return &ExpiryNoticeRepository{
+		db:     db,
+		logger: logger.Named("ExpiryNoticeRepository"),
+	}
+}
+
+var _ expirynotice.Repository = (*ExpiryNoticeRepository)(nil)
+
+func (r *ExpiryNoticeRepository) Claim(ctx context.Context, n *expirynotice.Notice) (bool, error) {
+	cmdTag, err := r.db.Exec(ctx, `
+        INSERT INTO license_expiry_notices (license_id, kind, days_before, expires_at)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT DO NOTHING
+    `, n.LicenseID, n.Kind, n.DaysBefore, n.ExpiresAt)
+	if err != nil {
+		r.logger.Error("Failed to claim expiry notice", zap.String("license_id", n.LicenseID.String()), zap.String("kind", string(n.Kind)), zap.Error(err))
+		return false, fmt.Errorf("database error claiming expiry notice: %w", mapError(err))
+	}
+	return cmdTag.RowsAffected() == 1, nil
+}
+
+func (r *ExpiryNoticeRepository) Release(ctx context.Context, n *expirynotice.Notice) error {
+	_, err := r.db.Exec(ctx, `
+        DELETE FROM license_expiry_notices
+        WHERE license_id = $1 AND kind = $2 AND days_before = $3 AND expires_at = $4
+    `, n.LicenseID, n.Kind, n.DaysBefore, n.ExpiresAt)
+	if err != nil {
+		r.logger.Error("Failed to release expiry notice", zap.String("license_id", n.LicenseID.String()), zap.String("kind", string(n.Kind)), zap.Error(err))
+		return fmt.Errorf("database error releasing expiry notice: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *ExpiryNoticeRepository) ListByLicense(ctx context.Context, licenseID uuid.UUID) ([]*expirynotice.Notice, error) {
+	rows, err := r.db.Query(ctx, `
+        SELECT license_id, kind, days_before, expires_at, sent_at
+        FROM license_expiry_notices
+        WHERE license_id = $1
+        ORDER BY sent_at DESC
+    `, licenseID)
+	if err != nil {
+		r.logger.Error("Failed to list expiry notices", zap.String("license_id", licenseID.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error listing expiry notices: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	notices := []*expirynotice.Notice{}
+	for rows.Next() {
+		var n expirynotice.Notice
+		if err := rows.Scan(&n.LicenseID, &n.Kind, &n.DaysBefore, &n.ExpiresAt, &n.SentAt); err != nil {
+			return nil, fmt.Errorf("database error scanning expiry notice: %w", mapError(err))
+		}
+		notices = append(notices, &n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("database iteration error listing expiry notices: %w", err)
+	}
+	return notices, nil
+}
+
+func (r *ExpiryNoticeRepository) OptedOut(ctx context.Context, licenseID uuid.UUID) (bool, error) {
+	optedOut, err := r.OptedOutAmong(ctx, []uuid.UUID{licenseID})
+	if err != nil {
+		return false, err
+	}
+	return optedOut[licenseID], nil
+}
+
+func (r *ExpiryNoticeRepository) OptedOutAmong(ctx context.Context, licenseIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	optedOut := make(map[uuid.UUID]bool)
+	if len(licenseIDs) == 0 {
+		return optedOut, nil
+	}
+	rows, err := r.db.Query(ctx, `SELECT license_id FROM license_expiry_opt_outs WHERE license_id = ANY($1)`, licenseIDs)
+	if err != nil {
+		r.logger.Error("Failed to look up expiry e-mail opt-outs", zap.Int("licenses", len(licenseIDs)), zap.Error(err))
+		return nil, fmt.Errorf("database error looking up expiry e-mail opt-outs: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("database error scanning expiry e-mail opt-out: %w", mapError(err))
+		}
+		optedOut[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("database iteration error looking up expiry e-mail opt-outs: %w", err)
+	}
+	return optedOut, nil
+}
+
+func (r *ExpiryNoticeRepository) SetOptOut(ctx context.Context, licenseID uuid.UUID, optOut bool) error {
+	query := `INSERT INTO license_expiry_opt_outs (license_id) VALUES ($1) ON CONFLICT DO NOTHING`
+	if !optOut {
+		query = `DELETE FROM license_expiry_opt_outs WHERE license_id = $1`
+	}
+	if _, err := r.db.Exec(ctx, query, licenseID); err != nil {
+		r.logger.Error("Failed to set expiry e-mail opt-out", zap.String("license_id", licenseID.String()), zap.Bool("opt_out", optOut), zap.Error(err))
+		return fmt.Errorf("database error setting expiry e-mail opt-out: %w", mapError(err))
+	}
+	return nil
+}
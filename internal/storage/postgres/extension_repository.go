@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/extension"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type ExtensionRepository struct {
+	db     *pgxpool.Pool
+	ids    idgen.Generator
+	logger *zap.Logger
+}
+
+func NewExtensionRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *ExtensionRepository {
+	return &ExtensionRepository{
+		db:     db,
+		ids:    ids,
+		logger: logger.Named("ExtensionRepository"),
+	}
+}
+
+var _ extension.Repository = (*ExtensionRepository)(nil)
+
+const extensionColumns = `id, license_id, requested_expires_at, previous_expires_at, reason, requested_by, status, decided_by, decided_at, decision_note, created_at`
+
+func scanExtension(row pgx.Row) (*extension.Request, error) {
+	var e extension.Request
+	err := row.Scan(&e.ID, &e.LicenseID, &e.RequestedExpiresAt, &e.PreviousExpiresAt, &e.Reason, &e.RequestedBy,
+		&e.Status, &e.DecidedBy, &e.DecidedAt, &e.DecisionNote, &e.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *ExtensionRepository) Create(ctx context.Context, e *extension.Request) error {
+	e.ID = r.ids.New()
+	e.Status = extension.StatusPending
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO license_extension_requests (id, license_id, requested_expires_at, previous_expires_at, reason, requested_by)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING created_at
+    `, e.ID, e.LicenseID, e.RequestedExpiresAt, e.PreviousExpiresAt, e.Reason, e.RequestedBy).Scan(&e.CreatedAt)
+	if err != nil {
+		err = mapError(err)
+		if !errors.Is(err, ierr.ErrDuplicateKey) {
+			r.logger.Error("Failed to create extension request", zap.String("license_id", e.LicenseID.String()), zap.Error(err))
+		}
+		return fmt.Errorf("database error creating extension request: %w", err)
+	}
+	return nil
+}
+
+func (r *ExtensionRepository) FindByID(ctx context.Context, licenseID, id uuid.UUID) (*extension.Request, error) {
+	e, err := scanExtension(r.db.QueryRow(ctx, `SELECT `+extensionColumns+` FROM license_extension_requests WHERE id = $1 AND license_id = $2`, id, licenseID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find extension request", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error finding extension request: %w", mapError(err))
+	}
+	return e, nil
+}
+
+func (r *ExtensionRepository) List(ctx context.Context, params extension.ListParams) ([]*extension.Request, int64, error) {
+	conditions := make([]string, 0, 2)
+	args := make([]interface{}, 0, 4)
+	if params.Status != nil {
+		args = append(args, *params.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if params.LicenseID != nil {
+		args = append(args, *params.LicenseID)
+		conditions = append(conditions, fmt.Sprintf("license_id = $%d", len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM license_extension_requests`+where, args...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count extension requests", zap.Error(err))
+		return nil, 0, fmt.Errorf("database error counting extension requests: %w", mapError(err))
+	}
+	if total == 0 {
+		return []*extension.Request{}, 0, nil
+	}
+
+	query := `SELECT ` + extensionColumns + ` FROM license_extension_requests` + where + fmt.Sprintf(`
+        ORDER BY created_at DESC, id DESC
+        LIMIT $%d OFFSET $%d
+    `, len(args)+1, len(args)+2)
+	rows, err := r.db.Query(ctx, query, append(args, params.Limit, params.Offset)...)
+	if err != nil {
+		r.logger.Error("Failed to list extension requests", zap.Error(err))
+		return nil, 0, fmt.Errorf("database error listing extension requests: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	requests := make([]*extension.Request, 0, params.Limit)
+	for rows.Next() {
+		e, err := scanExtension(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("database error scanning extension request: %w", mapError(err))
+		}
+		requests = append(requests, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("database iteration error listing extension requests: %w", err)
+	}
+	return requests, total, nil
+}
+
+func (r *ExtensionRepository) Decide(ctx context.Context, id uuid.UUID, status extension.Status, decidedBy, note string) (*extension.Request, error) {
+	e, err := scanExtension(r.db.QueryRow(ctx, `
+        UPDATE license_extension_requests
+        SET status = $2, decided_by = $3, decided_at = NOW(), decision_note = $4
+        WHERE id = $1 AND status = 'pending'
+        RETURNING `+extensionColumns, id, status, decidedBy, note))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: extension request was already decided", ierr.ErrConflict)
+		}
+		r.logger.Error("Failed to decide extension request", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error deciding extension request: %w", mapError(err))
+	}
+	return e, nil
+}
+
+func (r *ExtensionRepository) Reopen(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `
+        UPDATE license_extension_requests
+        SET status = 'pending', decided_by = NULL, decided_at = NULL, decision_note = ''
+        WHERE id = $1
+    `, id)
+	if err != nil {
+		r.logger.Error("Failed to reopen extension request", zap.String("id", id.String()), zap.Error(err))
+		return fmt.Errorf("database error reopening extension request: %w", mapError(err))
+	}
+	return nil
+}
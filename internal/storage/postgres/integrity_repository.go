@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/integrity"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type IntegrityRepository struct {
+	db     *pgxpool.Pool
+	ids    idgen.Generator
+	logger *zap.Logger
+}
+
+func NewIntegrityRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *IntegrityRepository {
+	return &IntegrityRepository{
+		db:     db,
+		ids:    ids,
+		logger: logger.Named("IntegrityRepository"),
+	}
+}
+
+var _ integrity.Repository = (*IntegrityRepository)(nil)
+
+const integrityRunColumns = `id, status, repairs, requested_by, report, error, created_at, started_at, finished_at`
+
+func scanIntegrityRun(row pgx.Row) (*integrity.Run, error) {
+	var run integrity.Run
+	if err := row.Scan(&run.ID, &run.Status, &run.Repairs, &run.RequestedBy, &run.Report, &run.Error, &run.CreatedAt, &run.StartedAt, &run.FinishedAt); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *IntegrityRepository) Create(ctx context.Context, run *integrity.Run) (uuid.UUID, error) {
+	if run.Repairs == nil {
+		run.Repairs = []string{}
+	}
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO integrity_checks (id, repairs, requested_by)
+        VALUES ($1, $2, $3)
+        RETURNING id, status, created_at
+    `, r.ids.New(), run.Repairs, run.RequestedBy).Scan(&run.ID, &run.Status, &run.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create integrity check", zap.Error(err))
+		return uuid.Nil, fmt.Errorf("database error creating integrity check: %w", mapError(err))
+	}
+	return run.ID, nil
+}
+
+func (r *IntegrityRepository) FindByID(ctx context.Context, id uuid.UUID) (*integrity.Run, error) {
+	run, err := scanIntegrityRun(r.db.QueryRow(ctx, `SELECT `+integrityRunColumns+` FROM integrity_checks WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find integrity check", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error finding integrity check: %w", mapError(err))
+	}
+	return run, nil
+}
+
+func (r *IntegrityRepository) List(ctx context.Context, limit int) ([]*integrity.Run, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+integrityRunColumns+` FROM integrity_checks ORDER BY created_at DESC LIMIT $1`, limit)
+	if err != nil {
+		r.logger.Error("Failed to list integrity checks", zap.Error(err))
+		return nil, fmt.Errorf("database error listing integrity checks: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	runs := []*integrity.Run{}
+	for rows.Next() {
+		run, err := scanIntegrityRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("database error scanning integrity check: %w", mapError(err))
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("database iteration error listing integrity checks: %w", err)
+	}
+	return runs, nil
+}
+
+func (r *IntegrityRepository) Start(ctx context.Context, id uuid.UUID) error {
+	return r.update(ctx, id, "start", `
+        UPDATE integrity_checks SET status = 'running', started_at = NOW()
+        WHERE id = $1 AND status IN ('pending', 'running')
+    `)
+}
+
+func (r *IntegrityRepository) Finish(ctx context.Context, id uuid.UUID, report *integrity.Report) error {
+	return r.update(ctx, id, "finish", `
+        UPDATE integrity_checks SET status = 'completed', report = $2, finished_at = NOW()
+        WHERE id = $1
+    `, report)
+}
+
+func (r *IntegrityRepository) Fail(ctx context.Context, id uuid.UUID, reason string) error {
+	return r.update(ctx, id, "fail", `
+        UPDATE integrity_checks SET status = 'failed', error = $2, finished_at = NOW()
+        WHERE id = $1
+    `, reason)
+}
+
+func (r *IntegrityRepository) update(ctx context.Context, id uuid.UUID, action, query string, args ...interface{}) error {
+	cmdTag, err := r.db.Exec(ctx, query, append([]interface{}{id}, args...)...)
+	if err != nil {
+		r.logger.Error("Failed to update integrity check", zap.String("id", id.String()), zap.String("action", action), zap.Error(err))
+		return fmt.Errorf("database error updating integrity check: %w", mapError(err))
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ierr.ErrNotFound
+	}
+	return nil
+}
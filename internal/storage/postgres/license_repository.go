@@ -6,67 +6,83 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/idgen"
 	"github.com/makkenzo/license-service-api/internal/ierr"
 	"go.uber.org/zap"
 )
 
 type LicenseRepository struct {
 	db     *pgxpool.Pool
+	ids    idgen.Generator
 	logger *zap.Logger
 }
 
-func NewLicenseRepository(db *pgxpool.Pool, logger *zap.Logger) *LicenseRepository {
+func NewLicenseRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *LicenseRepository {
 	return &LicenseRepository{
 		db:     db,
+		ids:    ids,
 		logger: logger.Named("LicenseRepository"),
 	}
 }
 
 var _ license.Repository = (*LicenseRepository)(nil)
+var _ license.Exporter = (*LicenseRepository)(nil)
+var _ license.Archiver = (*LicenseRepository)(nil)
 
-func (r *LicenseRepository) Create(ctx context.Context, lic *license.License) (uuid.UUID, error) {
-
-	query := `
+const insertLicenseQuery = `
         INSERT INTO licenses (
-            license_key, status, type, customer_name, customer_email,
-            product_name, metadata, issued_at, expires_at
+            id, license_key, status, type, customer_name, customer_email, customer_id,
+            product_id, product_name, metadata, issued_at, expires_at, max_activations, grace_period_days,
+            tags, operator_notes, starts_at, floating, parent_id
         ) VALUES (
-            $1, $2, $3, $4, $5, $6, $7, $8, $9
-        ) RETURNING id
-    `
-	var insertedID uuid.UUID
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
+        )`
 
-	err := r.db.QueryRow(ctx, query,
+func insertLicenseArgs(id uuid.UUID, lic *license.License) []interface{} {
+	return []interface{}{
+		id,
 		lic.LicenseKey,
 		lic.Status,
 		lic.Type,
 		lic.CustomerName,
 		lic.CustomerEmail,
+		lic.CustomerID,
+		lic.ProductID,
 		lic.ProductName,
 		lic.Metadata,
 		lic.IssuedAt,
 		lic.ExpiresAt,
-	).Scan(&insertedID)
+		maxActivations(lic),
+		lic.GracePeriodDays,
+		licenseTags(lic),
+		lic.OperatorNotes,
+		lic.StartsAt,
+		lic.Floating,
+		lic.ParentID,
+	}
+}
 
-	if err != nil {
+func (r *LicenseRepository) Create(ctx context.Context, lic *license.License) (uuid.UUID, error) {
+	var insertedID uuid.UUID
 
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+	err := r.db.QueryRow(ctx, insertLicenseQuery+" RETURNING id", insertLicenseArgs(r.ids.New(), lic)...).Scan(&insertedID)
 
+	if err != nil {
+		err = mapError(err)
+		if errors.Is(err, ierr.ErrDuplicateKey) {
 			r.logger.Warn("Attempted to create license with duplicate key",
 				zap.String("license_key", lic.LicenseKey),
-				zap.String("constraint", pgErr.ConstraintName),
+				zap.Error(err),
 			)
-
-			return uuid.Nil, fmt.Errorf("license key '%s' already exists", lic.LicenseKey)
+			return uuid.Nil, fmt.Errorf("%w: license key already exists", ierr.ErrDuplicateKey)
 		}
 
 		r.logger.Error("Failed to create license in database", zap.Error(err))
@@ -77,11 +93,60 @@ func (r *LicenseRepository) Create(ctx context.Context, lic *license.License) (u
 	return insertedID, nil
 }
 
+// createBatchSize is how many inserts CreateBatch sends in one round trip.
+const createBatchSize = 500
+
+func (r *LicenseRepository) CreateBatch(ctx context.Context, licenses []*license.License) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("database error starting batch license create: %w", mapError(err))
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// IDs are only handed out once the transaction commits, so a caller
+	// can tell which licenses were stored.
+	ids := make([]uuid.UUID, len(licenses))
+	for start := 0; start < len(licenses); start += createBatchSize {
+		chunk := licenses[start:min(start+createBatchSize, len(licenses))]
+		batch := &pgx.Batch{}
+		for i, lic := range chunk {
+			ids[start+i] = r.ids.New()
+			batch.Queue(insertLicenseQuery, insertLicenseArgs(ids[start+i], lic)...)
+		}
+
+		results := tx.SendBatch(ctx, batch)
+		for i := range chunk {
+			if _, err := results.Exec(); err != nil {
+				_ = results.Close()
+				err = mapError(err)
+				if errors.Is(err, ierr.ErrDuplicateKey) {
+					return fmt.Errorf("%w: license key of batch entry %d already exists", ierr.ErrDuplicateKey, start+i)
+				}
+				r.logger.Error("Failed to create license batch in database", zap.Int("batch_size", len(licenses)), zap.Error(err))
+				return fmt.Errorf("database error on batch create licenses: %w", err)
+			}
+		}
+		if err := results.Close(); err != nil {
+			return fmt.Errorf("database error on batch create licenses: %w", mapError(err))
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("database error committing batch license create: %w", mapError(err))
+	}
+	for i, lic := range licenses {
+		lic.ID = ids[i]
+	}
+
+	r.logger.Info("License batch created successfully", zap.Int("count", len(licenses)))
+	return nil
+}
+
 func (r *LicenseRepository) FindByID(ctx context.Context, id uuid.UUID) (*license.License, error) {
 	query := `
         SELECT
-            id, license_key, status, type, customer_name, customer_email,
-            product_name, metadata, issued_at, expires_at, created_at, updated_at
+            id, license_key, status, type, customer_name, customer_email, customer_id,
+            product_id, product_name, metadata, issued_at, expires_at, max_activations, grace_period_days, tags, operator_notes, starts_at, revoked_at, floating, parent_id, created_at, updated_at
         FROM licenses
         WHERE id = $1
     `
@@ -93,10 +158,13 @@ func (r *LicenseRepository) FindByID(ctx context.Context, id uuid.UUID) (*licens
 func (r *LicenseRepository) FindByKey(ctx context.Context, key string) (*license.License, error) {
 	query := `
         SELECT
-            id, license_key, status, type, customer_name, customer_email,
-            product_name, metadata, issued_at, expires_at, created_at, updated_at
+            id, license_key, status, type, customer_name, customer_email, customer_id,
+            product_id, product_name, metadata, issued_at, expires_at, max_activations, grace_period_days, tags, operator_notes, starts_at, revoked_at, floating, parent_id, created_at, updated_at
         FROM licenses
         WHERE license_key = $1
+           OR id = (SELECT license_id FROM superseded_keys WHERE license_key = $1 AND valid_until > NOW())
+        ORDER BY license_key = $1 DESC
+        LIMIT 1
     `
 
 	row := r.db.QueryRow(ctx, query, key)
@@ -106,67 +174,57 @@ func (r *LicenseRepository) FindByKey(ctx context.Context, key string) (*license
 func (r *LicenseRepository) List(ctx context.Context, params license.ListParams) ([]*license.License, int64, error) {
 	var baseQuery strings.Builder
 	var countQuery strings.Builder
-	args := make([]interface{}, 0, 5)
 	paramIndex := 1
 
 	baseQuery.WriteString(`
         SELECT
-            id, license_key, status, type, customer_name, customer_email,
-            product_name, metadata, issued_at, expires_at, created_at, updated_at
+            id, license_key, status, type, customer_name, customer_email, customer_id,
+            product_id, product_name, metadata, issued_at, expires_at, max_activations, grace_period_days, tags, operator_notes, starts_at, revoked_at, floating, parent_id, created_at, updated_at
         FROM licenses
     `)
 
 	countQuery.WriteString(`SELECT COUNT(*) FROM licenses`)
 
-	whereClause := strings.Builder{}
+	where, args := licenseFilter(params)
+	paramIndex += len(args)
+	baseQuery.WriteString(where)
+	countQuery.WriteString(where)
 
-	addWhereCondition := func(condition string, value interface{}) {
-		if whereClause.Len() == 0 {
-			whereClause.WriteString(" WHERE ")
-		} else {
-			whereClause.WriteString(" AND ")
+	var orderByClause string
+	if len(params.Sort) > 0 {
+		clause, err := r.buildOrderBy(params.Sort)
+		if err != nil {
+			r.logger.Warn("Invalid sort parameters", zap.Error(err))
+			return nil, 0, err
 		}
-		whereClause.WriteString(fmt.Sprintf("%s = $%d", condition, paramIndex))
-		args = append(args, value)
-		paramIndex++
-	}
-
-	if params.Status != nil {
-		addWhereCondition("status", *params.Status)
-	}
-	if params.CustomerEmail != nil {
-		addWhereCondition("customer_email", *params.CustomerEmail)
-	}
-	if params.ProductName != nil {
-		addWhereCondition("product_name", *params.ProductName)
-	}
-	if params.Type != nil {
-		addWhereCondition("type", *params.Type)
-	}
+		orderByClause = clause
+	} else {
+		clause, err := r.buildOrderBy([]license.SortField{{Column: params.SortBy, Order: params.SortOrder}})
+		if err != nil {
+			r.logger.Warn("Invalid sort parameters", zap.Error(err))
 
-	if whereClause.Len() > 0 {
-		baseQuery.WriteString(whereClause.String())
-		countQuery.WriteString(whereClause.String())
+			clause = " ORDER BY created_at DESC"
+		}
+		orderByClause = clause
 	}
 
 	var totalCount int64
 	countSQL := countQuery.String()
-	r.logger.Debug("Executing count query", zap.String("sql", countSQL), zap.Any("args", args))
+	r.logger.Debug("Executing count query", zap.String("sql", countSQL), zap.Int("arg_count", len(args)))
 	err := r.db.QueryRow(ctx, countSQL, args...).Scan(&totalCount)
 	if err != nil {
 		r.logger.Error("Failed to execute count query for licenses", zap.Error(err))
-		return nil, 0, fmt.Errorf("database error on count licenses: %w", err)
+		return nil, 0, fmt.Errorf("database error on count licenses: %w", mapError(err))
 	}
 
 	if totalCount == 0 {
 		return []*license.License{}, 0, nil
 	}
 
-	orderByClause, err := r.buildOrderBy(params.SortBy, params.SortOrder)
-	if err != nil {
-		r.logger.Warn("Invalid sort parameters", zap.Error(err))
-
-		orderByClause = " ORDER BY created_at DESC"
+	if params.Query != nil {
+		args = append(args, *params.Query)
+		orderByClause = " ORDER BY " + searchRankOrder(paramIndex) + ", " + strings.TrimPrefix(orderByClause, " ORDER BY ")
+		paramIndex++
 	}
 	baseQuery.WriteString(orderByClause)
 
@@ -178,28 +236,22 @@ func (r *LicenseRepository) List(ctx context.Context, params license.ListParams)
 	args = append(args, params.Offset)
 
 	listSQL := baseQuery.String()
-	r.logger.Debug("Executing list query", zap.String("sql", listSQL), zap.Any("args", args))
+	r.logger.Debug("Executing list query", zap.String("sql", listSQL), zap.Int("arg_count", len(args)))
 	rows, err := r.db.Query(ctx, listSQL, args...)
 	if err != nil {
 		r.logger.Error("Failed to query list of licenses", zap.Error(err))
-		return nil, 0, fmt.Errorf("database error on list licenses: %w", err)
+		return nil, 0, fmt.Errorf("database error on list licenses: %w", mapError(err))
 	}
 	defer rows.Close()
 
 	licenses := make([]*license.License, 0, params.Limit)
 
 	for rows.Next() {
-		var lic license.License
-		err := rows.Scan(
-			&lic.ID, &lic.LicenseKey, &lic.Status, &lic.Type, &lic.CustomerName,
-			&lic.CustomerEmail, &lic.ProductName, &lic.Metadata, &lic.IssuedAt,
-			&lic.ExpiresAt, &lic.CreatedAt, &lic.UpdatedAt,
-		)
+		lic, err := r.scanLicense(rows)
 		if err != nil {
-			r.logger.Error("Failed to scan license row during list", zap.Error(err))
-			return nil, 0, fmt.Errorf("database scan error during list: %w", err)
+			return nil, 0, err
 		}
-		licenses = append(licenses, &lic)
+		licenses = append(licenses, lic)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -210,40 +262,167 @@ func (r *LicenseRepository) List(ctx context.Context, params license.ListParams)
 	return licenses, totalCount, nil
 }
 
-func (r *LicenseRepository) buildOrderBy(sortBy, sortOrder string) (string, error) {
-	allowedSortBy := map[string]string{
-		"id":             "id",
-		"created_at":     "created_at",
-		"expires_at":     "expires_at",
-		"issued_at":      "issued_at",
-		"updated_at":     "updated_at",
-		"customer_name":  "customer_name",
-		"customer_email": "customer_email",
-		"product_name":   "product_name",
-		"type":           "type",
-		"status":         "status",
+// Export streams the licenses matching the filters of params in ID order;
+// sorting and paging are ignored.
+func (r *LicenseRepository) Export(ctx context.Context, params license.ListParams, fn func(*license.License) error) error {
+	where, args := licenseFilter(params)
+	query := `
+        SELECT
+            id, license_key, status, type, customer_name, customer_email, customer_id,
+            product_id, product_name, metadata, issued_at, expires_at, max_activations, grace_period_days, tags, operator_notes, starts_at, revoked_at, floating, parent_id, created_at, updated_at
+        FROM licenses` + where + `
+        ORDER BY id ASC`
+
+	err := streamCursor(ctx, r.db, query, args, func(rows pgx.Rows) error {
+		lic, err := r.scanLicense(rows)
+		if err != nil {
+			return err
+		}
+		return fn(lic)
+	})
+	if err != nil && ctx.Err() == nil {
+		r.logger.Error("License export failed", zap.Error(err))
 	}
+	return err
+}
 
-	dbColumn, ok := allowedSortBy[strings.ToLower(sortBy)]
-	if !ok {
-		return "", fmt.Errorf("invalid sort_by field: %s", sortBy)
+// licenseFilter builds the WHERE clause shared by List and Export. Its
+// placeholders start at $1.
+func licenseFilter(params license.ListParams) (string, []interface{}) {
+	var where strings.Builder
+	args := make([]interface{}, 0, 6)
+	and := func() {
+		if where.Len() == 0 {
+			where.WriteString(" WHERE ")
+		} else {
+			where.WriteString(" AND ")
+		}
+	}
+	add := func(column, op string, value interface{}) {
+		and()
+		args = append(args, value)
+		where.WriteString(fmt.Sprintf("%s %s $%d", column, op, len(args)))
+	}
+	addAny := func(column string, values []string) {
+		and()
+		args = append(args, values)
+		where.WriteString(fmt.Sprintf("%s = ANY($%d)", column, len(args)))
+	}
+
+	if params.Status != nil {
+		add("status", "=", *params.Status)
+	}
+	if len(params.Statuses) > 0 {
+		statuses := make([]string, len(params.Statuses))
+		for i, status := range params.Statuses {
+			statuses[i] = string(status)
+		}
+		addAny("status", statuses)
+	}
+	if params.CustomerEmail != nil {
+		add("customer_email", "=", *params.CustomerEmail)
 	}
+	if params.CustomerID != nil {
+		add("customer_id", "=", *params.CustomerID)
+	}
+	if params.ProductName != nil {
+		add("product_name", "=", *params.ProductName)
+	}
+	if len(params.ProductNames) > 0 {
+		addAny("product_name", params.ProductNames)
+	}
+	if params.Type != nil {
+		add("type", "=", *params.Type)
+	}
+	if params.Tag != nil {
+		add("tags", "@>", []string{*params.Tag})
+	}
+	if params.CreatedAfter != nil {
+		add("created_at", ">=", *params.CreatedAfter)
+	}
+	if params.CreatedBefore != nil {
+		add("created_at", "<", *params.CreatedBefore)
+	}
+	if params.StartsBefore != nil {
+		add("starts_at", "<=", *params.StartsBefore)
+	}
+	if params.RevokedSince != nil {
+		add("revoked_at", ">=", *params.RevokedSince)
+	}
+	if params.ExpiresAfter != nil {
+		add("expires_at", ">=", *params.ExpiresAfter)
+	}
+	if params.ExpiresBefore != nil {
+		add("expires_at", "<=", *params.ExpiresBefore)
+	}
+	if params.ParentID != nil {
+		add("parent_id", "=", *params.ParentID)
+	}
+	if params.Query != nil {
+		and()
+		args = append(args, "%"+escapeLike(*params.Query)+"%", *params.Query)
+		pattern, query := len(args)-1, len(args)
+		where.WriteString(fmt.Sprintf(`(license_key ILIKE $%[1]d OR customer_name ILIKE $%[1]d OR customer_email ILIKE $%[1]d
+            OR EXISTS (SELECT 1 FROM jsonb_path_query(metadata, 'strict $.**') v WHERE jsonb_typeof(v) = 'string' AND v #>> '{}' ILIKE $%[1]d)
+            OR search_vector @@ websearch_to_tsquery('simple', $%[2]d::text))`, pattern, query))
+	}
+	return where.String(), args
+}
 
-	order := strings.ToUpper(sortOrder)
-	if order != "ASC" && order != "DESC" {
-		return "", fmt.Errorf("invalid sort_order: %s", sortOrder)
+// searchRankOrder orders by license.SearchRank of query, the argument at
+// index arg.
+func searchRankOrder(arg int) string {
+	q := fmt.Sprintf("lower($%d::text)", arg)
+	return fmt.Sprintf(`CASE WHEN lower(license_key) = %[1]s THEN 0
+            WHEN starts_with(lower(license_key), %[1]s) OR starts_with(lower(customer_name), %[1]s)
+                OR starts_with(lower(customer_email), %[1]s) THEN 1
+            ELSE 2 END`, q)
+}
+
+var allowedSortColumns = map[string]string{
+	"id":             "id",
+	"created_at":     "created_at",
+	"expires_at":     "expires_at",
+	"issued_at":      "issued_at",
+	"starts_at":      "starts_at",
+	"revoked_at":     "revoked_at",
+	"updated_at":     "updated_at",
+	"customer_name":  "customer_name",
+	"customer_email": "customer_email",
+	"product_name":   "product_name",
+	"type":           "type",
+	"status":         "status",
+}
+
+func (r *LicenseRepository) buildOrderBy(fields []license.SortField) (string, error) {
+	if len(fields) == 0 {
+		return "", fmt.Errorf("no sort fields")
 	}
 
-	nullsPlacement := ""
-	if dbColumn == "expires_at" || dbColumn == "issued_at" || dbColumn == "customer_name" || dbColumn == "customer_email" {
-		if order == "ASC" {
-			nullsPlacement = " NULLS FIRST"
-		} else {
-			nullsPlacement = " NULLS LAST"
+	terms := make([]string, 0, len(fields))
+	for _, field := range fields {
+		dbColumn, ok := allowedSortColumns[strings.ToLower(field.Column)]
+		if !ok {
+			return "", fmt.Errorf("%w: invalid sort field: %s", ierr.ErrValidation, field.Column)
+		}
+
+		order := strings.ToUpper(field.Order)
+		if order != "ASC" && order != "DESC" {
+			return "", fmt.Errorf("%w: invalid sort order: %s", ierr.ErrValidation, field.Order)
+		}
+
+		nullsPlacement := ""
+		if dbColumn == "expires_at" || dbColumn == "issued_at" || dbColumn == "customer_name" || dbColumn == "customer_email" {
+			if order == "ASC" {
+				nullsPlacement = " NULLS FIRST"
+			} else {
+				nullsPlacement = " NULLS LAST"
+			}
 		}
+		terms = append(terms, fmt.Sprintf("%s %s%s", dbColumn, order, nullsPlacement))
 	}
 
-	return fmt.Sprintf(" ORDER BY %s %s%s", dbColumn, order, nullsPlacement), nil
+	return " ORDER BY " + strings.Join(terms, ", "), nil
 }
 
 func (r *LicenseRepository) Update(ctx context.Context, lic *license.License) error {
@@ -254,12 +433,20 @@ func (r *LicenseRepository) Update(ctx context.Context, lic *license.License) er
             type = $2,
             customer_name = $3,
             customer_email = $4,
-            product_name = $5,
-            metadata = $6,
-            issued_at = $7,
-            expires_at = $8
+            customer_id = $5,
+            product_id = $6,
+            product_name = $7,
+            metadata = $8,
+            issued_at = $9,
+            expires_at = $10,
+            max_activations = $11,
+            grace_period_days = $12,
+            tags = $13,
+            operator_notes = $14,
+            starts_at = $15,
+            floating = $16
             -- updated_at обновляется триггером
-        WHERE id = $9
+        WHERE id = $17 AND (status <> 'revoked' OR $1 = 'revoked')
     `
 
 	cmdTag, err := r.db.Exec(ctx, query,
@@ -267,62 +454,126 @@ func (r *LicenseRepository) Update(ctx context.Context, lic *license.License) er
 		lic.Type,
 		lic.CustomerName,
 		lic.CustomerEmail,
+		lic.CustomerID,
+		lic.ProductID,
 		lic.ProductName,
 		lic.Metadata,
 		lic.IssuedAt,
 		lic.ExpiresAt,
+		maxActivations(lic),
+		lic.GracePeriodDays,
+		licenseTags(lic),
+		lic.OperatorNotes,
+		lic.StartsAt,
+		lic.Floating,
 		lic.ID,
 	)
 
 	if err != nil {
 		r.logger.Error("Failed to update license in database", zap.String("id", lic.ID.String()), zap.Error(err))
 
-		return fmt.Errorf("database error on update license: %w", err)
+		return fmt.Errorf("database error on update license: %w", mapError(err))
 	}
 
 	if cmdTag.RowsAffected() == 0 {
+		if err := r.revokedOrMissing(ctx, lic.ID); !errors.Is(err, ierr.ErrNotFound) {
+			return err
+		}
 		r.logger.Warn("Attempted to update license, but no rows were affected (likely not found)", zap.String("id", lic.ID.String()))
 
-		return fmt.Errorf("license with ID %s not found for update", lic.ID)
+		return fmt.Errorf("%w: license with ID %s not found for update", ierr.ErrNotFound, lic.ID)
 	}
 
 	r.logger.Info("License updated successfully", zap.String("id", lic.ID.String()))
 	return nil
 }
 
-func (r *LicenseRepository) scanLicense(row pgx.Row) (*license.License, error) {
+// scanLicense scans the license columns of row, followed by extra when the
+// query selects more.
+// WithLock holds a session-level advisory lock on its own connection while
+// fn runs on the pool. The key is the one ActivationRepository.Activate
+// locks, so without sharding the lock also waits for activations of the
+// license.
+func (r *LicenseRepository) WithLock(ctx context.Context, id uuid.UUID, fn func(ctx context.Context) error) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("database error acquiring connection to lock license %s: %w", id, mapError(err))
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock(hashtext($1))`, id.String()); err != nil {
+		r.logger.Error("Failed to lock license", zap.String("id", id.String()), zap.Error(err))
+		return fmt.Errorf("database error locking license %s: %w", id, mapError(err))
+	}
+	defer func() {
+		unlockCtx := context.WithoutCancel(ctx)
+		if _, err := conn.Exec(unlockCtx, `SELECT pg_advisory_unlock(hashtext($1))`, id.String()); err != nil {
+			// Ending the session releases the lock as well.
+			r.logger.Warn("Failed to unlock license, closing its connection", zap.String("id", id.String()), zap.Error(err))
+			_ = conn.Conn().Close(unlockCtx)
+		}
+	}()
+	return fn(ctx)
+}
+
+// revokedOrMissing explains an update of license id that matched no row.
+// Revoked is terminal, so the updates only match a revoked license when they
+// keep it revoked: callers check the transition first, but a concurrent
+// revoke may land between their read and the update.
+func (r *LicenseRepository) revokedOrMissing(ctx context.Context, id uuid.UUID) error {
+	var exists bool
+	if err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM licenses WHERE id = $1)`, id).Scan(&exists); err != nil {
+		r.logger.Error("Failed to check license after rejected update", zap.String("id", id.String()), zap.Error(err))
+		return fmt.Errorf("database error checking license %s: %w", id, mapError(err))
+	}
+	if !exists {
+		return ierr.ErrNotFound
+	}
+	r.logger.Warn("Rejected update of revoked license", zap.String("id", id.String()))
+	return fmt.Errorf("%w: license %s has been revoked", license.ErrInvalidStatusTransition, id)
+}
+
+func (r *LicenseRepository) scanLicense(row pgx.Row, extra ...any) (*license.License, error) {
 	var lic license.License
-	err := row.Scan(
+	err := row.Scan(append([]any{
 		&lic.ID,
 		&lic.LicenseKey,
 		&lic.Status,
 		&lic.Type,
 		&lic.CustomerName,
 		&lic.CustomerEmail,
+		&lic.CustomerID,
+		&lic.ProductID,
 		&lic.ProductName,
 		&lic.Metadata,
 		&lic.IssuedAt,
 		&lic.ExpiresAt,
+		&lic.MaxActivations,
+		&lic.GracePeriodDays,
+		&lic.Tags,
+		&lic.OperatorNotes,
+		&lic.StartsAt,
+		&lic.RevokedAt,
+		&lic.Floating,
+		&lic.ParentID,
 		&lic.CreatedAt,
 		&lic.UpdatedAt,
-	)
+	}, extra...)...)
 
 	if err != nil {
-
 		if errors.Is(err, pgx.ErrNoRows) {
-
-			return nil, err
+			return nil, ierr.ErrNotFound
 		}
 
 		r.logger.Error("Failed to scan license row", zap.Error(err))
-		return nil, fmt.Errorf("database scan error: %w", err)
+		return nil, fmt.Errorf("database scan error: %w", mapError(err))
 	}
 
 	return &lic, nil
 }
 
 func (r *LicenseRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status license.LicenseStatus) error {
-	query := `UPDATE licenses SET status = $1 WHERE id = $2`
+	query := `UPDATE licenses SET status = $1 WHERE id = $2 AND (status <> 'revoked' OR $1 = 'revoked')`
 
 	cmdTag, err := r.db.Exec(ctx, query, status, id)
 	if err != nil {
@@ -332,10 +583,16 @@ func (r *LicenseRepository) UpdateStatus(ctx context.Context, id uuid.UUID, stat
 			zap.Error(err),
 		)
 
+		if mapped := mapError(err); mapped != err {
+			return fmt.Errorf("error updating status for license %s: %w", id, mapped)
+		}
 		return fmt.Errorf("%w: error updating status for license %s: %v", ierr.ErrUpdateFailed, id, err)
 	}
 
 	if cmdTag.RowsAffected() == 0 {
+		if err := r.revokedOrMissing(ctx, id); !errors.Is(err, ierr.ErrNotFound) {
+			return err
+		}
 		r.logger.Warn("Attempted to update status, but license was not found",
 			zap.String("id", id.String()),
 			zap.String("new_status", string(status)),
@@ -355,6 +612,7 @@ func (r *LicenseRepository) GetDashboardSummary(ctx context.Context, expiringPer
 		StatusCounts:  make(map[license.LicenseStatus]int64),
 		TypeCounts:    make(map[string]int64),
 		ProductCounts: make(map[string]int64),
+		TagCounts:     make(map[string]int64),
 	}
 	var err error
 
@@ -429,6 +687,27 @@ func (r *LicenseRepository) GetDashboardSummary(ctx context.Context, expiringPer
 		return nil, fmt.Errorf("db iteration error for product counts: %w", err)
 	}
 
+	rowsTag, err := dbExecutor.Query(ctx, "SELECT tag, COUNT(*) FROM licenses, unnest(tags) AS tag GROUP BY tag")
+	if err != nil {
+		r.logger.Error("Failed to get license counts by tag", zap.Error(err))
+		return nil, fmt.Errorf("db error counting by tag: %w", err)
+	}
+	for rowsTag.Next() {
+		var tag string
+		var count int64
+		if err := rowsTag.Scan(&tag, &count); err != nil {
+			rowsTag.Close()
+			r.logger.Error("Failed to scan tag count row", zap.Error(err))
+			return nil, fmt.Errorf("db scan error for tag counts: %w", err)
+		}
+		summary.TagCounts[tag] = count
+	}
+	rowsTag.Close()
+	if err = rowsTag.Err(); err != nil {
+		r.logger.Error("Error iterating tag counts", zap.Error(err))
+		return nil, fmt.Errorf("db iteration error for tag counts: %w", err)
+	}
+
 	now := time.Now().UTC()
 	expiresSoonDate := now.AddDate(0, 0, expiringPeriodDays)
 
@@ -485,9 +764,373 @@ func (r *LicenseRepository) UpdateMetadata(ctx context.Context, id uuid.UUID, me
 
 	if cmdTag.RowsAffected() == 0 {
 		r.logger.Warn("Attempted to update metadata, but license was not found", zap.String("id", id.String()))
-		return nil
+		return ierr.ErrNotFound
 	}
 
 	r.logger.Info("License metadata updated successfully", zap.String("id", id.String()))
 	return nil
 }
+
+func (r *LicenseRepository) ListRecentlyValidated(ctx context.Context, limit int) ([]*license.License, error) {
+	query := `
+        SELECT
+            id, license_key, status, type, customer_name, customer_email, customer_id,
+            product_id, product_name, metadata, issued_at, expires_at, max_activations, grace_period_days, tags, operator_notes, starts_at, revoked_at, floating, parent_id, created_at, updated_at
+        FROM licenses
+        WHERE status = $1 AND metadata ? 'last_validated_at'
+        ORDER BY (metadata->>'last_validated_at')::timestamptz DESC
+        LIMIT $2
+    `
+
+	rows, err := r.db.Query(ctx, query, license.StatusActive, limit)
+	if err != nil {
+		r.logger.Error("Failed to query recently validated licenses", zap.Error(err))
+		return nil, fmt.Errorf("database error on list recently validated licenses: %w", err)
+	}
+	defer rows.Close()
+
+	licenses := make([]*license.License, 0, limit)
+	for rows.Next() {
+		lic, err := r.scanLicense(rows)
+		if err != nil {
+			return nil, err
+		}
+		licenses = append(licenses, lic)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Error iterating recently validated license rows", zap.Error(err))
+		return nil, fmt.Errorf("database iteration error on list recently validated licenses: %w", err)
+	}
+
+	return licenses, nil
+}
+
+func (r *LicenseRepository) RotateKey(ctx context.Context, id uuid.UUID, newKey func() (string, error), oldKeyValidUntil time.Time) (*license.License, error) {
+	key, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+	return r.rotateKey(ctx, id, key, oldKeyValidUntil)
+}
+
+// rotateKey moves the current key of the license to superseded_keys and
+// sets key in its place, in one transaction.
+func (r *LicenseRepository) rotateKey(ctx context.Context, id uuid.UUID, key string, oldKeyValidUntil time.Time) (*license.License, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("database error starting key rotation: %w", mapError(err))
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var oldKey string
+	if err := tx.QueryRow(ctx, `SELECT license_key FROM licenses WHERE id = $1 FOR UPDATE`, id).Scan(&oldKey); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to lock license for key rotation", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error locking license for key rotation: %w", mapError(err))
+	}
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO superseded_keys (license_key, license_id, valid_until) VALUES ($1, $2, $3)`,
+		oldKey, id, oldKeyValidUntil,
+	); err != nil {
+		r.logger.Error("Failed to store superseded license key", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error storing superseded key: %w", mapError(err))
+	}
+
+	lic, err := r.scanLicense(tx.QueryRow(ctx, `
+        UPDATE licenses SET license_key = $1
+        WHERE id = $2
+        RETURNING
+            id, license_key, status, type, customer_name, customer_email, customer_id,
+            product_id, product_name, metadata, issued_at, expires_at, max_activations, grace_period_days, tags, operator_notes, starts_at, revoked_at, floating, parent_id, created_at, updated_at
+    `, key, id))
+	if err != nil {
+		if errors.Is(err, ierr.ErrDuplicateKey) {
+			return nil, fmt.Errorf("%w: new key of license %s already exists", ierr.ErrDuplicateKey, id)
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("database error committing key rotation: %w", mapError(err))
+	}
+
+	r.logger.Info("License key rotated", zap.String("id", id.String()), zap.Time("old_key_valid_until", oldKeyValidUntil))
+	return lic, nil
+}
+
+func (r *LicenseRepository) insertWithID(ctx context.Context, lic *license.License) error {
+	query := `
+        INSERT INTO licenses (
+            id, license_key, status, type, customer_name, customer_email, customer_id,
+            product_id, product_name, metadata, issued_at, expires_at, max_activations, grace_period_days, tags, operator_notes, starts_at, revoked_at, floating, parent_id, created_at, updated_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22
+        )
+    `
+
+	_, err := r.db.Exec(ctx, query,
+		lic.ID,
+		lic.LicenseKey,
+		lic.Status,
+		lic.Type,
+		lic.CustomerName,
+		lic.CustomerEmail,
+		lic.CustomerID,
+		lic.ProductID,
+		lic.ProductName,
+		lic.Metadata,
+		lic.IssuedAt,
+		lic.ExpiresAt,
+		maxActivations(lic),
+		lic.GracePeriodDays,
+		licenseTags(lic),
+		lic.OperatorNotes,
+		lic.StartsAt,
+		lic.RevokedAt,
+		lic.Floating,
+		lic.ParentID,
+		lic.CreatedAt,
+		lic.UpdatedAt,
+	)
+	if err != nil {
+		err = mapError(err)
+		if errors.Is(err, ierr.ErrDuplicateKey) {
+			return fmt.Errorf("%w: license %s already exists", ierr.ErrDuplicateKey, lic.ID)
+		}
+		r.logger.Error("Failed to insert license with explicit ID", zap.String("id", lic.ID.String()), zap.Error(err))
+		return fmt.Errorf("database error on insert license: %w", err)
+	}
+	return nil
+}
+
+// maxActivations treats an unset seat count as the default so callers that
+// build licenses by hand do not violate the column check.
+func maxActivations(lic *license.License) int {
+	if lic.MaxActivations < 1 {
+		return license.DefaultMaxActivations
+	}
+	return lic.MaxActivations
+}
+
+// licenseTags stores licenses without tags as an empty array rather than
+// NULL, which the column does not allow.
+func licenseTags(lic *license.License) []string {
+	if lic.Tags == nil {
+		return []string{}
+	}
+	return lic.Tags
+}
+
+func (r *LicenseRepository) deleteByID(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM licenses WHERE id = $1`, id); err != nil {
+		r.logger.Error("Failed to delete license", zap.String("id", id.String()), zap.Error(err))
+		return fmt.Errorf("database error on delete license: %w", mapError(err))
+	}
+	return nil
+}
+
+var aggregateColumns = map[string]string{
+	"product":    "product_name",
+	"type":       "type",
+	"status":     "status",
+	"customer":   "customer_email",
+	"created_at": "created_at",
+	"issued_at":  "issued_at",
+	"expires_at": "expires_at",
+}
+
+var aggregateBuckets = map[string]bool{"day": true, "week": true, "month": true, "year": true}
+
+const maxAggregateRows = 1000
+
+func buildAggregateColumn(dim license.AggregateDimension) (string, error) {
+	column, ok := aggregateColumns[dim.Name]
+	if !ok {
+		return "", fmt.Errorf("%w: invalid group_by dimension: %s", ierr.ErrValidation, dim.Name)
+	}
+
+	isDate := column == "created_at" || column == "issued_at" || column == "expires_at"
+	switch {
+	case isDate && dim.Bucket == "":
+		return "", fmt.Errorf("%w: dimension %s needs a bucket (day, week, month or year)", ierr.ErrValidation, dim.Name)
+	case isDate && !aggregateBuckets[dim.Bucket]:
+		return "", fmt.Errorf("%w: invalid bucket %s for %s", ierr.ErrValidation, dim.Bucket, dim.Name)
+	case !isDate && dim.Bucket != "":
+		return "", fmt.Errorf("%w: dimension %s cannot be bucketed", ierr.ErrValidation, dim.Name)
+	case isDate:
+		return fmt.Sprintf("to_char(date_trunc('%s', %s AT TIME ZONE 'UTC'), 'YYYY-MM-DD')", dim.Bucket, column), nil
+	default:
+		return column + "::text", nil
+	}
+}
+
+func (r *LicenseRepository) Aggregate(ctx context.Context, params license.AggregateParams) ([]license.AggregateRow, error) {
+	if len(params.GroupBy) == 0 {
+		return nil, fmt.Errorf("%w: group_by is required", ierr.ErrValidation)
+	}
+
+	var metricExpr string
+	switch params.Metric {
+	case license.MetricCount, "":
+		metricExpr = "COUNT(*)"
+	case license.MetricCustomers:
+		metricExpr = "COUNT(DISTINCT customer_email)"
+	default:
+		return nil, fmt.Errorf("%w: invalid metric: %s", ierr.ErrValidation, params.Metric)
+	}
+
+	groupExprs := make([]string, len(params.GroupBy))
+	positions := make([]string, len(params.GroupBy))
+	for i, dim := range params.GroupBy {
+		expr, err := buildAggregateColumn(dim)
+		if err != nil {
+			return nil, err
+		}
+		groupExprs[i] = expr
+		positions[i] = strconv.Itoa(i + 1)
+	}
+
+	var query strings.Builder
+	args := make([]interface{}, 0, 4)
+	query.WriteString("SELECT " + strings.Join(groupExprs, ", ") + ", " + metricExpr + " FROM licenses")
+
+	conditions := make([]string, 0, 3)
+	if params.Status != nil {
+		args = append(args, *params.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if params.ProductName != nil {
+		args = append(args, *params.ProductName)
+		conditions = append(conditions, fmt.Sprintf("product_name = $%d", len(args)))
+	}
+	if params.Type != nil {
+		args = append(args, *params.Type)
+		conditions = append(conditions, fmt.Sprintf("type = $%d", len(args)))
+	}
+	if len(conditions) > 0 {
+		query.WriteString(" WHERE " + strings.Join(conditions, " AND "))
+	}
+
+	order := strings.Join(positions, ", ")
+	query.WriteString(" GROUP BY " + order + " ORDER BY " + order)
+	args = append(args, maxAggregateRows)
+	query.WriteString(fmt.Sprintf(" LIMIT $%d", len(args)))
+
+	sql := query.String()
+	r.logger.Debug("Executing aggregate query", zap.String("sql", sql), zap.Int("arg_count", len(args)))
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		r.logger.Error("Failed to aggregate licenses", zap.Error(err))
+		return nil, fmt.Errorf("database error aggregating licenses: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	result := make([]license.AggregateRow, 0)
+	for rows.Next() {
+		row := license.AggregateRow{Group: make([]*string, len(params.GroupBy))}
+		dest := make([]interface{}, 0, len(params.GroupBy)+1)
+		for i := range row.Group {
+			dest = append(dest, &row.Group[i])
+		}
+		dest = append(dest, &row.Value)
+
+		if err := rows.Scan(dest...); err != nil {
+			r.logger.Error("Failed to scan aggregate row", zap.Error(err))
+			return nil, fmt.Errorf("database scan error aggregating licenses: %w", err)
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("Error iterating aggregate rows", zap.Error(err))
+		return nil, fmt.Errorf("database error iterating aggregate rows: %w", mapError(err))
+	}
+	return result, nil
+}
+
+func (r *LicenseRepository) ArchiveCandidates(ctx context.Context, cutoff time.Time, limit int) ([]uuid.UUID, error) {
+	query := `
+        SELECT l.id
+        FROM licenses l
+        WHERE l.status IN ('expired', 'revoked') AND l.updated_at < $1
+          AND NOT EXISTS (SELECT 1 FROM licenses c WHERE c.parent_id = l.id)
+        ORDER BY l.updated_at ASC
+        LIMIT $2
+    `
+
+	rows, err := r.db.Query(ctx, query, cutoff, limit)
+	if err != nil {
+		r.logger.Error("Failed to query archive candidates", zap.Error(err))
+		return nil, fmt.Errorf("database error on list archive candidates: %w", mapError(err))
+	}
+	return r.collectIDs(rows, "archive candidates")
+}
+
+// Archive moves the licenses in one statement, so a license is either in
+// licenses or in licenses_archive. The conditions of ArchiveCandidates are
+// checked again, as a license may have changed since.
+func (r *LicenseRepository) Archive(ctx context.Context, ids []uuid.UUID, cutoff time.Time) ([]uuid.UUID, error) {
+	query := `
+        WITH moved AS (
+            DELETE FROM licenses l
+            WHERE l.id = ANY($1) AND l.status IN ('expired', 'revoked') AND l.updated_at < $2
+              AND NOT EXISTS (SELECT 1 FROM licenses c WHERE c.parent_id = l.id)
+            RETURNING
+                id, license_key, status, type, customer_name, customer_email, customer_id,
+                product_id, product_name, metadata, issued_at, expires_at, max_activations, grace_period_days, tags, operator_notes, starts_at, revoked_at, floating, parent_id, created_at, updated_at
+        )
+        INSERT INTO licenses_archive (
+            id, license_key, status, type, customer_name, customer_email, customer_id,
+            product_id, product_name, metadata, issued_at, expires_at, max_activations, grace_period_days, tags, operator_notes, starts_at, revoked_at, floating, parent_id, created_at, updated_at
+        )
+        SELECT * FROM moved
+        RETURNING id
+    `
+
+	rows, err := r.db.Query(ctx, query, ids, cutoff)
+	if err != nil {
+		r.logger.Error("Failed to archive licenses", zap.Int("count", len(ids)), zap.Error(err))
+		return nil, fmt.Errorf("database error on archive licenses: %w", mapError(err))
+	}
+	return r.collectIDs(rows, "archived licenses")
+}
+
+func (r *LicenseRepository) FindArchivedByID(ctx context.Context, id uuid.UUID) (*license.Archived, error) {
+	query := `
+        SELECT
+            id, license_key, status, type, customer_name, customer_email, customer_id,
+            product_id, product_name, metadata, issued_at, expires_at, max_activations, grace_period_days, tags, operator_notes, starts_at, revoked_at, floating, parent_id, created_at, updated_at,
+            archived_at
+        FROM licenses_archive
+        WHERE id = $1
+    `
+
+	var archivedAt time.Time
+	lic, err := r.scanLicense(r.db.QueryRow(ctx, query, id), &archivedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &license.Archived{License: *lic, ArchivedAt: archivedAt}, nil
+}
+
+func (r *LicenseRepository) collectIDs(rows pgx.Rows, what string) ([]uuid.UUID, error) {
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			r.logger.Error("Failed to scan license id", zap.String("rows", what), zap.Error(err))
+			return nil, fmt.Errorf("database scan error on %s: %w", what, mapError(err))
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("Error iterating license id rows", zap.String("rows", what), zap.Error(err))
+		return nil, fmt.Errorf("database iteration error on %s: %w", what, mapError(err))
+	}
+	return ids, nil
+}
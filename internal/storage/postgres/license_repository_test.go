@@ -0,0 +1,87 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/license/repotest"
+	"github.com/makkenzo/license-service-api/internal/domain/product"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/storage/postgres"
+	"go.uber.org/zap"
+)
+
+// The repository tests need migrated databases they may wipe:
+// TEST_DATABASE_URL for the plain repository and, for the sharded one,
+// TEST_DATABASE_SHARD_URLS with a comma-separated URL per shard. The
+// licenses tables are truncated before every run.
+const (
+	testDatabaseURLEnv       = "TEST_DATABASE_URL"
+	testDatabaseShardURLsEnv = "TEST_DATABASE_SHARD_URLS"
+)
+
+func TestLicenseRepository(t *testing.T) {
+	url := os.Getenv(testDatabaseURLEnv)
+	if url == "" {
+		t.Skip(testDatabaseURLEnv + " is not set")
+	}
+	pool := testPool(t, url)
+	products := postgres.NewProductRepository(pool, idgen.UUIDGenerator{}, zap.NewNop())
+
+	repotest.Run(t, func(t *testing.T) license.Repository {
+		return postgres.NewLicenseRepository(pool, idgen.UUIDGenerator{}, zap.NewNop())
+	}, productFactory(products))
+}
+
+func TestShardedLicenseRepository(t *testing.T) {
+	url, shardURLs := os.Getenv(testDatabaseURLEnv), os.Getenv(testDatabaseShardURLsEnv)
+	if url == "" || shardURLs == "" {
+		t.Skip(testDatabaseURLEnv + " or " + testDatabaseShardURLsEnv + " is not set")
+	}
+	primary := postgres.NewProductRepository(testPool(t, url), idgen.UUIDGenerator{}, zap.NewNop())
+	var shards []*postgres.LicenseRepository
+	var shardProducts []*postgres.ProductRepository
+	for _, shardURL := range strings.Split(shardURLs, ",") {
+		pool := testPool(t, strings.TrimSpace(shardURL))
+		shards = append(shards, postgres.NewLicenseRepository(pool, idgen.UUIDGenerator{}, zap.NewNop()))
+		shardProducts = append(shardProducts, postgres.NewProductRepository(pool, idgen.UUIDGenerator{}, zap.NewNop()))
+	}
+	products := postgres.NewShardedProductRepository(primary, shardProducts, zap.NewNop())
+
+	repotest.Run(t, func(t *testing.T) license.Repository {
+		return postgres.NewShardedLicenseRepository(shards, zap.NewNop())
+	}, productFactory(products))
+}
+
+// testPool connects to url and empties its licenses table, since the
+// conformance suite expects to start without licenses.
+func testPool(t *testing.T, url string) *pgxpool.Pool {
+	t.Helper()
+	ctx := context.Background()
+	pool, err := postgres.NewPgxPool(ctx, &config.DatabaseConfig{URL: url, MaxOpenConns: 20, MaxIdleConns: 1}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("connecting to test database: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	if _, err := pool.Exec(ctx, "TRUNCATE licenses CASCADE"); err != nil {
+		t.Fatalf("emptying test database: %v", err)
+	}
+	return pool
+}
+
+func productFactory(products product.Repository) repotest.ProductFactory {
+	return func(t *testing.T, name string) uuid.UUID {
+		t.Helper()
+		p := &product.Product{Name: name, CustomerLicenseCaps: map[string]int{}}
+		if err := products.CreateProduct(context.Background(), p); err != nil {
+			t.Fatalf("creating product %s: %v", name, err)
+		}
+		return p.ID
+	}
+}
@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/licensetemplate"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type LicenseTemplateRepository struct {
+	db     *pgxpool.Pool
+	ids    idgen.Generator
+	logger *zap.Logger
+}
+
+func NewLicenseTemplateRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *LicenseTemplateRepository {
+	return &LicenseTemplateRepository{
+		db:     db,
+		ids:    ids,
+		logger: logger.Named("LicenseTemplateRepository"),
+	}
+}
+
+var _ licensetemplate.Repository = (*LicenseTemplateRepository)(nil)
+
+const licenseTemplateSelect = `
+        SELECT t.id, t.name, t.product_id, p.name, t.type, t.duration_days, t.max_activations,
+               t.grace_period_days, t.entitlements, t.metadata, t.created_at, t.updated_at
+        FROM license_templates t
+        JOIN products p ON p.id = t.product_id`
+
+func scanLicenseTemplate(row pgx.Row) (*licensetemplate.Template, error) {
+	var t licensetemplate.Template
+	err := row.Scan(&t.ID, &t.Name, &t.ProductID, &t.ProductName, &t.Type, &t.DurationDays, &t.MaxActivations,
+		&t.GracePeriodDays, &t.Entitlements, &t.Metadata, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if t.Entitlements == nil {
+		t.Entitlements = []licensetemplate.Entitlement{}
+	}
+	return &t, nil
+}
+
+func (r *LicenseTemplateRepository) Create(ctx context.Context, t *licensetemplate.Template) error {
+	t.ID = r.ids.New()
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO license_templates (id, name, product_id, type, duration_days, max_activations, grace_period_days, entitlements, metadata)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        RETURNING created_at, updated_at
+    `, t.ID, t.Name, t.ProductID, t.Type, t.DurationDays, t.MaxActivations, t.GracePeriodDays, t.Entitlements, t.Metadata).Scan(&t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		err = mapError(err)
+		if errors.Is(err, ierr.ErrDuplicateKey) {
+			return fmt.Errorf("%w: license template '%s' already exists", ierr.ErrDuplicateKey, t.Name)
+		}
+		r.logger.Error("Failed to create license template", zap.String("name", t.Name), zap.Error(err))
+		return fmt.Errorf("database error creating license template: %w", err)
+	}
+	return nil
+}
+
+func (r *LicenseTemplateRepository) FindByID(ctx context.Context, id uuid.UUID) (*licensetemplate.Template, error) {
+	t, err := scanLicenseTemplate(r.db.QueryRow(ctx, licenseTemplateSelect+` WHERE t.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find license template by ID", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error finding license template: %w", mapError(err))
+	}
+	return t, nil
+}
+
+func (r *LicenseTemplateRepository) List(ctx context.Context, productID *uuid.UUID) ([]*licensetemplate.Template, error) {
+	query, args := licenseTemplateSelect, []interface{}{}
+	if productID != nil {
+		query += ` WHERE t.product_id = $1`
+		args = append(args, *productID)
+	}
+	rows, err := r.db.Query(ctx, query+` ORDER BY lower(t.name)`, args...)
+	if err != nil {
+		r.logger.Error("Failed to list license templates", zap.Error(err))
+		return nil, fmt.Errorf("database error listing license templates: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	templates := []*licensetemplate.Template{}
+	for rows.Next() {
+		t, err := scanLicenseTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("database error scanning license template: %w", mapError(err))
+		}
+		templates = append(templates, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("database iteration error listing license templates: %w", err)
+	}
+	return templates, nil
+}
+
+func (r *LicenseTemplateRepository) Update(ctx context.Context, t *licensetemplate.Template) error {
+	err := r.db.QueryRow(ctx, `
+        UPDATE license_templates
+        SET name = $1, product_id = $2, type = $3, duration_days = $4, max_activations = $5,
+            grace_period_days = $6, entitlements = $7, metadata = $8
+        WHERE id = $9
+        RETURNING updated_at
+    `, t.Name, t.ProductID, t.Type, t.DurationDays, t.MaxActivations, t.GracePeriodDays, t.Entitlements, t.Metadata, t.ID).Scan(&t.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: license template with ID %s not found for update", ierr.ErrNotFound, t.ID)
+		}
+		err = mapError(err)
+		if errors.Is(err, ierr.ErrDuplicateKey) {
+			return fmt.Errorf("%w: license template '%s' already exists", ierr.ErrDuplicateKey, t.Name)
+		}
+		r.logger.Error("Failed to update license template", zap.String("id", t.ID.String()), zap.Error(err))
+		return fmt.Errorf("database error updating license template: %w", err)
+	}
+	return nil
+}
+
+func (r *LicenseTemplateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	cmdTag, err := r.db.Exec(ctx, `DELETE FROM license_templates WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete license template", zap.String("id", id.String()), zap.Error(err))
+		return fmt.Errorf("database error deleting license template: %w", mapError(err))
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ierr.ErrNotFound
+	}
+	return nil
+}
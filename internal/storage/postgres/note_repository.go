@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/note"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type NoteRepository struct {
+	db     *pgxpool.Pool
+	ids    idgen.Generator
+	logger *zap.Logger
+}
+
+func NewNoteRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *NoteRepository {
+	return &NoteRepository{
+		db:     db,
+		ids:    ids,
+		logger: logger.Named("NoteRepository"),
+	}
+}
+
+var _ note.Repository = (*NoteRepository)(nil)
+
+const noteColumns = `id, license_id, customer_id, source, external_id, author, subject, body, created_at`
+
+func scanNote(row pgx.Row) (*note.Note, error) {
+	var n note.Note
+	if err := row.Scan(&n.ID, &n.LicenseID, &n.CustomerID, &n.Source, &n.ExternalID, &n.Author, &n.Subject, &n.Body, &n.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func (r *NoteRepository) Create(ctx context.Context, n *note.Note) error {
+	n.ID = r.ids.New()
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO notes (id, license_id, customer_id, source, external_id, author, subject, body)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING created_at
+    `, n.ID, n.LicenseID, n.CustomerID, n.Source, n.ExternalID, n.Author, n.Subject, n.Body).Scan(&n.CreatedAt)
+	if err != nil {
+		err = mapError(err)
+		if errors.Is(err, ierr.ErrDuplicateKey) {
+			return fmt.Errorf("%w: note from %s already exists", ierr.ErrDuplicateKey, n.Source)
+		}
+		r.logger.Error("Failed to create note", zap.String("source", string(n.Source)), zap.Error(err))
+		return fmt.Errorf("database error creating note: %w", err)
+	}
+	return nil
+}
+
+func (r *NoteRepository) List(ctx context.Context, params note.ListParams) ([]*note.Note, int64, error) {
+	conditions := make([]string, 0, 2)
+	args := make([]interface{}, 0, 4)
+	if params.LicenseID != nil {
+		args = append(args, *params.LicenseID)
+		conditions = append(conditions, fmt.Sprintf("license_id = $%d", len(args)))
+	}
+	if params.CustomerID != nil {
+		args = append(args, *params.CustomerID)
+		conditions = append(conditions, fmt.Sprintf("customer_id = $%d", len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM notes`+where, args...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count notes", zap.Error(err))
+		return nil, 0, fmt.Errorf("database error counting notes: %w", mapError(err))
+	}
+	if total == 0 {
+		return []*note.Note{}, 0, nil
+	}
+
+	query := `SELECT ` + noteColumns + ` FROM notes` + where + fmt.Sprintf(`
+        ORDER BY created_at DESC, id DESC
+        LIMIT $%d OFFSET $%d
+    `, len(args)+1, len(args)+2)
+	rows, err := r.db.Query(ctx, query, append(args, params.Limit, params.Offset)...)
+	if err != nil {
+		r.logger.Error("Failed to list notes", zap.Error(err))
+		return nil, 0, fmt.Errorf("database error listing notes: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	notes := make([]*note.Note, 0, params.Limit)
+	for rows.Next() {
+		n, err := scanNote(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("database error scanning note: %w", mapError(err))
+		}
+		notes = append(notes, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("database iteration error listing notes: %w", err)
+	}
+	return notes, total, nil
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/offlineactivation"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type OfflineActivationRepository struct {
+	db     *pgxpool.Pool
+	ids    idgen.Generator
+	logger *zap.Logger
+}
+
+func NewOfflineActivationRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *OfflineActivationRepository {
+	return &OfflineActivationRepository{
+		db:     db,
+		ids:    ids,
+		logger: logger.Named("OfflineActivationRepository"),
+	}
+}
+
+var _ offlineactivation.Repository = (*OfflineActivationRepository)(nil)
+
+const offlineChallengeColumns = `id, license_id, device_id, nonce, created_at, expires_at, consumed_at`
+
+func scanOfflineChallenge(row pgx.Row) (*offlineactivation.Challenge, error) {
+	var c offlineactivation.Challenge
+	if err := row.Scan(&c.ID, &c.LicenseID, &c.DeviceID, &c.Nonce, &c.CreatedAt, &c.ExpiresAt, &c.ConsumedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *OfflineActivationRepository) Create(ctx context.Context, c *offlineactivation.Challenge) error {
+	c.ID = r.ids.New()
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO offline_activation_challenges (id, license_id, device_id, nonce, expires_at)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING created_at
+    `, c.ID, c.LicenseID, c.DeviceID, c.Nonce, c.ExpiresAt).Scan(&c.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create offline activation challenge", zap.String("license_id", c.LicenseID.String()), zap.Error(err))
+		return fmt.Errorf("database error creating offline activation challenge: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *OfflineActivationRepository) FindByID(ctx context.Context, id uuid.UUID) (*offlineactivation.Challenge, error) {
+	c, err := scanOfflineChallenge(r.db.QueryRow(ctx, `SELECT `+offlineChallengeColumns+` FROM offline_activation_challenges WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find offline activation challenge", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error finding offline activation challenge: %w", mapError(err))
+	}
+	return c, nil
+}
+
+func (r *OfflineActivationRepository) Consume(ctx context.Context, id uuid.UUID) (*offlineactivation.Challenge, error) {
+	c, err := scanOfflineChallenge(r.db.QueryRow(ctx, `
+        UPDATE offline_activation_challenges SET consumed_at = NOW()
+        WHERE id = $1 AND consumed_at IS NULL AND expires_at > NOW()
+        RETURNING `+offlineChallengeColumns, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			if _, errFind := r.FindByID(ctx, id); errFind != nil {
+				return nil, errFind
+			}
+			return nil, fmt.Errorf("%w: offline activation challenge was already consumed or has expired", ierr.ErrConflict)
+		}
+		r.logger.Error("Failed to consume offline activation challenge", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error consuming offline activation challenge: %w", mapError(err))
+	}
+	return c, nil
+}
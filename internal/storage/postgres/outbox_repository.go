@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/outbox"
+	"go.uber.org/zap"
+)
+
+type OutboxRepository struct {
+	db     *pgxpool.Pool
+	logger *zap.Logger
+}
+
+func NewOutboxRepository(db *pgxpool.Pool, logger *zap.Logger) *OutboxRepository {
+	return &OutboxRepository{
+		db:     db,
+		logger: logger.Named("OutboxRepository"),
+	}
+}
+
+var _ outbox.Repository = (*OutboxRepository)(nil)
+
+// outboxFilter builds the WHERE clause of q, leaving out the limit.
+func outboxFilter(q outbox.Query) (string, []interface{}) {
+	fields := q.Fields
+	if fields == nil {
+		fields = []string{}
+	}
+	args := []interface{}{q.AfterID, fields}
+	where := ` WHERE id > $1 AND (cardinality($2::text[]) = 0 OR changed_fields && $2::text[])`
+
+	var licenseConditions []string
+	if q.ProductName != nil {
+		args = append(args, *q.ProductName)
+		licenseConditions = append(licenseConditions, fmt.Sprintf("product_name = $%d", len(args)))
+	}
+	if q.CustomerID != nil {
+		args = append(args, *q.CustomerID)
+		licenseConditions = append(licenseConditions, fmt.Sprintf("customer_id = $%d", len(args)))
+	}
+	if len(licenseConditions) > 0 {
+		where += ` AND license_id IN (SELECT id FROM licenses WHERE ` + strings.Join(licenseConditions, " AND ") + `)`
+	}
+	return where, args
+}
+
+func (r *OutboxRepository) ListAfter(ctx context.Context, q outbox.Query) ([]*outbox.Event, error) {
+	where, args := outboxFilter(q)
+	query := `
+        SELECT id, license_id, operation, changed_fields, COALESCE(status, ''), COALESCE(previous_status, ''), created_at
+        FROM license_outbox` + where + fmt.Sprintf(`
+        ORDER BY id ASC
+        LIMIT $%d`, len(args)+1)
+
+	rows, err := r.db.Query(ctx, query, append(args, q.Limit)...)
+	if err != nil {
+		r.logger.Error("Failed to query license outbox", zap.Int64("after_id", q.AfterID), zap.Error(err))
+		return nil, fmt.Errorf("database error listing outbox events: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	events := make([]*outbox.Event, 0, q.Limit)
+	for rows.Next() {
+		var event outbox.Event
+		if err := rows.Scan(&event.ID, &event.LicenseID, &event.Operation, &event.ChangedFields, &event.Status, &event.PreviousStatus, &event.CreatedAt); err != nil {
+			r.logger.Error("Failed to scan license outbox row", zap.Error(err))
+			return nil, fmt.Errorf("database scan error listing outbox events: %w", err)
+		}
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("Error iterating license outbox rows", zap.Error(err))
+		return nil, fmt.Errorf("database error iterating outbox events: %w", mapError(err))
+	}
+	return events, nil
+}
+
+func (r *OutboxRepository) StreamAfter(ctx context.Context, q outbox.Query, fn func(*outbox.Event) error) error {
+	where, args := outboxFilter(q)
+	query := `
+        SELECT id, license_id, operation, changed_fields, COALESCE(status, ''), COALESCE(previous_status, ''), created_at
+        FROM license_outbox` + where + `
+        ORDER BY id ASC`
+
+	err := streamCursor(ctx, r.db, query, args, func(rows pgx.Rows) error {
+		var event outbox.Event
+		if err := rows.Scan(&event.ID, &event.LicenseID, &event.Operation, &event.ChangedFields, &event.Status, &event.PreviousStatus, &event.CreatedAt); err != nil {
+			return fmt.Errorf("database scan error streaming outbox events: %w", err)
+		}
+		return fn(&event)
+	})
+	if err != nil && ctx.Err() == nil {
+		r.logger.Error("Failed to stream license outbox", zap.Int64("after_id", q.AfterID), zap.Error(err))
+	}
+	return err
+}
+
+func (r *OutboxRepository) GetCursor(ctx context.Context, consumer string) (int64, error) {
+	var lastID int64
+	err := r.db.QueryRow(ctx, `SELECT last_id FROM outbox_cursors WHERE consumer = $1`, consumer).Scan(&lastID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		r.logger.Error("Failed to read outbox cursor", zap.String("consumer", consumer), zap.Error(err))
+		return 0, fmt.Errorf("database error reading outbox cursor: %w", mapError(err))
+	}
+	return lastID, nil
+}
+
+func (r *OutboxRepository) SaveCursor(ctx context.Context, consumer string, lastID int64) error {
+	query := `
+        INSERT INTO outbox_cursors (consumer, last_id, updated_at)
+        VALUES ($1, $2, NOW())
+        ON CONFLICT (consumer) DO UPDATE
+        SET last_id = GREATEST(outbox_cursors.last_id, EXCLUDED.last_id), updated_at = NOW()
+    `
+	if _, err := r.db.Exec(ctx, query, consumer, lastID); err != nil {
+		r.logger.Error("Failed to save outbox cursor", zap.String("consumer", consumer), zap.Int64("last_id", lastID), zap.Error(err))
+		return fmt.Errorf("database error saving outbox cursor: %w", mapError(err))
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/makkenzo/license-service-api/internal/config"
+	"github.com/makkenzo/license-service-api/internal/servertiming"
 	"go.uber.org/zap"
 )
 
@@ -19,6 +20,7 @@ func NewPgxPool(ctx context.Context, cfg *config.DatabaseConfig, logger *zap.Log
 	pgxConfig.MaxConns = int32(cfg.MaxOpenConns)
 	pgxConfig.MinConns = int32(cfg.MaxIdleConns)
 	pgxConfig.MaxConnLifetime = cfg.ConnMaxLifetime
+	pgxConfig.ConnConfig.Tracer = servertiming.QueryTracer{}
 
 	connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
@@ -39,3 +41,21 @@ func NewPgxPool(ctx context.Context, cfg *config.DatabaseConfig, logger *zap.Log
 	logger.Info("Successfully connected to PostgreSQL")
 	return pool, nil
 }
+
+func NewShardPools(ctx context.Context, cfg *config.DatabaseConfig, logger *zap.Logger) ([]*pgxpool.Pool, error) {
+	pools := make([]*pgxpool.Pool, 0, len(cfg.ShardURLs))
+	for i, url := range cfg.ShardURLs {
+		shardCfg := *cfg
+		shardCfg.URL = url
+
+		pool, err := NewPgxPool(ctx, &shardCfg, logger.With(zap.Int("shard", i)))
+		if err != nil {
+			for _, p := range pools {
+				p.Close()
+			}
+			return nil, fmt.Errorf("shard %d: %w", i, err)
+		}
+		pools = append(pools, pool)
+	}
+	return pools, nil
+}
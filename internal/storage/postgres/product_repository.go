@@ -0,0 +1,354 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/product"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type ProductRepository struct {
+	db     *pgxpool.Pool
+	ids    idgen.Generator
+	logger *zap.Logger
+}
+
+func NewProductRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *ProductRepository {
+	return &ProductRepository{
+		db:     db,
+		ids:    ids,
+		logger: logger.Named("ProductRepository"),
+	}
+}
+
+var _ product.Repository = (*ProductRepository)(nil)
+
+const productColumns = `id, name, display_name, description, key_format, key_prefix, customer_license_caps, metadata_schema, created_at, updated_at`
+
+func scanProduct(row pgx.Row) (*product.Product, error) {
+	var p product.Product
+	if err := row.Scan(&p.ID, &p.Name, &p.DisplayName, &p.Description, &p.KeyFormat, &p.KeyPrefix, &p.CustomerLicenseCaps, &p.MetadataSchema, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// customerLicenseCaps keeps a nil map from being stored as JSON null.
+func customerLicenseCaps(p *product.Product) map[string]int {
+	if p.CustomerLicenseCaps == nil {
+		return map[string]int{}
+	}
+	return p.CustomerLicenseCaps
+}
+
+func (r *ProductRepository) CreateProduct(ctx context.Context, p *product.Product) error {
+	p.ID = r.ids.New()
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO products (id, name, display_name, description, key_format, key_prefix, customer_license_caps, metadata_schema)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING created_at, updated_at
+    `, p.ID, p.Name, p.DisplayName, p.Description, p.KeyFormat, p.KeyPrefix, customerLicenseCaps(p), p.MetadataSchema).Scan(&p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		err = mapError(err)
+		if errors.Is(err, ierr.ErrDuplicateKey) {
+			return fmt.Errorf("%w: product '%s' already exists", ierr.ErrDuplicateKey, p.Name)
+		}
+		r.logger.Error("Failed to create product", zap.String("name", p.Name), zap.Error(err))
+		return fmt.Errorf("database error creating product: %w", err)
+	}
+	return nil
+}
+
+func (r *ProductRepository) FindProductByID(ctx context.Context, id uuid.UUID) (*product.Product, error) {
+	p, err := scanProduct(r.db.QueryRow(ctx, `SELECT `+productColumns+` FROM products WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find product by ID", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error finding product: %w", mapError(err))
+	}
+	return p, nil
+}
+
+func (r *ProductRepository) FindProductByName(ctx context.Context, name string) (*product.Product, error) {
+	p, err := scanProduct(r.db.QueryRow(ctx, `SELECT `+productColumns+` FROM products WHERE name = $1`, name))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find product by name", zap.String("name", name), zap.Error(err))
+		return nil, fmt.Errorf("database error finding product: %w", mapError(err))
+	}
+	return p, nil
+}
+
+func (r *ProductRepository) ListProducts(ctx context.Context) ([]*product.Product, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+productColumns+` FROM products ORDER BY name ASC`)
+	if err != nil {
+		r.logger.Error("Failed to list products", zap.Error(err))
+		return nil, fmt.Errorf("database error listing products: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	products := make([]*product.Product, 0)
+	for rows.Next() {
+		p, err := scanProduct(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan product row", zap.Error(err))
+			return nil, fmt.Errorf("database scan error listing products: %w", err)
+		}
+		products = append(products, p)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("Error iterating product rows", zap.Error(err))
+		return nil, fmt.Errorf("database error iterating products: %w", mapError(err))
+	}
+	return products, nil
+}
+
+func (r *ProductRepository) UpdateProduct(ctx context.Context, p *product.Product) error {
+	err := r.db.QueryRow(ctx, `
+        UPDATE products SET display_name = $1, description = $2, key_format = $3, key_prefix = $4,
+            customer_license_caps = $5, metadata_schema = $6
+        WHERE id = $7
+        RETURNING updated_at
+    `, p.DisplayName, p.Description, p.KeyFormat, p.KeyPrefix, customerLicenseCaps(p), p.MetadataSchema, p.ID).Scan(&p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: product with ID %s not found for update", ierr.ErrNotFound, p.ID)
+		}
+		r.logger.Error("Failed to update product", zap.String("id", p.ID.String()), zap.Error(err))
+		return fmt.Errorf("database error updating product: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *ProductRepository) DeleteProduct(ctx context.Context, id uuid.UUID) error {
+	cmdTag, err := r.db.Exec(ctx, `DELETE FROM products WHERE id = $1`, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+			return fmt.Errorf("%w: product is still referenced by licenses, API keys or license templates", ierr.ErrConflict)
+		}
+		r.logger.Error("Failed to delete product", zap.String("id", id.String()), zap.Error(err))
+		return fmt.Errorf("database error deleting product: %w", mapError(err))
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ierr.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ProductRepository) FindLifecycle(ctx context.Context, productName string) (*product.Lifecycle, error) {
+	query := `
+        SELECT product_name, state, eol_date, eol_behavior, migration_product, migration_offer, created_at, updated_at
+        FROM product_lifecycles
+        WHERE product_name = $1
+    `
+	var lc product.Lifecycle
+	err := r.db.QueryRow(ctx, query, productName).Scan(
+		&lc.ProductName, &lc.State, &lc.EOLDate, &lc.EOLBehavior,
+		&lc.MigrationProduct, &lc.MigrationOffer, &lc.CreatedAt, &lc.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find product lifecycle", zap.String("product_name", productName), zap.Error(err))
+		return nil, fmt.Errorf("database error finding product lifecycle: %w", mapError(err))
+	}
+	return &lc, nil
+}
+
+func (r *ProductRepository) ListLifecycles(ctx context.Context) ([]*product.Lifecycle, error) {
+	query := `
+        SELECT product_name, state, eol_date, eol_behavior, migration_product, migration_offer, created_at, updated_at
+        FROM product_lifecycles
+        ORDER BY product_name ASC
+    `
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		r.logger.Error("Failed to list product lifecycles", zap.Error(err))
+		return nil, fmt.Errorf("database error listing product lifecycles: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	lifecycles := make([]*product.Lifecycle, 0)
+	for rows.Next() {
+		var lc product.Lifecycle
+		if err := rows.Scan(
+			&lc.ProductName, &lc.State, &lc.EOLDate, &lc.EOLBehavior,
+			&lc.MigrationProduct, &lc.MigrationOffer, &lc.CreatedAt, &lc.UpdatedAt,
+		); err != nil {
+			r.logger.Error("Failed to scan product lifecycle row", zap.Error(err))
+			return nil, fmt.Errorf("database scan error listing product lifecycles: %w", err)
+		}
+		lifecycles = append(lifecycles, &lc)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("Error iterating product lifecycle rows", zap.Error(err))
+		return nil, fmt.Errorf("database error iterating product lifecycles: %w", mapError(err))
+	}
+	return lifecycles, nil
+}
+
+func (r *ProductRepository) UpsertLifecycle(ctx context.Context, lc *product.Lifecycle) error {
+	query := `
+        INSERT INTO product_lifecycles (product_name, state, eol_date, eol_behavior, migration_product, migration_offer)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (product_name) DO UPDATE SET
+            state = EXCLUDED.state,
+            eol_date = EXCLUDED.eol_date,
+            eol_behavior = EXCLUDED.eol_behavior,
+            migration_product = EXCLUDED.migration_product,
+            migration_offer = EXCLUDED.migration_offer,
+            updated_at = NOW()
+        RETURNING created_at, updated_at
+    `
+	err := r.db.QueryRow(ctx, query,
+		lc.ProductName, lc.State, lc.EOLDate, lc.EOLBehavior, lc.MigrationProduct, lc.MigrationOffer,
+	).Scan(&lc.CreatedAt, &lc.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Failed to upsert product lifecycle", zap.String("product_name", lc.ProductName), zap.Error(err))
+		return fmt.Errorf("database error saving product lifecycle: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *ProductRepository) FindAgentPolicy(ctx context.Context, productName string) (*product.AgentPolicy, error) {
+	query := `
+        SELECT product_name, min_version, enforcement, created_at, updated_at
+        FROM product_agent_policies
+        WHERE product_name = $1
+    `
+	var p product.AgentPolicy
+	err := r.db.QueryRow(ctx, query, productName).Scan(
+		&p.ProductName, &p.MinVersion, &p.Enforcement, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find product agent policy", zap.String("product_name", productName), zap.Error(err))
+		return nil, fmt.Errorf("database error finding product agent policy: %w", mapError(err))
+	}
+	return &p, nil
+}
+
+func (r *ProductRepository) ListAgentPolicies(ctx context.Context) ([]*product.AgentPolicy, error) {
+	query := `
+        SELECT product_name, min_version, enforcement, created_at, updated_at
+        FROM product_agent_policies
+        ORDER BY product_name ASC
+    `
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		r.logger.Error("Failed to list product agent policies", zap.Error(err))
+		return nil, fmt.Errorf("database error listing product agent policies: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	policies := make([]*product.AgentPolicy, 0)
+	for rows.Next() {
+		var p product.AgentPolicy
+		if err := rows.Scan(&p.ProductName, &p.MinVersion, &p.Enforcement, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			r.logger.Error("Failed to scan product agent policy row", zap.Error(err))
+			return nil, fmt.Errorf("database scan error listing product agent policies: %w", err)
+		}
+		policies = append(policies, &p)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("Error iterating product agent policy rows", zap.Error(err))
+		return nil, fmt.Errorf("database error iterating product agent policies: %w", mapError(err))
+	}
+	return policies, nil
+}
+
+func (r *ProductRepository) UpsertAgentPolicy(ctx context.Context, p *product.AgentPolicy) error {
+	query := `
+        INSERT INTO product_agent_policies (product_name, min_version, enforcement)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (product_name) DO UPDATE SET
+            min_version = EXCLUDED.min_version,
+            enforcement = EXCLUDED.enforcement,
+            updated_at = NOW()
+        RETURNING created_at, updated_at
+    `
+	err := r.db.QueryRow(ctx, query, p.ProductName, p.MinVersion, p.Enforcement).Scan(&p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Failed to upsert product agent policy", zap.String("product_name", p.ProductName), zap.Error(err))
+		return fmt.Errorf("database error saving product agent policy: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *ProductRepository) DeleteAgentPolicy(ctx context.Context, productName string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM product_agent_policies WHERE product_name = $1`, productName)
+	if err != nil {
+		r.logger.Error("Failed to delete product agent policy", zap.String("product_name", productName), zap.Error(err))
+		return fmt.Errorf("database error deleting product agent policy: %w", mapError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return ierr.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ProductRepository) FindCachePolicy(ctx context.Context, productName string) (*product.CachePolicy, error) {
+	query := `
+        SELECT product_name, cache_ttl, revalidate_after, created_at, updated_at
+        FROM product_cache_policies
+        WHERE product_name = $1
+    `
+	var p product.CachePolicy
+	err := r.db.QueryRow(ctx, query, productName).Scan(
+		&p.ProductName, &p.CacheTTL, &p.RevalidateAfter, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find product cache policy", zap.String("product_name", productName), zap.Error(err))
+		return nil, fmt.Errorf("database error finding product cache policy: %w", mapError(err))
+	}
+	return &p, nil
+}
+
+func (r *ProductRepository) UpsertCachePolicy(ctx context.Context, p *product.CachePolicy) error {
+	query := `
+        INSERT INTO product_cache_policies (product_name, cache_ttl, revalidate_after)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (product_name) DO UPDATE SET
+            cache_ttl = EXCLUDED.cache_ttl,
+            revalidate_after = EXCLUDED.revalidate_after,
+            updated_at = NOW()
+        RETURNING created_at, updated_at
+    `
+	err := r.db.QueryRow(ctx, query, p.ProductName, p.CacheTTL, p.RevalidateAfter).Scan(&p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Failed to upsert product cache policy", zap.String("product_name", p.ProductName), zap.Error(err))
+		return fmt.Errorf("database error saving product cache policy: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *ProductRepository) DeleteCachePolicy(ctx context.Context, productName string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM product_cache_policies WHERE product_name = $1`, productName)
+	if err != nil {
+		r.logger.Error("Failed to delete product cache policy", zap.String("product_name", productName), zap.Error(err))
+		return fmt.Errorf("database error deleting product cache policy: %w", mapError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return ierr.ErrNotFound
+	}
+	return nil
+}
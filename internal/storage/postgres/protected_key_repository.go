@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/approval"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type ProtectedKeyRepository struct {
+	db     *pgxpool.Pool
+	logger *zap.Logger
+}
+
+func NewProtectedKeyRepository(db *pgxpool.Pool, logger *zap.Logger) *ProtectedKeyRepository {
+	return &ProtectedKeyRepository{
+		db:     db,
+		logger: logger.Named("ProtectedKeyRepository"),
+	}
+}
+
+var _ approval.KeyRepository = (*ProtectedKeyRepository)(nil)
+
+func (r *ProtectedKeyRepository) List(ctx context.Context) ([]*approval.ProtectedKey, error) {
+	rows, err := r.db.Query(ctx, `SELECT key, description, created_by, created_at FROM protected_keys ORDER BY key`)
+	if err != nil {
+		r.logger.Error("Failed to list protected keys", zap.Error(err))
+		return nil, fmt.Errorf("database error listing protected keys: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	keys := []*approval.ProtectedKey{}
+	for rows.Next() {
+		var k approval.ProtectedKey
+		if err := rows.Scan(&k.Key, &k.Description, &k.CreatedBy, &k.CreatedAt); err != nil {
+			return nil, fmt.Errorf("database error scanning protected key: %w", mapError(err))
+		}
+		keys = append(keys, &k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("database iteration error listing protected keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (r *ProtectedKeyRepository) Put(ctx context.Context, key *approval.ProtectedKey) error {
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO protected_keys (key, description, created_by)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (key) DO UPDATE SET description = EXCLUDED.description
+        RETURNING created_by, created_at
+    `, key.Key, key.Description, key.CreatedBy).Scan(&key.CreatedBy, &key.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to put protected key", zap.String("key", key.Key), zap.Error(err))
+		return fmt.Errorf("database error saving protected key: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *ProtectedKeyRepository) Delete(ctx context.Context, key string) error {
+	cmdTag, err := r.db.Exec(ctx, `DELETE FROM protected_keys WHERE key = $1`, key)
+	if err != nil {
+		r.logger.Error("Failed to delete protected key", zap.String("key", key), zap.Error(err))
+		return fmt.Errorf("database error deleting protected key: %w", mapError(err))
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ierr.ErrNotFound
+	}
+	return nil
+}
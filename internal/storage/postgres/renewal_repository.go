@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/renewal"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type RenewalRepository struct {
+	db     *pgxpool.Pool
+	ids    idgen.Generator
+	logger *zap.Logger
+}
+
+func NewRenewalRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *RenewalRepository {
+	return &RenewalRepository{
+		db:     db,
+		ids:    ids,
+		logger: logger.Named("RenewalRepository"),
+	}
+}
+
+var _ renewal.Repository = (*RenewalRepository)(nil)
+
+func (r *RenewalRepository) Create(ctx context.Context, offer *renewal.Offer) (uuid.UUID, error) {
+	query := `
+        INSERT INTO renewal_offers (id, license_id, extend_days, link_expires_at, created_by)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, created_at
+    `
+	err := r.db.QueryRow(ctx, query,
+		r.ids.New(), offer.LicenseID, offer.ExtendDays, offer.LinkExpiresAt, offer.CreatedBy,
+	).Scan(&offer.ID, &offer.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create renewal offer", zap.String("license_id", offer.LicenseID.String()), zap.Error(err))
+		return uuid.Nil, fmt.Errorf("database error creating renewal offer: %w", mapError(err))
+	}
+	return offer.ID, nil
+}
+
+func (r *RenewalRepository) FindByID(ctx context.Context, id uuid.UUID) (*renewal.Offer, error) {
+	query := `
+        SELECT
+            id, license_id, extend_days, link_expires_at, created_by, created_at,
+            accepted_at, accepted_ip, accepted_user_agent, consent_text
+        FROM renewal_offers
+        WHERE id = $1
+    `
+	var offer renewal.Offer
+	var consent struct {
+		AcceptedAt *time.Time
+		IP         *string
+		UserAgent  *string
+		Text       *string
+	}
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&offer.ID, &offer.LicenseID, &offer.ExtendDays, &offer.LinkExpiresAt, &offer.CreatedBy, &offer.CreatedAt,
+		&consent.AcceptedAt, &consent.IP, &consent.UserAgent, &consent.Text,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find renewal offer", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error finding renewal offer: %w", mapError(err))
+	}
+
+	if consent.AcceptedAt != nil {
+		offer.Consent = &renewal.Consent{AcceptedAt: *consent.AcceptedAt}
+		if consent.IP != nil {
+			offer.Consent.IP = *consent.IP
+		}
+		if consent.UserAgent != nil {
+			offer.Consent.UserAgent = *consent.UserAgent
+		}
+		if consent.Text != nil {
+			offer.Consent.Text = *consent.Text
+		}
+	}
+	return &offer, nil
+}
+
+func (r *RenewalRepository) Accept(ctx context.Context, id uuid.UUID, consent *renewal.Consent) error {
+	query := `
+        UPDATE renewal_offers
+        SET accepted_at = $2, accepted_ip = $3, accepted_user_agent = $4, consent_text = $5
+        WHERE id = $1 AND accepted_at IS NULL
+    `
+	tag, err := r.db.Exec(ctx, query, id, consent.AcceptedAt, consent.IP, consent.UserAgent, consent.Text)
+	if err != nil {
+		r.logger.Error("Failed to record renewal consent", zap.String("id", id.String()), zap.Error(err))
+		return fmt.Errorf("database error accepting renewal offer: %w", mapError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: renewal offer already accepted", ierr.ErrConflict)
+	}
+	return nil
+}
+
+func (r *RenewalRepository) RevokeAcceptance(ctx context.Context, id uuid.UUID) error {
+	query := `
+        UPDATE renewal_offers
+        SET accepted_at = NULL, accepted_ip = NULL, accepted_user_agent = NULL, consent_text = NULL
+        WHERE id = $1
+    `
+	if _, err := r.db.Exec(ctx, query, id); err != nil {
+		r.logger.Error("Failed to revoke renewal consent", zap.String("id", id.String()), zap.Error(err))
+		return fmt.Errorf("database error revoking renewal consent: %w", mapError(err))
+	}
+	return nil
+}
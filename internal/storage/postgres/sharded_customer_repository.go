@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/customer"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+// ShardedCustomerRepository keeps a copy of the customers table on every
+// license shard, like ShardedProductRepository does for products. The
+// primary database stays authoritative; shard copies are written after it
+// and share its IDs.
+type ShardedCustomerRepository struct {
+	*CustomerRepository
+	shards []*CustomerRepository
+	logger *zap.Logger
+}
+
+func NewShardedCustomerRepository(primary *CustomerRepository, shards []*CustomerRepository, logger *zap.Logger) *ShardedCustomerRepository {
+	return &ShardedCustomerRepository{
+		CustomerRepository: primary,
+		shards:             shards,
+		logger:             logger.Named("ShardedCustomerRepository"),
+	}
+}
+
+var _ customer.Repository = (*ShardedCustomerRepository)(nil)
+
+func (r *ShardedCustomerRepository) Create(ctx context.Context, c *customer.Customer) error {
+	if err := r.CustomerRepository.Create(ctx, c); err != nil {
+		return err
+	}
+	return r.replicate(ctx, c)
+}
+
+// Ensure copies the customer even when it already existed, so a shard that
+// missed an earlier copy catches up before a license references it there.
+func (r *ShardedCustomerRepository) Ensure(ctx context.Context, email, name string) (*customer.Customer, error) {
+	c, err := r.CustomerRepository.Ensure(ctx, email, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.replicate(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *ShardedCustomerRepository) Update(ctx context.Context, c *customer.Customer) error {
+	if err := r.CustomerRepository.Update(ctx, c); err != nil {
+		return err
+	}
+	return r.replicate(ctx, c)
+}
+
+// Delete deletes shard copies first so that a customer with licenses on
+// any shard is refused before it disappears from the primary.
+func (r *ShardedCustomerRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	for i, shard := range r.shards {
+		if err := shard.Delete(ctx, id); err != nil && !errors.Is(err, ierr.ErrNotFound) {
+			return fmt.Errorf("shard %d: %w", i, err)
+		}
+	}
+	return r.CustomerRepository.Delete(ctx, id)
+}
+
+func (r *ShardedCustomerRepository) replicate(ctx context.Context, c *customer.Customer) error {
+	for i, shard := range r.shards {
+		if err := shard.upsertCustomer(ctx, c); err != nil {
+			r.logger.Error("Failed to copy customer to shard", zap.Int("shard", i), zap.String("email", c.Email), zap.Error(err))
+			return fmt.Errorf("shard %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// upsertCustomer writes a copy of a customer from the primary database and
+// updates the licenses referencing it. A copy the shard backfilled itself is
+// found by e-mail and takes over the primary's ID; its licenses follow
+// through the cascading foreign key.
+func (r *CustomerRepository) upsertCustomer(ctx context.Context, c *customer.Customer) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("database error starting customer copy: %w", mapError(err))
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	cmdTag, err := tx.Exec(ctx, `UPDATE customers SET email = $1, name = $2 WHERE id = $3`, c.Email, c.Name, c.ID)
+	if err != nil {
+		return fmt.Errorf("database error copying customer: %w", mapError(err))
+	}
+	if cmdTag.RowsAffected() == 0 {
+		_, err = tx.Exec(ctx, `
+            INSERT INTO customers (id, email, name, created_at)
+            VALUES ($1, $2, $3, $4)
+            ON CONFLICT ((lower(email))) DO UPDATE SET
+                id = EXCLUDED.id,
+                email = EXCLUDED.email,
+                name = EXCLUDED.name
+        `, c.ID, c.Email, c.Name, c.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("database error copying customer: %w", mapError(err))
+		}
+	}
+	if err := r.syncLicenses(ctx, tx, c); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("database error committing customer copy: %w", mapError(err))
+	}
+	return nil
+}
@@ -0,0 +1,703 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// ShardedLicenseRepository spreads licenses across several databases by a
+// hash of the license key. Lookups by key go straight to the owning shard,
+// everything else fans out to all shards and merges the results.
+type ShardedLicenseRepository struct {
+	shards []*LicenseRepository
+	logger *zap.Logger
+}
+
+func NewShardedLicenseRepository(shards []*LicenseRepository, logger *zap.Logger) *ShardedLicenseRepository {
+	return &ShardedLicenseRepository{
+		shards: shards,
+		logger: logger.Named("ShardedLicenseRepository"),
+	}
+}
+
+var _ license.Repository = (*ShardedLicenseRepository)(nil)
+var _ license.Exporter = (*ShardedLicenseRepository)(nil)
+var _ license.Archiver = (*ShardedLicenseRepository)(nil)
+
+func ShardIndex(key string, shardCount int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(shardCount))
+}
+
+func (r *ShardedLicenseRepository) shardFor(key string) *LicenseRepository {
+	return r.shards[ShardIndex(key, len(r.shards))]
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, ierr.ErrNotFound)
+}
+
+func (r *ShardedLicenseRepository) Create(ctx context.Context, lic *license.License) (uuid.UUID, error) {
+	return r.shardFor(lic.LicenseKey).Create(ctx, lic)
+}
+
+// CreateBatch inserts the licenses of each shard in a transaction of that
+// shard, one shard after the other. When a shard fails, the licenses the
+// shards before it stored are deleted again; those that cannot be keep
+// their ID.
+func (r *ShardedLicenseRepository) CreateBatch(ctx context.Context, licenses []*license.License) error {
+	byShard := make([][]*license.License, len(r.shards))
+	for _, lic := range licenses {
+		i := ShardIndex(lic.LicenseKey, len(r.shards))
+		byShard[i] = append(byShard[i], lic)
+	}
+	for i, batch := range byShard {
+		if len(batch) == 0 {
+			continue
+		}
+		if err := r.shards[i].CreateBatch(ctx, batch); err != nil {
+			r.undoCreateBatch(ctx, byShard[:i])
+			return err
+		}
+	}
+	return nil
+}
+
+// undoCreateBatch deletes the licenses stored by the shards of a failed
+// CreateBatch. It outlives the cancellation of ctx, which may be what made
+// the batch fail.
+func (r *ShardedLicenseRepository) undoCreateBatch(ctx context.Context, byShard [][]*license.License) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+	for i, batch := range byShard {
+		for _, lic := range batch {
+			if err := r.shards[i].deleteByID(ctx, lic.ID); err != nil {
+				r.logger.Error("Failed to undo license of a failed batch, it stays stored",
+					zap.Int("shard", i), zap.String("id", lic.ID.String()), zap.Error(err))
+				continue
+			}
+			lic.ID = uuid.Nil
+		}
+	}
+}
+
+func (r *ShardedLicenseRepository) FindByKey(ctx context.Context, key string) (*license.License, error) {
+	return r.shardFor(key).FindByKey(ctx, key)
+}
+
+func (r *ShardedLicenseRepository) FindByID(ctx context.Context, id uuid.UUID) (*license.License, error) {
+	results := make([]*license.License, len(r.shards))
+	g, gCtx := errgroup.WithContext(ctx)
+	for i, shard := range r.shards {
+		g.Go(func() error {
+			lic, err := shard.FindByID(gCtx, id)
+			if err != nil {
+				if isNotFound(err) {
+					return nil
+				}
+				return err
+			}
+			results[i] = lic
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	for _, lic := range results {
+		if lic != nil {
+			return lic, nil
+		}
+	}
+	return nil, ierr.ErrNotFound
+}
+
+// ArchiveCandidates takes up to limit candidates of each shard and keeps the
+// first limit, shard by shard. Children are only looked for in the shard of
+// their parent.
+func (r *ShardedLicenseRepository) ArchiveCandidates(ctx context.Context, cutoff time.Time, limit int) ([]uuid.UUID, error) {
+	results := make([][]uuid.UUID, len(r.shards))
+	g, gCtx := errgroup.WithContext(ctx)
+	for i, shard := range r.shards {
+		g.Go(func() error {
+			ids, err := shard.ArchiveCandidates(gCtx, cutoff, limit)
+			results[i] = ids
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	ids := slices.Concat(results...)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
+
+// Archive runs on every shard with all of ids; each moves the ones it holds.
+func (r *ShardedLicenseRepository) Archive(ctx context.Context, ids []uuid.UUID, cutoff time.Time) ([]uuid.UUID, error) {
+	results := make([][]uuid.UUID, len(r.shards))
+	g, gCtx := errgroup.WithContext(ctx)
+	for i, shard := range r.shards {
+		g.Go(func() error {
+			moved, err := shard.Archive(gCtx, ids, cutoff)
+			results[i] = moved
+			return err
+		})
+	}
+	err := g.Wait()
+	return slices.Concat(results...), err
+}
+
+func (r *ShardedLicenseRepository) FindArchivedByID(ctx context.Context, id uuid.UUID) (*license.Archived, error) {
+	results := make([]*license.Archived, len(r.shards))
+	g, gCtx := errgroup.WithContext(ctx)
+	for i, shard := range r.shards {
+		g.Go(func() error {
+			lic, err := shard.FindArchivedByID(gCtx, id)
+			if err != nil {
+				if isNotFound(err) {
+					return nil
+				}
+				return err
+			}
+			results[i] = lic
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	for _, lic := range results {
+		if lic != nil {
+			return lic, nil
+		}
+	}
+	return nil, ierr.ErrNotFound
+}
+
+// maxKeyDrawsPerShard bounds how many keys RotateKey draws, per shard,
+// before giving up on finding one that maps to the shard of the license.
+const maxKeyDrawsPerShard = 64
+
+// RotateKey draws keys until one maps to the shard the license is on, so
+// rotating never moves a license and its superseded key stays next to it.
+func (r *ShardedLicenseRepository) RotateKey(ctx context.Context, id uuid.UUID, newKey func() (string, error), oldKeyValidUntil time.Time) (*license.License, error) {
+	idx, err := r.shardOf(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for draw := 0; draw < maxKeyDrawsPerShard*len(r.shards); draw++ {
+		key, err := newKey()
+		if err != nil {
+			return nil, err
+		}
+		if ShardIndex(key, len(r.shards)) == idx {
+			return r.shards[idx].rotateKey(ctx, id, key, oldKeyValidUntil)
+		}
+	}
+	return nil, fmt.Errorf("no generated key maps to shard %d of license %s", idx, id)
+}
+
+// shardOf returns the index of the shard holding license id, which differs
+// from the one its key maps to while the license awaits a rebalance.
+func (r *ShardedLicenseRepository) shardOf(ctx context.Context, id uuid.UUID) (int, error) {
+	found := make([]bool, len(r.shards))
+	g, gCtx := errgroup.WithContext(ctx)
+	for i, shard := range r.shards {
+		g.Go(func() error {
+			_, err := shard.FindByID(gCtx, id)
+			if err != nil {
+				if isNotFound(err) {
+					return nil
+				}
+				return err
+			}
+			found[i] = true
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return 0, err
+	}
+
+	for i, ok := range found {
+		if ok {
+			return i, nil
+		}
+	}
+	return 0, ierr.ErrNotFound
+}
+
+func (r *ShardedLicenseRepository) List(ctx context.Context, params license.ListParams) ([]*license.License, int64, error) {
+	shardParams := params
+	shardParams.Offset = 0
+	shardParams.Limit = params.Offset + params.Limit
+
+	results := make([][]*license.License, len(r.shards))
+	totals := make([]int64, len(r.shards))
+	g, gCtx := errgroup.WithContext(ctx)
+	for i, shard := range r.shards {
+		g.Go(func() error {
+			licenses, total, err := shard.List(gCtx, shardParams)
+			if err != nil {
+				return err
+			}
+			results[i] = licenses
+			totals[i] = total
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, 0, err
+	}
+
+	var totalCount int64
+	merged := make([]*license.License, 0, shardParams.Limit*len(r.shards))
+	for i := range r.shards {
+		totalCount += totals[i]
+		merged = append(merged, results[i]...)
+	}
+
+	sortFields := params.Sort
+	if len(sortFields) == 0 {
+		sortFields = []license.SortField{{Column: params.SortBy, Order: params.SortOrder}}
+	}
+	less := licenseLess(sortFields)
+	if params.Query != nil {
+		byFields, query := less, *params.Query
+		less = func(a, b *license.License) bool {
+			if ra, rb := license.SearchRank(a, query), license.SearchRank(b, query); ra != rb {
+				return ra < rb
+			}
+			return byFields(a, b)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return less(merged[i], merged[j]) })
+
+	if params.Offset >= len(merged) {
+		return []*license.License{}, totalCount, nil
+	}
+	end := params.Offset + params.Limit
+	if end > len(merged) {
+		end = len(merged)
+	}
+	return merged[params.Offset:end], totalCount, nil
+}
+
+func (r *ShardedLicenseRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status license.LicenseStatus) error {
+	updated := make([]bool, len(r.shards))
+	g, gCtx := errgroup.WithContext(ctx)
+	for i, shard := range r.shards {
+		g.Go(func() error {
+			err := shard.UpdateStatus(gCtx, id, status)
+			if err != nil {
+				if isNotFound(err) {
+					return nil
+				}
+				return err
+			}
+			updated[i] = true
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	for _, ok := range updated {
+		if ok {
+			return nil
+		}
+	}
+	return ierr.ErrNotFound
+}
+
+// WithLock locks on the shard id hashes to. Licenses are placed by key, so
+// that need not be the shard holding the license; it only has to be the same
+// shard for every caller.
+func (r *ShardedLicenseRepository) WithLock(ctx context.Context, id uuid.UUID, fn func(ctx context.Context) error) error {
+	return r.shardFor(id.String()).WithLock(ctx, id, fn)
+}
+
+func (r *ShardedLicenseRepository) Update(ctx context.Context, lic *license.License) error {
+	return r.shardFor(lic.LicenseKey).Update(ctx, lic)
+}
+
+func (r *ShardedLicenseRepository) UpdateMetadata(ctx context.Context, id uuid.UUID, metadata json.RawMessage) error {
+	updated := make([]bool, len(r.shards))
+	g, gCtx := errgroup.WithContext(ctx)
+	for i, shard := range r.shards {
+		g.Go(func() error {
+			err := shard.UpdateMetadata(gCtx, id, metadata)
+			if err != nil {
+				if isNotFound(err) {
+					return nil
+				}
+				return err
+			}
+			updated[i] = true
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	for _, ok := range updated {
+		if ok {
+			return nil
+		}
+	}
+	return ierr.ErrNotFound
+}
+
+func (r *ShardedLicenseRepository) GetDashboardSummary(ctx context.Context, expiringPeriodDays int) (*license.DashboardSummaryData, error) {
+	results := make([]*license.DashboardSummaryData, len(r.shards))
+	g, gCtx := errgroup.WithContext(ctx)
+	for i, shard := range r.shards {
+		g.Go(func() error {
+			summary, err := shard.GetDashboardSummary(gCtx, expiringPeriodDays)
+			if err != nil {
+				return err
+			}
+			results[i] = summary
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	merged := &license.DashboardSummaryData{
+		StatusCounts:  make(map[license.LicenseStatus]int64),
+		TypeCounts:    make(map[string]int64),
+		ProductCounts: make(map[string]int64),
+		TagCounts:     make(map[string]int64),
+	}
+	for _, summary := range results {
+		merged.TotalCount += summary.TotalCount
+		merged.ExpiringSoonCount += summary.ExpiringSoonCount
+		for k, v := range summary.StatusCounts {
+			merged.StatusCounts[k] += v
+		}
+		for k, v := range summary.TypeCounts {
+			merged.TypeCounts[k] += v
+		}
+		for k, v := range summary.ProductCounts {
+			merged.ProductCounts[k] += v
+		}
+		for k, v := range summary.TagCounts {
+			merged.TagCounts[k] += v
+		}
+		if summary.NextToExpireDate != nil && (merged.NextToExpireDate == nil || summary.NextToExpireDate.Before(*merged.NextToExpireDate)) {
+			merged.NextToExpireDate = summary.NextToExpireDate
+			merged.NextToExpireKey = summary.NextToExpireKey
+			merged.NextToExpireProd = summary.NextToExpireProd
+		}
+	}
+	return merged, nil
+}
+
+// Aggregate sums per-shard counts for matching groups. Distinct customer
+// counts cannot be merged that way because one customer may own licenses on
+// several shards, so that metric is rejected here.
+func (r *ShardedLicenseRepository) Aggregate(ctx context.Context, params license.AggregateParams) ([]license.AggregateRow, error) {
+	if params.Metric == license.MetricCustomers {
+		return nil, fmt.Errorf("%w: metric %s is not supported with sharded storage", ierr.ErrValidation, params.Metric)
+	}
+
+	results := make([][]license.AggregateRow, len(r.shards))
+	g, gCtx := errgroup.WithContext(ctx)
+	for i, shard := range r.shards {
+		g.Go(func() error {
+			rows, err := shard.Aggregate(gCtx, params)
+			if err != nil {
+				return err
+			}
+			results[i] = rows
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int)
+	merged := make([]license.AggregateRow, 0)
+	for _, rows := range results {
+		for _, row := range rows {
+			key := aggregateGroupKey(row.Group)
+			if i, ok := index[key]; ok {
+				merged[i].Value += row.Value
+				continue
+			}
+			index[key] = len(merged)
+			merged = append(merged, row)
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return aggregateGroupLess(merged[i].Group, merged[j].Group)
+	})
+	if len(merged) > maxAggregateRows {
+		merged = merged[:maxAggregateRows]
+	}
+	return merged, nil
+}
+
+func aggregateGroupKey(group []*string) string {
+	var b strings.Builder
+	for _, v := range group {
+		if v == nil {
+			b.WriteString("\x01")
+		} else {
+			b.WriteString("\x02")
+			b.WriteString(*v)
+		}
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+// aggregateGroupLess mirrors ORDER BY on every column with NULLs last.
+func aggregateGroupLess(a, b []*string) bool {
+	for i := range a {
+		switch {
+		case a[i] == nil && b[i] == nil:
+			continue
+		case a[i] == nil:
+			return false
+		case b[i] == nil:
+			return true
+		case *a[i] != *b[i]:
+			return *a[i] < *b[i]
+		}
+	}
+	return false
+}
+
+// Export streams one shard after the other, so licenses are in ID order
+// within each shard only.
+func (r *ShardedLicenseRepository) Export(ctx context.Context, params license.ListParams, fn func(*license.License) error) error {
+	for _, shard := range r.shards {
+		if err := shard.Export(ctx, params, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ShardedLicenseRepository) ListRecentlyValidated(ctx context.Context, limit int) ([]*license.License, error) {
+	results := make([][]*license.License, len(r.shards))
+	g, gCtx := errgroup.WithContext(ctx)
+	for i, shard := range r.shards {
+		g.Go(func() error {
+			licenses, err := shard.ListRecentlyValidated(gCtx, limit)
+			if err != nil {
+				return err
+			}
+			results[i] = licenses
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	merged := make([]*license.License, 0, limit*len(r.shards))
+	for _, licenses := range results {
+		merged = append(merged, licenses...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return lastValidatedAt(merged[i]).After(lastValidatedAt(merged[j]))
+	})
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged, nil
+}
+
+type RebalanceReport struct {
+	Scanned   int
+	Misplaced int
+	Moved     int
+}
+
+// Rebalance moves every license that does not live on the shard its key hashes
+// to. It is meant to be run after changing the shard list; with dryRun set it
+// only counts the licenses that would move.
+func (r *ShardedLicenseRepository) Rebalance(ctx context.Context, dryRun bool) (*RebalanceReport, error) {
+	report := &RebalanceReport{}
+	const batchSize = 500
+
+	for sourceIdx, source := range r.shards {
+		var misplaced []*license.License
+		offset := 0
+
+		for {
+			params := license.ListParams{
+				Limit:     batchSize,
+				Offset:    offset,
+				SortBy:    "id",
+				SortOrder: "ASC",
+			}
+			batch, _, err := source.List(ctx, params)
+			if err != nil {
+				return report, fmt.Errorf("failed to scan shard %d: %w", sourceIdx, err)
+			}
+			for _, lic := range batch {
+				report.Scanned++
+				if ShardIndex(lic.LicenseKey, len(r.shards)) != sourceIdx {
+					misplaced = append(misplaced, lic)
+				}
+			}
+			if len(batch) < batchSize {
+				break
+			}
+			offset += batchSize
+		}
+
+		report.Misplaced += len(misplaced)
+		if dryRun {
+			continue
+		}
+
+		for _, lic := range misplaced {
+			target := r.shardFor(lic.LicenseKey)
+			if err := target.insertWithID(ctx, lic); err != nil && !errors.Is(err, ierr.ErrDuplicateKey) {
+				return report, fmt.Errorf("failed to copy license %s to its target shard: %w", lic.ID, err)
+			}
+			if err := source.deleteByID(ctx, lic.ID); err != nil {
+				return report, fmt.Errorf("failed to remove license %s from shard %d: %w", lic.ID, sourceIdx, err)
+			}
+			report.Moved++
+		}
+
+		r.logger.Info("Shard rebalanced",
+			zap.Int("shard", sourceIdx),
+			zap.Int("misplaced", len(misplaced)),
+		)
+	}
+
+	return report, nil
+}
+
+func licenseLess(fields []license.SortField) func(a, b *license.License) bool {
+	type key struct {
+		column string
+		desc   bool
+	}
+	keys := make([]key, 0, len(fields))
+	for _, field := range fields {
+		column := strings.ToLower(field.Column)
+		order := strings.ToUpper(field.Order)
+		if _, ok := allowedSortColumns[column]; !ok || (order != "ASC" && order != "DESC") {
+			keys = []key{{column: "created_at", desc: true}}
+			break
+		}
+		keys = append(keys, key{column: column, desc: order == "DESC"})
+	}
+	if len(keys) == 0 {
+		keys = []key{{column: "created_at", desc: true}}
+	}
+
+	compare := func(column string, a, b *license.License) int {
+		switch column {
+		case "id":
+			return bytes.Compare(a.ID[:], b.ID[:])
+		case "expires_at":
+			return compareNullTime(a.ExpiresAt.Valid, a.ExpiresAt.Time, b.ExpiresAt.Valid, b.ExpiresAt.Time)
+		case "issued_at":
+			return compareNullTime(a.IssuedAt.Valid, a.IssuedAt.Time, b.IssuedAt.Valid, b.IssuedAt.Time)
+		case "starts_at":
+			return compareNullTime(a.StartsAt.Valid, a.StartsAt.Time, b.StartsAt.Valid, b.StartsAt.Time)
+		case "revoked_at":
+			return compareNullTime(a.RevokedAt.Valid, a.RevokedAt.Time, b.RevokedAt.Valid, b.RevokedAt.Time)
+		case "updated_at":
+			return a.UpdatedAt.Compare(b.UpdatedAt)
+		case "customer_name":
+			return compareNullString(a.CustomerName.Valid, a.CustomerName.String, b.CustomerName.Valid, b.CustomerName.String)
+		case "customer_email":
+			return compareNullString(a.CustomerEmail.Valid, a.CustomerEmail.String, b.CustomerEmail.Valid, b.CustomerEmail.String)
+		case "product_name":
+			return strings.Compare(a.ProductName, b.ProductName)
+		case "type":
+			return strings.Compare(a.Type, b.Type)
+		case "status":
+			return strings.Compare(string(a.Status), string(b.Status))
+		default:
+			return a.CreatedAt.Compare(b.CreatedAt)
+		}
+	}
+
+	// Matches buildOrderBy: NULLS FIRST for ascending, NULLS LAST for
+	// descending, which is exactly what reversing the comparison gives us.
+	return func(a, b *license.License) bool {
+		for _, k := range keys {
+			c := compare(k.column, a, b)
+			if c == 0 {
+				continue
+			}
+			if k.desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	}
+}
+
+func compareNullTime(aValid bool, a time.Time, bValid bool, b time.Time) int {
+	switch {
+	case !aValid && !bValid:
+		return 0
+	case !aValid:
+		return -1
+	case !bValid:
+		return 1
+	default:
+		return a.Compare(b)
+	}
+}
+
+func compareNullString(aValid bool, a string, bValid bool, b string) int {
+	switch {
+	case !aValid && !bValid:
+		return 0
+	case !aValid:
+		return -1
+	case !bValid:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func lastValidatedAt(lic *license.License) time.Time {
+	var meta struct {
+		LastValidatedAt time.Time `json:"last_validated_at"`
+	}
+	_ = lic.GetMetadata(&meta)
+	return meta.LastValidatedAt
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/product"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+// ShardedProductRepository keeps a copy of the products table on every
+// license shard, because licenses reference their product by foreign key.
+// The primary database stays authoritative; shard copies are written after
+// it and share its IDs.
+type ShardedProductRepository struct {
+	*ProductRepository
+	shards []*ProductRepository
+	logger *zap.Logger
+}
+
+func NewShardedProductRepository(primary *ProductRepository, shards []*ProductRepository, logger *zap.Logger) *ShardedProductRepository {
+	return &ShardedProductRepository{
+		ProductRepository: primary,
+		shards:            shards,
+		logger:            logger.Named("ShardedProductRepository"),
+	}
+}
+
+var _ product.Repository = (*ShardedProductRepository)(nil)
+
+func (r *ShardedProductRepository) CreateProduct(ctx context.Context, p *product.Product) error {
+	if err := r.ProductRepository.CreateProduct(ctx, p); err != nil {
+		return err
+	}
+	return r.replicate(ctx, p)
+}
+
+func (r *ShardedProductRepository) UpdateProduct(ctx context.Context, p *product.Product) error {
+	if err := r.ProductRepository.UpdateProduct(ctx, p); err != nil {
+		return err
+	}
+	return r.replicate(ctx, p)
+}
+
+// DeleteProduct deletes shard copies first so that a product still used by
+// licenses on any shard is refused before it disappears from the primary.
+func (r *ShardedProductRepository) DeleteProduct(ctx context.Context, id uuid.UUID) error {
+	for i, shard := range r.shards {
+		if err := shard.DeleteProduct(ctx, id); err != nil && !errors.Is(err, ierr.ErrNotFound) {
+			return fmt.Errorf("shard %d: %w", i, err)
+		}
+	}
+	return r.ProductRepository.DeleteProduct(ctx, id)
+}
+
+func (r *ShardedProductRepository) replicate(ctx context.Context, p *product.Product) error {
+	for i, shard := range r.shards {
+		if err := shard.upsertProduct(ctx, p); err != nil {
+			r.logger.Error("Failed to copy product to shard", zap.Int("shard", i), zap.String("name", p.Name), zap.Error(err))
+			return fmt.Errorf("shard %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// upsertProduct writes a copy of a product from the primary database. It is
+// idempotent so a failed replication can be repeated by saving the product
+// again, and harmless when a shard is the primary database itself.
+func (r *ProductRepository) upsertProduct(ctx context.Context, p *product.Product) error {
+	_, err := r.db.Exec(ctx, `
+        INSERT INTO products (id, name, display_name, description, key_format, key_prefix, customer_license_caps, metadata_schema)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        ON CONFLICT (id) DO UPDATE SET
+            name = EXCLUDED.name,
+            display_name = EXCLUDED.display_name,
+            description = EXCLUDED.description,
+            key_format = EXCLUDED.key_format,
+            key_prefix = EXCLUDED.key_prefix,
+            customer_license_caps = EXCLUDED.customer_license_caps,
+            metadata_schema = EXCLUDED.metadata_schema
+    `, p.ID, p.Name, p.DisplayName, p.Description, p.KeyFormat, p.KeyPrefix, customerLicenseCaps(p), p.MetadataSchema)
+	if err != nil {
+		return fmt.Errorf("database error copying product: %w", mapError(err))
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/signingkey"
+	"go.uber.org/zap"
+)
+
+type SigningKeyRepository struct {
+	db     *pgxpool.Pool
+	logger *zap.Logger
+}
+
+func NewSigningKeyRepository(db *pgxpool.Pool, logger *zap.Logger) *SigningKeyRepository {
+	return &SigningKeyRepository{
+		db:     db,
+		logger: logger.Named("SigningKeyRepository"),
+	}
+}
+
+var _ signingkey.Repository = (*SigningKeyRepository)(nil)
+
+const signingKeyColumns = `kid, algorithm, key_ref, public_key, status, created_at, retired_at`
+
+func scanSigningKey(row pgx.Row) (*signingkey.Key, error) {
+	var k signingkey.Key
+	if err := row.Scan(&k.KID, &k.Algorithm, &k.KeyRef, &k.PublicKey, &k.Status, &k.CreatedAt, &k.RetiredAt); err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (r *SigningKeyRepository) Rotate(ctx context.Context, k *signingkey.Key) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("database error starting signing key rotation: %w", mapError(err))
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `
+        UPDATE signing_keys SET status = $1, retired_at = NOW()
+        WHERE status = $2
+    `, signingkey.StatusRetired, signingkey.StatusActive); err != nil {
+		r.logger.Error("Failed to retire active signing key", zap.Error(err))
+		return fmt.Errorf("database error retiring signing key: %w", mapError(err))
+	}
+
+	k.Status = signingkey.StatusActive
+	err = tx.QueryRow(ctx, `
+        INSERT INTO signing_keys (kid, algorithm, key_ref, public_key, status)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING created_at
+    `, k.KID, k.Algorithm, k.KeyRef, k.PublicKey, k.Status).Scan(&k.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to insert signing key", zap.String("kid", k.KID), zap.Error(err))
+		return fmt.Errorf("database error creating signing key: %w", mapError(err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("database error committing signing key rotation: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *SigningKeyRepository) List(ctx context.Context) ([]*signingkey.Key, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+signingKeyColumns+` FROM signing_keys ORDER BY created_at DESC`)
+	if err != nil {
+		r.logger.Error("Failed to list signing keys", zap.Error(err))
+		return nil, fmt.Errorf("database error listing signing keys: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	keys := make([]*signingkey.Key, 0)
+	for rows.Next() {
+		k, err := scanSigningKey(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan signing key row", zap.Error(err))
+			return nil, fmt.Errorf("database scan error listing signing keys: %w", err)
+		}
+		keys = append(keys, k)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("Error iterating signing key rows", zap.Error(err))
+		return nil, fmt.Errorf("database error iterating signing keys: %w", mapError(err))
+	}
+	return keys, nil
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/statushistory"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type StatusHistoryRepository struct {
+	db     *pgxpool.Pool
+	ids    idgen.Generator
+	logger *zap.Logger
+}
+
+func NewStatusHistoryRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *StatusHistoryRepository {
+	return &StatusHistoryRepository{
+		db:     db,
+		ids:    ids,
+		logger: logger.Named("StatusHistoryRepository"),
+	}
+}
+
+var _ statushistory.Repository = (*StatusHistoryRepository)(nil)
+
+func (r *StatusHistoryRepository) Record(ctx context.Context, entry *statushistory.Entry) error {
+	query := `
+        INSERT INTO license_status_history (id, license_id, from_status, to_status, reason, changed_by)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, created_at
+    `
+	err := r.db.QueryRow(ctx, query,
+		r.ids.New(), entry.LicenseID, entry.FromStatus, entry.ToStatus, entry.Reason, entry.ChangedBy,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to record status change",
+			zap.String("license_id", entry.LicenseID.String()),
+			zap.String("to_status", string(entry.ToStatus)),
+			zap.Error(err),
+		)
+		return fmt.Errorf("database error recording status change: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *StatusHistoryRepository) Latest(ctx context.Context, licenseID uuid.UUID, status license.LicenseStatus) (*statushistory.Entry, error) {
+	query := `
+        SELECT id, license_id, from_status, to_status, reason, changed_by, created_at
+        FROM license_status_history
+        WHERE license_id = $1 AND to_status = $2
+        ORDER BY created_at DESC
+        LIMIT 1
+    `
+	var entry statushistory.Entry
+	err := r.db.QueryRow(ctx, query, licenseID, status).Scan(
+		&entry.ID, &entry.LicenseID, &entry.FromStatus, &entry.ToStatus,
+		&entry.Reason, &entry.ChangedBy, &entry.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find status change", zap.String("license_id", licenseID.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error finding status change: %w", mapError(err))
+	}
+	return &entry, nil
+}
+
+func (r *StatusHistoryRepository) LatestFor(ctx context.Context, licenseIDs []uuid.UUID, status license.LicenseStatus) (map[uuid.UUID]*statushistory.Entry, error) {
+	entries := make(map[uuid.UUID]*statushistory.Entry, len(licenseIDs))
+	if len(licenseIDs) == 0 {
+		return entries, nil
+	}
+	query := `
+        SELECT DISTINCT ON (license_id) id, license_id, from_status, to_status, reason, changed_by, created_at
+        FROM license_status_history
+        WHERE license_id = ANY($1) AND to_status = $2
+        ORDER BY license_id, created_at DESC
+    `
+	rows, err := r.db.Query(ctx, query, licenseIDs, status)
+	if err != nil {
+		r.logger.Error("Failed to find status changes", zap.Int("licenses", len(licenseIDs)), zap.Error(err))
+		return nil, fmt.Errorf("database error finding status changes: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry statushistory.Entry
+		if err := rows.Scan(
+			&entry.ID, &entry.LicenseID, &entry.FromStatus, &entry.ToStatus,
+			&entry.Reason, &entry.ChangedBy, &entry.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("database error scanning status change: %w", mapError(err))
+		}
+		entries[entry.LicenseID] = &entry
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("database error iterating status changes: %w", mapError(err))
+	}
+	return entries, nil
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/stepup"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type TOTPRepository struct {
+	db     *pgxpool.Pool
+	logger *zap.Logger
+}
+
+func NewTOTPRepository(db *pgxpool.Pool, logger *zap.Logger) *TOTPRepository {
+	return &TOTPRepository{
+		db:     db,
+		logger: logger.Named("TOTPRepository"),
+	}
+}
+
+var _ stepup.TOTPRepository = (*TOTPRepository)(nil)
+
+func (r *TOTPRepository) Find(ctx context.Context, subject string) (*stepup.TOTPSecret, error) {
+	s := stepup.TOTPSecret{Subject: subject}
+	err := r.db.QueryRow(ctx, `SELECT secret, last_counter, created_at FROM totp_secrets WHERE subject = $1`, subject).
+		Scan(&s.Secret, &s.LastCounter, &s.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find TOTP secret", zap.String("subject", subject), zap.Error(err))
+		return nil, fmt.Errorf("database error finding TOTP secret: %w", mapError(err))
+	}
+	return &s, nil
+}
+
+func (r *TOTPRepository) Put(ctx context.Context, s *stepup.TOTPSecret) error {
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO totp_secrets (subject, secret, last_counter)
+        VALUES ($1, $2, 0)
+        ON CONFLICT (subject) DO UPDATE SET secret = EXCLUDED.secret, last_counter = 0, created_at = NOW()
+        RETURNING last_counter, created_at
+    `, s.Subject, s.Secret).Scan(&s.LastCounter, &s.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to save TOTP secret", zap.String("subject", s.Subject), zap.Error(err))
+		return fmt.Errorf("database error saving TOTP secret: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *TOTPRepository) Use(ctx context.Context, subject string, counter int64) error {
+	cmdTag, err := r.db.Exec(ctx, `
+        UPDATE totp_secrets SET last_counter = $2
+        WHERE subject = $1 AND last_counter < $2
+    `, subject, counter)
+	if err != nil {
+		r.logger.Error("Failed to record TOTP code use", zap.String("subject", subject), zap.Error(err))
+		return fmt.Errorf("database error recording TOTP code use: %w", mapError(err))
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: TOTP code was already used", ierr.ErrConflict)
+	}
+	return nil
+}
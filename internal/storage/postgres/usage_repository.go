@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/usage"
+	"go.uber.org/zap"
+)
+
+type UsageRepository struct {
+	db     *pgxpool.Pool
+	logger *zap.Logger
+}
+
+func NewUsageRepository(db *pgxpool.Pool, logger *zap.Logger) *UsageRepository {
+	return &UsageRepository{
+		db:     db,
+		logger: logger.Named("UsageRepository"),
+	}
+}
+
+var _ usage.Repository = (*UsageRepository)(nil)
+
+func (r *UsageRepository) Record(ctx context.Context, licenseID uuid.UUID, events []usage.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	metrics := make([]string, len(events))
+	days := make([]time.Time, len(events))
+	quantities := make([]int64, len(events))
+	for i, e := range events {
+		metrics[i] = e.Metric
+		days[i] = usage.PeriodDay.Start(e.OccurredAt)
+		quantities[i] = e.Quantity
+	}
+
+	// Events for the same counter are summed first; ON CONFLICT cannot
+	// update one row twice in a statement.
+	_, err := r.db.Exec(ctx, `
+        INSERT INTO license_usage (license_id, metric, day, quantity)
+        SELECT $1, e.metric, e.day, SUM(e.quantity)
+        FROM unnest($2::text[], $3::date[], $4::bigint[]) AS e(metric, day, quantity)
+        GROUP BY e.metric, e.day
+        ON CONFLICT (license_id, metric, day) DO UPDATE
+        SET quantity = license_usage.quantity + EXCLUDED.quantity, updated_at = NOW()
+    `, licenseID, metrics, days, quantities)
+	if err != nil {
+		r.logger.Error("Failed to record usage", zap.String("license_id", licenseID.String()), zap.Int("events", len(events)), zap.Error(err))
+		return fmt.Errorf("database error recording usage: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *UsageRepository) List(ctx context.Context, params usage.ListParams) ([]*usage.Counter, error) {
+	trunc := "day"
+	if params.Period == usage.PeriodMonth {
+		trunc = "month"
+	}
+	conditions := []string{"license_id = $1"}
+	args := []interface{}{params.LicenseID}
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if params.Metric != nil {
+		add("metric = $%d", *params.Metric)
+	}
+	if params.From != nil {
+		add("day >= $%d::date", *params.From)
+	}
+	if params.To != nil {
+		add("day < $%d::date", *params.To)
+	}
+
+	query := fmt.Sprintf(`
+        SELECT metric, date_trunc('%s', day)::date AS period_start, SUM(quantity)::bigint
+        FROM license_usage
+        WHERE %s
+        GROUP BY metric, period_start
+        ORDER BY period_start, metric
+    `, trunc, strings.Join(conditions, " AND "))
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list usage", zap.String("license_id", params.LicenseID.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error listing usage: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	counters := make([]*usage.Counter, 0)
+	for rows.Next() {
+		c := usage.Counter{LicenseID: params.LicenseID}
+		if err := rows.Scan(&c.Metric, &c.PeriodStart, &c.Quantity); err != nil {
+			return nil, fmt.Errorf("database error scanning usage: %w", mapError(err))
+		}
+		counters = append(counters, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("database iteration error listing usage: %w", err)
+	}
+	return counters, nil
+}
+
+func (r *UsageRepository) Totals(ctx context.Context, licenseID uuid.UUID, since time.Time) (map[string]int64, error) {
+	rows, err := r.db.Query(ctx, `
+        SELECT metric, SUM(quantity)::bigint
+        FROM license_usage
+        WHERE license_id = $1 AND day >= $2::date
+        GROUP BY metric
+    `, licenseID, since)
+	if err != nil {
+		r.logger.Error("Failed to sum usage", zap.String("license_id", licenseID.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error summing usage: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	totals := make(map[string]int64)
+	for rows.Next() {
+		var metric string
+		var quantity int64
+		if err := rows.Scan(&metric, &quantity); err != nil {
+			return nil, fmt.Errorf("database error scanning usage: %w", mapError(err))
+		}
+		totals[metric] = quantity
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("database iteration error summing usage: %w", err)
+	}
+	return totals, nil
+}
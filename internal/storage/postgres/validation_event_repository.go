@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/validationevent"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"go.uber.org/zap"
+)
+
+type ValidationEventRepository struct {
+	db     *pgxpool.Pool
+	ids    idgen.Generator
+	logger *zap.Logger
+}
+
+func NewValidationEventRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *ValidationEventRepository {
+	return &ValidationEventRepository{
+		db:     db,
+		ids:    ids,
+		logger: logger.Named("ValidationEventRepository"),
+	}
+}
+
+var _ validationevent.Repository = (*ValidationEventRepository)(nil)
+
+const validationEventColumns = `id, license_id, product_name, valid, reason, agent_version, device_id, ip_address, sample_rate, asn, as_org, country, user_agent, tls_fingerprint, created_at`
+
+func scanValidationEvent(row pgx.Row) (*validationevent.Event, error) {
+	var e validationevent.Event
+	if err := row.Scan(&e.ID, &e.LicenseID, &e.ProductName, &e.Valid, &e.Reason, &e.AgentVersion, &e.DeviceID, &e.IPAddress, &e.SampleRate, &e.ASN, &e.ASOrg, &e.Country, &e.UserAgent, &e.TLSFingerprint, &e.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *ValidationEventRepository) Record(ctx context.Context, e *validationevent.Event) error {
+	e.ID = r.ids.New()
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO validation_events (id, license_id, product_name, valid, reason, agent_version, device_id, ip_address, sample_rate,
+                                       asn, as_org, country, user_agent, tls_fingerprint)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+        RETURNING created_at
+    `, e.ID, e.LicenseID, e.ProductName, e.Valid, e.Reason, e.AgentVersion, e.DeviceID, e.IPAddress, e.SampleRate,
+		e.ASN, e.ASOrg, e.Country, e.UserAgent, e.TLSFingerprint).Scan(&e.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to record validation event", zap.String("product_name", e.ProductName), zap.Error(err))
+		return fmt.Errorf("database error recording validation event: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *ValidationEventRepository) List(ctx context.Context, params validationevent.ListParams) ([]*validationevent.Event, int64, error) {
+	conditions := make([]string, 0, 4)
+	args := make([]interface{}, 0, 5)
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if params.LicenseID != nil {
+		add("license_id = $%d", *params.LicenseID)
+	}
+	if params.ProductName != nil {
+		add("product_name = $%d", *params.ProductName)
+	}
+	if params.Valid != nil {
+		add("valid = $%d", *params.Valid)
+	}
+	if params.Country != nil {
+		add("country = $%d", *params.Country)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM validation_events`+where, args...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count validation events", zap.Error(err))
+		return nil, 0, fmt.Errorf("database error counting validation events: %w", mapError(err))
+	}
+	if total == 0 {
+		return []*validationevent.Event{}, 0, nil
+	}
+
+	query := `SELECT ` + validationEventColumns + ` FROM validation_events` + where + fmt.Sprintf(`
+        ORDER BY created_at DESC, id DESC
+        LIMIT $%d OFFSET $%d
+    `, len(args)+1, len(args)+2)
+	rows, err := r.db.Query(ctx, query, append(args, params.Limit, params.Offset)...)
+	if err != nil {
+		r.logger.Error("Failed to list validation events", zap.Error(err))
+		return nil, 0, fmt.Errorf("database error listing validation events: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	events := make([]*validationevent.Event, 0, params.Limit)
+	for rows.Next() {
+		e, err := scanValidationEvent(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("database error scanning validation event: %w", mapError(err))
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("database iteration error listing validation events: %w", err)
+	}
+	return events, total, nil
+}
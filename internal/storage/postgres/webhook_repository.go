@@ -0,0 +1,258 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makkenzo/license-service-api/internal/domain/webhook"
+	"github.com/makkenzo/license-service-api/internal/idgen"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"go.uber.org/zap"
+)
+
+type WebhookRepository struct {
+	db     *pgxpool.Pool
+	ids    idgen.Generator
+	logger *zap.Logger
+}
+
+func NewWebhookRepository(db *pgxpool.Pool, ids idgen.Generator, logger *zap.Logger) *WebhookRepository {
+	return &WebhookRepository{
+		db:     db,
+		ids:    ids,
+		logger: logger.Named("WebhookRepository"),
+	}
+}
+
+var _ webhook.Repository = (*WebhookRepository)(nil)
+
+const webhookSubscriptionColumns = `id, url, secret, events, description, is_enabled,
+        max_attempts, initial_backoff_seconds, max_backoff_seconds, timeout_seconds,
+        ` + webhookHealthColumns + `, created_by, created_at, updated_at`
+
+const webhookHealthColumns = `consecutive_failures, failing_since, last_success_at, last_failure_at, disabled_reason, disabled_at`
+
+func scanWebhookSubscription(row pgx.Row) (*webhook.Subscription, error) {
+	var s webhook.Subscription
+	p, h := &s.RetryPolicy, &s.Health
+	if err := row.Scan(&s.ID, &s.URL, &s.Secret, &s.Events, &s.Description, &s.IsEnabled,
+		&p.MaxAttempts, &p.InitialBackoffSeconds, &p.MaxBackoffSeconds, &p.TimeoutSeconds,
+		&h.ConsecutiveFailures, &h.FailingSince, &h.LastSuccessAt, &h.LastFailureAt, &h.DisabledReason, &h.DisabledAt,
+		&s.CreatedBy, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, s *webhook.Subscription) error {
+	s.ID = r.ids.New()
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO webhook_subscriptions (id, url, secret, events, description, is_enabled,
+            max_attempts, initial_backoff_seconds, max_backoff_seconds, timeout_seconds, created_by)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        RETURNING created_at, updated_at
+    `, s.ID, s.URL, s.Secret, s.Events, s.Description, s.IsEnabled,
+		s.RetryPolicy.MaxAttempts, s.RetryPolicy.InitialBackoffSeconds, s.RetryPolicy.MaxBackoffSeconds, s.RetryPolicy.TimeoutSeconds,
+		s.CreatedBy).Scan(&s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create webhook subscription", zap.String("url", s.URL), zap.Error(err))
+		return fmt.Errorf("database error creating webhook subscription: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *WebhookRepository) FindByID(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error) {
+	s, err := scanWebhookSubscription(r.db.QueryRow(ctx, `SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find webhook subscription", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error finding webhook subscription: %w", mapError(err))
+	}
+	return s, nil
+}
+
+func (r *WebhookRepository) List(ctx context.Context) ([]*webhook.Subscription, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions ORDER BY created_at ASC`)
+	if err != nil {
+		r.logger.Error("Failed to list webhook subscriptions", zap.Error(err))
+		return nil, fmt.Errorf("database error listing webhook subscriptions: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	subs := make([]*webhook.Subscription, 0)
+	for rows.Next() {
+		s, err := scanWebhookSubscription(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan webhook subscription row", zap.Error(err))
+			return nil, fmt.Errorf("database scan error listing webhook subscriptions: %w", err)
+		}
+		subs = append(subs, s)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("Error iterating webhook subscription rows", zap.Error(err))
+		return nil, fmt.Errorf("database error iterating webhook subscriptions: %w", mapError(err))
+	}
+	return subs, nil
+}
+
+func (r *WebhookRepository) Update(ctx context.Context, s *webhook.Subscription) error {
+	h := &s.Health
+	err := r.db.QueryRow(ctx, `
+        UPDATE webhook_subscriptions
+        SET url = $1, secret = $2, events = $3, description = $4, is_enabled = $5,
+            max_attempts = $6, initial_backoff_seconds = $7, max_backoff_seconds = $8, timeout_seconds = $9,
+            consecutive_failures = CASE WHEN $5 AND NOT is_enabled THEN 0 ELSE consecutive_failures END,
+            failing_since = CASE WHEN $5 AND NOT is_enabled THEN NULL ELSE failing_since END,
+            disabled_reason = CASE WHEN $5 AND NOT is_enabled THEN '' ELSE disabled_reason END,
+            disabled_at = CASE WHEN $5 AND NOT is_enabled THEN NULL ELSE disabled_at END
+        WHERE id = $10
+        RETURNING `+webhookHealthColumns+`, updated_at
+    `, s.URL, s.Secret, s.Events, s.Description, s.IsEnabled,
+		s.RetryPolicy.MaxAttempts, s.RetryPolicy.InitialBackoffSeconds, s.RetryPolicy.MaxBackoffSeconds, s.RetryPolicy.TimeoutSeconds,
+		s.ID).Scan(&h.ConsecutiveFailures, &h.FailingSince, &h.LastSuccessAt, &h.LastFailureAt, &h.DisabledReason, &h.DisabledAt, &s.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: webhook subscription with ID %s not found for update", ierr.ErrNotFound, s.ID)
+		}
+		r.logger.Error("Failed to update webhook subscription", zap.String("id", s.ID.String()), zap.Error(err))
+		return fmt.Errorf("database error updating webhook subscription: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	cmdTag, err := r.db.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete webhook subscription", zap.String("id", id.String()), zap.Error(err))
+		return fmt.Errorf("database error deleting webhook subscription: %w", mapError(err))
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ierr.ErrNotFound
+	}
+	return nil
+}
+
+func (r *WebhookRepository) RecordHealth(ctx context.Context, id uuid.UUID, succeeded bool, at time.Time) (*webhook.Health, error) {
+	var h webhook.Health
+	err := r.db.QueryRow(ctx, `
+        UPDATE webhook_subscriptions
+        SET consecutive_failures = CASE WHEN $2 THEN 0 ELSE consecutive_failures + 1 END,
+            failing_since = CASE WHEN $2 THEN NULL ELSE COALESCE(failing_since, $3) END,
+            last_success_at = CASE WHEN $2 THEN $3 ELSE last_success_at END,
+            last_failure_at = CASE WHEN $2 THEN last_failure_at ELSE $3 END
+        WHERE id = $1
+        RETURNING `+webhookHealthColumns+`
+    `, id, succeeded, at).Scan(&h.ConsecutiveFailures, &h.FailingSince, &h.LastSuccessAt, &h.LastFailureAt, &h.DisabledReason, &h.DisabledAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to record webhook subscription health", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error recording webhook subscription health: %w", mapError(err))
+	}
+	return &h, nil
+}
+
+func (r *WebhookRepository) Disable(ctx context.Context, id uuid.UUID, reason string, at time.Time) (bool, error) {
+	cmdTag, err := r.db.Exec(ctx, `
+        UPDATE webhook_subscriptions
+        SET is_enabled = FALSE, disabled_reason = $2, disabled_at = $3
+        WHERE id = $1 AND is_enabled
+    `, id, reason, at)
+	if err != nil {
+		r.logger.Error("Failed to disable webhook subscription", zap.String("id", id.String()), zap.Error(err))
+		return false, fmt.Errorf("database error disabling webhook subscription: %w", mapError(err))
+	}
+	return cmdTag.RowsAffected() > 0, nil
+}
+
+const webhookDeliveryColumns = `id, subscription_id, event_id, event, payload, status, attempts, response_status, last_error, created_at, last_attempt_at`
+
+func scanWebhookDelivery(row pgx.Row) (*webhook.Delivery, error) {
+	var d webhook.Delivery
+	if err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.Event, &d.Payload, &d.Status, &d.Attempts,
+		&d.ResponseStatus, &d.LastError, &d.CreatedAt, &d.LastAttemptAt); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, d *webhook.Delivery) error {
+	d.ID = r.ids.New()
+	d.Status = webhook.DeliveryPending
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO webhook_deliveries (id, subscription_id, event_id, event, payload, status)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING created_at
+    `, d.ID, d.SubscriptionID, d.EventID, d.Event, d.Payload, d.Status).Scan(&d.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create webhook delivery", zap.String("subscription_id", d.SubscriptionID.String()), zap.Error(err))
+		return fmt.Errorf("database error creating webhook delivery: %w", mapError(err))
+	}
+	return nil
+}
+
+func (r *WebhookRepository) FindDelivery(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	d, err := scanWebhookDelivery(r.db.QueryRow(ctx, `SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		r.logger.Error("Failed to find webhook delivery", zap.String("id", id.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error finding webhook delivery: %w", mapError(err))
+	}
+	return d, nil
+}
+
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, id uuid.UUID, a webhook.Attempt) error {
+	cmdTag, err := r.db.Exec(ctx, `
+        UPDATE webhook_deliveries
+        SET status = $2, attempts = attempts + 1, response_status = $3, last_error = $4, last_attempt_at = $5
+        WHERE id = $1
+    `, id, a.Status, a.ResponseStatus, a.Error, a.At)
+	if err != nil {
+		r.logger.Error("Failed to record webhook delivery attempt", zap.String("id", id.String()), zap.Error(err))
+		return fmt.Errorf("database error recording webhook delivery attempt: %w", mapError(err))
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ierr.ErrNotFound
+	}
+	return nil
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]*webhook.Delivery, error) {
+	rows, err := r.db.Query(ctx, `
+        SELECT `+webhookDeliveryColumns+`
+        FROM webhook_deliveries
+        WHERE subscription_id = $1
+        ORDER BY created_at DESC, id DESC
+        LIMIT $2
+    `, subscriptionID, limit)
+	if err != nil {
+		r.logger.Error("Failed to list webhook deliveries", zap.String("subscription_id", subscriptionID.String()), zap.Error(err))
+		return nil, fmt.Errorf("database error listing webhook deliveries: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	deliveries := make([]*webhook.Delivery, 0)
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan webhook delivery row", zap.Error(err))
+			return nil, fmt.Errorf("database scan error listing webhook deliveries: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("Error iterating webhook delivery rows", zap.Error(err))
+		return nil, fmt.Errorf("database error iterating webhook deliveries: %w", mapError(err))
+	}
+	return deliveries, nil
+}
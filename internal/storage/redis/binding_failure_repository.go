@@ -0,0 +1,84 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/domain/bindingfailure"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	bindingFailureKeyPrefix = "binding_failures:"
+	bindingFailureRetention = 8 * 7 * 24 * time.Hour
+	bindingFailureSeparator = "|"
+)
+
+// BindingFailureRepository keeps one hash per ISO week, keyed by
+// endpoint|field|client, so the weekly report is a single HGETALL.
+type BindingFailureRepository struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+func NewBindingFailureRepository(client *redis.Client, logger *zap.Logger) *BindingFailureRepository {
+	return &BindingFailureRepository{
+		client: client,
+		logger: logger.Named("BindingFailureRepository"),
+	}
+}
+
+var _ bindingfailure.Repository = (*BindingFailureRepository)(nil)
+
+func (r *BindingFailureRepository) Record(ctx context.Context, failure bindingfailure.Failure) error {
+	key := bindingFailureKeyPrefix + bindingfailure.WeekKey(time.Now())
+	field := strings.Join([]string{failure.Endpoint, failure.Field, failure.Client}, bindingFailureSeparator)
+
+	pipe := r.client.Pipeline()
+	pipe.HIncrBy(ctx, key, field, 1)
+	pipe.Expire(ctx, key, bindingFailureRetention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis error recording binding failure: %w", err)
+	}
+	return nil
+}
+
+func (r *BindingFailureRepository) Top(ctx context.Context, week string, limit int) ([]bindingfailure.Offender, error) {
+	counts, err := r.client.HGetAll(ctx, bindingFailureKeyPrefix+week).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis error reading binding failures for %s: %w", week, err)
+	}
+
+	offenders := make([]bindingfailure.Offender, 0, len(counts))
+	for field, value := range counts {
+		parts := strings.SplitN(field, bindingFailureSeparator, 3)
+		if len(parts) != 3 {
+			r.logger.Warn("Skipping malformed binding failure entry", zap.String("field", field))
+			continue
+		}
+		var count int64
+		if _, err := fmt.Sscan(value, &count); err != nil {
+			r.logger.Warn("Skipping binding failure entry with invalid count", zap.String("field", field), zap.String("value", value))
+			continue
+		}
+		offenders = append(offenders, bindingfailure.Offender{
+			Failure: bindingfailure.Failure{Endpoint: parts[0], Field: parts[1], Client: parts[2]},
+			Count:   count,
+		})
+	}
+
+	sort.Slice(offenders, func(i, j int) bool {
+		if offenders[i].Count != offenders[j].Count {
+			return offenders[i].Count > offenders[j].Count
+		}
+		return offenders[i].Endpoint+offenders[i].Field+offenders[i].Client < offenders[j].Endpoint+offenders[j].Field+offenders[j].Client
+	})
+	if limit > 0 && len(offenders) > limit {
+		offenders = offenders[:limit]
+	}
+	return offenders, nil
+}
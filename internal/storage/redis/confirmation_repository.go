@@ -0,0 +1,47 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/domain/confirmation"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const confirmationKeyPrefix = "confirmation:"
+
+type ConfirmationRepository struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+func NewConfirmationRepository(client *redis.Client, logger *zap.Logger) *ConfirmationRepository {
+	return &ConfirmationRepository{
+		client: client,
+		logger: logger.Named("ConfirmationRepository"),
+	}
+}
+
+var _ confirmation.Repository = (*ConfirmationRepository)(nil)
+
+func (r *ConfirmationRepository) Save(ctx context.Context, token string, data []byte, ttl time.Duration) error {
+	if err := r.client.Set(ctx, confirmationKeyPrefix+token, data, ttl).Err(); err != nil {
+		return fmt.Errorf("redis error saving confirmation token: %w", err)
+	}
+	return nil
+}
+
+func (r *ConfirmationRepository) Take(ctx context.Context, token string) ([]byte, error) {
+	data, err := r.client.GetDel(ctx, confirmationKeyPrefix+token).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ierr.ErrNotFound
+		}
+		return nil, fmt.Errorf("redis error reading confirmation token: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,85 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/domain/lease"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const leaseKeyPrefix = "lease:"
+
+// checkoutScript keeps the leases of a license in a sorted set scored by
+// expiry. Expired leases are dropped before seats are counted, so stale
+// leases free their seat without a sweeper, and the key itself expires
+// with its last lease.
+var checkoutScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+if not redis.call('ZSCORE', KEYS[1], ARGV[4]) and redis.call('ZCARD', KEYS[1]) >= tonumber(ARGV[3]) then
+  return -1
+end
+redis.call('ZADD', KEYS[1], ARGV[2], ARGV[4])
+local last = redis.call('ZRANGE', KEYS[1], -1, -1, 'WITHSCORES')
+redis.call('PEXPIREAT', KEYS[1], last[2])
+return redis.call('ZCARD', KEYS[1])
+`)
+
+type LeaseRepository struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+func NewLeaseRepository(client *redis.Client, logger *zap.Logger) *LeaseRepository {
+	return &LeaseRepository{
+		client: client,
+		logger: logger.Named("LeaseRepository"),
+	}
+}
+
+var _ lease.Repository = (*LeaseRepository)(nil)
+
+func leaseKey(licenseID uuid.UUID) string {
+	return leaseKeyPrefix + licenseID.String()
+}
+
+func (r *LeaseRepository) Checkout(ctx context.Context, licenseID uuid.UUID, clientID string, ttl time.Duration, maxSeats int) (*lease.Lease, int, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	inUse, err := checkoutScript.Run(ctx, r.client, []string{leaseKey(licenseID)},
+		now.UnixMilli(), expiresAt.UnixMilli(), maxSeats, clientID,
+	).Int()
+	if err != nil {
+		r.logger.Error("Failed to check out floating seat", zap.String("license_id", licenseID.String()), zap.Error(err))
+		return nil, 0, fmt.Errorf("redis error checking out seat: %w", err)
+	}
+	if inUse < 0 {
+		return nil, maxSeats, lease.ErrNoFreeSeat
+	}
+	return &lease.Lease{LicenseID: licenseID, ClientID: clientID, ExpiresAt: expiresAt}, inUse, nil
+}
+
+func (r *LeaseRepository) Checkin(ctx context.Context, licenseID uuid.UUID, clientID string) error {
+	removed, err := r.client.ZRem(ctx, leaseKey(licenseID), clientID).Result()
+	if err != nil {
+		return fmt.Errorf("redis error checking in seat: %w", err)
+	}
+	if removed == 0 {
+		return ierr.ErrNotFound
+	}
+	return nil
+}
+
+func (r *LeaseRepository) InUse(ctx context.Context, licenseID uuid.UUID) (int, error) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	n, err := r.client.ZCount(ctx, leaseKey(licenseID), "("+now, "+inf").Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis error counting leases: %w", err)
+	}
+	return int(n), nil
+}
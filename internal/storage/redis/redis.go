@@ -24,6 +24,7 @@ func NewRedisClient(ctx context.Context, cfg *config.RedisConfig, logger *zap.Lo
 
 	_, err := client.Ping(pingCtx).Result()
 	if err != nil {
+		_ = client.Close()
 		return nil, fmt.Errorf("failed to ping redis: %w", err)
 	}
 
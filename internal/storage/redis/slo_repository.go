@@ -0,0 +1,100 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/domain/slo"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	sloKeyPrefix       = "slo:validate:"
+	sloMinuteRetention = 24 * time.Hour
+)
+
+var sloFields = []string{"requests", "errors", "slow"}
+
+// SLORepository keeps one hash of counts per minute and per hour. Minute
+// buckets expire after a day, hour buckets after the SLO window.
+type SLORepository struct {
+	client        *redis.Client
+	hourRetention time.Duration
+	logger        *zap.Logger
+}
+
+func NewSLORepository(client *redis.Client, window time.Duration, logger *zap.Logger) *SLORepository {
+	return &SLORepository{
+		client:        client,
+		hourRetention: window + 2*time.Hour,
+		logger:        logger.Named("SLORepository"),
+	}
+}
+
+var _ slo.Repository = (*SLORepository)(nil)
+
+func sloKey(resolution time.Duration, start time.Time) string {
+	prefix := "m:"
+	if resolution == slo.Hour {
+		prefix = "h:"
+	}
+	return sloKeyPrefix + prefix + strconv.FormatInt(start.Unix(), 10)
+}
+
+func (r *SLORepository) Add(ctx context.Context, counts map[time.Time]slo.Counts) error {
+	pipe := r.client.Pipeline()
+	add := func(key string, c slo.Counts, retention time.Duration) {
+		pipe.HIncrBy(ctx, key, "requests", c.Requests)
+		if c.Errors > 0 {
+			pipe.HIncrBy(ctx, key, "errors", c.Errors)
+		}
+		if c.Slow > 0 {
+			pipe.HIncrBy(ctx, key, "slow", c.Slow)
+		}
+		pipe.Expire(ctx, key, retention)
+	}
+	for minute, c := range counts {
+		add(sloKey(slo.Minute, minute.Truncate(slo.Minute)), c, sloMinuteRetention)
+		add(sloKey(slo.Hour, minute.Truncate(slo.Hour)), c, r.hourRetention)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis error adding slo counts: %w", err)
+	}
+	return nil
+}
+
+func (r *SLORepository) Sum(ctx context.Context, resolution time.Duration, from, to time.Time) (slo.Counts, error) {
+	pipe := r.client.Pipeline()
+	var cmds []*redis.SliceCmd
+	for start := from.Truncate(resolution); start.Before(to); start = start.Add(resolution) {
+		cmds = append(cmds, pipe.HMGet(ctx, sloKey(resolution, start), sloFields...))
+	}
+	var total slo.Counts
+	if len(cmds) == 0 {
+		return total, nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return total, fmt.Errorf("redis error reading slo counts: %w", err)
+	}
+
+	for _, cmd := range cmds {
+		var values [3]int64
+		for i, v := range cmd.Val() {
+			s, ok := v.(string)
+			if !ok {
+				continue
+			}
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				r.logger.Warn("Skipping slo count with invalid value", zap.String("field", sloFields[i]), zap.String("value", s))
+				continue
+			}
+			values[i] = n
+		}
+		total.Add(slo.Counts{Requests: values[0], Errors: values[1], Slow: values[2]})
+	}
+	return total, nil
+}
@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/domain/stepup"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	stepUpGrantKeyPrefix   = "stepup:grant:"
+	stepUpFailureKeyPrefix = "stepup:failures:"
+)
+
+type StepUpGrantRepository struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+func NewStepUpGrantRepository(client *redis.Client, logger *zap.Logger) *StepUpGrantRepository {
+	return &StepUpGrantRepository{
+		client: client,
+		logger: logger.Named("StepUpGrantRepository"),
+	}
+}
+
+var _ stepup.GrantRepository = (*StepUpGrantRepository)(nil)
+
+func (r *StepUpGrantRepository) Save(ctx context.Context, subject string, at time.Time, ttl time.Duration) error {
+	if err := r.client.Set(ctx, stepUpGrantKeyPrefix+subject, at.UnixMilli(), ttl).Err(); err != nil {
+		return fmt.Errorf("redis error saving step-up grant: %w", err)
+	}
+	return nil
+}
+
+func (r *StepUpGrantRepository) Find(ctx context.Context, subject string) (time.Time, error) {
+	ms, err := r.client.Get(ctx, stepUpGrantKeyPrefix+subject).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return time.Time{}, ierr.ErrNotFound
+		}
+		return time.Time{}, fmt.Errorf("redis error reading step-up grant: %w", err)
+	}
+	return time.UnixMilli(ms), nil
+}
+
+func (r *StepUpGrantRepository) Failures(ctx context.Context, subject string) (int64, error) {
+	n, err := r.client.Get(ctx, stepUpFailureKeyPrefix+subject).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, fmt.Errorf("redis error reading step-up failures: %w", err)
+	}
+	return n, nil
+}
+
+func (r *StepUpGrantRepository) AddFailure(ctx context.Context, subject string, window time.Duration) error {
+	key := stepUpFailureKeyPrefix + subject
+	pipe := r.client.TxPipeline()
+	pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis error counting step-up failure: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package tabular
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+type csvWriter struct {
+	w           *csv.Writer
+	columns     []Column
+	wroteHeader bool
+	record      []string
+}
+
+func newCSVWriter(w io.Writer, columns []Column) *csvWriter {
+	return &csvWriter{
+		w:       csv.NewWriter(w),
+		columns: columns,
+		record:  make([]string, len(columns)),
+	}
+}
+
+func (c *csvWriter) header() error {
+	if c.wroteHeader {
+		return nil
+	}
+	c.wroteHeader = true
+	for i, col := range c.columns {
+		c.record[i] = col.Name
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvWriter) WriteRow(values ...any) error {
+	if err := checkRow(c.columns, values); err != nil {
+		return err
+	}
+	if err := c.header(); err != nil {
+		return err
+	}
+	for i, v := range values {
+		switch v := v.(type) {
+		case int64:
+			c.record[i] = strconv.FormatInt(v, 10)
+		case string:
+			c.record[i] = v
+		case time.Time:
+			c.record[i] = v.UTC().Format("2006-01-02")
+		}
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvWriter) Close() error {
+	// A file without rows still gets its header.
+	if err := c.header(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,326 @@
+package tabular
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// parquetRowGroupRows is how many rows are buffered before they are written
+// out as a row group.
+const parquetRowGroupRows = 100_000
+
+// Parquet enum values used by the writer, see parquet.thrift.
+const (
+	parquetInt32     = 1
+	parquetInt64     = 2
+	parquetByteArray = 6
+
+	parquetRequired = 0
+
+	parquetConvertedUTF8 = 0
+	parquetConvertedDate = 6
+
+	parquetEncodingPlain = 0
+	parquetEncodingRLE   = 3
+
+	parquetCodecGzip = 2
+
+	parquetDataPage = 0
+)
+
+var parquetMagic = []byte("PAR1")
+
+type parquetChunk struct {
+	column           Column
+	offset           int64
+	uncompressedSize int64
+	compressedSize   int64
+}
+
+type parquetRowGroup struct {
+	chunks []parquetChunk
+	rows   int64
+}
+
+// parquetWriter writes each row group as one gzip-compressed, plain-encoded
+// data page per column. That is the simplest layout every Parquet reader
+// understands; files are larger than with dictionary encoding, which the
+// exports do not need.
+type parquetWriter struct {
+	w         io.Writer
+	offset    int64
+	columns   []Column
+	pages     []bytes.Buffer
+	rows      int64
+	rowGroups []parquetRowGroup
+	err       error
+}
+
+func newParquetWriter(w io.Writer, columns []Column) *parquetWriter {
+	return &parquetWriter{
+		w:       w,
+		columns: columns,
+		pages:   make([]bytes.Buffer, len(columns)),
+	}
+}
+
+func (p *parquetWriter) write(b []byte) {
+	if p.err != nil {
+		return
+	}
+	n, err := p.w.Write(b)
+	p.offset += int64(n)
+	p.err = err
+}
+
+func (p *parquetWriter) WriteRow(values ...any) error {
+	if err := checkRow(p.columns, values); err != nil {
+		return err
+	}
+	if p.offset == 0 {
+		p.write(parquetMagic)
+	}
+	for i, v := range values {
+		page := &p.pages[i]
+		switch v := v.(type) {
+		case int64:
+			_ = binary.Write(page, binary.LittleEndian, v)
+		case string:
+			_ = binary.Write(page, binary.LittleEndian, uint32(len(v)))
+			page.WriteString(v)
+		case time.Time:
+			_ = binary.Write(page, binary.LittleEndian, int32(daysSinceEpoch(v)))
+		}
+	}
+	p.rows++
+	if p.rows == parquetRowGroupRows {
+		p.flushRowGroup()
+	}
+	return p.err
+}
+
+func daysSinceEpoch(t time.Time) int64 {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400
+}
+
+func (p *parquetWriter) flushRowGroup() {
+	if p.rows == 0 {
+		return
+	}
+	group := parquetRowGroup{rows: p.rows}
+	for i, col := range p.columns {
+		raw := p.pages[i].Bytes()
+		var compressed bytes.Buffer
+		gz := gzip.NewWriter(&compressed)
+		_, _ = gz.Write(raw)
+		_ = gz.Close()
+
+		header := thriftStruct()
+		header.i32(1, parquetDataPage)
+		header.i32(2, int32(len(raw)))
+		header.i32(3, int32(compressed.Len()))
+		header.beginStruct(5)
+		header.i32(1, int32(p.rows))
+		header.i32(2, parquetEncodingPlain)
+		header.i32(3, parquetEncodingRLE)
+		header.i32(4, parquetEncodingRLE)
+		header.endStruct()
+		headerBytes := header.finish()
+
+		chunk := parquetChunk{
+			column:           col,
+			offset:           p.offset,
+			uncompressedSize: int64(len(headerBytes) + len(raw)),
+			compressedSize:   int64(len(headerBytes) + compressed.Len()),
+		}
+		p.write(headerBytes)
+		p.write(compressed.Bytes())
+		group.chunks = append(group.chunks, chunk)
+		p.pages[i].Reset()
+	}
+	p.rowGroups = append(p.rowGroups, group)
+	p.rows = 0
+}
+
+func (p *parquetWriter) Close() error {
+	if p.offset == 0 {
+		p.write(parquetMagic)
+	}
+	p.flushRowGroup()
+
+	var totalRows int64
+	for _, g := range p.rowGroups {
+		totalRows += g.rows
+	}
+
+	meta := thriftStruct()
+	meta.i32(1, 1)
+	meta.beginList(2, thriftTypeStruct, len(p.columns)+1)
+	meta.beginElement()
+	meta.binary(4, "schema")
+	meta.i32(5, int32(len(p.columns)))
+	meta.endStruct()
+	for _, col := range p.columns {
+		meta.beginElement()
+		meta.i32(1, physicalType(col.Type))
+		meta.i32(3, parquetRequired)
+		meta.binary(4, col.Name)
+		switch col.Type {
+		case String:
+			meta.i32(6, parquetConvertedUTF8)
+			meta.beginStruct(10)
+			meta.beginStruct(1) // StringType
+			meta.endStruct()
+			meta.endStruct()
+		case Date:
+			meta.i32(6, parquetConvertedDate)
+			meta.beginStruct(10)
+			meta.beginStruct(6) // DateType
+			meta.endStruct()
+			meta.endStruct()
+		}
+		meta.endStruct()
+	}
+	meta.i64(3, totalRows)
+	meta.beginList(4, thriftTypeStruct, len(p.rowGroups))
+	for _, g := range p.rowGroups {
+		var total int64
+		meta.beginElement()
+		meta.beginList(1, thriftTypeStruct, len(g.chunks))
+		for _, c := range g.chunks {
+			total += c.uncompressedSize
+			meta.beginElement()
+			meta.i64(2, c.offset)
+			meta.beginStruct(3)
+			meta.i32(1, physicalType(c.column.Type))
+			meta.beginList(2, thriftTypeI32, 1)
+			meta.listI32(parquetEncodingPlain)
+			meta.beginList(3, thriftTypeBinary, 1)
+			meta.listBinary(c.column.Name)
+			meta.i32(4, parquetCodecGzip)
+			meta.i64(5, g.rows)
+			meta.i64(6, c.uncompressedSize)
+			meta.i64(7, c.compressedSize)
+			meta.i64(9, c.offset)
+			meta.endStruct()
+			meta.endStruct()
+		}
+		meta.i64(2, total)
+		meta.i64(3, g.rows)
+		meta.endStruct()
+	}
+	meta.binary(6, "license-service-api")
+	footer := meta.finish()
+
+	p.write(footer)
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(footer)))
+	p.write(length[:])
+	p.write(parquetMagic)
+	return p.err
+}
+
+func physicalType(t ColumnType) int32 {
+	switch t {
+	case String:
+		return parquetByteArray
+	case Date:
+		return parquetInt32
+	default:
+		return parquetInt64
+	}
+}
+
+// Thrift compact protocol type IDs.
+const (
+	thriftTypeI32    = 5
+	thriftTypeI64    = 6
+	thriftTypeBinary = 8
+	thriftTypeList   = 9
+	thriftTypeStruct = 12
+)
+
+// thriftWriter encodes the Parquet metadata structs in the Thrift compact
+// protocol. Fields must be written in increasing ID order within a struct.
+type thriftWriter struct {
+	buf     bytes.Buffer
+	lastIDs []int16
+}
+
+func thriftStruct() *thriftWriter {
+	return &thriftWriter{lastIDs: []int16{0}}
+}
+
+func (t *thriftWriter) finish() []byte {
+	t.buf.WriteByte(0)
+	return t.buf.Bytes()
+}
+
+func (t *thriftWriter) uvarint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	t.buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+func (t *thriftWriter) field(id int16, typ byte) {
+	last := &t.lastIDs[len(t.lastIDs)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.uvarint(uint64(uint16((id << 1) ^ (id >> 15))))
+	}
+	*last = id
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.field(id, thriftTypeI32)
+	t.listI32(v)
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.field(id, thriftTypeI64)
+	t.uvarint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (t *thriftWriter) binary(id int16, s string) {
+	t.field(id, thriftTypeBinary)
+	t.listBinary(s)
+}
+
+func (t *thriftWriter) beginStruct(id int16) {
+	t.field(id, thriftTypeStruct)
+	t.beginElement()
+}
+
+// beginElement starts a struct that is an element of a list.
+func (t *thriftWriter) beginElement() {
+	t.lastIDs = append(t.lastIDs, 0)
+}
+
+func (t *thriftWriter) endStruct() {
+	t.buf.WriteByte(0)
+	t.lastIDs = t.lastIDs[:len(t.lastIDs)-1]
+}
+
+func (t *thriftWriter) beginList(id int16, elemType byte, size int) {
+	t.field(id, thriftTypeList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elemType)
+		return
+	}
+	t.buf.WriteByte(0xF0 | elemType)
+	t.uvarint(uint64(size))
+}
+
+func (t *thriftWriter) listI32(v int32) {
+	t.uvarint(uint64(uint32((v << 1) ^ (v >> 31))))
+}
+
+func (t *thriftWriter) listBinary(s string) {
+	t.uvarint(uint64(len(s)))
+	t.buf.WriteString(s)
+}
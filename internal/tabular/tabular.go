@@ -0,0 +1,80 @@
+// Package tabular writes rows of a fixed set of columns as CSV or Parquet
+// files for data teams. Only the column types the exports need are
+// supported, and every column is required: there are no nulls.
+package tabular
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type ColumnType int
+
+const (
+	Int64 ColumnType = iota
+	String
+	// Date columns take time.Time values; only the UTC date is kept.
+	Date
+)
+
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// Writer writes rows with one value per column, in column order: int64 for
+// Int64, string for String and time.Time for Date. Close writes what is
+// still buffered and the file trailer; it does not close the underlying
+// writer.
+type Writer interface {
+	WriteRow(values ...any) error
+	Close() error
+}
+
+const (
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+)
+
+// NewWriter returns a writer for format, "csv" or "parquet".
+func NewWriter(format string, w io.Writer, columns []Column) (Writer, error) {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return newCSVWriter(w, columns), nil
+	case FormatParquet:
+		return newParquetWriter(w, columns), nil
+	default:
+		return nil, fmt.Errorf("unknown table format %q, expected csv or parquet", format)
+	}
+}
+
+// ContentType returns the media type of files written in format.
+func ContentType(format string) string {
+	if strings.ToLower(format) == FormatParquet {
+		return "application/vnd.apache.parquet"
+	}
+	return "text/csv"
+}
+
+func checkRow(columns []Column, values []any) error {
+	if len(values) != len(columns) {
+		return fmt.Errorf("row has %d values for %d columns", len(values), len(columns))
+	}
+	for i, col := range columns {
+		var ok bool
+		switch col.Type {
+		case Int64:
+			_, ok = values[i].(int64)
+		case String:
+			_, ok = values[i].(string)
+		case Date:
+			_, ok = values[i].(time.Time)
+		}
+		if !ok {
+			return fmt.Errorf("column %s: unexpected value of type %T", col.Name, values[i])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+type AnalyticsExporter interface {
+	ExportPreviousDay(ctx context.Context) error
+}
+
+type AnalyticsExportHandler struct {
+	exporter AnalyticsExporter
+	logger   *zap.Logger
+}
+
+func NewAnalyticsExportHandler(exporter AnalyticsExporter, logger *zap.Logger) *AnalyticsExportHandler {
+	return &AnalyticsExportHandler{
+		exporter: exporter,
+		logger:   logger.Named("AnalyticsExportHandler"),
+	}
+}
+
+// ProcessTask is retried on failure: the export replaces the object of the
+// day, so running it again is safe.
+func (h *AnalyticsExportHandler) ProcessTask(ctx context.Context, t *asynq.Task) error {
+	if t.Type() != TypeAnalyticsExport {
+		return fmt.Errorf("unexpected task type: %s", t.Type())
+	}
+
+	if err := h.exporter.ExportPreviousDay(ctx); err != nil {
+		h.logger.Error("Analytics export failed", zap.Error(err))
+		return fmt.Errorf("analytics export error: %w", err)
+	}
+	return nil
+}
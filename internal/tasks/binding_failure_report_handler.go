@@ -0,0 +1,56 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/makkenzo/license-service-api/internal/domain/bindingfailure"
+	"go.uber.org/zap"
+)
+
+// BindingFailureReportHandler logs the previous week's top payload binding
+// offenders so API owners can chase the integrations sending bad requests.
+type BindingFailureReportHandler struct {
+	repo   bindingfailure.Repository
+	topN   int
+	logger *zap.Logger
+}
+
+func NewBindingFailureReportHandler(repo bindingfailure.Repository, topN int, logger *zap.Logger) *BindingFailureReportHandler {
+	return &BindingFailureReportHandler{
+		repo:   repo,
+		topN:   topN,
+		logger: logger.Named("BindingFailureReportHandler"),
+	}
+}
+
+func (h *BindingFailureReportHandler) ProcessTask(ctx context.Context, t *asynq.Task) error {
+	if t.Type() != TypeBindingFailureReport {
+		return fmt.Errorf("unexpected task type: %s", t.Type())
+	}
+
+	week := bindingfailure.WeekKey(time.Now().AddDate(0, 0, -7))
+	h.logger.Info("Building weekly binding failure report...", zap.String("week", week))
+
+	offenders, err := h.repo.Top(ctx, week, h.topN)
+	if err != nil {
+		h.logger.Error("Failed to load binding failures for weekly report", zap.String("week", week), zap.Error(err))
+		return fmt.Errorf("binding failure report error: %w", err)
+	}
+
+	for i, o := range offenders {
+		h.logger.Info("Binding failure offender",
+			zap.String("week", week),
+			zap.Int("rank", i+1),
+			zap.String("endpoint", o.Endpoint),
+			zap.String("field", o.Field),
+			zap.String("client", o.Client),
+			zap.Int64("count", o.Count),
+		)
+	}
+
+	h.logger.Info("Weekly binding failure report finished", zap.String("week", week), zap.Int("offenders", len(offenders)))
+	return nil
+}
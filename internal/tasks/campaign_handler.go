@@ -0,0 +1,67 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+// CampaignRunner runs the two steps of an e-mail campaign. lastAttempt is
+// set when asynq will not retry a failure, so the campaign or the failed
+// sends of a batch can be marked failed.
+type CampaignRunner interface {
+	PrepareCampaign(ctx context.Context, id uuid.UUID, lastAttempt bool) error
+	SendCampaignBatch(ctx context.Context, id uuid.UUID, batch int, lastAttempt bool) error
+}
+
+// CampaignHandler processes both the prepare and the send tasks of
+// campaigns.
+type CampaignHandler struct {
+	runner CampaignRunner
+	logger *zap.Logger
+}
+
+func NewCampaignHandler(runner CampaignRunner, logger *zap.Logger) *CampaignHandler {
+	return &CampaignHandler{
+		runner: runner,
+		logger: logger.Named("CampaignHandler"),
+	}
+}
+
+func (h *CampaignHandler) ProcessTask(ctx context.Context, t *asynq.Task) error {
+	retried, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+
+	switch t.Type() {
+	case TypeCampaignPrepare:
+		var p CampaignPreparePayload
+		if err := json.Unmarshal(t.Payload(), &p); err != nil {
+			h.logger.Error("Failed to unmarshal payload for campaign prepare task", zap.Error(err), zap.ByteString("payload", t.Payload()))
+			return fmt.Errorf("invalid payload: %v: %w", err, asynq.SkipRetry)
+		}
+		if err := h.runner.PrepareCampaign(ctx, p.CampaignID, retried >= maxRetry); err != nil {
+			h.logger.Error("Failed to prepare e-mail campaign", zap.String("campaign_id", p.CampaignID.String()), zap.Error(err))
+			return fmt.Errorf("campaign prepare error: %w", err)
+		}
+		return nil
+
+	case TypeCampaignSend:
+		var p CampaignSendPayload
+		if err := json.Unmarshal(t.Payload(), &p); err != nil {
+			h.logger.Error("Failed to unmarshal payload for campaign send task", zap.Error(err), zap.ByteString("payload", t.Payload()))
+			return fmt.Errorf("invalid payload: %v: %w", err, asynq.SkipRetry)
+		}
+		if err := h.runner.SendCampaignBatch(ctx, p.CampaignID, p.Batch, retried >= maxRetry); err != nil {
+			h.logger.Warn("E-mail campaign batch failed", zap.String("campaign_id", p.CampaignID.String()), zap.Int("batch", p.Batch), zap.Int("retried", retried), zap.Error(err))
+			return fmt.Errorf("campaign send error: %w", err)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unexpected task type: %s", t.Type())
+	}
+}
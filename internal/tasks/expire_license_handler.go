@@ -39,6 +39,11 @@ func (h *LicenseExpireHandler) ProcessTask(ctx context.Context, t *asynq.Task) e
 	h.logger.Info("Processing license expiration check task...")
 
 	now := time.Now().UTC()
+	activatedCount, err := h.activateDue(ctx, now)
+	if err != nil {
+		return err
+	}
+
 	params := license.ListParams{
 		Status:    ptr(license.StatusActive),
 		SortBy:    "expires_at",
@@ -66,7 +71,7 @@ func (h *LicenseExpireHandler) ProcessTask(ctx context.Context, t *asynq.Task) e
 
 		for _, lic := range licensesToExpire {
 
-			if lic.ExpiresAt.Valid && lic.ExpiresAt.Time.UTC().Before(now) {
+			if lic.Lapsed(now) {
 				h.logger.Info("Found expired license, updating status",
 					zap.String("license_id", lic.ID.String()),
 					zap.String("license_key", lic.LicenseKey),
@@ -99,10 +104,58 @@ func (h *LicenseExpireHandler) ProcessTask(ctx context.Context, t *asynq.Task) e
 		}
 	}
 
-	h.logger.Info("License expiration check task finished", zap.Int("processed_licenses", processedCount), zap.Int("updated_to_expired", updatedCount))
+	h.logger.Info("License expiration check task finished",
+		zap.Int("processed_licenses", processedCount),
+		zap.Int("updated_to_active", activatedCount),
+		zap.Int("updated_to_expired", updatedCount),
+	)
 	return nil
 }
 
+// activateDue marks pending licenses whose starts_at has passed as active.
+// Activated licenses drop out of the listing, so only failures move the
+// offset on.
+func (h *LicenseExpireHandler) activateDue(ctx context.Context, now time.Time) (int, error) {
+	params := license.ListParams{
+		Status:       ptr(license.StatusPending),
+		StartsBefore: &now,
+		SortBy:       "starts_at",
+		SortOrder:    "ASC",
+		Limit:        1000,
+	}
+
+	activated := 0
+	for {
+		due, _, err := h.repo.List(ctx, params)
+		if err != nil {
+			h.logger.Error("Failed to list scheduled licenses for activation", zap.Error(err))
+			return activated, fmt.Errorf("repository error listing scheduled licenses: %w", err)
+		}
+
+		failed := 0
+		for _, lic := range due {
+			if err := h.repo.UpdateStatus(ctx, lic.ID, license.StatusActive); err != nil {
+				h.logger.Error("Failed to activate scheduled license",
+					zap.String("license_id", lic.ID.String()),
+					zap.Error(err),
+				)
+				failed++
+				continue
+			}
+			h.logger.Info("Scheduled license started, status updated to active",
+				zap.String("license_id", lic.ID.String()),
+				zap.Time("starts_at", lic.StartsAt.Time),
+			)
+			activated++
+		}
+
+		if len(due) < params.Limit {
+			return activated, nil
+		}
+		params.Offset += failed
+	}
+}
+
 func ptr[T any](v T) *T {
 	return &v
 }
@@ -0,0 +1,191 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/makkenzo/license-service-api/internal/display"
+	"github.com/makkenzo/license-service-api/internal/domain/expirynotice"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/notify"
+	"github.com/makkenzo/license-service-api/internal/templates"
+	"go.uber.org/zap"
+)
+
+// ExpiryNotifyHandler e-mails customers reminders before their active
+// licenses expire and a notice once they have expired. Each notice is
+// claimed in the notice log before it is sent, so it goes out at most once
+// per expiry date; a failed send releases the claim and is retried on the
+// next run. Licenses that opted out are skipped.
+type ExpiryNotifyHandler struct {
+	licenses      license.Repository
+	notices       expirynotice.Repository
+	mailer        notify.Mailer
+	renderer      *templates.Renderer
+	localizer     *display.Localizer
+	reminderDays  []int
+	expiredWindow time.Duration
+	logger        *zap.Logger
+}
+
+// NewExpiryNotifyHandler sends reminders the given numbers of days ahead of
+// expiry and notices for licenses that expired within expiredWindow; a
+// zero window sends no expiry notices.
+func NewExpiryNotifyHandler(licenses license.Repository, notices expirynotice.Repository, mailer notify.Mailer, renderer *templates.Renderer, localizer *display.Localizer, reminderDays []int, expiredWindow time.Duration, logger *zap.Logger) *ExpiryNotifyHandler {
+	days := make([]int, 0, len(reminderDays))
+	for _, d := range reminderDays {
+		if d > 0 {
+			days = append(days, d)
+		}
+	}
+	slices.Sort(days)
+	return &ExpiryNotifyHandler{
+		licenses:      licenses,
+		notices:       notices,
+		mailer:        mailer,
+		renderer:      renderer,
+		localizer:     localizer,
+		reminderDays:  slices.Compact(days),
+		expiredWindow: expiredWindow,
+		logger:        logger.Named("ExpiryNotifyHandler"),
+	}
+}
+
+type expiryNotifyStats struct {
+	sent, failed int
+}
+
+func (h *ExpiryNotifyHandler) ProcessTask(ctx context.Context, t *asynq.Task) error {
+	if t.Type() != TypeExpiryNotify {
+		return fmt.Errorf("unexpected task type: %s", t.Type())
+	}
+
+	h.logger.Info("Processing license expiry notification task...")
+
+	now := time.Now().UTC()
+	var stats expiryNotifyStats
+
+	if len(h.reminderDays) > 0 {
+		until := now.Add(time.Duration(h.reminderDays[len(h.reminderDays)-1]) * 24 * time.Hour)
+		params := license.ListParams{
+			Status:        ptr(license.StatusActive),
+			ExpiresAfter:  &now,
+			ExpiresBefore: &until,
+		}
+		err := h.notify(ctx, params, &stats, func(lic *license.License) *expirynotice.Notice {
+			left := lic.ExpiresAt.Time.Sub(now)
+			for _, d := range h.reminderDays {
+				if left <= time.Duration(d)*24*time.Hour {
+					return &expirynotice.Notice{LicenseID: lic.ID, Kind: expirynotice.KindReminder, DaysBefore: d, ExpiresAt: lic.ExpiresAt.Time}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if h.expiredWindow > 0 {
+		since := now.Add(-h.expiredWindow)
+		params := license.ListParams{
+			Status:        ptr(license.StatusExpired),
+			ExpiresAfter:  &since,
+			ExpiresBefore: &now,
+		}
+		err := h.notify(ctx, params, &stats, func(lic *license.License) *expirynotice.Notice {
+			return &expirynotice.Notice{LicenseID: lic.ID, Kind: expirynotice.KindExpired, ExpiresAt: lic.ExpiresAt.Time}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	h.logger.Info("License expiry notification task finished", zap.Int("sent", stats.sent), zap.Int("failed", stats.failed))
+	return nil
+}
+
+// notify pages through the licenses matching params and sends the notice
+// noticeFor picks for each of them, if any.
+func (h *ExpiryNotifyHandler) notify(ctx context.Context, params license.ListParams, stats *expiryNotifyStats, noticeFor func(*license.License) *expirynotice.Notice) error {
+	params.SortBy = "expires_at"
+	params.SortOrder = "ASC"
+	params.Limit = 500
+
+	for {
+		licenses, _, err := h.licenses.List(ctx, params)
+		if err != nil {
+			h.logger.Error("Failed to list licenses for expiry notifications", zap.Error(err))
+			return fmt.Errorf("repository error listing licenses: %w", err)
+		}
+
+		ids := make([]uuid.UUID, 0, len(licenses))
+		for _, lic := range licenses {
+			ids = append(ids, lic.ID)
+		}
+		optedOut, err := h.notices.OptedOutAmong(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("repository error looking up opt-outs: %w", err)
+		}
+
+		for _, lic := range licenses {
+			if optedOut[lic.ID] || !lic.ExpiresAt.Valid || !lic.CustomerEmail.Valid || lic.CustomerEmail.String == "" {
+				continue
+			}
+			notice := noticeFor(lic)
+			if notice == nil {
+				continue
+			}
+			claimed, err := h.notices.Claim(ctx, notice)
+			if err != nil {
+				return fmt.Errorf("repository error claiming expiry notice: %w", err)
+			}
+			if !claimed {
+				continue
+			}
+			if err := h.send(ctx, lic, notice); err != nil {
+				h.logger.Warn("Failed to send license expiry e-mail", zap.String("license_id", lic.ID.String()), zap.String("kind", string(notice.Kind)), zap.Error(err))
+				if err := h.notices.Release(ctx, notice); err != nil {
+					h.logger.Error("Failed to release expiry notice, it will not be retried", zap.String("license_id", lic.ID.String()), zap.Error(err))
+				}
+				stats.failed++
+				continue
+			}
+			stats.sent++
+		}
+
+		if len(licenses) < params.Limit {
+			return nil
+		}
+		params.Offset += params.Limit
+	}
+}
+
+func (h *ExpiryNotifyHandler) send(ctx context.Context, lic *license.License, notice *expirynotice.Notice) error {
+	data := templates.ExpiryNoticeData{
+		CustomerName: lic.CustomerName.String,
+		ProductName:  lic.ProductName,
+		LicenseKey:   lic.LicenseKey,
+		ExpiresAt:    notice.ExpiresAt,
+		ExpiresOn:    h.localizer.For(lic.Locale(), lic.Timezone()).Date(notice.ExpiresAt),
+		Expired:      notice.Kind == expirynotice.KindExpired,
+	}
+	if !data.Expired {
+		data.DaysLeft = int((time.Until(notice.ExpiresAt) + 24*time.Hour - 1) / (24 * time.Hour))
+	}
+
+	rendered, err := h.renderer.Render(templates.ExpiryNotice, lic.Locale(), data)
+	if err != nil {
+		return fmt.Errorf("failed to render license expiry e-mail: %w", err)
+	}
+	return h.mailer.Send(ctx, notify.Message{
+		To:        lic.CustomerEmail.String,
+		Subject:   rendered.Subject,
+		Body:      rendered.Body,
+		LicenseID: lic.ID,
+	})
+}
@@ -0,0 +1,45 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+type IntegrityChecker interface {
+	RunIntegrityCheck(ctx context.Context, id uuid.UUID) error
+}
+
+type IntegrityCheckHandler struct {
+	checker IntegrityChecker
+	logger  *zap.Logger
+}
+
+func NewIntegrityCheckHandler(checker IntegrityChecker, logger *zap.Logger) *IntegrityCheckHandler {
+	return &IntegrityCheckHandler{
+		checker: checker,
+		logger:  logger.Named("IntegrityCheckHandler"),
+	}
+}
+
+func (h *IntegrityCheckHandler) ProcessTask(ctx context.Context, t *asynq.Task) error {
+	if t.Type() != TypeIntegrityCheck {
+		return fmt.Errorf("unexpected task type: %s", t.Type())
+	}
+
+	var p IntegrityCheckPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		h.logger.Error("Failed to unmarshal payload for integrity check task", zap.Error(err), zap.ByteString("payload", t.Payload()))
+		return fmt.Errorf("invalid payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	if err := h.checker.RunIntegrityCheck(ctx, p.CheckID); err != nil {
+		h.logger.Error("Integrity check failed", zap.String("check_id", p.CheckID.String()), zap.Error(err))
+		return fmt.Errorf("integrity check error: %w", err)
+	}
+	return nil
+}
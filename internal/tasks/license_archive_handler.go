@@ -0,0 +1,41 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+type LicenseArchiver interface {
+	ArchiveLicenses(ctx context.Context) (int, error)
+}
+
+type LicenseArchiveHandler struct {
+	archiver LicenseArchiver
+	logger   *zap.Logger
+}
+
+func NewLicenseArchiveHandler(archiver LicenseArchiver, logger *zap.Logger) *LicenseArchiveHandler {
+	return &LicenseArchiveHandler{
+		archiver: archiver,
+		logger:   logger.Named("LicenseArchiveHandler"),
+	}
+}
+
+// ProcessTask is retried on failure: licenses already archived are no longer
+// candidates, so a new run carries on where the last one stopped.
+func (h *LicenseArchiveHandler) ProcessTask(ctx context.Context, t *asynq.Task) error {
+	if t.Type() != TypeLicenseArchive {
+		return fmt.Errorf("unexpected task type: %s", t.Type())
+	}
+
+	archived, err := h.archiver.ArchiveLicenses(ctx)
+	if err != nil {
+		h.logger.Error("License archive failed", zap.Int("archived", archived), zap.Error(err))
+		return fmt.Errorf("license archive error: %w", err)
+	}
+	h.logger.Info("License archive finished", zap.Int("archived", archived))
+	return nil
+}
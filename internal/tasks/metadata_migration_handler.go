@@ -0,0 +1,49 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/makkenzo/license-service-api/internal/metaschema"
+	"go.uber.org/zap"
+)
+
+type MetadataMigrator interface {
+	ApplyMetadataMigration(ctx context.Context, productName string, mapping metaschema.Mapping) error
+}
+
+type MetadataMigrationHandler struct {
+	migrator MetadataMigrator
+	logger   *zap.Logger
+}
+
+func NewMetadataMigrationHandler(migrator MetadataMigrator, logger *zap.Logger) *MetadataMigrationHandler {
+	return &MetadataMigrationHandler{
+		migrator: migrator,
+		logger:   logger.Named("MetadataMigrationHandler"),
+	}
+}
+
+func (h *MetadataMigrationHandler) ProcessTask(ctx context.Context, t *asynq.Task) error {
+	if t.Type() != TypeMetadataMigration {
+		return fmt.Errorf("unexpected task type: %s", t.Type())
+	}
+
+	var p MetadataMigrationPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		h.logger.Error("Failed to unmarshal payload for metadata migration task", zap.Error(err), zap.ByteString("payload", t.Payload()))
+		return fmt.Errorf("invalid payload: %v: %w", err, asynq.SkipRetry)
+	}
+	if err := p.Operations.Check(); err != nil {
+		h.logger.Error("Metadata migration task carries an invalid mapping", zap.String("product_name", p.ProductName), zap.Error(err))
+		return fmt.Errorf("invalid mapping: %v: %w", err, asynq.SkipRetry)
+	}
+
+	if err := h.migrator.ApplyMetadataMigration(ctx, p.ProductName, p.Operations); err != nil {
+		h.logger.Error("Metadata migration failed", zap.String("product_name", p.ProductName), zap.Error(err))
+		return fmt.Errorf("metadata migration error: %w", err)
+	}
+	return nil
+}
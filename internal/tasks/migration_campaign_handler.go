@@ -0,0 +1,141 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hibiken/asynq"
+	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/domain/product"
+	"github.com/makkenzo/license-service-api/internal/ierr"
+	"github.com/makkenzo/license-service-api/internal/notify"
+	"github.com/makkenzo/license-service-api/internal/templates"
+	"go.uber.org/zap"
+)
+
+// MigrationCampaignHandler e-mails every customer holding a non-revoked
+// license of a deprecated or EOL product about the migration offer. Each
+// address is mailed once per run. Failed sends are logged and skipped rather
+// than retried so that a retry does not mail the same customers twice.
+type MigrationCampaignHandler struct {
+	licenses   license.Repository
+	lifecycles product.Repository
+	mailer     notify.Mailer
+	renderer   *templates.Renderer
+	logger     *zap.Logger
+}
+
+func NewMigrationCampaignHandler(licenses license.Repository, lifecycles product.Repository, mailer notify.Mailer, renderer *templates.Renderer, logger *zap.Logger) *MigrationCampaignHandler {
+	return &MigrationCampaignHandler{
+		licenses:   licenses,
+		lifecycles: lifecycles,
+		mailer:     mailer,
+		renderer:   renderer,
+		logger:     logger.Named("MigrationCampaignHandler"),
+	}
+}
+
+func (h *MigrationCampaignHandler) ProcessTask(ctx context.Context, t *asynq.Task) error {
+	if t.Type() != TypeMigrationCampaign {
+		return fmt.Errorf("unexpected task type: %s", t.Type())
+	}
+
+	var p MigrationCampaignPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		h.logger.Error("Failed to unmarshal payload for migration campaign task", zap.Error(err), zap.ByteString("payload", t.Payload()))
+		return fmt.Errorf("invalid payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	lc, err := h.lifecycles.FindLifecycle(ctx, p.ProductName)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			h.logger.Warn("Product lifecycle disappeared before campaign ran", zap.String("product_name", p.ProductName))
+			return nil
+		}
+		return fmt.Errorf("repository error loading product lifecycle: %w", err)
+	}
+
+	h.logger.Info("Running product migration campaign", zap.String("product_name", p.ProductName), zap.String("state", string(lc.State)))
+
+	params := license.ListParams{
+		ProductName: &p.ProductName,
+		SortBy:      "created_at",
+		SortOrder:   "ASC",
+		Limit:       500,
+	}
+
+	seen := make(map[string]struct{})
+	sent, failed := 0, 0
+	for {
+		licenses, _, err := h.licenses.List(ctx, params)
+		if err != nil {
+			h.logger.Error("Failed to list licenses for migration campaign", zap.String("product_name", p.ProductName), zap.Error(err))
+			return fmt.Errorf("repository error listing licenses: %w", err)
+		}
+
+		for _, lic := range licenses {
+			if lic.Status == license.StatusRevoked || !lic.CustomerEmail.Valid || lic.CustomerEmail.String == "" {
+				continue
+			}
+			email := strings.ToLower(lic.CustomerEmail.String)
+			if _, ok := seen[email]; ok {
+				continue
+			}
+			seen[email] = struct{}{}
+
+			msg, err := h.migrationMessage(lic, lc, p.Message)
+			if err != nil {
+				h.logger.Error("Failed to render migration campaign e-mail", zap.String("license_id", lic.ID.String()), zap.Error(err))
+				failed++
+				continue
+			}
+			if err := h.mailer.Send(ctx, *msg); err != nil {
+				h.logger.Warn("Failed to send migration campaign e-mail", zap.String("license_id", lic.ID.String()), zap.Error(err))
+				failed++
+				continue
+			}
+			sent++
+		}
+
+		if len(licenses) < params.Limit {
+			break
+		}
+		params.Offset += params.Limit
+	}
+
+	h.logger.Info("Product migration campaign finished",
+		zap.String("product_name", p.ProductName),
+		zap.Int("sent", sent),
+		zap.Int("failed", failed),
+	)
+	return nil
+}
+
+func (h *MigrationCampaignHandler) migrationMessage(lic *license.License, lc *product.Lifecycle, extra string) (*notify.Message, error) {
+	data := templates.MigrationCampaignData{
+		CustomerName:   lic.CustomerName.String,
+		ProductName:    lc.ProductName,
+		LicenseKey:     lic.LicenseKey,
+		State:          string(lc.State),
+		EOLDate:        lc.EOLDate,
+		MigrationOffer: lc.MigrationOffer,
+		Message:        extra,
+	}
+	if lc.MigrationProduct != nil {
+		data.MigrationProduct = *lc.MigrationProduct
+	}
+
+	rendered, err := h.renderer.Render(templates.MigrationCampaign, lic.Locale(), data)
+	if err != nil {
+		return nil, err
+	}
+	return &notify.Message{
+		To:        lic.CustomerEmail.String,
+		Subject:   rendered.Subject,
+		Body:      rendered.Body,
+		LicenseID: lic.ID,
+	}, nil
+}
@@ -0,0 +1,44 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+type CacheWarmer interface {
+	Warm(ctx context.Context, topN int) (int, error)
+}
+
+type CachePrewarmHandler struct {
+	warmer CacheWarmer
+	topN   int
+	logger *zap.Logger
+}
+
+func NewCachePrewarmHandler(warmer CacheWarmer, topN int, logger *zap.Logger) *CachePrewarmHandler {
+	return &CachePrewarmHandler{
+		warmer: warmer,
+		topN:   topN,
+		logger: logger.Named("CachePrewarmHandler"),
+	}
+}
+
+func (h *CachePrewarmHandler) ProcessTask(ctx context.Context, t *asynq.Task) error {
+	if t.Type() != TypeLicenseCachePrewarm {
+		return fmt.Errorf("unexpected task type: %s", t.Type())
+	}
+
+	h.logger.Info("Processing license cache pre-warm task...")
+
+	warmed, err := h.warmer.Warm(ctx, h.topN)
+	if err != nil {
+		h.logger.Error("License cache pre-warm failed", zap.Error(err))
+		return fmt.Errorf("cache pre-warm error: %w", err)
+	}
+
+	h.logger.Info("License cache pre-warm task finished", zap.Int("warmed", warmed))
+	return nil
+}
@@ -0,0 +1,39 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+// RegionSender delivers a forwarded write to the primary region. Errors
+// wrapping asynq.SkipRetry are final, e.g. when the primary rejected it.
+type RegionSender interface {
+	Send(ctx context.Context, payload []byte) error
+}
+
+type RegionForwardHandler struct {
+	sender RegionSender
+	logger *zap.Logger
+}
+
+func NewRegionForwardHandler(sender RegionSender, logger *zap.Logger) *RegionForwardHandler {
+	return &RegionForwardHandler{
+		sender: sender,
+		logger: logger.Named("RegionForwardHandler"),
+	}
+}
+
+func (h *RegionForwardHandler) ProcessTask(ctx context.Context, t *asynq.Task) error {
+	if t.Type() != TypeRegionForward {
+		return fmt.Errorf("unexpected task type: %s", t.Type())
+	}
+
+	if err := h.sender.Send(ctx, t.Payload()); err != nil {
+		h.logger.Warn("Forwarding write to primary region failed", zap.ByteString("payload", t.Payload()), zap.Error(err))
+		return fmt.Errorf("region forward error: %w", err)
+	}
+	return nil
+}
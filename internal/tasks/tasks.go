@@ -4,11 +4,26 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
+	"github.com/makkenzo/license-service-api/internal/metaschema"
 )
 
 const (
-	TypeLicenseExpire = "license:expire:check"
+	TypeLicenseExpire        = "license:expire:check"
+	TypeLicenseCachePrewarm  = "license:cache:prewarm"
+	TypeBindingFailureReport = "report:binding_failures"
+	TypeMigrationCampaign    = "product:migration_campaign"
+	TypeTelemetryPing        = "telemetry:ping"
+	TypeRegionForward        = "region:forward"
+	TypeAnalyticsExport      = "analytics:export"
+	TypeMetadataMigration    = "product:metadata_migration"
+	TypeExpiryNotify         = "license:expiry:notify"
+	TypeIntegrityCheck       = "admin:integrity_check"
+	TypeWebhookDeliver       = "webhook:deliver"
+	TypeCampaignPrepare      = "campaign:prepare"
+	TypeCampaignSend         = "campaign:send"
+	TypeLicenseArchive       = "license:archive"
 )
 
 type ExpireLicensePayload struct{}
@@ -25,3 +40,157 @@ func NewLicenseExpireTask(opts ...asynq.Option) (*asynq.Task, error) {
 
 	return asynq.NewTask(TypeLicenseExpire, payloadBytes, allOpts...), nil
 }
+
+func NewLicenseCachePrewarmTask(opts ...asynq.Option) (*asynq.Task, error) {
+	uniqueOpt := asynq.Unique(5 * time.Minute)
+	allOpts := append(opts, uniqueOpt)
+
+	return asynq.NewTask(TypeLicenseCachePrewarm, nil, allOpts...), nil
+}
+
+func NewBindingFailureReportTask(opts ...asynq.Option) (*asynq.Task, error) {
+	uniqueOpt := asynq.Unique(1 * time.Hour)
+	allOpts := append(opts, uniqueOpt)
+
+	return asynq.NewTask(TypeBindingFailureReport, nil, allOpts...), nil
+}
+
+func NewTelemetryPingTask(opts ...asynq.Option) (*asynq.Task, error) {
+	uniqueOpt := asynq.Unique(1 * time.Hour)
+	allOpts := append(opts, uniqueOpt)
+
+	return asynq.NewTask(TypeTelemetryPing, nil, allOpts...), nil
+}
+
+func NewAnalyticsExportTask(opts ...asynq.Option) (*asynq.Task, error) {
+	allOpts := append(opts, asynq.MaxRetry(5), asynq.Unique(1*time.Hour))
+
+	return asynq.NewTask(TypeAnalyticsExport, nil, allOpts...), nil
+}
+
+func NewExpiryNotifyTask(opts ...asynq.Option) (*asynq.Task, error) {
+	uniqueOpt := asynq.Unique(30 * time.Minute)
+	allOpts := append(opts, uniqueOpt)
+
+	return asynq.NewTask(TypeExpiryNotify, nil, allOpts...), nil
+}
+
+func NewLicenseArchiveTask(opts ...asynq.Option) (*asynq.Task, error) {
+	uniqueOpt := asynq.Unique(1 * time.Hour)
+	allOpts := append(opts, uniqueOpt)
+
+	return asynq.NewTask(TypeLicenseArchive, nil, allOpts...), nil
+}
+
+type MigrationCampaignPayload struct {
+	ProductName string `json:"product_name"`
+	Message     string `json:"message,omitempty"`
+}
+
+func NewMigrationCampaignTask(payload MigrationCampaignPayload, opts ...asynq.Option) (*asynq.Task, error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	allOpts := append(opts, asynq.MaxRetry(3), asynq.Unique(10*time.Minute))
+
+	return asynq.NewTask(TypeMigrationCampaign, payloadBytes, allOpts...), nil
+}
+
+type MetadataMigrationPayload struct {
+	ProductName string             `json:"product_name"`
+	Operations  metaschema.Mapping `json:"operations"`
+}
+
+// NewMetadataMigrationTask is retried on failure: a mapping that has been
+// applied once changes nothing when it runs again.
+func NewMetadataMigrationTask(payload MetadataMigrationPayload, opts ...asynq.Option) (*asynq.Task, error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	allOpts := append(opts, asynq.MaxRetry(3), asynq.Unique(10*time.Minute))
+
+	return asynq.NewTask(TypeMetadataMigration, payloadBytes, allOpts...), nil
+}
+
+type IntegrityCheckPayload struct {
+	CheckID uuid.UUID `json:"check_id"`
+}
+
+// NewIntegrityCheckTask is not retried: a check that fails is marked failed
+// and can be started again. It scans every license, so it may run for hours.
+func NewIntegrityCheckTask(payload IntegrityCheckPayload, opts ...asynq.Option) (*asynq.Task, error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	allOpts := append(opts, asynq.MaxRetry(0), asynq.Timeout(6*time.Hour))
+
+	return asynq.NewTask(TypeIntegrityCheck, payloadBytes, allOpts...), nil
+}
+
+type WebhookDeliverPayload struct {
+	DeliveryID uuid.UUID `json:"delivery_id"`
+}
+
+// NewWebhookDeliverTask makes one attempt at a webhook delivery. A failing
+// endpoint is retried with a new task per attempt; asynq only retries
+// attempts that could not be made.
+func NewWebhookDeliverTask(payload WebhookDeliverPayload, opts ...asynq.Option) (*asynq.Task, error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	allOpts := append([]asynq.Option{asynq.MaxRetry(3), asynq.Timeout(time.Minute)}, opts...)
+
+	return asynq.NewTask(TypeWebhookDeliver, payloadBytes, allOpts...), nil
+}
+
+type CampaignPreparePayload struct {
+	CampaignID uuid.UUID `json:"campaign_id"`
+}
+
+// NewCampaignPrepareTask picks the recipients of a campaign and schedules
+// its batches. Picking them again on a retry adds no one twice.
+func NewCampaignPrepareTask(payload CampaignPreparePayload, opts ...asynq.Option) (*asynq.Task, error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	allOpts := append([]asynq.Option{asynq.MaxRetry(3), asynq.Timeout(time.Hour)}, opts...)
+
+	return asynq.NewTask(TypeCampaignPrepare, payloadBytes, allOpts...), nil
+}
+
+type CampaignSendPayload struct {
+	CampaignID uuid.UUID `json:"campaign_id"`
+	Batch      int       `json:"batch"`
+}
+
+// NewCampaignSendTask mails one batch of a campaign. A retry only mails
+// the recipients whose send failed.
+func NewCampaignSendTask(payload CampaignSendPayload, opts ...asynq.Option) (*asynq.Task, error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	allOpts := append([]asynq.Option{asynq.MaxRetry(3), asynq.Timeout(30 * time.Minute)}, opts...)
+
+	return asynq.NewTask(TypeCampaignSend, payloadBytes, allOpts...), nil
+}
+
+// NewRegionForwardTask wraps a write a replica region forwards to the
+// primary. Retries back off up to about a day, so a primary outage of that
+// length loses nothing.
+func NewRegionForwardTask(payload []byte, opts ...asynq.Option) *asynq.Task {
+	allOpts := append([]asynq.Option{asynq.MaxRetry(25)}, opts...)
+
+	return asynq.NewTask(TypeRegionForward, payload, allOpts...)
+}
@@ -0,0 +1,39 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+type TelemetrySender interface {
+	Send(ctx context.Context) error
+}
+
+type TelemetryPingHandler struct {
+	sender TelemetrySender
+	logger *zap.Logger
+}
+
+func NewTelemetryPingHandler(sender TelemetrySender, logger *zap.Logger) *TelemetryPingHandler {
+	return &TelemetryPingHandler{
+		sender: sender,
+		logger: logger.Named("TelemetryPingHandler"),
+	}
+}
+
+// ProcessTask does not retry on failure: a missed ping is harmless and the
+// next scheduled one will carry current numbers anyway.
+func (h *TelemetryPingHandler) ProcessTask(ctx context.Context, t *asynq.Task) error {
+	if t.Type() != TypeTelemetryPing {
+		return fmt.Errorf("unexpected task type: %s", t.Type())
+	}
+
+	if err := h.sender.Send(ctx); err != nil {
+		h.logger.Warn("Telemetry ping failed", zap.Error(err))
+		return fmt.Errorf("telemetry ping error: %w: %w", err, asynq.SkipRetry)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+// WebhookDeliverer makes one attempt at a webhook delivery and schedules
+// the next one itself when it fails. An error means the attempt could not
+// be made or recorded, and asynq retries it.
+type WebhookDeliverer interface {
+	Deliver(ctx context.Context, id uuid.UUID) error
+}
+
+type WebhookDeliverHandler struct {
+	deliverer WebhookDeliverer
+	logger    *zap.Logger
+}
+
+func NewWebhookDeliverHandler(deliverer WebhookDeliverer, logger *zap.Logger) *WebhookDeliverHandler {
+	return &WebhookDeliverHandler{
+		deliverer: deliverer,
+		logger:    logger.Named("WebhookDeliverHandler"),
+	}
+}
+
+func (h *WebhookDeliverHandler) ProcessTask(ctx context.Context, t *asynq.Task) error {
+	if t.Type() != TypeWebhookDeliver {
+		return fmt.Errorf("unexpected task type: %s", t.Type())
+	}
+
+	var p WebhookDeliverPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		h.logger.Error("Failed to unmarshal payload for webhook delivery task", zap.Error(err), zap.ByteString("payload", t.Payload()))
+		return fmt.Errorf("invalid payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	if err := h.deliverer.Deliver(ctx, p.DeliveryID); err != nil {
+		h.logger.Warn("Webhook delivery failed", zap.String("delivery_id", p.DeliveryID.String()), zap.Error(err))
+		return fmt.Errorf("webhook delivery error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package templates
+
+import (
+	"reflect"
+	"time"
+)
+
+type CertificateData struct {
+	CustomerName  string     `doc:"Customer name, empty when the license has none"`
+	CustomerEmail string     `doc:"Customer e-mail, empty when the license has none"`
+	ProductName   string     `doc:"Licensed product"`
+	LicenseKey    string     `doc:"License key"`
+	LicenseType   string     `doc:"License type, e.g. trial or subscription"`
+	Status        string     `doc:"Current license status"`
+	IssuedAt      *time.Time `doc:"Issue date, nil when unknown; format with {{date .IssuedAt}}"`
+	ExpiresAt     *time.Time `doc:"Expiry date, nil for perpetual licenses; format with {{date .ExpiresAt}}"`
+	GeneratedAt   time.Time  `doc:"When the certificate was generated"`
+}
+
+type RenewalOfferData struct {
+	CustomerName  string    `doc:"Customer name, empty when the license has none"`
+	ProductName   string    `doc:"Licensed product"`
+	ExtendDays    int       `doc:"Days the license is extended by when the offer is accepted"`
+	URL           string    `doc:"Signed self-service link to accept the offer"`
+	LinkExpiresAt time.Time `doc:"When the link stops working"`
+}
+
+type MigrationCampaignData struct {
+	CustomerName     string     `doc:"Customer name, empty when the license has none"`
+	ProductName      string     `doc:"Product being retired"`
+	LicenseKey       string     `doc:"Customer's license key for the retired product"`
+	State            string     `doc:"Lifecycle state of the product: deprecated or eol"`
+	EOLDate          *time.Time `doc:"End of life date, nil when not scheduled"`
+	MigrationProduct string     `doc:"Recommended replacement product, may be empty"`
+	MigrationOffer   string     `doc:"Offer text configured on the product lifecycle, may be empty"`
+	Message          string     `doc:"Extra message passed when the campaign was started, may be empty"`
+}
+
+type LicenseEventData struct {
+	CustomerName string     `doc:"Customer name, empty when the license has none"`
+	ProductName  string     `doc:"Licensed product"`
+	LicenseKey   string     `doc:"License key"`
+	Event        string     `doc:"What happened: created, activated, expired or revoked"`
+	ExpiresAt    *time.Time `doc:"Expiry date, nil for perpetual licenses; format with {{date .ExpiresAt}}"`
+	ExpiresOn    string     `doc:"Expiry date in the customer's locale and time zone as the API displays it, empty for perpetual licenses"`
+}
+
+type ExpiryNoticeData struct {
+	CustomerName string    `doc:"Customer name, empty when the license has none"`
+	ProductName  string    `doc:"Licensed product"`
+	LicenseKey   string    `doc:"License key"`
+	ExpiresAt    time.Time `doc:"Expiry date; format with {{date .ExpiresAt}}"`
+	ExpiresOn    string    `doc:"Expiry date in the customer's locale and time zone as the API displays it"`
+	DaysLeft     int       `doc:"Whole days until expiry, 0 once the license has expired"`
+	Expired      bool      `doc:"Whether the license has already expired"`
+}
+
+type CampaignData struct {
+	CustomerName   string `doc:"Customer name, empty when the license has none"`
+	ProductName    string `doc:"Product of the license the recipient was picked for"`
+	Subject        string `doc:"Subject written for the campaign"`
+	Message        string `doc:"Message written for the campaign"`
+	UnsubscribeURL string `doc:"Signed link that stops all future campaigns to the address"`
+}
+
+type Variable struct {
+	Name        string `json:"name"`
+	Placeholder string `json:"placeholder"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+type TemplateInfo struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Locales     []string   `json:"locales"`
+	Variables   []Variable `json:"variables"`
+}
+
+var catalog = []struct {
+	name        string
+	description string
+	data        interface{}
+}{
+	{Certificate, "License certificate issued to the customer", CertificateData{}},
+	{RenewalOffer, "E-mail carrying a self-service renewal link", RenewalOfferData{}},
+	{MigrationCampaign, "E-mail sent to customers of a deprecated or EOL product", MigrationCampaignData{}},
+	{LicenseEvent, "E-mail telling the customer a license was created, activated, expired or revoked", LicenseEventData{}},
+	{ExpiryNotice, "Reminder e-mailed before a license expires and notice sent once it has expired", ExpiryNoticeData{}},
+	{Campaign, "E-mail of a bulk campaign to license holders, with an unsubscribe link", CampaignData{}},
+}
+
+// Catalog describes every template and the placeholders it can use. It is
+// generated from the data structs passed to Render so it cannot drift.
+func (r *Renderer) Catalog() []TemplateInfo {
+	infos := make([]TemplateInfo, 0, len(catalog))
+	for _, entry := range catalog {
+		infos = append(infos, TemplateInfo{
+			Name:        entry.name,
+			Description: entry.description,
+			Locales:     r.Locales(entry.name),
+			Variables:   variablesOf(entry.data),
+		})
+	}
+	return infos
+}
+
+func variablesOf(data interface{}) []Variable {
+	t := reflect.TypeOf(data)
+	vars := make([]Variable, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		typeName := f.Type.String()
+		switch f.Type {
+		case reflect.TypeOf(time.Time{}), reflect.TypeOf(&time.Time{}):
+			typeName = "date"
+		}
+		vars = append(vars, Variable{
+			Name:        f.Name,
+			Placeholder: "{{." + f.Name + "}}",
+			Type:        typeName,
+			Description: f.Tag.Get("doc"),
+		})
+	}
+	return vars
+}
@@ -0,0 +1,168 @@
+// Package templates renders customer-facing documents and e-mails from
+// per-locale templates embedded in the binary.
+//
+// Templates live in files/<name>/<locale>.tmpl and define a "subject" and a
+// "body" block. A locale such as "pt-BR" falls back to "pt", then to the
+// configured default locale and finally to "en".
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/makkenzo/license-service-api/internal/ierr"
+)
+
+//go:embed files
+var files embed.FS
+
+const (
+	Certificate       = "certificate"
+	RenewalOffer      = "renewal_offer"
+	MigrationCampaign = "migration_campaign"
+	LicenseEvent      = "license_event"
+	ExpiryNotice      = "expiry_notice"
+	Campaign          = "campaign"
+
+	fallbackLocale = "en"
+)
+
+var ErrUnknownTemplate = fmt.Errorf("%w: unknown template", ierr.ErrNotFound)
+
+type Rendered struct {
+	Locale  string
+	Subject string
+	Body    string
+}
+
+type Renderer struct {
+	templates     map[string]map[string]*template.Template
+	defaultLocale string
+}
+
+var funcs = template.FuncMap{
+	"date": func(t time.Time) string { return t.UTC().Format("2006-01-02") },
+}
+
+func NewRenderer(defaultLocale string) (*Renderer, error) {
+	r := &Renderer{
+		templates:     make(map[string]map[string]*template.Template),
+		defaultLocale: NormalizeLocale(defaultLocale),
+	}
+
+	err := fs.WalkDir(files, "files", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != ".tmpl" {
+			return err
+		}
+		name := path.Base(path.Dir(p))
+		locale := NormalizeLocale(strings.TrimSuffix(path.Base(p), ".tmpl"))
+
+		tmpl, err := template.New(path.Base(p)).Funcs(funcs).Option("missingkey=error").ParseFS(files, p)
+		if err != nil {
+			return fmt.Errorf("failed to parse template %s: %w", p, err)
+		}
+		for _, block := range []string{"subject", "body"} {
+			if tmpl.Lookup(block) == nil {
+				return fmt.Errorf("template %s does not define %q", p, block)
+			}
+		}
+
+		if r.templates[name] == nil {
+			r.templates[name] = make(map[string]*template.Template)
+		}
+		r.templates[name][locale] = tmpl
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for name, locales := range r.templates {
+		if _, ok := locales[fallbackLocale]; !ok {
+			return nil, fmt.Errorf("template %s has no %q version to fall back to", name, fallbackLocale)
+		}
+	}
+	return r, nil
+}
+
+// Render executes template name for the first locale in the fallback chain of
+// locale that has a version of it.
+func (r *Renderer) Render(name, locale string, data interface{}) (*Rendered, error) {
+	versions, ok := r.templates[name]
+	if !ok {
+		return nil, ErrUnknownTemplate
+	}
+
+	for _, candidate := range r.Chain(locale) {
+		tmpl, ok := versions[candidate]
+		if !ok {
+			continue
+		}
+
+		var subject, body bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+			return nil, fmt.Errorf("failed to render %s/%s subject: %w", name, candidate, err)
+		}
+		if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+			return nil, fmt.Errorf("failed to render %s/%s body: %w", name, candidate, err)
+		}
+		return &Rendered{
+			Locale:  candidate,
+			Subject: strings.TrimSpace(subject.String()),
+			Body:    strings.TrimLeft(body.String(), "\n"),
+		}, nil
+	}
+
+	return nil, errors.New("no template version found in fallback chain")
+}
+
+// Chain lists the locales tried for locale, most specific first.
+func (r *Renderer) Chain(locale string) []string {
+	chain := make([]string, 0, 4)
+	add := func(l string) {
+		if l == "" {
+			return
+		}
+		for _, existing := range chain {
+			if existing == l {
+				return
+			}
+		}
+		chain = append(chain, l)
+	}
+
+	locale = NormalizeLocale(locale)
+	add(locale)
+	if base, _, found := strings.Cut(locale, "-"); found {
+		add(base)
+	}
+	add(r.defaultLocale)
+	if base, _, found := strings.Cut(r.defaultLocale, "-"); found {
+		add(base)
+	}
+	add(fallbackLocale)
+	return chain
+}
+
+// Locales returns the locales template name is available in.
+func (r *Renderer) Locales(name string) []string {
+	locales := make([]string, 0, len(r.templates[name]))
+	for locale := range r.templates[name] {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// NormalizeLocale turns "pt_BR" or "PT-br" into "pt-br".
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
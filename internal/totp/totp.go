@@ -0,0 +1,85 @@
+// Package totp implements time-based one-time passwords (RFC 6238) with the
+// parameters authenticator apps default to: HMAC-SHA1, six digits and a
+// 30 second step.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+const (
+	Digits = 6
+	Period = 30 * time.Second
+	// secretSize is the key length RFC 4226 recommends for HMAC-SHA1.
+	secretSize = 20
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewSecret reads a new secret from rand.
+func NewSecret(rand io.Reader) ([]byte, error) {
+	secret := make([]byte, secretSize)
+	if _, err := io.ReadFull(rand, secret); err != nil {
+		return nil, fmt.Errorf("generating TOTP secret: %w", err)
+	}
+	return secret, nil
+}
+
+// EncodeSecret returns secret in the base32 form users type into
+// authenticator apps.
+func EncodeSecret(secret []byte) string {
+	return encoding.EncodeToString(secret)
+}
+
+// URI returns the otpauth URI authenticator apps read from QR codes.
+func URI(issuer, account string, secret []byte) string {
+	params := url.Values{}
+	params.Set("secret", EncodeSecret(secret))
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(Digits))
+	params.Set("period", fmt.Sprint(int(Period.Seconds())))
+	label := url.PathEscape(issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// Counter returns the time step t falls in.
+func Counter(t time.Time) uint64 {
+	return uint64(t.Unix() / int64(Period.Seconds()))
+}
+
+// Code returns the code of secret for the time step counter.
+func Code(secret []byte, counter uint64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", Digits, value%1_000_000)
+}
+
+// Verify reports whether code is valid for secret at now and returns the
+// time step it belongs to. One step of clock drift either way is allowed.
+func Verify(secret []byte, code string, now time.Time) (uint64, bool) {
+	if len(code) != Digits {
+		return 0, false
+	}
+	current := Counter(now)
+	for _, counter := range []uint64{current - 1, current, current + 1} {
+		if subtle.ConstantTimeCompare([]byte(Code(secret, counter)), []byte(code)) == 1 {
+			return counter, true
+		}
+	}
+	return 0, false
+}
@@ -1,27 +1,27 @@
 package util
 
 import (
-	"crypto/rand"
-	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
+	"io"
 	"strings"
 
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
 	"github.com/makkenzo/license-service-api/internal/domain/apikey"
 )
 
-func generateRandomBytes(n int) ([]byte, error) {
+func generateRandomBytes(r io.Reader, n int) ([]byte, error) {
 	b := make([]byte, n)
-	_, err := rand.Read(b)
+	_, err := io.ReadFull(r, b)
 	if err != nil {
 		return nil, err
 	}
 	return b, nil
 }
 
-func generateRandomString(length int) (string, error) {
+func generateRandomString(r io.Reader, length int) (string, error) {
 	byteLength := (length*3 + 3) / 4
-	b, err := generateRandomBytes(byteLength)
+	b, err := generateRandomBytes(r, byteLength)
 	if err != nil {
 		return "", err
 	}
@@ -36,26 +36,23 @@ func generateRandomString(length int) (string, error) {
 	return str, nil
 }
 
-func GenerateAPIKey() (fullKey string, prefix string, keyHash string, err error) {
-	prefix, err = generateRandomString(apikey.APIKeyPrefixLength)
+func GenerateAPIKey(crypto cryptoprovider.Provider) (fullKey string, prefix string, keyHash string, err error) {
+	prefix, err = generateRandomString(crypto.Rand(), apikey.APIKeyPrefixLength)
 	if err != nil {
 		return "", "", "", fmt.Errorf("failed to generate prefix: %w", err)
 	}
 
-	secret, err := generateRandomString(apikey.APIKeySecretLength)
+	secret, err := generateRandomString(crypto.Rand(), apikey.APIKeySecretLength)
 	if err != nil {
 		return "", "", "", fmt.Errorf("failed to generate secret: %w", err)
 	}
 
 	fullKey = fmt.Sprintf(apikey.APIKeyFormat, prefix, secret)
-
-	hashBytes := sha256.Sum256([]byte(fullKey))
-	keyHash = fmt.Sprintf("%x", hashBytes)
+	keyHash = HashAPIKey(crypto, fullKey)
 
 	return fullKey, prefix, keyHash, nil
 }
 
-func HashAPIKey(fullKey string) string {
-	hashBytes := sha256.Sum256([]byte(fullKey))
-	return fmt.Sprintf("%x", hashBytes)
+func HashAPIKey(crypto cryptoprovider.Provider, fullKey string) string {
+	return fmt.Sprintf("%x", crypto.Hash([]byte(fullKey)))
 }
@@ -0,0 +1,58 @@
+package util
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+)
+
+var (
+	ErrRenewalTokenMalformed = errors.New("malformed renewal token")
+	ErrRenewalTokenSignature = errors.New("renewal token signature mismatch")
+	ErrRenewalTokenExpired   = errors.New("renewal token expired")
+)
+
+// SignRenewalToken returns "<payload>.<signature>" where the payload carries
+// the offer ID and the link expiry and the signature is HMAC-SHA256 over it.
+func SignRenewalToken(crypto cryptoprovider.Provider, secret []byte, offerID uuid.UUID, expiresAt time.Time) string {
+	payload := make([]byte, 24)
+	copy(payload, offerID[:])
+	binary.BigEndian.PutUint64(payload[16:], uint64(expiresAt.Unix()))
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(crypto.MAC(secret, []byte(encoded)))
+}
+
+func VerifyRenewalToken(crypto cryptoprovider.Provider, secret []byte, token string, now time.Time) (uuid.UUID, time.Time, error) {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.Nil, time.Time{}, ErrRenewalTokenMalformed
+	}
+	gotMAC, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return uuid.Nil, time.Time{}, ErrRenewalTokenMalformed
+	}
+	if !hmac.Equal(gotMAC, crypto.MAC(secret, []byte(encoded))) {
+		return uuid.Nil, time.Time{}, ErrRenewalTokenSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(payload) != 24 {
+		return uuid.Nil, time.Time{}, ErrRenewalTokenMalformed
+	}
+	offerID, err := uuid.FromBytes(payload[:16])
+	if err != nil {
+		return uuid.Nil, time.Time{}, ErrRenewalTokenMalformed
+	}
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload[16:])), 0).UTC()
+	if !now.Before(expiresAt) {
+		return offerID, expiresAt, ErrRenewalTokenExpired
+	}
+	return offerID, expiresAt, nil
+}
@@ -0,0 +1,41 @@
+package util
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/makkenzo/license-service-api/internal/cryptoprovider"
+)
+
+var ErrUnsubscribeTokenInvalid = errors.New("invalid unsubscribe token")
+
+// SignUnsubscribeToken returns "<recipient ID>.<signature>", both base64url
+// encoded, where the signature is HMAC-SHA256 over the encoded ID. The
+// token does not expire: an unsubscribe link keeps working.
+func SignUnsubscribeToken(crypto cryptoprovider.Provider, secret []byte, recipientID uuid.UUID) string {
+	encoded := base64.RawURLEncoding.EncodeToString(recipientID[:])
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(crypto.MAC(secret, []byte(encoded)))
+}
+
+func VerifyUnsubscribeToken(crypto cryptoprovider.Provider, secret []byte, token string) (uuid.UUID, error) {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.Nil, ErrUnsubscribeTokenInvalid
+	}
+	gotMAC, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(gotMAC, crypto.MAC(secret, []byte(encoded))) {
+		return uuid.Nil, ErrUnsubscribeTokenInvalid
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return uuid.Nil, ErrUnsubscribeTokenInvalid
+	}
+	id, err := uuid.FromBytes(raw)
+	if err != nil {
+		return uuid.Nil, ErrUnsubscribeTokenInvalid
+	}
+	return id, nil
+}
@@ -8,12 +8,22 @@ import (
 	"github.com/hibiken/asynq"
 	"github.com/makkenzo/license-service-api/internal/config"
 	"github.com/makkenzo/license-service-api/internal/domain/license"
+	"github.com/makkenzo/license-service-api/internal/safego"
 	"github.com/makkenzo/license-service-api/internal/tasks"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
 
-func RunWorkers(ctx context.Context, cfg *config.Config, repo license.Repository, logger *zap.Logger) error {
+// Job describes an additional task handler served by the Asynq server. Jobs
+// with a Schedule are also registered as periodic tasks on the scheduler.
+type Job struct {
+	TaskType string
+	Handler  asynq.Handler
+	Schedule string
+	NewTask  func() (*asynq.Task, error)
+}
+
+func RunWorkers(ctx context.Context, cfg *config.Config, repo license.Repository, logger *zap.Logger, jobs ...Job) error {
 	redisConnOpts := asynq.RedisClientOpt{
 		Addr:     cfg.Redis.Addr,
 		Password: cfg.Redis.Password,
@@ -42,6 +52,9 @@ func RunWorkers(ctx context.Context, cfg *config.Config, repo license.Repository
 	mux := asynq.NewServeMux()
 	expireHandler := tasks.NewLicenseExpireHandler(repo, logger)
 	mux.HandleFunc(tasks.TypeLicenseExpire, expireHandler.ProcessTask)
+	for _, job := range jobs {
+		mux.Handle(job.TaskType, job.Handler)
+	}
 
 	scheduler := asynq.NewScheduler(
 		redisConnOpts,
@@ -50,15 +63,34 @@ func RunWorkers(ctx context.Context, cfg *config.Config, repo license.Repository
 		},
 	)
 
-	licenseExpireTask, err := tasks.NewLicenseExpireTask()
-	if err != nil {
-		return fmt.Errorf("scheduler task creation error: %w", err)
+	// Replicas serve expired licenses as lapsed already; the primary's
+	// check flips the status and the change replicates back.
+	if !cfg.Region.Replica() {
+		licenseExpireTask, err := tasks.NewLicenseExpireTask()
+		if err != nil {
+			return fmt.Errorf("scheduler task creation error: %w", err)
+		}
+		entryID, err := scheduler.Register("@every 1h", licenseExpireTask)
+		if err != nil {
+			return fmt.Errorf("scheduler registration error: %w", err)
+		}
+		logger.Info("Registered periodic license expiration check", zap.String("entry_id", entryID), zap.String("schedule", "@every 1h"))
 	}
-	entryID, err := scheduler.Register("@every 1h", licenseExpireTask)
-	if err != nil {
-		return fmt.Errorf("scheduler registration error: %w", err)
+
+	for _, job := range jobs {
+		if job.Schedule == "" || job.NewTask == nil {
+			continue
+		}
+		task, err := job.NewTask()
+		if err != nil {
+			return fmt.Errorf("scheduler task creation error for %s: %w", job.TaskType, err)
+		}
+		jobEntryID, err := scheduler.Register(job.Schedule, task)
+		if err != nil {
+			return fmt.Errorf("scheduler registration error for %s: %w", job.TaskType, err)
+		}
+		logger.Info("Registered periodic task", zap.String("task_type", job.TaskType), zap.String("entry_id", jobEntryID), zap.String("schedule", job.Schedule))
 	}
-	logger.Info("Registered periodic license expiration check", zap.String("entry_id", entryID), zap.String("schedule", "@every 1h"))
 
 	g, workerCtx := errgroup.WithContext(ctx)
 
@@ -84,7 +116,7 @@ func RunWorkers(ctx context.Context, cfg *config.Config, repo license.Repository
 		return nil
 	})
 
-	go func() {
+	safego.Go(logger, "asynq_shutdown", func() {
 		<-workerCtx.Done()
 		logScheduler.Info("Shutdown signal received by worker, initiating Asynq shutdown...")
 
@@ -93,7 +125,7 @@ func RunWorkers(ctx context.Context, cfg *config.Config, repo license.Repository
 
 		srv.Shutdown()
 		logServer.Info("Asynq Server shutdown initiated.")
-	}()
+	})
 
 	logger.Info("Asynq workers running...")
 
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS license_outbox ON licenses;
+DROP FUNCTION IF EXISTS trigger_license_outbox();
+DROP TABLE IF EXISTS outbox_cursors;
+DROP INDEX IF EXISTS idx_license_outbox_created_at;
+DROP INDEX IF EXISTS idx_license_outbox_license_id;
+DROP TABLE IF EXISTS license_outbox;
@@ -0,0 +1,54 @@
+CREATE TABLE IF NOT EXISTS license_outbox (
+    id             BIGSERIAL PRIMARY KEY,
+    license_id     UUID NOT NULL,
+    operation      VARCHAR(10) NOT NULL,
+    changed_fields TEXT[] NOT NULL DEFAULT '{}',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE license_outbox IS 'Row-level change events for licenses, written by trigger in the same transaction as the change';
+COMMENT ON COLUMN license_outbox.operation IS 'insert, update or delete';
+COMMENT ON COLUMN license_outbox.changed_fields IS 'Columns whose value changed (all columns for insert/delete)';
+
+CREATE INDEX IF NOT EXISTS idx_license_outbox_license_id ON license_outbox (license_id);
+CREATE INDEX IF NOT EXISTS idx_license_outbox_created_at ON license_outbox (created_at);
+
+CREATE TABLE IF NOT EXISTS outbox_cursors (
+    consumer   VARCHAR(100) PRIMARY KEY,
+    last_id    BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE outbox_cursors IS 'Last license_outbox id processed by each consumer';
+
+CREATE OR REPLACE FUNCTION trigger_license_outbox()
+RETURNS TRIGGER AS $$
+DECLARE
+  changed TEXT[];
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    SELECT array_agg(key ORDER BY key) INTO changed FROM jsonb_object_keys(to_jsonb(NEW)) AS key;
+    INSERT INTO license_outbox (license_id, operation, changed_fields) VALUES (NEW.id, 'insert', changed);
+    RETURN NEW;
+  ELSIF TG_OP = 'DELETE' THEN
+    SELECT array_agg(key ORDER BY key) INTO changed FROM jsonb_object_keys(to_jsonb(OLD)) AS key;
+    INSERT INTO license_outbox (license_id, operation, changed_fields) VALUES (OLD.id, 'delete', changed);
+    RETURN OLD;
+  END IF;
+
+  SELECT array_agg(n.key ORDER BY n.key) INTO changed
+  FROM jsonb_each(to_jsonb(NEW)) AS n
+  JOIN jsonb_each(to_jsonb(OLD)) AS o USING (key)
+  WHERE n.value IS DISTINCT FROM o.value AND n.key <> 'updated_at';
+
+  IF changed IS NOT NULL THEN
+    INSERT INTO license_outbox (license_id, operation, changed_fields) VALUES (NEW.id, 'update', changed);
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER license_outbox
+AFTER INSERT OR UPDATE OR DELETE ON licenses
+FOR EACH ROW
+EXECUTE FUNCTION trigger_license_outbox();
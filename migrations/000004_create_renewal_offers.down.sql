@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_renewal_offers_license_id;
+DROP TABLE IF EXISTS renewal_offers;
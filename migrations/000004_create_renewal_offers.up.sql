@@ -0,0 +1,16 @@
+-- license_id has no foreign key: with sharding the license lives in a shard
+-- database, while renewal offers stay in the primary one.
+CREATE TABLE IF NOT EXISTS renewal_offers (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    license_id          UUID NOT NULL,
+    extend_days         INTEGER NOT NULL CHECK (extend_days > 0),
+    link_expires_at     TIMESTAMPTZ NOT NULL,
+    created_by          TEXT NOT NULL DEFAULT '',
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    accepted_at         TIMESTAMPTZ,
+    accepted_ip         TEXT,
+    accepted_user_agent TEXT,
+    consent_text        TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_renewal_offers_license_id ON renewal_offers (license_id);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS product_lifecycles (
+    product_name      VARCHAR(255) PRIMARY KEY,
+    state             VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (state IN ('active', 'deprecated', 'eol')),
+    eol_date          TIMESTAMPTZ,
+    eol_behavior      VARCHAR(10) NOT NULL DEFAULT 'warn' CHECK (eol_behavior IN ('warn', 'deny')),
+    migration_product VARCHAR(255),
+    migration_offer   TEXT NOT NULL DEFAULT '',
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
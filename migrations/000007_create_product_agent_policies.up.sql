@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS product_agent_policies (
+    product_name VARCHAR(255) PRIMARY KEY,
+    min_version  VARCHAR(64) NOT NULL,
+    enforcement  VARCHAR(10) NOT NULL DEFAULT 'warn' CHECK (enforcement IN ('warn', 'deny')),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,15 @@
+-- license_id has no foreign key: with sharding the license lives in a shard
+-- database, while activations stay in the primary one.
+CREATE TABLE IF NOT EXISTS license_activations (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    license_id     UUID NOT NULL,
+    device_id      VARCHAR(255) NOT NULL,
+    ip             TEXT NOT NULL DEFAULT '',
+    user_agent     TEXT NOT NULL DEFAULT '',
+    activated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deactivated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_license_activations_active_device
+    ON license_activations (license_id, device_id)
+    WHERE deactivated_at IS NULL;
@@ -0,0 +1 @@
+ALTER TABLE licenses DROP COLUMN IF EXISTS max_activations;
@@ -0,0 +1,3 @@
+ALTER TABLE licenses
+    ADD COLUMN IF NOT EXISTS max_activations INTEGER NOT NULL DEFAULT 1
+        CHECK (max_activations >= 1);
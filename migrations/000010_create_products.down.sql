@@ -0,0 +1,5 @@
+ALTER TABLE api_keys DROP CONSTRAINT IF EXISTS fk_api_keys_product;
+ALTER TABLE licenses DROP CONSTRAINT IF EXISTS fk_licenses_product;
+DROP INDEX IF EXISTS idx_licenses_product_id;
+ALTER TABLE licenses DROP COLUMN IF EXISTS product_id;
+DROP TABLE IF EXISTS products;
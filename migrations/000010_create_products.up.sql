@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS products (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name         VARCHAR(100) NOT NULL UNIQUE,
+    display_name VARCHAR(255) NOT NULL DEFAULT '',
+    description  TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (id, name)
+);
+
+COMMENT ON COLUMN products.name IS 'Stable product identifier used by agents, e.g. in license validation requests';
+
+CREATE TRIGGER set_timestamp
+BEFORE UPDATE ON products
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+-- Every product name already used by a license becomes a product.
+INSERT INTO products (name)
+SELECT DISTINCT product_name FROM licenses
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE licenses ADD COLUMN IF NOT EXISTS product_id UUID;
+
+UPDATE licenses l SET product_id = p.id
+FROM products p
+WHERE p.name = l.product_name AND l.product_id IS NULL;
+
+ALTER TABLE licenses ALTER COLUMN product_id SET NOT NULL;
+
+-- The composite key keeps licenses.product_name equal to the product's name,
+-- including when a product is renamed.
+ALTER TABLE licenses
+    ADD CONSTRAINT fk_licenses_product
+        FOREIGN KEY (product_id, product_name) REFERENCES products (id, name)
+        ON UPDATE CASCADE ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_licenses_product_id ON licenses (product_id);
+
+-- API keys pointing at products that never existed are detached.
+UPDATE api_keys SET product_id = NULL
+WHERE product_id IS NOT NULL AND product_id NOT IN (SELECT id FROM products);
+
+ALTER TABLE api_keys
+    ADD CONSTRAINT fk_api_keys_product
+        FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE RESTRICT;
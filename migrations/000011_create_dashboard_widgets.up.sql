@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS dashboard_widgets (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id     VARCHAR(255) NOT NULL,
+    kind       VARCHAR(32) NOT NULL CHECK (kind IN ('summary', 'timeseries')),
+    title      VARCHAR(200) NOT NULL DEFAULT '',
+    position   INTEGER NOT NULL DEFAULT 0,
+    params     JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_dashboard_widgets_org_id ON dashboard_widgets (org_id, position);
+
+CREATE TRIGGER set_timestamp
+BEFORE UPDATE ON dashboard_widgets
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
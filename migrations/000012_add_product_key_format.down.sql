@@ -0,0 +1,3 @@
+ALTER TABLE products
+    DROP COLUMN IF EXISTS key_prefix,
+    DROP COLUMN IF EXISTS key_format;
@@ -0,0 +1,5 @@
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS key_format VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS key_prefix VARCHAR(16) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN products.key_format IS 'Pattern for new license keys: X random character, 9 random digit, C check character, anything else literal. Empty means UUID keys';
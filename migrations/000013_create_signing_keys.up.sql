@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS signing_keys (
+    kid        VARCHAR(64) PRIMARY KEY,
+    algorithm  VARCHAR(16) NOT NULL,
+    key_ref    TEXT NOT NULL,
+    public_key BYTEA NOT NULL,
+    status     VARCHAR(16) NOT NULL CHECK (status IN ('active', 'retired')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    retired_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_signing_keys_single_active ON signing_keys (status) WHERE status = 'active';
@@ -0,0 +1 @@
+ALTER TABLE licenses DROP COLUMN IF EXISTS grace_period_days;
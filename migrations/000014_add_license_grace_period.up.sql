@@ -0,0 +1,3 @@
+ALTER TABLE licenses
+    ADD COLUMN IF NOT EXISTS grace_period_days INTEGER NOT NULL DEFAULT 0
+        CHECK (grace_period_days >= 0);
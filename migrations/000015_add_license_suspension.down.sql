@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_license_status_history_license;
+DROP TABLE IF EXISTS license_status_history;
+
+-- PostgreSQL cannot drop an enum value, so 'suspended' stays in the type;
+-- suspended licenses are moved to inactive instead.
+UPDATE licenses SET status = 'inactive' WHERE status = 'suspended';
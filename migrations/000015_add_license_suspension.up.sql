@@ -0,0 +1,15 @@
+ALTER TYPE license_status ADD VALUE IF NOT EXISTS 'suspended';
+
+-- license_id has no foreign key: with sharding the license lives in a shard
+-- database, while the history stays in the primary one.
+CREATE TABLE IF NOT EXISTS license_status_history (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    license_id  UUID NOT NULL,
+    from_status license_status NOT NULL,
+    to_status   license_status NOT NULL,
+    reason      TEXT NOT NULL DEFAULT '',
+    changed_by  TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_license_status_history_license ON license_status_history (license_id, created_at DESC);
@@ -0,0 +1,4 @@
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS customer_license_caps JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+COMMENT ON COLUMN products.customer_license_caps IS 'Maximum non-revoked licenses one customer e-mail may hold, by license type; "*" caps all types together';
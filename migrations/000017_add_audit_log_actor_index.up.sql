@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log (actor_type, actor_id, created_at DESC);
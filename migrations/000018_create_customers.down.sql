@@ -0,0 +1,4 @@
+ALTER TABLE licenses DROP CONSTRAINT IF EXISTS fk_licenses_customer;
+DROP INDEX IF EXISTS idx_licenses_customer_id;
+ALTER TABLE licenses DROP COLUMN IF EXISTS customer_id;
+DROP TABLE IF EXISTS customers;
@@ -0,0 +1,47 @@
+CREATE TABLE IF NOT EXISTS customers (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email      VARCHAR(255) NOT NULL,
+    name       VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON COLUMN customers.email IS 'Identifies the customer, unique regardless of case';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_email ON customers (lower(email));
+CREATE INDEX IF NOT EXISTS idx_customers_name ON customers (lower(name));
+
+CREATE TRIGGER set_timestamp
+BEFORE UPDATE ON customers
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+-- Every e-mail already used by a license becomes a customer, named after
+-- its most recent license that has a name.
+INSERT INTO customers (email, name)
+SELECT DISTINCT ON (lower(customer_email)) customer_email, COALESCE(customer_name, '')
+FROM licenses
+WHERE customer_email IS NOT NULL AND customer_email <> ''
+ORDER BY lower(customer_email), customer_name IS NULL, created_at DESC
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE licenses ADD COLUMN IF NOT EXISTS customer_id UUID;
+
+UPDATE licenses l SET customer_id = c.id
+FROM customers c
+WHERE lower(c.email) = lower(l.customer_email) AND l.customer_id IS NULL;
+
+-- From here on licenses.customer_name and customer_email mirror the customer.
+UPDATE licenses l SET customer_name = NULLIF(c.name, ''), customer_email = c.email
+FROM customers c
+WHERE c.id = l.customer_id
+  AND (l.customer_name IS DISTINCT FROM NULLIF(c.name, '') OR l.customer_email IS DISTINCT FROM c.email);
+
+-- ON UPDATE CASCADE lets a shard adopt the primary's ID for a customer it
+-- backfilled on its own.
+ALTER TABLE licenses
+    ADD CONSTRAINT fk_licenses_customer
+        FOREIGN KEY (customer_id) REFERENCES customers (id)
+        ON UPDATE CASCADE ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_licenses_customer_id ON licenses (customer_id);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS notes (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    license_id  UUID,
+    customer_id UUID REFERENCES customers (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    source      VARCHAR(32) NOT NULL,
+    external_id VARCHAR(998),
+    author      VARCHAR(255) NOT NULL DEFAULT '',
+    subject     TEXT NOT NULL DEFAULT '',
+    body        TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_notes_target CHECK (license_id IS NOT NULL OR customer_id IS NOT NULL)
+);
+
+-- license_id has no foreign key: with sharding the license lives in a shard
+-- database, while notes stay in the primary one next to customers.
+COMMENT ON COLUMN notes.external_id IS 'Message-ID of an ingested e-mail, so redelivered webhooks do not add the note twice';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notes_source_external_id ON notes (source, external_id) WHERE external_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_notes_license_id ON notes (license_id, created_at DESC) WHERE license_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_notes_customer_id ON notes (customer_id, created_at DESC) WHERE customer_id IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS license_change_approvals;
+DROP TABLE IF EXISTS protected_keys;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS protected_keys (
+    key         VARCHAR(255) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    created_by  TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON COLUMN protected_keys.key IS 'License field or metadata path as in audit diffs, e.g. max_activations or metadata.limits.seats';
+
+-- license_id has no foreign key: with sharding the license lives in a shard
+-- database, while approvals stay in the primary one.
+CREATE TABLE IF NOT EXISTS license_change_approvals (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    license_id      UUID NOT NULL,
+    changes         JSONB NOT NULL,
+    protected_keys  TEXT[] NOT NULL,
+    requested_by    TEXT NOT NULL,
+    base_updated_at TIMESTAMPTZ NOT NULL,
+    status          VARCHAR(20) NOT NULL DEFAULT 'pending',
+    decided_by      TEXT,
+    decided_at      TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at      TIMESTAMPTZ NOT NULL,
+    CONSTRAINT chk_license_change_approvals_status CHECK (status IN ('pending', 'approved', 'rejected'))
+);
+
+COMMENT ON COLUMN license_change_approvals.changes IS 'PATCH /licenses/{id} body held until a second user approves it';
+COMMENT ON COLUMN license_change_approvals.base_updated_at IS 'updated_at of the license when the change was requested; approving fails once the license changed';
+
+CREATE INDEX IF NOT EXISTS idx_license_change_approvals_status ON license_change_approvals (status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_license_change_approvals_license_id ON license_change_approvals (license_id, created_at DESC);
@@ -0,0 +1,14 @@
+UPDATE licenses l SET metadata = COALESCE(l.metadata, '{}'::jsonb) || e.restored
+FROM (
+    SELECT license_id,
+           jsonb_strip_nulls(jsonb_build_object(
+               'features', jsonb_agg(to_jsonb(name) ORDER BY name) FILTER (WHERE type = 'boolean' AND value = 'true'::jsonb),
+               'limits', jsonb_object_agg(name, value) FILTER (WHERE type <> 'boolean')
+           )) AS restored
+    FROM license_entitlements
+    GROUP BY license_id
+) e
+WHERE l.id = e.license_id;
+
+DROP TRIGGER IF EXISTS set_license_entitlements_timestamp ON license_entitlements;
+DROP TABLE IF EXISTS license_entitlements;
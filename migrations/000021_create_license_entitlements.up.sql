@@ -0,0 +1,54 @@
+-- license_id has no foreign key: with sharding the license lives in a shard
+-- database, while entitlements stay in the primary one.
+CREATE TABLE IF NOT EXISTS license_entitlements (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    license_id UUID NOT NULL,
+    name       VARCHAR(100) NOT NULL,
+    type       VARCHAR(20) NOT NULL,
+    value      JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_license_entitlements_type CHECK (type IN ('boolean', 'integer', 'string')),
+    CONSTRAINT chk_license_entitlements_value CHECK (
+        (type = 'boolean' AND jsonb_typeof(value) = 'boolean') OR
+        (type = 'integer' AND jsonb_typeof(value) = 'number') OR
+        (type = 'string' AND jsonb_typeof(value) = 'string')
+    )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_license_entitlements_license_name ON license_entitlements (license_id, name);
+
+CREATE TRIGGER set_license_entitlements_timestamp
+BEFORE UPDATE ON license_entitlements
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+-- Move the untyped metadata entitlements over: every name in
+-- metadata.features becomes a boolean, metadata.limits keep their JSON type
+-- (whole numbers become integers, other numbers and nested values are
+-- dropped). With sharding this runs on every shard and fills the shard's own
+-- table; copy those rows to the primary database afterwards.
+INSERT INTO license_entitlements (license_id, name, type, value)
+SELECT l.id, f.name, 'boolean', 'true'::jsonb
+FROM licenses l
+CROSS JOIN LATERAL jsonb_array_elements_text(l.metadata -> 'features') AS f(name)
+WHERE jsonb_typeof(l.metadata -> 'features') = 'array'
+  AND f.name <> '' AND length(f.name) <= 100
+ON CONFLICT (license_id, name) DO NOTHING;
+
+INSERT INTO license_entitlements (license_id, name, type, value)
+SELECT l.id, e.key,
+       CASE jsonb_typeof(e.value) WHEN 'boolean' THEN 'boolean' WHEN 'number' THEN 'integer' ELSE 'string' END,
+       e.value
+FROM licenses l
+CROSS JOIN LATERAL jsonb_each(l.metadata -> 'limits') AS e(key, value)
+WHERE jsonb_typeof(l.metadata -> 'limits') = 'object'
+  AND e.key <> '' AND length(e.key) <= 100
+  AND (jsonb_typeof(e.value) IN ('boolean', 'string')
+       OR (jsonb_typeof(e.value) = 'number'
+           AND (e.value #>> '{}')::numeric = trunc((e.value #>> '{}')::numeric)
+           AND abs((e.value #>> '{}')::numeric) <= 9223372036854775807))
+ON CONFLICT (license_id, name) DO NOTHING;
+
+UPDATE licenses SET metadata = metadata - 'features' - 'limits'
+WHERE metadata ?| ARRAY['features', 'limits'];
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS license_templates (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name              VARCHAR(100) NOT NULL,
+    product_id        UUID NOT NULL REFERENCES products (id) ON UPDATE CASCADE,
+    type              VARCHAR(100) NOT NULL,
+    duration_days     INTEGER,
+    max_activations   INTEGER,
+    grace_period_days INTEGER,
+    entitlements      JSONB NOT NULL DEFAULT '[]'::jsonb,
+    metadata          JSONB,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_license_templates_duration_days CHECK (duration_days IS NULL OR duration_days > 0),
+    CONSTRAINT chk_license_templates_max_activations CHECK (max_activations IS NULL OR max_activations > 0),
+    CONSTRAINT chk_license_templates_grace_period_days CHECK (grace_period_days IS NULL OR grace_period_days >= 0)
+);
+
+COMMENT ON COLUMN license_templates.duration_days IS 'Licenses created from the template expire this many days after creation; NULL means they never expire';
+COMMENT ON COLUMN license_templates.entitlements IS 'Array of {name, type, value} copied to license_entitlements of every license created from the template';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_license_templates_name ON license_templates (lower(name));
+CREATE INDEX IF NOT EXISTS idx_license_templates_product_id ON license_templates (product_id);
+
+CREATE TRIGGER set_timestamp
+BEFORE UPDATE ON license_templates
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
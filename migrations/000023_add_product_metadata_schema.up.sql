@@ -0,0 +1,4 @@
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS metadata_schema JSONB;
+
+COMMENT ON COLUMN products.metadata_schema IS 'JSON Schema (OpenAPI 3 dialect) the metadata of the product''s licenses must match; NULL accepts any metadata';
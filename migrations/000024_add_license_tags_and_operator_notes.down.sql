@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_licenses_tags;
+
+ALTER TABLE licenses
+    DROP COLUMN IF EXISTS operator_notes,
+    DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,5 @@
+ALTER TABLE licenses
+    ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS operator_notes TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_licenses_tags ON licenses USING GIN (tags);
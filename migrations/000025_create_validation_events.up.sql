@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS validation_events (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    license_id    UUID,
+    product_name  VARCHAR(255) NOT NULL,
+    valid         BOOLEAN NOT NULL,
+    reason        VARCHAR(64) NOT NULL,
+    agent_version VARCHAR(64) NOT NULL DEFAULT '',
+    device_id     VARCHAR(255) NOT NULL DEFAULT '',
+    ip_address    VARCHAR(64) NOT NULL DEFAULT '',
+    sample_rate   INTEGER NOT NULL DEFAULT 1 CHECK (sample_rate >= 1),
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- license_id has no foreign key: with sharding the license lives in a shard
+-- database, and attempts with unknown keys are recorded without one.
+COMMENT ON COLUMN validation_events.sample_rate IS 'Number of validations the row stands for: 1 for failures and unsampled successes, N when one in N successful validations is kept';
+
+CREATE INDEX IF NOT EXISTS idx_validation_events_product_created ON validation_events (product_name, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_validation_events_license_created ON validation_events (license_id, created_at DESC) WHERE license_id IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_licenses_pending_starts_at;
+
+ALTER TABLE licenses
+    DROP COLUMN IF EXISTS starts_at;
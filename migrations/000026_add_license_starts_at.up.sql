@@ -0,0 +1,6 @@
+ALTER TABLE licenses
+    ADD COLUMN IF NOT EXISTS starts_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN licenses.starts_at IS 'Scheduled start: the license does not validate before it, and a pending license is activated once it passes.';
+
+CREATE INDEX IF NOT EXISTS idx_licenses_pending_starts_at ON licenses (starts_at) WHERE status = 'pending' AND starts_at IS NOT NULL;
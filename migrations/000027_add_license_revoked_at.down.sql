@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_licenses_revoked_at;
+
+DROP TRIGGER IF EXISTS set_revoked_at ON licenses;
+DROP FUNCTION IF EXISTS trigger_set_revoked_at();
+
+ALTER TABLE licenses
+    DROP COLUMN IF EXISTS revoked_at;
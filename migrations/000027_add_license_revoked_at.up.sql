@@ -0,0 +1,25 @@
+ALTER TABLE licenses
+    ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN licenses.revoked_at IS 'When the license was revoked; set by trigger_set_revoked_at, whatever path revoked it.';
+
+-- Licenses revoked before this migration get their last update as an
+-- estimate.
+UPDATE licenses SET revoked_at = updated_at WHERE status = 'revoked' AND revoked_at IS NULL;
+
+CREATE OR REPLACE FUNCTION trigger_set_revoked_at()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF NEW.status = 'revoked' AND NEW.revoked_at IS NULL THEN
+    NEW.revoked_at = NOW();
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER set_revoked_at
+BEFORE INSERT OR UPDATE OF status ON licenses
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_revoked_at();
+
+CREATE INDEX IF NOT EXISTS idx_licenses_revoked_at ON licenses (revoked_at) WHERE status = 'revoked';
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS license_usage (
+    license_id UUID NOT NULL,
+    metric     VARCHAR(100) NOT NULL,
+    day        DATE NOT NULL,
+    quantity   BIGINT NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (license_id, metric, day)
+);
+
+-- license_id has no foreign key: with sharding the license lives in a shard
+-- database, while usage is kept in the primary one.
+COMMENT ON TABLE license_usage IS 'Usage reported by agents, summed per license, metric and UTC day';
@@ -0,0 +1,2 @@
+ALTER TABLE licenses
+    DROP COLUMN IF EXISTS floating;
@@ -0,0 +1,4 @@
+ALTER TABLE licenses
+    ADD COLUMN IF NOT EXISTS floating BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN licenses.floating IS 'Seats are leased to clients through checkout and check-in instead of bound to devices; max_activations caps concurrent leases';
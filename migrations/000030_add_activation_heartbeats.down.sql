@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_license_activations_last_seen;
+
+ALTER TABLE license_activations
+    DROP COLUMN IF EXISTS host_info,
+    DROP COLUMN IF EXISTS app_version,
+    DROP COLUMN IF EXISTS last_seen_at;
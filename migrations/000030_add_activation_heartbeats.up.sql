@@ -0,0 +1,10 @@
+ALTER TABLE license_activations
+    ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS app_version VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS host_info JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+CREATE INDEX IF NOT EXISTS idx_license_activations_last_seen
+    ON license_activations (last_seen_at)
+    WHERE deactivated_at IS NULL;
+
+COMMENT ON COLUMN license_activations.last_seen_at IS 'Time of the last heartbeat of the device; NULL until the agent sends one';
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS superseded_keys (
+    license_key   TEXT PRIMARY KEY,
+    license_id    UUID NOT NULL REFERENCES licenses (id) ON DELETE CASCADE,
+    superseded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    valid_until   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_superseded_keys_license_id ON superseded_keys (license_id);
+
+COMMENT ON TABLE superseded_keys IS 'Previous keys of rotated licenses, still accepted until valid_until';
+COMMENT ON COLUMN superseded_keys.valid_until IS 'End of the overlap window after which the old key stops finding the license';
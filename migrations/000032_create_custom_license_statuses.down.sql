@@ -0,0 +1,32 @@
+DROP TABLE IF EXISTS custom_license_statuses;
+
+-- Licenses in a custom status have no place in the enum and become inactive.
+UPDATE licenses SET status = 'inactive'
+WHERE status NOT IN ('pending', 'active', 'inactive', 'expired', 'revoked', 'suspended');
+DELETE FROM license_status_history
+WHERE from_status NOT IN ('pending', 'active', 'inactive', 'expired', 'revoked', 'suspended')
+   OR to_status NOT IN ('pending', 'active', 'inactive', 'expired', 'revoked', 'suspended');
+
+CREATE TYPE license_status AS ENUM ('pending', 'active', 'inactive', 'expired', 'revoked', 'suspended');
+
+DROP TRIGGER IF EXISTS set_revoked_at ON licenses;
+DROP INDEX IF EXISTS idx_licenses_pending_starts_at;
+DROP INDEX IF EXISTS idx_licenses_revoked_at;
+
+ALTER TABLE licenses ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE licenses ALTER COLUMN status TYPE license_status USING status::license_status;
+ALTER TABLE licenses ALTER COLUMN status SET DEFAULT 'pending';
+
+ALTER TABLE license_status_history
+    ALTER COLUMN from_status TYPE license_status USING from_status::license_status,
+    ALTER COLUMN to_status TYPE license_status USING to_status::license_status;
+
+COMMENT ON COLUMN licenses.status IS 'Current status of the license (pending, active, inactive, expired, revoked)';
+
+CREATE INDEX IF NOT EXISTS idx_licenses_pending_starts_at ON licenses (starts_at) WHERE status = 'pending' AND starts_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_licenses_revoked_at ON licenses (revoked_at) WHERE status = 'revoked';
+
+CREATE TRIGGER set_revoked_at
+BEFORE INSERT OR UPDATE OF status ON licenses
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_revoked_at();
@@ -0,0 +1,42 @@
+-- Custom statuses are defined at runtime, so licenses.status becomes text
+-- and the service checks it against the built-in and custom statuses. The
+-- trigger and partial indexes on status are recreated around the change.
+DROP TRIGGER IF EXISTS set_revoked_at ON licenses;
+DROP INDEX IF EXISTS idx_licenses_pending_starts_at;
+DROP INDEX IF EXISTS idx_licenses_revoked_at;
+
+ALTER TABLE licenses ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE licenses ALTER COLUMN status TYPE VARCHAR(48) USING status::text;
+ALTER TABLE licenses ALTER COLUMN status SET DEFAULT 'pending';
+
+ALTER TABLE license_status_history
+    ALTER COLUMN from_status TYPE VARCHAR(48) USING from_status::text,
+    ALTER COLUMN to_status TYPE VARCHAR(48) USING to_status::text;
+
+DROP TYPE IF EXISTS license_status;
+
+COMMENT ON COLUMN licenses.status IS 'Current status: pending, active, inactive, expired, revoked, suspended or a name from custom_license_statuses';
+
+CREATE INDEX IF NOT EXISTS idx_licenses_pending_starts_at ON licenses (starts_at) WHERE status = 'pending' AND starts_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_licenses_revoked_at ON licenses (revoked_at) WHERE status = 'revoked';
+
+CREATE TRIGGER set_revoked_at
+BEFORE INSERT OR UPDATE OF status ON licenses
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_revoked_at();
+
+CREATE TABLE IF NOT EXISTS custom_license_statuses (
+    name        VARCHAR(48) PRIMARY KEY,
+    behavior    VARCHAR(16) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_custom_license_statuses_behavior CHECK (behavior IN ('valid', 'warn', 'invalid'))
+);
+
+COMMENT ON COLUMN custom_license_statuses.behavior IS 'How validation treats licenses in the status: valid like active, warn like active with a warning, invalid denied';
+
+CREATE TRIGGER set_timestamp
+BEFORE UPDATE ON custom_license_statuses
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
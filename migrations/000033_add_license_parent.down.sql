@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_licenses_parent_id;
+
+ALTER TABLE licenses
+    DROP COLUMN IF EXISTS parent_id;
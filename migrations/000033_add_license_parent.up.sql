@@ -0,0 +1,8 @@
+-- Child licenses point at their parent without a foreign key: with
+-- sharding, a child is placed by its own key and may live on another shard.
+ALTER TABLE licenses
+    ADD COLUMN IF NOT EXISTS parent_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_licenses_parent_id ON licenses (parent_id) WHERE parent_id IS NOT NULL;
+
+COMMENT ON COLUMN licenses.parent_id IS 'Parent license whose seats, expiry and entitlements this child license shares';
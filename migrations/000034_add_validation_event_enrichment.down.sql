@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_validation_events_license_country;
+
+ALTER TABLE validation_events
+    DROP COLUMN IF EXISTS tls_fingerprint,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS country,
+    DROP COLUMN IF EXISTS as_org,
+    DROP COLUMN IF EXISTS asn;
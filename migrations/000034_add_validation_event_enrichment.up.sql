@@ -0,0 +1,13 @@
+-- Network context of the validation request, filled in by the enrichment
+-- providers; empty or 0 when no provider knew the value.
+ALTER TABLE validation_events
+    ADD COLUMN IF NOT EXISTS asn             BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS as_org          VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS country         VARCHAR(2) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS user_agent      VARCHAR(512) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS tls_fingerprint VARCHAR(128) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN validation_events.asn IS 'Autonomous system number of the client IP; 0 when unknown';
+COMMENT ON COLUMN validation_events.country IS 'ISO 3166-1 alpha-2 country of the client IP; empty when unknown';
+
+CREATE INDEX IF NOT EXISTS idx_validation_events_license_country ON validation_events (license_id, country) WHERE license_id IS NOT NULL AND country <> '';
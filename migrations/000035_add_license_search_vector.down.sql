@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_licenses_search_vector;
+
+ALTER TABLE licenses DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,10 @@
+-- Full-text document of a license for /licenses/search: key, customer and
+-- the string and numeric metadata values, with the simple configuration so
+-- names and keys are not stemmed.
+ALTER TABLE licenses
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+        to_tsvector('simple', license_key || ' ' || coalesce(customer_name, '') || ' ' || coalesce(customer_email, ''))
+        || jsonb_to_tsvector('simple', coalesce(metadata, '{}'::jsonb), '["string", "numeric"]')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_licenses_search_vector ON licenses USING GIN (search_vector);
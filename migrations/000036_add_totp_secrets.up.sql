@@ -0,0 +1,7 @@
+-- TOTP secrets users enrolled for step-up authentication, by OIDC subject.
+CREATE TABLE IF NOT EXISTS totp_secrets (
+    subject TEXT PRIMARY KEY,
+    secret BYTEA NOT NULL,
+    last_counter BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS license_expiry_opt_outs;
+DROP TABLE IF EXISTS license_expiry_notices;
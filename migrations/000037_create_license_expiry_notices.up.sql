@@ -0,0 +1,18 @@
+-- Expiry e-mails sent to customers, one row per license, kind, reminder day
+-- and expiry date so that nothing is sent twice. Like entitlements these
+-- live in the primary database, without a foreign key to licenses.
+CREATE TABLE IF NOT EXISTS license_expiry_notices (
+    license_id  UUID NOT NULL,
+    kind        VARCHAR(20) NOT NULL,
+    days_before INT NOT NULL DEFAULT 0,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    sent_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (license_id, kind, days_before, expires_at),
+    CONSTRAINT chk_license_expiry_notices_kind CHECK (kind IN ('reminder', 'expired'))
+);
+
+-- Licenses whose customers get no expiry e-mails.
+CREATE TABLE IF NOT EXISTS license_expiry_opt_outs (
+    license_id UUID PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,15 @@
+-- On-demand integrity checks and their reports.
+CREATE TABLE IF NOT EXISTS integrity_checks (
+    id           UUID PRIMARY KEY,
+    status       VARCHAR(20) NOT NULL DEFAULT 'pending',
+    repairs      TEXT[] NOT NULL DEFAULT '{}',
+    requested_by TEXT NOT NULL DEFAULT '',
+    report       JSONB,
+    error        TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at   TIMESTAMPTZ,
+    finished_at  TIMESTAMPTZ,
+    CONSTRAINT chk_integrity_checks_status CHECK (status IN ('pending', 'running', 'completed', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_integrity_checks_created_at ON integrity_checks (created_at DESC);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS api_usage;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS org_id;
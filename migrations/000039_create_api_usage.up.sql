@@ -0,0 +1,16 @@
+-- Zitadel organization an API key was created in; calls made with the key
+-- are billed to it.
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS org_id TEXT NOT NULL DEFAULT '';
+
+-- Admin and agent API calls per month, organization, API and API key, for
+-- usage billing. Admin calls have the nil UUID as api_key_id.
+CREATE TABLE IF NOT EXISTS api_usage (
+    month        DATE NOT NULL,
+    org_id       TEXT NOT NULL,
+    api          VARCHAR(20) NOT NULL,
+    api_key_id   UUID NOT NULL,
+    calls        BIGINT NOT NULL DEFAULT 0,
+    failed_calls BIGINT NOT NULL DEFAULT 0,
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (month, org_id, api, api_key_id)
+);
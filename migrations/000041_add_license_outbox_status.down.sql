@@ -0,0 +1,31 @@
+DELETE FROM outbox_cursors WHERE consumer = 'lifecycle_hooks';
+
+CREATE OR REPLACE FUNCTION trigger_license_outbox()
+RETURNS TRIGGER AS $$
+DECLARE
+  changed TEXT[];
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    SELECT array_agg(key ORDER BY key) INTO changed FROM jsonb_object_keys(to_jsonb(NEW)) AS key;
+    INSERT INTO license_outbox (license_id, operation, changed_fields) VALUES (NEW.id, 'insert', changed);
+    RETURN NEW;
+  ELSIF TG_OP = 'DELETE' THEN
+    SELECT array_agg(key ORDER BY key) INTO changed FROM jsonb_object_keys(to_jsonb(OLD)) AS key;
+    INSERT INTO license_outbox (license_id, operation, changed_fields) VALUES (OLD.id, 'delete', changed);
+    RETURN OLD;
+  END IF;
+
+  SELECT array_agg(n.key ORDER BY n.key) INTO changed
+  FROM jsonb_each(to_jsonb(NEW)) AS n
+  JOIN jsonb_each(to_jsonb(OLD)) AS o USING (key)
+  WHERE n.value IS DISTINCT FROM o.value AND n.key <> 'updated_at';
+
+  IF changed IS NOT NULL THEN
+    INSERT INTO license_outbox (license_id, operation, changed_fields) VALUES (NEW.id, 'update', changed);
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE license_outbox DROP COLUMN IF EXISTS previous_status;
+ALTER TABLE license_outbox DROP COLUMN IF EXISTS status;
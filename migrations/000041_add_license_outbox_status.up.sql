@@ -0,0 +1,40 @@
+-- The status before and after each change, so lifecycle events can be
+-- derived from the outbox instead of being fired after the commit.
+ALTER TABLE license_outbox ADD COLUMN IF NOT EXISTS status VARCHAR(48);
+ALTER TABLE license_outbox ADD COLUMN IF NOT EXISTS previous_status VARCHAR(48);
+
+COMMENT ON COLUMN license_outbox.status IS 'Status after the change, NULL for delete';
+COMMENT ON COLUMN license_outbox.previous_status IS 'Status before the change, NULL for insert';
+
+CREATE OR REPLACE FUNCTION trigger_license_outbox()
+RETURNS TRIGGER AS $$
+DECLARE
+  changed TEXT[];
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    SELECT array_agg(key ORDER BY key) INTO changed FROM jsonb_object_keys(to_jsonb(NEW)) AS key;
+    INSERT INTO license_outbox (license_id, operation, changed_fields, status) VALUES (NEW.id, 'insert', changed, NEW.status);
+    RETURN NEW;
+  ELSIF TG_OP = 'DELETE' THEN
+    SELECT array_agg(key ORDER BY key) INTO changed FROM jsonb_object_keys(to_jsonb(OLD)) AS key;
+    INSERT INTO license_outbox (license_id, operation, changed_fields, previous_status) VALUES (OLD.id, 'delete', changed, OLD.status);
+    RETURN OLD;
+  END IF;
+
+  SELECT array_agg(n.key ORDER BY n.key) INTO changed
+  FROM jsonb_each(to_jsonb(NEW)) AS n
+  JOIN jsonb_each(to_jsonb(OLD)) AS o USING (key)
+  WHERE n.value IS DISTINCT FROM o.value AND n.key <> 'updated_at';
+
+  IF changed IS NOT NULL THEN
+    INSERT INTO license_outbox (license_id, operation, changed_fields, status, previous_status)
+    VALUES (NEW.id, 'update', changed, NEW.status, OLD.status);
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Lifecycle hooks start with the changes made after this migration.
+INSERT INTO outbox_cursors (consumer, last_id)
+SELECT 'lifecycle_hooks', COALESCE(MAX(id), 0) FROM license_outbox
+ON CONFLICT (consumer) DO NOTHING;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS email_unsubscribes;
+DROP TABLE IF EXISTS email_campaign_recipients;
+DROP TABLE IF EXISTS email_campaigns;
@@ -0,0 +1,48 @@
+-- Bulk e-mail campaigns to license holders, their recipients and the
+-- addresses that unsubscribed from them.
+CREATE TABLE IF NOT EXISTS email_campaigns (
+    id           UUID PRIMARY KEY,
+    name         VARCHAR(255) NOT NULL,
+    subject      TEXT NOT NULL,
+    message      TEXT NOT NULL,
+    filter       JSONB NOT NULL DEFAULT '{}',
+    status       VARCHAR(20) NOT NULL DEFAULT 'pending',
+    requested_by TEXT NOT NULL DEFAULT '',
+    error        TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at   TIMESTAMPTZ,
+    finished_at  TIMESTAMPTZ,
+    CONSTRAINT chk_email_campaigns_status CHECK (status IN ('pending', 'sending', 'completed', 'cancelled', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_campaigns_created_at ON email_campaigns (created_at DESC);
+
+-- license_id has no foreign key: licenses may live on another shard.
+CREATE TABLE IF NOT EXISTS email_campaign_recipients (
+    id          UUID PRIMARY KEY,
+    campaign_id UUID NOT NULL REFERENCES email_campaigns (id) ON DELETE CASCADE,
+    email       VARCHAR(255) NOT NULL,
+    license_id  UUID NOT NULL,
+    batch       INTEGER NOT NULL,
+    status      VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts    INTEGER NOT NULL DEFAULT 0,
+    last_error  TEXT NOT NULL DEFAULT '',
+    sent_at     TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_email_campaign_recipients_email UNIQUE (campaign_id, email),
+    CONSTRAINT chk_email_campaign_recipients_status CHECK (status IN ('pending', 'sent', 'failed', 'unsubscribed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_campaign_recipients_batch ON email_campaign_recipients (campaign_id, batch);
+
+CREATE TRIGGER set_timestamp
+BEFORE UPDATE ON email_campaign_recipients
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+CREATE TABLE IF NOT EXISTS email_unsubscribes (
+    email           VARCHAR(255) PRIMARY KEY,
+    campaign_id     UUID,
+    unsubscribed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,15 @@
+ALTER TABLE webhook_subscriptions
+    DROP CONSTRAINT IF EXISTS chk_webhook_subscriptions_timeout,
+    DROP CONSTRAINT IF EXISTS chk_webhook_subscriptions_max_backoff,
+    DROP CONSTRAINT IF EXISTS chk_webhook_subscriptions_initial_backoff,
+    DROP CONSTRAINT IF EXISTS chk_webhook_subscriptions_max_attempts,
+    DROP COLUMN IF EXISTS disabled_at,
+    DROP COLUMN IF EXISTS disabled_reason,
+    DROP COLUMN IF EXISTS last_failure_at,
+    DROP COLUMN IF EXISTS last_success_at,
+    DROP COLUMN IF EXISTS failing_since,
+    DROP COLUMN IF EXISTS consecutive_failures,
+    DROP COLUMN IF EXISTS timeout_seconds,
+    DROP COLUMN IF EXISTS max_backoff_seconds,
+    DROP COLUMN IF EXISTS initial_backoff_seconds,
+    DROP COLUMN IF EXISTS max_attempts;
@@ -0,0 +1,17 @@
+-- Per-subscription delivery policy, NULL meaning the WEBHOOKS_* default,
+-- and the health used to disable endpoints that keep failing.
+ALTER TABLE webhook_subscriptions
+    ADD COLUMN IF NOT EXISTS max_attempts INTEGER,
+    ADD COLUMN IF NOT EXISTS initial_backoff_seconds INTEGER,
+    ADD COLUMN IF NOT EXISTS max_backoff_seconds INTEGER,
+    ADD COLUMN IF NOT EXISTS timeout_seconds INTEGER,
+    ADD COLUMN IF NOT EXISTS consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS failing_since TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS last_success_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS last_failure_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS disabled_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMPTZ,
+    ADD CONSTRAINT chk_webhook_subscriptions_max_attempts CHECK (max_attempts IS NULL OR max_attempts > 0),
+    ADD CONSTRAINT chk_webhook_subscriptions_initial_backoff CHECK (initial_backoff_seconds IS NULL OR initial_backoff_seconds > 0),
+    ADD CONSTRAINT chk_webhook_subscriptions_max_backoff CHECK (max_backoff_seconds IS NULL OR max_backoff_seconds > 0),
+    ADD CONSTRAINT chk_webhook_subscriptions_timeout CHECK (timeout_seconds IS NULL OR timeout_seconds > 0);
@@ -0,0 +1,15 @@
+-- Comments operators leave on a license to coordinate support and sales.
+-- license_id has no foreign key: with sharding the license lives in a shard
+-- database, while comments stay in the primary one.
+CREATE TABLE IF NOT EXISTS license_comments (
+    id          UUID PRIMARY KEY,
+    license_id  UUID NOT NULL,
+    author      VARCHAR(255) NOT NULL,
+    author_name VARCHAR(255) NOT NULL DEFAULT '',
+    body        TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON COLUMN license_comments.author IS 'OIDC subject of the operator who wrote the comment';
+
+CREATE INDEX IF NOT EXISTS idx_license_comments_license_id ON license_comments (license_id, created_at DESC);
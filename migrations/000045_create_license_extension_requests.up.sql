@@ -0,0 +1,25 @@
+-- Requests to push out the expiry of a license, which only users with the
+-- extension approver role can grant. license_id has no foreign key: with
+-- sharding the license lives in a shard database, while requests stay in
+-- the primary one.
+CREATE TABLE IF NOT EXISTS license_extension_requests (
+    id                   UUID PRIMARY KEY,
+    license_id           UUID NOT NULL,
+    requested_expires_at TIMESTAMPTZ NOT NULL,
+    previous_expires_at  TIMESTAMPTZ NOT NULL,
+    reason               TEXT NOT NULL DEFAULT '',
+    requested_by         TEXT NOT NULL,
+    status               VARCHAR(20) NOT NULL DEFAULT 'pending',
+    decided_by           TEXT,
+    decided_at           TIMESTAMPTZ,
+    decision_note        TEXT NOT NULL DEFAULT '',
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_license_extension_requests_status CHECK (status IN ('pending', 'approved', 'rejected'))
+);
+
+COMMENT ON COLUMN license_extension_requests.previous_expires_at IS 'expires_at of the license when the extension was requested';
+
+-- A license has at most one pending extension request.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_license_extension_requests_pending ON license_extension_requests (license_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_license_extension_requests_license_id ON license_extension_requests (license_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_license_extension_requests_status ON license_extension_requests (status, created_at DESC);
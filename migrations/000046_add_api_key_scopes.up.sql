@@ -0,0 +1,8 @@
+-- Agent operations an API key may call. Existing keys keep all of them.
+ALTER TABLE api_keys
+    ADD COLUMN IF NOT EXISTS scopes TEXT[] NOT NULL
+        DEFAULT ARRAY['licenses:validate', 'activations:write', 'usage:write', 'usage:read'];
+
+-- Subject of the user who created the key, empty for keys created outside
+-- the API.
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS created_by TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_licenses_terminal_updated_at;
+DROP TABLE IF EXISTS validation_events_archive;
+DROP TABLE IF EXISTS license_status_history_archive;
+DROP TABLE IF EXISTS license_activations_archive;
+DROP TABLE IF EXISTS licenses_archive;
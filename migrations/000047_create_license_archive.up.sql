@@ -0,0 +1,49 @@
+-- Licenses moved out of licenses by the archive job once they have been
+-- expired or revoked, and unchanged, for longer than the archive age. The
+-- columns are those the license repositories read.
+CREATE TABLE IF NOT EXISTS licenses_archive (
+    id                UUID PRIMARY KEY,
+    license_key       TEXT NOT NULL,
+    status            VARCHAR(48) NOT NULL,
+    type              VARCHAR(50) NOT NULL,
+    customer_name     VARCHAR(255),
+    customer_email    VARCHAR(255),
+    customer_id       UUID,
+    product_id        UUID NOT NULL,
+    product_name      VARCHAR(100) NOT NULL,
+    metadata          JSONB,
+    issued_at         TIMESTAMPTZ,
+    expires_at        TIMESTAMPTZ,
+    max_activations   INTEGER NOT NULL,
+    grace_period_days INTEGER NOT NULL,
+    tags              TEXT[] NOT NULL DEFAULT '{}',
+    operator_notes    TEXT NOT NULL DEFAULT '',
+    starts_at         TIMESTAMPTZ,
+    revoked_at        TIMESTAMPTZ,
+    floating          BOOLEAN NOT NULL DEFAULT FALSE,
+    parent_id         UUID,
+    created_at        TIMESTAMPTZ NOT NULL,
+    updated_at        TIMESTAMPTZ NOT NULL,
+    archived_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A key may be issued again once its license is archived.
+CREATE INDEX IF NOT EXISTS idx_licenses_archive_license_key ON licenses_archive (license_key);
+
+-- Events of archived licenses. The tables have the columns of their source
+-- tables, without foreign keys; a migration adding a column to a source
+-- table must add it here too.
+CREATE TABLE IF NOT EXISTS license_activations_archive (LIKE license_activations INCLUDING DEFAULTS);
+ALTER TABLE license_activations_archive ADD PRIMARY KEY (id);
+CREATE INDEX IF NOT EXISTS idx_license_activations_archive_license ON license_activations_archive (license_id);
+
+CREATE TABLE IF NOT EXISTS license_status_history_archive (LIKE license_status_history INCLUDING DEFAULTS);
+ALTER TABLE license_status_history_archive ADD PRIMARY KEY (id);
+CREATE INDEX IF NOT EXISTS idx_license_status_history_archive_license ON license_status_history_archive (license_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS validation_events_archive (LIKE validation_events INCLUDING DEFAULTS);
+ALTER TABLE validation_events_archive ADD PRIMARY KEY (id);
+CREATE INDEX IF NOT EXISTS idx_validation_events_archive_license ON validation_events_archive (license_id, created_at DESC);
+
+-- Finds archive candidates without scanning live licenses.
+CREATE INDEX IF NOT EXISTS idx_licenses_terminal_updated_at ON licenses (updated_at) WHERE status IN ('expired', 'revoked');
@@ -0,0 +1,9 @@
+-- How long agents may rely on a validation result of the product, in
+-- seconds; returned as cache hints in validation responses.
+CREATE TABLE IF NOT EXISTS product_cache_policies (
+    product_name     VARCHAR(255) PRIMARY KEY,
+    cache_ttl        INTEGER NOT NULL CHECK (cache_ttl > 0),
+    revalidate_after INTEGER NOT NULL CHECK (revalidate_after > 0 AND revalidate_after <= cache_ttl),
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,15 @@
+-- Challenges of offline activations. A challenge is consumed when the vendor
+-- signs its response, so every response answers exactly one challenge.
+-- license_id has no foreign key: with sharding the license lives in a shard
+-- database.
+CREATE TABLE IF NOT EXISTS offline_activation_challenges (
+    id          UUID PRIMARY KEY,
+    license_id  UUID NOT NULL,
+    device_id   VARCHAR(255) NOT NULL,
+    nonce       TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at  TIMESTAMPTZ NOT NULL,
+    consumed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_offline_activation_challenges_license ON offline_activation_challenges (license_id, created_at DESC);
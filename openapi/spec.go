@@ -0,0 +1,6 @@
+package openapi
+
+import _ "embed"
+
+//go:embed api.yaml
+var Spec []byte
@@ -0,0 +1,359 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: license/v1/events.proto
+
+package licensev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Operation int32
+
+const (
+	Operation_OPERATION_UNSPECIFIED Operation = 0
+	Operation_OPERATION_INSERT      Operation = 1
+	Operation_OPERATION_UPDATE      Operation = 2
+	Operation_OPERATION_DELETE      Operation = 3
+)
+
+// Enum value maps for Operation.
+var (
+	Operation_name = map[int32]string{
+		0: "OPERATION_UNSPECIFIED",
+		1: "OPERATION_INSERT",
+		2: "OPERATION_UPDATE",
+		3: "OPERATION_DELETE",
+	}
+	Operation_value = map[string]int32{
+		"OPERATION_UNSPECIFIED": 0,
+		"OPERATION_INSERT":      1,
+		"OPERATION_UPDATE":      2,
+		"OPERATION_DELETE":      3,
+	}
+)
+
+func (x Operation) Enum() *Operation {
+	p := new(Operation)
+	*p = x
+	return p
+}
+
+func (x Operation) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Operation) Descriptor() protoreflect.EnumDescriptor {
+	return file_license_v1_events_proto_enumTypes[0].Descriptor()
+}
+
+func (Operation) Type() protoreflect.EnumType {
+	return &file_license_v1_events_proto_enumTypes[0]
+}
+
+func (x Operation) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Operation.Descriptor instead.
+func (Operation) EnumDescriptor() ([]byte, []int) {
+	return file_license_v1_events_proto_rawDescGZIP(), []int{0}
+}
+
+type SubscribeLicenseEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Version of the last event already handled; 0 starts with the oldest
+	// change still in the outbox.
+	Since int64 `protobuf:"varint,1,opt,name=since,proto3" json:"since,omitempty"`
+	// Only changes of licenses of this product.
+	ProductName string `protobuf:"bytes,2,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	// Only changes of licenses of this customer.
+	CustomerId string `protobuf:"bytes,3,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	// Only changes touching at least one of these fields, as in the change
+	// feed's fields parameter.
+	Fields        []string `protobuf:"bytes,4,rep,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeLicenseEventsRequest) Reset() {
+	*x = SubscribeLicenseEventsRequest{}
+	mi := &file_license_v1_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeLicenseEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeLicenseEventsRequest) ProtoMessage() {}
+
+func (x *SubscribeLicenseEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_license_v1_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeLicenseEventsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeLicenseEventsRequest) Descriptor() ([]byte, []int) {
+	return file_license_v1_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SubscribeLicenseEventsRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *SubscribeLicenseEventsRequest) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+func (x *SubscribeLicenseEventsRequest) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *SubscribeLicenseEventsRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type SubscribeLicenseEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *LicenseEvent          `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeLicenseEventsResponse) Reset() {
+	*x = SubscribeLicenseEventsResponse{}
+	mi := &file_license_v1_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeLicenseEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeLicenseEventsResponse) ProtoMessage() {}
+
+func (x *SubscribeLicenseEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_license_v1_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeLicenseEventsResponse.ProtoReflect.Descriptor instead.
+func (*SubscribeLicenseEventsResponse) Descriptor() ([]byte, []int) {
+	return file_license_v1_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SubscribeLicenseEventsResponse) GetEvent() *LicenseEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+type LicenseEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Cursor of the event; pass it as since to resume after it.
+	Version       int64                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	LicenseId     string                 `protobuf:"bytes,2,opt,name=license_id,json=licenseId,proto3" json:"license_id,omitempty"`
+	Operation     Operation              `protobuf:"varint,3,opt,name=operation,proto3,enum=license.v1.Operation" json:"operation,omitempty"`
+	ChangedFields []string               `protobuf:"bytes,4,rep,name=changed_fields,json=changedFields,proto3" json:"changed_fields,omitempty"`
+	ChangedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LicenseEvent) Reset() {
+	*x = LicenseEvent{}
+	mi := &file_license_v1_events_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LicenseEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LicenseEvent) ProtoMessage() {}
+
+func (x *LicenseEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_license_v1_events_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LicenseEvent.ProtoReflect.Descriptor instead.
+func (*LicenseEvent) Descriptor() ([]byte, []int) {
+	return file_license_v1_events_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LicenseEvent) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *LicenseEvent) GetLicenseId() string {
+	if x != nil {
+		return x.LicenseId
+	}
+	return ""
+}
+
+func (x *LicenseEvent) GetOperation() Operation {
+	if x != nil {
+		return x.Operation
+	}
+	return Operation_OPERATION_UNSPECIFIED
+}
+
+func (x *LicenseEvent) GetChangedFields() []string {
+	if x != nil {
+		return x.ChangedFields
+	}
+	return nil
+}
+
+func (x *LicenseEvent) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+var File_license_v1_events_proto protoreflect.FileDescriptor
+
+const file_license_v1_events_proto_rawDesc = "" +
+	"\n" +
+	"\x17license/v1/events.proto\x12\n" +
+	"license.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x91\x01\n" +
+	"\x1dSubscribeLicenseEventsRequest\x12\x14\n" +
+	"\x05since\x18\x01 \x01(\x03R\x05since\x12!\n" +
+	"\fproduct_name\x18\x02 \x01(\tR\vproductName\x12\x1f\n" +
+	"\vcustomer_id\x18\x03 \x01(\tR\n" +
+	"customerId\x12\x16\n" +
+	"\x06fields\x18\x04 \x03(\tR\x06fields\"P\n" +
+	"\x1eSubscribeLicenseEventsResponse\x12.\n" +
+	"\x05event\x18\x01 \x01(\v2\x18.license.v1.LicenseEventR\x05event\"\xde\x01\n" +
+	"\fLicenseEvent\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x03R\aversion\x12\x1d\n" +
+	"\n" +
+	"license_id\x18\x02 \x01(\tR\tlicenseId\x123\n" +
+	"\toperation\x18\x03 \x01(\x0e2\x15.license.v1.OperationR\toperation\x12%\n" +
+	"\x0echanged_fields\x18\x04 \x03(\tR\rchangedFields\x129\n" +
+	"\n" +
+	"changed_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt*h\n" +
+	"\tOperation\x12\x19\n" +
+	"\x15OPERATION_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10OPERATION_INSERT\x10\x01\x12\x14\n" +
+	"\x10OPERATION_UPDATE\x10\x02\x12\x14\n" +
+	"\x10OPERATION_DELETE\x10\x032\x8a\x01\n" +
+	"\x13LicenseEventService\x12s\n" +
+	"\x16SubscribeLicenseEvents\x12).license.v1.SubscribeLicenseEventsRequest\x1a*.license.v1.SubscribeLicenseEventsResponse\"\x000\x01BFZDgithub.com/makkenzo/license-service-api/pkg/api/license/v1;licensev1b\x06proto3"
+
+var (
+	file_license_v1_events_proto_rawDescOnce sync.Once
+	file_license_v1_events_proto_rawDescData []byte
+)
+
+func file_license_v1_events_proto_rawDescGZIP() []byte {
+	file_license_v1_events_proto_rawDescOnce.Do(func() {
+		file_license_v1_events_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_license_v1_events_proto_rawDesc), len(file_license_v1_events_proto_rawDesc)))
+	})
+	return file_license_v1_events_proto_rawDescData
+}
+
+var file_license_v1_events_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_license_v1_events_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_license_v1_events_proto_goTypes = []any{
+	(Operation)(0),                         // 0: license.v1.Operation
+	(*SubscribeLicenseEventsRequest)(nil),  // 1: license.v1.SubscribeLicenseEventsRequest
+	(*SubscribeLicenseEventsResponse)(nil), // 2: license.v1.SubscribeLicenseEventsResponse
+	(*LicenseEvent)(nil),                   // 3: license.v1.LicenseEvent
+	(*timestamppb.Timestamp)(nil),          // 4: google.protobuf.Timestamp
+}
+var file_license_v1_events_proto_depIdxs = []int32{
+	3, // 0: license.v1.SubscribeLicenseEventsResponse.event:type_name -> license.v1.LicenseEvent
+	0, // 1: license.v1.LicenseEvent.operation:type_name -> license.v1.Operation
+	4, // 2: license.v1.LicenseEvent.changed_at:type_name -> google.protobuf.Timestamp
+	1, // 3: license.v1.LicenseEventService.SubscribeLicenseEvents:input_type -> license.v1.SubscribeLicenseEventsRequest
+	2, // 4: license.v1.LicenseEventService.SubscribeLicenseEvents:output_type -> license.v1.SubscribeLicenseEventsResponse
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_license_v1_events_proto_init() }
+func file_license_v1_events_proto_init() {
+	if File_license_v1_events_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_license_v1_events_proto_rawDesc), len(file_license_v1_events_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_license_v1_events_proto_goTypes,
+		DependencyIndexes: file_license_v1_events_proto_depIdxs,
+		EnumInfos:         file_license_v1_events_proto_enumTypes,
+		MessageInfos:      file_license_v1_events_proto_msgTypes,
+	}.Build()
+	File_license_v1_events_proto = out.File
+	file_license_v1_events_proto_goTypes = nil
+	file_license_v1_events_proto_depIdxs = nil
+}
@@ -0,0 +1,113 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: license/v1/events.proto
+
+package licensev1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/makkenzo/license-service-api/pkg/api/license/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// LicenseEventServiceName is the fully-qualified name of the LicenseEventService service.
+	LicenseEventServiceName = "license.v1.LicenseEventService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// LicenseEventServiceSubscribeLicenseEventsProcedure is the fully-qualified name of the
+	// LicenseEventService's SubscribeLicenseEvents RPC.
+	LicenseEventServiceSubscribeLicenseEventsProcedure = "/license.v1.LicenseEventService/SubscribeLicenseEvents"
+)
+
+// LicenseEventServiceClient is a client for the license.v1.LicenseEventService service.
+type LicenseEventServiceClient interface {
+	// SubscribeLicenseEvents sends every matching change after since and then
+	// keeps the stream open, sending new changes as they are committed.
+	SubscribeLicenseEvents(context.Context, *connect.Request[v1.SubscribeLicenseEventsRequest]) (*connect.ServerStreamForClient[v1.SubscribeLicenseEventsResponse], error)
+}
+
+// NewLicenseEventServiceClient constructs a client for the license.v1.LicenseEventService service.
+// By default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped
+// responses, and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewLicenseEventServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) LicenseEventServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	licenseEventServiceMethods := v1.File_license_v1_events_proto.Services().ByName("LicenseEventService").Methods()
+	return &licenseEventServiceClient{
+		subscribeLicenseEvents: connect.NewClient[v1.SubscribeLicenseEventsRequest, v1.SubscribeLicenseEventsResponse](
+			httpClient,
+			baseURL+LicenseEventServiceSubscribeLicenseEventsProcedure,
+			connect.WithSchema(licenseEventServiceMethods.ByName("SubscribeLicenseEvents")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// licenseEventServiceClient implements LicenseEventServiceClient.
+type licenseEventServiceClient struct {
+	subscribeLicenseEvents *connect.Client[v1.SubscribeLicenseEventsRequest, v1.SubscribeLicenseEventsResponse]
+}
+
+// SubscribeLicenseEvents calls license.v1.LicenseEventService.SubscribeLicenseEvents.
+func (c *licenseEventServiceClient) SubscribeLicenseEvents(ctx context.Context, req *connect.Request[v1.SubscribeLicenseEventsRequest]) (*connect.ServerStreamForClient[v1.SubscribeLicenseEventsResponse], error) {
+	return c.subscribeLicenseEvents.CallServerStream(ctx, req)
+}
+
+// LicenseEventServiceHandler is an implementation of the license.v1.LicenseEventService service.
+type LicenseEventServiceHandler interface {
+	// SubscribeLicenseEvents sends every matching change after since and then
+	// keeps the stream open, sending new changes as they are committed.
+	SubscribeLicenseEvents(context.Context, *connect.Request[v1.SubscribeLicenseEventsRequest], *connect.ServerStream[v1.SubscribeLicenseEventsResponse]) error
+}
+
+// NewLicenseEventServiceHandler builds an HTTP handler from the service implementation. It returns
+// the path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewLicenseEventServiceHandler(svc LicenseEventServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	licenseEventServiceMethods := v1.File_license_v1_events_proto.Services().ByName("LicenseEventService").Methods()
+	licenseEventServiceSubscribeLicenseEventsHandler := connect.NewServerStreamHandler(
+		LicenseEventServiceSubscribeLicenseEventsProcedure,
+		svc.SubscribeLicenseEvents,
+		connect.WithSchema(licenseEventServiceMethods.ByName("SubscribeLicenseEvents")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/license.v1.LicenseEventService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case LicenseEventServiceSubscribeLicenseEventsProcedure:
+			licenseEventServiceSubscribeLicenseEventsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedLicenseEventServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedLicenseEventServiceHandler struct{}
+
+func (UnimplementedLicenseEventServiceHandler) SubscribeLicenseEvents(context.Context, *connect.Request[v1.SubscribeLicenseEventsRequest], *connect.ServerStream[v1.SubscribeLicenseEventsResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("license.v1.LicenseEventService.SubscribeLicenseEvents is not implemented"))
+}
@@ -7,7 +7,9 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-func NewZapLogger(level string) (*zap.Logger, error) {
+// NewZapLogger builds the application logger. Unless masking is MaskingOff,
+// license keys, API keys and e-mails are masked in every entry.
+func NewZapLogger(level, masking string) (*zap.Logger, error) {
 	logLevel, err := zapcore.ParseLevel(level)
 	if err != nil {
 		logLevel = zapcore.InfoLevel
@@ -21,7 +23,21 @@ func NewZapLogger(level string) (*zap.Logger, error) {
 	cfg.Level = zap.NewAtomicLevelAt(logLevel)
 	cfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 
-	logger, err := cfg.Build()
+	switch masking {
+	case MaskingOff, MaskingPartial, MaskingStrict:
+	default:
+		log.Printf("Invalid log masking '%s', using default '%s'\n", masking, MaskingPartial)
+		masking = MaskingPartial
+	}
+
+	var opts []zap.Option
+	if masking != MaskingOff {
+		opts = append(opts, zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+			return &maskingCore{Core: c, mode: masking}
+		}))
+	}
+
+	logger, err := cfg.Build(opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,225 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Masking modes, from least to most strict.
+const (
+	// MaskingOff logs everything as is, e.g. for local development.
+	MaskingOff = "off"
+	// MaskingPartial keeps enough of a value to tell entries apart: the last
+	// four characters of keys and the first letter and domain of e-mails.
+	MaskingPartial = "partial"
+	// MaskingStrict replaces sensitive values entirely.
+	MaskingStrict = "strict"
+)
+
+const redacted = "[REDACTED]"
+
+// secretFields hold license keys, API keys, credentials and search terms,
+// which are often keys; emailFields hold e-mail addresses. Other fields and
+// messages are scanned for e-mails and API keys only. Names are matched in
+// snake case, so the Go field names of logged structs match too.
+var (
+	secretFields = map[string]bool{
+		"license_key":   true,
+		"key_received":  true,
+		"api_key":       true,
+		"authorization": true,
+		"token":         true,
+		"query":         true,
+		"q":             true,
+	}
+	emailFields = map[string]bool{
+		"email":          true,
+		"customer_email": true,
+		"from":           true,
+		"recipients":     true,
+	}
+
+	emailPattern  = regexp.MustCompile(`([A-Za-z0-9._%+\-])[A-Za-z0-9._%+\-]*@([A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+	apiKeyPattern = regexp.MustCompile(`\blm_([A-Za-z0-9]+)_[A-Za-z0-9\-_]+`)
+)
+
+// maskingCore masks sensitive fields before they reach the wrapped core.
+type maskingCore struct {
+	zapcore.Core
+	mode string
+}
+
+func (c *maskingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &maskingCore{Core: c.Core.With(c.maskFields(fields)), mode: c.mode}
+}
+
+func (c *maskingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *maskingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = c.maskText(ent.Message)
+	return c.Core.Write(ent, c.maskFields(fields))
+}
+
+func (c *maskingCore) maskFields(fields []zapcore.Field) []zapcore.Field {
+	masked := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		masked[i] = c.maskField(f)
+	}
+	return masked
+}
+
+func (c *maskingCore) maskField(f zapcore.Field) zapcore.Field {
+	key := snakeCase(f.Key)
+	switch {
+	case secretFields[key]:
+		if f.Type == zapcore.StringType {
+			return zap.String(f.Key, c.maskSecret(f.String))
+		}
+		return zap.String(f.Key, redacted)
+	case emailFields[key]:
+		if f.Type == zapcore.StringType {
+			return zap.String(f.Key, c.maskEmail(f.String))
+		}
+		// Lists of addresses are not worth unpacking.
+		return zap.String(f.Key, redacted)
+	}
+
+	switch f.Type {
+	case zapcore.StringType:
+		if text := c.maskText(f.String); text != f.String {
+			return zap.String(f.Key, text)
+		}
+	case zapcore.ErrorType:
+		// Database errors quote the offending value, e.g. a duplicate e-mail.
+		if err, ok := f.Interface.(error); ok && err != nil {
+			if text := c.maskText(err.Error()); text != err.Error() {
+				return zap.String(f.Key, text)
+			}
+		}
+	case zapcore.StringerType:
+		if s, ok := f.Interface.(fmt.Stringer); ok && s != nil {
+			if text := c.maskText(s.String()); text != s.String() {
+				return zap.String(f.Key, text)
+			}
+		}
+	case zapcore.ReflectType:
+		// zap.Any of structs, maps and slices, e.g. list parameters. The
+		// value is masked as its JSON encoding, field by field.
+		data, err := json.Marshal(f.Interface)
+		if err != nil {
+			return zap.String(f.Key, redacted)
+		}
+		var value any
+		if err := json.Unmarshal(data, &value); err != nil {
+			return zap.String(f.Key, redacted)
+		}
+		return zap.Any(f.Key, c.maskValue(value))
+	case zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType:
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		return zap.Any(f.Key, c.maskValue(enc.Fields[f.Key]))
+	}
+	return f
+}
+
+// maskValue masks a decoded value the way maskField masks fields: members
+// named like secret or e-mail fields are masked, other strings are scanned.
+func (c *maskingCore) maskValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		masked := make(map[string]any, len(v))
+		for k, member := range v {
+			masked[k] = c.maskMember(k, member)
+		}
+		return masked
+	case []any:
+		masked := make([]any, len(v))
+		for i, elem := range v {
+			masked[i] = c.maskValue(elem)
+		}
+		return masked
+	case string:
+		return c.maskText(v)
+	}
+	return v
+}
+
+func (c *maskingCore) maskMember(key string, v any) any {
+	if v == nil {
+		return nil
+	}
+	key = snakeCase(key)
+	switch {
+	case secretFields[key]:
+		if s, ok := v.(string); ok {
+			return c.maskSecret(s)
+		}
+		return redacted
+	case emailFields[key]:
+		if s, ok := v.(string); ok {
+			return c.maskEmail(s)
+		}
+		return redacted
+	}
+	return c.maskValue(v)
+}
+
+// snakeCase turns Go field names such as CustomerEmail or APIKey into
+// customer_email and api_key; snake case names are only lowercased.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			acronymEnd := i > 0 && unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || acronymEnd {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (c *maskingCore) maskSecret(s string) string {
+	if c.mode == MaskingStrict || len(s) < 12 {
+		return redacted
+	}
+	return "****" + s[len(s)-4:]
+}
+
+func (c *maskingCore) maskEmail(s string) string {
+	if c.mode == MaskingStrict {
+		return redacted
+	}
+	if masked := c.maskText(s); masked != s {
+		return masked
+	}
+	return redacted
+}
+
+// maskText masks e-mail addresses and API keys found anywhere in s.
+func (c *maskingCore) maskText(s string) string {
+	if !strings.Contains(s, "@") && !strings.Contains(s, "lm_") {
+		return s
+	}
+	if c.mode == MaskingStrict {
+		s = emailPattern.ReplaceAllString(s, redacted)
+		return apiKeyPattern.ReplaceAllString(s, redacted)
+	}
+	s = emailPattern.ReplaceAllString(s, "$1***@$2")
+	return apiKeyPattern.ReplaceAllString(s, "lm_${1}_****")
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type listParams struct {
+	CustomerEmail *string
+	Query         *string
+	ProductName   string
+	Limit         int
+}
+
+func TestMaskField(t *testing.T) {
+	email, query := "jane.doe@example.com", "ACME-1234-5678-ABCD"
+
+	cases := []struct {
+		name  string
+		mode  string
+		field zap.Field
+		want  any
+	}{
+		{"license key", MaskingPartial, zap.String("license_key", "ACME-1234-5678-ABCD"), "****ABCD"},
+		{"license key strict", MaskingStrict, zap.String("license_key", "ACME-1234-5678-ABCD"), redacted},
+		{"error", MaskingPartial, zap.Error(errors.New("duplicate customer jane.doe@example.com")), "duplicate customer j***@example.com"},
+		{"stringer", MaskingPartial, zap.Stringer("to", stringer("lm_abc123_secretpart")), "lm_abc123_****"},
+		{"strings", MaskingPartial, zap.Strings("cc", []string{email}), []any{"j***@example.com"}},
+		{"reflected struct", MaskingPartial, zap.Any("params", listParams{CustomerEmail: &email, Query: &query, ProductName: "acme", Limit: 20}),
+			map[string]any{"CustomerEmail": "j***@example.com", "Query": "****ABCD", "ProductName": "acme", "Limit": float64(20)}},
+		{"reflected map strict", MaskingStrict, zap.Any("params", map[string]any{"q": "ab", "note": "mail jane.doe@example.com"}),
+			map[string]any{"q": redacted, "note": "mail " + redacted}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			observed, logs := observer.New(zapcore.DebugLevel)
+			zap.New(&maskingCore{Core: observed, mode: tc.mode}).Info("logged", tc.field)
+
+			got := logs.All()[0].ContextMap()[tc.field.Key]
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for name, want := range map[string]string{
+		"CustomerEmail": "customer_email",
+		"APIKey":        "api_key",
+		"license_key":   "license_key",
+		"Query":         "query",
+	} {
+		if got := snakeCase(name); got != want {
+			t.Errorf("snakeCase(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+type stringer string
+
+func (s stringer) String() string { return string(s) }